@@ -0,0 +1,30 @@
+package benchmarks
+
+import (
+	"testing"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/expr"
+)
+
+const benchExpr = "retries < maxRetries and (status == 'pending' or priority * 2 >= 10)"
+
+var benchVars = map[string]any{"retries": 2, "maxRetries": 5, "status": "running", "priority": 6}
+
+// BenchmarkExpr_Eval parses and evaluates an expression on every call.
+func BenchmarkExpr_Eval(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = expr.Eval(benchExpr, benchVars)
+	}
+}
+
+// BenchmarkExpr_Program evaluates a precompiled expression.
+func BenchmarkExpr_Program(b *testing.B) {
+	prog, err := expr.Compile(benchExpr)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = prog.Eval(benchVars)
+	}
+}
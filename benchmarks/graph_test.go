@@ -96,6 +96,26 @@ func BenchmarkCompile_Branching(b *testing.B) {
 	}
 }
 
+// BenchmarkFingerprint_Linear_100 fingerprints a 100-node linear graph.
+func BenchmarkFingerprint_Linear_100(b *testing.B) {
+	graph := buildLinearGraph(100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = graph.Fingerprint()
+	}
+}
+
+// BenchmarkCompileCache_Hit_Linear_100 recompiles a cached 100-node linear graph.
+func BenchmarkCompileCache_Hit_Linear_100(b *testing.B) {
+	graph := buildLinearGraph(100)
+	cache := flowgraph.NewCompileCache[State]()
+	_, _ = cache.Compile(graph)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = cache.Compile(graph)
+	}
+}
+
 // Helper functions
 
 func nodeID(n int) string {
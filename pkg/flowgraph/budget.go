@@ -0,0 +1,73 @@
+package flowgraph
+
+import (
+	"time"
+)
+
+// runBudget tracks the time and cost budgets of one Run or Resume call.
+type runBudget struct {
+	cfg   *runConfig
+	start time.Time
+}
+
+// newRunBudget starts the budget clock for an invocation.
+func newRunBudget(cfg *runConfig) *runBudget {
+	return &runBudget{cfg: cfg, start: time.Now()}
+}
+
+// check is called at each node boundary before nextNode executes.
+// It returns a *BudgetError once a budget is used up, or a *SuspendedError
+// once the WithSuspendOnBudget threshold is crossed. Suspension requires
+// that the previous node's result was checkpointed and that at least one
+// node ran in this invocation, so a resumed run always makes progress.
+func (b *runBudget) check(nextNode string, state any, nodeCount int, checkpointed bool) error {
+	cfg := b.cfg
+	if cfg.timeBudget <= 0 && cfg.costFunc == nil {
+		return nil
+	}
+
+	kind, used, limit := b.usage(state)
+	if limit == 0 {
+		return nil
+	}
+	fraction := used / limit
+
+	if cfg.suspendFraction > 0 && fraction >= cfg.suspendFraction && checkpointed && nodeCount > 0 {
+		return &SuspendedError{
+			RunID:    cfg.runID,
+			NodeID:   nextNode,
+			Budget:   kind,
+			Fraction: fraction,
+			State:    state,
+		}
+	}
+
+	if fraction >= 1 {
+		return &BudgetError{
+			Budget: kind,
+			NodeID: nextNode,
+			Used:   used,
+			Limit:  limit,
+		}
+	}
+	return nil
+}
+
+// usage returns the budget with the highest fraction used.
+// Time is measured in seconds.
+func (b *runBudget) usage(state any) (kind string, used, limit float64) {
+	cfg := b.cfg
+	best := -1.0
+
+	if cfg.timeBudget > 0 {
+		u, l := time.Since(b.start).Seconds(), cfg.timeBudget.Seconds()
+		kind, used, limit, best = "time", u, l, u/l
+	}
+	if cfg.costFunc != nil {
+		u, l := cfg.costFunc(state), cfg.costBudget
+		if u/l > best {
+			kind, used, limit = "cost", u, l
+		}
+	}
+	return kind, used, limit
+}
@@ -0,0 +1,162 @@
+package flowgraph
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/checkpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addThree is a node that spends 3 cost units, tracked in Counter.Value.
+func addThree(ctx Context, s Counter) (Counter, error) {
+	s.Value += 3
+	return s, nil
+}
+
+// counterCost reports Counter.Value as the run's accumulated cost.
+func counterCost(s any) float64 {
+	return float64(s.(Counter).Value)
+}
+
+// linearCounterGraph compiles n nodes in sequence, each running fn.
+func linearCounterGraph(t *testing.T, n int, fn NodeFunc[Counter]) *CompiledGraph[Counter] {
+	t.Helper()
+	g := NewGraph[Counter]()
+	ids := []string{"a", "b", "c", "d", "e", "f"}[:n]
+	for i, id := range ids {
+		g.AddNode(id, fn)
+		if i+1 < len(ids) {
+			g.AddEdge(id, ids[i+1])
+		} else {
+			g.AddEdge(id, END)
+		}
+	}
+	compiled, err := g.SetEntry(ids[0]).Compile()
+	require.NoError(t, err)
+	return compiled
+}
+
+// TestSuspendOnBudget_Cost tests that a run suspends at the cost fraction and Resume completes it.
+func TestSuspendOnBudget_Cost(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	compiled := linearCounterGraph(t, 5, addThree)
+
+	result, err := compiled.Run(testCtx(), Counter{},
+		WithCheckpointing(store),
+		WithRunID("cost-run"),
+		WithCostBudget(10, counterCost),
+		WithSuspendOnBudget(0.5))
+
+	var suspended *SuspendedError
+	require.ErrorAs(t, err, &suspended)
+	assert.ErrorIs(t, err, ErrSuspended)
+	assert.Equal(t, "cost-run", suspended.RunID)
+	assert.Equal(t, "c", suspended.NodeID)
+	assert.Equal(t, "cost", suspended.Budget)
+	assert.InDelta(t, 0.6, suspended.Fraction, 1e-9)
+	assert.Equal(t, 6, result.Value)
+
+	// The latest checkpoint resumes at the suspension point
+	infos, err := store.List("cost-run")
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Equal(t, "b", infos[1].NodeID)
+
+	result, err = compiled.Resume(testCtx(), store, "cost-run")
+	require.NoError(t, err)
+	assert.Equal(t, 15, result.Value)
+}
+
+// TestSuspendOnBudget_ResumeMakesProgress tests that each resumed invocation runs at least one node.
+func TestSuspendOnBudget_ResumeMakesProgress(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	compiled := linearCounterGraph(t, 4, addThree)
+	opts := []RunOption{WithCostBudget(100, counterCost), WithSuspendOnBudget(0.01)}
+
+	_, err := compiled.Run(testCtx(), Counter{},
+		append(opts, WithCheckpointing(store), WithRunID("progress"))...)
+	var suspended *SuspendedError
+	require.ErrorAs(t, err, &suspended)
+	assert.Equal(t, "b", suspended.NodeID)
+
+	var visited []string
+	for i := 0; i < 5; i++ {
+		_, err = compiled.Resume(testCtx(), store, "progress", WithResumeRunOptions(opts...))
+		if !errors.As(err, &suspended) {
+			break
+		}
+		visited = append(visited, suspended.NodeID)
+	}
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "d"}, visited)
+}
+
+// TestSuspendOnBudget_Time tests suspension on the wall-time budget.
+func TestSuspendOnBudget_Time(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	slow := func(ctx Context, s Counter) (Counter, error) {
+		time.Sleep(30 * time.Millisecond)
+		s.Value++
+		return s, nil
+	}
+	compiled := linearCounterGraph(t, 6, slow)
+
+	_, err := compiled.Run(testCtx(), Counter{},
+		WithCheckpointing(store),
+		WithRunID("time-run"),
+		WithTimeBudget(100*time.Millisecond),
+		WithSuspendOnBudget(0.5))
+
+	var suspended *SuspendedError
+	require.ErrorAs(t, err, &suspended)
+	assert.Equal(t, "time", suspended.Budget)
+	assert.GreaterOrEqual(t, suspended.Fraction, 0.5)
+
+	result, err := compiled.Resume(testCtx(), store, "time-run")
+	require.NoError(t, err)
+	assert.Equal(t, 6, result.Value)
+}
+
+// TestCostBudget_Exceeded tests that exhausting a budget without suspension fails the run.
+func TestCostBudget_Exceeded(t *testing.T) {
+	compiled := linearCounterGraph(t, 4, addThree)
+
+	result, err := compiled.Run(testCtx(), Counter{}, WithCostBudget(5, counterCost))
+
+	var budgetErr *BudgetError
+	require.ErrorAs(t, err, &budgetErr)
+	assert.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.Equal(t, "cost", budgetErr.Budget)
+	assert.Equal(t, "c", budgetErr.NodeID)
+	assert.Equal(t, 6.0, budgetErr.Used)
+	assert.Equal(t, 5.0, budgetErr.Limit)
+	assert.Equal(t, 6, result.Value)
+}
+
+// TestSuspendOnBudget_RequiresCheckpointing tests that suspension without a store is rejected.
+func TestSuspendOnBudget_RequiresCheckpointing(t *testing.T) {
+	compiled := linearCounterGraph(t, 2, addThree)
+
+	_, err := compiled.Run(testCtx(), Counter{},
+		WithCostBudget(5, counterCost),
+		WithSuspendOnBudget(0.5))
+	assert.ErrorIs(t, err, ErrSuspendRequiresCheckpointing)
+}
+
+// TestBudget_WithinLimits tests that a run under budget is unaffected.
+func TestBudget_WithinLimits(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	compiled := linearCounterGraph(t, 3, addThree)
+
+	result, err := compiled.Run(testCtx(), Counter{},
+		WithCheckpointing(store),
+		WithRunID("under"),
+		WithTimeBudget(time.Minute),
+		WithCostBudget(100, counterCost),
+		WithSuspendOnBudget(0.8))
+	require.NoError(t, err)
+	assert.Equal(t, 9, result.Value)
+}
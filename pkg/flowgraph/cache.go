@@ -0,0 +1,255 @@
+package flowgraph
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Fingerprint returns a structural hash of the graph definition.
+//
+// The fingerprint covers node IDs, dynamic node factory keys, subgraph
+// bindings, per-node retry settings, which nodes have compensations, edges
+// (including their order), conditional edge and fan-out sources, the entry
+// point or presence of an entry selector, the fork/join configuration, and
+// the number of node middleware. It does not cover the node, router,
+// selector, compensation, or middleware functions themselves: two graphs
+// with the same shape but different node implementations have the same
+// fingerprint.
+//
+// Use the fingerprint to detect structural changes between builds.
+// CompileCache additionally keys on the code of each function.
+func (g *Graph[S]) Fingerprint() string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.fingerprintLocked()
+}
+
+// fingerprintLocked computes the fingerprint (must hold read lock).
+func (g *Graph[S]) fingerprintLocked() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "entry=%q selector=%t\n", g.entryPoint, g.entrySelector != nil)
+
+	for _, id := range sortedKeys(g.nodes) {
+		fmt.Fprintf(&b, "node %q", id)
+		if key, ok := g.dynamicNodes[id]; ok {
+			fmt.Fprintf(&b, " dynamic=%q", key)
+		}
+		if _, ok := g.subgraphs[id]; ok {
+			b.WriteString(" subgraph")
+		}
+		if _, ok := g.compensations[id]; ok {
+			b.WriteString(" compensating")
+		}
+		if retry := g.nodeConfigs[id].retry; retry != nil {
+			fmt.Fprintf(&b, " retry=%d/%s/%s/%g/%g", retry.MaxAttempts, retry.InitialBackoff,
+				retry.MaxBackoff, retry.BackoffFactor, retry.Jitter)
+		}
+		b.WriteByte('\n')
+	}
+
+	for _, from := range sortedKeys(g.edges) {
+		fmt.Fprintf(&b, "edges %q -> %q\n", from, g.edges[from])
+	}
+	for _, from := range sortedKeys(g.conditionalEdges) {
+		fmt.Fprintf(&b, "conditional %q\n", from)
+	}
+	for _, from := range sortedKeys(g.fanOuts) {
+		fmt.Fprintf(&b, "fanout %q\n", from)
+	}
+	for _, from := range sortedKeys(g.errorEdges) {
+		fmt.Fprintf(&b, "error %q -> %q\n", from, g.errorEdges[from])
+	}
+
+	fmt.Fprintf(&b, "forkjoin %+v hook=%t\n", g.forkJoinConfig, g.branchHook != nil)
+	fmt.Fprintf(&b, "middleware %d\n", len(g.middleware))
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// CompileCache memoizes Compile for repeated builds of the same graph.
+//
+// A cached CompiledGraph is returned when a graph has the same
+// Fingerprint and uses the same function values (node functions, routers,
+// fan-outs, entry selector, retryable checks, compensations, subgraphs,
+// middleware, and branch hook) as a previously compiled graph. Because
+// CompiledGraph is immutable and safe for concurrent use, sharing the
+// cached instance is safe.
+//
+// Function values are compared by their code, which cannot tell apart
+// closures created from the same function literal with different
+// captured values. Compile therefore only caches graphs whose functions
+// are all top-level functions or method expressions; graphs using
+// closures or method values are compiled afresh every time. To cache
+// such graphs, use CompileVersion with a version that identifies the
+// captured values. Graphs whose branch hook is not a pointer are never
+// cached. Compilation errors are not cached.
+//
+// CompileCache is safe for concurrent use.
+//
+// Example:
+//
+//	cache := flowgraph.NewCompileCache[State]()
+//	compiled, err := cache.Compile(buildGraph())
+type CompileCache[S any] struct {
+	mu      sync.RWMutex
+	entries map[string]*CompiledGraph[S]
+}
+
+// NewCompileCache creates an empty compile cache.
+func NewCompileCache[S any]() *CompileCache[S] {
+	return &CompileCache[S]{
+		entries: make(map[string]*CompiledGraph[S]),
+	}
+}
+
+// Compile returns the cached CompiledGraph for g, compiling and caching
+// it on a miss. Graphs that use closures are compiled without the cache.
+func (c *CompileCache[S]) Compile(g *Graph[S]) (*CompiledGraph[S], error) {
+	key, cacheable, closures := g.cacheKey()
+	if !cacheable || closures {
+		return g.Compile()
+	}
+	return c.compile(g, "|"+key)
+}
+
+// CompileVersion is like Compile but also caches graphs that use
+// closures, keyed on version as well as the graph. The caller must pass a
+// version that identifies every value the graph's closures capture: two
+// graphs with the same shape and version share one CompiledGraph.
+//
+// Example:
+//
+//	compiled, err := cache.CompileVersion(buildGraph(tenant), tenant.ID)
+func (c *CompileCache[S]) CompileVersion(g *Graph[S], version string) (*CompiledGraph[S], error) {
+	key, cacheable, _ := g.cacheKey()
+	if !cacheable {
+		return g.Compile()
+	}
+	return c.compile(g, fmt.Sprintf("%q|%s", version, key))
+}
+
+// compile returns the entry for key, compiling g on a miss.
+func (c *CompileCache[S]) compile(g *Graph[S], key string) (*CompiledGraph[S], error) {
+	c.mu.RLock()
+	cached, ok := c.entries[key]
+	c.mu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	compiled, err := g.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.entries[key]; ok {
+		// Another goroutine compiled the same graph first
+		return existing, nil
+	}
+	c.entries[key] = compiled
+	return compiled, nil
+}
+
+// Len returns the number of cached graphs.
+func (c *CompileCache[S]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// Clear removes all cached graphs.
+func (c *CompileCache[S]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*CompiledGraph[S])
+}
+
+// cacheKey combines the fingerprint with the identity of every function
+// value in the graph. cacheable is false if the graph cannot be cached;
+// closures reports whether any function value may capture variables.
+func (g *Graph[S]) cacheKey() (key string, cacheable, closures bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	funcIdentity := func(fn any) uintptr {
+		ptr := reflect.ValueOf(fn).Pointer()
+		if isClosure(ptr) {
+			closures = true
+		}
+		return ptr
+	}
+
+	var b strings.Builder
+	b.WriteString(g.fingerprintLocked())
+
+	for _, id := range sortedKeys(g.nodes) {
+		fmt.Fprintf(&b, "|n%q=%x", id, funcIdentity(g.nodes[id]))
+		if sub, ok := g.subgraphs[id]; ok {
+			fmt.Fprintf(&b, ",sub=%p", sub)
+		}
+		if comp, ok := g.compensations[id]; ok {
+			fmt.Fprintf(&b, ",comp=%x", funcIdentity(comp))
+		}
+		if retry := g.nodeConfigs[id].retry; retry != nil && retry.RetryableFunc != nil {
+			fmt.Fprintf(&b, ",retryable=%x", funcIdentity(retry.RetryableFunc))
+		}
+	}
+	for _, from := range sortedKeys(g.conditionalEdges) {
+		fmt.Fprintf(&b, "|c%q=%x", from, funcIdentity(g.conditionalEdges[from]))
+	}
+	for _, from := range sortedKeys(g.fanOuts) {
+		fmt.Fprintf(&b, "|f%q=%x", from, funcIdentity(g.fanOuts[from]))
+	}
+	if g.entrySelector != nil {
+		fmt.Fprintf(&b, "|s=%x", funcIdentity(g.entrySelector))
+	}
+	for i, mw := range g.middleware {
+		fmt.Fprintf(&b, "|m%d=%x", i, funcIdentity(mw))
+	}
+	if g.branchHook != nil {
+		v := reflect.ValueOf(g.branchHook)
+		if v.Kind() != reflect.Pointer {
+			return "", false, false
+		}
+		fmt.Fprintf(&b, "|h=%s@%x", v.Type(), v.Pointer())
+	}
+
+	return b.String(), true, closures
+}
+
+// isClosure reports whether the function at code pointer ptr may capture
+// variables: a function literal (named "outer.funcN" by the compiler), a
+// method value ("-fm"), or a range-over-func body ("-rangeN"). Unknown
+// code is treated as a closure.
+func isClosure(ptr uintptr) bool {
+	fn := runtime.FuncForPC(ptr)
+	if fn == nil {
+		return true
+	}
+	return closureName.MatchString(fn.Name())
+}
+
+// closureName matches compiler-generated names of closures.
+var closureName = regexp.MustCompile(`\.func\d+|-fm$|-range\d+`)
+
+// sortedKeys returns the keys of a string-keyed map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,295 @@
+package flowgraph
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildCacheTestGraph builds a two-node graph using top-level functions.
+func buildCacheTestGraph() *Graph[Counter] {
+	return NewGraph[Counter]().
+		AddNode("a", increment).
+		AddNode("b", increment).
+		AddEdge("a", "b").
+		AddEdge("b", END).
+		SetEntry("a")
+}
+
+// double is a top-level node function, so graphs using it are cacheable.
+func double(ctx Context, s Counter) (Counter, error) {
+	s.Value *= 2
+	return s, nil
+}
+
+// addHundred is top-level node middleware that adds 100 before the node.
+func addHundred(next NodeFunc[Counter]) NodeFunc[Counter] {
+	return func(ctx Context, s Counter) (Counter, error) {
+		s.Value += 100
+		return next(ctx, s)
+	}
+}
+
+// TestFingerprint_Stable tests that identical definitions have the same fingerprint.
+func TestFingerprint_Stable(t *testing.T) {
+	fp1 := buildCacheTestGraph().Fingerprint()
+	fp2 := buildCacheTestGraph().Fingerprint()
+
+	assert.Equal(t, fp1, fp2)
+	assert.Len(t, fp1, 64)
+}
+
+// TestFingerprint_ChangesWithStructure tests that structural changes alter the fingerprint.
+func TestFingerprint_ChangesWithStructure(t *testing.T) {
+	base := buildCacheTestGraph().Fingerprint()
+
+	tests := []struct {
+		name  string
+		graph *Graph[Counter]
+	}{
+		{
+			name:  "extra node",
+			graph: buildCacheTestGraph().AddNode("c", increment),
+		},
+		{
+			name:  "different entry",
+			graph: buildCacheTestGraph().SetEntry("b"),
+		},
+		{
+			name:  "extra edge",
+			graph: buildCacheTestGraph().AddEdge("a", END),
+		},
+		{
+			name: "conditional edge",
+			graph: buildCacheTestGraph().AddConditionalEdge("a", func(ctx Context, s Counter) string {
+				return "b"
+			}),
+		},
+		{
+			name:  "fork/join config",
+			graph: buildCacheTestGraph().SetForkJoinConfig(ForkJoinConfig{MaxConcurrency: 2}),
+		},
+		{
+			name:  "middleware",
+			graph: buildCacheTestGraph().Use(RecoveryMiddleware[Counter]()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotEqual(t, base, tt.graph.Fingerprint())
+		})
+	}
+}
+
+// TestCompileCache_ReturnsSameInstance tests that identical definitions share a compiled graph.
+func TestCompileCache_ReturnsSameInstance(t *testing.T) {
+	cache := NewCompileCache[Counter]()
+
+	first, err := cache.Compile(buildCacheTestGraph())
+	require.NoError(t, err)
+	second, err := cache.Compile(buildCacheTestGraph())
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, cache.Len())
+
+	result, err := second.Run(testCtx(), Counter{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Value)
+}
+
+// TestCompileCache_RecompilesChangedGraph tests that structural changes miss the cache.
+func TestCompileCache_RecompilesChangedGraph(t *testing.T) {
+	cache := NewCompileCache[Counter]()
+
+	first, err := cache.Compile(buildCacheTestGraph())
+	require.NoError(t, err)
+
+	changed := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddEdge("a", END).
+		SetEntry("a")
+	second, err := cache.Compile(changed)
+	require.NoError(t, err)
+
+	assert.NotSame(t, first, second)
+	assert.Equal(t, 2, cache.Len())
+}
+
+// TestCompileCache_DistinguishesFunctions tests that different node functions miss the cache.
+func TestCompileCache_DistinguishesFunctions(t *testing.T) {
+	cache := NewCompileCache[Counter]()
+
+	build := func(fn NodeFunc[Counter]) *Graph[Counter] {
+		return NewGraph[Counter]().
+			AddNode("a", fn).
+			AddEdge("a", END).
+			SetEntry("a")
+	}
+
+	c1, err := cache.Compile(build(increment))
+	require.NoError(t, err)
+	c2, err := cache.Compile(build(double))
+	require.NoError(t, err)
+
+	assert.NotSame(t, c1, c2)
+	assert.Equal(t, 2, cache.Len())
+}
+
+// TestCompileCache_CompileVersion tests that versions separate closures
+// created from the same literal with different captured values.
+func TestCompileCache_CompileVersion(t *testing.T) {
+	cache := NewCompileCache[Counter]()
+
+	build := func(delta int) *Graph[Counter] {
+		return NewGraph[Counter]().
+			AddNode("add", func(ctx Context, s Counter) (Counter, error) {
+				s.Value += delta
+				return s, nil
+			}).
+			AddEdge("add", END).
+			SetEntry("add")
+	}
+
+	c1, err := cache.CompileVersion(build(1), "delta=1")
+	require.NoError(t, err)
+	c10, err := cache.CompileVersion(build(10), "delta=10")
+	require.NoError(t, err)
+	assert.NotSame(t, c1, c10)
+
+	result, err := c10.Run(testCtx(), Counter{})
+	require.NoError(t, err)
+	assert.Equal(t, 10, result.Value)
+
+	again, err := cache.CompileVersion(build(1), "delta=1")
+	require.NoError(t, err)
+	assert.Same(t, c1, again)
+}
+
+// TestCompileCache_SkipsClosures tests that Compile does not share a graph
+// between closures from one literal that capture different values.
+func TestCompileCache_SkipsClosures(t *testing.T) {
+	cache := NewCompileCache[Counter]()
+
+	build := func(delta int) *Graph[Counter] {
+		return NewGraph[Counter]().
+			AddNode("add", func(ctx Context, s Counter) (Counter, error) {
+				s.Value += delta
+				return s, nil
+			}).
+			AddEdge("add", END).
+			SetEntry("add")
+	}
+
+	c1, err := cache.Compile(build(1))
+	require.NoError(t, err)
+	c10, err := cache.Compile(build(10))
+	require.NoError(t, err)
+
+	r1, err := c1.Run(testCtx(), Counter{})
+	require.NoError(t, err)
+	r10, err := c10.Run(testCtx(), Counter{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, r1.Value)
+	assert.Equal(t, 10, r10.Value)
+	assert.Equal(t, 0, cache.Len())
+}
+
+// TestCompileCache_DistinguishesMiddleware tests that a graph with Use
+// does not share a compiled graph with one without it.
+func TestCompileCache_DistinguishesMiddleware(t *testing.T) {
+	cache := NewCompileCache[Counter]()
+
+	build := func() *Graph[Counter] {
+		return NewGraph[Counter]().
+			AddNode("a", increment).
+			AddEdge("a", END).
+			SetEntry("a")
+	}
+
+	plain, err := cache.Compile(build())
+	require.NoError(t, err)
+	wrapped, err := cache.Compile(build().Use(addHundred))
+	require.NoError(t, err)
+
+	assert.NotSame(t, plain, wrapped)
+	assert.Equal(t, 2, cache.Len())
+
+	result, err := wrapped.Run(testCtx(), Counter{})
+	require.NoError(t, err)
+	assert.Equal(t, 101, result.Value)
+}
+
+// TestCompileCache_DistinguishesCompensations tests that a compensating
+// node does not share a compiled graph with a plain node.
+func TestCompileCache_DistinguishesCompensations(t *testing.T) {
+	cache := NewCompileCache[Counter]()
+
+	var compensated bool
+	compensate := func(ctx Context, s Counter) error {
+		compensated = true
+		return nil
+	}
+	fail := func(ctx Context, s Counter) (Counter, error) {
+		return s, errors.New("boom")
+	}
+	build := func(g *Graph[Counter]) *Graph[Counter] {
+		return g.AddNode("fail", fail).
+			AddEdge("a", "fail").
+			AddEdge("fail", END).
+			SetEntry("a")
+	}
+
+	plain := build(NewGraph[Counter]().AddNode("a", increment))
+	withComp := build(NewGraph[Counter]().AddCompensatingNode("a", increment, compensate))
+	assert.NotEqual(t, plain.Fingerprint(), withComp.Fingerprint())
+
+	// Same version, so only the cache key can tell the graphs apart
+	c1, err := cache.CompileVersion(plain, "v1")
+	require.NoError(t, err)
+	c2, err := cache.CompileVersion(withComp, "v1")
+	require.NoError(t, err)
+	assert.NotSame(t, c1, c2)
+	assert.Equal(t, 2, cache.Len())
+
+	_, err = c2.Run(testCtx(), Counter{}, WithGraphCompensation())
+	require.Error(t, err)
+	assert.True(t, compensated)
+}
+
+// TestCompileCache_ErrorsNotCached tests that failed compilations are not stored.
+func TestCompileCache_ErrorsNotCached(t *testing.T) {
+	cache := NewCompileCache[Counter]()
+
+	_, err := cache.Compile(NewGraph[Counter]().AddNode("a", increment))
+
+	require.ErrorIs(t, err, ErrNoEntryPoint)
+	assert.Equal(t, 0, cache.Len())
+}
+
+// TestCompileCache_Concurrent tests concurrent compiles of the same definition.
+func TestCompileCache_Concurrent(t *testing.T) {
+	cache := NewCompileCache[Counter]()
+
+	results := make([]*CompiledGraph[Counter], 10)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			compiled, err := cache.Compile(buildCacheTestGraph())
+			assert.NoError(t, err)
+			results[i] = compiled
+		}(i)
+	}
+	wg.Wait()
+
+	for _, compiled := range results[1:] {
+		assert.Same(t, results[0], compiled)
+	}
+}
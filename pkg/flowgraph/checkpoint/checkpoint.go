@@ -23,6 +23,10 @@ type Checkpoint struct {
 	State    json.RawMessage `json:"state"`
 	NextNode string          `json:"next_node"`
 
+	// Codec names the serialization of State, e.g. "json" or "gob".
+	// Empty means JSON.
+	Codec string `json:"codec,omitempty"`
+
 	// Execution context
 	Attempt    int    `json:"attempt"`
 	PrevNodeID string `json:"prev_node_id,omitempty"`
@@ -30,6 +34,10 @@ type Checkpoint struct {
 	// Parallel branch context (for fork/join execution)
 	BranchID   string `json:"branch_id,omitempty"`
 	ForkNodeID string `json:"fork_node_id,omitempty"`
+
+	// Labels attached by the run, such as the code version that produced
+	// the checkpoint. Stores report them in Info without loading state.
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // Marshal serializes a checkpoint to JSON.
@@ -73,9 +81,45 @@ func (c *Checkpoint) WithPrevNode(prevNodeID string) *Checkpoint {
 	return c
 }
 
+// WithCodec records the name of the codec that serialized the state.
+func (c *Checkpoint) WithCodec(codec string) *Checkpoint {
+	c.Codec = codec
+	return c
+}
+
 // WithBranch sets the branch context for parallel execution.
 func (c *Checkpoint) WithBranch(branchID, forkNodeID string) *Checkpoint {
 	c.BranchID = branchID
 	c.ForkNodeID = forkNodeID
 	return c
 }
+
+// WithMetadata sets the checkpoint's labels. The map is copied.
+func (c *Checkpoint) WithMetadata(metadata map[string]string) *Checkpoint {
+	c.Metadata = copyMetadata(metadata)
+	return c
+}
+
+// copyMetadata returns a copy of metadata, or nil if it is empty.
+func copyMetadata(metadata map[string]string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	copied := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		copied[k] = v
+	}
+	return copied
+}
+
+// metadataOf returns the Metadata of the checkpoint encoded in data, or nil
+// if data is not a checkpoint or has none. Stores use it to fill Info.
+func metadataOf(data []byte) map[string]string {
+	var envelope struct {
+		Metadata map[string]string `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil
+	}
+	return copyMetadata(envelope.Metadata)
+}
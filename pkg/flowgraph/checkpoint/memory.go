@@ -20,6 +20,7 @@ type storedCheckpoint struct {
 	data      []byte
 	sequence  int
 	timestamp time.Time
+	metadata  map[string]string
 }
 
 // NewMemoryStore creates a new in-memory checkpoint store.
@@ -55,6 +56,7 @@ func (m *MemoryStore) Save(runID, nodeID string, data []byte) error {
 		data:      stored,
 		sequence:  seq,
 		timestamp: time.Now().UTC(),
+		metadata:  metadataOf(stored),
 	}
 
 	return nil
@@ -107,6 +109,7 @@ func (m *MemoryStore) List(runID string) ([]Info, error) {
 			Sequence:  cp.sequence,
 			Timestamp: cp.timestamp,
 			Size:      int64(len(cp.data)),
+			Metadata:  copyMetadata(cp.metadata),
 		})
 	}
 
@@ -118,6 +121,25 @@ func (m *MemoryStore) List(runID string) ([]Info, error) {
 	return infos, nil
 }
 
+// ListRuns implements Store.
+func (m *MemoryStore) ListRuns() ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.closed {
+		return nil, ErrStoreClosed
+	}
+
+	runs := make([]string, 0, len(m.data))
+	for runID, run := range m.data {
+		if len(run) > 0 {
+			runs = append(runs, runID)
+		}
+	}
+	sort.Strings(runs)
+	return runs, nil
+}
+
 // Delete implements Store.
 func (m *MemoryStore) Delete(runID, nodeID string) error {
 	m.mu.Lock()
@@ -0,0 +1,401 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RedisClient is the subset of Redis commands used by RedisStore.
+//
+// flowgraph does not depend on a Redis driver; wrap the client you already
+// use (e.g. go-redis) in a small adapter implementing this interface.
+// Implementations must be safe for concurrent use.
+type RedisClient interface {
+	// Eval runs a Lua script with EVAL and returns its reply.
+	Eval(ctx context.Context, script string, keys []string, args ...any) (any, error)
+
+	// Get returns the value stored at key.
+	// found is false (with a nil error) if the key does not exist.
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+
+	// ZAdd adds member to the sorted set at key with the given score.
+	ZAdd(ctx context.Context, key string, score float64, member string) error
+
+	// ZRange returns all members of the sorted set at key, ordered by score.
+	ZRange(ctx context.Context, key string) ([]string, error)
+
+	// ZRem removes members from the sorted set at key.
+	ZRem(ctx context.Context, key string, members ...string) error
+
+	// ZRemRangeByScore removes the members of the sorted set at key whose
+	// score is at most max.
+	ZRemRangeByScore(ctx context.Context, key string, max float64) error
+}
+
+// RedisOptions configures a RedisStore.
+type RedisOptions struct {
+	// KeyPrefix is prepended to all keys. Default: "flowgraph".
+	KeyPrefix string
+
+	// TTL expires a run's checkpoints after this long without a save.
+	// Every save refreshes the TTL of all of the run's keys.
+	// 0 = keep until deleted.
+	TTL time.Duration
+
+	// Timeout bounds each store operation. 0 = no timeout.
+	Timeout time.Duration
+}
+
+// RedisStore persists checkpoints in Redis so they can be shared across
+// processes, allowing a run to resume on a different instance.
+//
+// Keys for a run (with the default prefix):
+//
+//	flowgraph:{runID}:{nodeID}   checkpoint data
+//	flowgraph:index:{runID}      sorted set of Info, scored by sequence
+//	flowgraph:seq:{runID}        sequence counter
+//	flowgraph:runs               sorted set of run IDs, scored by last save
+//
+// The braces around the run ID are literal: they form a Redis Cluster hash
+// tag, so all keys of a run map to one slot.
+//
+// Each Save, Delete and DeleteRun updates the run's keys with a single Lua
+// script, so it is applied in full or not at all. Saves of the same node
+// from different processes are not ordered: the last one to reach Redis
+// wins. The run registry is updated with separate commands; ListRuns
+// drops runs whose keys have expired or been deleted.
+type RedisStore struct {
+	client RedisClient
+	opts   RedisOptions
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewRedisStore creates a checkpoint store backed by client.
+// The store does not take ownership of client; Close does not close it.
+//
+// Panics if client is nil.
+func NewRedisStore(client RedisClient, opts RedisOptions) *RedisStore {
+	if client == nil {
+		panic("checkpoint: redis client cannot be nil")
+	}
+	if opts.KeyPrefix == "" {
+		opts.KeyPrefix = "flowgraph"
+	}
+	return &RedisStore{client: client, opts: opts}
+}
+
+// redisInfo is the sorted set member recorded for each checkpoint.
+type redisInfo struct {
+	NodeID    string            `json:"node_id"`
+	Sequence  int               `json:"sequence"`
+	Timestamp time.Time         `json:"timestamp"`
+	Size      int64             `json:"size"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// redisSaveScript stores a checkpoint, replaces its index entry, and
+// refreshes the TTL of every key passed.
+//
+// KEYS: data, index, seq, then the data keys of the run's other nodes.
+// ARGV: nodeID, data, info JSON without the sequence, TTL in ms (0 = none).
+//
+// The sequence is spliced into the info JSON so it is assigned in the
+// same script that writes the checkpoint.
+const redisSaveScript = `
+local seq = redis.call('INCR', KEYS[3])
+for _, member in ipairs(redis.call('ZRANGE', KEYS[2], 0, -1)) do
+	if cjson.decode(member).node_id == ARGV[1] then
+		redis.call('ZREM', KEYS[2], member)
+	end
+end
+redis.call('SET', KEYS[1], ARGV[2])
+redis.call('ZADD', KEYS[2], seq, '{"sequence":' .. seq .. ',' .. string.sub(ARGV[3], 2))
+local ttl = tonumber(ARGV[4])
+if ttl > 0 then
+	for i = 1, #KEYS do
+		redis.call('PEXPIRE', KEYS[i], ttl)
+	end
+end
+return seq
+`
+
+// redisDeleteScript removes one checkpoint and its index entry.
+//
+// KEYS: data, index. ARGV: nodeID.
+const redisDeleteScript = `
+redis.call('DEL', KEYS[1])
+for _, member in ipairs(redis.call('ZRANGE', KEYS[2], 0, -1)) do
+	if cjson.decode(member).node_id == ARGV[1] then
+		redis.call('ZREM', KEYS[2], member)
+	end
+end
+return 0
+`
+
+// redisDeleteKeysScript deletes every key passed.
+const redisDeleteKeysScript = `
+return redis.call('DEL', unpack(KEYS))
+`
+
+func (s *RedisStore) dataKey(runID, nodeID string) string {
+	return fmt.Sprintf("%s:{%s}:%s", s.opts.KeyPrefix, runID, nodeID)
+}
+
+func (s *RedisStore) indexKey(runID string) string {
+	return fmt.Sprintf("%s:index:{%s}", s.opts.KeyPrefix, runID)
+}
+
+func (s *RedisStore) seqKey(runID string) string {
+	return fmt.Sprintf("%s:seq:{%s}", s.opts.KeyPrefix, runID)
+}
+
+func (s *RedisStore) runsKey() string {
+	return s.opts.KeyPrefix + ":runs"
+}
+
+// opContext returns a context bounded by the configured timeout.
+func (s *RedisStore) opContext() (context.Context, context.CancelFunc) {
+	if s.opts.Timeout > 0 {
+		return context.WithTimeout(context.Background(), s.opts.Timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// Save implements Store.
+func (s *RedisStore) Save(runID, nodeID string, data []byte) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+
+	ctx, cancel := s.opContext()
+	defer cancel()
+
+	now := time.Now().UTC()
+	if err := s.client.ZAdd(ctx, s.runsKey(), float64(now.UnixMilli()), runID); err != nil {
+		return fmt.Errorf("save checkpoint: register run: %w", err)
+	}
+	if s.opts.TTL > 0 {
+		cutoff := now.Add(-s.opts.TTL).UnixMilli()
+		if err := s.client.ZRemRangeByScore(ctx, s.runsKey(), float64(cutoff)); err != nil {
+			return fmt.Errorf("save checkpoint: prune runs: %w", err)
+		}
+	}
+
+	// The run's other data keys, so the script can refresh their TTL
+	entries, err := s.index(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("save checkpoint: %w", err)
+	}
+	keys := []string{s.dataKey(runID, nodeID), s.indexKey(runID), s.seqKey(runID)}
+	for _, entry := range entries {
+		if entry.NodeID != nodeID {
+			keys = append(keys, s.dataKey(runID, entry.NodeID))
+		}
+	}
+
+	info, err := json.Marshal(struct {
+		NodeID    string            `json:"node_id"`
+		Timestamp time.Time         `json:"timestamp"`
+		Size      int64             `json:"size"`
+		Metadata  map[string]string `json:"metadata,omitempty"`
+	}{
+		NodeID:    nodeID,
+		Timestamp: now,
+		Size:      int64(len(data)),
+		Metadata:  metadataOf(data),
+	})
+	if err != nil {
+		return fmt.Errorf("save checkpoint: encode info: %w", err)
+	}
+
+	_, err = s.client.Eval(ctx, redisSaveScript, keys,
+		nodeID, string(data), string(info),
+		strconv.FormatInt(s.opts.TTL.Milliseconds(), 10))
+	if err != nil {
+		return fmt.Errorf("save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Load implements Store.
+func (s *RedisStore) Load(runID, nodeID string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil, ErrStoreClosed
+	}
+
+	ctx, cancel := s.opContext()
+	defer cancel()
+
+	data, found, err := s.client.Get(ctx, s.dataKey(runID, nodeID))
+	if err != nil {
+		return nil, fmt.Errorf("load checkpoint: %w", err)
+	}
+	if !found {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+// List implements Store.
+func (s *RedisStore) List(runID string) ([]Info, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil, ErrStoreClosed
+	}
+
+	ctx, cancel := s.opContext()
+	defer cancel()
+
+	entries, err := s.index(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("list checkpoints: %w", err)
+	}
+
+	infos := make([]Info, 0, len(entries))
+	for _, entry := range entries {
+		infos = append(infos, Info{
+			RunID:     runID,
+			NodeID:    entry.NodeID,
+			Sequence:  entry.Sequence,
+			Timestamp: entry.Timestamp,
+			Size:      entry.Size,
+			Metadata:  entry.Metadata,
+		})
+	}
+	return infos, nil
+}
+
+// Delete implements Store.
+func (s *RedisStore) Delete(runID, nodeID string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+
+	ctx, cancel := s.opContext()
+	defer cancel()
+
+	keys := []string{s.dataKey(runID, nodeID), s.indexKey(runID)}
+	if _, err := s.client.Eval(ctx, redisDeleteScript, keys, nodeID); err != nil {
+		return fmt.Errorf("delete checkpoint: %w", err)
+	}
+	return nil
+}
+
+// DeleteRun implements Store.
+func (s *RedisStore) DeleteRun(runID string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+
+	ctx, cancel := s.opContext()
+	defer cancel()
+
+	entries, err := s.index(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("delete run: %w", err)
+	}
+
+	keys := make([]string, 0, len(entries)+2)
+	for _, entry := range entries {
+		keys = append(keys, s.dataKey(runID, entry.NodeID))
+	}
+	keys = append(keys, s.indexKey(runID), s.seqKey(runID))
+
+	if _, err := s.client.Eval(ctx, redisDeleteKeysScript, keys); err != nil {
+		return fmt.Errorf("delete run: %w", err)
+	}
+	if err := s.client.ZRem(ctx, s.runsKey(), runID); err != nil {
+		return fmt.Errorf("delete run: %w", err)
+	}
+	return nil
+}
+
+// ListRuns implements Store.
+// Runs whose checkpoints have all been deleted or expired are omitted and
+// removed from the run registry.
+func (s *RedisStore) ListRuns() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil, ErrStoreClosed
+	}
+
+	ctx, cancel := s.opContext()
+	defer cancel()
+
+	candidates, err := s.client.ZRange(ctx, s.runsKey())
+	if err != nil {
+		return nil, fmt.Errorf("list runs: %w", err)
+	}
+
+	runs := make([]string, 0, len(candidates))
+	var stale []string
+	for _, runID := range candidates {
+		members, err := s.client.ZRange(ctx, s.indexKey(runID))
+		if err != nil {
+			return nil, fmt.Errorf("list runs: %w", err)
+		}
+		if len(members) > 0 {
+			runs = append(runs, runID)
+		} else {
+			stale = append(stale, runID)
+		}
+	}
+	if len(stale) > 0 {
+		if err := s.client.ZRem(ctx, s.runsKey(), stale...); err != nil {
+			return nil, fmt.Errorf("list runs: prune: %w", err)
+		}
+	}
+
+	sort.Strings(runs)
+	return runs, nil
+}
+
+// Close implements Store.
+// It marks the store closed but leaves the Redis client open.
+func (s *RedisStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	return nil
+}
+
+// index returns the run's index entries ordered by sequence.
+func (s *RedisStore) index(ctx context.Context, runID string) ([]redisInfo, error) {
+	members, err := s.client.ZRange(ctx, s.indexKey(runID))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]redisInfo, 0, len(members))
+	for _, member := range members {
+		var info redisInfo
+		if err := json.Unmarshal([]byte(member), &info); err != nil {
+			return nil, fmt.Errorf("decode index entry: %w", err)
+		}
+		entries = append(entries, info)
+	}
+	return entries, nil
+}
@@ -0,0 +1,251 @@
+package checkpoint_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/checkpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// goRedisClient adapts a go-redis client to checkpoint.RedisClient.
+type goRedisClient struct {
+	rdb *redis.Client
+}
+
+func (c goRedisClient) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+	return c.rdb.Eval(ctx, script, keys, args...).Result()
+}
+
+func (c goRedisClient) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.rdb.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (c goRedisClient) ZAdd(ctx context.Context, key string, score float64, member string) error {
+	return c.rdb.ZAdd(ctx, key, redis.Z{Score: score, Member: member}).Err()
+}
+
+func (c goRedisClient) ZRange(ctx context.Context, key string) ([]string, error) {
+	return c.rdb.ZRange(ctx, key, 0, -1).Result()
+}
+
+func (c goRedisClient) ZRem(ctx context.Context, key string, members ...string) error {
+	args := make([]any, len(members))
+	for i, member := range members {
+		args[i] = member
+	}
+	return c.rdb.ZRem(ctx, key, args...).Err()
+}
+
+func (c goRedisClient) ZRemRangeByScore(ctx context.Context, key string, max float64) error {
+	return c.rdb.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatFloat(max, 'f', -1, 64)).Err()
+}
+
+// newMiniRedis starts an in-process Redis server, which runs the store's
+// Lua scripts, and returns it with a client connected to it.
+func newMiniRedis(t *testing.T) (*miniredis.Miniredis, checkpoint.RedisClient) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return server, goRedisClient{rdb: rdb}
+}
+
+// TestRedisStore runs contract tests against RedisStore.
+func TestRedisStore(t *testing.T) {
+	factory := func(t *testing.T) checkpoint.Store {
+		_, client := newMiniRedis(t)
+		return checkpoint.NewRedisStore(client, checkpoint.RedisOptions{})
+	}
+	storeContractTest(t, "RedisStore", factory)
+}
+
+// TestRedisStore_KeyLayout tests the documented key layout.
+func TestRedisStore_KeyLayout(t *testing.T) {
+	server, client := newMiniRedis(t)
+	store := checkpoint.NewRedisStore(client, checkpoint.RedisOptions{})
+
+	require.NoError(t, store.Save("run-1", "node-a", []byte("a")))
+	require.NoError(t, store.Save("run-1", "node-b", []byte("b")))
+
+	assert.Equal(t, []string{
+		"flowgraph:index:{run-1}",
+		"flowgraph:runs",
+		"flowgraph:seq:{run-1}",
+		"flowgraph:{run-1}:node-a",
+		"flowgraph:{run-1}:node-b",
+	}, server.Keys())
+
+	require.NoError(t, store.DeleteRun("run-1"))
+	assert.Empty(t, server.Keys())
+}
+
+// TestRedisStore_OverwriteMovesToEnd tests that re-saving a node updates its sequence.
+func TestRedisStore_OverwriteMovesToEnd(t *testing.T) {
+	_, client := newMiniRedis(t)
+	store := checkpoint.NewRedisStore(client, checkpoint.RedisOptions{})
+
+	require.NoError(t, store.Save("run-1", "node-a", []byte("a")))
+	require.NoError(t, store.Save("run-1", "node-b", []byte("b")))
+	require.NoError(t, store.Save("run-1", "node-a", []byte("a2")))
+
+	infos, err := store.List("run-1")
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Equal(t, "node-b", infos[0].NodeID)
+	assert.Equal(t, "node-a", infos[1].NodeID)
+	assert.Equal(t, 3, infos[1].Sequence)
+	assert.Equal(t, int64(2), infos[1].Size)
+}
+
+// TestRedisStore_Delete tests that Delete removes both the data and its index entry.
+func TestRedisStore_Delete(t *testing.T) {
+	server, client := newMiniRedis(t)
+	store := checkpoint.NewRedisStore(client, checkpoint.RedisOptions{})
+
+	require.NoError(t, store.Save("run-1", "node-a", []byte("a")))
+	require.NoError(t, store.Save("run-1", "node-b", []byte("b")))
+	require.NoError(t, store.Delete("run-1", "node-a"))
+
+	assert.False(t, server.Exists("flowgraph:{run-1}:node-a"))
+	infos, err := store.List("run-1")
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, "node-b", infos[0].NodeID)
+}
+
+// TestRedisStore_TTL tests that every save refreshes the TTL of all of a
+// run's keys, so earlier checkpoints do not expire before later ones.
+func TestRedisStore_TTL(t *testing.T) {
+	server, client := newMiniRedis(t)
+	store := checkpoint.NewRedisStore(client, checkpoint.RedisOptions{
+		KeyPrefix: "fg",
+		TTL:       time.Hour,
+	})
+
+	require.NoError(t, store.Save("run-1", "node-a", []byte("a")))
+	server.FastForward(45 * time.Minute)
+	require.NoError(t, store.Save("run-1", "node-b", []byte("b")))
+
+	for _, key := range []string{"fg:{run-1}:node-a", "fg:{run-1}:node-b", "fg:index:{run-1}", "fg:seq:{run-1}"} {
+		assert.Equal(t, time.Hour, server.TTL(key), key)
+	}
+
+	server.FastForward(45 * time.Minute)
+	data, err := store.Load("run-1", "node-a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("a"), data)
+
+	server.FastForward(time.Hour)
+	_, err = store.Load("run-1", "node-a")
+	assert.ErrorIs(t, err, checkpoint.ErrNotFound)
+}
+
+// TestRedisStore_ExpiredRunsPruned tests that runs whose keys expired are
+// dropped from ListRuns and from the run registry.
+func TestRedisStore_ExpiredRunsPruned(t *testing.T) {
+	server, client := newMiniRedis(t)
+	store := checkpoint.NewRedisStore(client, checkpoint.RedisOptions{TTL: time.Hour})
+
+	require.NoError(t, store.Save("run-1", "node-a", []byte("a")))
+	server.FastForward(45 * time.Minute)
+	require.NoError(t, store.Save("run-2", "node-a", []byte("a")))
+	server.FastForward(45 * time.Minute)
+
+	runs, err := store.ListRuns()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"run-2"}, runs)
+
+	members, err := server.ZMembers("flowgraph:runs")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"run-2"}, members)
+}
+
+// TestRedisStore_SavePrunesStaleRuns tests that saves unregister runs
+// whose last save is older than the TTL.
+func TestRedisStore_SavePrunesStaleRuns(t *testing.T) {
+	server, client := newMiniRedis(t)
+	store := checkpoint.NewRedisStore(client, checkpoint.RedisOptions{TTL: time.Millisecond})
+
+	require.NoError(t, store.Save("run-1", "node-a", []byte("a")))
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, store.Save("run-2", "node-a", []byte("a")))
+
+	members, err := server.ZMembers("flowgraph:runs")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"run-2"}, members)
+}
+
+// TestRedisStore_NoReservedNodeIDs tests that node IDs cannot collide with
+// the store's own keys.
+func TestRedisStore_NoReservedNodeIDs(t *testing.T) {
+	_, client := newMiniRedis(t)
+	store := checkpoint.NewRedisStore(client, checkpoint.RedisOptions{})
+
+	for _, nodeID := range []string{"index", "seq", "runs", "index:{run-1}"} {
+		require.NoError(t, store.Save("run-1", nodeID, []byte(nodeID)))
+	}
+	require.NoError(t, store.Save("run-1", "seq", []byte("seq2")))
+
+	infos, err := store.List("run-1")
+	require.NoError(t, err)
+	require.Len(t, infos, 4)
+	assert.Equal(t, "seq", infos[3].NodeID)
+	assert.Equal(t, 5, infos[3].Sequence)
+
+	data, err := store.Load("run-1", "index:{run-1}")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("index:{run-1}"), data)
+}
+
+// TestRedisStore_SharedAcrossInstances tests that two stores on one server see the same data.
+func TestRedisStore_SharedAcrossInstances(t *testing.T) {
+	_, client := newMiniRedis(t)
+	writer := checkpoint.NewRedisStore(client, checkpoint.RedisOptions{})
+	reader := checkpoint.NewRedisStore(client, checkpoint.RedisOptions{})
+
+	require.NoError(t, writer.Save("run-1", "node-a", []byte("state")))
+
+	data, err := reader.Load("run-1", "node-a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("state"), data)
+}
+
+// TestRedisStore_Concurrent tests concurrent saves to one run.
+func TestRedisStore_Concurrent(t *testing.T) {
+	_, client := newMiniRedis(t)
+	store := checkpoint.NewRedisStore(client, checkpoint.RedisOptions{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, store.Save("run-1", fmt.Sprintf("node-%d", i), []byte("x")))
+		}(i)
+	}
+	wg.Wait()
+
+	infos, err := store.List("run-1")
+	require.NoError(t, err)
+	assert.Len(t, infos, 20)
+	for i, info := range infos {
+		assert.Equal(t, i+1, info.Sequence)
+	}
+}
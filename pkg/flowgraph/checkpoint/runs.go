@@ -0,0 +1,59 @@
+package checkpoint
+
+import "fmt"
+
+// RunSummary describes the most recent checkpoint of a run.
+type RunSummary struct {
+	// Info is the metadata of the run's latest checkpoint.
+	Info
+
+	// NextNode is where execution would continue on resume.
+	// It equals flowgraph.END for runs that finished.
+	NextNode string
+}
+
+// SummarizeRuns returns the latest checkpoint summary of every run in store,
+// ordered by run ID. Use it to find interrupted runs after a crash:
+//
+//	summaries, err := checkpoint.SummarizeRuns(store)
+//	for _, s := range summaries {
+//	    if s.NextNode != flowgraph.END {
+//	        compiled.Resume(ctx, store, s.RunID)
+//	    }
+//	}
+func SummarizeRuns(store Store) ([]RunSummary, error) {
+	runs, err := store.ListRuns()
+	if err != nil {
+		return nil, fmt.Errorf("list runs: %w", err)
+	}
+
+	summaries := make([]RunSummary, 0, len(runs))
+	for _, runID := range runs {
+		infos, err := store.List(runID)
+		if err != nil {
+			return nil, fmt.Errorf("list checkpoints for %s: %w", runID, err)
+		}
+		if len(infos) == 0 {
+			// Run was deleted since ListRuns
+			continue
+		}
+
+		latest := infos[len(infos)-1]
+		data, err := store.Load(runID, latest.NodeID)
+		if err != nil {
+			return nil, fmt.Errorf("load checkpoint for %s: %w", runID, err)
+		}
+
+		cp, err := Unmarshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("decode checkpoint for %s: %w", runID, err)
+		}
+
+		summaries = append(summaries, RunSummary{
+			Info:     latest,
+			NextNode: cp.NextNode,
+		})
+	}
+
+	return summaries, nil
+}
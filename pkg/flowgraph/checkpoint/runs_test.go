@@ -0,0 +1,45 @@
+package checkpoint_test
+
+import (
+	"testing"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/checkpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// saveCheckpoint stores a checkpoint whose next node is next.
+func saveCheckpoint(t *testing.T, store checkpoint.Store, runID, nodeID string, seq int, next string) {
+	t.Helper()
+	data, err := checkpoint.New(runID, nodeID, seq, []byte(`{}`), next).Marshal()
+	require.NoError(t, err)
+	require.NoError(t, store.Save(runID, nodeID, data))
+}
+
+// TestSummarizeRuns tests distinguishing completed and interrupted runs.
+func TestSummarizeRuns(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+
+	saveCheckpoint(t, store, "done", "a", 1, "b")
+	saveCheckpoint(t, store, "done", "b", 2, "__end__")
+	saveCheckpoint(t, store, "crashed", "a", 1, "b")
+
+	summaries, err := checkpoint.SummarizeRuns(store)
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+
+	assert.Equal(t, "crashed", summaries[0].RunID)
+	assert.Equal(t, "a", summaries[0].NodeID)
+	assert.Equal(t, "b", summaries[0].NextNode)
+
+	assert.Equal(t, "done", summaries[1].RunID)
+	assert.Equal(t, "b", summaries[1].NodeID)
+	assert.Equal(t, "__end__", summaries[1].NextNode)
+}
+
+// TestSummarizeRuns_Empty tests summarizing an empty store.
+func TestSummarizeRuns_Empty(t *testing.T) {
+	summaries, err := checkpoint.SummarizeRuns(checkpoint.NewMemoryStore())
+	require.NoError(t, err)
+	assert.Empty(t, summaries)
+}
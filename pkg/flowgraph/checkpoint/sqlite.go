@@ -2,6 +2,7 @@ package checkpoint
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -62,6 +63,7 @@ func NewSQLiteStore(path string) (*SQLiteStore, error) {
 			sequence INTEGER NOT NULL,
 			timestamp TEXT NOT NULL,
 			data BLOB NOT NULL,
+			metadata TEXT,
 			PRIMARY KEY (run_id, node_id)
 		)
 	`); err != nil {
@@ -69,6 +71,11 @@ func NewSQLiteStore(path string) (*SQLiteStore, error) {
 		return nil, fmt.Errorf("create table: %w", err)
 	}
 
+	if err := addMetadataColumn(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	if _, err := db.Exec(`
 		CREATE INDEX IF NOT EXISTS idx_checkpoints_run_id
 		ON checkpoints(run_id)
@@ -92,6 +99,25 @@ func NewSQLiteStore(path string) (*SQLiteStore, error) {
 	return &SQLiteStore{db: db}, nil
 }
 
+// addMetadataColumn adds the metadata column to tables created before it
+// existed.
+func addMetadataColumn(db *sql.DB) error {
+	var count int
+	if err := db.QueryRow(`
+		SELECT COUNT(*) FROM pragma_table_info('checkpoints') WHERE name = 'metadata'
+	`).Scan(&count); err != nil {
+		return fmt.Errorf("inspect table: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if _, err := db.Exec(`ALTER TABLE checkpoints ADD COLUMN metadata TEXT`); err != nil {
+		return fmt.Errorf("add metadata column: %w", err)
+	}
+	return nil
+}
+
 // Save implements Store.
 func (s *SQLiteStore) Save(runID, nodeID string, data []byte) error {
 	s.mu.Lock()
@@ -101,20 +127,30 @@ func (s *SQLiteStore) Save(runID, nodeID string, data []byte) error {
 		return ErrStoreClosed
 	}
 
+	var metadata sql.NullString
+	if m := metadataOf(data); m != nil {
+		encoded, err := json.Marshal(m)
+		if err != nil {
+			return fmt.Errorf("save checkpoint: encode metadata: %w", err)
+		}
+		metadata = sql.NullString{String: string(encoded), Valid: true}
+	}
+
 	// Use INSERT OR REPLACE to handle updates
 	// Calculate sequence as max + 1 for this run
 	_, err := s.db.Exec(`
-		INSERT INTO checkpoints (run_id, node_id, sequence, timestamp, data)
+		INSERT INTO checkpoints (run_id, node_id, sequence, timestamp, data, metadata)
 		VALUES (
 			?, ?,
 			COALESCE((SELECT MAX(sequence) FROM checkpoints WHERE run_id = ?), 0) + 1,
-			?, ?
+			?, ?, ?
 		)
 		ON CONFLICT(run_id, node_id) DO UPDATE SET
 			sequence = (SELECT MAX(sequence) FROM checkpoints WHERE run_id = excluded.run_id) + 1,
 			timestamp = excluded.timestamp,
-			data = excluded.data
-	`, runID, nodeID, runID, time.Now().UTC().Format(time.RFC3339Nano), data)
+			data = excluded.data,
+			metadata = excluded.metadata
+	`, runID, nodeID, runID, time.Now().UTC().Format(time.RFC3339Nano), data, metadata)
 
 	if err != nil {
 		return fmt.Errorf("save checkpoint: %w", err)
@@ -156,7 +192,7 @@ func (s *SQLiteStore) List(runID string) ([]Info, error) {
 	}
 
 	rows, err := s.db.Query(`
-		SELECT node_id, sequence, timestamp, LENGTH(data)
+		SELECT node_id, sequence, timestamp, LENGTH(data), metadata
 		FROM checkpoints
 		WHERE run_id = ?
 		ORDER BY sequence
@@ -170,10 +206,16 @@ func (s *SQLiteStore) List(runID string) ([]Info, error) {
 	for rows.Next() {
 		var info Info
 		var timestamp string
-		if err := rows.Scan(&info.NodeID, &info.Sequence, &timestamp, &info.Size); err != nil {
+		var metadata sql.NullString
+		if err := rows.Scan(&info.NodeID, &info.Sequence, &timestamp, &info.Size, &metadata); err != nil {
 			return nil, fmt.Errorf("scan checkpoint info: %w", err)
 		}
 		info.RunID = runID
+		if metadata.Valid {
+			if err := json.Unmarshal([]byte(metadata.String), &info.Metadata); err != nil {
+				return nil, fmt.Errorf("decode checkpoint metadata: %w", err)
+			}
+		}
 		var parseErr error
 		info.Timestamp, parseErr = time.Parse(time.RFC3339Nano, timestamp)
 		if parseErr != nil {
@@ -232,6 +274,39 @@ func (s *SQLiteStore) DeleteRun(runID string) error {
 	return nil
 }
 
+// ListRuns implements Store.
+func (s *SQLiteStore) ListRuns() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil, ErrStoreClosed
+	}
+
+	rows, err := s.db.Query(`
+		SELECT DISTINCT run_id FROM checkpoints ORDER BY run_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []string
+	for rows.Next() {
+		var runID string
+		if err := rows.Scan(&runID); err != nil {
+			return nil, fmt.Errorf("scan run id: %w", err)
+		}
+		runs = append(runs, runID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate runs: %w", err)
+	}
+
+	return runs, nil
+}
+
 // Close implements Store.
 func (s *SQLiteStore) Close() error {
 	s.mu.Lock()
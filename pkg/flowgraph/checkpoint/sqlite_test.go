@@ -1,6 +1,7 @@
 package checkpoint_test
 
 import (
+	"database/sql"
 	"os"
 	"path/filepath"
 	"sync"
@@ -34,6 +35,43 @@ func TestSQLiteStore_Persistence(t *testing.T) {
 	assert.Equal(t, []byte("persistent"), data)
 }
 
+// TestSQLiteStore_AddsMetadataColumn tests opening a database created before checkpoint metadata.
+func TestSQLiteStore_AddsMetadataColumn(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "old.db")
+	db, err := sql.Open("sqlite", dbPath)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		CREATE TABLE checkpoints (
+			run_id TEXT NOT NULL,
+			node_id TEXT NOT NULL,
+			sequence INTEGER NOT NULL,
+			timestamp TEXT NOT NULL,
+			data BLOB NOT NULL,
+			PRIMARY KEY (run_id, node_id)
+		)
+	`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO checkpoints VALUES ('run-1', 'node-a', 1, '2024-01-01T00:00:00Z', 'old')`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	store, err := checkpoint.NewSQLiteStore(dbPath)
+	require.NoError(t, err)
+	defer store.Close()
+
+	data, err := checkpoint.New("run-1", "node-b", 2, []byte(`{}`), "END").
+		WithMetadata(map[string]string{"stage": "review"}).
+		Marshal()
+	require.NoError(t, err)
+	require.NoError(t, store.Save("run-1", "node-b", data))
+
+	infos, err := store.List("run-1")
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Nil(t, infos[0].Metadata)
+	assert.Equal(t, map[string]string{"stage": "review"}, infos[1].Metadata)
+}
+
 func TestSQLiteStore_InvalidPath(t *testing.T) {
 	// Try to create in non-existent directory
 	_, err := checkpoint.NewSQLiteStore("/nonexistent/path/db.sqlite")
@@ -29,6 +29,10 @@ type Store interface {
 	// Returns nil if run has no checkpoints.
 	DeleteRun(runID string) error
 
+	// ListRuns returns the IDs of all runs that have checkpoints, sorted.
+	// Returns empty slice (not error) if the store is empty.
+	ListRuns() ([]string, error)
+
 	// Close releases any resources (connections, files).
 	Close() error
 }
@@ -40,6 +44,7 @@ type Info struct {
 	Sequence  int
 	Timestamp time.Time
 	Size      int64
+	Metadata  map[string]string // Checkpoint.Metadata; nil if none
 }
 
 // Sentinel errors for checkpoint operations.
@@ -59,6 +59,24 @@ func storeContractTest(t *testing.T, name string, factory storeFactory) {
 		assert.Empty(t, infos)
 	})
 
+	t.Run(name+"/List_Metadata", func(t *testing.T) {
+		store := factory(t)
+		defer store.Close()
+
+		cp := checkpoint.New("run-1", "node-a", 1, []byte(`{}`), "node-b").
+			WithMetadata(map[string]string{"git_sha": "abc123"})
+		data, err := cp.Marshal()
+		require.NoError(t, err)
+		require.NoError(t, store.Save("run-1", "node-a", data))
+		require.NoError(t, store.Save("run-1", "node-b", []byte("not a checkpoint")))
+
+		infos, err := store.List("run-1")
+		require.NoError(t, err)
+		require.Len(t, infos, 2)
+		assert.Equal(t, map[string]string{"git_sha": "abc123"}, infos[0].Metadata)
+		assert.Nil(t, infos[1].Metadata)
+	})
+
 	t.Run(name+"/List_Ordered", func(t *testing.T) {
 		store := factory(t)
 		defer store.Close()
@@ -165,6 +183,32 @@ func storeContractTest(t *testing.T, name string, factory storeFactory) {
 		assert.Len(t, infos2, 1)
 	})
 
+	t.Run(name+"/ListRuns", func(t *testing.T) {
+		store := factory(t)
+		defer store.Close()
+
+		runs, err := store.ListRuns()
+		require.NoError(t, err)
+		assert.Empty(t, runs)
+
+		require.NoError(t, store.Save("run-b", "node-a", []byte("b")))
+		require.NoError(t, store.Save("run-a", "node-a", []byte("a1")))
+		require.NoError(t, store.Save("run-a", "node-b", []byte("a2")))
+		require.NoError(t, store.Save("run-c", "node-a", []byte("c")))
+
+		runs, err = store.ListRuns()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"run-a", "run-b", "run-c"}, runs)
+
+		// Deleted runs disappear, including runs emptied node by node
+		require.NoError(t, store.DeleteRun("run-b"))
+		require.NoError(t, store.Delete("run-c", "node-a"))
+
+		runs, err = store.ListRuns()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"run-a"}, runs)
+	})
+
 	t.Run(name+"/DataCopy", func(t *testing.T) {
 		store := factory(t)
 		defer store.Close()
@@ -194,6 +238,9 @@ func storeContractTest(t *testing.T, name string, factory storeFactory) {
 
 		_, err = store.List("run-1")
 		assert.ErrorIs(t, err, checkpoint.ErrStoreClosed)
+
+		_, err = store.ListRuns()
+		assert.ErrorIs(t, err, checkpoint.ErrStoreClosed)
 	})
 }
 
@@ -389,3 +389,430 @@ func TestCheckpointing_CheckpointData(t *testing.T) {
 	assert.Equal(t, 42, state.Value)
 	assert.Equal(t, []string{"processed"}, state.Messages)
 }
+
+func TestCheckpointing_Compression(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+
+	graph := flowgraph.NewGraph[CheckpointState]().
+		AddNode("fill", func(ctx flowgraph.Context, s CheckpointState) (CheckpointState, error) {
+			for i := 0; i < 500; i++ {
+				s.Messages = append(s.Messages, "a fairly repetitive embedded document line")
+			}
+			return s, nil
+		}).
+		AddEdge("fill", flowgraph.END).
+		SetEntry("fill")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	ctx := flowgraph.NewContext(context.Background())
+	_, err = compiled.Run(ctx, CheckpointState{},
+		flowgraph.WithCheckpointing(store),
+		flowgraph.WithRunID("gzip-test"),
+		flowgraph.WithCheckpointCompression(flowgraph.CompressionGzip))
+	require.NoError(t, err)
+
+	data, err := store.Load("gzip-test", "fill")
+	require.NoError(t, err)
+	cp, err := checkpoint.Unmarshal(data)
+	require.NoError(t, err)
+
+	raw, err := json.Marshal(CheckpointState{Messages: make([]string, 500)})
+	require.NoError(t, err)
+	assert.Less(t, len(cp.State), len(raw), "compressed state should be smaller than raw JSON")
+	assert.NotEqual(t, byte('{'), cp.State[0], "stored state should not be a plain JSON object")
+}
+
+func TestCheckpointing_ResumeCompressed(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+
+	var executedNodes []string
+	crashOnB := true
+	makeNode := func(name string) flowgraph.NodeFunc[CheckpointState] {
+		return func(ctx flowgraph.Context, s CheckpointState) (CheckpointState, error) {
+			executedNodes = append(executedNodes, name)
+			s.Value++
+			s.Messages = append(s.Messages, name)
+			if name == "b" && crashOnB {
+				return s, errors.New("crash")
+			}
+			return s, nil
+		}
+	}
+
+	graph := flowgraph.NewGraph[CheckpointState]().
+		AddNode("a", makeNode("a")).
+		AddNode("b", makeNode("b")).
+		AddNode("c", makeNode("c")).
+		AddEdge("a", "b").
+		AddEdge("b", "c").
+		AddEdge("c", flowgraph.END).
+		SetEntry("a")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	ctx := flowgraph.NewContext(context.Background())
+	_, err = compiled.Run(ctx, CheckpointState{},
+		flowgraph.WithCheckpointing(store),
+		flowgraph.WithRunID("gzip-resume"),
+		flowgraph.WithCheckpointCompression(flowgraph.CompressionGzip))
+	require.Error(t, err)
+
+	crashOnB = false
+	executedNodes = nil
+	result, err := compiled.Resume(ctx, store, "gzip-resume")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, executedNodes)
+	assert.Equal(t, 3, result.Value)
+	assert.Equal(t, []string{"a", "b", "c"}, result.Messages)
+
+	// The resumed run keeps compressing its checkpoints
+	data, err := store.Load("gzip-resume", "c")
+	require.NoError(t, err)
+	cp, err := checkpoint.Unmarshal(data)
+	require.NoError(t, err)
+	assert.NotEqual(t, byte('{'), cp.State[0])
+
+	executedNodes = nil
+	result, err = compiled.ResumeFrom(ctx, store, "gzip-resume", "b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c"}, executedNodes)
+	assert.Equal(t, 3, result.Value)
+}
+
+func TestCheckpointing_ResumeUncompressedLegacy(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+
+	state, err := json.Marshal(CheckpointState{Value: 7})
+	require.NoError(t, err)
+	data, err := checkpoint.New("legacy", "a", 1, state, "b").Marshal()
+	require.NoError(t, err)
+	require.NoError(t, store.Save("legacy", "a", data))
+
+	graph := flowgraph.NewGraph[CheckpointState]().
+		AddNode("a", func(ctx flowgraph.Context, s CheckpointState) (CheckpointState, error) { return s, nil }).
+		AddNode("b", func(ctx flowgraph.Context, s CheckpointState) (CheckpointState, error) {
+			s.Value++
+			return s, nil
+		}).
+		AddEdge("a", "b").
+		AddEdge("b", flowgraph.END).
+		SetEntry("a")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Resume(flowgraph.NewContext(context.Background()), store, "legacy")
+	require.NoError(t, err)
+	assert.Equal(t, 8, result.Value)
+}
+
+// crashingGraph returns a graph a -> b -> c where b fails while *crash is true.
+func crashingGraph(t *testing.T, crash *bool) *flowgraph.CompiledGraph[CheckpointState] {
+	t.Helper()
+	makeNode := func(name string) flowgraph.NodeFunc[CheckpointState] {
+		return func(ctx flowgraph.Context, s CheckpointState) (CheckpointState, error) {
+			s.Value++
+			s.Messages = append(s.Messages, name)
+			if name == "b" && *crash {
+				return s, errors.New("crash")
+			}
+			return s, nil
+		}
+	}
+
+	compiled, err := flowgraph.NewGraph[CheckpointState]().
+		AddNode("a", makeNode("a")).
+		AddNode("b", makeNode("b")).
+		AddNode("c", makeNode("c")).
+		AddEdge("a", "b").
+		AddEdge("b", "c").
+		AddEdge("c", flowgraph.END).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+	return compiled
+}
+
+func TestCheckpointing_Encryption(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	stores := map[string]func(t *testing.T) checkpoint.Store{
+		"memory": func(t *testing.T) checkpoint.Store { return checkpoint.NewMemoryStore() },
+		"sqlite": func(t *testing.T) checkpoint.Store {
+			store, err := checkpoint.NewSQLiteStore(t.TempDir() + "/cp.db")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = store.Close() })
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+			crash := true
+			compiled := crashingGraph(t, &crash)
+			ctx := flowgraph.NewContext(context.Background())
+
+			_, err := compiled.Run(ctx, CheckpointState{Messages: []string{"secret-ssn"}},
+				flowgraph.WithCheckpointing(store),
+				flowgraph.WithRunID("enc-run"),
+				flowgraph.WithCheckpointEncryption(key),
+				flowgraph.WithCheckpointCompression(flowgraph.CompressionGzip))
+			require.Error(t, err)
+
+			data, err := store.Load("enc-run", "a")
+			require.NoError(t, err)
+			assert.NotContains(t, string(data), "secret-ssn")
+
+			crash = false
+			result, err := compiled.Resume(ctx, store, "enc-run", flowgraph.WithDecryptionKey(key))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"secret-ssn", "a", "b", "c"}, result.Messages)
+
+			// Checkpoints written by the resumed run are encrypted too
+			data, err = store.Load("enc-run", "c")
+			require.NoError(t, err)
+			assert.NotContains(t, string(data), "secret-ssn")
+
+			result, err = compiled.ResumeFrom(ctx, store, "enc-run", "b", flowgraph.WithDecryptionKey(key))
+			require.NoError(t, err)
+			assert.Equal(t, 3, result.Value)
+		})
+	}
+}
+
+func TestCheckpointing_DecryptionFailure(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	crash := true
+	compiled := crashingGraph(t, &crash)
+	ctx := flowgraph.NewContext(context.Background())
+
+	_, err := compiled.Run(ctx, CheckpointState{},
+		flowgraph.WithCheckpointing(store),
+		flowgraph.WithRunID("enc-fail"),
+		flowgraph.WithCheckpointEncryption([]byte("0123456789abcdef")))
+	require.Error(t, err)
+	crash = false
+
+	_, err = compiled.Resume(ctx, store, "enc-fail",
+		flowgraph.WithDecryptionKey([]byte("fedcba9876543210")))
+	assert.ErrorIs(t, err, flowgraph.ErrCheckpointDecryption)
+	assert.NotErrorIs(t, err, flowgraph.ErrDeserializeState)
+
+	_, err = compiled.Resume(ctx, store, "enc-fail")
+	assert.ErrorIs(t, err, flowgraph.ErrCheckpointDecryption)
+
+	_, err = compiled.ResumeFrom(ctx, store, "enc-fail", "a")
+	assert.ErrorIs(t, err, flowgraph.ErrCheckpointDecryption)
+}
+
+func TestCheckpointing_DecryptionKeyWithPlainCheckpoint(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	crash := true
+	compiled := crashingGraph(t, &crash)
+	ctx := flowgraph.NewContext(context.Background())
+
+	_, err := compiled.Run(ctx, CheckpointState{},
+		flowgraph.WithCheckpointing(store),
+		flowgraph.WithRunID("plain"))
+	require.Error(t, err)
+	crash = false
+
+	result, err := compiled.Resume(ctx, store, "plain",
+		flowgraph.WithDecryptionKey([]byte("0123456789abcdef")))
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Value)
+}
+
+func TestCheckpointing_BeforeResumeTransformsState(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	crash := true
+	compiled := crashingGraph(t, &crash)
+	ctx := flowgraph.NewContext(context.Background())
+
+	_, err := compiled.Run(ctx, CheckpointState{},
+		flowgraph.WithCheckpointing(store),
+		flowgraph.WithRunID("before-resume"))
+	require.Error(t, err)
+
+	crash = false
+	var seen flowgraph.ResumeInfo
+	result, err := compiled.Resume(ctx, store, "before-resume",
+		flowgraph.WithBeforeResume(func(info flowgraph.ResumeInfo, s CheckpointState) (CheckpointState, error) {
+			seen = info
+			s.Value += 100
+			s.Messages = append(s.Messages, "patched")
+			return s, nil
+		}))
+	require.NoError(t, err)
+
+	infos, err := store.List("before-resume")
+	require.NoError(t, err)
+	require.Len(t, infos, 3) // a from the crashed run, then b and c
+	assert.Equal(t, flowgraph.ResumeInfo{
+		RunID:    "before-resume",
+		LastNode: "a",
+		NextNode: "b",
+		Sequence: infos[0].Sequence,
+	}, seen)
+
+	assert.Equal(t, 103, result.Value)
+	assert.Equal(t, []string{"a", "patched", "b", "c"}, result.Messages)
+}
+
+func TestCheckpointing_BeforeResumeAborts(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	crash := true
+	compiled := crashingGraph(t, &crash)
+	ctx := flowgraph.NewContext(context.Background())
+
+	_, err := compiled.Run(ctx, CheckpointState{},
+		flowgraph.WithCheckpointing(store),
+		flowgraph.WithRunID("abort-resume"))
+	require.Error(t, err)
+
+	crash = false
+	expired := errors.New("run expired")
+	result, err := compiled.Resume(ctx, store, "abort-resume",
+		flowgraph.WithBeforeResume(func(_ flowgraph.ResumeInfo, s CheckpointState) (CheckpointState, error) {
+			return s, expired
+		}))
+	require.ErrorIs(t, err, flowgraph.ErrResumeAborted)
+	require.ErrorIs(t, err, expired)
+	assert.Equal(t, 1, result.Value, "restored state is returned")
+
+	infos, err := store.List("abort-resume")
+	require.NoError(t, err)
+	assert.Len(t, infos, 1, "no nodes ran")
+}
+
+func TestCheckpointing_Metadata(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	crash := true
+	compiled := crashingGraph(t, &crash)
+	ctx := flowgraph.NewContext(context.Background())
+	metadata := map[string]string{"git_sha": "abc123"}
+
+	_, err := compiled.Run(ctx, CheckpointState{},
+		flowgraph.WithCheckpointing(store),
+		flowgraph.WithRunID("metadata"),
+		flowgraph.WithCheckpointMetadata(metadata))
+	require.Error(t, err)
+	metadata["git_sha"] = "changed" // The option copies the map
+
+	infos, err := store.List("metadata")
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, map[string]string{"git_sha": "abc123"}, infos[0].Metadata)
+
+	crash = false
+	var seen flowgraph.ResumeInfo
+	_, err = compiled.Resume(ctx, store, "metadata",
+		flowgraph.WithBeforeResume(func(info flowgraph.ResumeInfo, s CheckpointState) (CheckpointState, error) {
+			seen = info
+			return s, nil
+		}))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"git_sha": "abc123"}, seen.Metadata)
+
+	infos, err = store.List("metadata")
+	require.NoError(t, err)
+	assert.Nil(t, infos[len(infos)-1].Metadata, "resumed run saves without metadata unless given")
+}
+
+func TestCheckpointing_Interval(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	crash := true
+	var executed []string
+	graph := flowgraph.NewGraph[CheckpointState]()
+	nodes := []string{"a", "b", "c", "d", "e"}
+	for i, name := range nodes {
+		graph.AddNode(name, func(ctx flowgraph.Context, s CheckpointState) (CheckpointState, error) {
+			executed = append(executed, name)
+			if name == "d" && crash {
+				return s, errors.New("crash")
+			}
+			s.Value++
+			s.Messages = append(s.Messages, name)
+			return s, nil
+		})
+		if i > 0 {
+			graph.AddEdge(nodes[i-1], name)
+		}
+	}
+	compiled, err := graph.AddEdge("e", flowgraph.END).SetEntry("a").Compile()
+	require.NoError(t, err)
+	ctx := flowgraph.NewContext(context.Background())
+	opts := []flowgraph.RunOption{
+		flowgraph.WithCheckpointing(store),
+		flowgraph.WithRunID("interval"),
+		flowgraph.WithCheckpointInterval(2),
+	}
+
+	_, err = compiled.Run(ctx, CheckpointState{}, opts...)
+	require.Error(t, err)
+
+	infos, err := store.List("interval")
+	require.NoError(t, err)
+	require.Len(t, infos, 1, "only b, the second node, was checkpointed")
+	assert.Equal(t, "b", infos[0].NodeID)
+
+	crash = false
+	executed = nil
+	result, err := compiled.Resume(ctx, store, "interval",
+		flowgraph.WithResumeRunOptions(flowgraph.WithCheckpointInterval(2)))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"c", "d", "e"}, executed, "c re-executes since it was not checkpointed")
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, result.Messages)
+
+	infos, err = store.List("interval")
+	require.NoError(t, err)
+	assert.Equal(t, "e", infos[len(infos)-1].NodeID, "the last node before END is always checkpointed")
+}
+
+func TestCheckpointing_BeforeResumeFromWithReplay(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	crash := false
+	compiled := crashingGraph(t, &crash)
+	ctx := flowgraph.NewContext(context.Background())
+
+	_, err := compiled.Run(ctx, CheckpointState{},
+		flowgraph.WithCheckpointing(store),
+		flowgraph.WithRunID("before-resume-from"))
+	require.NoError(t, err)
+
+	var seen flowgraph.ResumeInfo
+	_, err = compiled.ResumeFrom(ctx, store, "before-resume-from", "b",
+		flowgraph.WithReplayNode(),
+		flowgraph.WithBeforeResume(func(info flowgraph.ResumeInfo, s CheckpointState) (CheckpointState, error) {
+			seen = info
+			return s, nil
+		}))
+	require.NoError(t, err)
+	assert.Equal(t, "before-resume-from", seen.RunID)
+	assert.Equal(t, "b", seen.LastNode)
+	assert.Equal(t, "b", seen.NextNode)
+	assert.Equal(t, 2, seen.Sequence)
+}
+
+func TestCheckpointing_BeforeResumeWrongStateType(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	crash := true
+	compiled := crashingGraph(t, &crash)
+	ctx := flowgraph.NewContext(context.Background())
+
+	_, err := compiled.Run(ctx, CheckpointState{},
+		flowgraph.WithCheckpointing(store),
+		flowgraph.WithRunID("wrong-type"))
+	require.Error(t, err)
+
+	_, err = compiled.Resume(ctx, store, "wrong-type",
+		flowgraph.WithBeforeResume(func(_ flowgraph.ResumeInfo, s string) (string, error) {
+			return s, nil
+		}))
+	require.ErrorIs(t, err, flowgraph.ErrResumeAborted)
+}
@@ -0,0 +1,127 @@
+package flowgraph_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph"
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/checkpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hookState cannot be serialized as JSON because of its func field.
+type hookState struct {
+	Value int
+	Seen  time.Time
+	Hook  func()
+}
+
+// hookGraph compiles a -> b -> c, each adding one to Value.
+func hookGraph(t *testing.T) *flowgraph.CompiledGraph[hookState] {
+	t.Helper()
+	add := func(ctx flowgraph.Context, s hookState) (hookState, error) {
+		s.Value++
+		return s, nil
+	}
+	compiled, err := flowgraph.NewGraph[hookState]().
+		AddNode("a", add).
+		AddNode("b", add).
+		AddNode("c", add).
+		AddEdge("a", "b").
+		AddEdge("b", "c").
+		AddEdge("c", flowgraph.END).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+	return compiled
+}
+
+// TestCheckpointCodec_Gob tests that the gob codec checkpoints state JSON cannot.
+func TestCheckpointCodec_Gob(t *testing.T) {
+	compiled := hookGraph(t)
+	ctx := flowgraph.NewContext(t.Context())
+	state := hookState{Seen: time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC), Hook: func() {}}
+
+	_, err := compiled.Run(ctx, state,
+		flowgraph.WithCheckpointing(checkpoint.NewMemoryStore()),
+		flowgraph.WithRunID("json-run"))
+	var cpErr *flowgraph.CheckpointError
+	require.ErrorAs(t, err, &cpErr, "JSON cannot encode a func field")
+	assert.Equal(t, "serialize", cpErr.Op)
+
+	store := checkpoint.NewMemoryStore()
+	_, err = compiled.Run(ctx, state,
+		flowgraph.WithCheckpointing(store),
+		flowgraph.WithRunID("gob-run"),
+		flowgraph.WithCheckpointCodec(flowgraph.GobCodec{}))
+	require.NoError(t, err)
+
+	data, err := store.Load("gob-run", "b")
+	require.NoError(t, err)
+	cp, err := checkpoint.Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, "gob", cp.Codec)
+	assert.True(t, json.Valid(cp.State))
+
+	// Resume decodes with the recorded codec, not the default
+	result, err := compiled.ResumeFrom(ctx, store, "gob-run", "a")
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Value)
+	assert.True(t, state.Seen.Equal(result.Seen))
+	assert.Nil(t, result.Hook, "func fields are skipped")
+}
+
+// TestCheckpointCodec_GobCompressedEncrypted tests the gob codec with compression and encryption.
+func TestCheckpointCodec_GobCompressedEncrypted(t *testing.T) {
+	compiled := hookGraph(t)
+	ctx := flowgraph.NewContext(t.Context())
+	store := checkpoint.NewMemoryStore()
+	key := make([]byte, 32)
+
+	_, err := compiled.Run(ctx, hookState{},
+		flowgraph.WithCheckpointing(store),
+		flowgraph.WithRunID("run-1"),
+		flowgraph.WithCheckpointCodec(flowgraph.GobCodec{}),
+		flowgraph.WithCheckpointCompression(flowgraph.CompressionGzip),
+		flowgraph.WithCheckpointEncryption(key))
+	require.NoError(t, err)
+
+	result, err := compiled.ResumeFrom(ctx, store, "run-1", "b", flowgraph.WithDecryptionKey(key))
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Value)
+}
+
+// renamedCodec is a custom codec: gob under another name.
+type renamedCodec struct{ flowgraph.GobCodec }
+
+func (renamedCodec) Name() string { return "custom" }
+
+// TestCheckpointCodec_Custom tests that resuming a custom-codec checkpoint needs the codec.
+func TestCheckpointCodec_Custom(t *testing.T) {
+	compiled := hookGraph(t)
+	ctx := flowgraph.NewContext(t.Context())
+	store := checkpoint.NewMemoryStore()
+
+	_, err := compiled.Run(ctx, hookState{},
+		flowgraph.WithCheckpointing(store),
+		flowgraph.WithRunID("run-1"),
+		flowgraph.WithCheckpointCodec(renamedCodec{}))
+	require.NoError(t, err)
+
+	_, err = compiled.ResumeFrom(ctx, store, "run-1", "a")
+	assert.ErrorIs(t, err, flowgraph.ErrDeserializeState)
+
+	result, err := compiled.ResumeFrom(ctx, store, "run-1", "a",
+		flowgraph.WithResumeRunOptions(flowgraph.WithCheckpointCodec(renamedCodec{})))
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Value)
+}
+
+// TestWithCheckpointCodec_Nil tests that a nil codec panics.
+func TestWithCheckpointCodec_Nil(t *testing.T) {
+	assert.PanicsWithValue(t, "flowgraph: checkpoint codec cannot be nil", func() {
+		flowgraph.WithCheckpointCodec(nil)
+	})
+}
@@ -0,0 +1,285 @@
+package flowgraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/checkpoint"
+)
+
+// DiffReport describes how a replay of a golden run differs from it.
+type DiffReport struct {
+	// GoldenRunID is the ID of the run that was replayed.
+	GoldenRunID string
+
+	// GoldenPath is the golden run's nodes in checkpoint order.
+	GoldenPath []string
+
+	// ReplayPath is the replay's nodes in checkpoint order.
+	ReplayPath []string
+
+	// DivergedAt is the index of the first differing entry in the paths,
+	// or -1 if they are identical.
+	DivergedAt int
+
+	// StateDiffs lists the differences between the final states.
+	StateDiffs []StateDiff
+}
+
+// StateDiff is a single difference between two final states.
+type StateDiff struct {
+	// Path locates the value in the state's JSON form, e.g. "items[2].name".
+	// It is empty when the states differ at the top level.
+	Path string
+
+	// Golden is the golden run's value, or nil if absent.
+	Golden any
+
+	// Replay is the replay's value, or nil if absent.
+	Replay any
+}
+
+// HasDiff reports whether the replay differed from the golden run.
+func (r DiffReport) HasDiff() bool {
+	return r.DivergedAt >= 0 || len(r.StateDiffs) > 0
+}
+
+// String summarizes the report for test and CI output.
+func (r DiffReport) String() string {
+	if !r.HasDiff() {
+		return fmt.Sprintf("run %s: no differences", r.GoldenRunID)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "run %s:", r.GoldenRunID)
+	if r.DivergedAt >= 0 {
+		fmt.Fprintf(&b, "\n  path diverged at step %d: golden %s, replay %s",
+			r.DivergedAt+1, pathStep(r.GoldenPath, r.DivergedAt), pathStep(r.ReplayPath, r.DivergedAt))
+	}
+	for _, d := range r.StateDiffs {
+		path := d.Path
+		if path == "" {
+			path = "(state)"
+		}
+		fmt.Fprintf(&b, "\n  %s: golden %v, replay %v", path, d.Golden, d.Replay)
+	}
+	return b.String()
+}
+
+// pathStep returns the node at index i of path, or END past its end.
+func pathStep(path []string, i int) string {
+	if i < len(path) {
+		return path[i]
+	}
+	return END
+}
+
+// CompareRun replays a golden run on the current graph and reports any
+// difference in the nodes executed or the final state. Use it in tests to
+// catch unintended behavior changes when modifying nodes or routing.
+//
+// The golden run must have been checkpointed to store with
+// WithInitialCheckpoint, so its initial state is known; otherwise
+// CompareRun returns ErrNoInitialCheckpoint. The replay runs from that
+// state with opts, checkpointing to a temporary in-memory store. The
+// golden store is not modified. Pass WithCheckpointEncryption if the golden
+// run was encrypted.
+//
+// Paths are compared as checkpoint histories, which record each node's
+// most recent visit, so a node revisited in a loop appears once. The final
+// states are compared in their JSON form. If the replay fails, its error
+// is returned along with the report so far.
+//
+// Example:
+//
+//	report, err := compiled.CompareRun(ctx, "golden-1", store)
+//	require.NoError(t, err)
+//	assert.False(t, report.HasDiff(), report.String())
+func (cg *CompiledGraph[S]) CompareRun(ctx Context, goldenRunID string, store checkpoint.Store, opts ...RunOption) (DiffReport, error) {
+	report := DiffReport{GoldenRunID: goldenRunID, DivergedAt: -1}
+	if ctx == nil {
+		return report, ErrNilContext
+	}
+
+	cfg := defaultRunConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	golden, err := loadRunHistory(store, goldenRunID)
+	if err != nil {
+		return report, err
+	}
+	if len(golden) == 0 {
+		return report, fmt.Errorf("%w: %s", ErrNoCheckpoints, goldenRunID)
+	}
+	if golden[0].NodeID != START {
+		return report, fmt.Errorf("%w: %s", ErrNoInitialCheckpoint, goldenRunID)
+	}
+
+	var initial, goldenFinal S
+	if _, _, err := decodeCheckpointState(golden[0], cfg.checkpointCodec, cfg.checkpointCipher, &initial); err != nil {
+		return report, err
+	}
+	if _, _, err := decodeCheckpointState(golden[len(golden)-1], cfg.checkpointCodec, cfg.checkpointCipher, &goldenFinal); err != nil {
+		return report, err
+	}
+
+	replayStore := checkpoint.NewMemoryStore()
+	defer replayStore.Close()
+	runOpts := append(slices.Clone(opts), WithCheckpointing(replayStore), WithRunID(goldenRunID))
+	replayFinal, runErr := cg.Run(ctx, initial, runOpts...)
+
+	replay, err := loadRunHistory(replayStore, goldenRunID)
+	if err != nil {
+		return report, err
+	}
+	report.GoldenPath = historyPath(golden)
+	report.ReplayPath = historyPath(replay)
+	report.DivergedAt = divergence(report.GoldenPath, report.ReplayPath)
+	if runErr != nil {
+		return report, fmt.Errorf("replay: %w", runErr)
+	}
+
+	report.StateDiffs, err = diffStates(goldenFinal, replayFinal)
+	if err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// loadRunHistory loads all checkpoints of a run in sequence order.
+func loadRunHistory(store checkpoint.Store, runID string) ([]*checkpoint.Checkpoint, error) {
+	infos, err := store.List(runID)
+	if err != nil {
+		return nil, fmt.Errorf("list checkpoints: %w", err)
+	}
+
+	history := make([]*checkpoint.Checkpoint, 0, len(infos))
+	for _, info := range infos {
+		data, err := store.Load(runID, info.NodeID)
+		if err != nil {
+			if errors.Is(err, checkpoint.ErrNotFound) {
+				continue // Deleted since List
+			}
+			return nil, fmt.Errorf("load checkpoint: %w", err)
+		}
+		cp, err := checkpoint.Unmarshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDeserializeState, err)
+		}
+		if cp.Version != checkpoint.Version {
+			return nil, fmt.Errorf("%w: got %d, expected %d",
+				ErrCheckpointVersionMismatch, cp.Version, checkpoint.Version)
+		}
+		history = append(history, cp)
+	}
+	return history, nil
+}
+
+// historyPath returns the executed node IDs of a checkpoint history,
+// excluding the initial-state checkpoint.
+func historyPath(history []*checkpoint.Checkpoint) []string {
+	path := make([]string, 0, len(history))
+	for _, cp := range history {
+		if cp.NodeID != START {
+			path = append(path, cp.NodeID)
+		}
+	}
+	return path
+}
+
+// divergence returns the index of the first difference between a and b,
+// or -1 if they are equal.
+func divergence(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		if i >= len(a) || i >= len(b) || a[i] != b[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+// diffStates compares two states in their JSON form.
+func diffStates[S any](golden, replay S) ([]StateDiff, error) {
+	g, err := toJSONValue(golden)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSerializeState, err)
+	}
+	r, err := toJSONValue(replay)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSerializeState, err)
+	}
+
+	var diffs []StateDiff
+	diffJSONValues("", g, r, &diffs)
+	return diffs, nil
+}
+
+// toJSONValue converts v to its generic JSON representation.
+func toJSONValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// diffJSONValues appends the differences between generic JSON values to
+// diffs, descending into objects and arrays.
+func diffJSONValues(path string, golden, replay any, diffs *[]StateDiff) {
+	switch g := golden.(type) {
+	case map[string]any:
+		r, ok := replay.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(g)+len(r))
+		for k := range g {
+			keys = append(keys, k)
+		}
+		for k := range r {
+			if _, ok := g[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			child := k
+			if path != "" {
+				child = path + "." + k
+			}
+			diffJSONValues(child, g[k], r[k], diffs)
+		}
+		return
+
+	case []any:
+		r, ok := replay.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < len(g) || i < len(r); i++ {
+			var gv, rv any
+			if i < len(g) {
+				gv = g[i]
+			}
+			if i < len(r) {
+				rv = r[i]
+			}
+			diffJSONValues(fmt.Sprintf("%s[%d]", path, i), gv, rv, diffs)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(golden, replay) {
+		*diffs = append(*diffs, StateDiff{Path: path, Golden: golden, Replay: replay})
+	}
+}
@@ -0,0 +1,196 @@
+package flowgraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/checkpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reviewGraph builds classify -> (small | large) -> END, routing to large
+// when Count exceeds threshold. Each node records itself in Progress, and
+// the branches set Output with the given suffix.
+func reviewGraph(t *testing.T, threshold int, suffix string) *CompiledGraph[State] {
+	t.Helper()
+
+	branch := func(name string) NodeFunc[State] {
+		return func(ctx Context, s State) (State, error) {
+			s.Progress = append(s.Progress, name)
+			s.Output = name + suffix
+			return s, nil
+		}
+	}
+	compiled, err := NewGraph[State]().
+		AddNode("classify", func(ctx Context, s State) (State, error) {
+			s.Progress = append(s.Progress, "classify")
+			return s, nil
+		}).
+		AddNode("small", branch("small")).
+		AddNode("large", branch("large")).
+		AddConditionalEdge("classify", func(ctx Context, s State) string {
+			if s.Count > threshold {
+				return "large"
+			}
+			return "small"
+		}).
+		AddEdge("small", END).
+		AddEdge("large", END).
+		SetEntry("classify").
+		Compile()
+	require.NoError(t, err)
+	return compiled
+}
+
+// recordGolden runs the graph as a golden run with its initial state checkpointed.
+func recordGolden(t *testing.T, compiled *CompiledGraph[State], store checkpoint.Store, runID string) {
+	t.Helper()
+	_, err := compiled.Run(testCtx(), State{Count: 4},
+		WithCheckpointing(store),
+		WithRunID(runID),
+		WithInitialCheckpoint())
+	require.NoError(t, err)
+}
+
+// TestCompareRun_Unchanged tests that replaying an unchanged graph reports no diff.
+func TestCompareRun_Unchanged(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	compiled := reviewGraph(t, 5, "")
+	recordGolden(t, compiled, store, "golden")
+
+	report, err := compiled.CompareRun(testCtx(), "golden", store)
+
+	require.NoError(t, err)
+	assert.False(t, report.HasDiff(), report.String())
+	assert.Equal(t, -1, report.DivergedAt)
+	assert.Equal(t, []string{"classify", "small"}, report.GoldenPath)
+	assert.Equal(t, report.GoldenPath, report.ReplayPath)
+	assert.Empty(t, report.StateDiffs)
+}
+
+// TestCompareRun_RoutingChange tests that altered routing is reported as
+// a path divergence along with the resulting state changes.
+func TestCompareRun_RoutingChange(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	recordGolden(t, reviewGraph(t, 5, ""), store, "golden")
+
+	report, err := reviewGraph(t, 3, "").CompareRun(testCtx(), "golden", store)
+
+	require.NoError(t, err)
+	assert.True(t, report.HasDiff())
+	assert.Equal(t, 1, report.DivergedAt)
+	assert.Equal(t, []string{"classify", "small"}, report.GoldenPath)
+	assert.Equal(t, []string{"classify", "large"}, report.ReplayPath)
+	assert.Equal(t, []StateDiff{
+		{Path: "Output", Golden: "small", Replay: "large"},
+		{Path: "Progress[1]", Golden: "small", Replay: "large"},
+	}, report.StateDiffs)
+	assert.Contains(t, report.String(), "path diverged at step 2: golden small, replay large")
+}
+
+// TestCompareRun_NodeLogicChange tests that a change to node output with
+// the same path is reported as a state diff only.
+func TestCompareRun_NodeLogicChange(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	recordGolden(t, reviewGraph(t, 5, ""), store, "golden")
+
+	report, err := reviewGraph(t, 5, "-v2").CompareRun(testCtx(), "golden", store)
+
+	require.NoError(t, err)
+	assert.Equal(t, -1, report.DivergedAt)
+	assert.Equal(t, []StateDiff{{Path: "Output", Golden: "small", Replay: "small-v2"}}, report.StateDiffs)
+}
+
+// TestCompareRun_LeavesGoldenUntouched tests that the replay does not
+// write to the golden store.
+func TestCompareRun_LeavesGoldenUntouched(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	recordGolden(t, reviewGraph(t, 5, ""), store, "golden")
+	before, err := store.List("golden")
+	require.NoError(t, err)
+
+	_, err = reviewGraph(t, 3, "").CompareRun(testCtx(), "golden", store)
+	require.NoError(t, err)
+
+	after, err := store.List("golden")
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+// TestCompareRun_ReplayError tests that a failing replay returns its error
+// with the path so far.
+func TestCompareRun_ReplayError(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	recordGolden(t, reviewGraph(t, 5, ""), store, "golden")
+
+	nodeErr := errors.New("broken")
+	compiled, err := NewGraph[State]().
+		AddNode("classify", passthrough[State]).
+		AddNode("small", makeFailingNode(nodeErr)).
+		AddEdge("classify", "small").
+		AddEdge("small", END).
+		SetEntry("classify").
+		Compile()
+	require.NoError(t, err)
+
+	report, err := compiled.CompareRun(testCtx(), "golden", store)
+
+	assert.ErrorIs(t, err, nodeErr)
+	assert.Equal(t, []string{"classify"}, report.ReplayPath)
+	assert.Equal(t, 1, report.DivergedAt)
+}
+
+// TestCompareRun_RequiresInitialCheckpoint tests that runs recorded without
+// WithInitialCheckpoint cannot be replayed.
+func TestCompareRun_RequiresInitialCheckpoint(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	compiled := reviewGraph(t, 5, "")
+	_, err := compiled.Run(testCtx(), State{Count: 4}, WithCheckpointing(store), WithRunID("plain"))
+	require.NoError(t, err)
+
+	_, err = compiled.CompareRun(testCtx(), "plain", store)
+	assert.ErrorIs(t, err, ErrNoInitialCheckpoint)
+
+	_, err = compiled.CompareRun(testCtx(), "missing", store)
+	assert.ErrorIs(t, err, ErrNoCheckpoints)
+
+	_, err = compiled.CompareRun(nil, "plain", store)
+	assert.ErrorIs(t, err, ErrNilContext)
+}
+
+// TestWithInitialCheckpoint_ResumeAfterFirstNodeFails tests that the initial
+// checkpoint lets Resume restart a run whose first node failed.
+func TestWithInitialCheckpoint_ResumeAfterFirstNodeFails(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	fail := true
+	compiled, err := NewGraph[Counter]().
+		AddNode("first", func(ctx Context, s Counter) (Counter, error) {
+			if fail {
+				return s, errors.New("crash")
+			}
+			s.Value += 10
+			return s, nil
+		}).
+		AddEdge("first", END).
+		SetEntry("first").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), Counter{Value: 1},
+		WithCheckpointing(store), WithRunID("run-1"), WithInitialCheckpoint())
+	require.Error(t, err)
+
+	fail = false
+	result, err := compiled.Resume(testCtx(), store, "run-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, 11, result.Value)
+}
+
+// TestAddNode_ReservedStart tests that START cannot be used as a node ID.
+func TestAddNode_ReservedStart(t *testing.T) {
+	assert.Panics(t, func() {
+		NewGraph[Counter]().AddNode(START, increment)
+	})
+}
@@ -0,0 +1,199 @@
+package flowgraph
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// CompensationFunc undoes the effects of a node, such as deleting a
+// resource the node created. It receives the state the node returned.
+type CompensationFunc[S any] func(ctx Context, state S) error
+
+// AddCompensatingNode adds a node with a compensation that undoes it.
+// Returns the graph for method chaining.
+//
+// Compensations run only for runs started with WithGraphCompensation:
+// if the run fails, every completed compensating node is compensated in
+// reverse execution order. Without that option the node behaves as if it
+// were added with AddNode.
+//
+// Panics under the same conditions as AddNode, or if compensate is nil.
+//
+// Example:
+//
+//	graph.AddCompensatingNode("reserve", reserveInventory,
+//	    func(ctx flowgraph.Context, s Order) error {
+//	        return inventory.Release(ctx, s.ReservationID)
+//	    })
+func (g *Graph[S]) AddCompensatingNode(id string, fn NodeFunc[S], compensate CompensationFunc[S], opts ...NodeOption) *Graph[S] {
+	validateNodeID(id)
+
+	if fn == nil {
+		panic("flowgraph: node function cannot be nil")
+	}
+	if compensate == nil {
+		panic("flowgraph: compensation function cannot be nil")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.addNodeLocked(id, fn, opts)
+	g.compensations[id] = compensate
+	return g
+}
+
+// CompensationResult is the outcome of compensating one node execution.
+type CompensationResult struct {
+	// NodeID is the compensated node.
+	NodeID string
+	// Err is the compensation's error, or nil if it succeeded.
+	Err error
+}
+
+// CompensationError is returned by runs using WithGraphCompensation when
+// the run failed after at least one compensating node completed. It wraps
+// the original failure, so errors.Is and errors.As match it as well as any
+// compensation errors.
+type CompensationError struct {
+	// Err is the error that failed the run.
+	Err error
+	// Results lists each compensation in the order it ran, which is the
+	// reverse of execution order.
+	Results []CompensationResult
+}
+
+// Error implements the error interface.
+func (e *CompensationError) Error() string {
+	var ok, failed []string
+	for _, r := range e.Results {
+		if r.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", r.NodeID, r.Err))
+		} else {
+			ok = append(ok, r.NodeID)
+		}
+	}
+
+	msg := fmt.Sprintf("%v; compensated [%s]", e.Err, strings.Join(ok, ", "))
+	if len(failed) > 0 {
+		msg += fmt.Sprintf("; compensation failed [%s]", strings.Join(failed, "; "))
+	}
+	return msg
+}
+
+// Unwrap returns the original error followed by any compensation errors.
+func (e *CompensationError) Unwrap() []error {
+	errs := []error{e.Err}
+	for _, r := range e.Results {
+		if r.Err != nil {
+			errs = append(errs, r.Err)
+		}
+	}
+	return errs
+}
+
+// Failed reports whether any compensation returned an error.
+func (e *CompensationError) Failed() bool {
+	for _, r := range e.Results {
+		if r.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// compensationLog records completed compensating nodes in execution order.
+// It is shared by fork/join branches, so methods are safe for concurrent use.
+type compensationLog struct {
+	mu      sync.Mutex
+	entries []compensationEntry
+}
+
+// compensationEntry is a completed node's compensation, bound to the state
+// the node returned.
+type compensationEntry struct {
+	nodeID     string
+	compensate func(ctx Context) error
+}
+
+// add records a completed node.
+func (l *compensationLog) add(nodeID string, compensate func(ctx Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, compensationEntry{nodeID: nodeID, compensate: compensate})
+}
+
+// recordCompleted logs nodeID's compensation if graph compensation is
+// enabled and the node has one.
+func (cg *CompiledGraph[S]) recordCompleted(cfg *runConfig, nodeID string, state S) {
+	if cfg.compensationLog == nil {
+		return
+	}
+	fn, ok := cg.compensations[nodeID]
+	if !ok {
+		return
+	}
+	cfg.compensationLog.add(nodeID, func(ctx Context) error { return fn(ctx, state) })
+}
+
+// compensate runs logged compensations in reverse execution order after
+// runErr failed the run. Each runs even if an earlier one failed. Returns
+// runErr unchanged if nothing was compensated, or if the run was
+// suspended or interrupted, since such a run is resumed rather than rolled
+// back.
+func compensate(ctx Context, log *compensationLog, runErr error) error {
+	if _, suspended := runErr.(*SuspendedError); suspended {
+		return runErr
+	}
+	if _, interrupted := runErr.(*InterruptError); interrupted {
+		return runErr
+	}
+
+	log.mu.Lock()
+	entries := log.entries
+	log.entries = nil
+	log.mu.Unlock()
+
+	if len(entries) == 0 {
+		return runErr
+	}
+
+	// Compensate even if the run was cancelled
+	if ec, ok := ctx.(*executionContext); ok {
+		ctx = ec.withContext(context.WithoutCancel(ec.Context))
+	}
+
+	results := make([]CompensationResult, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		err := runCompensation(ctx, entry)
+		if err != nil {
+			ctx.Logger().Warn("node compensation failed", "node_id", entry.nodeID, "error", err)
+		}
+		results = append(results, CompensationResult{NodeID: entry.nodeID, Err: err})
+	}
+	return &CompensationError{Err: runErr, Results: results}
+}
+
+// runCompensation runs one compensation with panic recovery.
+func runCompensation(ctx Context, entry compensationEntry) (err error) {
+	nodeCtx := ctx
+	if ec, ok := ctx.(*executionContext); ok {
+		nodeCtx = ec.withNodeID(entry.nodeID)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{
+				NodeID: entry.nodeID,
+				Value:  r,
+				Stack:  string(debug.Stack()),
+			}
+		}
+	}()
+
+	return entry.compensate(nodeCtx)
+}
@@ -0,0 +1,258 @@
+package flowgraph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compensationRecorder records compensations in the order they run.
+type compensationRecorder struct {
+	mu     sync.Mutex
+	undone []string
+	states map[string]State
+}
+
+func newCompensationRecorder() *compensationRecorder {
+	return &compensationRecorder{states: make(map[string]State)}
+}
+
+// undo returns a compensation that records name and the state it received.
+func (r *compensationRecorder) undo(name string, err error) CompensationFunc[State] {
+	return func(ctx Context, s State) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.undone = append(r.undone, name)
+		r.states[name] = s
+		return err
+	}
+}
+
+// sagaGraph builds reserve -> charge -> audit -> ship -> END, where every
+// node but audit is compensating and ship fails with shipErr.
+func sagaGraph(t *testing.T, rec *compensationRecorder, shipErr, chargeUndoErr error) *CompiledGraph[State] {
+	t.Helper()
+	var ran []string
+	compiled, err := NewGraph[State]().
+		AddCompensatingNode("reserve", makeTrackingNode("reserve", &ran), rec.undo("reserve", nil)).
+		AddCompensatingNode("charge", makeTrackingNode("charge", &ran), rec.undo("charge", chargeUndoErr)).
+		AddNode("audit", makeTrackingNode("audit", &ran)).
+		AddCompensatingNode("ship", makeFailingNode(shipErr), rec.undo("ship", nil)).
+		AddEdge("reserve", "charge").
+		AddEdge("charge", "audit").
+		AddEdge("audit", "ship").
+		AddEdge("ship", END).
+		SetEntry("reserve").
+		Compile()
+	require.NoError(t, err)
+	return compiled
+}
+
+// TestGraphCompensation_ReverseOrder tests that completed compensating nodes roll back in reverse order.
+func TestGraphCompensation_ReverseOrder(t *testing.T) {
+	errShip := errors.New("carrier unavailable")
+	rec := newCompensationRecorder()
+	compiled := sagaGraph(t, rec, errShip, nil)
+
+	_, err := compiled.Run(testCtx(), State{}, WithGraphCompensation())
+
+	assert.Equal(t, []string{"charge", "reserve"}, rec.undone, "failed node is not compensated")
+	assert.Equal(t, []string{"reserve"}, rec.states["reserve"].Progress, "compensation gets the node's output")
+	assert.Equal(t, []string{"reserve", "charge"}, rec.states["charge"].Progress)
+
+	var compErr *CompensationError
+	require.ErrorAs(t, err, &compErr)
+	assert.False(t, compErr.Failed())
+	assert.Equal(t, []CompensationResult{{NodeID: "charge"}, {NodeID: "reserve"}}, compErr.Results)
+
+	var nodeErr *NodeError
+	require.ErrorAs(t, err, &nodeErr)
+	assert.Equal(t, "ship", nodeErr.NodeID)
+	assert.ErrorIs(t, err, errShip)
+	assert.Contains(t, err.Error(), "carrier unavailable")
+	assert.Contains(t, err.Error(), "compensated [charge, reserve]")
+}
+
+// TestGraphCompensation_FailedCompensation tests that a failing compensation is reported and the rest still run.
+func TestGraphCompensation_FailedCompensation(t *testing.T) {
+	errShip := errors.New("carrier unavailable")
+	errRefund := errors.New("refund rejected")
+	rec := newCompensationRecorder()
+	compiled := sagaGraph(t, rec, errShip, errRefund)
+
+	_, err := compiled.Run(testCtx(), State{}, WithGraphCompensation())
+
+	assert.Equal(t, []string{"charge", "reserve"}, rec.undone)
+
+	var compErr *CompensationError
+	require.ErrorAs(t, err, &compErr)
+	assert.True(t, compErr.Failed())
+	require.Len(t, compErr.Results, 2)
+	assert.ErrorIs(t, compErr.Results[0].Err, errRefund)
+	assert.NoError(t, compErr.Results[1].Err)
+
+	assert.ErrorIs(t, err, errShip)
+	assert.ErrorIs(t, err, errRefund)
+	assert.Contains(t, err.Error(), "compensated [reserve]")
+	assert.Contains(t, err.Error(), "compensation failed [charge: refund rejected]")
+}
+
+// TestGraphCompensation_Disabled tests that compensations do not run without the option.
+func TestGraphCompensation_Disabled(t *testing.T) {
+	errShip := errors.New("carrier unavailable")
+	rec := newCompensationRecorder()
+	compiled := sagaGraph(t, rec, errShip, nil)
+
+	_, err := compiled.Run(testCtx(), State{})
+
+	assert.Empty(t, rec.undone)
+	var compErr *CompensationError
+	assert.False(t, errors.As(err, &compErr))
+	assert.ErrorIs(t, err, errShip)
+}
+
+// TestGraphCompensation_Success tests that nothing is compensated when the run succeeds.
+func TestGraphCompensation_Success(t *testing.T) {
+	rec := newCompensationRecorder()
+	var ran []string
+	compiled, err := NewGraph[State]().
+		AddCompensatingNode("a", makeTrackingNode("a", &ran), rec.undo("a", nil)).
+		AddEdge("a", END).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{}, WithGraphCompensation())
+	require.NoError(t, err)
+	assert.Empty(t, rec.undone)
+}
+
+// TestGraphCompensation_NothingToCompensate tests that the original error is returned unwrapped.
+func TestGraphCompensation_NothingToCompensate(t *testing.T) {
+	errFail := errors.New("boom")
+	compiled, err := NewGraph[State]().
+		AddNode("a", makeFailingNode(errFail)).
+		AddEdge("a", END).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{}, WithGraphCompensation())
+	var nodeErr *NodeError
+	require.True(t, errors.As(err, &nodeErr))
+	_, wrapped := err.(*CompensationError)
+	assert.False(t, wrapped)
+}
+
+// TestGraphCompensation_Loop tests that each execution of a looping node is compensated.
+func TestGraphCompensation_Loop(t *testing.T) {
+	rec := newCompensationRecorder()
+	errFail := errors.New("boom")
+	compiled, err := NewGraph[State]().
+		AddCompensatingNode("step", func(ctx Context, s State) (State, error) {
+			s.Count++
+			return s, nil
+		}, func(ctx Context, s State) error {
+			rec.mu.Lock()
+			defer rec.mu.Unlock()
+			rec.undone = append(rec.undone, strconv.Itoa(s.Count))
+			return nil
+		}).
+		AddNode("fail", makeFailingNode(errFail)).
+		AddConditionalEdge("step", func(ctx Context, s State) string {
+			if s.Count < 3 {
+				return "step"
+			}
+			return "fail"
+		}).
+		AddEdge("fail", END).
+		SetEntry("step").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{}, WithGraphCompensation())
+	require.ErrorIs(t, err, errFail)
+	assert.Equal(t, []string{"3", "2", "1"}, rec.undone)
+}
+
+// TestGraphCompensation_ForkJoin tests that nodes in fork/join branches are compensated.
+func TestGraphCompensation_ForkJoin(t *testing.T) {
+	var mu sync.Mutex
+	var undone []string
+	undo := func(name string) CompensationFunc[TestState] {
+		return func(ctx Context, s TestState) error {
+			mu.Lock()
+			defer mu.Unlock()
+			undone = append(undone, name)
+			return nil
+		}
+	}
+	errCollect := errors.New("collect failed")
+
+	compiled, err := NewGraph[TestState]().
+		AddCompensatingNode("dispatch", passthrough[TestState], undo("dispatch")).
+		AddCompensatingNode("workerA", passthrough[TestState], undo("workerA")).
+		AddCompensatingNode("workerB", passthrough[TestState], undo("workerB")).
+		AddNode("collect", func(ctx Context, s TestState) (TestState, error) {
+			return s, errCollect
+		}).
+		AddEdge("dispatch", "workerA").
+		AddEdge("dispatch", "workerB").
+		AddEdge("workerA", "collect").
+		AddEdge("workerB", "collect").
+		AddEdge("collect", END).
+		SetEntry("dispatch").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), TestState{Values: map[string]int{}}, WithGraphCompensation())
+	require.ErrorIs(t, err, errCollect)
+
+	require.Len(t, undone, 3)
+	assert.ElementsMatch(t, []string{"workerA", "workerB"}, undone[:2])
+	assert.Equal(t, "dispatch", undone[2], "fork node ran first, so it is compensated last")
+}
+
+// TestGraphCompensation_Cancelled tests that compensations run with an uncancelled context.
+func TestGraphCompensation_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var compCtxErr error
+	compiled, err := NewGraph[Counter]().
+		AddCompensatingNode("a", func(ctx Context, s Counter) (Counter, error) {
+			cancel()
+			return s, nil
+		}, func(ctx Context, s Counter) error {
+			compCtxErr = ctx.Err()
+			return nil
+		}).
+		AddNode("b", increment).
+		AddEdge("a", "b").
+		AddEdge("b", END).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(NewContext(ctx), Counter{}, WithGraphCompensation())
+
+	var cancelErr *CancellationError
+	require.ErrorAs(t, err, &cancelErr)
+	var compErr *CompensationError
+	require.ErrorAs(t, err, &compErr)
+	assert.Equal(t, []CompensationResult{{NodeID: "a"}}, compErr.Results)
+	assert.NoError(t, compCtxErr)
+}
+
+// TestAddCompensatingNode_Panics tests validation of AddCompensatingNode arguments.
+func TestAddCompensatingNode_Panics(t *testing.T) {
+	assert.Panics(t, func() {
+		NewGraph[Counter]().AddCompensatingNode("a", increment, nil)
+	})
+	assert.Panics(t, func() {
+		NewGraph[Counter]().AddCompensatingNode("a", nil, func(Context, Counter) error { return nil })
+	})
+}
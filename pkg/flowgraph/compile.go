@@ -4,17 +4,25 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 )
 
 // Compile validates the graph and creates an executable CompiledGraph.
 // Returns an error if validation fails. Multiple errors are joined together.
 //
 // Validation checks (in order):
-//  1. Entry point must be set
+//  1. Exactly one of entry point or entry selector must be set
 //  2. Entry point must reference an existing node
 //  3. All edge sources must reference existing nodes
 //  4. All edge targets must reference existing nodes or END
-//  5. All nodes must have a path to END
+//  5. Dynamic fan-outs have exactly one edge, naming their join node
+//  6. Error edges connect existing nodes, or a node and END
+//  7. All nodes must have a path to END
+//
+// With an entry selector, the entry node is only known at runtime and any
+// node may be selected, so check 7 requires every node to have a path to
+// END.
 //
 // Unreachable nodes (not reachable from entry) are logged as warnings
 // but do not cause compilation to fail.
@@ -24,10 +32,16 @@ func (g *Graph[S]) Compile() (*CompiledGraph[S], error) {
 
 	var errs []error
 
-	// 1. Validate entry point is set
-	if g.entryPoint == "" {
+	// 1. Validate entry point is set (exactly one of entry point or selector)
+	_, entryExists := g.nodes[g.entryPoint]
+	switch {
+	case g.entryPoint != "" && g.entrySelector != nil:
+		errs = append(errs, ErrEntryConflict)
+	case g.entrySelector != nil:
+		// Entry is chosen at runtime from the initial state
+	case g.entryPoint == "":
 		errs = append(errs, ErrNoEntryPoint)
-	} else if _, exists := g.nodes[g.entryPoint]; !exists {
+	case !entryExists:
 		// 2. Validate entry point references existing node
 		errs = append(errs, fmt.Errorf("%w: %s", ErrEntryNotFound, g.entryPoint))
 	}
@@ -60,13 +74,46 @@ func (g *Graph[S]) Compile() (*CompiledGraph[S], error) {
 		}
 	}
 
-	// 5. Validate path to END exists from entry
+	// 5. Validate dynamic fan-outs
+	for from := range g.fanOuts {
+		if err := g.validateFanOut(from); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// 6. Validate error edges
+	for from, to := range g.errorEdges {
+		if _, exists := g.nodes[from]; !exists {
+			errs = append(errs, fmt.Errorf("%w: error edge source '%s' does not exist", ErrNodeNotFound, from))
+		}
+		if to != END {
+			if _, exists := g.nodes[to]; !exists {
+				errs = append(errs, fmt.Errorf("%w: error edge target '%s' does not exist", ErrNodeNotFound, to))
+			}
+		}
+	}
+
+	// 7. Validate path to END exists from entry
 	if g.entryPoint != "" {
 		if _, exists := g.nodes[g.entryPoint]; exists {
 			if !g.hasPathToEnd() {
 				errs = append(errs, ErrNoPathToEnd)
 			}
 		}
+	} else if g.entrySelector != nil {
+		// Any node may be selected, so every node must reach END
+		canReachEnd := g.nodesWithPathToEnd()
+		var stuck []string
+		for id := range g.nodes {
+			if !canReachEnd[id] {
+				stuck = append(stuck, id)
+			}
+		}
+		if len(stuck) > 0 {
+			slices.Sort(stuck)
+			errs = append(errs, fmt.Errorf("%w: entry selector may select %s",
+				ErrNoPathToEnd, strings.Join(stuck, ", ")))
+		}
 	}
 
 	// Check for unreachable nodes (warning only)
@@ -79,11 +126,32 @@ func (g *Graph[S]) Compile() (*CompiledGraph[S], error) {
 	return g.buildCompiledGraph(), nil
 }
 
+// validateFanOut checks that a dynamic fan-out has a source node and a
+// single static edge naming its join node.
+func (g *Graph[S]) validateFanOut(from string) error {
+	if _, exists := g.nodes[from]; !exists {
+		return fmt.Errorf("%w: source '%s' does not exist", ErrInvalidFanOut, from)
+	}
+	if _, hasConditional := g.conditionalEdges[from]; hasConditional {
+		return fmt.Errorf("%w: node '%s' also has a conditional edge", ErrInvalidFanOut, from)
+	}
+	if len(g.edges[from]) != 1 {
+		return fmt.Errorf("%w: node '%s' needs exactly one static edge, AddEdge(%q, join), naming its join node; has %d",
+			ErrInvalidFanOut, from, from, len(g.edges[from]))
+	}
+	return nil
+}
+
 // hasPathToEnd checks if there's a path from entry to END.
 // This uses a simple reachability analysis.
 // Nodes with conditional edges are assumed to potentially reach any of their
 // possible targets, including END.
 func (g *Graph[S]) hasPathToEnd() bool {
+	return g.nodesWithPathToEnd()[g.entryPoint]
+}
+
+// nodesWithPathToEnd returns the set of nodes that can reach END.
+func (g *Graph[S]) nodesWithPathToEnd() map[string]bool {
 	// Find all nodes that can reach END using reverse traversal
 	canReachEnd := make(map[string]bool)
 	canReachEnd[END] = true
@@ -118,7 +186,8 @@ func (g *Graph[S]) hasPathToEnd() bool {
 		}
 	}
 
-	return canReachEnd[g.entryPoint]
+	delete(canReachEnd, END)
+	return canReachEnd
 }
 
 // warnUnreachableNodes logs warnings for nodes not reachable from entry.
@@ -152,8 +221,12 @@ func (g *Graph[S]) findReachableNodes() map[string]bool {
 		current := queue[0]
 		queue = queue[1:]
 
-		// Follow simple edges
-		for _, target := range g.edges[current] {
+		// Follow simple edges and the error edge
+		targets := g.edges[current]
+		if to, ok := g.errorEdges[current]; ok {
+			targets = append(targets[:len(targets):len(targets)], to)
+		}
+		for _, target := range targets {
 			if target != END && !reachable[target] {
 				reachable[target] = true
 				queue = append(queue, target)
@@ -163,7 +236,10 @@ func (g *Graph[S]) findReachableNodes() map[string]bool {
 		// For conditional edges, we can't know the actual targets at compile time
 		// since they depend on runtime state. The router function could potentially
 		// return any node ID, so we must assume ALL nodes are reachable.
-		if _, hasConditional := g.conditionalEdges[current]; hasConditional {
+		// The same applies to dynamic fan-outs.
+		_, hasConditional := g.conditionalEdges[current]
+		_, hasFanOut := g.fanOuts[current]
+		if hasConditional || hasFanOut {
 			for nodeID := range g.nodes {
 				if !reachable[nodeID] {
 					reachable[nodeID] = true
@@ -184,6 +260,24 @@ func (g *Graph[S]) buildCompiledGraph() *CompiledGraph[S] {
 		nodes[id] = fn
 	}
 
+	// Copy per-node configuration
+	nodeConfigs := make(map[string]nodeConfig, len(g.nodeConfigs))
+	for id, cfg := range g.nodeConfigs {
+		nodeConfigs[id] = cfg
+	}
+
+	// Copy dynamic node bindings
+	dynamicNodes := make(map[string]string, len(g.dynamicNodes))
+	for id, key := range g.dynamicNodes {
+		dynamicNodes[id] = key
+	}
+
+	// Copy subgraph bindings
+	subgraphs := make(map[string]*CompiledGraph[S], len(g.subgraphs))
+	for id, sub := range g.subgraphs {
+		subgraphs[id] = sub
+	}
+
 	// Deep copy edges
 	edges := make(map[string][]string, len(g.edges))
 	for from, targets := range g.edges {
@@ -197,6 +291,24 @@ func (g *Graph[S]) buildCompiledGraph() *CompiledGraph[S] {
 		conditionalEdges[from] = router
 	}
 
+	// Copy dynamic fan-outs
+	fanOuts := make(map[string]FanOutFunc[S], len(g.fanOuts))
+	for from, fn := range g.fanOuts {
+		fanOuts[from] = fn
+	}
+
+	// Copy compensations
+	compensations := make(map[string]CompensationFunc[S], len(g.compensations))
+	for id, fn := range g.compensations {
+		compensations[id] = fn
+	}
+
+	// Copy error edges
+	errorEdges := make(map[string]string, len(g.errorEdges))
+	for from, to := range g.errorEdges {
+		errorEdges[from] = to
+	}
+
 	// Pre-compute successors
 	successors := make(map[string][]string)
 	for from, targets := range edges {
@@ -224,9 +336,17 @@ func (g *Graph[S]) buildCompiledGraph() *CompiledGraph[S] {
 
 	return &CompiledGraph[S]{
 		nodes:            nodes,
+		nodeConfigs:      nodeConfigs,
+		dynamicNodes:     dynamicNodes,
+		subgraphs:        subgraphs,
 		edges:            edges,
 		conditionalEdges: conditionalEdges,
+		fanOuts:          fanOuts,
+		compensations:    compensations,
+		errorEdges:       errorEdges,
 		entryPoint:       g.entryPoint,
+		entrySelector:    g.entrySelector,
+		middleware:       slices.Clone(g.middleware),
 		successors:       successors,
 		predecessors:     predecessors,
 		isConditional:    isConditional,
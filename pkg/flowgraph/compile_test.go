@@ -136,6 +136,65 @@ func TestCompile_EntryNotFound_Error(t *testing.T) {
 	assert.Contains(t, err.Error(), "nonexistent")
 }
 
+// TestCompile_EntrySelector tests compilation with an entry selector instead of an entry point.
+func TestCompile_EntrySelector(t *testing.T) {
+	graph := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddNode("b", increment).
+		AddEdge("a", "b").
+		AddEdge("b", END).
+		SetEntrySelector(func(s Counter) string { return "b" })
+
+	compiled, err := graph.Compile()
+
+	require.NoError(t, err)
+	assert.True(t, compiled.HasEntrySelector())
+	assert.Empty(t, compiled.EntryPoint())
+}
+
+// TestCompile_EntryAndSelector_Error tests that SetEntry and SetEntrySelector are mutually exclusive.
+func TestCompile_EntryAndSelector_Error(t *testing.T) {
+	graph := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddEdge("a", END).
+		SetEntry("a").
+		SetEntrySelector(func(s Counter) string { return "a" })
+
+	_, err := graph.Compile()
+
+	assert.ErrorIs(t, err, ErrEntryConflict)
+}
+
+// TestCompile_EntrySelector_NoPathToEnd_Error tests selector graphs with no node reaching END.
+func TestCompile_EntrySelector_NoPathToEnd_Error(t *testing.T) {
+	graph := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddNode("b", increment).
+		AddEdge("a", "b").
+		SetEntrySelector(func(s Counter) string { return "a" })
+
+	_, err := graph.Compile()
+
+	assert.ErrorIs(t, err, ErrNoPathToEnd)
+}
+
+// TestCompile_EntrySelector_DeadEndNode_Error tests that a selector graph
+// fails to compile if any node, not only some, cannot reach END.
+func TestCompile_EntrySelector_DeadEndNode_Error(t *testing.T) {
+	graph := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddNode("b", increment).
+		AddNode("stuck", increment).
+		AddEdge("a", "b").
+		AddEdge("b", END).
+		SetEntrySelector(func(s Counter) string { return "a" })
+
+	_, err := graph.Compile()
+
+	assert.ErrorIs(t, err, ErrNoPathToEnd)
+	assert.Contains(t, err.Error(), "stuck")
+}
+
 // TestCompile_MissingEdgeTarget_Error tests edge to missing node.
 func TestCompile_MissingEdgeTarget_Error(t *testing.T) {
 	graph := NewGraph[Counter]().
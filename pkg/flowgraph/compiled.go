@@ -10,9 +10,17 @@ package flowgraph
 // the graph structure for debugging or visualization.
 type CompiledGraph[S any] struct {
 	nodes            map[string]NodeFunc[S]
+	nodeConfigs      map[string]nodeConfig
+	dynamicNodes     map[string]string
+	subgraphs        map[string]*CompiledGraph[S]
 	edges            map[string][]string
 	conditionalEdges map[string]RouterFunc[S]
+	fanOuts          map[string]FanOutFunc[S]
+	compensations    map[string]CompensationFunc[S]
+	errorEdges       map[string]string
 	entryPoint       string
+	entrySelector    EntrySelectorFunc[S]
+	middleware       []NodeMiddleware[S]
 
 	// Pre-computed for efficient lookup
 	successors    map[string][]string
@@ -27,10 +35,17 @@ type CompiledGraph[S any] struct {
 }
 
 // EntryPoint returns the entry node ID.
+// Returns an empty string if the graph uses an entry selector.
 func (cg *CompiledGraph[S]) EntryPoint() string {
 	return cg.entryPoint
 }
 
+// HasEntrySelector returns true if the entry node is chosen at runtime
+// by an entry selector rather than fixed at compile time.
+func (cg *CompiledGraph[S]) HasEntrySelector() bool {
+	return cg.entrySelector != nil
+}
+
 // NodeIDs returns all node identifiers in the graph.
 // The order is not guaranteed.
 func (cg *CompiledGraph[S]) NodeIDs() []string {
@@ -76,6 +91,12 @@ func (cg *CompiledGraph[S]) getNode(id string) (NodeFunc[S], bool) {
 	return fn, exists
 }
 
+// getNodeConfig returns the per-node configuration for the given ID.
+// Used internally by the executor.
+func (cg *CompiledGraph[S]) getNodeConfig(id string) nodeConfig {
+	return cg.nodeConfigs[id]
+}
+
 // getRouter returns the router function for the given node.
 // Used internally by the executor.
 func (cg *CompiledGraph[S]) getRouter(id string) (RouterFunc[S], bool) {
@@ -0,0 +1,161 @@
+package flowgraph
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/checkpoint"
+)
+
+// Compression selects how checkpoint state is encoded before storage.
+type Compression int
+
+const (
+	// CompressionNone stores the JSON-encoded state as-is.
+	CompressionNone Compression = iota
+
+	// CompressionGzip gzip-compresses the JSON-encoded state.
+	CompressionGzip
+)
+
+// String returns the name of the compression.
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionGzip:
+		return "gzip"
+	default:
+		return fmt.Sprintf("Compression(%d)", int(c))
+	}
+}
+
+// Headers for encoded checkpoint state.
+//
+// Checkpoint state must be valid JSON, so encoded state is stored as a
+// JSON string: a header followed by base64 data and a closing quote. Plain
+// JSON state never starts with an escaped NUL, so uncompressed, unencrypted
+// checkpoints are unambiguous. State from a codec other than JSON is
+// wrapped with binaryStateMagic when it is not compressed.
+var (
+	gzipStateMagic      = []byte(`"\u0000FGZ:`)
+	encryptedStateMagic = []byte(`"\u0000FGE:`)
+	binaryStateMagic    = []byte(`"\u0000FGB:`)
+)
+
+// wrapState encodes payload as a JSON string with the given header.
+func wrapState(magic, payload []byte) []byte {
+	out := make([]byte, 0, len(magic)+base64.StdEncoding.EncodedLen(len(payload))+1)
+	out = append(out, magic...)
+	out = base64.StdEncoding.AppendEncode(out, payload)
+	return append(out, '"')
+}
+
+// unwrapState decodes state produced by wrapState.
+// Returns ok=false if data does not start with magic.
+func unwrapState(magic, data []byte) (payload []byte, ok bool, err error) {
+	if !bytes.HasPrefix(data, magic) {
+		return nil, false, nil
+	}
+	encoded, terminated := bytes.CutSuffix(data[len(magic):], []byte{'"'})
+	if !terminated {
+		return nil, true, errors.New("unterminated encoded state")
+	}
+	payload, err = base64.StdEncoding.AppendDecode(nil, encoded)
+	return payload, true, err
+}
+
+// compressState encodes serialized state for storage using c.
+func compressState(c Compression, data []byte) ([]byte, error) {
+	switch c {
+	case CompressionNone:
+		return data, nil
+	case CompressionGzip:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return wrapState(gzipStateMagic, buf.Bytes()), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression: %v", c)
+	}
+}
+
+// decompressState detects the encoding of stored state and returns the
+// serialized bytes along with the compression that was used.
+// State without a recognized header is returned unchanged.
+func decompressState(data []byte) ([]byte, Compression, error) {
+	compressed, ok, err := unwrapState(gzipStateMagic, data)
+	if !ok {
+		raw, binary, err := unwrapState(binaryStateMagic, data)
+		if !binary {
+			return data, CompressionNone, nil
+		}
+		return raw, CompressionNone, err
+	}
+	if err != nil {
+		return nil, CompressionGzip, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, CompressionGzip, err
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, CompressionGzip, err
+	}
+	return out, CompressionGzip, nil
+}
+
+// encodeCheckpointState compresses, then encrypts, state serialized with
+// codec according to cfg. The returned op names the failing step on error.
+func encodeCheckpointState(cfg *runConfig, codec Codec, data []byte) ([]byte, string, error) {
+	data, err := compressState(cfg.checkpointCompression, data)
+	if err != nil {
+		return nil, "compress", err
+	}
+	if cfg.checkpointCompression == CompressionNone && codec.Name() != (JSONCodec{}).Name() {
+		data = wrapState(binaryStateMagic, data)
+	}
+	if cfg.checkpointCipher != nil {
+		data, err = encryptState(cfg.checkpointCipher, data)
+		if err != nil {
+			return nil, "encrypt", err
+		}
+	}
+	return data, "", nil
+}
+
+// decodeCheckpointState decrypts, decompresses and unmarshals the state of
+// cp into state, returning the compression and codec the checkpoint was
+// written with. configured is the run's WithCheckpointCodec, consulted for
+// checkpoints written with a custom codec. Decryption failures wrap
+// ErrCheckpointDecryption; other failures wrap ErrDeserializeState.
+func decodeCheckpointState[S any](cp *checkpoint.Checkpoint, configured Codec, aead cipher.AEAD, state *S) (Compression, Codec, error) {
+	codec, err := resolveCodec(cp.Codec, configured)
+	if err != nil {
+		return CompressionNone, nil, err
+	}
+	data, err := decryptState(aead, cp.State)
+	if err != nil {
+		return CompressionNone, codec, fmt.Errorf("%w: %w", ErrCheckpointDecryption, err)
+	}
+	raw, c, err := decompressState(data)
+	if err != nil {
+		return c, codec, fmt.Errorf("%w: decompress: %w", ErrDeserializeState, err)
+	}
+	if err := codec.Unmarshal(raw, state); err != nil {
+		return c, codec, fmt.Errorf("%w: %w", ErrDeserializeState, err)
+	}
+	return c, codec, nil
+}
@@ -0,0 +1,47 @@
+package flowgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompression_String tests Compression names.
+func TestCompression_String(t *testing.T) {
+	assert.Equal(t, "none", CompressionNone.String())
+	assert.Equal(t, "gzip", CompressionGzip.String())
+	assert.Equal(t, "Compression(9)", Compression(9).String())
+}
+
+// TestCompressState_RoundTrip tests that compressed state decompresses to the original.
+func TestCompressState_RoundTrip(t *testing.T) {
+	original := []byte(`{"value":42,"messages":["hello"]}`)
+
+	compressed, err := compressState(CompressionGzip, original)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(compressed, gzipStateMagic))
+
+	decoded, c, err := decompressState(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, CompressionGzip, c)
+	assert.Equal(t, original, decoded)
+
+	plain, c, err := decompressState(original)
+	require.NoError(t, err)
+	assert.Equal(t, CompressionNone, c)
+	assert.Equal(t, original, plain)
+
+	_, _, err = decompressState(append(append([]byte{}, gzipStateMagic...), "not-base64!\""...))
+	assert.Error(t, err)
+}
+
+// TestCompressState_ValidJSON tests that compressed state is still valid JSON,
+// as required by checkpoint.Checkpoint.State.
+func TestCompressState_ValidJSON(t *testing.T) {
+	compressed, err := compressState(CompressionGzip, []byte(`{"a":1}`))
+	require.NoError(t, err)
+	assert.True(t, json.Valid(compressed))
+}
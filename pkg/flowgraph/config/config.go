@@ -7,6 +7,10 @@ import (
 // Config wraps a map[string]any for type-safe value extraction.
 // All accessor methods return default values if the key is missing
 // or the value cannot be converted to the requested type.
+//
+// Keys may use dot notation to reach into nested maps, e.g.
+// "database.host". A key that exists literally, dots included, takes
+// precedence over the nested path.
 type Config struct {
 	data map[string]any
 }
@@ -22,11 +26,7 @@ func New(data map[string]any) Config {
 
 // String returns the string value for key, or defaultVal if missing or not a string.
 func (c Config) String(key, defaultVal string) string {
-	v, ok := c.data[key]
-	if !ok {
-		return defaultVal
-	}
-	if s, ok := v.(string); ok {
+	if s, ok := toString(c.get(key)); ok {
 		return s
 	}
 	return defaultVal
@@ -41,34 +41,15 @@ func (c Config) String(key, defaultVal string) string {
 //   - float64: interpreted as seconds
 //   - time.Duration: used directly
 func (c Config) Duration(key string, defaultVal time.Duration) time.Duration {
-	v, ok := c.data[key]
-	if !ok {
-		return defaultVal
-	}
-	switch val := v.(type) {
-	case string:
-		if d, err := time.ParseDuration(val); err == nil {
-			return d
-		}
-	case float64:
-		return time.Duration(val * float64(time.Second))
-	case int:
-		return time.Duration(val) * time.Second
-	case int64:
-		return time.Duration(val) * time.Second
-	case time.Duration:
-		return val
+	if d, ok := toDuration(c.get(key)); ok {
+		return d
 	}
 	return defaultVal
 }
 
 // Bool returns the boolean value for key, or defaultVal if missing or not a bool.
 func (c Config) Bool(key string, defaultVal bool) bool {
-	v, ok := c.data[key]
-	if !ok {
-		return defaultVal
-	}
-	if b, ok := v.(bool); ok {
+	if b, ok := toBool(c.get(key)); ok {
 		return b
 	}
 	return defaultVal
@@ -81,20 +62,8 @@ func (c Config) Bool(key string, defaultVal bool) bool {
 //   - int64: converted to int
 //   - float64: converted to int (truncated, only if no fractional part)
 func (c Config) Int(key string, defaultVal int) int {
-	v, ok := c.data[key]
-	if !ok {
-		return defaultVal
-	}
-	switch val := v.(type) {
-	case int:
-		return val
-	case int64:
-		return int(val)
-	case float64:
-		// Only convert if there's no fractional part
-		if val == float64(int(val)) {
-			return int(val)
-		}
+	if i, ok := toInt(c.get(key)); ok {
+		return i
 	}
 	return defaultVal
 }
@@ -106,17 +75,8 @@ func (c Config) Int(key string, defaultVal int) int {
 //   - int: converted to float64
 //   - int64: converted to float64
 func (c Config) Float(key string, defaultVal float64) float64 {
-	v, ok := c.data[key]
-	if !ok {
-		return defaultVal
-	}
-	switch val := v.(type) {
-	case float64:
-		return val
-	case int:
-		return float64(val)
-	case int64:
-		return float64(val)
+	if f, ok := toFloat(c.get(key)); ok {
+		return f
 	}
 	return defaultVal
 }
@@ -127,45 +87,151 @@ func (c Config) Float(key string, defaultVal float64) float64 {
 //   - []string: used directly
 //   - []any: each element converted to string if possible
 func (c Config) StringSlice(key string, defaultVal []string) []string {
-	v, ok := c.data[key]
-	if !ok {
-		return defaultVal
-	}
-	switch val := v.(type) {
-	case []string:
-		return val
-	case []any:
-		result := make([]string, 0, len(val))
-		for _, item := range val {
-			if s, ok := item.(string); ok {
-				result = append(result, s)
-			} else {
-				// If any element isn't a string, return default
-				return defaultVal
-			}
-		}
-		return result
+	if ss, ok := toStringSlice(c.get(key)); ok {
+		return ss
 	}
 	return defaultVal
 }
 
 // Any returns the raw value for key, or defaultVal if missing.
 func (c Config) Any(key string, defaultVal any) any {
-	v, ok := c.data[key]
+	v, ok := c.lookup(key)
 	if !ok {
 		return defaultVal
 	}
 	return v
 }
 
-// Has returns true if the key exists in the config.
+// Has returns true if the key exists in the config, including nested
+// keys in dot notation.
 func (c Config) Has(key string) bool {
-	_, ok := c.data[key]
+	_, ok := c.lookup(key)
 	return ok
 }
 
+// Sub returns the config rooted at the nested map under key, so
+// cfg.Sub("database").String("host", "") is cfg.String("database.host", "").
+// Returns an empty Config if key is missing or not a map.
+func (c Config) Sub(key string) Config {
+	if m, ok := c.get(key).(map[string]any); ok {
+		return New(m)
+	}
+	return New(nil)
+}
+
 // Raw returns the underlying map.
 // The returned map should not be modified.
 func (c Config) Raw() map[string]any {
 	return c.data
 }
+
+// lookup returns the value for key, trying the exact key before walking
+// nested maps along its dots.
+func (c Config) lookup(key string) (any, bool) {
+	return lookupPath(c.data, key)
+}
+
+// get returns the value for key, or nil if missing.
+func (c Config) get(key string) any {
+	v, _ := c.lookup(key)
+	return v
+}
+
+// lookupPath finds key in m. At each level the exact remaining key wins;
+// otherwise each dot is tried as a separator, leftmost first, so segments
+// may themselves contain dots.
+func lookupPath(m map[string]any, key string) (any, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] != '.' {
+			continue
+		}
+		sub, ok := m[key[:i]].(map[string]any)
+		if !ok {
+			continue
+		}
+		if v, ok := lookupPath(sub, key[i+1:]); ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// Conversions shared by the accessors and Unmarshal. Each reports false if
+// v is missing (nil) or cannot be converted.
+
+func toString(v any) (string, bool) {
+	s, ok := v.(string)
+	return s, ok
+}
+
+func toDuration(v any) (time.Duration, bool) {
+	switch val := v.(type) {
+	case string:
+		if d, err := time.ParseDuration(val); err == nil {
+			return d, true
+		}
+	case float64:
+		return time.Duration(val * float64(time.Second)), true
+	case int:
+		return time.Duration(val) * time.Second, true
+	case int64:
+		return time.Duration(val) * time.Second, true
+	case time.Duration:
+		return val, true
+	}
+	return 0, false
+}
+
+func toBool(v any) (bool, bool) {
+	b, ok := v.(bool)
+	return b, ok
+}
+
+func toInt(v any) (int, bool) {
+	switch val := v.(type) {
+	case int:
+		return val, true
+	case int64:
+		return int(val), true
+	case float64:
+		// Only convert if there's no fractional part
+		if val == float64(int(val)) {
+			return int(val), true
+		}
+	}
+	return 0, false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case int:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	}
+	return 0, false
+}
+
+func toStringSlice(v any) ([]string, bool) {
+	switch val := v.(type) {
+	case []string:
+		return val, true
+	case []any:
+		result := make([]string, 0, len(val))
+		for _, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				// If any element isn't a string, the slice doesn't convert
+				return nil, false
+			}
+			result = append(result, s)
+		}
+		return result, true
+	}
+	return nil, false
+}
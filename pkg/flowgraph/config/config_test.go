@@ -698,3 +698,65 @@ func TestInt_LargeNumbers(t *testing.T) {
 		})
 	}
 }
+
+// TestDotPath verifies dotted keys reach into nested maps.
+func TestDotPath(t *testing.T) {
+	cfg := config.New(map[string]any{
+		"database": map[string]any{
+			"host": "db.local",
+			"pool": map[string]any{"size": 10, "timeout": "5s"},
+		},
+		"server":    map[string]any{"port": float64(8080), "tls": true},
+		"log.level": "debug", // Literal dotted key
+		"log":       map[string]any{"level": "info", "format": "json"},
+		"feature.flags": map[string]any{
+			"beta": true,
+		},
+		"scalar": "value",
+	})
+
+	assert.Equal(t, "db.local", cfg.String("database.host", ""))
+	assert.Equal(t, 8080, cfg.Int("server.port", 0))
+	assert.True(t, cfg.Bool("server.tls", false))
+	assert.Equal(t, 10, cfg.Int("database.pool.size", 0))
+	assert.Equal(t, 5*time.Second, cfg.Duration("database.pool.timeout", 0))
+	assert.Equal(t, float64(10), cfg.Float("database.pool.size", 0))
+
+	// Exact key wins over the nested path
+	assert.Equal(t, "debug", cfg.String("log.level", ""))
+	assert.Equal(t, "json", cfg.String("log.format", ""))
+
+	// Segments may contain dots
+	assert.True(t, cfg.Bool("feature.flags.beta", false))
+
+	// Missing paths use the default
+	assert.Equal(t, "none", cfg.String("database.user", "none"))
+	assert.Equal(t, "none", cfg.String("scalar.child", "none"))
+	assert.Equal(t, "none", cfg.String("missing.key", "none"))
+
+	assert.True(t, cfg.Has("database.pool"))
+	assert.False(t, cfg.Has("database.pool.max"))
+	assert.Equal(t, "db.local", cfg.Any("database.host", nil))
+}
+
+// TestSub verifies sub-configs rooted at nested keys.
+func TestSub(t *testing.T) {
+	cfg, err := config.FromYAML([]byte(`
+database:
+  host: db.local
+  pool:
+    size: 10
+port: 8080
+`))
+	require.NoError(t, err)
+
+	db := cfg.Sub("database")
+	assert.Equal(t, "db.local", db.String("host", ""))
+	assert.Equal(t, 10, db.Sub("pool").Int("size", 0))
+	assert.Equal(t, 10, cfg.Sub("database.pool").Int("size", 0))
+
+	// Missing or non-map keys give an empty config
+	assert.Empty(t, cfg.Sub("missing").Raw())
+	assert.Empty(t, cfg.Sub("port").Raw())
+	assert.Equal(t, "default", cfg.Sub("port").String("x", "default"))
+}
@@ -23,6 +23,20 @@ Create a Config from any map and extract values with defaults:
 	enabled := cfg.Bool("enabled", false)              // true
 	missing := cfg.String("missing", "default")        // "default"
 
+# Nested Keys
+
+Keys in dot notation reach into nested maps, and Sub returns the config
+rooted at a nested key:
+
+	cfg.String("database.host", "localhost")
+	cfg.Int("server.port", 8080)
+
+	db := cfg.Sub("database")
+	db.String("host", "localhost") // Same as above
+
+A key that exists literally, dots included, takes precedence over the
+nested path, so flat configs with dotted keys keep working.
+
 # Type Coercion
 
 Duration handles multiple input types:
@@ -39,6 +53,28 @@ All methods return the default value if:
   - The value cannot be converted to the requested type
   - The conversion would lose precision (e.g., float to int with fraction)
 
+# Binding to Structs
+
+Unmarshal populates a struct in one call, using `config` tags to name keys
+and the same coercion rules as the typed accessors:
+
+	type ServerConfig struct {
+	    Host    string        `config:"host"`
+	    Timeout time.Duration `config:"timeout"` // "30s" or seconds
+	    Retry   struct {
+	        Attempts int `config:"attempts"`
+	    } `config:"retry"`
+	}
+
+	var server ServerConfig
+	if err := cfg.Unmarshal(&server); err != nil {
+	    log.Fatal(err) // e.g. config field "retry.attempts": cannot convert ...
+	}
+
+Unknown keys are ignored, and missing keys leave fields unchanged. Unlike
+the accessors, a value that cannot be converted is an error (*FieldError)
+rather than silently replaced by a default.
+
 # File Loading
 
 Load configuration from YAML or JSON files:
@@ -52,6 +88,38 @@ Load configuration from YAML or JSON files:
 	cfg, err = config.FromYAML(yamlBytes)
 	cfg, err = config.FromJSON(jsonBytes)
 
+# References
+
+Resolve expands ${key} references between values of the same config,
+so a value can be written once and reused:
+
+	host: api.example.com
+	base_url: https://${host}/v1
+	db_url: postgres://${database.host}/app
+
+	cfg, err = cfg.Resolve()
+	cfg.String("base_url", "") // "https://api.example.com/v1"
+
+References may be nested and use template defaults such as
+${region:-us-east}. Missing references and cycles are errors.
+
+# Environment Overrides
+
+WithEnvOverride lets environment variables take precedence over file
+values, with the key path uppercased and joined by underscores:
+
+	cfg, err := config.FromFile("config.yaml")
+	if err != nil {
+	    log.Fatal(err)
+	}
+	cfg = cfg.WithEnvOverride("APP")
+
+	cfg.String("database.host", "") // APP_DATABASE_HOST if set
+
+Overrides are converted to the type of the file value they replace, so
+APP_DATABASE_PORT=6432 reads back through Int. Only keys present in the
+config are overridden.
+
 # Thread Safety
 
 Config is safe for concurrent read access. The underlying map is not
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// WithEnvOverride returns a copy of the config in which each value is
+// replaced by an environment variable, when one is set. The variable name
+// is prefix followed by the key's path, uppercased and joined by
+// underscores, so with prefix "APP" the key "database.host" is overridden
+// by APP_DATABASE_HOST. Dots and hyphens within a key also become
+// underscores. An empty prefix uses the path alone.
+//
+// Environment values are strings, and are converted to the type of the
+// value they replace so the typed accessors and Unmarshal treat them like
+// file values: booleans with strconv.ParseBool, integers and floats with
+// strconv, and lists as comma-separated items. A value that does not parse
+// is kept as a string, which the accessors handle as they would a
+// mistyped file value; a duration such as "30s" therefore works wherever
+// the file held a number of seconds.
+//
+// Only keys present in the config can be overridden, so give every
+// overridable key a default in the file. The environment is read once,
+// when WithEnvOverride is called.
+//
+// Example:
+//
+//	cfg, err := config.FromFile("config.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	cfg = cfg.WithEnvOverride("APP") // APP_DATABASE_HOST wins over database.host
+func (c Config) WithEnvOverride(prefix string) Config {
+	prefix = strings.TrimSuffix(envSegment(prefix), "_")
+	return Config{data: overrideFromEnv(c.data, prefix)}
+}
+
+// overrideFromEnv copies m, replacing leaf values whose environment
+// variable is set. Nested maps are copied rather than shared.
+func overrideFromEnv(m map[string]any, prefix string) map[string]any {
+	out := make(map[string]any, len(m))
+	for key, val := range m {
+		name := envSegment(key)
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		if nested, ok := val.(map[string]any); ok {
+			out[key] = overrideFromEnv(nested, name)
+			continue
+		}
+		if s, ok := os.LookupEnv(name); ok {
+			out[key] = parseEnvValue(s, val)
+			continue
+		}
+		out[key] = val
+	}
+	return out
+}
+
+// envSegment converts a key to its environment variable form.
+func envSegment(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' {
+			return '_'
+		}
+		return r
+	}, strings.ToUpper(key))
+}
+
+// parseEnvValue converts s to the type of current, falling back to s.
+func parseEnvValue(s string, current any) any {
+	switch current.(type) {
+	case bool:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case int:
+		if i, err := strconv.Atoi(s); err == nil {
+			return i
+		}
+	case int64:
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+	case float64:
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case []any, []string:
+		items := []any{}
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	return s
+}
@@ -0,0 +1,73 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithEnvOverride verifies environment variables replace nested values.
+func TestWithEnvOverride(t *testing.T) {
+	cfg, err := config.FromYAML([]byte(`
+database:
+  host: localhost
+  port: 5432
+  read-only: false
+timeout: 30
+ratio: 0.5
+tags: [a]
+name: api
+`))
+	require.NoError(t, err)
+
+	t.Setenv("APP_DATABASE_HOST", "db.prod")
+	t.Setenv("APP_DATABASE_PORT", "6432")
+	t.Setenv("APP_DATABASE_READ_ONLY", "true")
+	t.Setenv("APP_TIMEOUT", "1m")
+	t.Setenv("APP_RATIO", "0.75")
+	t.Setenv("APP_TAGS", "x, y")
+	t.Setenv("NAME", "unprefixed")
+
+	got := cfg.WithEnvOverride("APP")
+
+	assert.Equal(t, "db.prod", got.String("database.host", ""))
+	assert.Equal(t, 6432, got.Int("database.port", 0))
+	assert.True(t, got.Bool("database.read-only", false))
+	assert.Equal(t, time.Minute, got.Duration("timeout", 0))
+	assert.Equal(t, 0.75, got.Float("ratio", 0))
+	assert.Equal(t, []string{"x", "y"}, got.StringSlice("tags", nil))
+	assert.Equal(t, "api", got.String("name", ""))
+	assert.Equal(t, "db.prod", got.Sub("database").String("host", ""))
+
+	// The original config is unchanged
+	assert.Equal(t, "localhost", cfg.String("database.host", ""))
+}
+
+// TestWithEnvOverride_InvalidValue verifies unparseable values are kept as
+// strings, so accessors fall back to their defaults.
+func TestWithEnvOverride_InvalidValue(t *testing.T) {
+	t.Setenv("APP_PORT", "not-a-port")
+
+	got := config.New(map[string]any{"port": 8080}).WithEnvOverride("APP_")
+
+	assert.Equal(t, 1, got.Int("port", 1))
+	assert.Equal(t, "not-a-port", got.Any("port", nil))
+}
+
+// TestWithEnvOverride_Unmarshal verifies overrides feed through Unmarshal.
+func TestWithEnvOverride_Unmarshal(t *testing.T) {
+	t.Setenv("APP_RETRY_ATTEMPTS", "7")
+	t.Setenv("APP_RETRY_BACKOFF", "250ms")
+
+	cfg := config.New(map[string]any{
+		"retry": map[string]any{"attempts": 3, "backoff": 1},
+	}).WithEnvOverride("app")
+
+	var got retryConfig
+	require.NoError(t, cfg.Sub("retry").Unmarshal(&got))
+
+	assert.Equal(t, retryConfig{Attempts: 7, Backoff: 250 * time.Millisecond}, got)
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/template"
+)
+
+// ErrReferenceCycle is returned by Resolve when values reference each
+// other in a cycle.
+var ErrReferenceCycle = errors.New("config reference cycle")
+
+// Resolve returns a copy of the config with ${key} references in string
+// values replaced by the values of other keys in the same config. Keys
+// may use dot notation, as with the accessors:
+//
+//	host: api.example.com
+//	base_url: https://${host}/v1      # "https://api.example.com/v1"
+//	health_url: ${base_url}/health    # References resolve transitively
+//	db_host: ${database.host}
+//
+// Strings are expanded with the template package, so defaults
+// (${region:-us-east}) and template functions are supported; $name
+// without braces is left as-is. A string that is exactly one reference
+// takes the referenced value with its type, so "${port}" stays an int.
+//
+// Returns an error wrapping *template.UndefinedVariableError if a
+// reference has no value and no default, or ErrReferenceCycle if values
+// reference each other in a cycle. The receiver is not modified.
+func (c Config) Resolve() (Config, error) {
+	r := &resolver{
+		root: c.data,
+		expander: template.NewExpander(
+			template.WithDollarStyle(false),
+			template.WithMissingAction(template.MissingError),
+		),
+		resolved: make(map[string]any),
+	}
+	data, err := r.resolveMap("", c.data)
+	if err != nil {
+		return Config{}, err
+	}
+	return New(data), nil
+}
+
+// resolver expands references within a config, memoizing resolved values
+// by key path and tracking the keys being resolved to detect cycles.
+type resolver struct {
+	root     map[string]any
+	expander *template.Expander
+	resolved map[string]any
+	visiting []string
+}
+
+func (r *resolver) resolveMap(prefix string, m map[string]any) (map[string]any, error) {
+	out := make(map[string]any, len(m))
+	for key, val := range m {
+		resolved, err := r.resolveValue(joinPath(prefix, key), val)
+		if err != nil {
+			return nil, err
+		}
+		out[key] = resolved
+	}
+	return out, nil
+}
+
+// resolveValue returns val with its references expanded. path is the key
+// path of val, used to memoize strings and report cycles.
+func (r *resolver) resolveValue(path string, val any) (any, error) {
+	switch v := val.(type) {
+	case map[string]any:
+		return r.resolveMap(path, v)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			resolved, err := r.resolveValue(fmt.Sprintf("%s[%d]", path, i), item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	case string:
+		return r.resolveString(path, v)
+	default:
+		return val, nil
+	}
+}
+
+func (r *resolver) resolveString(path, s string) (any, error) {
+	if val, ok := r.resolved[path]; ok {
+		return val, nil
+	}
+	refs := template.References(s)
+	if len(refs) == 0 {
+		return s, nil
+	}
+
+	for i, key := range r.visiting {
+		if key == path {
+			chain := append(append([]string{}, r.visiting[i:]...), path)
+			return nil, fmt.Errorf("%w: %s", ErrReferenceCycle, strings.Join(chain, " -> "))
+		}
+	}
+	r.visiting = append(r.visiting, path)
+	defer func() { r.visiting = r.visiting[:len(r.visiting)-1] }()
+
+	vars := make(map[string]any, len(refs))
+	for _, ref := range refs {
+		val, ok := lookupPath(r.root, ref)
+		if !ok {
+			continue // Left to the template's default or missing handling
+		}
+		resolved, err := r.resolveValue(ref, val)
+		if err != nil {
+			return nil, err
+		}
+		vars[ref] = resolved
+	}
+
+	var result any
+	if len(refs) == 1 && s == "${"+refs[0]+"}" {
+		val, ok := vars[refs[0]]
+		if !ok {
+			return nil, fmt.Errorf("resolve %q: %w", path, &template.UndefinedVariableError{Names: refs})
+		}
+		result = val
+	} else {
+		expanded, err := r.expander.Expand(s, vars)
+		if err != nil {
+			return nil, fmt.Errorf("resolve %q: %w", path, err)
+		}
+		result = expanded
+	}
+
+	r.resolved[path] = result
+	return result, nil
+}
@@ -0,0 +1,91 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/config"
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResolve verifies references to other keys are expanded.
+func TestResolve(t *testing.T) {
+	cfg, err := config.FromYAML([]byte(`
+host: api.example.com
+port: 8443
+base_url: https://${host}:${port}
+health_url: ${base_url}/health
+listen_port: ${port}
+region: ${region_override:-us-east}
+hosts: ["${host}", other]
+price: $5
+`))
+	require.NoError(t, err)
+
+	got, err := cfg.Resolve()
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://api.example.com:8443", got.String("base_url", ""))
+	assert.Equal(t, "https://api.example.com:8443/health", got.String("health_url", ""))
+	assert.Equal(t, 8443, got.Int("listen_port", 0))
+	assert.Equal(t, "us-east", got.String("region", ""))
+	assert.Equal(t, []string{"api.example.com", "other"}, got.StringSlice("hosts", nil))
+	assert.Equal(t, "$5", got.String("price", ""))
+
+	// The original config is unchanged
+	assert.Equal(t, "https://${host}:${port}", cfg.String("base_url", ""))
+}
+
+// TestResolve_NestedPath verifies references in dot notation.
+func TestResolve_NestedPath(t *testing.T) {
+	cfg := config.New(map[string]any{
+		"database": map[string]any{
+			"host": "db.local",
+			"dsn":  "postgres://${database.host}/${name}",
+		},
+		"name":  "app",
+		"proxy": "${database.dsn}?proxy=1",
+	})
+
+	got, err := cfg.Resolve()
+	require.NoError(t, err)
+
+	assert.Equal(t, "postgres://db.local/app", got.String("database.dsn", ""))
+	assert.Equal(t, "postgres://db.local/app?proxy=1", got.String("proxy", ""))
+}
+
+// TestResolve_Cycle verifies reference cycles are reported.
+func TestResolve_Cycle(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]any
+	}{
+		{"two keys", map[string]any{"a": "x${b}", "b": "y${a}"}},
+		{"self", map[string]any{"a": "${a}"}},
+		{"through nested map", map[string]any{
+			"a": "${b.c}",
+			"b": map[string]any{"c": "${a}"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := config.New(tt.data).Resolve()
+			require.ErrorIs(t, err, config.ErrReferenceCycle)
+			assert.Contains(t, err.Error(), "a -> ")
+		})
+	}
+}
+
+// TestResolve_Undefined verifies references to missing keys are errors.
+func TestResolve_Undefined(t *testing.T) {
+	for _, value := range []string{"${missing}", "https://${missing}"} {
+		_, err := config.New(map[string]any{"url": value}).Resolve()
+
+		var undefined *template.UndefinedVariableError
+		require.ErrorAs(t, err, &undefined)
+		assert.Equal(t, []string{"missing"}, undefined.Names)
+		assert.Contains(t, err.Error(), `"url"`)
+	}
+}
@@ -0,0 +1,255 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTarget is returned by Unmarshal when the target is not a
+// non-nil pointer to a struct.
+var ErrInvalidTarget = errors.New("unmarshal target must be a non-nil pointer to a struct")
+
+// FieldError is returned by Unmarshal when a value cannot be converted to
+// the type of the field it is bound to.
+type FieldError struct {
+	// Field is the dotted key path of the value, e.g. "server.timeout".
+	Field string
+
+	// Value is the value that could not be converted.
+	Value any
+
+	// Type is the type of the target field.
+	Type reflect.Type
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("config field %q: cannot convert %T %v to %s", e.Field, e.Value, e.Value, e.Type)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Unmarshal populates the struct pointed to by target from the config.
+//
+// Each exported field is bound to the key named by its `config` tag (which
+// may be a dotted path like the accessors' keys), or if untagged, to the
+// key matching its name case-insensitively. A tag of
+// "-" skips the field. Untagged embedded structs are flattened into the
+// parent. Keys with no matching field are ignored, and fields whose key is
+// missing or null keep their current value, so defaults can be set on
+// target beforehand.
+//
+// Values are converted with the same rules as the typed accessors: a
+// time.Duration field accepts "30s" or a number of seconds, integer fields
+// accept whole floats, and so on. Nested structs, maps with string keys,
+// slices, and pointers are populated recursively. A value that cannot be
+// converted returns a *FieldError naming the field.
+//
+// Example:
+//
+//	type ServerConfig struct {
+//	    Host    string        `config:"host"`
+//	    Timeout time.Duration `config:"timeout"`
+//	    Retries int           `config:"retries"`
+//	}
+//
+//	cfg, _ := config.FromFile("server.yaml")
+//	server := ServerConfig{Retries: 3} // Default if "retries" is missing
+//	if err := cfg.Unmarshal(&server); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c Config) Unmarshal(target any) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: got %T", ErrInvalidTarget, target)
+	}
+	return decodeStruct("", c.data, rv.Elem())
+}
+
+// decodeStruct populates the fields of dst from m. prefix is the key path
+// of m, for error messages.
+func decodeStruct(prefix string, m map[string]any, dst reflect.Value) error {
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, tagged := field.Tag.Lookup("config")
+		if tag == "-" {
+			continue
+		}
+		// Embedded structs are flattened even if their type is unexported
+		if field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct {
+			if err := decodeStruct(prefix, m, dst.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		key, val, ok := lookupField(m, field.Name, tag)
+		if !ok || val == nil {
+			continue
+		}
+		if err := decodeValue(joinPath(prefix, key), val, dst.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupField finds the value for a field: by tag if given, which may be a
+// dotted path, else by case-insensitive field name.
+func lookupField(m map[string]any, name, tag string) (string, any, bool) {
+	if tag != "" {
+		val, ok := lookupPath(m, tag)
+		return tag, val, ok
+	}
+	if val, ok := m[name]; ok {
+		return name, val, true
+	}
+	for key, val := range m {
+		if strings.EqualFold(key, name) {
+			return key, val, true
+		}
+	}
+	return "", nil, false
+}
+
+// decodeValue converts val and stores it in dst.
+func decodeValue(path string, val any, dst reflect.Value) error {
+	mismatch := &FieldError{Field: path, Value: val, Type: dst.Type()}
+
+	if dst.Type() == durationType {
+		d, ok := toDuration(val)
+		if !ok {
+			return mismatch
+		}
+		dst.SetInt(int64(d))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.String:
+		s, ok := toString(val)
+		if !ok {
+			return mismatch
+		}
+		dst.SetString(s)
+
+	case reflect.Bool:
+		b, ok := toBool(val)
+		if !ok {
+			return mismatch
+		}
+		dst.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := toInt(val)
+		if !ok || dst.OverflowInt(int64(i)) {
+			return mismatch
+		}
+		dst.SetInt(int64(i))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, ok := toInt(val)
+		if !ok || i < 0 || dst.OverflowUint(uint64(i)) {
+			return mismatch
+		}
+		dst.SetUint(uint64(i))
+
+	case reflect.Float32, reflect.Float64:
+		f, ok := toFloat(val)
+		if !ok || dst.OverflowFloat(f) {
+			return mismatch
+		}
+		dst.SetFloat(f)
+
+	case reflect.Slice:
+		return decodeSlice(path, val, dst, mismatch)
+
+	case reflect.Map:
+		return decodeMap(path, val, dst, mismatch)
+
+	case reflect.Struct:
+		m, ok := val.(map[string]any)
+		if !ok {
+			return mismatch
+		}
+		return decodeStruct(path, m, dst)
+
+	case reflect.Pointer:
+		elem := reflect.New(dst.Type().Elem())
+		if err := decodeValue(path, val, elem.Elem()); err != nil {
+			return err
+		}
+		dst.Set(elem)
+
+	case reflect.Interface:
+		v := reflect.ValueOf(val)
+		if !v.Type().AssignableTo(dst.Type()) {
+			return mismatch
+		}
+		dst.Set(v)
+
+	default:
+		return mismatch
+	}
+	return nil
+}
+
+// decodeSlice converts a []any (or a slice of matching type) into dst.
+func decodeSlice(path string, val any, dst reflect.Value, mismatch error) error {
+	src := reflect.ValueOf(val)
+	if src.Kind() != reflect.Slice {
+		return mismatch
+	}
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+
+	out := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+	for i := 0; i < src.Len(); i++ {
+		item := src.Index(i).Interface()
+		if item == nil {
+			continue
+		}
+		if err := decodeValue(fmt.Sprintf("%s[%d]", path, i), item, out.Index(i)); err != nil {
+			return err
+		}
+	}
+	dst.Set(out)
+	return nil
+}
+
+// decodeMap converts a map[string]any into a map with string keys.
+func decodeMap(path string, val any, dst reflect.Value, mismatch error) error {
+	m, ok := val.(map[string]any)
+	if !ok || dst.Type().Key().Kind() != reflect.String {
+		return mismatch
+	}
+
+	out := reflect.MakeMapWithSize(dst.Type(), len(m))
+	for k, item := range m {
+		elem := reflect.New(dst.Type().Elem()).Elem()
+		if item != nil {
+			if err := decodeValue(joinPath(path, k), item, elem); err != nil {
+				return err
+			}
+		}
+		out.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), elem)
+	}
+	dst.Set(out)
+	return nil
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
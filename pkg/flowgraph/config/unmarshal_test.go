@@ -0,0 +1,164 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type retryConfig struct {
+	Attempts int           `config:"attempts"`
+	Backoff  time.Duration `config:"backoff"`
+}
+
+type baseConfig struct {
+	Name string `config:"name"`
+}
+
+type serviceConfig struct {
+	baseConfig
+	Host     string            `config:"host"`
+	Port     uint16            `config:"port"`
+	Timeout  time.Duration     `config:"timeout"`
+	Debug    bool              `config:"debug"`
+	Ratio    float64           `config:"ratio"`
+	Tags     []string          `config:"tags"`
+	Retry    retryConfig       `config:"retry"`
+	Fallback *retryConfig      `config:"fallback"`
+	Labels   map[string]string `config:"labels"`
+	Limits   []retryConfig     `config:"limits"`
+	Extra    any               `config:"extra"`
+	Region   string
+	Ignored  string `config:"-"`
+}
+
+// TestUnmarshal verifies binding a config to a struct.
+func TestUnmarshal(t *testing.T) {
+	cfg := config.New(map[string]any{
+		"name":     "api",
+		"host":     "localhost",
+		"port":     float64(8080), // JSON numbers decode as float64
+		"timeout":  "30s",
+		"debug":    true,
+		"ratio":    1, // int into float
+		"tags":     []any{"a", "b"},
+		"retry":    map[string]any{"attempts": 3, "backoff": 2}, // seconds
+		"fallback": map[string]any{"attempts": 1},
+		"labels":   map[string]any{"team": "core"},
+		"limits":   []any{map[string]any{"attempts": 5}},
+		"extra":    map[string]any{"any": "thing"},
+		"REGION":   "us-east",
+		"Ignored":  "nope",
+		"unknown":  "ignored",
+	})
+
+	var got serviceConfig
+	require.NoError(t, cfg.Unmarshal(&got))
+
+	assert.Equal(t, serviceConfig{
+		baseConfig: baseConfig{Name: "api"},
+		Host:       "localhost",
+		Port:       8080,
+		Timeout:    30 * time.Second,
+		Debug:      true,
+		Ratio:      1,
+		Tags:       []string{"a", "b"},
+		Retry:      retryConfig{Attempts: 3, Backoff: 2 * time.Second},
+		Fallback:   &retryConfig{Attempts: 1},
+		Labels:     map[string]string{"team": "core"},
+		Limits:     []retryConfig{{Attempts: 5}},
+		Extra:      map[string]any{"any": "thing"},
+		Region:     "us-east",
+	}, got)
+}
+
+// TestUnmarshal_KeepsDefaults verifies missing and null keys leave fields unchanged.
+func TestUnmarshal_KeepsDefaults(t *testing.T) {
+	cfg := config.New(map[string]any{"attempts": nil})
+
+	got := retryConfig{Attempts: 3, Backoff: time.Second}
+	require.NoError(t, cfg.Unmarshal(&got))
+
+	assert.Equal(t, retryConfig{Attempts: 3, Backoff: time.Second}, got)
+}
+
+// TestUnmarshal_FromYAML verifies binding a config loaded from YAML.
+func TestUnmarshal_FromYAML(t *testing.T) {
+	cfg, err := config.FromYAML([]byte(`
+host: example.com
+timeout: 1m30s
+retry:
+  attempts: 4
+  backoff: 500ms
+`))
+	require.NoError(t, err)
+
+	var got serviceConfig
+	require.NoError(t, cfg.Unmarshal(&got))
+
+	assert.Equal(t, "example.com", got.Host)
+	assert.Equal(t, 90*time.Second, got.Timeout)
+	assert.Equal(t, retryConfig{Attempts: 4, Backoff: 500 * time.Millisecond}, got.Retry)
+}
+
+// TestUnmarshal_TypeMismatch verifies conversion errors name the field.
+func TestUnmarshal_TypeMismatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  map[string]any
+		field string
+	}{
+		{"invalid duration", map[string]any{"timeout": "soon"}, "timeout"},
+		{"string into int", map[string]any{"retry": map[string]any{"attempts": "three"}}, "retry.attempts"},
+		{"fractional int", map[string]any{"retry": map[string]any{"attempts": 1.5}}, "retry.attempts"},
+		{"port overflow", map[string]any{"port": 70000}, "port"},
+		{"negative uint", map[string]any{"port": -1}, "port"},
+		{"number into bool", map[string]any{"debug": 1}, "debug"},
+		{"scalar into struct", map[string]any{"retry": "fast"}, "retry"},
+		{"bad slice element", map[string]any{"tags": []any{"a", 2}}, "tags[1]"},
+		{"bad map value", map[string]any{"labels": map[string]any{"team": 1}}, "labels.team"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got serviceConfig
+			err := config.New(tt.data).Unmarshal(&got)
+
+			var fieldErr *config.FieldError
+			require.ErrorAs(t, err, &fieldErr)
+			assert.Equal(t, tt.field, fieldErr.Field)
+			assert.Contains(t, err.Error(), tt.field)
+		})
+	}
+}
+
+// TestUnmarshal_InvalidTarget verifies non-struct-pointer targets are rejected.
+func TestUnmarshal_InvalidTarget(t *testing.T) {
+	cfg := config.New(nil)
+	var nilPtr *retryConfig
+	var m map[string]any
+
+	assert.ErrorIs(t, cfg.Unmarshal(retryConfig{}), config.ErrInvalidTarget)
+	assert.ErrorIs(t, cfg.Unmarshal(nilPtr), config.ErrInvalidTarget)
+	assert.ErrorIs(t, cfg.Unmarshal(&m), config.ErrInvalidTarget)
+	assert.ErrorIs(t, cfg.Unmarshal(nil), config.ErrInvalidTarget)
+}
+
+// TestUnmarshal_DottedTags verifies tags can name nested keys.
+func TestUnmarshal_DottedTags(t *testing.T) {
+	cfg := config.New(map[string]any{
+		"database": map[string]any{"host": "db.local", "port": 5432},
+	})
+
+	var got struct {
+		Host string `config:"database.host"`
+		Port int    `config:"database.port"`
+	}
+	require.NoError(t, cfg.Unmarshal(&got))
+
+	assert.Equal(t, "db.local", got.Host)
+	assert.Equal(t, 5432, got.Port)
+}
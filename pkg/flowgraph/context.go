@@ -2,10 +2,14 @@ package flowgraph
 
 import (
 	"context"
+	"hash/fnv"
 	"log/slog"
+	"math/rand/v2"
+	"sync"
 
 	"github.com/google/uuid"
 	"github.com/randalmurphal/flowgraph/pkg/flowgraph/checkpoint"
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/llm"
 )
 
 // Context provides execution context to nodes.
@@ -19,6 +23,8 @@ type Context interface {
 	// Services
 
 	// Logger returns the configured logger, enriched with run and node context.
+	// Inside a node it is bound with run_id, node_id, and attempt, where
+	// run_id is the ID passed via WithRunID or Resume when one is given.
 	// Never returns nil - defaults to slog.Default() if not configured.
 	Logger() *slog.Logger
 
@@ -26,6 +32,29 @@ type Context interface {
 	// Nodes should check for nil before using.
 	Checkpointer() checkpoint.Store
 
+	// LLM returns the LLM client configured with WithLLM, or nil.
+	// During a run, the cost of each Complete call made through it is added
+	// to TotalCostUSD and checked against WithRunBudgetUSD.
+	LLM() llm.Client
+
+	// TotalCostUSD returns the LLM spend of the current Run or Resume call,
+	// summed from CompletionResponse.CostUSD. Zero outside a run.
+	TotalCostUSD() float64
+
+	// Rand returns a random number generator for the run. It is seeded
+	// from WithRandSeed if set, else from the run ID, so a run repeated with
+	// the same ID makes the same random choices. Safe for concurrent use.
+	Rand() *rand.Rand
+
+	// BranchScratch returns the scratchpad shared by the branches of the
+	// enclosing fork/join and its join node, or nil outside one. See
+	// Scratchpad for the concurrency contract.
+	BranchScratch() *Scratchpad
+
+	// LastError returns the error that routed execution to the current
+	// node through an error edge (see Graph.AddErrorEdge), or nil.
+	LastError() error
+
 	// Metadata
 
 	// RunID returns the unique identifier for this execution run.
@@ -44,8 +73,16 @@ type Context interface {
 type executionContext struct {
 	context.Context
 
-	logger       *slog.Logger
+	logger       *slog.Logger // base with run, node, and attempt fields inside a node
+	base         *slog.Logger // logger as configured
 	checkpointer checkpoint.Store
+	llm          llm.Client
+	spend        *llmSpend // nil outside a run
+	rng          *rand.Rand
+	seed         *uint64         // set by WithRandSeed
+	scratch      *Scratchpad     // set inside a fork/join
+	lastErr      error           // set on the target of an error edge
+	attempts     *attemptCounter // set by the executor while tracing a node
 	runID        string
 	nodeID       string
 	attempt      int
@@ -61,6 +98,37 @@ func (c *executionContext) Checkpointer() checkpoint.Store {
 	return c.checkpointer
 }
 
+// LLM returns the LLM client, metered when inside a run.
+func (c *executionContext) LLM() llm.Client {
+	if c.llm == nil || c.spend == nil {
+		return c.llm
+	}
+	return &meteredClient{client: c.llm, spend: c.spend, nodeID: c.nodeID}
+}
+
+// TotalCostUSD returns the LLM spend of the current run.
+func (c *executionContext) TotalCostUSD() float64 {
+	if c.spend == nil {
+		return 0
+	}
+	return c.spend.total()
+}
+
+// Rand returns the run's random number generator.
+func (c *executionContext) Rand() *rand.Rand {
+	return c.rng
+}
+
+// BranchScratch returns the enclosing fork/join's scratchpad.
+func (c *executionContext) BranchScratch() *Scratchpad {
+	return c.scratch
+}
+
+// LastError returns the error that routed execution to the current node.
+func (c *executionContext) LastError() error {
+	return c.lastErr
+}
+
 // RunID returns the run identifier.
 func (c *executionContext) RunID() string {
 	return c.runID
@@ -94,6 +162,13 @@ func WithCheckpointer(store checkpoint.Store) ContextOption {
 	}
 }
 
+// WithLLM sets the LLM client returned by Context.LLM.
+func WithLLM(client llm.Client) ContextOption {
+	return func(c *executionContext) {
+		c.llm = client
+	}
+}
+
 // WithContextRunID sets the run identifier for the context.
 // If not set, a UUID will be auto-generated.
 // This is used for logging and tracing. For checkpointing, use
@@ -104,6 +179,14 @@ func WithContextRunID(id string) ContextOption {
 	}
 }
 
+// WithRandSeed sets the seed of the generator returned by Context.Rand.
+// If not set, the generator is seeded from the run ID.
+func WithRandSeed(seed uint64) ContextOption {
+	return func(c *executionContext) {
+		c.seed = &seed
+	}
+}
+
 // NewContext creates an execution context from a standard context.
 // The returned Context wraps the provided context.Context and adds
 // flowgraph-specific services and metadata.
@@ -124,19 +207,84 @@ func NewContext(ctx context.Context, opts ...ContextOption) Context {
 	for _, opt := range opts {
 		opt(ec)
 	}
+	ec.base = ec.logger
+	ec.rng = ec.newRand()
 
 	return ec
 }
 
+// newRand returns a generator seeded from the configured seed or run ID.
+func (c *executionContext) newRand() *rand.Rand {
+	seed := c.seed
+	if seed == nil {
+		h := fnv.New64a()
+		h.Write([]byte(c.runID))
+		sum := h.Sum64()
+		seed = &sum
+	}
+	return rand.New(&lockedSource{src: rand.NewPCG(*seed, *seed)})
+}
+
+// lockedSource makes a rand.Source safe for concurrent use, so parallel
+// branches can share one generator.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+// Uint64 implements rand.Source.
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+// derive returns a copy of the context with fn applied. Inside a node,
+// the logger is rebound from the configured logger so the run, node, and
+// attempt fields reflect the copy and are never repeated.
+func (c *executionContext) derive(fn func(*executionContext)) *executionContext {
+	d := *c
+	fn(&d)
+	if d.nodeID != "" {
+		d.logger = d.base.With("run_id", d.runID, "node_id", d.nodeID, "attempt", d.attempt)
+	}
+	return &d
+}
+
 // withNodeID returns a new context with the given node ID set.
 // Used internally by the executor to enrich the context per-node.
 func (c *executionContext) withNodeID(nodeID string) *executionContext {
-	return &executionContext{
-		Context:      c.Context,
-		logger:       c.logger.With("run_id", c.runID, "node_id", nodeID, "attempt", c.attempt),
-		checkpointer: c.checkpointer,
-		runID:        c.runID,
-		nodeID:       nodeID,
-		attempt:      c.attempt,
+	return c.derive(func(d *executionContext) {
+		d.nodeID = nodeID
+		d.attempts = nil // Only the executor reports attempts
+	})
+}
+
+// withAttempt returns a new context with the given attempt number set.
+// Used internally by the executor when retrying a node.
+func (c *executionContext) withAttempt(attempt int) *executionContext {
+	return c.derive(func(d *executionContext) { d.attempt = attempt })
+}
+
+// withContext returns a new context wrapping the given context.Context.
+// Used internally by the executor to apply per-node deadlines.
+func (c *executionContext) withContext(ctx context.Context) *executionContext {
+	return c.derive(func(d *executionContext) { d.Context = ctx })
+}
+
+// bindRun returns ctx prepared for one Run or Resume call: its run ID is
+// set to runID, so that node loggers and RunID report the ID used for
+// checkpointing, LLM spend starts again from zero under budgetUSD (0 for
+// no limit), and Rand is reseeded so the run's random choices repeat. Contexts not created by NewContext are returned
+// unchanged.
+func bindRun(ctx Context, runID string, budgetUSD float64) Context {
+	ec, ok := ctx.(*executionContext)
+	if !ok {
+		return ctx
 	}
+	return ec.derive(func(d *executionContext) {
+		d.runID = runID
+		d.spend = &llmSpend{limit: budgetUSD}
+		d.rng = d.newRand()
+	})
 }
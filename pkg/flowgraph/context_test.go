@@ -1,12 +1,18 @@
 package flowgraph
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
+	"strings"
 	"testing"
 
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestWithLogger tests WithLogger option.
@@ -41,3 +47,116 @@ func TestContext_DefaultAttempt(t *testing.T) {
 	ctx := NewContext(context.Background())
 	assert.Equal(t, 1, ctx.Attempt())
 }
+
+// logCapture returns a context whose logger writes JSON records to buf.
+func logCapture(buf *bytes.Buffer) Context {
+	return NewContext(context.Background(), WithLogger(slog.New(slog.NewJSONHandler(buf, nil))))
+}
+
+// logRecords decodes the JSON records in buf, failing on duplicate keys.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		for _, key := range []string{"run_id", "node_id", "attempt"} {
+			assert.LessOrEqual(t, strings.Count(line, `"`+key+`"`), 1, "duplicate %s in %s", key, line)
+		}
+		var rec map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &rec))
+		records = append(records, rec)
+	}
+	return records
+}
+
+// TestNodeLogger_BindsRunAndNode tests that a node's logger carries run and node fields.
+func TestNodeLogger_BindsRunAndNode(t *testing.T) {
+	var buf bytes.Buffer
+	logNode := func(ctx Context, s Counter) (Counter, error) {
+		ctx.Logger().Info("hello")
+		return s, nil
+	}
+
+	graph := NewGraph[Counter]().
+		AddNode("greet", logNode).
+		AddEdge("greet", END).
+		SetEntry("greet")
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(logCapture(&buf), Counter{}, WithRunID("run-42"))
+	require.NoError(t, err)
+
+	records := logRecords(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "hello", records[0]["msg"])
+	assert.Equal(t, "run-42", records[0]["run_id"])
+	assert.Equal(t, "greet", records[0]["node_id"])
+	assert.Equal(t, float64(1), records[0]["attempt"])
+}
+
+// TestNodeLogger_BindsAttemptOnRetry tests that retried attempts log their attempt number.
+func TestNodeLogger_BindsAttemptOnRetry(t *testing.T) {
+	var buf bytes.Buffer
+	flaky := func(ctx Context, s Counter) (Counter, error) {
+		ctx.Logger().Info("trying")
+		if ctx.Attempt() < 2 {
+			return s, fgerrors.Transient(errors.New("rate limited"), "fetch")
+		}
+		return s, nil
+	}
+
+	graph := NewGraph[Counter]().
+		AddNode("fetch", flaky, WithNodeRetry(fastRetry)).
+		AddEdge("fetch", END).
+		SetEntry("fetch")
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	ctx := logCapture(&buf)
+	_, err = compiled.Run(ctx, Counter{})
+	require.NoError(t, err)
+
+	var attempts []map[string]any
+	for _, rec := range logRecords(t, &buf) {
+		if rec["msg"] == "trying" {
+			attempts = append(attempts, rec)
+		}
+	}
+	require.Len(t, attempts, 2)
+	for i, rec := range attempts {
+		assert.Equal(t, ctx.RunID(), rec["run_id"])
+		assert.Equal(t, "fetch", rec["node_id"])
+		assert.Equal(t, float64(i+1), rec["attempt"])
+	}
+}
+
+// TestNodeLogger_SubgraphNodes tests that subgraph nodes log their own node ID once.
+func TestNodeLogger_SubgraphNodes(t *testing.T) {
+	var buf bytes.Buffer
+	logNode := func(ctx Context, s Counter) (Counter, error) {
+		ctx.Logger().Info("inner")
+		return s, nil
+	}
+
+	inner, err := NewGraph[Counter]().
+		AddNode("leaf", logNode).
+		AddEdge("leaf", END).
+		SetEntry("leaf").
+		Compile()
+	require.NoError(t, err)
+
+	compiled, err := NewGraph[Counter]().
+		AddSubgraph("sub", inner).
+		AddEdge("sub", END).
+		SetEntry("sub").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(logCapture(&buf), Counter{}, WithRunID("run-7"))
+	require.NoError(t, err)
+
+	records := logRecords(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "run-7", records[0]["run_id"])
+	assert.Equal(t, "leaf", records[0]["node_id"])
+}
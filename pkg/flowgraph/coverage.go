@@ -0,0 +1,64 @@
+package flowgraph
+
+import "sync"
+
+// BranchRecorder records the targets chosen by conditional edges, so
+// tooling can report which branches a set of runs exercised.
+//
+// Pass the same recorder to many runs with WithBranchRecorder to
+// accumulate coverage over a test suite. BranchRecorder is safe for
+// concurrent use, including by parallel branches and concurrent runs.
+type BranchRecorder struct {
+	mu     sync.Mutex
+	counts map[string]map[string]int
+}
+
+// NewBranchRecorder creates an empty BranchRecorder.
+func NewBranchRecorder() *BranchRecorder {
+	return &BranchRecorder{counts: make(map[string]map[string]int)}
+}
+
+// record counts one routing decision from a conditional edge.
+func (r *BranchRecorder) record(from, to string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	targets, ok := r.counts[from]
+	if !ok {
+		targets = make(map[string]int)
+		r.counts[from] = targets
+	}
+	targets[to]++
+}
+
+// BranchCoverage returns how many times each conditional edge routed to
+// each target, keyed by source node and then by target (which may be END).
+// Routers that were never evaluated are absent. The result is a copy.
+//
+// Example:
+//
+//	coverage := recorder.BranchCoverage()
+//	if coverage["review"]["reject"] == 0 {
+//	    t.Error("no test exercised the reject branch")
+//	}
+func (r *BranchRecorder) BranchCoverage() map[string]map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	coverage := make(map[string]map[string]int, len(r.counts))
+	for from, targets := range r.counts {
+		copied := make(map[string]int, len(targets))
+		for to, n := range targets {
+			copied[to] = n
+		}
+		coverage[from] = copied
+	}
+	return coverage
+}
+
+// Reset discards all recorded decisions.
+func (r *BranchRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.counts)
+}
@@ -0,0 +1,89 @@
+package flowgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBranchRecorder_MatchesStates tests that the recorded branch choices
+// accumulate across runs and match the states fed to the graph.
+func TestBranchRecorder_MatchesStates(t *testing.T) {
+	compiled := reviewGraph(t, 5, "")
+	recorder := NewBranchRecorder()
+
+	for _, count := range []int{1, 3, 9} {
+		_, err := compiled.Run(testCtx(), State{Count: count}, WithBranchRecorder(recorder))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, map[string]map[string]int{
+		"classify": {"small": 2, "large": 1},
+	}, recorder.BranchCoverage())
+}
+
+// TestBranchRecorder_Loop tests that each evaluation of a router in a loop
+// is recorded, including routing to END.
+func TestBranchRecorder_Loop(t *testing.T) {
+	compiled, err := NewGraph[Counter]().
+		AddNode("inc", increment).
+		AddConditionalEdge("inc", func(ctx Context, s Counter) string {
+			if s.Value < 3 {
+				return "inc"
+			}
+			return END
+		}).
+		SetEntry("inc").
+		Compile()
+	require.NoError(t, err)
+	recorder := NewBranchRecorder()
+
+	_, err = compiled.Run(testCtx(), Counter{}, WithBranchRecorder(recorder))
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]int{
+		"inc": {"inc": 2, END: 1},
+	}, recorder.BranchCoverage())
+}
+
+// TestBranchRecorder_InvalidRouteNotRecorded tests that a router returning
+// an unknown node is not recorded.
+func TestBranchRecorder_InvalidRouteNotRecorded(t *testing.T) {
+	compiled, err := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddConditionalEdge("a", func(ctx Context, s Counter) string { return "nowhere" }).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+	recorder := NewBranchRecorder()
+
+	_, err = compiled.Run(testCtx(), Counter{}, WithBranchRecorder(recorder))
+
+	require.ErrorIs(t, err, ErrRouterTargetNotFound)
+	assert.Empty(t, recorder.BranchCoverage())
+}
+
+// TestBranchRecorder_CoverageIsCopy tests that BranchCoverage returns a
+// snapshot and that Reset clears the recorder.
+func TestBranchRecorder_CoverageIsCopy(t *testing.T) {
+	compiled := reviewGraph(t, 5, "")
+	recorder := NewBranchRecorder()
+	_, err := compiled.Run(testCtx(), State{Count: 1}, WithBranchRecorder(recorder))
+	require.NoError(t, err)
+
+	coverage := recorder.BranchCoverage()
+	coverage["classify"]["small"] = 100
+	assert.Equal(t, 1, recorder.BranchCoverage()["classify"]["small"])
+
+	recorder.Reset()
+	assert.Empty(t, recorder.BranchCoverage())
+	assert.Equal(t, 100, coverage["classify"]["small"])
+}
+
+// TestWithBranchRecorder_Nil tests that a nil recorder panics.
+func TestWithBranchRecorder_Nil(t *testing.T) {
+	assert.Panics(t, func() {
+		WithBranchRecorder(nil)
+	})
+}
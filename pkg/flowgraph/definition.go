@@ -0,0 +1,217 @@
+package flowgraph
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidDefinition indicates a graph definition passed to
+// LoadDefinition is malformed.
+var ErrInvalidDefinition = errors.New("invalid graph definition")
+
+// GraphDefinition is a declarative graph, parsed from YAML or JSON by
+// ParseDefinition. See LoadDefinition for the document format.
+type GraphDefinition struct {
+	Entry            string                      `yaml:"entry" json:"entry,omitempty"`
+	Nodes            []NodeDefinition            `yaml:"nodes" json:"nodes,omitempty"`
+	Edges            []EdgeDefinition            `yaml:"edges" json:"edges,omitempty"`
+	ConditionalEdges []ConditionalEdgeDefinition `yaml:"conditional_edges" json:"conditional_edges,omitempty"`
+}
+
+// NodeDefinition is a node of a GraphDefinition.
+type NodeDefinition struct {
+	// ID is the node ID.
+	ID string `yaml:"id" json:"id"`
+
+	// Factory is the node registry key. Defaults to ID.
+	Factory string `yaml:"factory" json:"factory,omitempty"`
+}
+
+// EdgeDefinition is an unconditional edge of a GraphDefinition.
+type EdgeDefinition struct {
+	From string `yaml:"from" json:"from"`
+	To   string `yaml:"to" json:"to"`
+}
+
+// ConditionalEdgeDefinition is a conditional edge of a GraphDefinition,
+// routed with expr conditions as in AddConditionalEdgeExpr.
+type ConditionalEdgeDefinition struct {
+	From      string            `yaml:"from" json:"from"`
+	Routes    []RouteDefinition `yaml:"routes" json:"routes"`
+	Otherwise string            `yaml:"otherwise" json:"otherwise,omitempty"`
+}
+
+// RouteDefinition is one route of a ConditionalEdgeDefinition.
+type RouteDefinition struct {
+	When string `yaml:"when" json:"when"`
+	To   string `yaml:"to" json:"to"`
+}
+
+// ParseDefinition reads a declarative graph from a YAML or JSON document
+// without resolving its nodes, so the same definition can be built with
+// BuildDefinition for several registries or state types. See LoadDefinition
+// for the document format. Unknown fields are rejected to catch typos.
+//
+// Returns an error wrapping ErrInvalidDefinition if the document is
+// malformed.
+//
+// Example:
+//
+//	f, err := os.Open("workflow.yaml")
+//	if err != nil {
+//	    return err
+//	}
+//	defer f.Close()
+//
+//	def, err := flowgraph.ParseDefinition(f)
+func ParseDefinition(r io.Reader) (*GraphDefinition, error) {
+	var def GraphDefinition
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&def); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: empty document", ErrInvalidDefinition)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDefinition, err)
+	}
+	return &def, nil
+}
+
+// LoadDefinition builds a graph from a declarative YAML or JSON document,
+// resolving each node's function from reg by its factory key.
+//
+// The document lists the nodes, edges, and entry point:
+//
+//	entry: draft
+//	nodes:
+//	  - id: draft
+//	    factory: write-draft   # Registry key; defaults to the node ID
+//	  - id: review
+//	  - id: publish
+//	edges:
+//	  - from: draft
+//	    to: review
+//	  - from: publish
+//	    to: END
+//	conditional_edges:
+//	  - from: review
+//	    routes:
+//	      - when: "score >= 8"
+//	        to: publish
+//	    otherwise: draft
+//
+// Conditional edges are added with AddConditionalEdgeExpr, so their
+// conditions are expr expressions over the state. "END" names END in any
+// target. Unknown fields are rejected to catch typos.
+//
+// The returned graph is not compiled, so more nodes and edges can be
+// added in code before calling Compile, which validates the structure.
+//
+// Returns an error wrapping ErrInvalidDefinition if the document is
+// malformed, ErrNodeFactoryNotFound if a factory key is not in reg, or
+// ErrNodeRegistryMissing if reg is nil.
+func LoadDefinition[S any](data []byte, reg *NodeRegistry[S]) (*Graph[S], error) {
+	if reg == nil {
+		return nil, ErrNodeRegistryMissing
+	}
+
+	def, err := ParseDefinition(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return definitionGraph(def, reg, stateVars[S])
+}
+
+// BuildDefinition resolves the nodes of def from reg and compiles the
+// graph. Its expr conditions are evaluated against the variables vars
+// returns for the state; a nil vars uses the state's JSON encoding, as
+// AddConditionalEdgeExpr does.
+//
+// Returns an error wrapping ErrInvalidDefinition if def is malformed,
+// ErrNodeFactoryNotFound if a factory key is not in reg,
+// ErrNodeRegistryMissing if reg is nil, or the Compile error.
+//
+// Example:
+//
+//	compiled, err := flowgraph.BuildDefinition(def, nodes, func(s Order) map[string]any {
+//	    return map[string]any{"total": s.Total, "region": s.Region}
+//	})
+func BuildDefinition[S any](def *GraphDefinition, reg *NodeRegistry[S], vars func(S) map[string]any) (*CompiledGraph[S], error) {
+	if reg == nil {
+		return nil, ErrNodeRegistryMissing
+	}
+
+	varsFn := stateVars[S]
+	if vars != nil {
+		varsFn = func(state S) (map[string]any, error) {
+			return vars(state), nil
+		}
+	}
+
+	g, err := definitionGraph(def, reg, varsFn)
+	if err != nil {
+		return nil, err
+	}
+	return g.Compile()
+}
+
+// definitionGraph builds the graph described by def.
+func definitionGraph[S any](def *GraphDefinition, reg *NodeRegistry[S], vars func(S) (map[string]any, error)) (g *Graph[S], err error) {
+	// The builder panics on invalid input; report that as an error instead
+	defer func() {
+		if r := recover(); r != nil {
+			g = nil
+			err = fmt.Errorf("%w: %v", ErrInvalidDefinition, r)
+		}
+	}()
+
+	g = NewGraph[S]()
+	for i, node := range def.Nodes {
+		key := node.Factory
+		if key == "" {
+			key = node.ID
+		}
+		if key == "" {
+			return nil, fmt.Errorf("%w: node %d: id is required", ErrInvalidDefinition, i)
+		}
+		fn, ok := reg.Get(key)
+		if !ok || fn == nil {
+			return nil, fmt.Errorf("node %q: %w: %s", node.ID, ErrNodeFactoryNotFound, key)
+		}
+		g.AddNode(node.ID, fn)
+	}
+
+	for _, edge := range def.Edges {
+		g.AddEdge(edge.From, definitionTarget(edge.To))
+	}
+
+	for _, cond := range def.ConditionalEdges {
+		routes := make([]ExprRoute, len(cond.Routes))
+		for i, route := range cond.Routes {
+			routes[i] = ExprRoute{When: route.When, To: definitionTarget(route.To)}
+		}
+		router, err := exprRouter(routes, definitionTarget(cond.Otherwise), vars)
+		if err != nil {
+			return nil, fmt.Errorf("%w: conditional edge from %q: %w", ErrInvalidDefinition, cond.From, err)
+		}
+		g.AddConditionalEdge(cond.From, router)
+	}
+
+	if def.Entry != "" {
+		g.SetEntry(def.Entry)
+	}
+	return g, nil
+}
+
+// definitionTarget maps the "END" spelling used in definitions to END.
+func definitionTarget(id string) string {
+	if strings.EqualFold(id, "END") {
+		return END
+	}
+	return id
+}
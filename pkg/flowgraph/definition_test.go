@@ -0,0 +1,229 @@
+package flowgraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const loopDefinition = `
+entry: start
+nodes:
+  - id: start
+    factory: inc
+  - id: step
+    factory: inc
+  - id: finish
+    factory: double
+edges:
+  - from: start
+    to: step
+  - from: finish
+    to: END
+conditional_edges:
+  - from: step
+    routes:
+      - when: "Value >= 3"
+        to: finish
+    otherwise: step
+`
+
+func definitionRegistry() *NodeRegistry[Counter] {
+	nodes := registry.New[string, NodeFunc[Counter]]()
+	nodes.Register("inc", increment)
+	nodes.Register("double", func(ctx Context, s Counter) (Counter, error) {
+		s.Value *= 2
+		return s, nil
+	})
+	return nodes
+}
+
+// TestLoadDefinition_YAML tests loading and running a graph with a linear path and an expr route.
+func TestLoadDefinition_YAML(t *testing.T) {
+	graph, err := LoadDefinition([]byte(loopDefinition), definitionRegistry())
+	require.NoError(t, err)
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), Counter{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 6, result.Value, "start, step x2 to reach 3, then doubled")
+}
+
+// TestLoadDefinition_JSON tests loading a JSON document with the factory key defaulting to the node ID.
+func TestLoadDefinition_JSON(t *testing.T) {
+	doc := `{
+		"entry": "inc",
+		"nodes": [{"id": "inc"}],
+		"edges": [{"from": "inc", "to": "end"}]
+	}`
+
+	graph, err := LoadDefinition([]byte(doc), definitionRegistry())
+	require.NoError(t, err)
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), Counter{Value: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Value)
+}
+
+// TestLoadDefinition_Errors tests that malformed definitions return errors instead of panicking.
+func TestLoadDefinition_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr error
+	}{
+		{"empty document", "", ErrInvalidDefinition},
+		{"syntax error", "nodes: [", ErrInvalidDefinition},
+		{"unknown field", "entry: a\nnodez: []", ErrInvalidDefinition},
+		{"missing factory", "nodes:\n  - id: a\n    factory: missing", ErrNodeFactoryNotFound},
+		{"reserved node ID", "nodes:\n  - id: END\n    factory: inc", ErrInvalidDefinition},
+		{"duplicate node", "nodes:\n  - id: inc\n  - id: inc", ErrInvalidDefinition},
+		{"bad condition", "nodes:\n  - id: inc\nconditional_edges:\n  - from: inc\n    routes:\n      - when: \"Value >=\"\n        to: END", ErrInvalidDefinition},
+		{"no routes", "nodes:\n  - id: inc\nconditional_edges:\n  - from: inc", ErrInvalidDefinition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph, err := LoadDefinition([]byte(tt.doc), definitionRegistry())
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, graph)
+		})
+	}
+}
+
+// TestLoadDefinition_NilRegistry tests that a nil registry is rejected.
+func TestLoadDefinition_NilRegistry(t *testing.T) {
+	_, err := LoadDefinition[Counter]([]byte(loopDefinition), nil)
+
+	assert.ErrorIs(t, err, ErrNodeRegistryMissing)
+}
+
+// TestBuildDefinition tests parsing from a reader and compiling in a separate step.
+func TestBuildDefinition(t *testing.T) {
+	def, err := ParseDefinition(strings.NewReader(loopDefinition))
+	require.NoError(t, err)
+	assert.Equal(t, "start", def.Entry)
+	assert.Len(t, def.Nodes, 3)
+
+	compiled, err := BuildDefinition(def, definitionRegistry(), nil)
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), Counter{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 6, result.Value)
+}
+
+// TestBuildDefinition_StateVars tests that conditions see the variables of the vars function.
+func TestBuildDefinition_StateVars(t *testing.T) {
+	doc := strings.ReplaceAll(loopDefinition, "Value >= 3", "count >= 2")
+	def, err := ParseDefinition(strings.NewReader(doc))
+	require.NoError(t, err)
+
+	compiled, err := BuildDefinition(def, definitionRegistry(), func(s Counter) map[string]any {
+		return map[string]any{"count": s.Value}
+	})
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), Counter{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Value, "start, step to reach 2, then doubled")
+}
+
+// TestBuildDefinition_Errors tests that parse, build, and compile errors are returned.
+func TestBuildDefinition_Errors(t *testing.T) {
+	_, err := ParseDefinition(strings.NewReader("nodes: ["))
+	assert.ErrorIs(t, err, ErrInvalidDefinition)
+
+	def, err := ParseDefinition(strings.NewReader("nodes:\n  - id: inc"))
+	require.NoError(t, err)
+
+	_, err = BuildDefinition[Counter](def, nil, nil)
+	assert.ErrorIs(t, err, ErrNodeRegistryMissing)
+
+	_, err = BuildDefinition(def, definitionRegistry(), nil)
+	assert.ErrorIs(t, err, ErrNoEntryPoint)
+}
+
+// TestAddConditionalEdgeExpr_Routes tests route order and the otherwise target.
+func TestAddConditionalEdgeExpr_Routes(t *testing.T) {
+	graph := NewGraph[Counter]().
+		AddNode("check", passthrough[Counter]).
+		AddNode("big", increment).
+		AddNode("small", increment).
+		AddEdge("big", END).
+		AddEdge("small", END).
+		AddConditionalEdgeExpr("check", []ExprRoute{
+			{When: "Value > 100", To: "big"},
+			{When: "Value > 10", To: END},
+		}, "small").
+		SetEntry("check")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	for input, want := range map[int]int{500: 501, 50: 50, 5: 6} {
+		result, err := compiled.Run(testCtx(), Counter{Value: input})
+		require.NoError(t, err)
+		assert.Equal(t, want, result.Value, "input %d", input)
+	}
+}
+
+// TestAddConditionalEdgeExpr_ScalarState tests that non-object state is exposed as "state".
+func TestAddConditionalEdgeExpr_ScalarState(t *testing.T) {
+	graph := NewGraph[int]().
+		AddNode("check", func(ctx Context, s int) (int, error) { return s, nil }).
+		AddNode("negate", func(ctx Context, s int) (int, error) { return -s, nil }).
+		AddEdge("negate", END).
+		AddConditionalEdgeExpr("check", []ExprRoute{{When: "state < 0", To: "negate"}}, END).
+		SetEntry("check")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), -4)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result)
+}
+
+// TestAddConditionalEdgeExpr_NoMatch tests that an unmatched route without otherwise fails the run.
+func TestAddConditionalEdgeExpr_NoMatch(t *testing.T) {
+	graph := NewGraph[Counter]().
+		AddNode("check", passthrough[Counter]).
+		AddConditionalEdgeExpr("check", []ExprRoute{{When: "Value > 0", To: END}}, "").
+		SetEntry("check")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), Counter{})
+
+	var routerErr *RouterError
+	require.ErrorAs(t, err, &routerErr)
+	assert.ErrorIs(t, err, ErrInvalidRouterResult)
+}
+
+// TestAddConditionalEdgeExpr_Panics tests builder validation.
+func TestAddConditionalEdgeExpr_Panics(t *testing.T) {
+	assert.Panics(t, func() {
+		NewGraph[Counter]().AddConditionalEdgeExpr("a", nil, END)
+	})
+	assert.Panics(t, func() {
+		NewGraph[Counter]().AddConditionalEdgeExpr("a", []ExprRoute{{When: "x ==", To: END}}, END)
+	})
+	assert.Panics(t, func() {
+		NewGraph[Counter]().AddConditionalEdgeExpr("a", []ExprRoute{{When: "x == 1"}}, END)
+	})
+}
@@ -61,6 +61,40 @@ Use conditional edges for decision points:
 The router function returns the ID of the next node to execute.
 Invalid return values (referencing non-existent nodes) cause runtime errors.
 
+Simple routes can be written as expr conditions over the state's JSON
+fields instead:
+
+	graph.AddConditionalEdgeExpr("review", []flowgraph.ExprRoute{
+	    {When: "approved == true", To: "publish"},
+	}, "revise")
+
+WeightedRouter splits runs between targets by weight, drawing from
+Context.Rand so WithRandSeed makes the split reproducible:
+
+	graph.AddConditionalEdge("checkout", flowgraph.WeightedRouter[State](map[string]int{
+	    "checkoutV1": 90,
+	    "checkoutV2": 10,
+	}))
+
+# Declarative Graphs
+
+LoadDefinition builds a graph from a YAML or JSON document that names
+its nodes by registry key and routes with expr conditions:
+
+	nodes := registry.New[string, flowgraph.NodeFunc[State]]()
+	nodes.Register("write-draft", writeDraft)
+	nodes.Register("review", review)
+
+	graph, err := flowgraph.LoadDefinition(data, nodes)
+	if err != nil {
+	    return err
+	}
+	compiled, err := graph.Compile()
+
+ParseDefinition reads the same document from an io.Reader into a
+GraphDefinition, and BuildDefinition resolves and compiles it, optionally
+evaluating conditions against a custom state-to-map function.
+
 # Loops
 
 Create loops by having conditional edges that return to earlier nodes:
@@ -94,26 +128,28 @@ Enable crash recovery with checkpointing:
 	// Resume after crash
 	result, err = compiled.Resume(ctx, store, "run-123")
 
-Checkpoints are saved after each successful node execution.
-When resuming, execution continues from the node after the last checkpoint.
+Checkpoints are saved after each successful node execution, or after
+every nth with WithCheckpointInterval(n).
+When resuming, execution continues from the node after the last checkpoint,
+so nodes that completed after it run again and should be idempotent.
 
-# LLM Integration
+State is serialized as JSON by default; WithCheckpointCodec selects
+another Codec, such as GobCodec, and each checkpoint records the codec
+that wrote it.
 
-Use LLM clients via Go's context.WithValue pattern:
+A node can pause the run for human input by returning Interrupt. Run then
+checkpoints the state the node received and returns an *InterruptError;
+Resume executes the node again, typically with the input supplied through
+WithStateOverride.
 
-	// Context key for LLM client injection
-	type llmKey struct{}
-	func WithLLM(ctx context.Context, c claude.Client) context.Context {
-	    return context.WithValue(ctx, llmKey{}, c)
-	}
-	func LLM(ctx context.Context) claude.Client {
-	    if c, ok := ctx.Value(llmKey{}).(claude.Client); ok { return c }
-	    return nil
-	}
+# LLM Integration
+
+Configure an LLM client on the context with WithLLM and call it from
+nodes through ctx.LLM():
 
 	// In a node:
 	func generateSpec(ctx flowgraph.Context, s State) (State, error) {
-	    client := LLM(ctx) // Access via context.Value
+	    client := ctx.LLM()
 	    if client == nil {
 	        return s, fmt.Errorf("LLM client not configured")
 	    }
@@ -127,10 +163,14 @@ Use LLM clients via Go's context.WithValue pattern:
 	    return s, nil
 	}
 
-	// Configure client and inject via context
 	client := claude.NewClaudeCLI(...)
-	baseCtx := WithLLM(context.Background(), client)
-	ctx := flowgraph.NewContext(baseCtx)
+	ctx := flowgraph.NewContext(context.Background(), flowgraph.WithLLM(client))
+
+During a run, the CostUSD of every completion made through ctx.LLM() is
+summed into ctx.TotalCostUSD(). WithRunBudgetUSD caps that total: once it
+is reached, the next LLM call fails with a *BudgetExceededError:
+
+	result, err := compiled.Run(ctx, state, flowgraph.WithRunBudgetUSD(5.00))
 
 This keeps flowgraph decoupled from specific LLM implementations while
 supporting Claude CLI with JSON output, token tracking, and budget controls.
@@ -149,8 +189,22 @@ Enable logging, metrics, and tracing:
 
 Logs include structured fields: run_id, node_id, duration_ms, attempt.
 OpenTelemetry metrics: flowgraph.node.executions, flowgraph.node.latency_ms, etc.
+WithMetricsCollector sends the same metrics to any MetricsCollector instead.
 OpenTelemetry tracing: flowgraph.run > flowgraph.node.{id} spans.
 
+RunWithTrace also returns an in-memory Trace of the nodes executed, with
+their timing, attempts, and routing decisions, for test assertions and
+timelines.
+
+While a run is in progress, Query returns its latest committed state and
+current node by run ID, without interrupting it.
+
+Graph.Use wraps every node in NodeMiddleware for cross-cutting concerns
+such as authorization or state logging; RecoveryMiddleware and
+TimingMiddleware are built in:
+
+	graph.Use(flowgraph.RecoveryMiddleware[State]())
+
 # Error Handling
 
 Errors include context about which node failed:
@@ -168,16 +222,37 @@ Errors include context about which node failed:
 
 Panics in nodes are recovered and converted to PanicError with stack trace.
 
+Nodes added with AddCompensatingNode carry an undo action. Under
+WithGraphCompensation a failed run compensates every completed
+compensating node in reverse execution order and returns a
+CompensationError wrapping the original error:
+
+	graph.AddCompensatingNode("charge", charge, refund)
+	_, err := compiled.Run(ctx, order, flowgraph.WithGraphCompensation())
+
+To handle a node's failure inside the graph instead, add an error edge.
+When the node returns an error, execution continues at the target, which
+reads the error with Context.LastError. Panics are only routed under
+WithErrorEdgeCatchesPanics:
+
+	graph.AddErrorEdge("charge", "notifyFailure")
+
+WithNodeRetry repeats a single failing node. WithRunRetry instead reruns
+the whole graph from its entry point with the initial state, under a
+derived run ID per attempt, when a run fails with a retryable error.
+
 # Thread Safety
 
   - Graph[S] is NOT safe for concurrent use during construction
   - CompiledGraph[S] IS safe for concurrent use (immutable)
   - Context IS safe for concurrent use
   - CheckpointStore implementations are safe for concurrent use
+  - Scratchpad (Context.BranchScratch) is safe for concurrent use by the
+    branches of one fork/join
 
 # Subpackages
 
-  - checkpoint: Checkpoint storage (memory, SQLite)
+  - checkpoint: Checkpoint storage (memory, SQLite, Redis)
   - llm: LLM client interface and implementations
   - observability: Logging, metrics, and tracing helpers
 */
@@ -0,0 +1,51 @@
+package flowgraph
+
+import (
+	"fmt"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/registry"
+)
+
+// NodeRegistry maps factory keys to node functions for dynamic nodes.
+type NodeRegistry[S any] = registry.Registry[string, NodeFunc[S]]
+
+// WithNodeRegistry sets the registry used to resolve dynamic nodes added
+// with Graph.AddDynamicNode. The registry's state type must match the graph's.
+//
+// Example:
+//
+//	nodes := registry.New[string, flowgraph.NodeFunc[MyState]]()
+//	nodes.Register("fetch-http", fetchHTTP)
+//
+//	result, err := compiled.Run(ctx, state, flowgraph.WithNodeRegistry(nodes))
+func WithNodeRegistry[S any](reg *NodeRegistry[S]) RunOption {
+	return func(c *runConfig) {
+		c.nodeRegistry = reg
+	}
+}
+
+// resolveDynamicNode looks up a node function in the configured registry.
+func resolveDynamicNode[S any](reg any, factoryKey string) (NodeFunc[S], error) {
+	if reg == nil {
+		return nil, ErrNodeRegistryMissing
+	}
+
+	typed, ok := reg.(*NodeRegistry[S])
+	if !ok || typed == nil {
+		return nil, fmt.Errorf("%w: registry type %T does not match graph state type", ErrNodeRegistryMissing, reg)
+	}
+
+	fn, ok := typed.Get(factoryKey)
+	if !ok || fn == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNodeFactoryNotFound, factoryKey)
+	}
+
+	return fn, nil
+}
+
+// unresolvedDynamicNode is the placeholder stored for dynamic nodes.
+// The executor resolves the real function before execution, so this only
+// runs if a dynamic node is invoked outside the executor.
+func unresolvedDynamicNode[S any](ctx Context, state S) (S, error) {
+	return state, ErrNodeRegistryMissing
+}
@@ -0,0 +1,133 @@
+package flowgraph
+
+import (
+	"testing"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAddDynamicNode_ResolvedFromRegistry tests executing a node resolved from a registry.
+func TestAddDynamicNode_ResolvedFromRegistry(t *testing.T) {
+	nodes := registry.New[string, NodeFunc[Counter]]()
+	nodes.Register("double", func(ctx Context, s Counter) (Counter, error) {
+		s.Value *= 2
+		return s, nil
+	})
+
+	graph := NewGraph[Counter]().
+		AddNode("inc", increment).
+		AddDynamicNode("plugin", "double").
+		AddEdge("inc", "plugin").
+		AddEdge("plugin", END).
+		SetEntry("inc")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+	assert.True(t, compiled.HasNode("plugin"))
+
+	result, err := compiled.Run(testCtx(), Counter{Value: 2}, WithNodeRegistry(nodes))
+
+	require.NoError(t, err)
+	assert.Equal(t, 6, result.Value)
+}
+
+// TestAddDynamicNode_ResolvedPerRun tests that registry changes apply to later runs.
+func TestAddDynamicNode_ResolvedPerRun(t *testing.T) {
+	nodes := registry.New[string, NodeFunc[Counter]]()
+	nodes.Register("impl", increment)
+
+	graph := NewGraph[Counter]().
+		AddDynamicNode("plugin", "impl").
+		AddEdge("plugin", END).
+		SetEntry("plugin")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), Counter{}, WithNodeRegistry(nodes))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Value)
+
+	nodes.Register("impl", func(ctx Context, s Counter) (Counter, error) {
+		s.Value += 100
+		return s, nil
+	})
+
+	result, err = compiled.Run(testCtx(), Counter{}, WithNodeRegistry(nodes))
+	require.NoError(t, err)
+	assert.Equal(t, 100, result.Value)
+}
+
+// TestAddDynamicNode_MissingFactory_Error tests the error when the factory key is absent.
+func TestAddDynamicNode_MissingFactory_Error(t *testing.T) {
+	nodes := registry.New[string, NodeFunc[Counter]]()
+
+	graph := NewGraph[Counter]().
+		AddDynamicNode("plugin", "not-registered").
+		AddEdge("plugin", END).
+		SetEntry("plugin")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), Counter{}, WithNodeRegistry(nodes))
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNodeFactoryNotFound)
+	assert.Contains(t, err.Error(), "not-registered")
+
+	var nodeErr *NodeError
+	require.ErrorAs(t, err, &nodeErr)
+	assert.Equal(t, "plugin", nodeErr.NodeID)
+	assert.Equal(t, "resolve", nodeErr.Op)
+}
+
+// TestAddDynamicNode_NoRegistry_Error tests the error when no registry is configured.
+func TestAddDynamicNode_NoRegistry_Error(t *testing.T) {
+	graph := NewGraph[Counter]().
+		AddDynamicNode("plugin", "impl").
+		AddEdge("plugin", END).
+		SetEntry("plugin")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), Counter{})
+
+	assert.ErrorIs(t, err, ErrNodeRegistryMissing)
+}
+
+// TestAddDynamicNode_RegistryTypeMismatch_Error tests a registry for a different state type.
+func TestAddDynamicNode_RegistryTypeMismatch_Error(t *testing.T) {
+	nodes := registry.New[string, NodeFunc[State]]()
+	nodes.Register("impl", passthrough[State])
+
+	graph := NewGraph[Counter]().
+		AddDynamicNode("plugin", "impl").
+		AddEdge("plugin", END).
+		SetEntry("plugin")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), Counter{}, WithNodeRegistry(nodes))
+
+	assert.ErrorIs(t, err, ErrNodeRegistryMissing)
+}
+
+// TestAddDynamicNode_Panics tests builder validation for dynamic nodes.
+func TestAddDynamicNode_Panics(t *testing.T) {
+	assert.PanicsWithValue(t, "flowgraph: factory key cannot be empty", func() {
+		NewGraph[Counter]().AddDynamicNode("plugin", "")
+	})
+	assert.PanicsWithValue(t, "flowgraph: node ID cannot be empty", func() {
+		NewGraph[Counter]().AddDynamicNode("", "impl")
+	})
+	assert.PanicsWithValue(t, "flowgraph: duplicate node ID: plugin", func() {
+		NewGraph[Counter]().
+			AddNode("plugin", increment).
+			AddDynamicNode("plugin", "impl")
+	})
+}
@@ -0,0 +1,66 @@
+package flowgraph
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// encryptionVersion identifies the layout of encrypted checkpoint state:
+// version byte, AES-GCM nonce, then ciphertext.
+const encryptionVersion byte = 1
+
+// newCheckpointCipher creates an AES-GCM cipher for checkpoint encryption.
+// Panics if key is not 16, 24, or 32 bytes.
+func newCheckpointCipher(key []byte) cipher.AEAD {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		panic("flowgraph: checkpoint encryption key must be 16, 24, or 32 bytes")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(fmt.Sprintf("flowgraph: checkpoint encryption: %v", err))
+	}
+	return aead
+}
+
+// encryptState seals data with a fresh random nonce.
+func encryptState(aead cipher.AEAD, data []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := make([]byte, 0, 1+len(nonce)+len(data)+aead.Overhead())
+	sealed = append(sealed, encryptionVersion)
+	sealed = append(sealed, nonce...)
+	sealed = aead.Seal(sealed, nonce, data, nil)
+	return wrapState(encryptedStateMagic, sealed), nil
+}
+
+// decryptState opens state sealed by encryptState.
+// State without the encryption header is returned unchanged, so
+// checkpoints written without encryption still load.
+func decryptState(aead cipher.AEAD, data []byte) ([]byte, error) {
+	sealed, ok, err := unwrapState(encryptedStateMagic, data)
+	if !ok {
+		return data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if aead == nil {
+		return nil, errors.New("checkpoint is encrypted but no key was configured")
+	}
+	if len(sealed) < 1+aead.NonceSize() {
+		return nil, errors.New("encrypted state is truncated")
+	}
+	if sealed[0] != encryptionVersion {
+		return nil, fmt.Errorf("unsupported encryption version %d", sealed[0])
+	}
+
+	nonce := sealed[1 : 1+aead.NonceSize()]
+	return aead.Open(nil, nonce, sealed[1+aead.NonceSize():], nil)
+}
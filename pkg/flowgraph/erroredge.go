@@ -0,0 +1,86 @@
+package flowgraph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AddErrorEdge routes failures of node from to node to. When from returns
+// an error, the run continues at to instead of returning the error; to
+// receives the state from returned and can read the error with
+// Context.LastError. The target can be a node ID or flowgraph.END, which
+// ends the run successfully. Nodes without an error edge fail the run as
+// before.
+// Returns the graph for method chaining.
+//
+// The error edge is taken after the node's retries are exhausted, and
+// applies to errors from the node function, its timeout, or its fallback.
+// It is not taken when the run is cancelled or the state exceeds
+// WithMaxStateSize, or for panics unless WithErrorEdgeCatchesPanics is
+// set. Errors from conditional edges, fan-outs, and fork/join branches
+// are not routed; a failed fork/join still returns a *ForkJoinError.
+//
+// Edge validation happens at Compile() time. Panics if from already has an
+// error edge.
+//
+// Example:
+//
+//	graph.AddNode("charge", charge).
+//	    AddNode("refund", refund).
+//	    AddEdge("charge", "ship").
+//	    AddErrorEdge("charge", "refund").
+//	    AddEdge("refund", flowgraph.END)
+//
+//	func refund(ctx flowgraph.Context, s Order) (Order, error) {
+//	    s.Failure = ctx.LastError().Error()
+//	    return s, nil
+//	}
+func (g *Graph[S]) AddErrorEdge(from, to string) *Graph[S] {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, exists := g.errorEdges[from]; exists {
+		panic(fmt.Sprintf("flowgraph: duplicate error edge from node: %s", from))
+	}
+
+	g.errorEdges[from] = to
+	return g
+}
+
+// ErrorEdge returns the target of the node's error edge, if it has one.
+func (cg *CompiledGraph[S]) ErrorEdge(id string) (string, bool) {
+	to, exists := cg.errorEdges[id]
+	return to, exists
+}
+
+// errorEdgeTarget returns where execution continues after nodeID failed
+// with err, or false if the error should fail the run.
+func (cg *CompiledGraph[S]) errorEdgeTarget(nodeID string, err error, cfg *runConfig) (string, bool) {
+	to, exists := cg.errorEdges[nodeID]
+	if !exists {
+		return "", false
+	}
+
+	var cancelErr *CancellationError
+	var sizeErr *StateSizeError
+	if errors.As(err, &cancelErr) || errors.As(err, &sizeErr) {
+		return "", false
+	}
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) && !cfg.errorEdgeCatchesPanics {
+		return "", false
+	}
+
+	return to, true
+}
+
+// withLastError returns ctx with the error that routed execution to its
+// node. Contexts not created by NewContext are returned unchanged.
+func withLastError(ctx Context, err error) Context {
+	ec, ok := ctx.(*executionContext)
+	if !ok {
+		return ctx
+	}
+	return ec.derive(func(d *executionContext) { d.lastErr = err })
+}
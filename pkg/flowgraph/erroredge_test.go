@@ -0,0 +1,156 @@
+package flowgraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildErrorEdgeGraph builds work -> done -> END with an error edge from
+// work to recover, which records the routed error in Output.
+func buildErrorEdgeGraph(work NodeFunc[State]) *Graph[State] {
+	return NewGraph[State]().
+		AddNode("work", work).
+		AddNode("done", func(ctx Context, s State) (State, error) {
+			s.Done = true
+			return s, nil
+		}).
+		AddNode("recover", func(ctx Context, s State) (State, error) {
+			if err := ctx.LastError(); err != nil {
+				s.Output = err.Error()
+			}
+			return s, nil
+		}).
+		AddEdge("work", "done").
+		AddEdge("done", END).
+		AddErrorEdge("work", "recover").
+		AddEdge("recover", END).
+		SetEntry("work")
+}
+
+// TestAddErrorEdge_RoutesFailure tests that a failing node continues at its error target.
+func TestAddErrorEdge_RoutesFailure(t *testing.T) {
+	boom := errors.New("boom")
+	compiled, err := buildErrorEdgeGraph(func(ctx Context, s State) (State, error) {
+		s.Step = 1
+		return s, boom
+	}).Compile()
+	require.NoError(t, err)
+
+	to, ok := compiled.ErrorEdge("work")
+	assert.True(t, ok)
+	assert.Equal(t, "recover", to)
+
+	result, err := compiled.Run(testCtx(), State{})
+
+	require.NoError(t, err)
+	assert.False(t, result.Done)
+	assert.Equal(t, 1, result.Step, "target should receive the failed node's state")
+	assert.Contains(t, result.Output, "boom")
+}
+
+// TestAddErrorEdge_SuccessUnchanged tests that the error edge is ignored when the node succeeds.
+func TestAddErrorEdge_SuccessUnchanged(t *testing.T) {
+	compiled, err := buildErrorEdgeGraph(passthrough[State]).Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), State{})
+
+	require.NoError(t, err)
+	assert.True(t, result.Done)
+	assert.Empty(t, result.Output)
+}
+
+// TestAddErrorEdge_LastErrorOnlyOnTarget tests that LastError is cleared after the target runs.
+func TestAddErrorEdge_LastErrorOnlyOnTarget(t *testing.T) {
+	var seen []error
+	compiled, err := NewGraph[State]().
+		AddNode("work", makeFailingNode(errors.New("boom"))).
+		AddNode("recover", func(ctx Context, s State) (State, error) {
+			seen = append(seen, ctx.LastError())
+			return s, nil
+		}).
+		AddNode("after", func(ctx Context, s State) (State, error) {
+			seen = append(seen, ctx.LastError())
+			return s, nil
+		}).
+		AddEdge("work", END).
+		AddErrorEdge("work", "recover").
+		AddEdge("recover", "after").
+		AddEdge("after", END).
+		SetEntry("work").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{})
+
+	require.NoError(t, err)
+	require.Len(t, seen, 2)
+	var nodeErr *NodeError
+	assert.True(t, errors.As(seen[0], &nodeErr))
+	assert.Equal(t, "work", nodeErr.NodeID)
+	assert.Nil(t, seen[1])
+}
+
+// TestAddErrorEdge_PanicNotRouted tests that panics fail the run by default.
+func TestAddErrorEdge_PanicNotRouted(t *testing.T) {
+	compiled, err := buildErrorEdgeGraph(makePanicNode("kaboom")).Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{})
+
+	var panicErr *PanicError
+	require.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "work", panicErr.NodeID)
+}
+
+// TestAddErrorEdge_CatchesPanics tests that WithErrorEdgeCatchesPanics routes panics.
+func TestAddErrorEdge_CatchesPanics(t *testing.T) {
+	compiled, err := buildErrorEdgeGraph(makePanicNode("kaboom")).Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), State{}, WithErrorEdgeCatchesPanics())
+
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "kaboom")
+}
+
+// TestAddErrorEdge_ToEnd tests that an error edge to END ends the run successfully.
+func TestAddErrorEdge_ToEnd(t *testing.T) {
+	compiled, err := NewGraph[State]().
+		AddNode("work", makeFailingNode(errors.New("boom"))).
+		AddEdge("work", END).
+		AddErrorEdge("work", END).
+		SetEntry("work").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{})
+
+	assert.NoError(t, err)
+}
+
+// TestAddErrorEdge_UnknownTarget tests that Compile rejects error edges to unknown nodes.
+func TestAddErrorEdge_UnknownTarget(t *testing.T) {
+	_, err := NewGraph[State]().
+		AddNode("work", passthrough[State]).
+		AddEdge("work", END).
+		AddErrorEdge("work", "missing").
+		SetEntry("work").
+		Compile()
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+	assert.Contains(t, err.Error(), "error edge target 'missing'")
+}
+
+// TestAddErrorEdge_DuplicatePanics tests that a second error edge from a node panics.
+func TestAddErrorEdge_DuplicatePanics(t *testing.T) {
+	assert.PanicsWithValue(t, "flowgraph: duplicate error edge from node: work", func() {
+		NewGraph[State]().
+			AddErrorEdge("work", "a").
+			AddErrorEdge("work", "b")
+	})
+}
@@ -19,6 +19,15 @@ var (
 
 	// ErrNoPathToEnd indicates no path exists from the entry point to END.
 	ErrNoPathToEnd = errors.New("no path to END from entry")
+
+	// ErrEntryConflict indicates both SetEntry() and SetEntrySelector() were called.
+	ErrEntryConflict = errors.New("entry point and entry selector are mutually exclusive")
+
+	// ErrInvalidFanOut indicates a dynamic fan-out is misconfigured.
+	ErrInvalidFanOut = errors.New("invalid dynamic fan-out")
+
+	// ErrLintWarnings indicates CompileStrict found lint warnings.
+	ErrLintWarnings = errors.New("graph has lint warnings")
 )
 
 // Sentinel errors for execution.
@@ -34,6 +43,33 @@ var (
 
 	// ErrRouterTargetNotFound indicates a router function returned an unknown node ID.
 	ErrRouterTargetNotFound = errors.New("router returned unknown node")
+
+	// ErrNodeRegistryMissing indicates a dynamic node ran without a matching node registry.
+	ErrNodeRegistryMissing = errors.New("node registry not configured")
+
+	// ErrNodeFactoryNotFound indicates a dynamic node's factory key is absent from the registry.
+	ErrNodeFactoryNotFound = errors.New("node factory not found in registry")
+
+	// ErrInvalidEntrySelection indicates an entry selector returned an empty or unknown node ID.
+	ErrInvalidEntrySelection = errors.New("entry selector returned invalid node")
+
+	// ErrInvalidFanOutTarget indicates a fan-out function returned an unusable branch.
+	ErrInvalidFanOutTarget = errors.New("fan-out returned invalid branch")
+
+	// ErrStateTooLarge indicates the state exceeded the limit set by WithMaxStateSize.
+	ErrStateTooLarge = errors.New("state exceeds maximum size")
+
+	// ErrBudgetExceeded indicates a run used up its WithTimeBudget,
+	// WithCostBudget, or WithRunBudgetUSD.
+	ErrBudgetExceeded = errors.New("run budget exceeded")
+
+	// ErrSuspended indicates a run checkpointed and stopped early under
+	// WithSuspendOnBudget. Resume the run to continue it.
+	ErrSuspended = errors.New("run suspended")
+
+	// ErrInterrupted indicates a node returned Interrupt to pause the run
+	// for external input. Resume the run to continue it.
+	ErrInterrupted = errors.New("run interrupted")
 )
 
 // Sentinel errors for checkpointing and resume.
@@ -41,6 +77,10 @@ var (
 	// ErrRunIDRequired indicates checkpointing was enabled without a run ID.
 	ErrRunIDRequired = errors.New("run ID required for checkpointing")
 
+	// ErrSuspendRequiresCheckpointing indicates WithSuspendOnBudget was used
+	// without WithCheckpointing, so a suspended run could not be resumed.
+	ErrSuspendRequiresCheckpointing = errors.New("suspend on budget requires checkpointing")
+
 	// ErrSerializeState indicates state serialization failed.
 	ErrSerializeState = errors.New("failed to serialize state")
 
@@ -53,8 +93,19 @@ var (
 	// ErrInvalidResumeNode indicates the resume node doesn't exist in the graph.
 	ErrInvalidResumeNode = errors.New("invalid resume node")
 
+	// ErrResumeAborted indicates a WithBeforeResume hook rejected the resume.
+	ErrResumeAborted = errors.New("resume aborted")
+
+	// ErrNoInitialCheckpoint indicates CompareRun was given a run recorded
+	// without WithInitialCheckpoint, so its initial state is unknown.
+	ErrNoInitialCheckpoint = errors.New("run has no initial checkpoint")
+
 	// ErrCheckpointVersionMismatch indicates the checkpoint version is incompatible.
 	ErrCheckpointVersionMismatch = errors.New("checkpoint version mismatch")
+
+	// ErrCheckpointDecryption indicates encrypted checkpoint state could not
+	// be decrypted, typically because the key is missing or wrong.
+	ErrCheckpointDecryption = errors.New("failed to decrypt checkpoint state")
 )
 
 // CheckpointError wraps errors from checkpoint operations.
@@ -161,6 +212,120 @@ func (e *RouterError) Unwrap() error {
 	return e.Err
 }
 
+// StateSizeError reports that state grew past the WithMaxStateSize limit.
+type StateSizeError struct {
+	// NodeID is the node after which the limit was exceeded.
+	NodeID string
+	// Size is the JSON-encoded size of the state in bytes.
+	Size int
+	// Limit is the configured maximum size in bytes.
+	Limit int
+}
+
+// Error implements the error interface.
+func (e *StateSizeError) Error() string {
+	return fmt.Sprintf("state size %d bytes exceeds limit %d after node %s", e.Size, e.Limit, e.NodeID)
+}
+
+// Unwrap returns ErrStateTooLarge for errors.Is support.
+func (e *StateSizeError) Unwrap() error {
+	return ErrStateTooLarge
+}
+
+// BudgetError reports that a run exhausted its time or cost budget.
+type BudgetError struct {
+	// Budget is "time" or "cost".
+	Budget string
+	// NodeID is the node that would have executed next.
+	NodeID string
+	// Used is the amount consumed: seconds for time, cost units for cost.
+	Used float64
+	// Limit is the configured budget in the same units as Used.
+	Limit float64
+}
+
+// Error implements the error interface.
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("%s budget exceeded before node %s: used %g of %g", e.Budget, e.NodeID, e.Used, e.Limit)
+}
+
+// Unwrap returns ErrBudgetExceeded for errors.Is support.
+func (e *BudgetError) Unwrap() error {
+	return ErrBudgetExceeded
+}
+
+// BudgetExceededError reports that an LLM call was refused because the run
+// had already spent its WithRunBudgetUSD limit.
+type BudgetExceededError struct {
+	// NodeID is the node that made the refused call.
+	NodeID string
+	// SpentUSD is the LLM spend of the run so far.
+	SpentUSD float64
+	// LimitUSD is the configured budget.
+	LimitUSD float64
+}
+
+// Error implements the error interface.
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("LLM budget exceeded in node %s: spent $%g of $%g", e.NodeID, e.SpentUSD, e.LimitUSD)
+}
+
+// Unwrap returns ErrBudgetExceeded for errors.Is support.
+func (e *BudgetExceededError) Unwrap() error {
+	return ErrBudgetExceeded
+}
+
+// SuspendedError reports that a run voluntarily stopped at a node boundary
+// after crossing its WithSuspendOnBudget threshold. The latest checkpoint
+// is resumable: call Resume with the same run ID to continue from NodeID.
+type SuspendedError struct {
+	// RunID identifies the checkpointed run to resume.
+	RunID string
+	// NodeID is the node that will execute first on resume.
+	NodeID string
+	// Budget is the budget that crossed the threshold: "time" or "cost".
+	Budget string
+	// Fraction is the portion of that budget used when suspending.
+	Fraction float64
+	// State is the state at suspension (can type-assert to the actual type).
+	State any
+}
+
+// Error implements the error interface.
+func (e *SuspendedError) Error() string {
+	return fmt.Sprintf("run %s suspended before node %s: %.0f%% of %s budget used",
+		e.RunID, e.NodeID, e.Fraction*100, e.Budget)
+}
+
+// Unwrap returns ErrSuspended for errors.Is support.
+func (e *SuspendedError) Unwrap() error {
+	return ErrSuspended
+}
+
+// InterruptError reports that a node returned Interrupt. When checkpointing
+// is enabled, the run is checkpointed with the state the node received:
+// call Resume with the same run ID to execute NodeID again.
+type InterruptError struct {
+	// RunID identifies the run to resume.
+	RunID string
+	// NodeID is the node that interrupted; it executes first on resume.
+	NodeID string
+	// Reason is the reason passed to Interrupt.
+	Reason string
+	// State is the state NodeID received (can type-assert to the actual type).
+	State any
+}
+
+// Error implements the error interface.
+func (e *InterruptError) Error() string {
+	return fmt.Sprintf("run %s interrupted at node %s: %s", e.RunID, e.NodeID, e.Reason)
+}
+
+// Unwrap returns ErrInterrupted for errors.Is support.
+func (e *InterruptError) Unwrap() error {
+	return ErrInterrupted
+}
+
 // MaxIterationsError provides context when the loop limit is exceeded.
 // It includes the state at termination for inspection.
 type MaxIterationsError struct {
@@ -3,6 +3,7 @@ package errors
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"testing"
@@ -561,3 +562,92 @@ func TestNewRetryConfig(t *testing.T) {
 		t.Errorf("Jitter = %f, want 0.2", cfg.Jitter)
 	}
 }
+
+func TestHumanInterventionError_ErrorsAs(t *testing.T) {
+	orig := errors.New("confidence too low")
+	hie := NewHumanIntervention("Deploy to production?", "approve", "reject").
+		WithContext(map[string]any{"env": "prod", "replicas": 3}).
+		WithOriginal(orig)
+	wrapped := fmt.Errorf("node deploy: %w", hie)
+
+	var got *HumanInterventionError
+	if !errors.As(wrapped, &got) {
+		t.Fatal("errors.As failed to find HumanInterventionError")
+	}
+	if got.Question != "Deploy to production?" {
+		t.Errorf("Question = %q", got.Question)
+	}
+	if len(got.Options) != 2 || got.Options[0] != "approve" || got.Options[1] != "reject" {
+		t.Errorf("Options = %v", got.Options)
+	}
+	if got.Context["env"] != "prod" || got.Context["replicas"] != 3 {
+		t.Errorf("Context = %v", got.Context)
+	}
+	if got.ResolutionID == "" {
+		t.Error("ResolutionID should be generated")
+	}
+	if !errors.Is(wrapped, orig) {
+		t.Error("errors.Is should find the original error")
+	}
+	if Categorize(wrapped) != CategoryHumanRequired {
+		t.Errorf("Categorize = %v, want human_required", Categorize(wrapped))
+	}
+
+	other := NewHumanIntervention("Deploy to production?")
+	if other.ResolutionID == got.ResolutionID {
+		t.Error("ResolutionIDs should be unique")
+	}
+}
+
+func TestHumanInterventionError_Resolve(t *testing.T) {
+	hie := NewHumanIntervention("Approve?", "yes", "no")
+
+	tests := []struct {
+		name    string
+		res     HumanResolution
+		wantErr error
+	}{
+		{"valid choice", HumanResolution{ResolutionID: hie.ResolutionID, Choice: "yes"}, nil},
+		{"wrong id", HumanResolution{ResolutionID: "other", Choice: "yes"}, ErrResolutionMismatch},
+		{"invalid choice", HumanResolution{ResolutionID: hie.ResolutionID, Choice: "maybe"}, ErrInvalidChoice},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := hie.Resolve(tt.res)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Resolve() = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Resolve() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	freeForm := NewHumanIntervention("Why did this fail?")
+	if err := freeForm.Resolve(HumanResolution{ResolutionID: freeForm.ResolutionID, Choice: "anything"}); err != nil {
+		t.Errorf("free-form Resolve() = %v, want nil", err)
+	}
+}
+
+func TestHumanResolution_Payload(t *testing.T) {
+	a := NewHumanIntervention("A?", "ok")
+	b := NewHumanIntervention("B?", "ok")
+	res := HumanResolution{ResolutionID: b.ResolutionID, Choice: "ok", Comment: "looks good"}
+
+	decoded, ok := ResolutionFromPayload(res.Payload())
+	if !ok {
+		t.Fatal("ResolutionFromPayload returned false")
+	}
+	if decoded != res {
+		t.Errorf("decoded = %+v, want %+v", decoded, res)
+	}
+	if got := FindIntervention([]*HumanInterventionError{a, b}, decoded); got != b {
+		t.Errorf("FindIntervention returned %v, want b", got)
+	}
+	if got := FindIntervention([]*HumanInterventionError{a}, decoded); got != nil {
+		t.Errorf("FindIntervention returned %v, want nil", got)
+	}
+	if _, ok := ResolutionFromPayload(map[string]any{ChoiceKey: "ok"}); ok {
+		t.Error("payload without resolution_id should not decode")
+	}
+}
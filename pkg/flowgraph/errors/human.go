@@ -0,0 +1,116 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Sentinel errors for resolving human interventions.
+var (
+	// ErrResolutionMismatch indicates a resolution targets a different intervention.
+	ErrResolutionMismatch = errors.New("resolution does not match intervention")
+
+	// ErrInvalidChoice indicates a resolution picked a choice not offered.
+	ErrInvalidChoice = errors.New("choice is not one of the offered options")
+)
+
+// Payload keys used by HumanResolution.Payload and ResolutionFromPayload.
+const (
+	ResolutionIDKey = "resolution_id"
+	ChoiceKey       = "choice"
+	CommentKey      = "comment"
+)
+
+// NewHumanIntervention creates a HumanInterventionError with a fresh
+// ResolutionID.
+//
+// Example:
+//
+//	return state, errors.NewHumanIntervention("Deploy to production?", "approve", "reject").
+//	    WithContext(map[string]any{"diff_url": url})
+func NewHumanIntervention(question string, options ...string) *HumanInterventionError {
+	return &HumanInterventionError{
+		Question:     question,
+		Options:      options,
+		ResolutionID: uuid.New().String(),
+	}
+}
+
+// WithContext sets data to display alongside the question.
+func (e *HumanInterventionError) WithContext(ctx map[string]any) *HumanInterventionError {
+	e.Context = ctx
+	return e
+}
+
+// WithOriginal sets the error that triggered the intervention.
+func (e *HumanInterventionError) WithOriginal(err error) *HumanInterventionError {
+	e.Original = err
+	return e
+}
+
+// HumanResolution is a human's response to a HumanInterventionError.
+type HumanResolution struct {
+	// ResolutionID must equal the intervention's ResolutionID.
+	ResolutionID string
+
+	// Choice is the selected option, or free-form text if no options were offered.
+	Choice string
+
+	// Comment is optional free-form text from the human.
+	Comment string
+}
+
+// Payload encodes the resolution as a map, suitable for a signal payload.
+func (r HumanResolution) Payload() map[string]any {
+	return map[string]any{
+		ResolutionIDKey: r.ResolutionID,
+		ChoiceKey:       r.Choice,
+		CommentKey:      r.Comment,
+	}
+}
+
+// ResolutionFromPayload decodes a resolution from a map produced by
+// HumanResolution.Payload, e.g. a signal payload.
+// Returns false if the payload has no resolution ID.
+func ResolutionFromPayload(payload map[string]any) (HumanResolution, bool) {
+	id, _ := payload[ResolutionIDKey].(string)
+	if id == "" {
+		return HumanResolution{}, false
+	}
+	choice, _ := payload[ChoiceKey].(string)
+	comment, _ := payload[CommentKey].(string)
+	return HumanResolution{ResolutionID: id, Choice: choice, Comment: comment}, true
+}
+
+// Matches reports whether the resolution answers this intervention.
+func (e *HumanInterventionError) Matches(res HumanResolution) bool {
+	return e.ResolutionID != "" && e.ResolutionID == res.ResolutionID
+}
+
+// Resolve validates a resolution against this intervention.
+// Returns ErrResolutionMismatch if the resolution is for another
+// intervention, or ErrInvalidChoice if options were offered and the choice
+// is not among them.
+func (e *HumanInterventionError) Resolve(res HumanResolution) error {
+	if !e.Matches(res) {
+		return fmt.Errorf("%w: got %q, want %q", ErrResolutionMismatch, res.ResolutionID, e.ResolutionID)
+	}
+	if len(e.Options) > 0 && !slices.Contains(e.Options, res.Choice) {
+		return fmt.Errorf("%w: %q (options: %v)", ErrInvalidChoice, res.Choice, e.Options)
+	}
+	return nil
+}
+
+// FindIntervention returns the pending intervention that res answers.
+// Returns nil if none match.
+func FindIntervention(pending []*HumanInterventionError, res HumanResolution) *HumanInterventionError {
+	for _, e := range pending {
+		if e.Matches(res) {
+			return e
+		}
+	}
+	return nil
+}
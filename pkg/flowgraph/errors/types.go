@@ -54,10 +54,17 @@ func (e *TimeoutError) Error() string {
 }
 
 // HumanInterventionError indicates human input is required.
+//
+// Options lists the answers a human can pick from (empty means free-form).
+// Context carries data an approval UI can show alongside the question.
+// ResolutionID identifies this intervention so a later response can be
+// matched back to it; see NewHumanIntervention and Resolve.
 type HumanInterventionError struct {
-	Question string
-	Options  []string
-	Original error
+	Question     string
+	Options      []string
+	Context      map[string]any
+	ResolutionID string
+	Original     error
 }
 
 // Error implements the error interface.
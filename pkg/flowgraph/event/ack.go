@@ -0,0 +1,335 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AckConfig configures an AckableBus.
+type AckConfig struct {
+	// AckTimeout is how long a delivered event may stay unacknowledged
+	// before it is delivered again.
+	// Default: 30 seconds
+	AckTimeout time.Duration
+
+	// MaxDeliveries caps the deliveries of one event to one subscriber.
+	// An event still unacknowledged after that many is dropped and passed
+	// to OnExhausted.
+	// Default: 0 (redeliver until acknowledged)
+	MaxDeliveries int
+
+	// OnRedeliver is called before an unacknowledged event is delivered
+	// again. attempt is the number of the upcoming delivery, from 2.
+	OnRedeliver func(evt Event, subscriberID string, attempt int)
+
+	// OnExhausted is called when an event is dropped after MaxDeliveries
+	// unacknowledged deliveries, e.g. to move it to a dead letter queue.
+	OnExhausted func(evt Event, subscriberID string)
+}
+
+// DefaultAckConfig provides reasonable defaults.
+var DefaultAckConfig = AckConfig{
+	AckTimeout: 30 * time.Second,
+}
+
+// AckHandler processes a delivery on an acknowledged subscription. It
+// must call delivery.Ack once the event is handled, or the event is
+// delivered again after the ack timeout. Ack may be called after the
+// handler returns, e.g. from another goroutine.
+type AckHandler func(ctx context.Context, delivery *Delivery)
+
+// Delivery is one delivery of an event to an acknowledged subscription.
+type Delivery struct {
+	// Event is the delivered event.
+	Event Event
+
+	// Attempt is 1 for the first delivery and counts up on redelivery.
+	Attempt int
+
+	sub *AckSubscription
+}
+
+// Ack acknowledges the event, so it is not delivered again. Acking an
+// event that was already acknowledged or dropped is a no-op.
+func (d *Delivery) Ack() {
+	d.sub.ack(d.Event.ID())
+}
+
+// AckableBus wraps a Bus to give critical subscribers at-least-once
+// delivery. Subscribers added with SubscribeAck must acknowledge each
+// event, and events left unacknowledged past AckConfig.AckTimeout are
+// delivered again, so a subscriber that fails mid-event gets another
+// chance. Subscribe and SubscribeAll still create fire-and-forget
+// subscriptions on the wrapped bus.
+//
+// Events are tracked in flight per subscriber by event ID, so handlers
+// must tolerate seeing an event more than once. An event published again
+// while still in flight to a subscriber is not delivered twice.
+//
+// Example:
+//
+//	bus := event.NewAckableBus(event.NewBus(event.DefaultBusConfig), event.AckConfig{
+//	    AckTimeout:    10 * time.Second,
+//	    MaxDeliveries: 5,
+//	})
+//	bus.SubscribeAck([]string{"order.created"}, func(ctx context.Context, d *event.Delivery) {
+//	    if err := fulfil(ctx, d.Event); err != nil {
+//	        return // Not acked; redelivered after 10s
+//	    }
+//	    d.Ack()
+//	})
+type AckableBus struct {
+	Bus
+
+	config AckConfig
+	nextID atomic.Int64
+
+	mu   sync.Mutex
+	subs map[*AckSubscription]struct{}
+}
+
+// NewAckableBus wraps bus with acknowledged subscriptions.
+//
+// Panics if bus is nil.
+func NewAckableBus(bus Bus, config AckConfig) *AckableBus {
+	if bus == nil {
+		panic("event: bus cannot be nil")
+	}
+	if config.AckTimeout <= 0 {
+		config.AckTimeout = DefaultAckConfig.AckTimeout
+	}
+	return &AckableBus{
+		Bus:    bus,
+		config: config,
+		subs:   make(map[*AckSubscription]struct{}),
+	}
+}
+
+// SubscribeAck creates an acknowledged subscription for specific event
+// types. It returns nil if the wrapped bus refuses the subscription.
+func (b *AckableBus) SubscribeAck(types []string, handler AckHandler) *AckSubscription {
+	return b.subscribeAck(types, handler)
+}
+
+// SubscribeAllAck creates an acknowledged subscription for all events.
+// It returns nil if the wrapped bus refuses the subscription.
+func (b *AckableBus) SubscribeAllAck(handler AckHandler) *AckSubscription {
+	return b.subscribeAck(nil, handler)
+}
+
+func (b *AckableBus) subscribeAck(types []string, handler AckHandler) *AckSubscription {
+	if handler == nil {
+		panic("event: ack handler cannot be nil")
+	}
+
+	sub := &AckSubscription{
+		id:       fmt.Sprintf("ack-%d", b.nextID.Add(1)),
+		handler:  handler,
+		config:   b.config,
+		incoming: make(chan Event),
+		inFlight: make(map[string]*inFlightEvent),
+		done:     make(chan struct{}),
+		bus:      b,
+	}
+
+	// The bus handler hands events to run, so first deliveries and
+	// redeliveries reach the handler from one goroutine
+	forward := HandlerFunc(func(_ context.Context, evt Event) ([]Event, error) {
+		select {
+		case sub.incoming <- evt:
+		case <-sub.done:
+		}
+		return nil, nil
+	})
+
+	var inner Subscription
+	if len(types) == 0 {
+		inner = b.Bus.SubscribeAll(forward)
+	} else {
+		inner = b.Bus.Subscribe(types, forward)
+	}
+	if isNilSubscription(inner) {
+		return nil
+	}
+	sub.Subscription = inner
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	go sub.run()
+	return sub
+}
+
+// isNilSubscription reports whether a Bus returned no subscription,
+// including a typed nil pointer such as LocalBus returns.
+func isNilSubscription(s Subscription) bool {
+	if s == nil {
+		return true
+	}
+	sub, ok := s.(*subscription)
+	return ok && sub == nil
+}
+
+// Close stops all acknowledged subscriptions, discarding their in-flight
+// events, and closes the wrapped bus.
+func (b *AckableBus) Close() error {
+	b.mu.Lock()
+	subs := b.subs
+	b.subs = make(map[*AckSubscription]struct{})
+	b.mu.Unlock()
+
+	for sub := range subs {
+		sub.stop()
+	}
+	return b.Bus.Close()
+}
+
+// AckSubscription is a subscription whose events must be acknowledged.
+// Pause stops both new deliveries and redeliveries; events in flight
+// when it is paused are redelivered after Resume.
+type AckSubscription struct {
+	Subscription
+
+	id       string
+	handler  AckHandler
+	config   AckConfig
+	incoming chan Event
+	bus      *AckableBus
+
+	mu       sync.Mutex
+	inFlight map[string]*inFlightEvent
+	seq      int64
+
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// inFlightEvent is an event delivered but not yet acknowledged.
+type inFlightEvent struct {
+	evt      Event
+	seq      int64 // Orders redeliveries by first delivery
+	attempts int
+	deadline time.Time
+}
+
+// ID returns the subscriber ID passed to the AckConfig callbacks.
+func (s *AckSubscription) ID() string {
+	return s.id
+}
+
+// InFlight returns the number of events delivered to this subscriber and
+// not yet acknowledged.
+func (s *AckSubscription) InFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.inFlight)
+}
+
+// Unsubscribe removes the subscription and discards its in-flight events.
+func (s *AckSubscription) Unsubscribe() {
+	s.bus.mu.Lock()
+	delete(s.bus.subs, s)
+	s.bus.mu.Unlock()
+
+	s.stop()
+}
+
+func (s *AckSubscription) stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+		s.Subscription.Unsubscribe()
+	})
+}
+
+// run delivers incoming events and redelivers expired ones until the
+// subscription stops.
+func (s *AckSubscription) run() {
+	interval := max(s.config.AckTimeout/4, time.Millisecond)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case evt := <-s.incoming:
+			s.deliver(evt)
+		case now := <-ticker.C:
+			if !s.IsPaused() {
+				s.redeliverExpired(now)
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// deliver tracks evt as in flight and hands it to the handler, unless it
+// is already in flight.
+func (s *AckSubscription) deliver(evt Event) {
+	s.mu.Lock()
+	if _, exists := s.inFlight[evt.ID()]; exists {
+		s.mu.Unlock()
+		return
+	}
+	s.seq++
+	s.inFlight[evt.ID()] = &inFlightEvent{
+		evt:      evt,
+		seq:      s.seq,
+		attempts: 1,
+		deadline: time.Now().Add(s.config.AckTimeout),
+	}
+	s.mu.Unlock()
+
+	s.handler(context.Background(), &Delivery{Event: evt, Attempt: 1, sub: s})
+}
+
+// redeliverExpired delivers again, oldest first, each in-flight event
+// whose ack deadline has passed, dropping those out of deliveries.
+func (s *AckSubscription) redeliverExpired(now time.Time) {
+	var redeliver, exhausted []*inFlightEvent
+
+	s.mu.Lock()
+	for id, entry := range s.inFlight {
+		if now.Before(entry.deadline) {
+			continue
+		}
+		if s.config.MaxDeliveries > 0 && entry.attempts >= s.config.MaxDeliveries {
+			delete(s.inFlight, id)
+			exhausted = append(exhausted, entry)
+			continue
+		}
+		entry.attempts++
+		entry.deadline = now.Add(s.config.AckTimeout)
+		redeliver = append(redeliver, &inFlightEvent{evt: entry.evt, seq: entry.seq, attempts: entry.attempts})
+	}
+	s.mu.Unlock()
+
+	sortBySeq(exhausted)
+	for _, entry := range exhausted {
+		if s.config.OnExhausted != nil {
+			s.config.OnExhausted(entry.evt, s.id)
+		}
+	}
+
+	sortBySeq(redeliver)
+	for _, entry := range redeliver {
+		if s.config.OnRedeliver != nil {
+			s.config.OnRedeliver(entry.evt, s.id, entry.attempts)
+		}
+		s.handler(context.Background(), &Delivery{Event: entry.evt, Attempt: entry.attempts, sub: s})
+	}
+}
+
+func (s *AckSubscription) ack(eventID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inFlight, eventID)
+}
+
+func sortBySeq(entries []*inFlightEvent) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].seq < entries[j].seq })
+}
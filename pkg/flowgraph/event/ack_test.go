@@ -0,0 +1,230 @@
+package event_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+)
+
+type deliveryLog struct {
+	mu       sync.Mutex
+	attempts []int
+}
+
+func (l *deliveryLog) add(attempt int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.attempts = append(l.attempts, attempt)
+}
+
+func (l *deliveryLog) get() []int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]int(nil), l.attempts...)
+}
+
+func TestAckableBus_UnackedEventRedelivered(t *testing.T) {
+	var redelivered atomic.Int32
+	bus := event.NewAckableBus(event.NewBus(event.DefaultBusConfig), event.AckConfig{
+		AckTimeout: 20 * time.Millisecond,
+		OnRedeliver: func(evt event.Event, subscriberID string, attempt int) {
+			redelivered.Add(1)
+		},
+	})
+	defer bus.Close()
+
+	seen := &deliveryLog{}
+	sub := bus.SubscribeAck([]string{"order.created"}, func(ctx context.Context, d *event.Delivery) {
+		seen.add(d.Attempt)
+		if d.Attempt >= 2 {
+			d.Ack()
+		}
+	})
+	if sub == nil {
+		t.Fatal("expected subscription")
+	}
+
+	if err := bus.Publish(context.Background(), sampleEvent()); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	got := seen.get()
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("expected deliveries [1 2], got %v", got)
+	}
+	if redelivered.Load() != 1 {
+		t.Errorf("expected 1 OnRedeliver call, got %d", redelivered.Load())
+	}
+	if sub.InFlight() != 0 {
+		t.Errorf("expected nothing in flight, got %d", sub.InFlight())
+	}
+}
+
+func TestAckableBus_AckedEventNotRedelivered(t *testing.T) {
+	bus := event.NewAckableBus(event.NewBus(event.DefaultBusConfig), event.AckConfig{
+		AckTimeout: 20 * time.Millisecond,
+	})
+	defer bus.Close()
+
+	seen := &deliveryLog{}
+	sub := bus.SubscribeAck([]string{"order.created"}, func(ctx context.Context, d *event.Delivery) {
+		seen.add(d.Attempt)
+		d.Ack()
+	})
+
+	if err := bus.Publish(context.Background(), sampleEvent()); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	if got := seen.get(); len(got) != 1 {
+		t.Errorf("expected exactly 1 delivery, got %v", got)
+	}
+	if sub.InFlight() != 0 {
+		t.Errorf("expected nothing in flight, got %d", sub.InFlight())
+	}
+}
+
+func TestAckableBus_AsyncAck(t *testing.T) {
+	bus := event.NewAckableBus(event.NewBus(event.DefaultBusConfig), event.AckConfig{
+		AckTimeout: time.Second,
+	})
+	defer bus.Close()
+
+	deliveries := make(chan *event.Delivery, 1)
+	sub := bus.SubscribeAllAck(func(ctx context.Context, d *event.Delivery) {
+		deliveries <- d
+	})
+
+	if err := bus.Publish(context.Background(), sampleEvent()); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	d := <-deliveries
+	if sub.InFlight() != 1 {
+		t.Errorf("expected 1 in flight before ack, got %d", sub.InFlight())
+	}
+	d.Ack()
+	d.Ack()
+	if sub.InFlight() != 0 {
+		t.Errorf("expected nothing in flight after ack, got %d", sub.InFlight())
+	}
+}
+
+func TestAckableBus_MaxDeliveries(t *testing.T) {
+	exhausted := make(chan string, 1)
+	bus := event.NewAckableBus(event.NewBus(event.DefaultBusConfig), event.AckConfig{
+		AckTimeout:    10 * time.Millisecond,
+		MaxDeliveries: 3,
+		OnExhausted: func(evt event.Event, subscriberID string) {
+			exhausted <- evt.ID()
+		},
+	})
+	defer bus.Close()
+
+	seen := &deliveryLog{}
+	sub := bus.SubscribeAck([]string{"order.created"}, func(ctx context.Context, d *event.Delivery) {
+		seen.add(d.Attempt)
+	})
+
+	evt := sampleEvent()
+	if err := bus.Publish(context.Background(), evt); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	select {
+	case id := <-exhausted:
+		if id != evt.ID() {
+			t.Errorf("expected exhausted event %s, got %s", evt.ID(), id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for OnExhausted")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if got := seen.get(); len(got) != 3 {
+		t.Errorf("expected 3 deliveries, got %v", got)
+	}
+	if sub.InFlight() != 0 {
+		t.Errorf("expected nothing in flight, got %d", sub.InFlight())
+	}
+}
+
+func TestAckableBus_PerSubscriberTracking(t *testing.T) {
+	bus := event.NewAckableBus(event.NewBus(event.DefaultBusConfig), event.AckConfig{
+		AckTimeout: 20 * time.Millisecond,
+	})
+	defer bus.Close()
+
+	acker := &deliveryLog{}
+	bus.SubscribeAck([]string{"order.created"}, func(ctx context.Context, d *event.Delivery) {
+		acker.add(d.Attempt)
+		d.Ack()
+	})
+	laggard := &deliveryLog{}
+	lagSub := bus.SubscribeAck([]string{"order.created"}, func(ctx context.Context, d *event.Delivery) {
+		laggard.add(d.Attempt)
+	})
+	var plain atomic.Int32
+	bus.Subscribe([]string{"order.created"}, event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		plain.Add(1)
+		return nil, nil
+	}))
+
+	if err := bus.Publish(context.Background(), sampleEvent()); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	lagSub.Unsubscribe()
+
+	if got := acker.get(); len(got) != 1 {
+		t.Errorf("expected acking subscriber to get 1 delivery, got %v", got)
+	}
+	if got := laggard.get(); len(got) < 2 {
+		t.Errorf("expected unacking subscriber to get redeliveries, got %v", got)
+	}
+	if plain.Load() != 1 {
+		t.Errorf("expected plain subscriber to get 1 delivery, got %d", plain.Load())
+	}
+}
+
+func TestAckableBus_DuplicateWhileInFlight(t *testing.T) {
+	bus := event.NewAckableBus(event.NewBus(event.DefaultBusConfig), event.AckConfig{
+		AckTimeout: time.Second,
+	})
+	defer bus.Close()
+
+	seen := &deliveryLog{}
+	bus.SubscribeAck([]string{"order.created"}, func(ctx context.Context, d *event.Delivery) {
+		seen.add(d.Attempt)
+	})
+
+	evt := sampleEvent()
+	bus.Publish(context.Background(), evt)
+	bus.Publish(context.Background(), evt)
+
+	time.Sleep(50 * time.Millisecond)
+
+	if got := seen.get(); len(got) != 1 {
+		t.Errorf("expected 1 delivery of an in-flight event, got %v", got)
+	}
+}
+
+func TestAckableBus_ClosedBus(t *testing.T) {
+	inner := event.NewBus(event.DefaultBusConfig)
+	inner.Close()
+	bus := event.NewAckableBus(inner, event.AckConfig{})
+
+	sub := bus.SubscribeAck(nil, func(ctx context.Context, d *event.Delivery) {})
+	if sub != nil {
+		t.Error("expected nil subscription on a closed bus")
+	}
+}
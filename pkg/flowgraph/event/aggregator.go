@@ -38,6 +38,18 @@ type WindowConfig struct {
 
 	// Sliding enables sliding windows (vs tumbling).
 	Sliding bool
+
+	// Merge, if set, produces the aggregated event's payload from the
+	// collected events.
+	// Default: nil (the payload is an AggregatedPayload)
+	Merge MergeFunc
+
+	// OnTimeout is called with the events collected so far if Duration
+	// elapses after the first Add without MinEvents being reached. The
+	// aggregator is then expired and rejects further events. It runs on a
+	// timer goroutine.
+	// Default: nil (the aggregator still expires)
+	OnTimeout func(partial []Event)
 }
 
 // DefaultWindowConfig provides reasonable defaults.
@@ -47,6 +59,57 @@ var DefaultWindowConfig = WindowConfig{
 	MaxEvents: 100,
 }
 
+// MergeFunc combines the events collected by an aggregator, in the order
+// added, into the payload of the aggregated event. An error fails Complete.
+type MergeFunc func(events []Event) (any, error)
+
+// AggregatorOption configures a count-based aggregator.
+type AggregatorOption func(*aggregatorOptions)
+
+// aggregatorOptions holds settings shared by count-based aggregators.
+type aggregatorOptions struct {
+	merge MergeFunc
+}
+
+// WithAggregatorMerge sets the MergeFunc that produces the aggregated
+// event's payload, replacing the aggregator's default payload.
+func WithAggregatorMerge(fn MergeFunc) AggregatorOption {
+	return func(o *aggregatorOptions) {
+		o.merge = fn
+	}
+}
+
+// newAggregatorOptions applies opts.
+func newAggregatorOptions(opts []AggregatorOption) aggregatorOptions {
+	var o aggregatorOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// aggregatedEvent builds the event returned by Complete. The payload is
+// merge's result if merge is set, otherwise defaultPayload. The event
+// carries correlationID, and its causation ID is the last collected event.
+func aggregatedEvent[T any](correlationID string, events []Event, merge MergeFunc, defaultPayload T) (Event, error) {
+	tenantID := ""
+	opts := []EventOption{WithCorrelationID(correlationID)}
+	if len(events) > 0 {
+		tenantID = events[0].TenantID()
+		opts = append(opts, WithCausationID(events[len(events)-1].ID()))
+	}
+
+	if merge == nil {
+		return New("aggregation.completed", "aggregator", tenantID, defaultPayload, opts...), nil
+	}
+
+	payload, err := merge(append([]Event(nil), events...))
+	if err != nil {
+		return nil, fmt.Errorf("merge aggregated events: %w", err)
+	}
+	return New("aggregation.completed", "aggregator", tenantID, payload, opts...), nil
+}
+
 // CorrelationAggregator aggregates events by correlation ID.
 type CorrelationAggregator struct {
 	correlationID string
@@ -55,6 +118,8 @@ type CorrelationAggregator struct {
 	mu            sync.Mutex
 	startTime     time.Time
 	completed     bool
+	expired       bool
+	timer         *time.Timer // Started by the first Add
 }
 
 // NewCorrelationAggregator creates a correlation-based aggregator.
@@ -67,11 +132,16 @@ func NewCorrelationAggregator(correlationID string, window WindowConfig) *Correl
 	}
 }
 
-// Add contributes an event to the aggregation.
+// Add contributes an event to the aggregation. The first event starts the
+// window's timeout. Returns an error once the aggregator is completed or
+// expired.
 func (a *CorrelationAggregator) Add(_ context.Context, evt Event) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
+	if a.expired {
+		return fmt.Errorf("aggregator expired")
+	}
 	if a.completed {
 		return fmt.Errorf("aggregator already completed")
 	}
@@ -84,26 +154,56 @@ func (a *CorrelationAggregator) Add(_ context.Context, evt Event) error {
 
 	a.events = append(a.events, evt)
 
+	if a.timer == nil && a.window.Duration > 0 {
+		a.timer = time.AfterFunc(a.window.Duration, a.expire)
+	}
+
 	// Check if max events reached
 	if a.window.MaxEvents > 0 && len(a.events) >= a.window.MaxEvents {
-		a.completed = true
+		a.markCompleted()
 	}
 
 	return nil
 }
 
+// expire ends an incomplete window when its timer fires.
+func (a *CorrelationAggregator) expire() {
+	a.mu.Lock()
+	if a.completed || len(a.events) >= a.window.MinEvents {
+		a.mu.Unlock()
+		return
+	}
+	a.expired = true
+	partial := append([]Event(nil), a.events...)
+	a.mu.Unlock()
+
+	if a.window.OnTimeout != nil {
+		a.window.OnTimeout(partial)
+	}
+}
+
+// markCompleted marks the aggregator completed and stops its timer.
+// a.mu must be held.
+func (a *CorrelationAggregator) markCompleted() {
+	a.completed = true
+	if a.timer != nil {
+		a.timer.Stop()
+	}
+}
+
 // Complete returns the aggregated event.
 func (a *CorrelationAggregator) Complete(ctx context.Context) (Event, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
+	if a.expired {
+		return nil, fmt.Errorf("aggregator expired")
+	}
 	if len(a.events) < a.window.MinEvents {
 		return nil, fmt.Errorf("not enough events: have %d, need %d",
 			len(a.events), a.window.MinEvents)
 	}
 
-	a.completed = true
-
 	// Create aggregated event
 	payload := AggregatedPayload{
 		Events:        a.events,
@@ -113,26 +213,24 @@ func (a *CorrelationAggregator) Complete(ctx context.Context) (Event, error) {
 		EndTime:       time.Now(),
 	}
 
-	// Determine tenant ID from first event
-	tenantID := ""
-	if len(a.events) > 0 {
-		tenantID = a.events[0].TenantID()
+	evt, err := aggregatedEvent(a.correlationID, a.events, a.window.Merge, payload)
+	if err != nil {
+		return nil, err
 	}
 
-	return New(
-		"aggregation.completed",
-		"aggregator",
-		tenantID,
-		payload,
-		WithCorrelationID(a.correlationID),
-	), nil
+	a.markCompleted()
+	return evt, nil
 }
 
-// IsComplete returns true if aggregation criteria are met.
+// IsComplete returns true if aggregation criteria are met. An expired
+// aggregator is never complete.
 func (a *CorrelationAggregator) IsComplete() bool {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
+	if a.expired {
+		return false
+	}
 	if a.completed {
 		return true
 	}
@@ -150,6 +248,14 @@ func (a *CorrelationAggregator) IsComplete() bool {
 	return false
 }
 
+// IsExpired returns true if the window timed out before MinEvents were
+// collected. An expired aggregator is terminal.
+func (a *CorrelationAggregator) IsExpired() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.expired
+}
+
 // Events returns all collected events.
 func (a *CorrelationAggregator) Events() []Event {
 	a.mu.Lock()
@@ -178,15 +284,18 @@ type CountAggregator struct {
 	correlationID string
 	expectedCount int
 	events        []Event
+	merge         MergeFunc
 	mu            sync.Mutex
 }
 
 // NewCountAggregator creates a count-based aggregator.
-func NewCountAggregator(correlationID string, expectedCount int) *CountAggregator {
+func NewCountAggregator(correlationID string, expectedCount int, opts ...AggregatorOption) *CountAggregator {
+	o := newAggregatorOptions(opts)
 	return &CountAggregator{
 		correlationID: correlationID,
 		expectedCount: expectedCount,
 		events:        make([]Event, 0, expectedCount),
+		merge:         o.merge,
 	}
 }
 
@@ -219,18 +328,7 @@ func (a *CountAggregator) Complete(ctx context.Context) (Event, error) {
 		CorrelationID: a.correlationID,
 	}
 
-	tenantID := ""
-	if len(a.events) > 0 {
-		tenantID = a.events[0].TenantID()
-	}
-
-	return New(
-		"aggregation.completed",
-		"aggregator",
-		tenantID,
-		payload,
-		WithCorrelationID(a.correlationID),
-	), nil
+	return aggregatedEvent(a.correlationID, a.events, a.merge, payload)
 }
 
 // IsComplete returns true if expected count is reached.
@@ -252,6 +350,102 @@ func (a *CountAggregator) CorrelationID() string {
 	return a.correlationID
 }
 
+// PredicateCountAggregator waits for a fixed number of events matching a
+// predicate, with no time window. Events that do not match are ignored, so
+// it can be fed a whole stream.
+type PredicateCountAggregator struct {
+	predicate     func(Event) bool
+	expectedCount int
+	events        []Event
+	merge         MergeFunc
+	mu            sync.Mutex
+}
+
+// NewPredicateCountAggregator creates an aggregator that completes once n
+// events matching predicate have been added. Complete returns an event
+// whose payload is the matching events as a []Event, in the order added,
+// unless WithAggregatorMerge replaces it.
+//
+// Panics if predicate is nil or n is not positive.
+//
+// Example:
+//
+//	agg := event.NewPredicateCountAggregator(func(evt event.Event) bool {
+//	    return evt.Type() == "shard.indexed"
+//	}, shardCount)
+func NewPredicateCountAggregator(predicate func(Event) bool, n int, opts ...AggregatorOption) *PredicateCountAggregator {
+	if predicate == nil {
+		panic("event: aggregator predicate cannot be nil")
+	}
+	if n <= 0 {
+		panic("event: aggregator count must be positive")
+	}
+	o := newAggregatorOptions(opts)
+	return &PredicateCountAggregator{
+		predicate:     predicate,
+		expectedCount: n,
+		events:        make([]Event, 0, n),
+		merge:         o.merge,
+	}
+}
+
+// Add contributes an event. Events not matching the predicate are ignored.
+// Returns an error for a matching event once the aggregator is complete.
+func (a *PredicateCountAggregator) Add(_ context.Context, evt Event) error {
+	if !a.predicate(evt) {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.events) >= a.expectedCount {
+		return fmt.Errorf("aggregator already completed")
+	}
+
+	a.events = append(a.events, evt)
+	return nil
+}
+
+// Complete returns the aggregated event. It carries the correlation ID and
+// tenant ID of the first collected event.
+func (a *PredicateCountAggregator) Complete(ctx context.Context) (Event, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.events) < a.expectedCount {
+		return nil, fmt.Errorf("not enough events: have %d, expected %d",
+			len(a.events), a.expectedCount)
+	}
+
+	return aggregatedEvent(a.events[0].CorrelationID(), a.events, a.merge, append([]Event(nil), a.events...))
+}
+
+// IsComplete returns true once n matching events have been added.
+func (a *PredicateCountAggregator) IsComplete() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.events) >= a.expectedCount
+}
+
+// Events returns collected events.
+func (a *PredicateCountAggregator) Events() []Event {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]Event(nil), a.events...)
+}
+
+// CorrelationID returns the correlation ID of the first collected event,
+// or "" if none has been collected.
+func (a *PredicateCountAggregator) CorrelationID() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.events) == 0 {
+		return ""
+	}
+	return a.events[0].CorrelationID()
+}
+
 // AggregatorRegistry manages active aggregations.
 type AggregatorRegistry struct {
 	mu          sync.RWMutex
@@ -321,7 +515,13 @@ func (r *AggregatorRegistry) Close() {
 	close(r.closeCh)
 }
 
-// cleanupLoop removes completed aggregators.
+// expirable is implemented by aggregators that can time out, such as
+// CorrelationAggregator.
+type expirable interface {
+	IsExpired() bool
+}
+
+// cleanupLoop removes completed and expired aggregators.
 func (r *AggregatorRegistry) cleanupLoop() {
 	ticker := time.NewTicker(r.cleanup)
 	defer ticker.Stop()
@@ -333,6 +533,8 @@ func (r *AggregatorRegistry) cleanupLoop() {
 			for id, agg := range r.aggregators {
 				if agg.IsComplete() {
 					delete(r.aggregators, id)
+				} else if exp, ok := agg.(expirable); ok && exp.IsExpired() {
+					delete(r.aggregators, id)
 				}
 			}
 			r.mu.Unlock()
@@ -2,6 +2,11 @@ package event_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -90,6 +95,66 @@ func TestCorrelationAggregatorMaxEvents(t *testing.T) {
 	}
 }
 
+func TestCorrelationAggregatorTimeout(t *testing.T) {
+	correlationID := "timeout-test"
+	timedOut := make(chan []event.Event, 1)
+
+	agg := event.NewCorrelationAggregator(correlationID, event.WindowConfig{
+		Duration:  30 * time.Millisecond,
+		MinEvents: 3,
+		OnTimeout: func(partial []event.Event) { timedOut <- partial },
+	})
+
+	evt := event.NewAny("test", "test", "t1", nil, event.WithCorrelationID(correlationID))
+	if err := agg.Add(context.Background(), evt); err != nil {
+		t.Fatalf("failed to add event: %v", err)
+	}
+
+	select {
+	case partial := <-timedOut:
+		if len(partial) != 1 || partial[0].ID() != evt.ID() {
+			t.Errorf("expected the collected event, got %v", partial)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected OnTimeout to be called")
+	}
+
+	if !agg.IsExpired() {
+		t.Error("expected aggregator to be expired")
+	}
+	if agg.IsComplete() {
+		t.Error("expected expired aggregator not to be complete")
+	}
+	late := event.NewAny("test", "test", "t1", nil, event.WithCorrelationID(correlationID))
+	if err := agg.Add(context.Background(), late); err == nil {
+		t.Error("expected Add to fail after expiry")
+	}
+	if _, err := agg.Complete(context.Background()); err == nil {
+		t.Error("expected Complete to fail after expiry")
+	}
+}
+
+func TestCorrelationAggregatorNoTimeoutWhenReached(t *testing.T) {
+	correlationID := "reached-test"
+	var timedOut atomic.Bool
+
+	agg := event.NewCorrelationAggregator(correlationID, event.WindowConfig{
+		Duration:  20 * time.Millisecond,
+		MinEvents: 1,
+		OnTimeout: func([]event.Event) { timedOut.Store(true) },
+	})
+	agg.Add(context.Background(), event.NewAny("test", "test", "t1", nil, event.WithCorrelationID(correlationID)))
+
+	time.Sleep(50 * time.Millisecond)
+
+	if timedOut.Load() || agg.IsExpired() {
+		t.Error("expected no timeout once MinEvents was reached")
+	}
+	if !agg.IsComplete() {
+		t.Error("expected aggregator to be complete after its window")
+	}
+}
+
 func TestCountAggregator(t *testing.T) {
 	correlationID := "count-test"
 
@@ -140,6 +205,206 @@ func TestCountAggregatorNotEnough(t *testing.T) {
 	}
 }
 
+func TestPredicateCountAggregator(t *testing.T) {
+	agg := event.NewPredicateCountAggregator(func(evt event.Event) bool {
+		return evt.Type() == "shard.indexed"
+	}, 3)
+
+	var want []string
+	for i := 0; i < 3; i++ {
+		indexed := event.NewAny("shard.indexed", "test", "t1", i, event.WithCorrelationID("job-1"))
+		want = append(want, indexed.ID())
+		if err := agg.Add(context.Background(), indexed); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+		// Non-matching events are ignored
+		if err := agg.Add(context.Background(), event.NewAny("shard.started", "test", "t1", i)); err != nil {
+			t.Fatalf("add non-matching: %v", err)
+		}
+		if i < 2 && agg.IsComplete() {
+			t.Fatalf("expected incomplete after %d events", i+1)
+		}
+	}
+
+	if !agg.IsComplete() {
+		t.Fatal("expected aggregator to be complete")
+	}
+	if agg.CorrelationID() != "job-1" {
+		t.Errorf("expected correlation job-1, got %s", agg.CorrelationID())
+	}
+
+	// Matching events beyond N are rejected
+	extra := event.NewAny("shard.indexed", "test", "t1", 3)
+	if err := agg.Add(context.Background(), extra); err == nil {
+		t.Error("expected error adding to a complete aggregator")
+	}
+
+	result, err := agg.Complete(context.Background())
+	if err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+	if result.CorrelationID() != "job-1" {
+		t.Errorf("expected correlation job-1, got %s", result.CorrelationID())
+	}
+
+	events, ok := result.Data().([]event.Event)
+	if !ok {
+		t.Fatalf("expected []event.Event payload, got %T", result.Data())
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	for i, evt := range events {
+		if evt.ID() != want[i] {
+			t.Errorf("event %d: expected %s, got %s", i, want[i], evt.ID())
+		}
+	}
+}
+
+func TestPredicateCountAggregatorConcurrent(t *testing.T) {
+	agg := event.NewPredicateCountAggregator(func(evt event.Event) bool { return true }, 50)
+
+	var wg sync.WaitGroup
+	var rejected atomic.Int32
+	for i := 0; i < 60; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := agg.Add(context.Background(), event.NewAny("test", "test", "t1", nil)); err != nil {
+				rejected.Add(1)
+			}
+			agg.IsComplete()
+		}()
+	}
+	wg.Wait()
+
+	if !agg.IsComplete() || len(agg.Events()) != 50 {
+		t.Errorf("expected exactly 50 collected events, got %d", len(agg.Events()))
+	}
+	if rejected.Load() != 10 {
+		t.Errorf("expected 10 rejected events, got %d", rejected.Load())
+	}
+}
+
+func TestPredicateCountAggregatorNotEnough(t *testing.T) {
+	agg := event.NewPredicateCountAggregator(func(evt event.Event) bool { return true }, 2)
+	agg.Add(context.Background(), event.NewAny("test", "test", "t1", nil))
+
+	if _, err := agg.Complete(context.Background()); err == nil {
+		t.Error("expected error when completing with insufficient events")
+	}
+	if agg.CorrelationID() == "" {
+		t.Error("expected correlation ID of the first event")
+	}
+}
+
+func TestNewPredicateCountAggregatorPanics(t *testing.T) {
+	for name, fn := range map[string]func(){
+		"nil predicate": func() { event.NewPredicateCountAggregator(nil, 1) },
+		"zero count":    func() { event.NewPredicateCountAggregator(func(event.Event) bool { return true }, 0) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
+
+// quoteTotal is a domain payload reduced from partial quotes.
+type quoteTotal struct {
+	Total int
+	Parts int
+}
+
+func sumQuotes(events []event.Event) (any, error) {
+	var total quoteTotal
+	for _, evt := range events {
+		amount, ok := evt.Data().(int)
+		if !ok {
+			return nil, fmt.Errorf("event %s: unexpected payload %T", evt.ID(), evt.Data())
+		}
+		total.Total += amount
+		total.Parts++
+	}
+	return total, nil
+}
+
+func TestAggregatorMerge(t *testing.T) {
+	correlationID := "quote-1"
+	newQuote := func(amount any) event.Event {
+		return event.NewAny("quote.partial", "test", "t1", amount, event.WithCorrelationID(correlationID))
+	}
+
+	aggregators := map[string]func(merge event.MergeFunc) event.Aggregator{
+		"correlation": func(merge event.MergeFunc) event.Aggregator {
+			return event.NewCorrelationAggregator(correlationID, event.WindowConfig{MinEvents: 3, Merge: merge})
+		},
+		"count": func(merge event.MergeFunc) event.Aggregator {
+			return event.NewCountAggregator(correlationID, 3, event.WithAggregatorMerge(merge))
+		},
+		"predicate": func(merge event.MergeFunc) event.Aggregator {
+			return event.NewPredicateCountAggregator(func(event.Event) bool { return true }, 3,
+				event.WithAggregatorMerge(merge))
+		},
+	}
+
+	for name, newAgg := range aggregators {
+		t.Run(name, func(t *testing.T) {
+			agg := newAgg(sumQuotes)
+			var last event.Event
+			for _, amount := range []int{10, 20, 30} {
+				last = newQuote(amount)
+				agg.Add(context.Background(), last)
+			}
+
+			result, err := agg.Complete(context.Background())
+			if err != nil {
+				t.Fatalf("failed to complete: %v", err)
+			}
+			if got, want := result.Data(), (quoteTotal{Total: 60, Parts: 3}); got != want {
+				t.Errorf("expected merged payload %+v, got %+v", want, got)
+			}
+			if result.CorrelationID() != correlationID {
+				t.Errorf("expected correlation %s, got %s", correlationID, result.CorrelationID())
+			}
+			if result.CausationID() != last.ID() {
+				t.Errorf("expected causation %s, got %s", last.ID(), result.CausationID())
+			}
+		})
+
+		t.Run(name+" error", func(t *testing.T) {
+			agg := newAgg(sumQuotes)
+			agg.Add(context.Background(), newQuote(10))
+			agg.Add(context.Background(), newQuote("ten"))
+			agg.Add(context.Background(), newQuote(30))
+
+			if _, err := agg.Complete(context.Background()); err == nil || !strings.Contains(err.Error(), "unexpected payload string") {
+				t.Errorf("expected merge error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCorrelationAggregatorMergeErrorKeepsOpen(t *testing.T) {
+	errMerge := errors.New("partial results disagree")
+	agg := event.NewCorrelationAggregator("c1", event.WindowConfig{
+		MinEvents: 1,
+		Merge:     func([]event.Event) (any, error) { return nil, errMerge },
+	})
+	agg.Add(context.Background(), event.NewAny("test", "test", "t1", nil, event.WithCorrelationID("c1")))
+
+	if _, err := agg.Complete(context.Background()); !errors.Is(err, errMerge) {
+		t.Fatalf("expected merge error, got %v", err)
+	}
+	if agg.IsComplete() {
+		t.Error("expected a failed merge not to complete the aggregator")
+	}
+}
+
 func TestAggregatorRegistry(t *testing.T) {
 	registry := event.NewAggregatorRegistry(100 * time.Millisecond)
 	defer registry.Close()
@@ -213,6 +478,26 @@ func TestAggregatorRegistryCleanup(t *testing.T) {
 	}
 }
 
+func TestAggregatorRegistryCleanupExpired(t *testing.T) {
+	registry := event.NewAggregatorRegistry(20 * time.Millisecond)
+	defer registry.Close()
+
+	correlationID := "expired-test"
+	agg := registry.GetOrCreate(correlationID, func() event.Aggregator {
+		return event.NewCorrelationAggregator(correlationID, event.WindowConfig{
+			Duration:  10 * time.Millisecond,
+			MinEvents: 2,
+		})
+	})
+	agg.Add(context.Background(), event.NewAny("test", "test", "t1", nil, event.WithCorrelationID(correlationID)))
+
+	time.Sleep(100 * time.Millisecond)
+
+	if _, ok := registry.Get(correlationID); ok {
+		t.Error("expected expired aggregator to be cleaned up")
+	}
+}
+
 func TestAggregatorCorrelationID(t *testing.T) {
 	correlationID := "my-correlation"
 
@@ -13,6 +13,7 @@ type Bus interface {
 	Publish(ctx context.Context, evt Event) error
 
 	// Subscribe creates a subscription for specific event types.
+	// Types may be patterns such as "order.*"; see LocalBus.Subscribe.
 	Subscribe(types []string, handler Handler) Subscription
 
 	// SubscribeAll subscribes to all events.
@@ -51,15 +52,42 @@ type BusConfig struct {
 	// Default: false (blocking)
 	NonBlocking bool
 
-	// DeduplicateTTL enables deduplication with the given TTL.
+	// DeduplicateTTL enables deduplication with the given TTL: an event is
+	// dropped if another with the same dedup key was published within the
+	// TTL. The window is per key, and keys are kept only for the TTL, so
+	// memory is bounded by the number of distinct keys published within
+	// one TTL; a background loop removes expired keys every TTL/2.
 	// Default: 0 (disabled)
 	DeduplicateTTL time.Duration
 
+	// DedupKeyFunc returns the key events are deduplicated on. Use
+	// ContentDedupKey to drop events with the same type and payload even
+	// if their IDs differ.
+	// Default: nil (the event ID)
+	DedupKeyFunc func(Event) string
+
 	// OnDrop is called when an event is dropped (non-blocking mode).
 	OnDrop func(evt Event, subscriberID string)
 
 	// OnError is called when a handler returns an error.
 	OnError func(evt Event, subscriberID string, err error)
+
+	// Codec, if set, round-trips every published event through Encode and
+	// Decode, and subscribers receive the decoded copy. This surfaces codec
+	// errors and lost fields at publish time, as a networked bus would.
+	// Default: nil (events are delivered as published)
+	Codec Codec
+
+	// RateMeter, if set, records every published event that passes
+	// deduplication, so rates per event type can be queried.
+	// Default: nil
+	RateMeter *RateMeter
+
+	// Store, if set, appends every published event that passes
+	// deduplication before it is delivered, so it can be replayed later.
+	// Publish fails if the append fails.
+	// Default: nil
+	Store EventStore
 }
 
 // DefaultBusConfig provides reasonable defaults.
@@ -74,10 +102,11 @@ type LocalBus struct {
 	mu            sync.RWMutex
 	subscriptions map[string]*subscription
 	byType        map[string]map[string]*subscription // event type -> subscription ID -> subscription
+	patterned     map[string]*subscription            // subscriptions with type patterns
 	wildcards     map[string]*subscription            // subscriptions for all events
 
 	// Deduplication cache
-	dedupeMu    sync.RWMutex
+	dedupeMu    sync.Mutex
 	dedupeCache map[string]time.Time
 
 	nextID  atomic.Int64
@@ -90,11 +119,15 @@ func NewBus(config BusConfig) *LocalBus {
 	if config.BufferSize <= 0 {
 		config.BufferSize = DefaultBusConfig.BufferSize
 	}
+	if config.DedupKeyFunc == nil {
+		config.DedupKeyFunc = Event.ID
+	}
 
 	bus := &LocalBus{
 		config:        config,
 		subscriptions: make(map[string]*subscription),
 		byType:        make(map[string]map[string]*subscription),
+		patterned:     make(map[string]*subscription),
 		wildcards:     make(map[string]*subscription),
 		closeCh:       make(chan struct{}),
 	}
@@ -109,13 +142,14 @@ func NewBus(config BusConfig) *LocalBus {
 
 // subscription is an internal subscription implementation.
 type subscription struct {
-	id      string
-	types   []string // empty = all types
-	handler Handler
-	events  chan Event
-	paused  atomic.Bool
-	done    chan struct{}
-	bus     *LocalBus
+	id       string
+	types    []string // empty = all types
+	patterns []*typePattern
+	handler  Handler
+	events   chan Event
+	paused   atomic.Bool
+	done     chan struct{}
+	bus      *LocalBus
 }
 
 // Publish sends an event to all matching subscribers.
@@ -132,7 +166,32 @@ func (b *LocalBus) Publish(ctx context.Context, evt Event) error {
 		if b.isDuplicate(evt) {
 			return nil // Silently skip duplicates
 		}
-		b.recordEvent(evt)
+	}
+
+	if b.config.RateMeter != nil {
+		b.config.RateMeter.Record(evt.Type())
+	}
+
+	if b.config.Store != nil {
+		if err := b.config.Store.Append(ctx, evt); err != nil {
+			return &EventError{
+				Event:   evt,
+				Message: "append to event store failed",
+				Err:     err,
+			}
+		}
+	}
+
+	if b.config.Codec != nil {
+		decoded, err := roundTrip(b.config.Codec, evt)
+		if err != nil {
+			return &EventError{
+				Event:   evt,
+				Message: "codec round trip failed",
+				Err:     err,
+			}
+		}
+		evt = decoded
 	}
 
 	// Get matching subscriptions
@@ -173,6 +232,16 @@ func (b *LocalBus) Publish(ctx context.Context, evt Event) error {
 }
 
 // Subscribe creates a subscription for specific event types.
+//
+// A type containing "*" is a pattern, compiled once here and matched
+// against dot-delimited event types: "*" matches a single segment, so
+// "order.*" matches "order.created" but not "order.item.added", and a
+// final "**" matches the rest, so "order.**" matches both. "*" within a
+// segment matches part of it, as in "order.ship*". Each event is delivered
+// once per subscription, and subscriptions matching its type exactly
+// receive it before pattern subscriptions.
+//
+// Panics if a pattern uses "**" anywhere but as its last segment.
 func (b *LocalBus) Subscribe(types []string, handler Handler) Subscription {
 	return b.subscribe(types, handler)
 }
@@ -183,6 +252,20 @@ func (b *LocalBus) SubscribeAll(handler Handler) Subscription {
 }
 
 func (b *LocalBus) subscribe(types []string, handler Handler) *subscription {
+	var exact []string
+	var patterns []*typePattern
+	for _, t := range types {
+		if !isTypePattern(t) {
+			exact = append(exact, t)
+			continue
+		}
+		p, err := compileTypePattern(t)
+		if err != nil {
+			panic("event: " + err.Error())
+		}
+		patterns = append(patterns, p)
+	}
+
 	if b.closed.Load() {
 		return nil
 	}
@@ -197,12 +280,13 @@ func (b *LocalBus) subscribe(types []string, handler Handler) *subscription {
 
 	id := b.nextID.Add(1)
 	sub := &subscription{
-		id:      string(rune(id)),
-		types:   types,
-		handler: handler,
-		events:  make(chan Event, b.config.BufferSize),
-		done:    make(chan struct{}),
-		bus:     b,
+		id:       string(rune(id)),
+		types:    exact,
+		patterns: patterns,
+		handler:  handler,
+		events:   make(chan Event, b.config.BufferSize),
+		done:     make(chan struct{}),
+		bus:      b,
 	}
 
 	b.subscriptions[sub.id] = sub
@@ -210,12 +294,15 @@ func (b *LocalBus) subscribe(types []string, handler Handler) *subscription {
 	if len(types) == 0 {
 		b.wildcards[sub.id] = sub
 	} else {
-		for _, t := range types {
+		for _, t := range exact {
 			if b.byType[t] == nil {
 				b.byType[t] = make(map[string]*subscription)
 			}
 			b.byType[t][sub.id] = sub
 		}
+		if len(patterns) > 0 {
+			b.patterned[sub.id] = sub
+		}
 	}
 
 	// Start processing goroutine
@@ -224,13 +311,23 @@ func (b *LocalBus) subscribe(types []string, handler Handler) *subscription {
 	return sub
 }
 
-// getMatchingSubscriptions returns all subscriptions matching an event type.
+// getMatchingSubscriptions returns all subscriptions matching an event type:
+// exact matches first, then pattern matches, then wildcards.
 func (b *LocalBus) getMatchingSubscriptions(eventType string) []*subscription {
 	subs := make([]*subscription, 0)
 
 	// Add type-specific subscriptions
-	if typeSubs, ok := b.byType[eventType]; ok {
-		for _, sub := range typeSubs {
+	typeSubs := b.byType[eventType]
+	for _, sub := range typeSubs {
+		subs = append(subs, sub)
+	}
+
+	// Add pattern subscriptions not already matched exactly
+	for id, sub := range b.patterned {
+		if _, ok := typeSubs[id]; ok {
+			continue
+		}
+		if sub.matchesPattern(eventType) {
 			subs = append(subs, sub)
 		}
 	}
@@ -282,12 +379,23 @@ func (s *subscription) process() {
 	}
 }
 
+// matchesPattern reports whether any of the subscription's patterns match.
+func (s *subscription) matchesPattern(eventType string) bool {
+	for _, p := range s.patterns {
+		if p.Match(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
 // Unsubscribe removes the subscription.
 func (s *subscription) Unsubscribe() {
 	s.bus.mu.Lock()
 	defer s.bus.mu.Unlock()
 
 	delete(s.bus.subscriptions, s.id)
+	delete(s.bus.patterned, s.id)
 	delete(s.bus.wildcards, s.id)
 
 	for _, t := range s.types {
@@ -316,19 +424,28 @@ func (s *subscription) IsPaused() bool {
 
 // Deduplication helpers
 
-func (b *LocalBus) isDuplicate(evt Event) bool {
-	b.dedupeMu.RLock()
-	defer b.dedupeMu.RUnlock()
-
-	_, exists := b.dedupeCache[evt.ID()]
-	return exists
+// ContentDedupKey is a BusConfig.DedupKeyFunc that keys events on a hash
+// of their type and DataBytes, so republished events with new IDs but the
+// same payload are dropped.
+func ContentDedupKey(evt Event) string {
+	return defaultHashFunc(evt)
 }
 
-func (b *LocalBus) recordEvent(evt Event) {
+// isDuplicate reports whether an event with the same key was published
+// within the TTL, and records the event if not.
+func (b *LocalBus) isDuplicate(evt Event) bool {
+	key := b.config.DedupKeyFunc(evt)
+	now := time.Now()
+
 	b.dedupeMu.Lock()
 	defer b.dedupeMu.Unlock()
 
-	b.dedupeCache[evt.ID()] = time.Now()
+	// Expired keys may linger until the next cleanup
+	if seen, exists := b.dedupeCache[key]; exists && now.Sub(seen) < b.config.DeduplicateTTL {
+		return true
+	}
+	b.dedupeCache[key] = now
+	return false
 }
 
 func (b *LocalBus) cleanupDedupe() {
@@ -340,9 +457,9 @@ func (b *LocalBus) cleanupDedupe() {
 		case <-ticker.C:
 			b.dedupeMu.Lock()
 			cutoff := time.Now().Add(-b.config.DeduplicateTTL)
-			for id, ts := range b.dedupeCache {
+			for key, ts := range b.dedupeCache {
 				if ts.Before(cutoff) {
-					delete(b.dedupeCache, id)
+					delete(b.dedupeCache, key)
 				}
 			}
 			b.dedupeMu.Unlock()
@@ -2,6 +2,7 @@ package event_test
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -197,6 +198,46 @@ func TestBusDeduplication(t *testing.T) {
 	}
 }
 
+func TestBusDeduplication_ContentKey(t *testing.T) {
+	bus := event.NewBus(event.BusConfig{
+		BufferSize:     10,
+		DeduplicateTTL: 100 * time.Millisecond,
+		DedupKeyFunc:   event.ContentDedupKey,
+	})
+	defer bus.Close()
+
+	var received atomic.Int32
+	sub := bus.SubscribeAll(event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		received.Add(1)
+		return nil, nil
+	}))
+	defer sub.Unsubscribe()
+
+	ctx := context.Background()
+	payload := map[string]any{"order_id": "o-1"}
+
+	// Same type and payload with new IDs is a duplicate
+	bus.Publish(ctx, event.NewAny("order.created", "orders", "t1", payload))
+	bus.Publish(ctx, event.NewAny("order.created", "orders", "t1", payload))
+	// A different payload or type is not
+	bus.Publish(ctx, event.NewAny("order.created", "orders", "t1", map[string]any{"order_id": "o-2"}))
+	bus.Publish(ctx, event.NewAny("order.updated", "orders", "t1", payload))
+	time.Sleep(50 * time.Millisecond)
+
+	if received.Load() != 3 {
+		t.Fatalf("expected 3 events after content dedup, got %d", received.Load())
+	}
+
+	// Once the TTL has passed the key is accepted again
+	time.Sleep(100 * time.Millisecond)
+	bus.Publish(ctx, event.NewAny("order.created", "orders", "t1", payload))
+	time.Sleep(50 * time.Millisecond)
+
+	if received.Load() != 4 {
+		t.Errorf("expected republish after TTL to be delivered, got %d events", received.Load())
+	}
+}
+
 func TestBusNonBlocking(t *testing.T) {
 	var dropped atomic.Int32
 
@@ -291,3 +332,93 @@ func TestBusFanOut(t *testing.T) {
 			received1.Load(), received2.Load(), received3.Load())
 	}
 }
+
+func TestBusSubscribePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		matches []string
+		misses  []string
+	}{
+		{"order.*", []string{"order.created", "order.shipped"}, []string{"order", "order.item.added", "orders.created"}},
+		{"order.**", []string{"order.created", "order.item.added"}, []string{"order", "user.created"}},
+		{"*.created", []string{"order.created", "user.created"}, []string{"created", "order.item.created"}},
+		{"order.ship*", []string{"order.shipped", "order.shipping"}, []string{"order.reship", "order.created"}},
+		{"**", []string{"order", "order.created"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			bus := event.NewBus(event.BusConfig{BufferSize: 10})
+			defer bus.Close()
+
+			var mu sync.Mutex
+			got := make(map[string]bool)
+			sub := bus.Subscribe([]string{tt.pattern}, event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+				mu.Lock()
+				got[evt.Type()] = true
+				mu.Unlock()
+				return nil, nil
+			}))
+			defer sub.Unsubscribe()
+
+			for _, typ := range append(append([]string(nil), tt.matches...), tt.misses...) {
+				bus.Publish(context.Background(), event.NewAny(typ, "test", "t1", nil))
+			}
+			time.Sleep(50 * time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, typ := range tt.matches {
+				if !got[typ] {
+					t.Errorf("expected %q to match %q", tt.pattern, typ)
+				}
+			}
+			for _, typ := range tt.misses {
+				if got[typ] {
+					t.Errorf("expected %q not to match %q", tt.pattern, typ)
+				}
+			}
+		})
+	}
+}
+
+func TestBusSubscribePatternDeliversOnce(t *testing.T) {
+	bus := event.NewBus(event.BusConfig{BufferSize: 10})
+	defer bus.Close()
+
+	var received atomic.Int32
+	sub := bus.Subscribe([]string{"order.created", "order.*", "order.**"}, event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		received.Add(1)
+		return nil, nil
+	}))
+
+	bus.Publish(context.Background(), event.NewAny("order.created", "test", "t1", nil))
+	time.Sleep(50 * time.Millisecond)
+
+	if received.Load() != 1 {
+		t.Errorf("expected 1 delivery, got %d", received.Load())
+	}
+
+	// Unsubscribing removes pattern matches too
+	sub.Unsubscribe()
+	bus.Publish(context.Background(), event.NewAny("order.shipped", "test", "t1", nil))
+	time.Sleep(50 * time.Millisecond)
+
+	if received.Load() != 1 {
+		t.Errorf("expected no delivery after unsubscribe, got %d", received.Load())
+	}
+}
+
+func TestBusSubscribeInvalidPattern(t *testing.T) {
+	bus := event.NewBus(event.BusConfig{})
+	defer bus.Close()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for ** before the last segment")
+		}
+	}()
+	bus.Subscribe([]string{"order.**.created"}, event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		return nil, nil
+	}))
+}
@@ -0,0 +1,301 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is wrapped by the error a circuit breaker returns while
+// it rejects events.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed passes events to the handler and tracks failures.
+	BreakerClosed BreakerState = iota
+
+	// BreakerOpen rejects events without calling the handler.
+	BreakerOpen
+
+	// BreakerHalfOpen passes a limited number of trial events to test
+	// whether the handler has recovered.
+	BreakerHalfOpen
+)
+
+// String returns the name of the state.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("BreakerState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerConfig configures CircuitBreakerMiddleware.
+type CircuitBreakerConfig struct {
+	// FailureRate is the fraction of failed events, from 0 to 1, within
+	// Window that opens the breaker.
+	// Default: 0.5
+	FailureRate float64
+
+	// MinEvents is the number of events within Window needed before the
+	// failure rate is considered, so a few early failures do not trip it.
+	// Default: 10
+	MinEvents int
+
+	// Window is how far back failures are counted.
+	// Default: 1 minute
+	Window time.Duration
+
+	// Cooldown is how long the breaker stays open before half-opening.
+	// Default: 30 seconds
+	Cooldown time.Duration
+
+	// HalfOpenEvents is the number of trial events let through while
+	// half-open. The breaker closes once all of them succeed and opens
+	// again on the first failure.
+	// Default: 1
+	HalfOpenEvents int
+
+	// IsFailure decides which handler errors count as failures.
+	// Default: nil (every error counts)
+	IsFailure func(err error) bool
+
+	// DLQ, if set, receives each event rejected while the breaker is open.
+	// Leave it nil under a router with RouterConfig.DLQ, which already
+	// dead-letters the rejection error.
+	DLQ DeadLetterQueue
+
+	// OnStateChange is called on every state transition, e.g. to alert.
+	// It runs synchronously, so it should return quickly.
+	OnStateChange func(handler string, from, to BreakerState)
+}
+
+// DefaultCircuitBreakerConfig provides reasonable defaults.
+var DefaultCircuitBreakerConfig = CircuitBreakerConfig{
+	FailureRate:    0.5,
+	MinEvents:      10,
+	Window:         time.Minute,
+	Cooldown:       30 * time.Second,
+	HalfOpenEvents: 1,
+}
+
+// CircuitBreakerMiddleware creates middleware that stops calling a handler
+// that keeps failing. Each handler the middleware wraps gets its own
+// breaker. Once the handler's failure rate within the window reaches
+// FailureRate, the breaker opens and events fail fast with an *EventError
+// wrapping ErrCircuitOpen for the cooldown; it then half-opens and lets
+// trial events through to decide whether to close or open again.
+//
+// Register it after RecoveryMiddleware, so panics count as failures, and
+// before PoisonPillMiddleware, so fast rejections are not recorded as
+// poison pill failures:
+//
+//	router.Use(event.RecoveryMiddleware())
+//	router.Use(event.CircuitBreakerMiddleware(event.CircuitBreakerConfig{
+//	    Cooldown: time.Minute,
+//	    OnStateChange: func(handler string, from, to event.BreakerState) {
+//	        log.Printf("breaker for %s: %s -> %s", handler, from, to)
+//	    },
+//	}))
+//	router.Use(event.PoisonPillMiddleware(detector))
+//
+// In that order, events rejected as poison pills count as failures unless
+// IsFailure excludes them.
+func CircuitBreakerMiddleware(cfg CircuitBreakerConfig) MiddlewareFunc {
+	cfg = cfg.withDefaults()
+	return func(next Handler) Handler {
+		breaker := newCircuitBreaker(handlerName(next), cfg)
+		return HandlerFunc(func(ctx context.Context, evt Event) ([]Event, error) {
+			if !breaker.allow() {
+				err := &EventError{
+					Event:     evt,
+					Handler:   breaker.handler,
+					Message:   "handler short-circuited",
+					Err:       ErrCircuitOpen,
+					Timestamp: time.Now(),
+				}
+				if cfg.DLQ != nil {
+					_ = cfg.DLQ.Enqueue(ctx, NewFailedEvent(evt, err, breaker.handler))
+				}
+				return nil, err
+			}
+
+			result, err := next.Handle(ctx, evt)
+			breaker.record(err != nil && cfg.IsFailure(err))
+			return result, err
+		})
+	}
+}
+
+// withDefaults fills zero fields from DefaultCircuitBreakerConfig.
+func (cfg CircuitBreakerConfig) withDefaults() CircuitBreakerConfig {
+	if cfg.FailureRate <= 0 {
+		cfg.FailureRate = DefaultCircuitBreakerConfig.FailureRate
+	}
+	if cfg.MinEvents <= 0 {
+		cfg.MinEvents = DefaultCircuitBreakerConfig.MinEvents
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultCircuitBreakerConfig.Window
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = DefaultCircuitBreakerConfig.Cooldown
+	}
+	if cfg.HalfOpenEvents <= 0 {
+		cfg.HalfOpenEvents = DefaultCircuitBreakerConfig.HalfOpenEvents
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = func(error) bool { return true }
+	}
+	return cfg
+}
+
+// breakerBuckets is the number of time buckets the window is split into.
+const breakerBuckets = 10
+
+// breakerBucket counts outcomes in one slice of the window.
+type breakerBucket struct {
+	epoch     int64
+	successes int
+	failures  int
+}
+
+// circuitBreaker is the breaker for one handler.
+type circuitBreaker struct {
+	handler string
+	cfg     CircuitBreakerConfig
+	width   time.Duration
+
+	mu       sync.Mutex
+	state    BreakerState
+	buckets  [breakerBuckets]breakerBucket
+	openedAt time.Time
+	trials   int // Trial events admitted while half-open
+	passed   int // Trial events that succeeded
+}
+
+func newCircuitBreaker(handler string, cfg CircuitBreakerConfig) *circuitBreaker {
+	width := cfg.Window / breakerBuckets
+	if width <= 0 {
+		width = 1
+	}
+	return &circuitBreaker{handler: handler, cfg: cfg, width: width}
+}
+
+// allow reports whether an event may reach the handler, half-opening the
+// breaker once the cooldown has passed.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	var from BreakerState
+	changed := false
+
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.cfg.Cooldown {
+		from, changed = b.state, true
+		b.state = BreakerHalfOpen
+		b.trials, b.passed = 0, 0
+	}
+
+	allowed := true
+	switch b.state {
+	case BreakerOpen:
+		allowed = false
+	case BreakerHalfOpen:
+		if b.trials >= b.cfg.HalfOpenEvents {
+			allowed = false
+		} else {
+			b.trials++
+		}
+	}
+	b.mu.Unlock()
+
+	if changed {
+		b.notify(from, BreakerHalfOpen)
+	}
+	return allowed
+}
+
+// record counts the outcome of an event the handler processed.
+func (b *circuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	from := b.state
+	to := b.state
+
+	switch b.state {
+	case BreakerHalfOpen:
+		if failed {
+			to = b.open()
+		} else if b.passed++; b.passed >= b.cfg.HalfOpenEvents {
+			to = BreakerClosed
+			b.state = BreakerClosed
+			b.buckets = [breakerBuckets]breakerBucket{}
+		}
+	case BreakerClosed:
+		bucket := b.bucket(time.Now())
+		if failed {
+			bucket.failures++
+		} else {
+			bucket.successes++
+		}
+		if b.tripped() {
+			to = b.open()
+		}
+	}
+	b.mu.Unlock()
+
+	if to != from {
+		b.notify(from, to)
+	}
+}
+
+// open moves the breaker to the open state. b.mu must be held.
+func (b *circuitBreaker) open() BreakerState {
+	b.state = BreakerOpen
+	b.openedAt = time.Now()
+	b.buckets = [breakerBuckets]breakerBucket{}
+	return BreakerOpen
+}
+
+// bucket returns the bucket for now, resetting it if stale. b.mu must be
+// held.
+func (b *circuitBreaker) bucket(now time.Time) *breakerBucket {
+	epoch := now.UnixNano() / int64(b.width)
+	bucket := &b.buckets[epoch%breakerBuckets]
+	if bucket.epoch != epoch {
+		*bucket = breakerBucket{epoch: epoch}
+	}
+	return bucket
+}
+
+// tripped reports whether the failure rate within the window has reached
+// the threshold. b.mu must be held.
+func (b *circuitBreaker) tripped() bool {
+	oldest := time.Now().UnixNano()/int64(b.width) - breakerBuckets + 1
+	var successes, failures int
+	for _, bucket := range b.buckets {
+		if bucket.epoch >= oldest {
+			successes += bucket.successes
+			failures += bucket.failures
+		}
+	}
+
+	total := successes + failures
+	return total >= b.cfg.MinEvents && float64(failures)/float64(total) >= b.cfg.FailureRate
+}
+
+// notify reports a state transition.
+func (b *circuitBreaker) notify(from, to BreakerState) {
+	if b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(b.handler, from, to)
+	}
+}
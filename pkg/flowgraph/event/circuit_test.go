@@ -0,0 +1,224 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+)
+
+// flakyHandler fails while failing is set.
+type flakyHandler struct {
+	failing atomic.Bool
+	calls   atomic.Int32
+}
+
+func (h *flakyHandler) Handle(ctx context.Context, evt event.Event) ([]event.Event, error) {
+	h.calls.Add(1)
+	if h.failing.Load() {
+		return nil, errors.New("downstream unavailable")
+	}
+	return nil, nil
+}
+
+func (h *flakyHandler) Handles() []string { return nil }
+
+// transitionRecorder records breaker state changes.
+type transitionRecorder struct {
+	mu          sync.Mutex
+	transitions []string
+}
+
+func (r *transitionRecorder) record(handler string, from, to event.BreakerState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transitions = append(r.transitions, from.String()+"->"+to.String())
+}
+
+func (r *transitionRecorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.transitions...)
+}
+
+func TestCircuitBreakerMiddleware(t *testing.T) {
+	handler := &flakyHandler{}
+	handler.failing.Store(true)
+	rec := &transitionRecorder{}
+
+	wrapped := event.CircuitBreakerMiddleware(event.CircuitBreakerConfig{
+		FailureRate:   0.5,
+		MinEvents:     4,
+		Window:        time.Minute,
+		Cooldown:      50 * time.Millisecond,
+		OnStateChange: rec.record,
+	})(handler)
+
+	ctx := context.Background()
+	evt := event.NewAny("test", "test", "t1", nil)
+
+	// Failures below MinEvents do not trip the breaker
+	for i := 0; i < 3; i++ {
+		wrapped.Handle(ctx, evt)
+	}
+	if len(rec.get()) != 0 {
+		t.Fatalf("expected breaker closed before MinEvents, got %v", rec.get())
+	}
+
+	// The fourth failure trips it
+	wrapped.Handle(ctx, evt)
+	if got := rec.get(); len(got) != 1 || got[0] != "closed->open" {
+		t.Fatalf("expected closed->open, got %v", got)
+	}
+
+	// Open: fail fast without calling the handler
+	calls := handler.calls.Load()
+	_, err := wrapped.Handle(ctx, evt)
+	if !errors.Is(err, event.ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	var evtErr *event.EventError
+	if !errors.As(err, &evtErr) || evtErr.Event != evt {
+		t.Errorf("expected EventError for the event, got %v", err)
+	}
+	if handler.calls.Load() != calls {
+		t.Error("expected handler not to be called while open")
+	}
+
+	// After the cooldown a failing trial reopens it
+	time.Sleep(60 * time.Millisecond)
+	wrapped.Handle(ctx, evt)
+	if got := rec.get(); len(got) != 3 || got[1] != "open->half-open" || got[2] != "half-open->open" {
+		t.Fatalf("expected half-open then open, got %v", got)
+	}
+
+	// A succeeding trial closes it
+	handler.failing.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := wrapped.Handle(ctx, evt); err != nil {
+		t.Fatalf("expected trial to succeed, got %v", err)
+	}
+	if got := rec.get(); len(got) != 5 || got[4] != "half-open->closed" {
+		t.Fatalf("expected half-open->closed, got %v", got)
+	}
+	if _, err := wrapped.Handle(ctx, evt); err != nil {
+		t.Errorf("expected closed breaker to pass events, got %v", err)
+	}
+}
+
+func TestCircuitBreakerHalfOpenLimit(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	failing := atomic.Bool{}
+	failing.Store(true)
+
+	handler := event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		calls.Add(1)
+		if failing.Load() {
+			return nil, errors.New("boom")
+		}
+		<-release
+		return nil, nil
+	})
+	wrapped := event.CircuitBreakerMiddleware(event.CircuitBreakerConfig{
+		MinEvents:      1,
+		Cooldown:       10 * time.Millisecond,
+		HalfOpenEvents: 1,
+	})(handler)
+
+	ctx := context.Background()
+	evt := event.NewAny("test", "test", "t1", nil)
+	wrapped.Handle(ctx, evt) // Trips
+	failing.Store(false)
+	time.Sleep(20 * time.Millisecond)
+
+	// The trial blocks in the handler; further events are rejected
+	done := make(chan struct{})
+	go func() {
+		wrapped.Handle(ctx, evt)
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	if _, err := wrapped.Handle(ctx, evt); !errors.Is(err, event.ErrCircuitOpen) {
+		t.Errorf("expected rejection beyond the trial limit, got %v", err)
+	}
+	close(release)
+	<-done
+
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 handler calls, got %d", calls.Load())
+	}
+}
+
+func TestCircuitBreakerIsFailure(t *testing.T) {
+	errIgnored := errors.New("bad input")
+	handler := event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		return nil, errIgnored
+	})
+	rec := &transitionRecorder{}
+	wrapped := event.CircuitBreakerMiddleware(event.CircuitBreakerConfig{
+		MinEvents:     1,
+		IsFailure:     func(err error) bool { return !errors.Is(err, errIgnored) },
+		OnStateChange: rec.record,
+	})(handler)
+
+	for i := 0; i < 5; i++ {
+		wrapped.Handle(context.Background(), event.NewAny("test", "test", "t1", nil))
+	}
+	if len(rec.get()) != 0 {
+		t.Errorf("expected ignored errors not to trip the breaker, got %v", rec.get())
+	}
+}
+
+func TestCircuitBreakerWithRouter(t *testing.T) {
+	dlq := event.NewInMemoryDLQ(event.DLQConfig{})
+	handler := &flakyHandler{}
+	handler.failing.Store(true)
+
+	router := event.NewRouter(event.RouterConfig{DLQ: dlq})
+	router.Use(event.RecoveryMiddleware())
+	router.Use(event.CircuitBreakerMiddleware(event.CircuitBreakerConfig{
+		MinEvents: 2,
+		Cooldown:  time.Minute,
+	}))
+	detector := event.NewInMemoryPoisonPillDetector(event.DefaultInMemoryPoisonPillConfig)
+	defer detector.Close()
+	router.Use(event.PoisonPillMiddleware(detector))
+	router.Register(handler)
+
+	for i := 0; i < 5; i++ {
+		router.Route(context.Background(), event.NewAny("test", "test", "t1", i))
+	}
+
+	if handler.calls.Load() != 2 {
+		t.Errorf("expected handler called until the breaker opened, got %d calls", handler.calls.Load())
+	}
+	// Both handler failures and short-circuited events are dead-lettered
+	if n, _ := dlq.Len(context.Background()); n != 5 {
+		t.Errorf("expected 5 dead-lettered events, got %d", n)
+	}
+}
+
+func TestCircuitBreakerDLQ(t *testing.T) {
+	dlq := event.NewInMemoryDLQ(event.DLQConfig{})
+	handler := event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		return nil, errors.New("boom")
+	})
+	wrapped := event.CircuitBreakerMiddleware(event.CircuitBreakerConfig{
+		MinEvents: 1,
+		Cooldown:  time.Minute,
+		DLQ:       dlq,
+	})(handler)
+
+	wrapped.Handle(context.Background(), event.NewAny("test", "test", "t1", nil)) // Trips
+	wrapped.Handle(context.Background(), event.NewAny("test", "test", "t1", nil)) // Rejected
+
+	if n, _ := dlq.Len(context.Background()); n != 1 {
+		t.Errorf("expected the rejected event to be dead-lettered, got %d", n)
+	}
+}
@@ -0,0 +1,66 @@
+package event
+
+import "fmt"
+
+// Codec encodes events to and from a wire format such as JSON, Protobuf,
+// or Avro. Implementations must be safe for concurrent use.
+//
+// A codec must preserve every envelope field through Encode and Decode:
+//
+//   - ID, Type, Source
+//   - CorrelationID, CausationID
+//   - Version (schema version)
+//   - TenantID
+//   - Timestamp, to at least microsecond precision
+//   - Data, carried as the bytes returned by DataBytes
+//
+// The decoded event's DataBytes must hold the same JSON value as the
+// original's, though not necessarily byte for byte, and Data may be
+// decoded generically; TypedHandler converts it as needed. MetadataOf
+// collects the fields to encode from any Event.
+type Codec interface {
+	// Encode serializes an event.
+	Encode(evt Event) ([]byte, error)
+
+	// Decode deserializes an event produced by Encode.
+	Decode(data []byte) (Event, error)
+
+	// ContentType identifies the format, e.g. "application/json".
+	// It is sent as the Content-Type of webhook deliveries and recorded
+	// on failed events encoded with NewFailedEventWithCodec.
+	ContentType() string
+}
+
+// DefaultCodec is the codec used when none is configured.
+var DefaultCodec Codec = JSONCodec{}
+
+// JSONCodec encodes events as JSON envelopes (see Marshal).
+type JSONCodec struct{}
+
+// Encode implements Codec.
+func (JSONCodec) Encode(evt Event) ([]byte, error) {
+	return Marshal(evt)
+}
+
+// Decode implements Codec. The payload is decoded generically.
+func (JSONCodec) Decode(data []byte) (Event, error) {
+	return Unmarshal(data)
+}
+
+// ContentType implements Codec.
+func (JSONCodec) ContentType() string {
+	return "application/json"
+}
+
+// roundTrip encodes and decodes evt with codec.
+func roundTrip(codec Codec, evt Event) (Event, error) {
+	data, err := codec.Encode(evt)
+	if err != nil {
+		return nil, fmt.Errorf("encode: %w", err)
+	}
+	decoded, err := codec.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+	return decoded, nil
+}
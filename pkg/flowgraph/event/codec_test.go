@@ -0,0 +1,224 @@
+package event_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+)
+
+// gobCodec is a stub binary codec standing in for Protobuf or Avro.
+type gobCodec struct{}
+
+type gobEnvelope struct {
+	Meta    event.Metadata
+	Payload []byte
+}
+
+func (gobCodec) Encode(evt event.Event) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(gobEnvelope{Meta: event.MetadataOf(evt), Payload: evt.DataBytes()})
+	return buf.Bytes(), err
+}
+
+func (gobCodec) Decode(data []byte) (event.Event, error) {
+	var env gobEnvelope
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&env); err != nil {
+		return nil, err
+	}
+	var payload any
+	if err := json.Unmarshal(env.Payload, &payload); err != nil {
+		return nil, err
+	}
+	return &event.BaseEvent[any]{Meta: env.Meta, Payload: payload}, nil
+}
+
+func (gobCodec) ContentType() string { return "application/x-gob" }
+
+func sampleEvent() event.Event {
+	return event.New("order.created", "orders", "tenant-1",
+		map[string]any{"id": "o-1", "total": 42.5},
+		event.WithCorrelationID("corr-1"),
+		event.WithCausationID("cause-1"),
+		event.WithSchemaVersion(3),
+		event.WithTimestamp(time.Date(2026, 1, 2, 3, 4, 5, 6000, time.UTC)))
+}
+
+func assertPreserved(t *testing.T, want, got event.Event) {
+	t.Helper()
+	if !reflect.DeepEqual(event.MetadataOf(want), event.MetadataOf(got)) {
+		t.Errorf("metadata not preserved:\nwant %+v\ngot  %+v", event.MetadataOf(want), event.MetadataOf(got))
+	}
+	var wantData, gotData any
+	if err := json.Unmarshal(want.DataBytes(), &wantData); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(got.DataBytes(), &gotData); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(wantData, gotData) {
+		t.Errorf("data not preserved: want %v, got %v", wantData, gotData)
+	}
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	codecs := map[string]event.Codec{
+		"json": event.JSONCodec{},
+		"stub": gobCodec{},
+	}
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			evt := sampleEvent()
+
+			data, err := codec.Encode(evt)
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			decoded, err := codec.Decode(data)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+
+			assertPreserved(t, evt, decoded)
+		})
+	}
+}
+
+func TestJSONCodec_MatchesMarshal(t *testing.T) {
+	evt := sampleEvent()
+
+	fromCodec, err := event.JSONCodec{}.Encode(evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromMarshal, err := event.Marshal(evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(fromCodec, fromMarshal) {
+		t.Errorf("JSONCodec output differs from Marshal:\n%s\n%s", fromCodec, fromMarshal)
+	}
+	if event.DefaultCodec.ContentType() != "application/json" {
+		t.Errorf("unexpected default content type %q", event.DefaultCodec.ContentType())
+	}
+}
+
+func TestBus_CodecRoundTripsEvents(t *testing.T) {
+	bus := event.NewBus(event.BusConfig{Codec: gobCodec{}})
+	defer bus.Close()
+
+	received := make(chan event.Event, 1)
+	bus.SubscribeAll(event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		received <- evt
+		return nil, nil
+	}))
+
+	evt := sampleEvent()
+	if err := bus.Publish(context.Background(), evt); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	select {
+	case got := <-received:
+		if got == evt {
+			t.Error("expected subscriber to receive the decoded copy")
+		}
+		assertPreserved(t, evt, got)
+	case <-time.After(time.Second):
+		t.Fatal("event not delivered")
+	}
+}
+
+type failingCodec struct{ gobCodec }
+
+func (failingCodec) Encode(event.Event) ([]byte, error) { return nil, errors.New("unsupported") }
+
+func TestBus_CodecErrorFailsPublish(t *testing.T) {
+	bus := event.NewBus(event.BusConfig{Codec: failingCodec{}})
+	defer bus.Close()
+
+	err := bus.Publish(context.Background(), sampleEvent())
+
+	var evtErr *event.EventError
+	if !errors.As(err, &evtErr) {
+		t.Fatalf("expected EventError, got %v", err)
+	}
+}
+
+func TestDLQProcessor_RestoresCodecEncodedEvents(t *testing.T) {
+	dlq := event.NewInMemoryDLQ(event.DLQConfig{
+		RetryDelay: time.Millisecond,
+		Codec:      gobCodec{},
+	})
+
+	routed := make(chan event.Event, 1)
+	router := event.NewRouter(event.RouterConfig{})
+	router.Register(event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		routed <- evt
+		return nil, nil
+	}))
+
+	evt := sampleEvent()
+	failed, err := event.NewFailedEventWithCodec(evt, errors.New("boom"), "handler", gobCodec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed.Encoding != "application/x-gob" {
+		t.Errorf("unexpected encoding %q", failed.Encoding)
+	}
+	if err := dlq.Enqueue(context.Background(), failed); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	processor := event.NewDLQProcessor(dlq, router, event.DLQProcessorConfig{PollInterval: 5 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	processor.Start(ctx)
+	defer processor.Stop()
+
+	select {
+	case got := <-routed:
+		assertPreserved(t, evt, got)
+	case <-time.After(time.Second):
+		t.Fatal("event not retried")
+	}
+}
+
+func TestWebhookHandler_Codec(t *testing.T) {
+	var (
+		gotBody        []byte
+		gotContentType string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotContentType = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	hook := event.NewWebhookHandler(server.URL, event.WithWebhookCodec(gobCodec{}))
+	evt := sampleEvent()
+	if _, err := hook.Handle(context.Background(), evt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotContentType != "application/x-gob" {
+		t.Errorf("expected codec content type, got %q", gotContentType)
+	}
+	decoded, err := gobCodec{}.Decode(gotBody)
+	if err != nil {
+		t.Fatalf("body not decodable by codec: %v", err)
+	}
+	assertPreserved(t, evt, decoded)
+}
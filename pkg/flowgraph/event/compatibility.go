@@ -0,0 +1,446 @@
+package event
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// CompatibilityMode selects which readers a new schema version must not
+// break, following the Confluent Schema Registry definitions.
+type CompatibilityMode int
+
+const (
+	// CompatibilityBackward requires the new version to read payloads
+	// written with the previous version, so consumers can upgrade first.
+	CompatibilityBackward CompatibilityMode = iota
+
+	// CompatibilityForward requires the previous version to read payloads
+	// written with the new version, so producers can upgrade first.
+	CompatibilityForward
+
+	// CompatibilityFull requires both backward and forward compatibility.
+	CompatibilityFull
+)
+
+// String returns the name of the mode.
+func (m CompatibilityMode) String() string {
+	switch m {
+	case CompatibilityBackward:
+		return "backward"
+	case CompatibilityForward:
+		return "forward"
+	case CompatibilityFull:
+		return "full"
+	default:
+		return fmt.Sprintf("CompatibilityMode(%d)", int(m))
+	}
+}
+
+// CompatibilityError is returned by RegisterVersion when a schema version
+// breaks compatibility with a neighbouring registered version.
+type CompatibilityError struct {
+	// EventType and Version identify the rejected schema.
+	EventType string
+	Version   int
+
+	// Against is the registered version it was compared with.
+	Against int
+
+	// Mode is the compatibility mode that was violated.
+	Mode CompatibilityMode
+
+	// Issues lists each incompatibility. Paths are JSON Pointers into the
+	// payload, with "*" standing for any array item.
+	Issues []FieldError
+}
+
+// Error implements the error interface.
+func (e *CompatibilityError) Error() string {
+	parts := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		parts[i] = issue.String()
+	}
+	return fmt.Sprintf("%s v%d is not %s compatible with v%d: %s",
+		e.EventType, e.Version, e.Mode, e.Against, strings.Join(parts, "; "))
+}
+
+// RegisterVersion adds an event schema to the registry after checking its
+// JSON Schema against the nearest registered versions below and above it
+// under mode. Returns a *CompatibilityError if the schema is incompatible,
+// in which case nothing is registered. Otherwise it behaves like Register.
+//
+// A missing JSON Schema is treated as accepting any payload. The check
+// compares types, enum and const values, numeric, length and size bounds,
+// patterns, required properties, properties, additionalProperties, and
+// single-schema items. Combinators (allOf, anyOf, oneOf, not, if) are not
+// compared, and properties added to a schema that allows additional
+// properties are not reported.
+//
+// Example:
+//
+//	err := registry.RegisterVersion(&event.EventSchema{
+//	    Type:    "order.created",
+//	    Version: 2,
+//	    Schema:  schemaV2,
+//	}, event.CompatibilityBackward)
+//	var compatErr *event.CompatibilityError
+//	if errors.As(err, &compatErr) {
+//	    // v2 cannot read v1 payloads; see compatErr.Issues
+//	}
+func (r *EventRegistry) RegisterVersion(schema *EventSchema, mode CompatibilityMode) error {
+	if err := prepareSchema(schema); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var prev, next *EventSchema
+	for v, s := range r.versions[schema.Type] {
+		if v < schema.Version && (prev == nil || v > prev.Version) {
+			prev = s
+		}
+		if v > schema.Version && (next == nil || v < next.Version) {
+			next = s
+		}
+	}
+
+	for _, pair := range [][2]*EventSchema{{prev, schema}, {schema, next}} {
+		older, newer := pair[0], pair[1]
+		if older == nil || newer == nil {
+			continue
+		}
+		issues, err := compatibilityIssues(older, newer, mode)
+		if err != nil {
+			return err
+		}
+		if len(issues) > 0 {
+			against := older
+			if against == schema {
+				against = newer
+			}
+			return &CompatibilityError{
+				EventType: schema.Type,
+				Version:   schema.Version,
+				Against:   against.Version,
+				Mode:      mode,
+				Issues:    issues,
+			}
+		}
+	}
+
+	r.registerLocked(schema)
+	return nil
+}
+
+// compatibilityIssues returns the ways newer fails to follow older under
+// mode.
+func compatibilityIssues(older, newer *EventSchema, mode CompatibilityMode) ([]FieldError, error) {
+	oldSchema, err := older.jsonSchema()
+	if err != nil {
+		return nil, err
+	}
+	newSchema, err := newer.jsonSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []FieldError
+	if mode == CompatibilityBackward || mode == CompatibilityFull {
+		issues = append(issues, compareSchemas(newSchema, newer.Version, oldSchema, older.Version)...)
+	}
+	if mode == CompatibilityForward || mode == CompatibilityFull {
+		issues = append(issues, compareSchemas(oldSchema, older.Version, newSchema, newer.Version)...)
+	}
+	return issues, nil
+}
+
+// jsonSchema returns the schema's compiled JSON Schema, or one accepting
+// any payload if it has none.
+func (s *EventSchema) jsonSchema() (*jsonSchema, error) {
+	if s.compiled != nil {
+		return s.compiled, nil
+	}
+	if len(s.Schema) == 0 {
+		return &jsonSchema{root: true}, nil
+	}
+	compiled, err := compileJSONSchema(s.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("schema %s v%d: %w", s.Type, s.Version, err)
+	}
+	return compiled, nil
+}
+
+// maxCompareDepth bounds recursion through "$ref" cycles.
+const maxCompareDepth = 32
+
+// schemaComparison reports payloads a writer schema allows that a reader
+// schema rejects.
+type schemaComparison struct {
+	reader, writer   *jsonSchema
+	readerV, writerV int
+	issues           []FieldError
+}
+
+// compareSchemas returns the ways reader fails to accept writer's payloads.
+func compareSchemas(reader *jsonSchema, readerV int, writer *jsonSchema, writerV int) []FieldError {
+	c := &schemaComparison{reader: reader, writer: writer, readerV: readerV, writerV: writerV}
+	c.compare(reader.root, writer.root, "", 0)
+	return c.issues
+}
+
+// report records an issue at path.
+func (c *schemaComparison) report(path, keyword, format string, args ...any) {
+	c.issues = append(c.issues, FieldError{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+}
+
+// compare checks reader schema r against writer schema w at path.
+func (c *schemaComparison) compare(r, w any, path string, depth int) {
+	if depth > maxCompareDepth {
+		return
+	}
+
+	r = c.deref(c.reader, r)
+	w = c.deref(c.writer, w)
+
+	if allowed, ok := w.(bool); ok && !allowed {
+		return // The writer produces nothing here
+	}
+	if allowed, ok := r.(bool); ok {
+		if !allowed {
+			c.report(path, "false", "v%d rejects values v%d allows", c.readerV, c.writerV)
+		}
+		return
+	}
+	rObj, _ := r.(map[string]any)
+	wObj, _ := w.(map[string]any)
+	if wObj == nil {
+		wObj = map[string]any{}
+	}
+	if rObj == nil {
+		return
+	}
+
+	if !c.compareTypes(rObj, wObj, path) {
+		return
+	}
+	c.compareValues(rObj, wObj, path)
+	c.compareBounds(rObj, wObj, path)
+	c.compareObjects(rObj, wObj, path, depth)
+
+	rItems, rOK := rObj["items"]
+	wItems, wOK := wObj["items"]
+	if rOK && isSchema(rItems) {
+		if !wOK || !isSchema(wItems) {
+			wItems = true
+		}
+		c.compare(rItems, wItems, childPath(path, "*"), depth+1)
+	}
+}
+
+// deref follows a local "$ref" within s.
+func (c *schemaComparison) deref(s *jsonSchema, schema any) any {
+	for i := 0; i < maxCompareDepth; i++ {
+		obj, ok := schema.(map[string]any)
+		if !ok {
+			return schema
+		}
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return schema
+		}
+		target, err := s.resolve(ref)
+		if err != nil {
+			return true
+		}
+		schema = target
+	}
+	return schema
+}
+
+// compareTypes reports writer types the reader rejects. Returns false if
+// the schemas share no type, since further comparison is then noise.
+func (c *schemaComparison) compareTypes(r, w map[string]any, path string) bool {
+	rTypes := schemaTypes(r)
+	if rTypes == nil {
+		return true
+	}
+	wTypes := schemaTypes(w)
+	if wTypes == nil {
+		c.report(path, "type", "v%d requires type %s but v%d allows any type",
+			c.readerV, strings.Join(rTypes, " or "), c.writerV)
+		return true
+	}
+
+	shared := false
+	for _, t := range wTypes {
+		if typeAccepts(rTypes, t) {
+			shared = true
+		} else {
+			c.report(path, "type", "type %s from v%d is not accepted by v%d", t, c.writerV, c.readerV)
+		}
+	}
+	return shared
+}
+
+// compareValues compares enum and const.
+func (c *schemaComparison) compareValues(r, w map[string]any, path string) {
+	allowed, ok := schemaValues(r)
+	if !ok {
+		return
+	}
+	written, ok := schemaValues(w)
+	if !ok {
+		c.report(path, "enum", "v%d only accepts %s but v%d allows other values",
+			c.readerV, formatValues(allowed), c.writerV)
+		return
+	}
+	for _, v := range written {
+		if !slices.ContainsFunc(allowed, func(a any) bool { return jsonEqual(a, v) }) {
+			c.report(path, "enum", "value %s from v%d is not accepted by v%d", formatValue(v), c.writerV, c.readerV)
+		}
+	}
+}
+
+// compareBounds compares numeric, length and size bounds, multipleOf and
+// pattern.
+func (c *schemaComparison) compareBounds(r, w map[string]any, path string) {
+	for _, key := range []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties"} {
+		c.compareBound(r, w, path, key, func(rv, wv float64) bool { return wv >= rv })
+	}
+	for _, key := range []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties"} {
+		c.compareBound(r, w, path, key, func(rv, wv float64) bool { return wv <= rv })
+	}
+	c.compareBound(r, w, path, "multipleOf", func(rv, wv float64) bool {
+		q := wv / rv
+		return math.Abs(q-math.Round(q)) < 1e-9
+	})
+
+	if rp, ok := r["pattern"].(string); ok {
+		if wp, _ := w["pattern"].(string); wp != rp {
+			c.report(path, "pattern", "v%d requires pattern %q, which v%d does not", c.readerV, rp, c.writerV)
+		}
+	}
+	if unique, _ := r["uniqueItems"].(bool); unique {
+		if wUnique, _ := w["uniqueItems"].(bool); !wUnique {
+			c.report(path, "uniqueItems", "v%d requires unique items but v%d does not", c.readerV, c.writerV)
+		}
+	}
+}
+
+// compareBound reports a reader bound the writer does not guarantee.
+func (c *schemaComparison) compareBound(r, w map[string]any, path, key string, within func(rv, wv float64) bool) {
+	rv, ok := schemaNumber(r, key)
+	if !ok {
+		return
+	}
+	wv, ok := schemaNumber(w, key)
+	if !ok {
+		c.report(path, key, "v%d requires %s %v but v%d does not", c.readerV, key, rv, c.writerV)
+		return
+	}
+	if !within(rv, wv) {
+		c.report(path, key, "%s %v in v%d is stricter than %v in v%d", key, rv, c.readerV, wv, c.writerV)
+	}
+}
+
+// compareObjects compares required properties, properties and
+// additionalProperties.
+func (c *schemaComparison) compareObjects(r, w map[string]any, path string, depth int) {
+	wRequired := stringSet(w["required"])
+	if rRequired, ok := r["required"].([]any); ok {
+		for _, name := range rRequired {
+			name, _ := name.(string)
+			if !wRequired[name] {
+				c.report(childPath(path, name), "required", "required in v%d but optional in v%d", c.readerV, c.writerV)
+			}
+		}
+	}
+
+	rProps, _ := r["properties"].(map[string]any)
+	wProps, _ := w["properties"].(map[string]any)
+	rAdditional, rHasAdditional := r["additionalProperties"]
+	wAdditional, wHasAdditional := w["additionalProperties"]
+	rClosed := rHasAdditional && rAdditional == false
+	wClosed := wHasAdditional && wAdditional == false
+
+	names := make([]string, 0, len(rProps)+len(wProps))
+	for name := range rProps {
+		names = append(names, name)
+	}
+	for name := range wProps {
+		if _, ok := rProps[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p := childPath(path, name)
+		rProp, inReader := rProps[name]
+		wProp, inWriter := wProps[name]
+		switch {
+		case inReader && inWriter:
+			c.compare(rProp, wProp, p, depth+1)
+		case inWriter && rClosed:
+			c.report(p, "additionalProperties", "property from v%d is not allowed by v%d", c.writerV, c.readerV)
+		case inWriter && rHasAdditional:
+			c.compare(rAdditional, wProp, p, depth+1)
+		case inReader && wHasAdditional && !wClosed:
+			c.compare(rProp, wAdditional, p, depth+1)
+		}
+	}
+
+	if rClosed && !wClosed {
+		c.report(path, "additionalProperties", "v%d rejects additional properties that v%d allows", c.readerV, c.writerV)
+	}
+}
+
+// schemaTypes returns the "type" keyword as a list, or nil if absent.
+func schemaTypes(obj map[string]any) []string {
+	switch t := obj["type"].(type) {
+	case string:
+		return []string{t}
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, name := range t {
+			if s, ok := name.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// typeAccepts reports whether a value of type t passes a "type" keyword
+// listing types.
+func typeAccepts(types []string, t string) bool {
+	return slices.Contains(types, t) || (t == "integer" && slices.Contains(types, "number"))
+}
+
+// schemaValues returns the values allowed by "const" or "enum".
+func schemaValues(obj map[string]any) ([]any, bool) {
+	if c, ok := obj["const"]; ok {
+		return []any{c}, true
+	}
+	if enum, ok := obj["enum"].([]any); ok {
+		return enum, true
+	}
+	return nil, false
+}
+
+// stringSet converts a JSON array of strings to a set.
+func stringSet(v any) map[string]bool {
+	set := make(map[string]bool)
+	list, _ := v.([]any)
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,201 @@
+package event_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+)
+
+const customerV1 = `{
+	"type": "object",
+	"required": ["id", "email"],
+	"properties": {
+		"id":    {"type": "string"},
+		"email": {"type": "string"},
+		"tier":  {"enum": ["free", "pro"]}
+	}
+}`
+
+func newCustomerRegistry(t *testing.T) *event.EventRegistry {
+	t.Helper()
+	registry := event.NewEventRegistry()
+	err := registry.RegisterVersion(&event.EventSchema{
+		Type:    "customer.updated",
+		Version: 1,
+		Schema:  json.RawMessage(customerV1),
+	}, event.CompatibilityFull)
+	if err != nil {
+		t.Fatalf("register v1: %v", err)
+	}
+	return registry
+}
+
+func TestRegisterVersionCompatibility(t *testing.T) {
+	tests := []struct {
+		name   string
+		v2     string
+		mode   event.CompatibilityMode
+		issues []string // "path keyword"; nil means compatible
+	}{
+		{
+			name: "optional field added",
+			v2:   `{"type": "object", "required": ["id", "email"], "properties": {"id": {"type": "string"}, "email": {"type": "string"}, "tier": {"enum": ["free", "pro"]}, "name": {"type": "string"}}}`,
+			mode: event.CompatibilityFull,
+		},
+		{
+			name:   "required field added breaks backward",
+			v2:     `{"type": "object", "required": ["id", "email", "name"], "properties": {"id": {"type": "string"}, "email": {"type": "string"}, "name": {"type": "string"}}}`,
+			mode:   event.CompatibilityBackward,
+			issues: []string{"/name required"},
+		},
+		{
+			name: "required field added keeps forward",
+			v2:   `{"type": "object", "required": ["id", "email", "name"], "properties": {"id": {"type": "string"}, "email": {"type": "string"}, "name": {"type": "string"}}}`,
+			mode: event.CompatibilityForward,
+		},
+		{
+			name:   "required field removed breaks forward",
+			v2:     `{"type": "object", "required": ["id"], "properties": {"id": {"type": "string"}, "email": {"type": "string"}}}`,
+			mode:   event.CompatibilityForward,
+			issues: []string{"/email required"},
+		},
+		{
+			name:   "required field removed from closed schema breaks backward",
+			v2:     `{"type": "object", "required": ["id"], "properties": {"id": {"type": "string"}}, "additionalProperties": false}`,
+			mode:   event.CompatibilityBackward,
+			issues: []string{"/email additionalProperties", "/tier additionalProperties", " additionalProperties"},
+		},
+		{
+			name:   "type narrowed",
+			v2:     `{"type": "object", "required": ["id", "email"], "properties": {"id": {"type": "integer"}, "email": {"type": "string"}}}`,
+			mode:   event.CompatibilityBackward,
+			issues: []string{"/id type"},
+		},
+		{
+			name:   "enum value removed",
+			v2:     `{"type": "object", "required": ["id", "email"], "properties": {"id": {"type": "string"}, "email": {"type": "string"}, "tier": {"enum": ["pro"]}}}`,
+			mode:   event.CompatibilityBackward,
+			issues: []string{"/tier enum"},
+		},
+		{
+			name:   "enum value added breaks forward only",
+			v2:     `{"type": "object", "required": ["id", "email"], "properties": {"id": {"type": "string"}, "email": {"type": "string"}, "tier": {"enum": ["free", "pro", "team"]}}}`,
+			mode:   event.CompatibilityFull,
+			issues: []string{"/tier enum"},
+		},
+		{
+			name:   "bound added",
+			v2:     `{"type": "object", "required": ["id", "email"], "properties": {"id": {"type": "string"}, "email": {"type": "string", "minLength": 3}}}`,
+			mode:   event.CompatibilityBackward,
+			issues: []string{"/email minLength"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newCustomerRegistry(t)
+			err := registry.RegisterVersion(&event.EventSchema{
+				Type:    "customer.updated",
+				Version: 2,
+				Schema:  json.RawMessage(tt.v2),
+			}, tt.mode)
+
+			if tt.issues == nil {
+				if err != nil {
+					t.Fatalf("expected compatible, got %v", err)
+				}
+				if v, _ := registry.LatestVersion("customer.updated"); v != 2 {
+					t.Errorf("expected v2 to be registered, latest is v%d", v)
+				}
+				return
+			}
+
+			var compatErr *event.CompatibilityError
+			if !errors.As(err, &compatErr) {
+				t.Fatalf("expected CompatibilityError, got %v", err)
+			}
+			if compatErr.Version != 2 || compatErr.Against != 1 || compatErr.Mode != tt.mode {
+				t.Errorf("unexpected error identity: v%d against v%d (%s)", compatErr.Version, compatErr.Against, compatErr.Mode)
+			}
+			got := make([]string, len(compatErr.Issues))
+			for i, issue := range compatErr.Issues {
+				got[i] = issue.Path + " " + issue.Keyword
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.issues, ", ") {
+				t.Errorf("expected issues %v, got %v", tt.issues, compatErr.Issues)
+			}
+			if _, ok := registry.GetVersion("customer.updated", 2); ok {
+				t.Error("expected incompatible v2 not to be registered")
+			}
+		})
+	}
+}
+
+func TestRegisterVersionChecksNextVersion(t *testing.T) {
+	registry := newCustomerRegistry(t)
+	err := registry.RegisterVersion(&event.EventSchema{
+		Type:    "customer.updated",
+		Version: 3,
+		Schema:  json.RawMessage(customerV1),
+	}, event.CompatibilityBackward)
+	if err != nil {
+		t.Fatalf("register v3: %v", err)
+	}
+
+	// v2 sits between v1 and v3; v3 cannot read v2's unconstrained id
+	err = registry.RegisterVersion(&event.EventSchema{
+		Type:    "customer.updated",
+		Version: 2,
+		Schema:  json.RawMessage(`{"type": "object", "required": ["id", "email"], "properties": {"id": {}, "email": {"type": "string"}, "tier": {"enum": ["free", "pro"]}}}`),
+	}, event.CompatibilityBackward)
+
+	var compatErr *event.CompatibilityError
+	if !errors.As(err, &compatErr) {
+		t.Fatalf("expected CompatibilityError, got %v", err)
+	}
+	if compatErr.Against != 3 {
+		t.Errorf("expected comparison against v3, got v%d", compatErr.Against)
+	}
+	if !strings.Contains(err.Error(), "customer.updated v2 is not backward compatible with v3") {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestRegistryValidateByEventVersion(t *testing.T) {
+	registry := newCustomerRegistry(t)
+	err := registry.RegisterVersion(&event.EventSchema{
+		Type:    "customer.updated",
+		Version: 2,
+		Schema:  json.RawMessage(`{"type": "object", "required": ["id"], "properties": {"id": {"type": "string"}, "email": {"type": "string"}}}`),
+	}, event.CompatibilityBackward)
+	if err != nil {
+		t.Fatalf("register v2: %v", err)
+	}
+
+	payload := map[string]any{"id": "c1"}
+
+	// v2 made email optional
+	v2 := event.NewAny("customer.updated", "crm", "t1", payload, event.WithSchemaVersion(2))
+	if err := registry.Validate(v2); err != nil {
+		t.Errorf("expected v2 event to pass: %v", err)
+	}
+
+	// v1 events are still checked against v1
+	v1 := event.NewAny("customer.updated", "crm", "t1", payload, event.WithSchemaVersion(1))
+	var schemaErr *event.SchemaValidationError
+	if err := registry.Validate(v1); !errors.As(err, &schemaErr) || schemaErr.Version != 1 {
+		t.Errorf("expected v1 schema failure, got %v", err)
+	}
+}
+
+func TestCompatibilityModeString(t *testing.T) {
+	if event.CompatibilityFull.String() != "full" {
+		t.Errorf("unexpected name %q", event.CompatibilityFull.String())
+	}
+	if event.CompatibilityMode(9).String() != "CompatibilityMode(9)" {
+		t.Errorf("unexpected name %q", event.CompatibilityMode(9).String())
+	}
+}
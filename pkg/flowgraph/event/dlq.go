@@ -2,6 +2,7 @@ package event
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -44,11 +45,25 @@ type DLQConfig struct {
 	// Default: 1 minute
 	RetryDelay time.Duration
 
+	// VisibilityTimeout is how long an event returned by Dequeue is hidden
+	// from later Dequeue calls. If it is neither acknowledged nor
+	// rescheduled in that time, for example because the process crashed,
+	// it is offered again. Used by SQLiteDLQ; InMemoryDLQ removes events
+	// on Dequeue.
+	// Default: 5 minutes
+	VisibilityTimeout time.Duration
+
 	// OnEnqueue is called when an event is added.
 	OnEnqueue func(*FailedEvent)
 
 	// OnPark is called when an event is moved to PLQ.
 	OnPark func(*ParkedEvent)
+
+	// Codec decodes failed events created with NewFailedEventWithCodec
+	// when they are retried. Events encoded with a different content type
+	// are retried with their payload only.
+	// Default: DefaultCodec
+	Codec Codec
 }
 
 // DefaultDLQConfig provides reasonable defaults.
@@ -57,10 +72,12 @@ var DefaultDLQConfig = DLQConfig{
 	MaxRetries:  5,
 	RetryDelay:  1 * time.Minute,
 	RetryConfig: fgerrors.DefaultRetry,
+
+	VisibilityTimeout: 5 * time.Minute,
 }
 
-// NewInMemoryDLQ creates a new in-memory dead letter queue.
-func NewInMemoryDLQ(cfg DLQConfig) *InMemoryDLQ {
+// withDefaults fills unset fields from DefaultDLQConfig.
+func (cfg DLQConfig) withDefaults() DLQConfig {
 	if cfg.MaxSize <= 0 {
 		cfg.MaxSize = DefaultDLQConfig.MaxSize
 	}
@@ -70,11 +87,21 @@ func NewInMemoryDLQ(cfg DLQConfig) *InMemoryDLQ {
 	if cfg.RetryDelay <= 0 {
 		cfg.RetryDelay = DefaultDLQConfig.RetryDelay
 	}
+	if cfg.VisibilityTimeout <= 0 {
+		cfg.VisibilityTimeout = DefaultDLQConfig.VisibilityTimeout
+	}
+	if cfg.Codec == nil {
+		cfg.Codec = DefaultCodec
+	}
+	return cfg
+}
 
+// NewInMemoryDLQ creates a new in-memory dead letter queue.
+func NewInMemoryDLQ(cfg DLQConfig) *InMemoryDLQ {
 	return &InMemoryDLQ{
 		events: make(map[string]*FailedEvent),
 		plq:    make(map[string]*ParkedEvent),
-		cfg:    cfg,
+		cfg:    cfg.withDefaults(),
 	}
 }
 
@@ -330,6 +357,43 @@ func (d *InMemoryDLQ) DeleteParked(ctx context.Context, eventID string) error {
 	return nil
 }
 
+// DrainAll removes and returns all queued events, including those not yet
+// due for retry. Implements DLQDrainer.
+func (d *InMemoryDLQ) DrainAll(ctx context.Context) ([]*FailedEvent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	drained := make([]*FailedEvent, 0, len(d.events))
+	for _, evt := range d.events {
+		drained = append(drained, evt)
+	}
+	d.events = make(map[string]*FailedEvent)
+	return drained, nil
+}
+
+// DrainParked removes and returns all parked events. Implements ParkedDLQ.
+func (d *InMemoryDLQ) DrainParked(ctx context.Context) ([]*ParkedEvent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	drained := make([]*ParkedEvent, 0, len(d.plq))
+	for _, evt := range d.plq {
+		drained = append(drained, evt)
+	}
+	d.plq = make(map[string]*ParkedEvent)
+	return drained, nil
+}
+
+// ImportParked stores an already-parked event as-is, keeping its reason
+// and timestamps. OnPark is not called. Implements ParkedDLQ.
+func (d *InMemoryDLQ) ImportParked(ctx context.Context, parked *ParkedEvent) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.plq[parked.EventID] = parked
+	return nil
+}
+
 // Stats returns DLQ statistics.
 func (d *InMemoryDLQ) Stats() DLQStats {
 	d.mu.RLock()
@@ -355,13 +419,28 @@ type DLQStats struct {
 	Recovered  int64 // Total events recovered
 }
 
+// RetryableDLQ is a DeadLetterQueue that records the outcome of retries,
+// as DLQProcessor requires. InMemoryDLQ and SQLiteDLQ implement it.
+type RetryableDLQ interface {
+	DeadLetterQueue
+
+	// RecordRetrySuccess removes an event after a successful retry.
+	RecordRetrySuccess(ctx context.Context, eventID string) error
+
+	// RecordRetryFailure reschedules a dequeued event after a failed
+	// retry, parking it once it reaches the retry limit.
+	RecordRetryFailure(ctx context.Context, failed *FailedEvent) error
+}
+
 // DLQProcessor processes events from a DLQ.
 type DLQProcessor struct {
-	dlq     *InMemoryDLQ
+	dlq     RetryableDLQ
 	router  Router
 	cfg     DLQProcessorConfig
 	stopCh  chan struct{}
+	doneCh  chan struct{}
 	running bool
+	stopped bool
 	mu      sync.Mutex
 }
 
@@ -392,7 +471,7 @@ var DefaultDLQProcessorConfig = DLQProcessorConfig{
 }
 
 // NewDLQProcessor creates a new DLQ processor.
-func NewDLQProcessor(dlq *InMemoryDLQ, router Router, cfg DLQProcessorConfig) *DLQProcessor {
+func NewDLQProcessor(dlq RetryableDLQ, router Router, cfg DLQProcessorConfig) *DLQProcessor {
 	if cfg.BatchSize <= 0 {
 		cfg.BatchSize = DefaultDLQProcessorConfig.BatchSize
 	}
@@ -405,37 +484,81 @@ func NewDLQProcessor(dlq *InMemoryDLQ, router Router, cfg DLQProcessorConfig) *D
 		router: router,
 		cfg:    cfg,
 		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
 	}
 }
 
 // Start begins processing events from the DLQ.
+// A processor that has been stopped can be started again.
 func (p *DLQProcessor) Start(ctx context.Context) {
 	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	if p.running {
-		p.mu.Unlock()
 		return
 	}
+
+	// Re-arm channels after a previous shutdown
+	if p.stopped {
+		p.stopCh = make(chan struct{})
+		p.doneCh = make(chan struct{})
+		p.stopped = false
+	}
 	p.running = true
-	p.mu.Unlock()
 
-	go p.run(ctx)
+	go p.run(ctx, p.stopCh, p.doneCh)
 }
 
 // Stop halts the processor.
+// It blocks until any in-flight batch has finished processing.
+// No new batches start once Stop has been called.
 func (p *DLQProcessor) Stop() {
+	_ = p.StopContext(context.Background())
+}
+
+// StopContext halts the processor, waiting for any in-flight batch
+// to finish or for ctx to be done, whichever comes first.
+// Returns ctx.Err() if ctx is done before shutdown completes; the
+// in-flight batch still runs to completion in the background.
+func (p *DLQProcessor) StopContext(ctx context.Context) error {
 	p.mu.Lock()
-	defer p.mu.Unlock()
+	if !p.stopped {
+		p.stopped = true
+		close(p.stopCh)
+		if !p.running {
+			// Never started - nothing to wait for
+			close(p.doneCh)
+		}
+	}
+	done := p.doneCh
+	p.mu.Unlock()
 
-	if !p.running {
-		return
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+}
 
-	close(p.stopCh)
-	p.running = false
+// Done returns a channel that is closed once the processor has fully
+// shut down, either via Stop or cancellation of the Start context.
+func (p *DLQProcessor) Done() <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.doneCh
 }
 
 // run is the main processing loop.
-func (p *DLQProcessor) run(ctx context.Context) {
+func (p *DLQProcessor) run(ctx context.Context, stopCh, doneCh chan struct{}) {
+	defer func() {
+		p.mu.Lock()
+		p.running = false
+		p.stopped = true
+		close(doneCh)
+		p.mu.Unlock()
+	}()
+
 	ticker := time.NewTicker(p.cfg.PollInterval)
 	defer ticker.Stop()
 
@@ -443,9 +566,16 @@ func (p *DLQProcessor) run(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return
-		case <-p.stopCh:
+		case <-stopCh:
 			return
 		case <-ticker.C:
+			// select picks randomly among ready cases; don't start a
+			// batch if stop was requested at the same time.
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
 			p.processBatch(ctx)
 		}
 	}
@@ -463,11 +593,10 @@ func (p *DLQProcessor) processBatch(ctx context.Context) {
 			p.cfg.OnRetry(failed)
 		}
 
-		// Reconstruct event from failed event data for routing
-		evt := NewAny(failed.EventType, "", failed.TenantID, failed.EventData,
-			WithEventID(failed.EventID))
-
-		_, routeErr := p.router.Route(ctx, evt)
+		evt, routeErr := p.restoreEvent(failed)
+		if routeErr == nil {
+			_, routeErr = p.router.Route(ctx, evt)
+		}
 		if routeErr != nil {
 			if p.cfg.OnFailure != nil {
 				p.cfg.OnFailure(failed, routeErr)
@@ -481,3 +610,34 @@ func (p *DLQProcessor) processBatch(ctx context.Context) {
 		}
 	}
 }
+
+// restoreEvent reconstructs the event of a failed event for routing,
+// using the DLQ's codec when it has one.
+func (p *DLQProcessor) restoreEvent(failed *FailedEvent) (Event, error) {
+	codec := DefaultCodec
+	if c, ok := p.dlq.(interface{ codec() Codec }); ok {
+		codec = c.codec()
+	}
+	return restoreEvent(codec, failed)
+}
+
+// codec returns the codec used to restore retried events.
+func (d *InMemoryDLQ) codec() Codec {
+	return d.cfg.Codec
+}
+
+// restoreEvent reconstructs the event of a failed event for routing.
+// Events encoded with codec are decoded in full; otherwise an event is
+// built from the stored payload.
+func restoreEvent(codec Codec, failed *FailedEvent) (Event, error) {
+	if failed.Encoding == "" || failed.Encoding != codec.ContentType() {
+		return NewAny(failed.EventType, "", failed.TenantID, failed.EventData,
+			WithEventID(failed.EventID)), nil
+	}
+
+	evt, err := codec.Decode(failed.EventData)
+	if err != nil {
+		return nil, fmt.Errorf("decode event %s: %w", failed.EventID, err)
+	}
+	return evt, nil
+}
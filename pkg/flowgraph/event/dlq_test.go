@@ -311,6 +311,169 @@ func TestDLQProcessor(t *testing.T) {
 	}
 }
 
+func TestDLQProcessor_StopWaitsForInFlightBatch(t *testing.T) {
+	dlq := event.NewInMemoryDLQ(event.DLQConfig{
+		RetryDelay: 1 * time.Millisecond,
+	})
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var processed atomic.Int32
+
+	router := event.NewRouter(event.RouterConfig{})
+	router.Register(event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		processed.Add(1)
+		return nil, nil
+	}))
+
+	processor := event.NewDLQProcessor(dlq, router, event.DLQProcessorConfig{
+		BatchSize:    10,
+		PollInterval: 5 * time.Millisecond,
+	})
+
+	for i := 0; i < 2; i++ {
+		evt := event.NewAny("test.event", "test", "t1", nil)
+		dlq.Enqueue(context.Background(), event.NewFailedEvent(evt, errors.New("error"), "handler"))
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	processor.Start(context.Background())
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("batch never started")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		processor.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned while batch was in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return after batch completed")
+	}
+
+	if processed.Load() != 2 {
+		t.Errorf("expected full batch of 2 processed, got %d", processed.Load())
+	}
+
+	select {
+	case <-processor.Done():
+	default:
+		t.Error("expected Done to be closed after Stop")
+	}
+}
+
+func TestDLQProcessor_NoProcessingAfterStop(t *testing.T) {
+	dlq := event.NewInMemoryDLQ(event.DLQConfig{
+		RetryDelay: 1 * time.Millisecond,
+	})
+
+	var processed atomic.Int32
+
+	router := event.NewRouter(event.RouterConfig{})
+	router.Register(event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		processed.Add(1)
+		return nil, nil
+	}))
+
+	processor := event.NewDLQProcessor(dlq, router, event.DLQProcessorConfig{
+		BatchSize:    10,
+		PollInterval: 1 * time.Millisecond,
+	})
+
+	processor.Start(context.Background())
+	processor.Stop()
+
+	evt := event.NewAny("test.event", "test", "t1", nil)
+	dlq.Enqueue(context.Background(), event.NewFailedEvent(evt, errors.New("error"), "handler"))
+
+	time.Sleep(20 * time.Millisecond)
+
+	if processed.Load() != 0 {
+		t.Errorf("expected no events processed after Stop, got %d", processed.Load())
+	}
+	if length, _ := dlq.Len(context.Background()); length != 1 {
+		t.Errorf("expected event to remain in DLQ, got length %d", length)
+	}
+}
+
+func TestDLQProcessor_StopContextTimeout(t *testing.T) {
+	dlq := event.NewInMemoryDLQ(event.DLQConfig{
+		RetryDelay: 1 * time.Millisecond,
+	})
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	router := event.NewRouter(event.RouterConfig{})
+	router.Register(event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		return nil, nil
+	}))
+
+	processor := event.NewDLQProcessor(dlq, router, event.DLQProcessorConfig{
+		PollInterval: 5 * time.Millisecond,
+	})
+
+	evt := event.NewAny("test.event", "test", "t1", nil)
+	dlq.Enqueue(context.Background(), event.NewFailedEvent(evt, errors.New("error"), "handler"))
+	time.Sleep(5 * time.Millisecond)
+
+	processor.Start(context.Background())
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := processor.StopContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+
+	close(release)
+
+	select {
+	case <-processor.Done():
+	case <-time.After(time.Second):
+		t.Fatal("processor did not shut down after batch completed")
+	}
+}
+
+func TestDLQProcessor_StopWithoutStart(t *testing.T) {
+	dlq := event.NewInMemoryDLQ(event.DLQConfig{})
+	processor := event.NewDLQProcessor(dlq, event.NewRouter(event.RouterConfig{}), event.DLQProcessorConfig{})
+
+	processor.Stop()
+
+	select {
+	case <-processor.Done():
+	default:
+		t.Error("expected Done to be closed after Stop on unstarted processor")
+	}
+}
+
 func TestDLQAcknowledge(t *testing.T) {
 	dlq := event.NewInMemoryDLQ(event.DLQConfig{
 		RetryDelay: 1 * time.Millisecond,
@@ -50,6 +50,17 @@
 //	// child.CorrelationID() == parent.ID()
 //	// child.CausationID() == parent.ID()
 //
+// The router attaches a CorrelationScope to the handler context. Values set
+// while handling an event are visible to handlers of its derived events:
+//
+//	event.SetScopedValue(ctx, "tenant_tier", "gold")
+//	// later, in a handler for a derived event:
+//	tier, ok := event.ScopedValue(ctx, "tenant_tier")
+//
+// Scopes are kept per correlation ID by the router, so this holds whether
+// a derived event is routed inline or later through a bus. Call
+// router.ReleaseScope when a chain finishes.
+//
 // # Registry and Schema Validation
 //
 // EventRegistry manages event type definitions with version support:
@@ -67,6 +78,44 @@
 //	    // Handle validation error
 //	}
 //
+// A schema may carry a JSON Schema (draft-07) for the payload. Validate
+// then checks the event's DataBytes and returns a *SchemaValidationError
+// listing every failing field; events of schemas without one are checked
+// only for type and version:
+//
+//	registry.Register(&event.EventSchema{
+//	    Type:    "order.created",
+//	    Version: 1,
+//	    Schema: json.RawMessage(`{
+//	        "type": "object",
+//	        "required": ["order_id", "amount"],
+//	        "properties": {
+//	            "order_id": {"type": "string", "minLength": 1},
+//	            "amount":   {"type": "number", "exclusiveMinimum": 0}
+//	        }
+//	    }`),
+//	})
+//
+//	var schemaErr *event.SchemaValidationError
+//	if errors.As(registry.Validate(evt), &schemaErr) {
+//	    for _, f := range schemaErr.Fields {
+//	        log.Printf("%s: %s", f.Path, f.Message) // e.g. "/amount: is required"
+//	    }
+//	}
+//
+// With RouterConfig.ValidateEvents, invalid events are rejected before any
+// handler runs. Validate uses the schema registered for the event's
+// version, falling back to the latest.
+//
+// RegisterVersion evolves a schema safely: it compares the new JSON Schema
+// with the neighbouring registered versions and returns a
+// *CompatibilityError instead of registering a breaking change:
+//
+//	// Backward: v2 must read v1 payloads (e.g. no new required fields)
+//	// Forward:  v1 must read v2 payloads (e.g. no removed required fields)
+//	// Full:     both
+//	err := registry.RegisterVersion(schemaV2, event.CompatibilityBackward)
+//
 // # Router and Middleware
 //
 // Router dispatches events to registered handlers with middleware support:
@@ -87,6 +136,12 @@
 //	// Dispatch events
 //	derived, err := router.Route(ctx, evt)
 //
+// SchemaValidationMiddleware validates events against a registry with a
+// chosen policy for invalid ones: RejectInvalid, DeadLetterInvalid(dlq), or
+// LogInvalid(logFn), which passes them on:
+//
+//	router.Use(event.SchemaValidationMiddleware(registry, event.DeadLetterInvalid(dlq)))
+//
 // # Bus for Pub/Sub
 //
 // LocalBus provides in-memory pub/sub with fan-out:
@@ -100,12 +155,80 @@
 //	sub := bus.Subscribe([]string{"order.created"}, handler)
 //	defer sub.Unsubscribe()
 //
+//	// Or to a pattern: * matches one dot-delimited segment, a final **
+//	// matches the rest ("order.**" also matches "order.item.added")
+//	sub := bus.Subscribe([]string{"order.*"}, handler)
+//
 //	// Or subscribe to all events
 //	sub := bus.SubscribeAll(auditHandler)
 //
 //	// Publish events
 //	bus.Publish(ctx, evt)
 //
+// Deduplication keys on the event ID by default. Set DedupKeyFunc to
+// ContentDedupKey to drop republished events with the same type and payload
+// but new IDs; the window is per key and expired keys are cleaned up.
+//
+// For subscribers that must not lose events, AckableBus wraps a bus with
+// at-least-once delivery: each delivery must be acknowledged, and events
+// left unacknowledged past the ack timeout are delivered again:
+//
+//	bus := event.NewAckableBus(event.NewBus(event.DefaultBusConfig), event.AckConfig{
+//	    AckTimeout:    10 * time.Second,
+//	    MaxDeliveries: 5, // Then OnExhausted; 0 = redeliver forever
+//	})
+//	bus.SubscribeAck([]string{"order.created"}, func(ctx context.Context, d *event.Delivery) {
+//	    if err := fulfil(ctx, d.Event); err == nil {
+//	        d.Ack()
+//	    }
+//	})
+//
+// A RateMeter tracks how many events of each type were seen recently, using
+// a fixed ring of time buckets per type. Feed it from a bus or router:
+//
+//	meter := event.NewRateMeter(event.DefaultRateMeterConfig) // 1m window, 1s buckets
+//	bus := event.NewBus(event.BusConfig{RateMeter: meter})
+//	router.Use(event.RateMiddleware(meter))
+//
+//	perSecond := meter.Rate("order.created", 30*time.Second)
+//	all := meter.Snapshot(time.Minute) // event type -> events per second
+//
+// # Replay
+//
+// An EventStore records events so they can be routed again, e.g. after a
+// handler bug is fixed. BusConfig.Store appends every published event, and
+// a Replayer routes a stored range back through a Router:
+//
+//	store := event.NewInMemoryEventStore()
+//	bus := event.NewBus(event.BusConfig{Store: store})
+//
+//	replayer := event.NewReplayer(store, router, event.ReplayConfig{
+//	    RemapCorrelation: true, // Replayed chains get "replay-" correlation IDs
+//	})
+//	result, err := replayer.Replay(ctx, event.EventQuery{
+//	    Types: []string{"order.placed"},
+//	    From:  incidentStart,
+//	    To:    incidentEnd,
+//	})
+//
+// # Webhooks and Serialization
+//
+// Marshal and Unmarshal convert any Event to and from a JSON envelope
+// ({"metadata": ..., "payload": ...}). WebhookHandler POSTs that envelope
+// to an external endpoint, with optional HMAC signing and retries:
+//
+//	router.Register(event.NewWebhookHandler(url,
+//	    event.WithWebhookSigningSecret(secret)))
+//
+// For other wire formats such as Protobuf or Avro, implement Codec. The
+// Codec documentation lists the envelope fields every codec must preserve;
+// JSONCodec (the default) wraps Marshal and Unmarshal. Codecs plug in at:
+//
+//   - WithWebhookCodec, for the request body and Content-Type
+//   - BusConfig.Codec, to round-trip published events as a remote bus would
+//   - NewFailedEventWithCodec and DLQConfig.Codec, so retried events keep
+//     their full metadata
+//
 // # Aggregation for Fan-In
 //
 // Aggregators combine multiple related events:
@@ -125,6 +248,37 @@
 //	    aggregatedEvent, _ := agg.Complete(ctx)
 //	}
 //
+// The window starts with the first Add. If Duration passes before
+// MinEvents arrive, the aggregator expires: OnTimeout receives the partial
+// events, IsExpired reports true, and further events are rejected. An
+// AggregatorRegistry removes expired aggregators with completed ones:
+//
+//	agg := event.NewCorrelationAggregator(correlationID, event.WindowConfig{
+//	    Duration:  time.Minute,
+//	    MinEvents: 3,
+//	    OnTimeout: func(partial []event.Event) {
+//	        log.Printf("%s: only %d of 3 events arrived", correlationID, len(partial))
+//	    },
+//	})
+//
+// To wait for exactly N events of a kind regardless of timing, use a
+// PredicateCountAggregator. It ignores events that do not match, and its
+// aggregated event's payload is the matching events as a []Event:
+//
+//	agg := event.NewPredicateCountAggregator(func(evt event.Event) bool {
+//	    return evt.Type() == "shard.indexed"
+//	}, shardCount)
+//
+// A MergeFunc reduces the collected events into a single domain payload,
+// set with WindowConfig.Merge or WithAggregatorMerge. The aggregated event
+// carries the shared correlation ID and is caused by the last input event;
+// a merge error is returned from Complete:
+//
+//	agg := event.NewCountAggregator(correlationID, 3,
+//	    event.WithAggregatorMerge(func(events []event.Event) (any, error) {
+//	        return sumQuotes(events)
+//	    }))
+//
 // # Error Handling
 //
 // DeadLetterQueue stores failed events for retry:
@@ -135,7 +289,65 @@
 //	    DLQ: myDLQ,
 //	})
 //
+// InMemoryDLQ keeps failed events in memory; SQLiteDLQ persists them so
+// they survive a restart. Either can be driven by a DLQProcessor:
+//
+//	dlq, err := event.NewSQLiteDLQ("./dlq.db", event.DefaultDLQConfig)
+//	if err != nil {
+//	    return err
+//	}
+//	defer dlq.Close()
+//	processor := event.NewDLQProcessor(dlq, router, event.DefaultDLQProcessorConfig)
+//	processor.Start(ctx)
+//
 // ParkedLetterQueue stores permanently failed events requiring manual review.
 //
 // PoisonPillDetector identifies events that consistently cause failures.
+//
+// CircuitBreakerMiddleware protects against handlers that fail for every
+// event, e.g. during a downstream outage. Once a handler's failure rate
+// crosses the threshold, events fail fast with ErrCircuitOpen (and are
+// dead-lettered by the router's DLQ) until the cooldown ends and trial
+// events succeed:
+//
+//	router.Use(event.RecoveryMiddleware())
+//	router.Use(event.CircuitBreakerMiddleware(event.CircuitBreakerConfig{
+//	    FailureRate: 0.5, // Of at least MinEvents within Window
+//	    Cooldown:    30 * time.Second,
+//	    OnStateChange: func(handler string, from, to event.BreakerState) {
+//	        alert(handler, to)
+//	    },
+//	}))
+//	router.Use(event.PoisonPillMiddleware(detector))
+//
+// RetryMiddleware retries transient handler errors in line, with backoff
+// and jitter, before they reach the DLQ; the failed event's AttemptCount
+// then starts from the retries already made. Disable the router's own
+// retries so the two do not multiply:
+//
+//	router := event.NewRouter(event.RouterConfig{DLQ: dlq, RetryConfig: fgerrors.NoRetry})
+//	router.Use(event.RetryMiddleware(fgerrors.DefaultRetry))
+//
+// MigrateDLQ moves all queued and parked events from one DLQ to another,
+// e.g. when switching from InMemoryDLQ to a persistent store:
+//
+//	migrated, err := event.MigrateDLQ(ctx, memoryDLQ, persistentDLQ)
+//
+// Implement DLQDrainer and ParkedDLQ on a DLQ so it can be drained
+// completely and keep park reasons across a migration.
+//
+// NewPrometheusCollector exposes DLQ and poison pill statistics to
+// Prometheus, read on each scrape:
+//
+//	prometheus.MustRegister(event.NewPrometheusCollector(dlq, detector))
+//
+// # Testing Handlers
+//
+// The eventtest package provides a Harness that feeds events through a
+// router and captures derived events and errors for assertions:
+//
+//	h := eventtest.NewHandlerHarness(t, myHandler)
+//	h.Feed(evt1, evt2)
+//	h.AssertNoErrors()
+//	h.AssertDerived("order.shipped", "email.queued")
 package event
@@ -0,0 +1,60 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// envelope is the wire form of an event: metadata plus raw payload.
+// It matches the JSON encoding of BaseEvent.
+type envelope struct {
+	Meta    Metadata        `json:"metadata"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// MetadataOf returns the metadata of any Event implementation.
+func MetadataOf(evt Event) Metadata {
+	return Metadata{
+		EventID:       evt.ID(),
+		EventType:     evt.Type(),
+		EventSource:   evt.Source(),
+		CorrelationID: evt.CorrelationID(),
+		CausationID:   evt.CausationID(),
+		Timestamp:     evt.Timestamp(),
+		SchemaVersion: evt.Version(),
+		TenantID:      evt.TenantID(),
+	}
+}
+
+// Marshal encodes an event as a JSON envelope:
+//
+//	{"metadata": {"id": ..., "type": ..., ...}, "payload": ...}
+//
+// The envelope has the same shape as a marshaled BaseEvent, so it works for
+// any Event implementation and round-trips through Unmarshal.
+func Marshal(evt Event) ([]byte, error) {
+	payload := evt.DataBytes()
+	if len(payload) == 0 {
+		payload = []byte("null")
+	}
+
+	data, err := json.Marshal(envelope{
+		Meta:    MetadataOf(evt),
+		Payload: payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal event %s: %w", evt.ID(), err)
+	}
+	return data, nil
+}
+
+// Unmarshal decodes a JSON envelope produced by Marshal.
+// The payload is decoded generically (objects become map[string]any),
+// which handlers created with TypedHandler convert as needed.
+func Unmarshal(data []byte) (*BaseEvent[any], error) {
+	var evt BaseEvent[any]
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return nil, fmt.Errorf("unmarshal event: %w", err)
+	}
+	return &evt, nil
+}
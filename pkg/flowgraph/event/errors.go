@@ -2,6 +2,7 @@ package event
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 )
@@ -18,6 +19,12 @@ type EventError struct {
 
 // Error implements error interface.
 func (e *EventError) Error() string {
+	if e.Event == nil {
+		if e.Err != nil {
+			return fmt.Sprintf("%s: %v", e.Message, e.Err)
+		}
+		return e.Message
+	}
 	if e.Err != nil {
 		return fmt.Sprintf("event %s: %s: %v", e.Event.ID(), e.Message, e.Err)
 	}
@@ -37,6 +44,11 @@ type FailedEvent struct {
 	EventData []byte `json:"event_data"`
 	TenantID  string `json:"tenant_id"`
 
+	// Encoding is the content type of the codec that encoded the whole
+	// event into EventData (see NewFailedEventWithCodec). Empty means
+	// EventData holds only the payload.
+	Encoding string `json:"encoding,omitempty"`
+
 	// Error information
 	ErrorMessage string `json:"error_message"`
 	Handler      string `json:"handler,omitempty"`
@@ -51,9 +63,16 @@ type FailedEvent struct {
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
-// NewFailedEvent creates a FailedEvent from an error.
+// NewFailedEvent creates a FailedEvent from an error. If err wraps an
+// *EventError recording more than one attempt (see RetryMiddleware),
+// AttemptCount starts at the number of retries already made.
 func NewFailedEvent(evt Event, err error, handler string) *FailedEvent {
 	now := time.Now()
+	attempts := 0 // No retry attempts yet
+	var evtErr *EventError
+	if errors.As(err, &evtErr) && evtErr.Attempt > 1 {
+		attempts = evtErr.Attempt - 1
+	}
 	return &FailedEvent{
 		EventID:       evt.ID(),
 		EventType:     evt.Type(),
@@ -61,12 +80,27 @@ func NewFailedEvent(evt Event, err error, handler string) *FailedEvent {
 		TenantID:      evt.TenantID(),
 		ErrorMessage:  err.Error(),
 		Handler:       handler,
-		AttemptCount:  0, // No retry attempts yet
+		AttemptCount:  attempts,
 		FirstFailedAt: now,
 		LastFailedAt:  now,
 	}
 }
 
+// NewFailedEventWithCodec creates a FailedEvent whose EventData holds the
+// whole event encoded with codec, rather than just the payload, so a DLQ
+// configured with the same codec restores its metadata (source,
+// correlation and causation IDs, timestamp, version) when retrying it.
+func NewFailedEventWithCodec(evt Event, err error, handler string, codec Codec) (*FailedEvent, error) {
+	data, encErr := codec.Encode(evt)
+	if encErr != nil {
+		return nil, fmt.Errorf("encode event %s: %w", evt.ID(), encErr)
+	}
+	failed := NewFailedEvent(evt, err, handler)
+	failed.EventData = data
+	failed.Encoding = codec.ContentType()
+	return failed, nil
+}
+
 // ParkedEvent represents an event that has been moved to the parked letter queue.
 type ParkedEvent struct {
 	FailedEvent
@@ -185,6 +185,41 @@ func TestEventJSON(t *testing.T) {
 	}
 }
 
+func TestMarshalEnvelope(t *testing.T) {
+	parent := event.New("order.created", "orders", "tenant-1", map[string]string{"id": "o-1"})
+	evt := event.NewFromParent(parent, "order.shipped", "shipping", map[string]string{"id": "o-1"})
+
+	data, err := event.Marshal(evt)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	decoded, err := event.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if decoded.ID() != evt.ID() {
+		t.Errorf("expected ID %s, got %s", evt.ID(), decoded.ID())
+	}
+	if decoded.CorrelationID() != parent.ID() {
+		t.Errorf("expected correlation %s, got %s", parent.ID(), decoded.CorrelationID())
+	}
+	if decoded.CausationID() != parent.ID() {
+		t.Errorf("expected causation %s, got %s", parent.ID(), decoded.CausationID())
+	}
+	if decoded.TenantID() != "tenant-1" {
+		t.Errorf("expected tenant-1, got %s", decoded.TenantID())
+	}
+	if !decoded.Timestamp().Equal(evt.Timestamp()) {
+		t.Errorf("expected timestamp %v, got %v", evt.Timestamp(), decoded.Timestamp())
+	}
+	payload, ok := decoded.Data().(map[string]any)
+	if !ok || payload["id"] != "o-1" {
+		t.Errorf("expected payload id=o-1, got %v", decoded.Data())
+	}
+}
+
 func TestHandlerFunc(t *testing.T) {
 	called := false
 	var receivedEvt event.Event
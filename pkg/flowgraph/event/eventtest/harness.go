@@ -0,0 +1,198 @@
+// Package eventtest provides utilities for testing event handlers, in the
+// manner of net/http/httptest.
+//
+// Harness feeds events through a router and captures derived events and
+// errors for assertions:
+//
+//	h := eventtest.NewHandlerHarness(t, myHandler)
+//	h.Feed(evt1, evt2)
+//	h.AssertNoErrors()
+//	h.AssertDerived("order.shipped", "email.queued")
+package eventtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+)
+
+// Harness feeds events to an event.Router and records what comes out, so
+// handler tests can assert on derived events and errors without wiring a
+// bus or DLQ.
+//
+// Events are routed synchronously in the order they are fed, making the
+// captured output deterministic.
+//
+// Example:
+//
+//	h := eventtest.NewHandlerHarness(t, orderHandler)
+//	h.Feed(event.NewAny("order.placed", "test", "t1", order))
+//	h.AssertNoErrors()
+//	h.AssertDerived("inventory.reserved", "payment.requested")
+type Harness struct {
+	t      testing.TB
+	router event.Router
+
+	mu      sync.Mutex
+	fed     []event.Event
+	derived []event.Event
+	errs    []error
+}
+
+// NewHarness creates a harness that routes events through router.
+//
+// Only errors returned by Route are captured. event.DefaultRouter reports
+// handler failures through RouterConfig.OnError rather than returning
+// them; use NewHandlerHarness to capture handler errors directly.
+func NewHarness(t testing.TB, router event.Router) *Harness {
+	return &Harness{t: t, router: router}
+}
+
+// NewHandlerHarness creates a harness around a fresh event.DefaultRouter
+// with the given handlers registered. Handlers run once (no retries) and
+// every handler error is captured.
+func NewHandlerHarness(t testing.TB, handlers ...event.Handler) *Harness {
+	h := &Harness{t: t}
+	router := event.NewRouter(event.RouterConfig{
+		RetryConfig: fgerrors.RetryConfig{MaxAttempts: 1},
+		OnError: func(evt event.Event, handler string, err error) {
+			h.recordError(fmt.Errorf("%s on %s: %w", handler, evt.Type(), err))
+		},
+	})
+	for _, handler := range handlers {
+		router.Register(handler)
+	}
+	h.router = router
+	return h
+}
+
+// Feed routes each event in order and records the derived events.
+// Returns the events derived from this call.
+func (h *Harness) Feed(events ...event.Event) []event.Event {
+	h.t.Helper()
+
+	var derived []event.Event
+	for _, evt := range events {
+		out, err := h.router.Route(context.Background(), evt)
+
+		h.mu.Lock()
+		h.fed = append(h.fed, evt)
+		h.derived = append(h.derived, out...)
+		h.mu.Unlock()
+
+		if err != nil {
+			h.recordError(err)
+		}
+		derived = append(derived, out...)
+	}
+	return derived
+}
+
+// Fed returns all events fed to the harness, in order.
+func (h *Harness) Fed() []event.Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]event.Event(nil), h.fed...)
+}
+
+// Derived returns all derived events captured so far, in order.
+func (h *Harness) Derived() []event.Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]event.Event(nil), h.derived...)
+}
+
+// DerivedOfType returns the captured derived events of the given type.
+func (h *Harness) DerivedOfType(eventType string) []event.Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var matched []event.Event
+	for _, evt := range h.derived {
+		if evt.Type() == eventType {
+			matched = append(matched, evt)
+		}
+	}
+	return matched
+}
+
+// Errors returns all errors captured so far, in order.
+func (h *Harness) Errors() []error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]error(nil), h.errs...)
+}
+
+// Reset discards all captured events and errors.
+func (h *Harness) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fed = nil
+	h.derived = nil
+	h.errs = nil
+}
+
+// AssertDerived fails the test unless the captured derived events have
+// exactly the given types, in order. With no types, it asserts that no
+// events were derived.
+func (h *Harness) AssertDerived(types ...string) {
+	h.t.Helper()
+
+	got := eventTypes(h.Derived())
+	if !slices.Equal(got, types) {
+		h.t.Errorf("derived events: got %v, want %v", got, types)
+	}
+}
+
+// AssertDerivedCount fails the test unless exactly n events were derived.
+func (h *Harness) AssertDerivedCount(n int) {
+	h.t.Helper()
+
+	if got := len(h.Derived()); got != n {
+		h.t.Errorf("derived event count: got %d, want %d", got, n)
+	}
+}
+
+// AssertNoErrors fails the test if any errors were captured.
+func (h *Harness) AssertNoErrors() {
+	h.t.Helper()
+
+	if errs := h.Errors(); len(errs) > 0 {
+		h.t.Errorf("expected no errors, got %d: %v", len(errs), errors.Join(errs...))
+	}
+}
+
+// AssertError fails the test unless a captured error matches target
+// according to errors.Is.
+func (h *Harness) AssertError(target error) {
+	h.t.Helper()
+
+	for _, err := range h.Errors() {
+		if errors.Is(err, target) {
+			return
+		}
+	}
+	h.t.Errorf("expected an error matching %v, got %v", target, h.Errors())
+}
+
+// recordError appends err to the captured errors.
+func (h *Harness) recordError(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.errs = append(h.errs, err)
+}
+
+// eventTypes returns the type of each event, in order.
+func eventTypes(events []event.Event) []string {
+	types := make([]string, len(events))
+	for i, evt := range events {
+		types[i] = evt.Type()
+	}
+	return types
+}
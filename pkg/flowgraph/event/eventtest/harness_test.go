@@ -0,0 +1,126 @@
+package eventtest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event/eventtest"
+)
+
+// recordingTB captures assertion failures instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+// typedTestHandler wraps a handler with explicit types
+type typedTestHandler struct {
+	types   []string
+	handler event.Handler
+}
+
+func (h *typedTestHandler) Handle(ctx context.Context, evt event.Event) ([]event.Event, error) {
+	return h.handler.Handle(ctx, evt)
+}
+
+func (h *typedTestHandler) Handles() []string {
+	return h.types
+}
+
+var errOutOfStock = errors.New("out of stock")
+
+// orderHandler derives a reservation and a payment request per order,
+// and fails orders for the item "none".
+func orderHandler() event.Handler {
+	return &typedTestHandler{
+		types: []string{"order.placed"},
+		handler: event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+			if evt.Data() == "none" {
+				return nil, errOutOfStock
+			}
+			return []event.Event{
+				event.NewAnyFromParent(evt, "inventory.reserved", "orders", evt.Data()),
+				event.NewAnyFromParent(evt, "payment.requested", "orders", evt.Data()),
+			}, nil
+		}),
+	}
+}
+
+func TestHandlerHarness_DerivedEvents(t *testing.T) {
+	h := eventtest.NewHandlerHarness(t, orderHandler())
+
+	derived := h.Feed(
+		event.NewAny("order.placed", "test", "t1", "widget"),
+		event.NewAny("order.cancelled", "test", "t1", "widget"),
+		event.NewAny("order.placed", "test", "t1", "gadget"),
+	)
+
+	if len(derived) != 4 {
+		t.Fatalf("expected 4 derived events from Feed, got %d", len(derived))
+	}
+	h.AssertNoErrors()
+	h.AssertDerived("inventory.reserved", "payment.requested", "inventory.reserved", "payment.requested")
+	h.AssertDerivedCount(4)
+
+	if got := len(h.Fed()); got != 3 {
+		t.Errorf("expected 3 fed events, got %d", got)
+	}
+	payments := h.DerivedOfType("payment.requested")
+	if len(payments) != 2 || payments[1].Data() != "gadget" {
+		t.Errorf("unexpected payment events: %v", payments)
+	}
+	if payments[0].CorrelationID() != h.Fed()[0].CorrelationID() {
+		t.Error("derived event should share the parent's correlation ID")
+	}
+}
+
+func TestHandlerHarness_CapturesErrors(t *testing.T) {
+	h := eventtest.NewHandlerHarness(t, orderHandler())
+
+	h.Feed(event.NewAny("order.placed", "test", "t1", "none"))
+
+	h.AssertDerived()
+	h.AssertError(errOutOfStock)
+	if got := len(h.Errors()); got != 1 {
+		t.Errorf("expected 1 error (no retries), got %d", got)
+	}
+
+	h.Reset()
+	if len(h.Errors()) != 0 || len(h.Derived()) != 0 || len(h.Fed()) != 0 {
+		t.Error("Reset should discard captured state")
+	}
+}
+
+func TestHarness_Router(t *testing.T) {
+	router := event.NewRouter(event.RouterConfig{MaxDepth: 1})
+	router.Register(orderHandler())
+	h := eventtest.NewHarness(t, router)
+
+	h.Feed(event.NewAny("order.placed", "test", "t1", "widget"))
+	h.AssertDerived("inventory.reserved", "payment.requested")
+	h.AssertNoErrors()
+}
+
+func TestHarness_AssertionsFail(t *testing.T) {
+	rec := &recordingTB{TB: t}
+	h := eventtest.NewHandlerHarness(rec, orderHandler())
+
+	h.Feed(event.NewAny("order.placed", "test", "t1", "none"))
+	h.AssertDerived("inventory.reserved")
+	h.AssertDerivedCount(1)
+	h.AssertNoErrors()
+	h.AssertError(errors.New("unrelated"))
+
+	if len(rec.failures) != 4 {
+		t.Errorf("expected 4 assertion failures, got %d: %v", len(rec.failures), rec.failures)
+	}
+}
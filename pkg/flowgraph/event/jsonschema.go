@@ -0,0 +1,631 @@
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SchemaValidationError reports an event payload that does not conform to
+// its schema's JSON Schema. It lists every failing field, not just the
+// first.
+type SchemaValidationError struct {
+	// EventType and Version identify the schema the payload was checked
+	// against.
+	EventType string
+	Version   int
+
+	// Fields lists each failure in the order found.
+	Fields []FieldError
+}
+
+// FieldError is one JSON Schema failure.
+type FieldError struct {
+	// Path is the JSON Pointer of the failing value (e.g. "/items/0/sku"),
+	// or "" for the payload itself. For a missing required property it is
+	// the path the property would have.
+	Path string
+
+	// Keyword is the schema keyword that failed (e.g. "required", "type").
+	Keyword string
+
+	// Message describes the failure.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *SchemaValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.String()
+	}
+	return fmt.Sprintf("payload does not match schema %s v%d: %s",
+		e.EventType, e.Version, strings.Join(parts, "; "))
+}
+
+// String formats the failure as "path: message".
+func (f FieldError) String() string {
+	path := f.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return path + ": " + f.Message
+}
+
+// jsonSchema is a parsed JSON Schema (draft-07). It supports the
+// validation keywords of the draft, including local "$ref"s such as
+// "#/definitions/item"; "format" is treated as an annotation and not
+// checked. Patterns use Go's RE2 syntax, which covers the common subset of
+// ECMA-262.
+type jsonSchema struct {
+	root     any
+	patterns map[string]*regexp.Regexp
+}
+
+// compileJSONSchema parses raw and compiles its patterns.
+func compileJSONSchema(raw json.RawMessage) (*jsonSchema, error) {
+	root, err := decodeJSON(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse JSON schema: %w", err)
+	}
+	if !isSchema(root) {
+		return nil, fmt.Errorf("JSON schema must be an object or boolean")
+	}
+
+	s := &jsonSchema{root: root, patterns: make(map[string]*regexp.Regexp)}
+	if err := s.compilePatterns(root); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// decodeJSON decodes data keeping numbers exact.
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return v, nil
+}
+
+// isSchema reports whether v can be a schema.
+func isSchema(v any) bool {
+	switch v.(type) {
+	case map[string]any, bool:
+		return true
+	}
+	return false
+}
+
+// compilePatterns compiles every "pattern" and "patternProperties" key
+// reachable from schema.
+func (s *jsonSchema) compilePatterns(schema any) error {
+	obj, ok := schema.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	var patterns []string
+	if p, ok := obj["pattern"].(string); ok {
+		patterns = append(patterns, p)
+	}
+	if pp, ok := obj["patternProperties"].(map[string]any); ok {
+		for p := range pp {
+			patterns = append(patterns, p)
+		}
+	}
+	for _, p := range patterns {
+		if _, ok := s.patterns[p]; ok {
+			continue
+		}
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		s.patterns[p] = re
+	}
+
+	for _, sub := range subschemas(obj) {
+		if err := s.compilePatterns(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subschemas returns the schemas nested directly in obj.
+func subschemas(obj map[string]any) []any {
+	var subs []any
+	for _, key := range []string{"additionalItems", "additionalProperties", "contains", "propertyNames", "not", "if", "then", "else"} {
+		if sub, ok := obj[key]; ok {
+			subs = append(subs, sub)
+		}
+	}
+	if items, ok := obj["items"].([]any); ok {
+		subs = append(subs, items...)
+	} else if sub, ok := obj["items"]; ok {
+		subs = append(subs, sub)
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if list, ok := obj[key].([]any); ok {
+			subs = append(subs, list...)
+		}
+	}
+	for _, key := range []string{"properties", "patternProperties", "definitions", "dependencies"} {
+		if m, ok := obj[key].(map[string]any); ok {
+			for _, sub := range m {
+				if isSchema(sub) {
+					subs = append(subs, sub)
+				}
+			}
+		}
+	}
+	return subs
+}
+
+// validate checks the JSON document data against the schema.
+func (s *jsonSchema) validate(data []byte) []FieldError {
+	value, err := decodeJSON(data)
+	if err != nil {
+		return []FieldError{{Keyword: "type", Message: fmt.Sprintf("payload is not valid JSON: %v", err)}}
+	}
+	var errs []FieldError
+	s.check(s.root, value, "", &errs)
+	return errs
+}
+
+// valid reports whether value conforms to schema.
+func (s *jsonSchema) valid(schema, value any, path string) bool {
+	var errs []FieldError
+	s.check(schema, value, path, &errs)
+	return len(errs) == 0
+}
+
+// check appends the failures of value against schema to errs.
+func (s *jsonSchema) check(schema, value any, path string, errs *[]FieldError) {
+	fail := func(keyword, format string, args ...any) {
+		*errs = append(*errs, FieldError{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if allowed, ok := schema.(bool); ok {
+		if !allowed {
+			fail("false", "no value is allowed")
+		}
+		return
+	}
+	obj, ok := schema.(map[string]any)
+	if !ok {
+		return
+	}
+
+	// In draft-07, "$ref" replaces all sibling keywords
+	if ref, ok := obj["$ref"].(string); ok {
+		target, err := s.resolve(ref)
+		if err != nil {
+			fail("$ref", "%v", err)
+			return
+		}
+		s.check(target, value, path, errs)
+		return
+	}
+
+	if t, ok := obj["type"]; ok && !matchesType(t, value) {
+		fail("type", "expected %s, got %s", typeList(t), jsonType(value))
+		return
+	}
+
+	if enum, ok := obj["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if jsonEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("enum", "must be one of %s", formatValues(enum))
+		}
+	}
+	if c, ok := obj["const"]; ok && !jsonEqual(c, value) {
+		fail("const", "must equal %s", formatValue(c))
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		s.checkNumber(obj, v, fail)
+	case string:
+		s.checkString(obj, v, fail)
+	case []any:
+		s.checkArray(obj, v, path, errs, fail)
+	case map[string]any:
+		s.checkObject(obj, v, path, errs, fail)
+	}
+
+	if all, ok := obj["allOf"].([]any); ok {
+		for _, sub := range all {
+			s.check(sub, value, path, errs)
+		}
+	}
+	if anyOf, ok := obj["anyOf"].([]any); ok {
+		matched := false
+		for _, sub := range anyOf {
+			if s.valid(sub, value, path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("anyOf", "must match at least one schema in anyOf")
+		}
+	}
+	if oneOf, ok := obj["oneOf"].([]any); ok {
+		matched := 0
+		for _, sub := range oneOf {
+			if s.valid(sub, value, path) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			fail("oneOf", "must match exactly one schema in oneOf, matched %d", matched)
+		}
+	}
+	if not, ok := obj["not"]; ok && s.valid(not, value, path) {
+		fail("not", "must not match the schema in not")
+	}
+	if cond, ok := obj["if"]; ok {
+		if s.valid(cond, value, path) {
+			if then, ok := obj["then"]; ok {
+				s.check(then, value, path, errs)
+			}
+		} else if els, ok := obj["else"]; ok {
+			s.check(els, value, path, errs)
+		}
+	}
+}
+
+// checkNumber applies the numeric keywords.
+func (s *jsonSchema) checkNumber(obj map[string]any, n json.Number, fail func(string, string, ...any)) {
+	v, _ := n.Float64()
+
+	if m, ok := schemaNumber(obj, "multipleOf"); ok && m > 0 {
+		if q := v / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			fail("multipleOf", "must be a multiple of %v", m)
+		}
+	}
+	if m, ok := schemaNumber(obj, "maximum"); ok && v > m {
+		fail("maximum", "must be <= %v", m)
+	}
+	if m, ok := schemaNumber(obj, "exclusiveMaximum"); ok && v >= m {
+		fail("exclusiveMaximum", "must be < %v", m)
+	}
+	if m, ok := schemaNumber(obj, "minimum"); ok && v < m {
+		fail("minimum", "must be >= %v", m)
+	}
+	if m, ok := schemaNumber(obj, "exclusiveMinimum"); ok && v <= m {
+		fail("exclusiveMinimum", "must be > %v", m)
+	}
+}
+
+// checkString applies the string keywords.
+func (s *jsonSchema) checkString(obj map[string]any, v string, fail func(string, string, ...any)) {
+	length := float64(utf8.RuneCountInString(v))
+	if m, ok := schemaNumber(obj, "maxLength"); ok && length > m {
+		fail("maxLength", "must be at most %v characters", m)
+	}
+	if m, ok := schemaNumber(obj, "minLength"); ok && length < m {
+		fail("minLength", "must be at least %v characters", m)
+	}
+	if p, ok := obj["pattern"].(string); ok && !s.patterns[p].MatchString(v) {
+		fail("pattern", "must match pattern %q", p)
+	}
+}
+
+// checkArray applies the array keywords.
+func (s *jsonSchema) checkArray(obj map[string]any, v []any, path string, errs *[]FieldError, fail func(string, string, ...any)) {
+	length := float64(len(v))
+	if m, ok := schemaNumber(obj, "maxItems"); ok && length > m {
+		fail("maxItems", "must have at most %v items", m)
+	}
+	if m, ok := schemaNumber(obj, "minItems"); ok && length < m {
+		fail("minItems", "must have at least %v items", m)
+	}
+	if unique, _ := obj["uniqueItems"].(bool); unique {
+		for i := range v {
+			for j := i + 1; j < len(v); j++ {
+				if jsonEqual(v[i], v[j]) {
+					fail("uniqueItems", "items %d and %d are equal", i, j)
+				}
+			}
+		}
+	}
+
+	switch items := obj["items"].(type) {
+	case []any:
+		for i, item := range v {
+			if i < len(items) {
+				s.check(items[i], item, childPath(path, strconv.Itoa(i)), errs)
+			} else if additional, ok := obj["additionalItems"]; ok {
+				s.check(additional, item, childPath(path, strconv.Itoa(i)), errs)
+			}
+		}
+	case map[string]any, bool:
+		for i, item := range v {
+			s.check(items, item, childPath(path, strconv.Itoa(i)), errs)
+		}
+	}
+
+	if contains, ok := obj["contains"]; ok {
+		found := false
+		for i, item := range v {
+			if s.valid(contains, item, childPath(path, strconv.Itoa(i))) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("contains", "must contain an item matching the schema in contains")
+		}
+	}
+}
+
+// checkObject applies the object keywords.
+func (s *jsonSchema) checkObject(obj map[string]any, v map[string]any, path string, errs *[]FieldError, fail func(string, string, ...any)) {
+	count := float64(len(v))
+	if m, ok := schemaNumber(obj, "maxProperties"); ok && count > m {
+		fail("maxProperties", "must have at most %v properties", m)
+	}
+	if m, ok := schemaNumber(obj, "minProperties"); ok && count < m {
+		fail("minProperties", "must have at least %v properties", m)
+	}
+
+	if required, ok := obj["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, present := v[name]; !present {
+				*errs = append(*errs, FieldError{
+					Path:    childPath(path, name),
+					Keyword: "required",
+					Message: "is required",
+				})
+			}
+		}
+	}
+
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	properties, _ := obj["properties"].(map[string]any)
+	patternProperties, _ := obj["patternProperties"].(map[string]any)
+	additional, hasAdditional := obj["additionalProperties"]
+	propertyNames, hasPropertyNames := obj["propertyNames"]
+
+	for _, name := range names {
+		value := v[name]
+		p := childPath(path, name)
+
+		if hasPropertyNames && !s.valid(propertyNames, name, p) {
+			*errs = append(*errs, FieldError{Path: p, Keyword: "propertyNames", Message: "property name does not match the schema in propertyNames"})
+		}
+
+		matched := false
+		if sub, ok := properties[name]; ok {
+			matched = true
+			s.check(sub, value, p, errs)
+		}
+		for pattern, sub := range patternProperties {
+			if s.patterns[pattern].MatchString(name) {
+				matched = true
+				s.check(sub, value, p, errs)
+			}
+		}
+		if !matched && hasAdditional {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				*errs = append(*errs, FieldError{Path: p, Keyword: "additionalProperties", Message: "is not allowed"})
+			} else {
+				s.check(additional, value, p, errs)
+			}
+		}
+	}
+
+	if deps, ok := obj["dependencies"].(map[string]any); ok {
+		for _, name := range names {
+			dep, ok := deps[name]
+			if !ok {
+				continue
+			}
+			if list, ok := dep.([]any); ok {
+				for _, r := range list {
+					other, _ := r.(string)
+					if _, present := v[other]; !present {
+						*errs = append(*errs, FieldError{
+							Path:    childPath(path, other),
+							Keyword: "dependencies",
+							Message: fmt.Sprintf("is required when %q is present", name),
+						})
+					}
+				}
+				continue
+			}
+			s.check(dep, v, path, errs)
+		}
+	}
+}
+
+// resolve returns the schema a local "$ref" points to.
+func (s *jsonSchema) resolve(ref string) (any, error) {
+	if ref != "#" && !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref %q: only local references are supported", ref)
+	}
+
+	target := s.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch t := target.(type) {
+		case map[string]any:
+			target = t[token]
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil, fmt.Errorf("unresolved $ref %q", ref)
+			}
+			target = t[i]
+		default:
+			target = nil
+		}
+		if target == nil {
+			return nil, fmt.Errorf("unresolved $ref %q", ref)
+		}
+	}
+	return target, nil
+}
+
+// childPath appends a JSON Pointer token to path.
+func childPath(path, token string) string {
+	token = strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+	return path + "/" + token
+}
+
+// schemaNumber returns a numeric keyword value.
+func schemaNumber(obj map[string]any, key string) (float64, bool) {
+	n, ok := obj[key].(json.Number)
+	if !ok {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
+
+// jsonType names the JSON type of a decoded value.
+func jsonType(v any) string {
+	switch n := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if isInteger(n) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// isInteger reports whether n has no fractional part, so 1.0 counts.
+func isInteger(n json.Number) bool {
+	f, ok := new(big.Float).SetString(n.String())
+	return ok && f.IsInt()
+}
+
+// matchesType reports whether value has the type, or one of the types,
+// named by the "type" keyword t.
+func matchesType(t, value any) bool {
+	var names []any
+	switch tt := t.(type) {
+	case string:
+		names = []any{tt}
+	case []any:
+		names = tt
+	default:
+		return true
+	}
+
+	actual := jsonType(value)
+	for _, name := range names {
+		if name == actual || (name == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeList formats the "type" keyword for messages.
+func typeList(t any) string {
+	if list, ok := t.([]any); ok {
+		names := make([]string, len(list))
+		for i, n := range list {
+			names[i] = fmt.Sprint(n)
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+// jsonEqual reports whether two decoded JSON values are equal, comparing
+// numbers by value.
+func jsonEqual(a, b any) bool {
+	switch av := a.(type) {
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		af, aok := new(big.Float).SetString(av.String())
+		bf, bok := new(big.Float).SetString(bv.String())
+		return aok && bok && af.Cmp(bf) == 0
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !jsonEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			other, ok := bv[k]
+			if !ok || !jsonEqual(v, other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+// formatValue renders a decoded JSON value for messages.
+func formatValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// formatValues renders a list of decoded JSON values for messages.
+func formatValues(vs []any) string {
+	parts := make([]string, len(vs))
+	for i, v := range vs {
+		parts[i] = formatValue(v)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
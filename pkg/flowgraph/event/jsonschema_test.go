@@ -0,0 +1,207 @@
+package event_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+)
+
+const orderSchema = `{
+	"type": "object",
+	"required": ["order_id", "amount", "customer"],
+	"properties": {
+		"order_id": {"type": "string", "pattern": "^ord-[0-9]+$"},
+		"amount":   {"type": "number", "exclusiveMinimum": 0},
+		"currency": {"enum": ["USD", "EUR"]},
+		"customer": {
+			"type": "object",
+			"required": ["email"],
+			"properties": {"email": {"type": "string", "minLength": 3}}
+		},
+		"items": {"type": "array", "items": {"$ref": "#/definitions/item"}, "minItems": 1}
+	},
+	"additionalProperties": false,
+	"definitions": {
+		"item": {
+			"type": "object",
+			"required": ["sku", "qty"],
+			"properties": {
+				"sku": {"type": "string"},
+				"qty": {"type": "integer", "minimum": 1}
+			}
+		}
+	}
+}`
+
+func newOrderRegistry(t *testing.T) *event.EventRegistry {
+	t.Helper()
+	registry := event.NewEventRegistry()
+	err := registry.Register(&event.EventSchema{
+		Type:    "order.created",
+		Version: 1,
+		Schema:  json.RawMessage(orderSchema),
+	})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	return registry
+}
+
+func TestEventSchemaJSONSchema(t *testing.T) {
+	registry := newOrderRegistry(t)
+
+	valid := event.NewAny("order.created", "orders", "t1", map[string]any{
+		"order_id": "ord-1",
+		"amount":   12.5,
+		"currency": "USD",
+		"customer": map[string]any{"email": "a@b.c"},
+		"items":    []any{map[string]any{"sku": "x", "qty": 2}},
+	}, event.WithSchemaVersion(1))
+	if err := registry.Validate(valid); err != nil {
+		t.Fatalf("expected valid event to pass: %v", err)
+	}
+
+	invalid := event.NewAny("order.created", "orders", "t1", map[string]any{
+		"order_id": "1",
+		"amount":   0,
+		"currency": "GBP",
+		"customer": map[string]any{},
+		"items":    []any{map[string]any{"sku": "x", "qty": 1.5}},
+		"note":     "rush",
+	}, event.WithSchemaVersion(1))
+
+	err := registry.Validate(invalid)
+	var schemaErr *event.SchemaValidationError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected SchemaValidationError, got %v", err)
+	}
+	if schemaErr.EventType != "order.created" || schemaErr.Version != 1 {
+		t.Errorf("unexpected schema identity: %s v%d", schemaErr.EventType, schemaErr.Version)
+	}
+
+	got := make(map[string]string)
+	for _, f := range schemaErr.Fields {
+		got[f.Path] = f.Keyword
+	}
+	want := map[string]string{
+		"/order_id":       "pattern",
+		"/amount":         "exclusiveMinimum",
+		"/currency":       "enum",
+		"/customer/email": "required",
+		"/items/0/qty":    "type",
+		"/note":           "additionalProperties",
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected %d failing fields, got %v", len(want), schemaErr.Fields)
+	}
+	for path, keyword := range want {
+		if got[path] != keyword {
+			t.Errorf("field %s: expected %q failure, got %q", path, keyword, got[path])
+		}
+	}
+	if !strings.Contains(err.Error(), "/customer/email: is required") {
+		t.Errorf("expected error message to list fields, got %q", err.Error())
+	}
+}
+
+func TestEventSchemaJSONSchemaKeywords(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		payload any
+		valid   bool
+	}{
+		{"type array", `{"type": ["string", "null"]}`, nil, true},
+		{"type mismatch", `{"type": "string"}`, 1, false},
+		{"integer accepts 1.0", `{"type": "integer"}`, json.Number("1.0"), true},
+		{"number accepts integer", `{"type": "number"}`, 3, true},
+		{"const", `{"const": {"a": 1}}`, map[string]any{"a": 1.0}, true},
+		{"const mismatch", `{"const": 1}`, 2, false},
+		{"multipleOf", `{"multipleOf": 0.5}`, 1.5, true},
+		{"multipleOf mismatch", `{"multipleOf": 2}`, 3, false},
+		{"maximum", `{"maximum": 3}`, 4, false},
+		{"maxLength counts runes", `{"maxLength": 2}`, "éé", true},
+		{"uniqueItems", `{"uniqueItems": true}`, []any{1, 1.0}, false},
+		{"tuple items", `{"items": [{"type": "string"}], "additionalItems": false}`, []any{"a", 1}, false},
+		{"contains", `{"contains": {"const": "x"}}`, []any{"a", "x"}, true},
+		{"contains mismatch", `{"contains": {"const": "x"}}`, []any{"a"}, false},
+		{"patternProperties", `{"patternProperties": {"^n_": {"type": "number"}}, "additionalProperties": false}`, map[string]any{"n_a": "x"}, false},
+		{"propertyNames", `{"propertyNames": {"maxLength": 2}}`, map[string]any{"abc": 1}, false},
+		{"dependencies", `{"dependencies": {"card": ["billing"]}}`, map[string]any{"card": 1}, false},
+		{"minProperties", `{"minProperties": 1}`, map[string]any{}, false},
+		{"allOf", `{"allOf": [{"minimum": 1}, {"maximum": 2}]}`, 3, false},
+		{"anyOf", `{"anyOf": [{"type": "string"}, {"type": "number"}]}`, 1, true},
+		{"oneOf matches both", `{"oneOf": [{"minimum": 1}, {"maximum": 5}]}`, 3, false},
+		{"not", `{"not": {"type": "null"}}`, nil, false},
+		{"if then", `{"if": {"properties": {"k": {"const": "a"}}}, "then": {"required": ["v"]}}`, map[string]any{"k": "a"}, false},
+		{"if else", `{"if": {"properties": {"k": {"const": "a"}}}, "then": {"required": ["v"]}, "else": {"required": ["w"]}}`, map[string]any{"k": "b", "w": 1}, true},
+		{"ref root", `{"properties": {"child": {"$ref": "#"}}, "required": ["id"]}`, map[string]any{"id": 1, "child": map[string]any{}}, false},
+		{"false schema", `false`, 1, false},
+		{"format is annotation", `{"format": "email"}`, "not-an-email", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := event.NewEventRegistry()
+			if err := registry.Register(&event.EventSchema{
+				Type:    "test",
+				Version: 1,
+				Schema:  json.RawMessage(tt.schema),
+			}); err != nil {
+				t.Fatalf("register: %v", err)
+			}
+
+			evt := event.NewAny("test", "test", "t1", tt.payload, event.WithSchemaVersion(1))
+			err := registry.Validate(evt)
+			if tt.valid && err != nil {
+				t.Errorf("expected valid, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestEventSchemaWithoutJSONSchema(t *testing.T) {
+	registry := event.NewEventRegistry()
+	registry.Register(&event.EventSchema{Type: "free.form", Version: 1})
+
+	evt := event.NewAny("free.form", "test", "t1", "anything", event.WithSchemaVersion(1))
+	if err := registry.Validate(evt); err != nil {
+		t.Errorf("expected event without JSON schema to pass: %v", err)
+	}
+}
+
+func TestRouterRejectsSchemaInvalidEvents(t *testing.T) {
+	registry := newOrderRegistry(t)
+	router := event.NewRouter(event.RouterConfig{
+		Registry:       registry,
+		ValidateEvents: true,
+	})
+
+	var called bool
+	router.Register(&typedTestHandler{
+		types: []string{"order.created"},
+		handler: event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+			called = true
+			return nil, nil
+		}),
+	})
+
+	evt := event.NewAny("order.created", "orders", "t1", map[string]any{"order_id": "ord-1"},
+		event.WithSchemaVersion(1))
+	_, err := router.Route(context.Background(), evt)
+
+	var schemaErr *event.SchemaValidationError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected SchemaValidationError, got %v", err)
+	}
+	if called {
+		t.Error("expected handler not to run for an invalid event")
+	}
+}
@@ -0,0 +1,97 @@
+package event
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DLQStatsReader reports DLQ statistics. InMemoryDLQ and SQLiteDLQ
+// implement it.
+type DLQStatsReader interface {
+	Stats() DLQStats
+}
+
+// PrometheusCollector is a prometheus.Collector that reports DLQ and
+// poison pill statistics. Create one with NewPrometheusCollector.
+type PrometheusCollector struct {
+	dlq      DLQStatsReader
+	detector *InMemoryPoisonPillDetector
+
+	queueSize       *prometheus.Desc
+	parkedSize      *prometheus.Desc
+	enqueued        *prometheus.Desc
+	retried         *prometheus.Desc
+	parked          *prometheus.Desc
+	recovered       *prometheus.Desc
+	trackedPatterns *prometheus.Desc
+	poisonPills     *prometheus.Desc
+}
+
+// NewPrometheusCollector returns a collector exposing the statistics of
+// dlq and detector. The statistics are read lazily on each Collect, so
+// scraped values are always current. Either dlq or detector may be nil to
+// skip its metrics.
+//
+// Metrics:
+//   - dlq_queue_size, dlq_parked_size (gauges)
+//   - dlq_enqueued_total, dlq_retried_total, dlq_parked_total,
+//     dlq_recovered_total (counters)
+//   - poison_pill_tracked_patterns, poison_pill_count (gauges)
+//
+// Panics if both dlq and detector are nil.
+//
+// Example:
+//
+//	prometheus.MustRegister(event.NewPrometheusCollector(dlq, detector))
+func NewPrometheusCollector(dlq DLQStatsReader, detector *InMemoryPoisonPillDetector) *PrometheusCollector {
+	if dlq == nil && detector == nil {
+		panic("event: prometheus collector needs a DLQ or a poison pill detector")
+	}
+
+	return &PrometheusCollector{
+		dlq:      dlq,
+		detector: detector,
+
+		queueSize:       prometheus.NewDesc("dlq_queue_size", "Events waiting in the dead letter queue", nil, nil),
+		parkedSize:      prometheus.NewDesc("dlq_parked_size", "Events in the parked letter queue", nil, nil),
+		enqueued:        prometheus.NewDesc("dlq_enqueued_total", "Events enqueued to the dead letter queue", nil, nil),
+		retried:         prometheus.NewDesc("dlq_retried_total", "Dead letter retry attempts", nil, nil),
+		parked:          prometheus.NewDesc("dlq_parked_total", "Events moved to the parked letter queue", nil, nil),
+		recovered:       prometheus.NewDesc("dlq_recovered_total", "Events recovered by a retry", nil, nil),
+		trackedPatterns: prometheus.NewDesc("poison_pill_tracked_patterns", "Event patterns with recorded failures", nil, nil),
+		poisonPills:     prometheus.NewDesc("poison_pill_count", "Event patterns over the poison pill threshold", nil, nil),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	if c.dlq != nil {
+		ch <- c.queueSize
+		ch <- c.parkedSize
+		ch <- c.enqueued
+		ch <- c.retried
+		ch <- c.parked
+		ch <- c.recovered
+	}
+	if c.detector != nil {
+		ch <- c.trackedPatterns
+		ch <- c.poisonPills
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.dlq != nil {
+		stats := c.dlq.Stats()
+		ch <- prometheus.MustNewConstMetric(c.queueSize, prometheus.GaugeValue, float64(stats.QueueSize))
+		ch <- prometheus.MustNewConstMetric(c.parkedSize, prometheus.GaugeValue, float64(stats.ParkedSize))
+		ch <- prometheus.MustNewConstMetric(c.enqueued, prometheus.CounterValue, float64(stats.Enqueued))
+		ch <- prometheus.MustNewConstMetric(c.retried, prometheus.CounterValue, float64(stats.Retried))
+		ch <- prometheus.MustNewConstMetric(c.parked, prometheus.CounterValue, float64(stats.Parked))
+		ch <- prometheus.MustNewConstMetric(c.recovered, prometheus.CounterValue, float64(stats.Recovered))
+	}
+	if c.detector != nil {
+		stats := c.detector.Stats()
+		ch <- prometheus.MustNewConstMetric(c.trackedPatterns, prometheus.GaugeValue, float64(stats.TrackedPatterns))
+		ch <- prometheus.MustNewConstMetric(c.poisonPills, prometheus.GaugeValue, float64(stats.PoisonPillCount))
+	}
+}
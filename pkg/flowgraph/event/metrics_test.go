@@ -0,0 +1,103 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+)
+
+// gather collects reg's metrics and returns each value by name.
+func gather(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+
+	values := make(map[string]float64)
+	for _, mf := range families {
+		m := mf.GetMetric()[0]
+		switch {
+		case m.GetGauge() != nil:
+			values[mf.GetName()] = m.GetGauge().GetValue()
+		case m.GetCounter() != nil:
+			values[mf.GetName()] = m.GetCounter().GetValue()
+		}
+	}
+	return values
+}
+
+func TestPrometheusCollector(t *testing.T) {
+	dlq := event.NewInMemoryDLQ(event.DLQConfig{MaxRetries: 3})
+	detector := event.NewInMemoryPoisonPillDetector(event.InMemoryPoisonPillConfig{FailureThreshold: 2})
+	defer detector.Close()
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(event.NewPrometheusCollector(dlq, detector))
+
+	values := gather(t, reg)
+	if values["dlq_queue_size"] != 0 {
+		t.Errorf("expected empty queue, got %v", values["dlq_queue_size"])
+	}
+
+	// Stats are read on each collection; the same event failing twice is
+	// one poison pill
+	poison := event.NewAny("test.poison", "test", "t1", nil)
+	for i := 0; i < 2; i++ {
+		evt := event.NewAny("test.event", "test", "t1", nil)
+		dlq.Enqueue(context.Background(), event.NewFailedEvent(evt, errors.New("error"), "handler"))
+		detector.Record(context.Background(), event.NewFailedEvent(poison, errors.New("error"), "handler"))
+	}
+
+	values = gather(t, reg)
+	want := map[string]float64{
+		"dlq_queue_size":               2,
+		"dlq_parked_size":              0,
+		"dlq_enqueued_total":           2,
+		"dlq_retried_total":            0,
+		"dlq_parked_total":             0,
+		"dlq_recovered_total":          0,
+		"poison_pill_tracked_patterns": 1,
+		"poison_pill_count":            1,
+	}
+	for name, v := range want {
+		got, ok := values[name]
+		if !ok {
+			t.Errorf("metric %s not reported", name)
+		} else if got != v {
+			t.Errorf("%s = %v, want %v", name, got, v)
+		}
+	}
+}
+
+func TestPrometheusCollector_DLQOnly(t *testing.T) {
+	dlq, err := event.NewSQLiteDLQ(":memory:", event.DLQConfig{})
+	if err != nil {
+		t.Fatalf("NewSQLiteDLQ() error: %v", err)
+	}
+	defer dlq.Close()
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(event.NewPrometheusCollector(dlq, nil))
+
+	values := gather(t, reg)
+	if _, ok := values["dlq_queue_size"]; !ok {
+		t.Error("metric dlq_queue_size not reported")
+	}
+	if _, ok := values["poison_pill_count"]; ok {
+		t.Error("poison pill metrics reported without a detector")
+	}
+}
+
+func TestPrometheusCollector_RequiresSource(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic without a DLQ or detector")
+		}
+	}()
+	event.NewPrometheusCollector(nil, nil)
+}
@@ -0,0 +1,181 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrMigrationIncomplete is returned by MigrateDLQ when events could not
+// all be moved, or the destination counts do not match what was migrated.
+var ErrMigrationIncomplete = errors.New("DLQ migration incomplete")
+
+// migrateBatchSize is how many events MigrateDLQ dequeues at a time from
+// a source that does not implement DLQDrainer.
+const migrateBatchSize = 100
+
+// DLQDrainer is implemented by dead letter queues that can remove every
+// queued event at once, regardless of when it is next due for retry.
+// MigrateDLQ uses it to drain the source completely.
+type DLQDrainer interface {
+	// DrainAll removes and returns all queued (not parked) events.
+	DrainAll(ctx context.Context) ([]*FailedEvent, error)
+}
+
+// ParkedDLQ is implemented by dead letter queues that hold parked events
+// alongside queued ones. MigrateDLQ uses it to move parked events with
+// their park reason and timestamps intact.
+type ParkedDLQ interface {
+	// ParkedLen returns the number of parked events.
+	ParkedLen(ctx context.Context) (int, error)
+
+	// DrainParked removes and returns all parked events.
+	DrainParked(ctx context.Context) ([]*ParkedEvent, error)
+
+	// ImportParked stores an already-parked event as-is.
+	ImportParked(ctx context.Context, parked *ParkedEvent) error
+}
+
+// MigrateDLQ moves every event from src to dst, for swapping DLQ backends
+// without losing failed events. Queued events keep their attempt counts and
+// retry schedule; parked events keep their park reason.
+//
+// The source is drained with DrainAll if it implements DLQDrainer, and
+// otherwise by calling Dequeue until it returns nothing. Dequeue only
+// returns events that are due for retry, so in that case events scheduled
+// later stay in src and the migration reports ErrMigrationIncomplete.
+// Parked events are migrated when src implements ParkedDLQ. If dst does
+// not, they are enqueued and then moved to parked with their reason.
+//
+// If an event cannot be stored in dst, it and all events not yet migrated
+// are put back into src before returning the error.
+//
+// After moving the events, MigrateDLQ checks that dst grew by exactly the
+// number migrated, returning ErrMigrationIncomplete if not. Parked events
+// are included in the check only if dst implements ParkedDLQ. src and dst
+// should not be used by other writers during the migration.
+//
+// Example:
+//
+//	migrated, err := event.MigrateDLQ(ctx, memoryDLQ, persistentDLQ)
+//	if err != nil {
+//	    log.Fatalf("migrated %d events before failing: %v", migrated, err)
+//	}
+func MigrateDLQ(ctx context.Context, src, dst DeadLetterQueue) (migrated int, err error) {
+	before, err := dlqSize(ctx, dst)
+	if err != nil {
+		return 0, fmt.Errorf("count destination: %w", err)
+	}
+
+	queued, err := drainQueued(ctx, src)
+	if err != nil {
+		return 0, fmt.Errorf("drain source: %w", err)
+	}
+
+	var parked []*ParkedEvent
+	if p, ok := src.(ParkedDLQ); ok {
+		if parked, err = p.DrainParked(ctx); err != nil {
+			restore(ctx, src, queued, nil)
+			return 0, fmt.Errorf("drain source parked events: %w", err)
+		}
+	}
+
+	for i, failed := range queued {
+		if err := dst.Enqueue(ctx, failed); err != nil {
+			restore(ctx, src, queued[i:], parked)
+			return migrated, fmt.Errorf("enqueue event %s: %w", failed.EventID, err)
+		}
+		migrated++
+	}
+	for i, p := range parked {
+		if err := importParked(ctx, dst, p); err != nil {
+			restore(ctx, src, nil, parked[i:])
+			return migrated, fmt.Errorf("park event %s: %w", p.EventID, err)
+		}
+		migrated++
+	}
+
+	// Without ParkedDLQ, dst can only report its queued events
+	expected := migrated
+	if _, ok := dst.(ParkedDLQ); !ok {
+		expected = len(queued)
+	}
+	after, err := dlqSize(ctx, dst)
+	if err != nil {
+		return migrated, fmt.Errorf("count destination: %w", err)
+	}
+	if after-before != expected {
+		return migrated, fmt.Errorf("%w: expected destination to grow by %d, grew by %d",
+			ErrMigrationIncomplete, expected, after-before)
+	}
+
+	remaining, err := src.Count(ctx)
+	if err != nil {
+		return migrated, fmt.Errorf("count source: %w", err)
+	}
+	if remaining > 0 {
+		return migrated, fmt.Errorf("%w: %d events remain in source",
+			ErrMigrationIncomplete, remaining)
+	}
+	return migrated, nil
+}
+
+// drainQueued removes all queued events from dlq.
+func drainQueued(ctx context.Context, dlq DeadLetterQueue) ([]*FailedEvent, error) {
+	if d, ok := dlq.(DLQDrainer); ok {
+		return d.DrainAll(ctx)
+	}
+
+	var all []*FailedEvent
+	for {
+		batch, err := dlq.Dequeue(ctx, migrateBatchSize)
+		if err != nil {
+			return all, err
+		}
+		if len(batch) == 0 {
+			return all, nil
+		}
+		all = append(all, batch...)
+	}
+}
+
+// importParked stores a parked event in dlq, preserving it as-is when dlq
+// supports parked events directly.
+func importParked(ctx context.Context, dlq DeadLetterQueue, parked *ParkedEvent) error {
+	if p, ok := dlq.(ParkedDLQ); ok {
+		return p.ImportParked(ctx, parked)
+	}
+
+	failed := parked.FailedEvent
+	if err := dlq.Enqueue(ctx, &failed); err != nil {
+		return err
+	}
+	return dlq.MoveToParked(ctx, parked.EventID, parked.ParkReason)
+}
+
+// restore puts events that were not migrated back into dlq. Errors are
+// ignored: the caller is already returning the error that caused this.
+func restore(ctx context.Context, dlq DeadLetterQueue, queued []*FailedEvent, parked []*ParkedEvent) {
+	for _, failed := range queued {
+		_ = dlq.Enqueue(ctx, failed)
+	}
+	for _, p := range parked {
+		_ = importParked(ctx, dlq, p)
+	}
+}
+
+// dlqSize returns the number of queued plus parked events in dlq.
+func dlqSize(ctx context.Context, dlq DeadLetterQueue) (int, error) {
+	n, err := dlq.Count(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if p, ok := dlq.(ParkedDLQ); ok {
+		parked, err := p.ParkedLen(ctx)
+		if err != nil {
+			return 0, err
+		}
+		n += parked
+	}
+	return n, nil
+}
@@ -0,0 +1,167 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+)
+
+// populatedDLQ returns an in-memory DLQ holding three queued events with
+// distinct attempt counts and two parked events with custom reasons.
+func populatedDLQ(t *testing.T) *event.InMemoryDLQ {
+	t.Helper()
+	ctx := context.Background()
+
+	dlq := event.NewInMemoryDLQ(event.DLQConfig{MaxRetries: 5, RetryDelay: time.Hour})
+	for i, id := range []string{"q1", "q2", "q3"} {
+		failed := event.NewFailedEvent(event.NewAny("order.placed", "test", "t1", nil), errors.New("handler failed"), "orders")
+		failed.EventID = id
+		failed.AttemptCount = i
+		if err := dlq.Enqueue(ctx, failed); err != nil {
+			t.Fatalf("enqueue %s: %v", id, err)
+		}
+	}
+	for _, id := range []string{"p1", "p2"} {
+		failed := event.NewFailedEvent(event.NewAny("payment.failed", "test", "t1", nil), errors.New("bad payload"), "payments")
+		failed.EventID = id
+		if err := dlq.Enqueue(ctx, failed); err != nil {
+			t.Fatalf("enqueue %s: %v", id, err)
+		}
+		if err := dlq.MoveToParked(ctx, id, "reason-"+id); err != nil {
+			t.Fatalf("park %s: %v", id, err)
+		}
+	}
+	return dlq
+}
+
+// dequeueOnlyDLQ hides the optional drain interfaces of the wrapped DLQ.
+type dequeueOnlyDLQ struct {
+	event.DeadLetterQueue
+}
+
+func TestMigrateDLQ(t *testing.T) {
+	ctx := context.Background()
+	src := populatedDLQ(t)
+	dst := event.NewInMemoryDLQ(event.DLQConfig{MaxRetries: 5})
+
+	migrated, err := event.MigrateDLQ(ctx, src, dst)
+	if err != nil {
+		t.Fatalf("MigrateDLQ: %v", err)
+	}
+	if migrated != 5 {
+		t.Errorf("expected 5 migrated, got %d", migrated)
+	}
+
+	if n, _ := src.Count(ctx); n != 0 {
+		t.Errorf("expected empty source queue, got %d", n)
+	}
+	if n, _ := src.ParkedLen(ctx); n != 0 {
+		t.Errorf("expected no parked events in source, got %d", n)
+	}
+	if n, _ := dst.Count(ctx); n != 3 {
+		t.Errorf("expected 3 queued in destination, got %d", n)
+	}
+	if n, _ := dst.ParkedLen(ctx); n != 2 {
+		t.Errorf("expected 2 parked in destination, got %d", n)
+	}
+
+	// Attempt counts survive the move
+	queued, _ := dst.DrainAll(ctx)
+	attempts := make(map[string]int)
+	for _, failed := range queued {
+		attempts[failed.EventID] = failed.AttemptCount
+	}
+	for id, want := range map[string]int{"q1": 0, "q2": 1, "q3": 2} {
+		if attempts[id] != want {
+			t.Errorf("event %s: expected attempt count %d, got %d", id, want, attempts[id])
+		}
+	}
+
+	// Park reasons survive the move
+	parked, _ := dst.ListParked(ctx, 0)
+	for _, p := range parked {
+		if p.ParkReason != "reason-"+p.EventID {
+			t.Errorf("event %s: expected park reason %q, got %q", p.EventID, "reason-"+p.EventID, p.ParkReason)
+		}
+		if p.OriginalError != "bad payload" {
+			t.Errorf("event %s: expected original error to be kept, got %q", p.EventID, p.OriginalError)
+		}
+	}
+}
+
+func TestMigrateDLQ_NonParkedDestination(t *testing.T) {
+	ctx := context.Background()
+	src := populatedDLQ(t)
+	inner := event.NewInMemoryDLQ(event.DLQConfig{MaxRetries: 5})
+
+	migrated, err := event.MigrateDLQ(ctx, src, dequeueOnlyDLQ{inner})
+	if err != nil {
+		t.Fatalf("MigrateDLQ: %v", err)
+	}
+	if migrated != 5 {
+		t.Errorf("expected 5 migrated, got %d", migrated)
+	}
+
+	// Parked events are enqueued and then parked with their reason
+	parked, _ := inner.ListParked(ctx, 0)
+	if len(parked) != 2 {
+		t.Fatalf("expected 2 parked in destination, got %d", len(parked))
+	}
+	for _, p := range parked {
+		if p.ParkReason != "reason-"+p.EventID {
+			t.Errorf("event %s: expected park reason %q, got %q", p.EventID, "reason-"+p.EventID, p.ParkReason)
+		}
+	}
+}
+
+func TestMigrateDLQ_DestinationFullRestoresSource(t *testing.T) {
+	ctx := context.Background()
+	src := populatedDLQ(t)
+	dst := event.NewInMemoryDLQ(event.DLQConfig{MaxSize: 1, MaxRetries: 5})
+
+	migrated, err := event.MigrateDLQ(ctx, src, dst)
+	if err == nil {
+		t.Fatal("expected error when destination is full")
+	}
+	if migrated != 1 {
+		t.Errorf("expected 1 migrated before failure, got %d", migrated)
+	}
+
+	// Nothing is lost: every event is in exactly one of the queues
+	srcQueued, _ := src.Count(ctx)
+	srcParked, _ := src.ParkedLen(ctx)
+	dstQueued, _ := dst.Count(ctx)
+	if srcQueued != 2 || srcParked != 2 || dstQueued != 1 {
+		t.Errorf("expected 2+2 in source and 1 in destination, got %d+%d and %d",
+			srcQueued, srcParked, dstQueued)
+	}
+}
+
+func TestMigrateDLQ_DequeueOnlySourceReportsRemaining(t *testing.T) {
+	ctx := context.Background()
+	inner := event.NewInMemoryDLQ(event.DLQConfig{RetryDelay: time.Hour})
+
+	due := event.NewFailedEvent(event.NewAny("a", "test", "t1", nil), errors.New("x"), "h")
+	due.NextRetryAt = time.Now().Add(-time.Minute)
+	later := event.NewFailedEvent(event.NewAny("b", "test", "t1", nil), errors.New("x"), "h")
+	for _, failed := range []*event.FailedEvent{due, later} {
+		if err := inner.Enqueue(ctx, failed); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+
+	dst := event.NewInMemoryDLQ(event.DLQConfig{})
+	migrated, err := event.MigrateDLQ(ctx, dequeueOnlyDLQ{inner}, dst)
+	if !errors.Is(err, event.ErrMigrationIncomplete) {
+		t.Fatalf("expected ErrMigrationIncomplete, got %v", err)
+	}
+	if migrated != 1 {
+		t.Errorf("expected the due event to migrate, got %d", migrated)
+	}
+	if n, _ := inner.Count(ctx); n != 1 {
+		t.Errorf("expected the scheduled event to stay in source, got %d", n)
+	}
+}
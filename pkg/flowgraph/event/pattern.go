@@ -0,0 +1,86 @@
+package event
+
+import (
+	"fmt"
+	"strings"
+)
+
+// typePattern is a compiled event type pattern such as "order.*" or
+// "order.**". Patterns are matched segment by segment on ".":
+//
+//   - "*" as a whole segment matches exactly one segment
+//   - "**" as the last segment matches one or more remaining segments
+//   - "*" inside a segment (e.g. "ship*") matches any run of characters
+//     within that segment
+//   - any other segment matches literally
+type typePattern struct {
+	source   string
+	segments []string
+	rest     bool // Ends in "**"
+}
+
+// isTypePattern reports whether s contains wildcards.
+func isTypePattern(s string) bool {
+	return strings.Contains(s, "*")
+}
+
+// compileTypePattern compiles a pattern. Returns an error if "**" is used
+// anywhere but as the last segment.
+func compileTypePattern(s string) (*typePattern, error) {
+	segments := strings.Split(s, ".")
+	p := &typePattern{source: s}
+	for i, seg := range segments {
+		if seg == "**" {
+			if i != len(segments)-1 {
+				return nil, fmt.Errorf("invalid event type pattern %q: ** must be the last segment", s)
+			}
+			p.rest = true
+			break
+		}
+		p.segments = append(p.segments, seg)
+	}
+	return p, nil
+}
+
+// Match reports whether eventType matches the pattern.
+func (p *typePattern) Match(eventType string) bool {
+	segments := strings.Split(eventType, ".")
+	if p.rest {
+		if len(segments) <= len(p.segments) {
+			return false
+		}
+	} else if len(segments) != len(p.segments) {
+		return false
+	}
+
+	for i, seg := range p.segments {
+		if !matchSegment(seg, segments[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchSegment matches one segment, where "*" matches any run of
+// characters.
+func matchSegment(pattern, segment string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == segment
+	}
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(segment, parts[0]) {
+		return false
+	}
+	segment = segment[len(parts[0]):]
+
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(segment, part)
+		if i < 0 {
+			return false
+		}
+		segment = segment[i+len(part):]
+	}
+	return len(segment) >= len(last) && strings.HasSuffix(segment, last)
+}
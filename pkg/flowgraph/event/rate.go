@@ -0,0 +1,197 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateMeterConfig configures a RateMeter.
+type RateMeterConfig struct {
+	// Window is the longest window rates can be measured over.
+	// Default: 1 minute
+	Window time.Duration
+
+	// Resolution is the width of each time bucket. Smaller buckets make
+	// the window edge more precise at the cost of memory per event type.
+	// Default: 1 second
+	Resolution time.Duration
+}
+
+// DefaultRateMeterConfig provides reasonable defaults.
+var DefaultRateMeterConfig = RateMeterConfig{
+	Window:     time.Minute,
+	Resolution: time.Second,
+}
+
+// RateMeter counts events per type over a sliding time window, for
+// capacity planning and dashboards.
+//
+// Counts are kept in a fixed ring of time buckets per event type, so memory
+// does not grow with event volume; counts older than the window fall out
+// as the ring wraps. Feed a meter from a bus with BusConfig.RateMeter or
+// from a router with RateMiddleware, or call Record directly.
+//
+// A RateMeter is safe for concurrent use.
+type RateMeter struct {
+	resolution time.Duration
+	buckets    int
+
+	mu    sync.Mutex
+	rings map[string]*rateRing
+}
+
+// rateRing holds the bucket counts of one event type. slot i counts the
+// events of bucket epochs[i]; a slot whose epoch is stale counts zero.
+type rateRing struct {
+	counts []int
+	epochs []int64
+}
+
+// NewRateMeter creates a rate meter.
+func NewRateMeter(config RateMeterConfig) *RateMeter {
+	if config.Window <= 0 {
+		config.Window = DefaultRateMeterConfig.Window
+	}
+	if config.Resolution <= 0 {
+		config.Resolution = DefaultRateMeterConfig.Resolution
+	}
+	if config.Resolution > config.Window {
+		config.Resolution = config.Window
+	}
+
+	buckets := int((config.Window + config.Resolution - 1) / config.Resolution)
+	return &RateMeter{
+		resolution: config.Resolution,
+		buckets:    buckets,
+		rings:      make(map[string]*rateRing),
+	}
+}
+
+// Record counts one event of the given type at the current time.
+func (m *RateMeter) Record(eventType string) {
+	epoch := m.epoch(time.Now())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ring, ok := m.rings[eventType]
+	if !ok {
+		ring = &rateRing{
+			counts: make([]int, m.buckets),
+			epochs: make([]int64, m.buckets),
+		}
+		m.rings[eventType] = ring
+	}
+
+	slot := int(epoch % int64(m.buckets))
+	if ring.epochs[slot] != epoch {
+		ring.epochs[slot] = epoch
+		ring.counts[slot] = 0
+	}
+	ring.counts[slot]++
+}
+
+// Count returns the number of events of the given type recorded within
+// the last window. The window is rounded up to whole buckets, including
+// the current one, and capped at the configured Window.
+func (m *RateMeter) Count(eventType string, window time.Duration) int {
+	n, now := m.span(window), m.epoch(time.Now())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ring, ok := m.rings[eventType]
+	if !ok {
+		return 0
+	}
+	return ring.sum(now, n)
+}
+
+// Rate returns the events per second of the given type over the last
+// window, capped at the configured Window. Returns 0 if window <= 0.
+func (m *RateMeter) Rate(eventType string, window time.Duration) float64 {
+	if window <= 0 {
+		return 0
+	}
+	return float64(m.Count(eventType, window)) / m.clamp(window).Seconds()
+}
+
+// Snapshot returns the events per second of every type seen within the
+// last window, keyed by event type. Types with no events in the window
+// are omitted.
+func (m *RateMeter) Snapshot(window time.Duration) map[string]float64 {
+	rates := make(map[string]float64)
+	if window <= 0 {
+		return rates
+	}
+	n, now := m.span(window), m.epoch(time.Now())
+	seconds := m.clamp(window).Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for eventType, ring := range m.rings {
+		if count := ring.sum(now, n); count > 0 {
+			rates[eventType] = float64(count) / seconds
+		}
+	}
+	return rates
+}
+
+// Reset discards all recorded counts.
+func (m *RateMeter) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.rings)
+}
+
+// epoch returns the index of the bucket containing t.
+func (m *RateMeter) epoch(t time.Time) int64 {
+	return t.UnixNano() / int64(m.resolution)
+}
+
+// clamp caps window at the meter's window.
+func (m *RateMeter) clamp(window time.Duration) time.Duration {
+	if limit := time.Duration(m.buckets) * m.resolution; window > limit {
+		return limit
+	}
+	return window
+}
+
+// span returns the number of buckets covering window.
+func (m *RateMeter) span(window time.Duration) int {
+	window = m.clamp(window)
+	n := int((window + m.resolution - 1) / m.resolution)
+	return max(n, 1)
+}
+
+// sum adds the counts of the n buckets ending at epoch now.
+func (r *rateRing) sum(now int64, n int) int {
+	total := 0
+	for epoch := now - int64(n) + 1; epoch <= now; epoch++ {
+		slot := int(epoch % int64(len(r.counts)))
+		if r.epochs[slot] == epoch {
+			total += r.counts[slot]
+		}
+	}
+	return total
+}
+
+// RateMiddleware creates middleware that records every routed event in
+// meter before calling the handler. Router middleware wraps each handler,
+// so an event delivered to several handlers is counted once per handler;
+// use BusConfig.RateMeter to count published events instead.
+//
+// Panics if meter is nil.
+func RateMiddleware(meter *RateMeter) MiddlewareFunc {
+	if meter == nil {
+		panic("event: rate meter cannot be nil")
+	}
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, evt Event) ([]Event, error) {
+			meter.Record(evt.Type())
+			return next.Handle(ctx, evt)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package event_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+)
+
+func TestRateMeter_MeasuresKnownRate(t *testing.T) {
+	meter := event.NewRateMeter(event.RateMeterConfig{Window: time.Second, Resolution: 10 * time.Millisecond})
+
+	// 40 events over roughly 200ms, all well within the 1s window
+	for i := 0; i < 40; i++ {
+		meter.Record("order.created")
+		time.Sleep(5 * time.Millisecond)
+	}
+	meter.Record("order.shipped")
+
+	if got := meter.Count("order.created", time.Second); got != 40 {
+		t.Errorf("expected 40 events in window, got %d", got)
+	}
+	if got := meter.Rate("order.created", time.Second); math.Abs(got-40) > 0.001 {
+		t.Errorf("expected 40/s over 1s, got %v", got)
+	}
+	// Over the last 100ms, about 20 events at 200/s; allow for scheduling delays
+	if got := meter.Rate("order.created", 100*time.Millisecond); got < 50 || got > 250 {
+		t.Errorf("expected roughly 200/s over 100ms, got %v", got)
+	}
+
+	snap := meter.Snapshot(time.Second)
+	if len(snap) != 2 || snap["order.shipped"] != 1 {
+		t.Errorf("unexpected snapshot %v", snap)
+	}
+}
+
+func TestRateMeter_CountsDecayOutOfWindow(t *testing.T) {
+	meter := event.NewRateMeter(event.RateMeterConfig{Window: 100 * time.Millisecond, Resolution: 10 * time.Millisecond})
+
+	for i := 0; i < 5; i++ {
+		meter.Record("tick")
+	}
+	if got := meter.Count("tick", time.Minute); got != 5 {
+		t.Fatalf("expected 5 events, got %d", got)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	meter.Record("tock")
+
+	if got := meter.Count("tick", time.Minute); got != 0 {
+		t.Errorf("expected counts to decay out of the window, got %d", got)
+	}
+	if got := meter.Rate("tick", 100*time.Millisecond); got != 0 {
+		t.Errorf("expected zero rate, got %v", got)
+	}
+	if snap := meter.Snapshot(time.Minute); len(snap) != 1 || snap["tock"] != 10 {
+		t.Errorf("expected only tock at 10/s over the capped window, got %v", snap)
+	}
+}
+
+func TestRateMeter_UnknownTypeAndReset(t *testing.T) {
+	meter := event.NewRateMeter(event.DefaultRateMeterConfig)
+	meter.Record("a")
+
+	if got := meter.Rate("missing", time.Second); got != 0 {
+		t.Errorf("expected 0 for unknown type, got %v", got)
+	}
+	if got := meter.Rate("a", 0); got != 0 {
+		t.Errorf("expected 0 for empty window, got %v", got)
+	}
+
+	meter.Reset()
+	if got := meter.Count("a", time.Minute); got != 0 {
+		t.Errorf("expected 0 after reset, got %d", got)
+	}
+}
+
+func TestBus_FeedsRateMeter(t *testing.T) {
+	meter := event.NewRateMeter(event.DefaultRateMeterConfig)
+	bus := event.NewBus(event.BusConfig{RateMeter: meter})
+	defer bus.Close()
+
+	for i := 0; i < 3; i++ {
+		if err := bus.Publish(context.Background(), sampleEvent()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := meter.Count("order.created", time.Minute); got != 3 {
+		t.Errorf("expected 3 published events, got %d", got)
+	}
+}
+
+func TestRateMiddleware(t *testing.T) {
+	meter := event.NewRateMeter(event.DefaultRateMeterConfig)
+	router := event.NewRouter(event.RouterConfig{})
+	router.Use(event.RateMiddleware(meter))
+	router.Register(event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		return nil, nil
+	}))
+
+	if _, err := router.Route(context.Background(), sampleEvent()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := meter.Count("order.created", time.Minute); got != 1 {
+		t.Errorf("expected 1 routed event, got %d", got)
+	}
+}
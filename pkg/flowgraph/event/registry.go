@@ -1,6 +1,7 @@
 package event
 
 import (
+	"encoding/json"
 	"fmt"
 	"sync"
 )
@@ -26,7 +27,14 @@ type EventSchema struct {
 	// Tags enable semantic search and categorization.
 	Tags []string
 
+	// Schema is an optional JSON Schema (draft-07) for the payload.
+	// Validate checks the event's DataBytes against it and reports
+	// failures as a *SchemaValidationError. Register rejects schemas that
+	// are not valid JSON or contain invalid patterns.
+	Schema json.RawMessage
+
 	// Validator is an optional custom validation function.
+	// It runs after the JSON Schema check.
 	Validator func(Event) error
 
 	// Compatible lists backward-compatible versions.
@@ -38,6 +46,9 @@ type EventSchema struct {
 
 	// DeprecationMessage explains the deprecation.
 	DeprecationMessage string
+
+	// compiled caches Schema once the schema is registered.
+	compiled *jsonSchema
 }
 
 // IsCompatibleWith returns true if this schema can read events at the given version.
@@ -63,6 +74,10 @@ func (s *EventSchema) Validate(evt Event) error {
 		return fmt.Errorf("incompatible version: schema %d, event %d", s.Version, evt.Version())
 	}
 
+	if err := s.validatePayload(evt); err != nil {
+		return err
+	}
+
 	if s.Validator != nil {
 		if err := s.Validator(evt); err != nil {
 			return fmt.Errorf("validation failed: %w", err)
@@ -72,6 +87,30 @@ func (s *EventSchema) Validate(evt Event) error {
 	return nil
 }
 
+// validatePayload checks the event's payload against Schema, if set.
+func (s *EventSchema) validatePayload(evt Event) error {
+	if len(s.Schema) == 0 {
+		return nil
+	}
+
+	compiled := s.compiled
+	if compiled == nil {
+		var err error
+		if compiled, err = compileJSONSchema(s.Schema); err != nil {
+			return fmt.Errorf("schema %s v%d: %w", s.Type, s.Version, err)
+		}
+	}
+
+	if fields := compiled.validate(evt.DataBytes()); len(fields) > 0 {
+		return &SchemaValidationError{
+			EventType: s.Type,
+			Version:   s.Version,
+			Fields:    fields,
+		}
+	}
+	return nil
+}
+
 // EventRegistry manages event type definitions with version support.
 type EventRegistry struct {
 	mu sync.RWMutex
@@ -93,17 +132,40 @@ func NewEventRegistry() *EventRegistry {
 
 // Register adds an event schema to the registry.
 // If a schema with the same type and version exists, it's replaced.
+// Use RegisterVersion to check compatibility with other versions first.
 func (r *EventRegistry) Register(schema *EventSchema) error {
+	if err := prepareSchema(schema); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.registerLocked(schema)
+	return nil
+}
+
+// prepareSchema validates a schema for registration and compiles its JSON
+// Schema.
+func prepareSchema(schema *EventSchema) error {
 	if schema.Type == "" {
 		return fmt.Errorf("event type is required")
 	}
 	if schema.Version <= 0 {
 		return fmt.Errorf("version must be positive")
 	}
+	if len(schema.Schema) > 0 {
+		compiled, err := compileJSONSchema(schema.Schema)
+		if err != nil {
+			return fmt.Errorf("invalid schema for %s v%d: %w", schema.Type, schema.Version, err)
+		}
+		schema.compiled = compiled
+	}
+	return nil
+}
 
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
+// registerLocked stores a prepared schema. r.mu must be held.
+func (r *EventRegistry) registerLocked(schema *EventSchema) {
 	// Initialize version map if needed
 	if r.versions[schema.Type] == nil {
 		r.versions[schema.Type] = make(map[int]*EventSchema)
@@ -113,11 +175,9 @@ func (r *EventRegistry) Register(schema *EventSchema) error {
 	r.versions[schema.Type][schema.Version] = schema
 
 	// Update latest if this is a higher version
-	if current, ok := r.schemas[schema.Type]; !ok || schema.Version > current.Version {
+	if current, ok := r.schemas[schema.Type]; !ok || schema.Version >= current.Version {
 		r.schemas[schema.Type] = schema
 	}
-
-	return nil
 }
 
 // Get returns the latest schema for an event type.
@@ -143,9 +203,15 @@ func (r *EventRegistry) GetVersion(eventType string, version int) (*EventSchema,
 }
 
 // Validate checks if an event conforms to its registered schema.
+// The schema registered for the event's version is used if there is one;
+// otherwise the latest schema is used, which accepts the versions listed
+// in its Compatible field.
 func (r *EventRegistry) Validate(evt Event) error {
 	r.mu.RLock()
-	schema, ok := r.schemas[evt.Type()]
+	schema, ok := r.versions[evt.Type()][evt.Version()]
+	if !ok {
+		schema, ok = r.schemas[evt.Type()]
+	}
 	r.mu.RUnlock()
 
 	if !ok {
@@ -157,10 +223,7 @@ func (r *EventRegistry) Validate(evt Event) error {
 
 // ValidateStrict checks using the exact schema version.
 func (r *EventRegistry) ValidateStrict(evt Event) error {
-	r.mu.RLock()
 	schema, ok := r.GetVersion(evt.Type(), evt.Version())
-	r.mu.RUnlock()
-
 	if !ok {
 		return fmt.Errorf("unknown event type %s at version %d", evt.Type(), evt.Version())
 	}
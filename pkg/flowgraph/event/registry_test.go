@@ -1,6 +1,7 @@
 package event_test
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -266,4 +267,16 @@ func TestRegisterValidation(t *testing.T) {
 	if err == nil {
 		t.Error("expected error for negative version")
 	}
+
+	// Malformed JSON Schema should fail
+	err = registry.Register(&event.EventSchema{Type: "test", Version: 1, Schema: json.RawMessage(`{"type":`)})
+	if err == nil {
+		t.Error("expected error for malformed JSON schema")
+	}
+
+	// Invalid pattern should fail
+	err = registry.Register(&event.EventSchema{Type: "test", Version: 1, Schema: json.RawMessage(`{"pattern": "("}`)})
+	if err == nil {
+		t.Error("expected error for invalid pattern")
+	}
 }
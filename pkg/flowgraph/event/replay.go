@@ -0,0 +1,128 @@
+package event
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ReplayConfig configures a Replayer.
+type ReplayConfig struct {
+	// RemapCorrelation gives each replayed correlation chain a new
+	// correlation ID, so replayed events and the events their handlers
+	// derive can be told apart from the originals. Event and causation IDs
+	// are kept, so parent/child links within a chain still hold.
+	// Default: false (events keep their correlation IDs)
+	RemapCorrelation bool
+
+	// OnError is called when routing a replayed event fails, and replay
+	// continues with the next event. If nil, Replay stops at the first
+	// error and returns it.
+	OnError func(evt Event, err error)
+
+	// OnDerived is called with the events derived from each replayed
+	// event, e.g. to publish them to a bus. If nil, derived events are
+	// discarded.
+	OnDerived func(evt Event, derived []Event)
+}
+
+// ReplayResult summarizes a replay.
+type ReplayResult struct {
+	// Replayed is the number of events routed, including failed ones.
+	Replayed int
+
+	// Failed is the number of events whose routing failed.
+	Failed int
+
+	// Correlations maps each original correlation ID to its replay
+	// correlation ID. Empty unless RemapCorrelation is set.
+	Correlations map[string]string
+}
+
+// Replayer routes stored events through a Router again, e.g. after fixing
+// a handler bug.
+//
+// Example:
+//
+//	replayer := event.NewReplayer(store, router, event.ReplayConfig{RemapCorrelation: true})
+//	result, err := replayer.Replay(ctx, event.EventQuery{
+//	    Types: []string{"order.placed"},
+//	    From:  incidentStart,
+//	    To:    incidentEnd,
+//	})
+type Replayer struct {
+	store  EventStore
+	router Router
+	config ReplayConfig
+}
+
+// NewReplayer creates a replayer reading from store and routing to router.
+func NewReplayer(store EventStore, router Router, config ReplayConfig) *Replayer {
+	return &Replayer{
+		store:  store,
+		router: router,
+		config: config,
+	}
+}
+
+// Replay routes the stored events matching query, one at a time in store
+// order. Replay does not route derived events itself; see
+// ReplayConfig.OnDerived. Cancelling ctx stops the replay between events.
+func (r *Replayer) Replay(ctx context.Context, query EventQuery) (ReplayResult, error) {
+	result := ReplayResult{Correlations: make(map[string]string)}
+
+	events, err := r.store.Range(ctx, query)
+	if err != nil {
+		return result, fmt.Errorf("range events: %w", err)
+	}
+
+	for _, evt := range events {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if r.config.RemapCorrelation {
+			evt = r.remap(evt, result.Correlations)
+		}
+
+		result.Replayed++
+		derived, err := r.router.Route(ctx, evt)
+		if err != nil {
+			result.Failed++
+			if r.config.OnError == nil {
+				return result, fmt.Errorf("replay event %s: %w", evt.ID(), err)
+			}
+			r.config.OnError(evt, err)
+			continue
+		}
+		if len(derived) > 0 && r.config.OnDerived != nil {
+			r.config.OnDerived(evt, derived)
+		}
+	}
+	return result, nil
+}
+
+// remap returns evt with the replay correlation ID of its chain, creating
+// one on first use.
+func (r *Replayer) remap(evt Event, correlations map[string]string) Event {
+	original := evt.CorrelationID()
+	replayID, ok := correlations[original]
+	if !ok {
+		replayID = "replay-" + uuid.New().String()
+		correlations[original] = replayID
+	}
+	return &replayedEvent{Event: evt, correlationID: replayID}
+}
+
+// replayedEvent is a stored event with a replay correlation ID.
+// Events derived from it with NewFromParent inherit that ID.
+type replayedEvent struct {
+	Event
+	correlationID string
+}
+
+// CorrelationID returns the replay correlation ID.
+func (e *replayedEvent) CorrelationID() string {
+	return e.correlationID
+}
@@ -0,0 +1,265 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+)
+
+func TestInMemoryEventStoreRange(t *testing.T) {
+	store := event.NewInMemoryEventStore()
+	ctx := context.Background()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	events := []event.Event{
+		event.NewAny("order.placed", "test", "t1", nil, event.WithCorrelationID("c1"), event.WithTimestamp(base)),
+		event.NewAny("order.paid", "test", "t1", nil, event.WithCorrelationID("c1"), event.WithTimestamp(base.Add(time.Minute))),
+		event.NewAny("order.placed", "test", "t1", nil, event.WithCorrelationID("c2"), event.WithTimestamp(base.Add(2*time.Minute))),
+	}
+	for _, evt := range events {
+		if err := store.Append(ctx, evt); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query event.EventQuery
+		want  []event.Event
+	}{
+		{"all", event.EventQuery{}, events},
+		{"correlation", event.EventQuery{CorrelationID: "c1"}, events[:2]},
+		{"type", event.EventQuery{Types: []string{"order.placed"}}, []event.Event{events[0], events[2]}},
+		{"window", event.EventQuery{From: base.Add(time.Minute), To: base.Add(2 * time.Minute)}, events[1:2]},
+		{"limit", event.EventQuery{Limit: 2}, events[:2]},
+		{"no match", event.EventQuery{CorrelationID: "c3"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Range(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("range: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d events, got %d", len(tt.want), len(got))
+			}
+			for i := range got {
+				if got[i].ID() != tt.want[i].ID() {
+					t.Errorf("event %d: expected %s, got %s", i, tt.want[i].ID(), got[i].ID())
+				}
+			}
+		})
+	}
+}
+
+func TestBusStore(t *testing.T) {
+	store := event.NewInMemoryEventStore()
+	bus := event.NewBus(event.BusConfig{
+		BufferSize:     10,
+		DeduplicateTTL: time.Second,
+		Store:          store,
+	})
+	defer bus.Close()
+
+	evt := event.NewAny("test", "test", "t1", nil, event.WithEventID("dup-id"))
+	if err := bus.Publish(context.Background(), evt); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if err := bus.Publish(context.Background(), evt); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	if store.Len() != 1 {
+		t.Errorf("expected 1 stored event (duplicate skipped), got %d", store.Len())
+	}
+}
+
+func TestBusStoreError(t *testing.T) {
+	errAppend := errors.New("disk full")
+	bus := event.NewBus(event.BusConfig{Store: failingStore{err: errAppend}})
+	defer bus.Close()
+
+	err := bus.Publish(context.Background(), event.NewAny("test", "test", "t1", nil))
+	if !errors.Is(err, errAppend) {
+		t.Errorf("expected append error, got %v", err)
+	}
+}
+
+func TestReplayer(t *testing.T) {
+	store := event.NewInMemoryEventStore()
+	ctx := context.Background()
+
+	placed := event.NewAny("order.placed", "test", "t1", nil, event.WithCorrelationID("c1"))
+	paid := event.NewAnyFromParent(placed, "order.paid", "test", nil)
+	other := event.NewAny("user.created", "test", "t1", nil)
+	for _, evt := range []event.Event{placed, paid, other} {
+		store.Append(ctx, evt)
+	}
+
+	var routed []event.Event
+	router := event.NewRouter(event.DefaultRouterConfig)
+	router.Register(&typedTestHandler{
+		types: []string{"order.placed", "order.paid"},
+		handler: event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+			routed = append(routed, evt)
+			return nil, nil
+		}),
+	})
+
+	replayer := event.NewReplayer(store, router, event.ReplayConfig{})
+	result, err := replayer.Replay(ctx, event.EventQuery{CorrelationID: "c1"})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+
+	if result.Replayed != 2 || result.Failed != 0 {
+		t.Errorf("expected 2 replayed and 0 failed, got %+v", result)
+	}
+	if len(routed) != 2 || routed[0].ID() != placed.ID() || routed[1].ID() != paid.ID() {
+		t.Fatalf("expected placed then paid to be routed, got %v", routed)
+	}
+	if routed[1].CorrelationID() != "c1" {
+		t.Errorf("expected correlation ID to be kept, got %s", routed[1].CorrelationID())
+	}
+}
+
+func TestReplayerRemapCorrelation(t *testing.T) {
+	store := event.NewInMemoryEventStore()
+	ctx := context.Background()
+
+	placed := event.NewAny("order.placed", "test", "t1", nil, event.WithCorrelationID("c1"))
+	paid := event.NewAnyFromParent(placed, "order.paid", "test", nil)
+	unrelated := event.NewAny("order.placed", "test", "t1", nil, event.WithCorrelationID("c2"))
+	for _, evt := range []event.Event{placed, paid, unrelated} {
+		store.Append(ctx, evt)
+	}
+
+	var routed, derived []event.Event
+	router := event.NewRouter(event.DefaultRouterConfig)
+	router.Register(&typedTestHandler{
+		types: []string{"order.placed", "order.paid"},
+		handler: event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+			routed = append(routed, evt)
+			if evt.Type() == "order.paid" {
+				return []event.Event{event.NewAnyFromParent(evt, "order.shipped", "test", nil)}, nil
+			}
+			return nil, nil
+		}),
+	})
+
+	replayer := event.NewReplayer(store, router, event.ReplayConfig{
+		RemapCorrelation: true,
+		OnDerived: func(evt event.Event, out []event.Event) {
+			derived = append(derived, out...)
+		},
+	})
+	result, err := replayer.Replay(ctx, event.EventQuery{})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+
+	if len(result.Correlations) != 2 {
+		t.Fatalf("expected 2 remapped correlations, got %v", result.Correlations)
+	}
+	replayID := result.Correlations["c1"]
+	if !strings.HasPrefix(replayID, "replay-") || replayID == result.Correlations["c2"] {
+		t.Errorf("unexpected replay correlation IDs: %v", result.Correlations)
+	}
+
+	if len(routed) != 3 {
+		t.Fatalf("expected 3 routed events, got %d", len(routed))
+	}
+	if routed[0].CorrelationID() != replayID || routed[1].CorrelationID() != replayID {
+		t.Errorf("expected chain c1 to share replay correlation %s", replayID)
+	}
+	if routed[1].ID() != paid.ID() || routed[1].CausationID() != placed.ID() {
+		t.Errorf("expected event and causation IDs to be kept")
+	}
+
+	if len(derived) != 1 {
+		t.Fatalf("expected 1 derived event, got %d", len(derived))
+	}
+	if derived[0].CorrelationID() != replayID || derived[0].CausationID() != paid.ID() {
+		t.Errorf("expected derived event to inherit replay correlation and causation, got %s/%s",
+			derived[0].CorrelationID(), derived[0].CausationID())
+	}
+}
+
+func TestReplayerErrors(t *testing.T) {
+	store := event.NewInMemoryEventStore()
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		store.Append(ctx, event.NewAny("test", "test", "t1", nil))
+	}
+
+	errRoute := errors.New("still broken")
+	router := failingRouter{err: errRoute}
+
+	t.Run("stops at first error", func(t *testing.T) {
+		replayer := event.NewReplayer(store, router, event.ReplayConfig{})
+		result, err := replayer.Replay(ctx, event.EventQuery{})
+		if !errors.Is(err, errRoute) {
+			t.Errorf("expected route error, got %v", err)
+		}
+		if result.Replayed != 1 || result.Failed != 1 {
+			t.Errorf("expected 1 replayed and 1 failed, got %+v", result)
+		}
+	})
+
+	t.Run("continues with OnError", func(t *testing.T) {
+		var failed int
+		replayer := event.NewReplayer(store, router, event.ReplayConfig{
+			OnError: func(evt event.Event, err error) { failed++ },
+		})
+		result, err := replayer.Replay(ctx, event.EventQuery{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Replayed != 3 || result.Failed != 3 || failed != 3 {
+			t.Errorf("expected 3 replayed and failed, got %+v (OnError %d)", result, failed)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		replayer := event.NewReplayer(store, router, event.ReplayConfig{})
+		result, err := replayer.Replay(cancelled, event.EventQuery{})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		if result.Replayed != 0 {
+			t.Errorf("expected nothing replayed, got %d", result.Replayed)
+		}
+	})
+}
+
+// failingRouter is a Router whose Route always fails.
+type failingRouter struct {
+	err error
+}
+
+func (r failingRouter) Route(ctx context.Context, evt event.Event) ([]event.Event, error) {
+	return nil, r.err
+}
+
+func (r failingRouter) Register(handler event.Handler, opts ...event.HandlerOption) {}
+
+func (r failingRouter) Use(middleware event.MiddlewareFunc) {}
+
+// failingStore is an EventStore whose Append always fails.
+type failingStore struct {
+	err error
+}
+
+func (s failingStore) Append(ctx context.Context, evt event.Event) error {
+	return s.err
+}
+
+func (s failingStore) Range(ctx context.Context, query event.EventQuery) ([]event.Event, error) {
+	return nil, s.err
+}
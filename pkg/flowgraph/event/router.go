@@ -48,12 +48,19 @@ type RouterConfig struct {
 
 	// OnSuccess is called after successful processing (for metrics).
 	OnSuccess func(evt Event, handler string, duration time.Duration)
+
+	// ScopeTTL drops a correlation chain's CorrelationScope after it has
+	// not been routed for this long. Release scopes of finished chains
+	// earlier with ReleaseScope.
+	// Default: 10 minutes
+	ScopeTTL time.Duration
 }
 
 // DefaultRouterConfig provides reasonable defaults.
 var DefaultRouterConfig = RouterConfig{
 	MaxDepth:    10,
 	RetryConfig: fgerrors.DefaultRetry,
+	ScopeTTL:    10 * time.Minute,
 }
 
 // handlerEntry stores a handler with its configuration.
@@ -71,6 +78,8 @@ type DefaultRouter struct {
 	handlers   map[string][]handlerEntry // event type -> handlers
 	wildcards  []handlerEntry            // handlers for all events
 	middleware []MiddlewareFunc
+
+	scopes *scopeRegistry
 }
 
 // NewRouter creates a new event router.
@@ -81,10 +90,14 @@ func NewRouter(config RouterConfig) *DefaultRouter {
 	if config.RetryConfig.MaxAttempts <= 0 {
 		config.RetryConfig = DefaultRouterConfig.RetryConfig
 	}
+	if config.ScopeTTL <= 0 {
+		config.ScopeTTL = DefaultRouterConfig.ScopeTTL
+	}
 
 	return &DefaultRouter{
 		config:   config,
 		handlers: make(map[string][]handlerEntry),
+		scopes:   newScopeRegistry(config.ScopeTTL),
 	}
 }
 
@@ -141,6 +154,14 @@ func (r *DefaultRouter) Use(middleware MiddlewareFunc) {
 	r.middleware = append(r.middleware, middleware)
 }
 
+// ReleaseScope drops the CorrelationScope of a correlation chain.
+// Call it when the chain is finished; otherwise the scope is dropped after
+// RouterConfig.ScopeTTL without routing. Events of the chain routed later
+// start with an empty scope.
+func (r *DefaultRouter) ReleaseScope(correlationID string) {
+	r.scopes.release(correlationID)
+}
+
 // Route dispatches an event to all matching handlers.
 func (r *DefaultRouter) Route(ctx context.Context, evt Event) ([]Event, error) {
 	// Check depth to prevent infinite recursion
@@ -178,6 +199,9 @@ func (r *DefaultRouter) Route(ctx context.Context, evt Event) ([]Event, error) {
 	// Increment depth for derived events
 	ctx = withEventDepth(ctx, depth+1)
 
+	// Share correlation-scoped values with handlers of derived events
+	ctx = r.scopes.withCorrelationScope(ctx, evt)
+
 	// Collect all derived events
 	var allDerived []Event
 	var mu sync.Mutex
@@ -299,6 +323,44 @@ func RecoveryMiddleware() MiddlewareFunc {
 	}
 }
 
+// RetryMiddleware creates middleware that retries a failing handler in
+// line, with the backoff and jitter of cfg, before its error reaches the
+// router's DLQ. Only retryable errors are retried: by default those
+// fgerrors.Categorize reports as transient, or those cfg.RetryableFunc
+// accepts. Other errors are returned after the first attempt.
+//
+// Retries stop when ctx is done, so a WithHandlerTimeout bounds all
+// attempts together. When retries do not help, the error is an *EventError
+// whose Attempt is the number of attempts made; NewFailedEvent starts the
+// failed event's AttemptCount from it.
+//
+// The router also retries transient errors with RouterConfig.RetryConfig,
+// so set that (or WithHandlerRetry) to fgerrors.NoRetry to retry only here.
+// A zero MaxAttempts uses fgerrors.DefaultRetry.
+func RetryMiddleware(cfg fgerrors.RetryConfig) MiddlewareFunc {
+	if cfg.MaxAttempts <= 0 {
+		cfg = fgerrors.DefaultRetry
+	}
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, evt Event) ([]Event, error) {
+			result := fgerrors.WithRetryContext(ctx, cfg, func(ctx context.Context) ([]Event, error) {
+				return next.Handle(ctx, evt)
+			})
+			if result.Err != nil {
+				return nil, &EventError{
+					Event:     evt,
+					Handler:   handlerName(next),
+					Message:   fmt.Sprintf("handler failed after %d attempts", result.Attempts),
+					Err:       result.Err,
+					Attempt:   result.Attempts,
+					Timestamp: time.Now(),
+				}
+			}
+			return result.Value, nil
+		})
+	}
+}
+
 // MetricsMiddleware records handler metrics.
 func MetricsMiddleware(
 	onStart func(eventType string),
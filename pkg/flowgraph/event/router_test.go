@@ -3,10 +3,12 @@ package event_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
 	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
 )
 
@@ -256,6 +258,104 @@ func TestRecoveryMiddleware(t *testing.T) {
 	}
 }
 
+var fastRetry = fgerrors.RetryConfig{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+	BackoffFactor:  2,
+	Jitter:         0.1,
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	var calls atomic.Int32
+	handler := event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		if calls.Add(1) < 3 {
+			return nil, fgerrors.Transient(errors.New("busy"), "handle")
+		}
+		return []event.Event{event.NewAny("derived", "test", "t1", nil)}, nil
+	})
+
+	derived, err := event.RetryMiddleware(fastRetry)(handler).Handle(
+		context.Background(), event.NewAny("test", "test", "t1", nil))
+	if err != nil {
+		t.Fatalf("expected success on the third attempt, got %v", err)
+	}
+	if len(derived) != 1 || calls.Load() != 3 {
+		t.Errorf("expected 1 derived event after 3 calls, got %d after %d", len(derived), calls.Load())
+	}
+}
+
+func TestRetryMiddleware_Permanent(t *testing.T) {
+	var calls atomic.Int32
+	handler := event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		calls.Add(1)
+		return nil, errors.New("bad payload") // Uncategorized errors are permanent
+	})
+
+	_, err := event.RetryMiddleware(fastRetry)(handler).Handle(
+		context.Background(), event.NewAny("test", "test", "t1", nil))
+
+	var evtErr *event.EventError
+	if !errors.As(err, &evtErr) || evtErr.Attempt != 1 {
+		t.Fatalf("expected EventError with 1 attempt, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected permanent error not to be retried, got %d calls", calls.Load())
+	}
+}
+
+func TestRetryMiddleware_DLQAttemptCount(t *testing.T) {
+	var failed []*event.FailedEvent
+	dlq := event.NewInMemoryDLQ(event.DLQConfig{
+		MaxRetries: 5,
+		OnEnqueue:  func(f *event.FailedEvent) { failed = append(failed, f) },
+	})
+	router := event.NewRouter(event.RouterConfig{
+		DLQ:         dlq,
+		RetryConfig: fgerrors.NoRetry,
+	})
+	router.Use(event.RetryMiddleware(fastRetry))
+
+	var calls atomic.Int32
+	router.Register(event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		calls.Add(1)
+		return nil, fgerrors.Transient(errors.New("busy"), "handle")
+	}))
+
+	router.Route(context.Background(), event.NewAny("test", "test", "t1", nil))
+
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+	if len(failed) != 1 {
+		t.Fatalf("expected 1 dead-lettered event, got %d", len(failed))
+	}
+	if failed[0].AttemptCount != 2 {
+		t.Errorf("expected AttemptCount 2 after 2 in-line retries, got %d", failed[0].AttemptCount)
+	}
+}
+
+func TestRetryMiddleware_ContextDone(t *testing.T) {
+	var calls atomic.Int32
+	handler := event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		calls.Add(1)
+		return nil, fgerrors.Transient(errors.New("busy"), "handle")
+	})
+	slow := fgerrors.RetryConfig{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: time.Second}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := event.RetryMiddleware(slow)(handler).Handle(ctx, event.NewAny("test", "test", "t1", nil))
+	if err == nil || time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("expected retries to stop at the deadline, got %v after %v", err, time.Since(start))
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 call before the deadline, got %d", calls.Load())
+	}
+}
+
 func TestLoggingMiddleware(t *testing.T) {
 	var loggedType string
 	var loggedDuration time.Duration
@@ -296,3 +396,187 @@ func (h *typedTestHandler) Handle(ctx context.Context, evt event.Event) ([]event
 func (h *typedTestHandler) Handles() []string {
 	return h.types
 }
+
+// TestRouter_CorrelationScope tests that scoped values flow to derived events.
+func TestRouter_CorrelationScope(t *testing.T) {
+	router := event.NewRouter(event.RouterConfig{MaxDepth: 5})
+
+	var got atomic.Value
+	router.Register(&typedTestHandler{
+		types: []string{"child.event"},
+		handler: event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+			if v, ok := event.ScopedValue(ctx, "tenant"); ok {
+				got.Store(v)
+			}
+			return nil, nil
+		}),
+	})
+	router.Register(&typedTestHandler{
+		types: []string{"parent.event"},
+		handler: event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+			if !event.SetScopedValue(ctx, "tenant", "acme") {
+				t.Error("expected routing context to carry a correlation scope")
+			}
+			child := event.NewAnyFromParent(evt, "child.event", "test", nil)
+			return router.Route(ctx, child)
+		}),
+	})
+
+	parent := event.NewAny("parent.event", "test", "t1", nil)
+	if _, err := router.Route(context.Background(), parent); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Load() != "acme" {
+		t.Errorf("expected child handler to see tenant=acme, got %v", got.Load())
+	}
+}
+
+// TestRouter_CorrelationScope_Isolated tests that unrelated events get a fresh scope.
+func TestRouter_CorrelationScope_Isolated(t *testing.T) {
+	router := event.NewRouter(event.RouterConfig{MaxDepth: 5})
+
+	var leaked atomic.Bool
+	router.Register(&typedTestHandler{
+		types: []string{"other.event"},
+		handler: event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+			if _, ok := event.ScopedValue(ctx, "tenant"); ok {
+				leaked.Store(true)
+			}
+			return nil, nil
+		}),
+	})
+	router.Register(&typedTestHandler{
+		types: []string{"parent.event"},
+		handler: event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+			event.SetScopedValue(ctx, "tenant", "acme")
+			// Unrelated event: new correlation chain
+			other := event.NewAny("other.event", "test", "t1", nil)
+			return router.Route(ctx, other)
+		}),
+	})
+
+	parent := event.NewAny("parent.event", "test", "t1", nil)
+	if _, err := router.Route(context.Background(), parent); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if leaked.Load() {
+		t.Error("expected unrelated event not to see parent's scoped values")
+	}
+}
+
+// TestRouter_CorrelationScope_ThroughBus tests that scoped values reach
+// derived events that are published to a bus and routed later.
+func TestRouter_CorrelationScope_ThroughBus(t *testing.T) {
+	router := event.NewRouter(event.RouterConfig{MaxDepth: 5})
+	bus := event.NewBus(event.BusConfig{})
+	defer bus.Close()
+
+	got := make(chan any, 1)
+	router.Register(&typedTestHandler{
+		types: []string{"child.event"},
+		handler: event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+			v, _ := event.ScopedValue(ctx, "tenant")
+			got <- v
+			return nil, nil
+		}),
+	})
+	router.Register(&typedTestHandler{
+		types: []string{"parent.event"},
+		handler: event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+			event.SetScopedValue(ctx, "tenant", "acme")
+			return []event.Event{event.NewAnyFromParent(evt, "child.event", "test", nil)}, nil
+		}),
+	})
+	bus.Subscribe([]string{"child.event"}, event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		return router.Route(ctx, evt)
+	}))
+
+	parent := event.NewAny("parent.event", "test", "t1", nil)
+	derived, err := router.Route(context.Background(), parent)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, evt := range derived {
+		if err := bus.Publish(context.Background(), evt); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	select {
+	case v := <-got:
+		if v != "acme" {
+			t.Errorf("expected child handler to see tenant=acme, got %v", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("child event was not routed")
+	}
+}
+
+// TestRouter_ReleaseScope tests that released and expired scopes start empty.
+func TestRouter_ReleaseScope(t *testing.T) {
+	router := event.NewRouter(event.RouterConfig{MaxDepth: 5, ScopeTTL: 20 * time.Millisecond})
+
+	var seen atomic.Value
+	router.Register(&typedTestHandler{
+		types: []string{"child.event"},
+		handler: event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+			v, _ := event.ScopedValue(ctx, "tenant")
+			seen.Store(fmt.Sprint(v))
+			return nil, nil
+		}),
+	})
+	router.Register(&typedTestHandler{
+		types: []string{"parent.event"},
+		handler: event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+			event.SetScopedValue(ctx, "tenant", "acme")
+			return nil, nil
+		}),
+	})
+
+	tests := []struct {
+		name  string
+		after func(parent event.Event)
+		want  string
+	}{
+		{name: "kept", after: func(event.Event) {}, want: "acme"},
+		{name: "released", after: func(p event.Event) { router.ReleaseScope(p.CorrelationID()) }, want: "<nil>"},
+		{name: "expired", after: func(event.Event) { time.Sleep(40 * time.Millisecond) }, want: "<nil>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := event.NewAny("parent.event", "test", "t1", nil)
+			if _, err := router.Route(context.Background(), parent); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.after(parent)
+
+			child := event.NewAnyFromParent(parent, "child.event", "test", nil)
+			if _, err := router.Route(context.Background(), child); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if seen.Load() != tt.want {
+				t.Errorf("expected tenant=%s, got %v", tt.want, seen.Load())
+			}
+		})
+	}
+}
+
+// TestScopedValue_NoScope tests scoped value helpers without a scope.
+func TestScopedValue_NoScope(t *testing.T) {
+	ctx := context.Background()
+	if event.SetScopedValue(ctx, "k", "v") {
+		t.Error("expected SetScopedValue to report no scope")
+	}
+	if _, ok := event.ScopedValue(ctx, "k"); ok {
+		t.Error("expected no value without scope")
+	}
+
+	scope := event.NewCorrelationScope("corr-1")
+	ctx = event.ContextWithScope(ctx, scope)
+	event.SetScopedValue(ctx, "k", "v")
+	if v, ok := scope.Get("k"); !ok || v != "v" {
+		t.Errorf("expected v, got %v", v)
+	}
+}
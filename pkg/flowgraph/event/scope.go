@@ -0,0 +1,164 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// correlationScopeKey is the context key for the active CorrelationScope.
+const correlationScopeKey contextKey = "correlation_scope"
+
+// CorrelationScope holds values shared by every event in a correlation chain.
+//
+// The router attaches a scope to the context before dispatching an event.
+// Values set while handling an event remain visible while handling any
+// events derived from it, as long as they share the same correlation ID.
+// This carries cross-cutting data (tenant settings, trace baggage) without
+// adding it to event payloads.
+//
+// A DefaultRouter keeps the scope of each correlation ID until it is
+// released with ReleaseScope or goes unused for RouterConfig.ScopeTTL, so
+// derived events routed later (from a bus subscriber, a Replayer or a DLQ
+// processor) see the same scope as events routed inline by a handler.
+//
+// CorrelationScope is safe for concurrent use.
+type CorrelationScope struct {
+	correlationID string
+
+	mu     sync.RWMutex
+	values map[any]any
+}
+
+// NewCorrelationScope creates an empty scope for the given correlation ID.
+func NewCorrelationScope(correlationID string) *CorrelationScope {
+	return &CorrelationScope{
+		correlationID: correlationID,
+		values:        make(map[any]any),
+	}
+}
+
+// CorrelationID returns the correlation ID this scope belongs to.
+func (s *CorrelationScope) CorrelationID() string {
+	return s.correlationID
+}
+
+// Set stores a value in the scope, replacing any existing value for key.
+func (s *CorrelationScope) Set(key, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+// Get returns the value stored for key.
+func (s *CorrelationScope) Get(key any) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// ScopeFromContext returns the correlation scope attached to ctx, or nil.
+func ScopeFromContext(ctx context.Context) *CorrelationScope {
+	if s, ok := ctx.Value(correlationScopeKey).(*CorrelationScope); ok {
+		return s
+	}
+	return nil
+}
+
+// ContextWithScope attaches a correlation scope to ctx.
+// Use this to carry a scope across boundaries the router does not see,
+// such as events handed to a bus and routed from another goroutine.
+func ContextWithScope(ctx context.Context, scope *CorrelationScope) context.Context {
+	return context.WithValue(ctx, correlationScopeKey, scope)
+}
+
+// SetScopedValue stores a value in the correlation scope of ctx.
+// Returns false if ctx carries no scope.
+func SetScopedValue(ctx context.Context, key, value any) bool {
+	s := ScopeFromContext(ctx)
+	if s == nil {
+		return false
+	}
+	s.Set(key, value)
+	return true
+}
+
+// ScopedValue returns a value from the correlation scope of ctx.
+func ScopedValue(ctx context.Context, key any) (any, bool) {
+	s := ScopeFromContext(ctx)
+	if s == nil {
+		return nil, false
+	}
+	return s.Get(key)
+}
+
+// scopeEntry is a registered scope and when it was last used.
+type scopeEntry struct {
+	scope    *CorrelationScope
+	lastUsed time.Time
+}
+
+// scopeRegistry holds the correlation scopes of a router by correlation ID.
+type scopeRegistry struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	scopes    map[string]*scopeEntry
+	lastSweep time.Time
+}
+
+func newScopeRegistry(ttl time.Duration) *scopeRegistry {
+	return &scopeRegistry{
+		ttl:       ttl,
+		scopes:    make(map[string]*scopeEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// get returns the scope for correlationID, creating it if needed.
+func (r *scopeRegistry) get(correlationID string) *CorrelationScope {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.lastSweep) >= r.ttl {
+		r.sweepLocked(now)
+	}
+
+	entry, ok := r.scopes[correlationID]
+	if !ok || now.Sub(entry.lastUsed) >= r.ttl {
+		entry = &scopeEntry{scope: NewCorrelationScope(correlationID)}
+		r.scopes[correlationID] = entry
+	}
+	entry.lastUsed = now
+	return entry.scope
+}
+
+// release drops the scope for correlationID.
+func (r *scopeRegistry) release(correlationID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.scopes, correlationID)
+}
+
+// sweepLocked drops scopes unused for the TTL (must hold lock).
+func (r *scopeRegistry) sweepLocked(now time.Time) {
+	for id, entry := range r.scopes {
+		if now.Sub(entry.lastUsed) >= r.ttl {
+			delete(r.scopes, id)
+		}
+	}
+	r.lastSweep = now
+}
+
+// withCorrelationScope ensures ctx carries a scope for evt's correlation ID.
+// A scope already on ctx with a matching ID is kept; otherwise the
+// registered scope for the ID is attached, so derived events see the
+// values set by their ancestors however they are routed.
+func (r *scopeRegistry) withCorrelationScope(ctx context.Context, evt Event) context.Context {
+	if s := ScopeFromContext(ctx); s != nil && s.correlationID == evt.CorrelationID() {
+		return ctx
+	}
+	return ContextWithScope(ctx, r.get(evt.CorrelationID()))
+}
@@ -0,0 +1,770 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver
+)
+
+// ErrDLQClosed is returned when using a SQLiteDLQ after Close.
+var ErrDLQClosed = errors.New("DLQ is closed")
+
+// SQLiteDLQ is a DeadLetterQueue persisted to SQLite, so failed and
+// parked events survive a restart. MaxSize, MaxRetries, NoRetries, and
+// RetryDelay behave as they do for InMemoryDLQ.
+//
+// Dequeue leases events rather than removing them: a dequeued event stays
+// in the queue, hidden for DLQConfig.VisibilityTimeout, until Acknowledge
+// or RecordRetrySuccess removes it or RecordRetryFailure reschedules it.
+// An event whose processor crashed is therefore retried after the
+// timeout instead of being lost. Count includes leased events.
+//
+// Events are stored as JSON, so Metadata values read back from the queue
+// are JSON-decoded (map[string]any, float64, and so on) rather than the
+// original Go types. The metrics reported by Stats count operations since
+// the queue was opened.
+type SQLiteDLQ struct {
+	db     *sql.DB
+	cfg    DLQConfig
+	mu     sync.RWMutex
+	closed bool
+
+	// Metrics
+	enqueued  int64
+	retried   int64
+	parked    int64
+	recovered int64
+}
+
+// NewSQLiteDLQ creates a dead letter queue stored in SQLite.
+// The path should be a file path (e.g., "./dlq.db") or ":memory:" for testing.
+//
+// The database file is created with restrictive permissions (0600) since
+// event payloads may contain sensitive data.
+func NewSQLiteDLQ(path string, cfg DLQConfig) (*SQLiteDLQ, error) {
+	// Create file with restrictive permissions BEFORE sql.Open touches it.
+	if path != ":memory:" {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			f, createErr := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+			if createErr == nil {
+				if closeErr := f.Close(); closeErr != nil {
+					slog.Warn("failed to close DLQ file after creation",
+						slog.String("path", path),
+						slog.String("error", closeErr.Error()))
+				}
+			}
+			// Ignore createErr - file might have been created between Stat and OpenFile (TOCTOU)
+		}
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	if path == ":memory:" {
+		// Each connection to :memory: is a separate database
+		db.SetMaxOpenConns(1)
+	}
+
+	// Enable WAL mode for better concurrent read performance
+	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("enable WAL mode: %w", err)
+	}
+
+	// Wait for locks held by other processes sharing the file
+	if _, err := db.Exec("PRAGMA busy_timeout=5000"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("set busy timeout: %w", err)
+	}
+
+	// Times are Unix nanoseconds so they compare numerically
+	for _, stmt := range []string{
+		`CREATE TABLE IF NOT EXISTS dlq_events (
+			event_id TEXT PRIMARY KEY,
+			event_type TEXT NOT NULL,
+			next_retry_at INTEGER NOT NULL,
+			data BLOB NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS dlq_parked (
+			event_id TEXT PRIMARY KEY,
+			event_type TEXT NOT NULL,
+			parked_at INTEGER NOT NULL,
+			data BLOB NOT NULL
+		)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("create table: %w", err)
+		}
+	}
+
+	for _, stmt := range []string{
+		`CREATE INDEX IF NOT EXISTS idx_dlq_events_event_type ON dlq_events(event_type)`,
+		`CREATE INDEX IF NOT EXISTS idx_dlq_events_next_retry_at ON dlq_events(next_retry_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_dlq_parked_event_type ON dlq_parked(event_type)`,
+		`CREATE INDEX IF NOT EXISTS idx_dlq_parked_parked_at ON dlq_parked(parked_at)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("create index: %w", err)
+		}
+	}
+
+	// Ensure permissions are correct for existing files
+	if path != ":memory:" {
+		if err := os.Chmod(path, 0600); err != nil {
+			slog.Warn("failed to set restrictive permissions on DLQ file",
+				slog.String("path", path),
+				slog.String("error", err.Error()),
+				slog.String("security_note", "event payloads may be readable by other users"))
+		}
+	}
+
+	return &SQLiteDLQ{db: db, cfg: cfg.withDefaults()}, nil
+}
+
+// Enqueue adds a failed event to the DLQ. An event with the same ID
+// replaces the queued one.
+func (d *SQLiteDLQ) Enqueue(ctx context.Context, failed *FailedEvent) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDLQClosed
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op after Commit
+
+	// Check max size, not counting an event this one replaces
+	var size int
+	if err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM dlq_events WHERE event_id != ?
+	`, failed.EventID).Scan(&size); err != nil {
+		return fmt.Errorf("count events: %w", err)
+	}
+	if size >= d.cfg.MaxSize {
+		return &EventError{
+			Message: "DLQ is full",
+		}
+	}
+
+	// NoRetries mode or AttemptCount exceeded MaxRetries
+	if d.cfg.NoRetries || failed.AttemptCount >= d.cfg.MaxRetries {
+		return d.parkTx(ctx, tx, failed, "max retries exceeded")
+	}
+
+	if failed.NextRetryAt.IsZero() {
+		failed.NextRetryAt = time.Now().Add(d.cfg.RetryDelay)
+	}
+	if err := putFailed(ctx, tx, failed); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	d.enqueued++
+
+	if d.cfg.OnEnqueue != nil {
+		d.cfg.OnEnqueue(failed)
+	}
+	return nil
+}
+
+// Dequeue leases and returns up to limit events due for retry, earliest
+// first.
+func (d *SQLiteDLQ) Dequeue(ctx context.Context, limit int) ([]*FailedEvent, error) {
+	return d.lease(ctx, `
+		SELECT data FROM dlq_events
+		WHERE next_retry_at <= ? ORDER BY next_retry_at LIMIT ?
+	`, time.Now().UnixNano(), limit)
+}
+
+// DequeueByType leases and returns up to limit events of a type due for
+// retry, earliest first.
+func (d *SQLiteDLQ) DequeueByType(ctx context.Context, eventType string, limit int) ([]*FailedEvent, error) {
+	return d.lease(ctx, `
+		SELECT data FROM dlq_events
+		WHERE event_type = ? AND next_retry_at <= ? ORDER BY next_retry_at LIMIT ?
+	`, eventType, time.Now().UnixNano(), limit)
+}
+
+// Acknowledge marks an event as successfully reprocessed.
+func (d *SQLiteDLQ) Acknowledge(ctx context.Context, eventID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDLQClosed
+	}
+
+	if _, err := d.db.ExecContext(ctx, `DELETE FROM dlq_events WHERE event_id = ?`, eventID); err != nil {
+		return fmt.Errorf("delete event: %w", err)
+	}
+	d.recovered++
+	return nil
+}
+
+// Retry updates retry tracking and schedules next attempt.
+func (d *SQLiteDLQ) Retry(ctx context.Context, eventID string, nextRetryAt time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDLQClosed
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op after Commit
+
+	evt, err := getFailed(ctx, tx, eventID)
+	if err != nil {
+		return err
+	}
+
+	evt.AttemptCount++
+	evt.LastFailedAt = time.Now()
+	evt.NextRetryAt = nextRetryAt
+
+	if evt.AttemptCount >= d.cfg.MaxRetries {
+		if err := deleteFailed(ctx, tx, eventID); err != nil {
+			return err
+		}
+		return d.parkTx(ctx, tx, evt, "max retries exceeded")
+	}
+
+	if err := putFailed(ctx, tx, evt); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	d.retried++
+	return nil
+}
+
+// MoveToParked moves an event to the parked letter queue.
+func (d *SQLiteDLQ) MoveToParked(ctx context.Context, eventID string, reason string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDLQClosed
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op after Commit
+
+	evt, err := getFailed(ctx, tx, eventID)
+	if err != nil {
+		return err
+	}
+	if err := deleteFailed(ctx, tx, eventID); err != nil {
+		return err
+	}
+	return d.parkTx(ctx, tx, evt, reason)
+}
+
+// Count returns the number of events in the queue.
+func (d *SQLiteDLQ) Count(ctx context.Context) (int, error) {
+	return d.count(ctx, `SELECT COUNT(*) FROM dlq_events`)
+}
+
+// CountByType returns counts grouped by event type.
+func (d *SQLiteDLQ) CountByType(ctx context.Context) (map[string]int, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return nil, ErrDLQClosed
+	}
+
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT event_type, COUNT(*) FROM dlq_events GROUP BY event_type
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("count events: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var eventType string
+		var n int
+		if err := rows.Scan(&eventType, &n); err != nil {
+			return nil, fmt.Errorf("scan count: %w", err)
+		}
+		counts[eventType] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate counts: %w", err)
+	}
+	return counts, nil
+}
+
+// RecordRetrySuccess removes an event from tracking after successful retry.
+func (d *SQLiteDLQ) RecordRetrySuccess(ctx context.Context, eventID string) error {
+	return d.Acknowledge(ctx, eventID)
+}
+
+// RecordRetryFailure updates retry count and reschedules.
+func (d *SQLiteDLQ) RecordRetryFailure(ctx context.Context, failed *FailedEvent) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDLQClosed
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op after Commit
+
+	failed.AttemptCount++
+	failed.LastFailedAt = time.Now()
+
+	if failed.AttemptCount >= d.cfg.MaxRetries {
+		if err := deleteFailed(ctx, tx, failed.EventID); err != nil {
+			return err
+		}
+		return d.parkTx(ctx, tx, failed, "max retries exceeded")
+	}
+
+	// Exponential backoff for next retry
+	backoff := d.cfg.RetryDelay * time.Duration(1<<uint(failed.AttemptCount))
+	failed.NextRetryAt = time.Now().Add(backoff)
+
+	if err := putFailed(ctx, tx, failed); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	d.retried++
+	return nil
+}
+
+// Len returns the number of events in the DLQ (alias for Count).
+func (d *SQLiteDLQ) Len(ctx context.Context) (int, error) {
+	return d.Count(ctx)
+}
+
+// ParkedLen returns the number of parked events.
+func (d *SQLiteDLQ) ParkedLen(ctx context.Context) (int, error) {
+	return d.count(ctx, `SELECT COUNT(*) FROM dlq_parked`)
+}
+
+// ListParked returns up to limit parked events, oldest first. A limit of
+// zero or less returns all of them.
+func (d *SQLiteDLQ) ListParked(ctx context.Context, limit int) ([]*ParkedEvent, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return nil, ErrDLQClosed
+	}
+
+	if limit <= 0 {
+		limit = -1 // No limit
+	}
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT data FROM dlq_parked ORDER BY parked_at LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query parked events: %w", err)
+	}
+	return scanParked(rows)
+}
+
+// RecoverParked moves a parked event back to DLQ for retry.
+func (d *SQLiteDLQ) RecoverParked(ctx context.Context, eventID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDLQClosed
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op after Commit
+
+	var data []byte
+	err = tx.QueryRowContext(ctx, `SELECT data FROM dlq_parked WHERE event_id = ?`, eventID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &EventError{Message: "event not found in PLQ"}
+	}
+	if err != nil {
+		return fmt.Errorf("get parked event: %w", err)
+	}
+	var parked ParkedEvent
+	if err := json.Unmarshal(data, &parked); err != nil {
+		return fmt.Errorf("unmarshal parked event: %w", err)
+	}
+
+	// Reset retry count and move back to DLQ
+	failed := &parked.FailedEvent
+	failed.AttemptCount = 0
+	failed.NextRetryAt = time.Now()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM dlq_parked WHERE event_id = ?`, eventID); err != nil {
+		return fmt.Errorf("delete parked event: %w", err)
+	}
+	if err := putFailed(ctx, tx, failed); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	d.recovered++
+	return nil
+}
+
+// DeleteParked permanently deletes a parked event.
+func (d *SQLiteDLQ) DeleteParked(ctx context.Context, eventID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDLQClosed
+	}
+
+	res, err := d.db.ExecContext(ctx, `DELETE FROM dlq_parked WHERE event_id = ?`, eventID)
+	if err != nil {
+		return fmt.Errorf("delete parked event: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return &EventError{Message: "event not found in PLQ"}
+	}
+	return nil
+}
+
+// DrainAll removes and returns all queued events, including those not yet
+// due for retry. Implements DLQDrainer.
+func (d *SQLiteDLQ) DrainAll(ctx context.Context) ([]*FailedEvent, error) {
+	return d.take(ctx, `SELECT data FROM dlq_events ORDER BY next_retry_at`)
+}
+
+// DrainParked removes and returns all parked events. Implements ParkedDLQ.
+func (d *SQLiteDLQ) DrainParked(ctx context.Context) ([]*ParkedEvent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil, ErrDLQClosed
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op after Commit
+
+	rows, err := tx.QueryContext(ctx, `SELECT data FROM dlq_parked ORDER BY parked_at`)
+	if err != nil {
+		return nil, fmt.Errorf("query parked events: %w", err)
+	}
+	drained, err := scanParked(rows)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM dlq_parked`); err != nil {
+		return nil, fmt.Errorf("delete parked events: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return drained, nil
+}
+
+// ImportParked stores an already-parked event as-is, keeping its reason
+// and timestamps. OnPark is not called. Implements ParkedDLQ.
+func (d *SQLiteDLQ) ImportParked(ctx context.Context, parked *ParkedEvent) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDLQClosed
+	}
+	return putParked(ctx, d.db, parked)
+}
+
+// Stats returns DLQ statistics. Queue sizes are zero if they cannot be
+// read.
+func (d *SQLiteDLQ) Stats() DLQStats {
+	ctx := context.Background()
+	queued, _ := d.Count(ctx)
+	parked, _ := d.ParkedLen(ctx)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return DLQStats{
+		QueueSize:  queued,
+		ParkedSize: parked,
+		Enqueued:   d.enqueued,
+		Retried:    d.retried,
+		Parked:     d.parked,
+		Recovered:  d.recovered,
+	}
+}
+
+// Close closes the database. Closing twice is safe.
+func (d *SQLiteDLQ) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil
+	}
+
+	d.closed = true
+	return d.db.Close()
+}
+
+// codec returns the codec used to restore retried events.
+func (d *SQLiteDLQ) codec() Codec {
+	return d.cfg.Codec
+}
+
+// parkTx stores failed as parked and commits tx (must hold lock).
+func (d *SQLiteDLQ) parkTx(ctx context.Context, tx *sql.Tx, failed *FailedEvent, reason string) error {
+	parked := &ParkedEvent{
+		FailedEvent:   *failed,
+		ParkReason:    reason,
+		OriginalError: failed.ErrorMessage,
+		ParkedAt:      time.Now(),
+	}
+	if err := putParked(ctx, tx, parked); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	d.parked++
+
+	if d.cfg.OnPark != nil {
+		d.cfg.OnPark(parked)
+	}
+	return nil
+}
+
+// lease returns the queued events selected by a query on the data column
+// and hides them from later queries for the visibility timeout. The
+// returned events' NextRetryAt is the end of the lease.
+func (d *SQLiteDLQ) lease(ctx context.Context, query string, args ...any) ([]*FailedEvent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil, ErrDLQClosed
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op after Commit
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query events: %w", err)
+	}
+	events, err := scanFailed(rows)
+	if err != nil {
+		return nil, err
+	}
+	visibleAt := time.Now().Add(d.cfg.VisibilityTimeout)
+	for _, failed := range events {
+		failed.NextRetryAt = visibleAt
+		if err := putFailed(ctx, tx, failed); err != nil {
+			return nil, fmt.Errorf("lease event: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return events, nil
+}
+
+// take removes and returns the queued events selected by a query on the
+// data column.
+func (d *SQLiteDLQ) take(ctx context.Context, query string, args ...any) ([]*FailedEvent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil, ErrDLQClosed
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op after Commit
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query events: %w", err)
+	}
+	events, err := scanFailed(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, failed := range events {
+		if err := deleteFailed(ctx, tx, failed.EventID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return events, nil
+}
+
+// count runs a COUNT query.
+func (d *SQLiteDLQ) count(ctx context.Context, query string) (int, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return 0, ErrDLQClosed
+	}
+
+	var n int
+	if err := d.db.QueryRowContext(ctx, query).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count events: %w", err)
+	}
+	return n, nil
+}
+
+// execer is implemented by *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// putFailed inserts or replaces a queued event.
+func putFailed(ctx context.Context, db execer, failed *FailedEvent) error {
+	data, err := json.Marshal(failed)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO dlq_events (event_id, event_type, next_retry_at, data) VALUES (?, ?, ?, ?)
+	`, failed.EventID, failed.EventType, failed.NextRetryAt.UnixNano(), data); err != nil {
+		return fmt.Errorf("store event: %w", err)
+	}
+	return nil
+}
+
+// putParked inserts or replaces a parked event.
+func putParked(ctx context.Context, db execer, parked *ParkedEvent) error {
+	data, err := json.Marshal(parked)
+	if err != nil {
+		return fmt.Errorf("marshal parked event: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO dlq_parked (event_id, event_type, parked_at, data) VALUES (?, ?, ?, ?)
+	`, parked.EventID, parked.EventType, parked.ParkedAt.UnixNano(), data); err != nil {
+		return fmt.Errorf("store parked event: %w", err)
+	}
+	return nil
+}
+
+// getFailed reads a queued event.
+func getFailed(ctx context.Context, tx *sql.Tx, eventID string) (*FailedEvent, error) {
+	var data []byte
+	err := tx.QueryRowContext(ctx, `SELECT data FROM dlq_events WHERE event_id = ?`, eventID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &EventError{Message: "event not found in DLQ"}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get event: %w", err)
+	}
+	var failed FailedEvent
+	if err := json.Unmarshal(data, &failed); err != nil {
+		return nil, fmt.Errorf("unmarshal event: %w", err)
+	}
+	return &failed, nil
+}
+
+// deleteFailed removes a queued event.
+func deleteFailed(ctx context.Context, tx *sql.Tx, eventID string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM dlq_events WHERE event_id = ?`, eventID); err != nil {
+		return fmt.Errorf("delete event: %w", err)
+	}
+	return nil
+}
+
+// scanFailed reads and closes rows of the queued data column.
+func scanFailed(rows *sql.Rows) ([]*FailedEvent, error) {
+	defer rows.Close()
+
+	result := make([]*FailedEvent, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+		var failed FailedEvent
+		if err := json.Unmarshal(data, &failed); err != nil {
+			return nil, fmt.Errorf("unmarshal event: %w", err)
+		}
+		result = append(result, &failed)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate events: %w", err)
+	}
+	return result, nil
+}
+
+// scanParked reads and closes rows of the parked data column.
+func scanParked(rows *sql.Rows) ([]*ParkedEvent, error) {
+	defer rows.Close()
+
+	result := make([]*ParkedEvent, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("scan parked event: %w", err)
+		}
+		var parked ParkedEvent
+		if err := json.Unmarshal(data, &parked); err != nil {
+			return nil, fmt.Errorf("unmarshal parked event: %w", err)
+		}
+		result = append(result, &parked)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate parked events: %w", err)
+	}
+	return result, nil
+}
+
+// Compile-time checks that SQLiteDLQ supports processing and migration.
+var (
+	_ RetryableDLQ = (*SQLiteDLQ)(nil)
+	_ DLQDrainer   = (*SQLiteDLQ)(nil)
+	_ ParkedDLQ    = (*SQLiteDLQ)(nil)
+)
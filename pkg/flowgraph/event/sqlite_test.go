@@ -0,0 +1,425 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+)
+
+func newTestSQLiteDLQ(t *testing.T, path string, cfg event.DLQConfig) *event.SQLiteDLQ {
+	t.Helper()
+	dlq, err := event.NewSQLiteDLQ(path, cfg)
+	if err != nil {
+		t.Fatalf("NewSQLiteDLQ() error: %v", err)
+	}
+	t.Cleanup(func() { dlq.Close() })
+	return dlq
+}
+
+func failedWithID(id, eventType string) *event.FailedEvent {
+	evt := event.NewAny(eventType, "test", "t1", map[string]any{"id": id}, event.WithEventID(id))
+	return event.NewFailedEvent(evt, errors.New("handler failed"), "handler")
+}
+
+func TestSQLiteDLQ_DequeueReadyInRetryOrder(t *testing.T) {
+	dlq := newTestSQLiteDLQ(t, ":memory:", event.DLQConfig{RetryDelay: time.Hour})
+	ctx := context.Background()
+
+	now := time.Now()
+	for id, offset := range map[string]time.Duration{
+		"late":    -time.Second,
+		"early":   -time.Minute,
+		"future":  time.Hour,
+		"middle":  -10 * time.Second,
+		"default": 0, // Scheduled RetryDelay from now
+	} {
+		failed := failedWithID(id, "order.placed")
+		if offset != 0 {
+			failed.NextRetryAt = now.Add(offset)
+		}
+		if err := dlq.Enqueue(ctx, failed); err != nil {
+			t.Fatalf("Enqueue(%s) error: %v", id, err)
+		}
+	}
+
+	events, err := dlq.Dequeue(ctx, 2)
+	if err != nil {
+		t.Fatalf("Dequeue() error: %v", err)
+	}
+	if len(events) != 2 || events[0].EventID != "early" || events[1].EventID != "middle" {
+		t.Fatalf("Dequeue(2) = %v, want [early middle]", eventIDs(events))
+	}
+
+	events, _ = dlq.Dequeue(ctx, 10)
+	if len(events) != 1 || events[0].EventID != "late" {
+		t.Errorf("Dequeue(10) = %v, want [late]", eventIDs(events))
+	}
+
+	if events, _ := dlq.Dequeue(ctx, 10); len(events) != 0 {
+		t.Errorf("Dequeue() = %v, want leased events hidden", eventIDs(events))
+	}
+	if n, _ := dlq.Count(ctx); n != 5 {
+		t.Errorf("Count() = %d, want 5 including leased", n)
+	}
+}
+
+func TestSQLiteDLQ_DequeueByType(t *testing.T) {
+	dlq := newTestSQLiteDLQ(t, ":memory:", event.DLQConfig{})
+	ctx := context.Background()
+
+	for i, id := range []string{"a1", "b1", "a2"} {
+		eventType := "type.a"
+		if id[0] == 'b' {
+			eventType = "type.b"
+		}
+		failed := failedWithID(id, eventType)
+		failed.NextRetryAt = time.Now().Add(time.Duration(i-10) * time.Second)
+		dlq.Enqueue(ctx, failed)
+	}
+
+	counts, err := dlq.CountByType(ctx)
+	if err != nil {
+		t.Fatalf("CountByType() error: %v", err)
+	}
+	if counts["type.a"] != 2 || counts["type.b"] != 1 {
+		t.Errorf("CountByType() = %v", counts)
+	}
+
+	events, err := dlq.DequeueByType(ctx, "type.a", 10)
+	if err != nil {
+		t.Fatalf("DequeueByType() error: %v", err)
+	}
+	if ids := eventIDs(events); len(ids) != 2 || ids[0] != "a1" || ids[1] != "a2" {
+		t.Errorf("DequeueByType() = %v, want [a1 a2]", ids)
+	}
+	if events, _ := dlq.DequeueByType(ctx, "type.a", 10); len(events) != 0 {
+		t.Errorf("DequeueByType() = %v, want leased events hidden", eventIDs(events))
+	}
+	if n, _ := dlq.Count(ctx); n != 3 {
+		t.Errorf("Count() = %d, want 3 including leased", n)
+	}
+}
+
+func TestSQLiteDLQ_CrashAfterDequeue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dlq.db")
+	ctx := context.Background()
+	cfg := event.DLQConfig{VisibilityTimeout: 50 * time.Millisecond}
+
+	dlq, err := event.NewSQLiteDLQ(path, cfg)
+	if err != nil {
+		t.Fatalf("NewSQLiteDLQ() error: %v", err)
+	}
+	failed := failedWithID("e1", "order.placed")
+	failed.NextRetryAt = time.Now().Add(-time.Second)
+	dlq.Enqueue(ctx, failed)
+
+	events, err := dlq.Dequeue(ctx, 10)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("Dequeue() = %v, %v", eventIDs(events), err)
+	}
+	// Crash before RecordRetryFailure or Acknowledge
+	dlq.Close()
+
+	reopened := newTestSQLiteDLQ(t, path, cfg)
+	if events, _ := reopened.Dequeue(ctx, 10); len(events) != 0 {
+		t.Errorf("Dequeue() within visibility timeout = %v, want none", eventIDs(events))
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	events, err = reopened.Dequeue(ctx, 10)
+	if err != nil {
+		t.Fatalf("Dequeue() error: %v", err)
+	}
+	if len(events) != 1 || events[0].EventID != "e1" {
+		t.Fatalf("Dequeue() after visibility timeout = %v, want [e1]", eventIDs(events))
+	}
+
+	if err := reopened.Acknowledge(ctx, "e1"); err != nil {
+		t.Fatalf("Acknowledge() error: %v", err)
+	}
+	if n, _ := reopened.Count(ctx); n != 0 {
+		t.Errorf("Count() after Acknowledge = %d, want 0", n)
+	}
+}
+
+func TestSQLiteDLQ_LeaseUpdatesStoredEvent(t *testing.T) {
+	dlq := newTestSQLiteDLQ(t, ":memory:", event.DLQConfig{VisibilityTimeout: time.Hour})
+	ctx := context.Background()
+
+	failed := failedWithID("e1", "order.placed")
+	failed.NextRetryAt = time.Now().Add(-time.Second)
+	dlq.Enqueue(ctx, failed)
+
+	leased, err := dlq.Dequeue(ctx, 10)
+	if err != nil || len(leased) != 1 {
+		t.Fatalf("Dequeue() = %v, %v", eventIDs(leased), err)
+	}
+
+	drained, err := dlq.DrainAll(ctx)
+	if err != nil || len(drained) != 1 {
+		t.Fatalf("DrainAll() = %v, %v", eventIDs(drained), err)
+	}
+	if !drained[0].NextRetryAt.Equal(leased[0].NextRetryAt) {
+		t.Errorf("stored NextRetryAt = %v, want lease end %v", drained[0].NextRetryAt, leased[0].NextRetryAt)
+	}
+	if until := time.Until(drained[0].NextRetryAt); until < 59*time.Minute {
+		t.Errorf("stored NextRetryAt in %v, want about the visibility timeout", until)
+	}
+}
+
+func TestSQLiteDLQ_RecordRetryFailureReschedulesLeased(t *testing.T) {
+	dlq := newTestSQLiteDLQ(t, ":memory:", event.DLQConfig{MaxRetries: 2, RetryDelay: time.Hour})
+	ctx := context.Background()
+
+	failed := failedWithID("e1", "order.placed")
+	failed.NextRetryAt = time.Now().Add(-time.Second)
+	dlq.Enqueue(ctx, failed)
+
+	events, _ := dlq.Dequeue(ctx, 10)
+	if err := dlq.RecordRetryFailure(ctx, events[0]); err != nil {
+		t.Fatalf("RecordRetryFailure() error: %v", err)
+	}
+	if n, _ := dlq.Count(ctx); n != 1 {
+		t.Errorf("Count() = %d, want 1 rescheduled", n)
+	}
+
+	if err := dlq.RecordRetryFailure(ctx, events[0]); err != nil {
+		t.Fatalf("RecordRetryFailure() error: %v", err)
+	}
+	if n, _ := dlq.Count(ctx); n != 0 {
+		t.Errorf("Count() = %d, want 0 after parking", n)
+	}
+	if n, _ := dlq.ParkedLen(ctx); n != 1 {
+		t.Errorf("ParkedLen() = %d, want 1", n)
+	}
+}
+
+func TestSQLiteDLQ_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dlq.db")
+	ctx := context.Background()
+
+	dlq, err := event.NewSQLiteDLQ(path, event.DLQConfig{MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("NewSQLiteDLQ() error: %v", err)
+	}
+	queued := failedWithID("queued", "order.placed")
+	queued.Metadata = map[string]any{"attempt_source": "webhook"}
+	dlq.Enqueue(ctx, queued)
+	parked := failedWithID("parked", "order.placed")
+	parked.AttemptCount = 1
+	dlq.Enqueue(ctx, parked)
+	dlq.Close()
+
+	if err := dlq.Enqueue(ctx, failedWithID("closed", "x")); !errors.Is(err, event.ErrDLQClosed) {
+		t.Errorf("Enqueue() after Close error = %v, want ErrDLQClosed", err)
+	}
+
+	reopened := newTestSQLiteDLQ(t, path, event.DLQConfig{MaxRetries: 1})
+	drained, err := reopened.DrainAll(ctx)
+	if err != nil {
+		t.Fatalf("DrainAll() error: %v", err)
+	}
+	if len(drained) != 1 || drained[0].EventID != "queued" {
+		t.Fatalf("DrainAll() = %v, want [queued]", eventIDs(drained))
+	}
+	if drained[0].ErrorMessage != "handler failed" || drained[0].Metadata["attempt_source"] != "webhook" {
+		t.Errorf("restored event = %+v", drained[0])
+	}
+
+	list, err := reopened.ListParked(ctx, 0)
+	if err != nil {
+		t.Fatalf("ListParked() error: %v", err)
+	}
+	if len(list) != 1 || list[0].EventID != "parked" || list[0].ParkReason != "max retries exceeded" {
+		t.Errorf("ListParked() = %+v", list)
+	}
+}
+
+func TestSQLiteDLQ_MaxSize(t *testing.T) {
+	dlq := newTestSQLiteDLQ(t, ":memory:", event.DLQConfig{MaxSize: 2})
+	ctx := context.Background()
+
+	for _, id := range []string{"e1", "e2"} {
+		if err := dlq.Enqueue(ctx, failedWithID(id, "x")); err != nil {
+			t.Fatalf("Enqueue(%s) error: %v", id, err)
+		}
+	}
+	if err := dlq.Enqueue(ctx, failedWithID("e3", "x")); err == nil {
+		t.Error("expected error when DLQ is full")
+	}
+
+	// Replacing a queued event does not grow the queue
+	if err := dlq.Enqueue(ctx, failedWithID("e1", "x")); err != nil {
+		t.Errorf("Enqueue(e1) again at capacity error: %v", err)
+	}
+	if n, _ := dlq.Count(ctx); n != 2 {
+		t.Errorf("Count() = %d, want 2", n)
+	}
+}
+
+func TestSQLiteDLQ_MaxRetries(t *testing.T) {
+	var parkedCalls atomic.Int32
+	dlq := newTestSQLiteDLQ(t, ":memory:", event.DLQConfig{
+		MaxRetries: 2,
+		RetryDelay: time.Millisecond,
+		OnPark:     func(*event.ParkedEvent) { parkedCalls.Add(1) },
+	})
+	ctx := context.Background()
+
+	dlq.Enqueue(ctx, failedWithID("e1", "x"))
+	for i := 0; i < 2; i++ {
+		time.Sleep(5 * time.Millisecond)
+		events, _ := dlq.Dequeue(ctx, 10)
+		if len(events) != 1 {
+			t.Fatalf("attempt %d: Dequeue() = %d events, want 1", i, len(events))
+		}
+		if err := dlq.RecordRetryFailure(ctx, events[0]); err != nil {
+			t.Fatalf("RecordRetryFailure() error: %v", err)
+		}
+	}
+
+	if n, _ := dlq.ParkedLen(ctx); n != 1 {
+		t.Errorf("ParkedLen() = %d, want 1", n)
+	}
+	if n, _ := dlq.Len(ctx); n != 0 {
+		t.Errorf("Len() = %d, want 0", n)
+	}
+	if parkedCalls.Load() != 1 {
+		t.Errorf("OnPark called %d times, want 1", parkedCalls.Load())
+	}
+
+	stats := dlq.Stats()
+	if stats.Enqueued != 1 || stats.Retried != 1 || stats.Parked != 1 || stats.ParkedSize != 1 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestSQLiteDLQ_NoRetries(t *testing.T) {
+	dlq := newTestSQLiteDLQ(t, ":memory:", event.DLQConfig{NoRetries: true})
+	ctx := context.Background()
+
+	dlq.Enqueue(ctx, failedWithID("e1", "x"))
+	if n, _ := dlq.ParkedLen(ctx); n != 1 {
+		t.Errorf("ParkedLen() = %d, want 1", n)
+	}
+}
+
+func TestSQLiteDLQ_RetryAndMoveToParked(t *testing.T) {
+	dlq := newTestSQLiteDLQ(t, ":memory:", event.DLQConfig{MaxRetries: 5, RetryDelay: time.Hour})
+	ctx := context.Background()
+
+	dlq.Enqueue(ctx, failedWithID("e1", "x"))
+	if err := dlq.Retry(ctx, "e1", time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("Retry() error: %v", err)
+	}
+	events, _ := dlq.Dequeue(ctx, 10)
+	if len(events) != 1 || events[0].AttemptCount != 1 {
+		t.Fatalf("Dequeue() after Retry = %+v", events)
+	}
+
+	dlq.Enqueue(ctx, failedWithID("e2", "x"))
+	if err := dlq.MoveToParked(ctx, "e2", "manual intervention"); err != nil {
+		t.Fatalf("MoveToParked() error: %v", err)
+	}
+	if err := dlq.MoveToParked(ctx, "e2", "again"); err == nil {
+		t.Error("MoveToParked() of missing event: expected error")
+	}
+	list, _ := dlq.ListParked(ctx, 10)
+	if len(list) != 1 || list[0].ParkReason != "manual intervention" {
+		t.Errorf("ListParked() = %+v", list)
+	}
+}
+
+func TestSQLiteDLQ_RecoverAndDeleteParked(t *testing.T) {
+	dlq := newTestSQLiteDLQ(t, ":memory:", event.DLQConfig{NoRetries: true})
+	ctx := context.Background()
+
+	for _, id := range []string{"e1", "e2", "e3"} {
+		dlq.Enqueue(ctx, failedWithID(id, "x"))
+	}
+	if list, _ := dlq.ListParked(ctx, 2); len(list) != 2 {
+		t.Errorf("ListParked(2) = %d events, want 2", len(list))
+	}
+
+	if err := dlq.RecoverParked(ctx, "e1"); err != nil {
+		t.Fatalf("RecoverParked() error: %v", err)
+	}
+	events, _ := dlq.Dequeue(ctx, 10)
+	if len(events) != 1 || events[0].EventID != "e1" || events[0].AttemptCount != 0 {
+		t.Errorf("Dequeue() after RecoverParked = %+v", events)
+	}
+
+	if err := dlq.DeleteParked(ctx, "e2"); err != nil {
+		t.Fatalf("DeleteParked() error: %v", err)
+	}
+	if err := dlq.DeleteParked(ctx, "e2"); err == nil {
+		t.Error("DeleteParked() of missing event: expected error")
+	}
+	if err := dlq.RecoverParked(ctx, "e2"); err == nil {
+		t.Error("RecoverParked() of missing event: expected error")
+	}
+	if n, _ := dlq.ParkedLen(ctx); n != 1 {
+		t.Errorf("ParkedLen() = %d, want 1", n)
+	}
+}
+
+func TestSQLiteDLQ_Processor(t *testing.T) {
+	dlq := newTestSQLiteDLQ(t, ":memory:", event.DLQConfig{RetryDelay: time.Millisecond})
+	ctx := context.Background()
+
+	var processed atomic.Int32
+	router := event.NewRouter(event.RouterConfig{})
+	router.Register(event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		processed.Add(1)
+		return nil, nil
+	}))
+
+	for _, id := range []string{"e1", "e2", "e3"} {
+		dlq.Enqueue(ctx, failedWithID(id, "test.event"))
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	processor := event.NewDLQProcessor(dlq, router, event.DLQProcessorConfig{PollInterval: 5 * time.Millisecond})
+	processor.Start(ctx)
+	deadline := time.Now().Add(time.Second)
+	for processed.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	processor.Stop()
+
+	if processed.Load() != 3 {
+		t.Errorf("processed %d events, want 3", processed.Load())
+	}
+	if n, _ := dlq.Count(ctx); n != 0 {
+		t.Errorf("Count() = %d, want 0", n)
+	}
+}
+
+func TestSQLiteDLQ_MigrateFromMemory(t *testing.T) {
+	src := populatedDLQ(t)
+	dst := newTestSQLiteDLQ(t, ":memory:", event.DLQConfig{MaxRetries: 5})
+
+	migrated, err := event.MigrateDLQ(context.Background(), src, dst)
+	if err != nil {
+		t.Fatalf("MigrateDLQ() error: %v", err)
+	}
+	if migrated != 5 {
+		t.Errorf("migrated = %d, want 5", migrated)
+	}
+	if n, _ := dst.ParkedLen(context.Background()); n != 2 {
+		t.Errorf("ParkedLen() = %d, want 2", n)
+	}
+}
+
+func eventIDs(events []*event.FailedEvent) []string {
+	ids := make([]string, len(events))
+	for i, evt := range events {
+		ids[i] = evt.EventID
+	}
+	return ids
+}
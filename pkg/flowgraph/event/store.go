@@ -0,0 +1,102 @@
+package event
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// EventStore persists events so they can be replayed later, e.g. through
+// a fixed handler with a Replayer. Set BusConfig.Store to record every
+// published event.
+type EventStore interface {
+	// Append stores an event.
+	Append(ctx context.Context, evt Event) error
+
+	// Range returns the stored events matching query, in append order.
+	Range(ctx context.Context, query EventQuery) ([]Event, error)
+}
+
+// EventQuery selects stored events. Zero-valued fields match every event;
+// set fields must all match.
+type EventQuery struct {
+	// CorrelationID selects one chain of related events.
+	CorrelationID string
+
+	// Types selects events of any of the listed types.
+	Types []string
+
+	// From and To bound event timestamps: From is inclusive and To is
+	// exclusive.
+	From time.Time
+	To   time.Time
+
+	// Limit caps the number of events returned.
+	// Default: 0 (no limit)
+	Limit int
+}
+
+// Matches reports whether evt is selected by the query, ignoring Limit.
+func (q EventQuery) Matches(evt Event) bool {
+	if q.CorrelationID != "" && evt.CorrelationID() != q.CorrelationID {
+		return false
+	}
+	if len(q.Types) > 0 && !slices.Contains(q.Types, evt.Type()) {
+		return false
+	}
+	ts := evt.Timestamp()
+	if !q.From.IsZero() && ts.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !ts.Before(q.To) {
+		return false
+	}
+	return true
+}
+
+// InMemoryEventStore is an in-memory implementation of EventStore.
+// It keeps every appended event, so it suits tests and short-lived
+// processes.
+type InMemoryEventStore struct {
+	mu     sync.RWMutex
+	events []Event
+}
+
+// NewInMemoryEventStore creates an empty in-memory event store.
+func NewInMemoryEventStore() *InMemoryEventStore {
+	return &InMemoryEventStore{}
+}
+
+// Append stores an event.
+func (s *InMemoryEventStore) Append(ctx context.Context, evt Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, evt)
+	return nil
+}
+
+// Range returns the stored events matching query, in append order.
+func (s *InMemoryEventStore) Range(ctx context.Context, query EventQuery) ([]Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]Event, 0)
+	for _, evt := range s.events {
+		if query.Limit > 0 && len(result) >= query.Limit {
+			break
+		}
+		if query.Matches(evt) {
+			result = append(result, evt)
+		}
+	}
+	return result, nil
+}
+
+// Len returns the number of stored events.
+func (s *InMemoryEventStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.events)
+}
@@ -0,0 +1,95 @@
+package event
+
+import (
+	"context"
+	"fmt"
+)
+
+// ValidationAction decides what happens to an event that fails schema
+// validation in SchemaValidationMiddleware. It returns proceed=true to
+// pass the event to the handler anyway; otherwise the middleware returns
+// err (nil to drop the event silently) without calling the handler.
+type ValidationAction func(ctx context.Context, evt Event, handler string, validationErr error) (proceed bool, err error)
+
+// RejectInvalid returns a ValidationAction that fails the handler with an
+// *EventError wrapping the validation error, as RouterConfig.ValidateEvents
+// does for the whole route. Under a router with a DLQ, the failure is
+// dead-lettered like any other handler error.
+func RejectInvalid() ValidationAction {
+	return func(ctx context.Context, evt Event, handler string, validationErr error) (bool, error) {
+		return false, &EventError{
+			Event:   evt,
+			Message: "event validation failed",
+			Err:     validationErr,
+		}
+	}
+}
+
+// DeadLetterInvalid returns a ValidationAction that enqueues the event in
+// dlq with the validation error and skips the handler without failing it.
+// If the enqueue fails, the handler fails with the enqueue error.
+//
+// Panics if dlq is nil.
+func DeadLetterInvalid(dlq DeadLetterQueue) ValidationAction {
+	if dlq == nil {
+		panic("event: dead letter queue cannot be nil")
+	}
+	return func(ctx context.Context, evt Event, handler string, validationErr error) (bool, error) {
+		failed := NewFailedEvent(evt, validationErr, handler)
+		if err := dlq.Enqueue(ctx, failed); err != nil {
+			return false, &EventError{
+				Event:   evt,
+				Message: "dead-letter invalid event",
+				Err:     fmt.Errorf("%w (validation: %v)", err, validationErr),
+			}
+		}
+		return false, nil
+	}
+}
+
+// LogInvalid returns a ValidationAction that reports the validation error
+// to logFn and passes the event to the handler.
+func LogInvalid(logFn func(evt Event, handler string, err error)) ValidationAction {
+	return func(ctx context.Context, evt Event, handler string, validationErr error) (bool, error) {
+		if logFn != nil {
+			logFn(evt, handler, validationErr)
+		}
+		return true, nil
+	}
+}
+
+// SchemaValidationMiddleware creates middleware that validates each event
+// with registry.Validate before the handler runs, and applies action to
+// events that fail. Valid events pass through unchanged. Events of types
+// without a registered schema fail validation.
+//
+// Unlike RouterConfig.ValidateEvents, which rejects an event for every
+// handler, the middleware can be applied per handler with its own policy.
+// Because it runs per handler, an invalid event reaching several handlers
+// is acted on once for each.
+//
+// Panics if registry or action is nil.
+//
+// Example:
+//
+//	router.Use(event.SchemaValidationMiddleware(registry, event.DeadLetterInvalid(dlq)))
+func SchemaValidationMiddleware(registry *EventRegistry, action ValidationAction) MiddlewareFunc {
+	if registry == nil {
+		panic("event: schema validation registry cannot be nil")
+	}
+	if action == nil {
+		panic("event: validation action cannot be nil")
+	}
+	return func(next Handler) Handler {
+		name := handlerName(next)
+		return HandlerFunc(func(ctx context.Context, evt Event) ([]Event, error) {
+			if err := registry.Validate(evt); err != nil {
+				proceed, actionErr := action(ctx, evt, name, err)
+				if !proceed {
+					return nil, actionErr
+				}
+			}
+			return next.Handle(ctx, evt)
+		})
+	}
+}
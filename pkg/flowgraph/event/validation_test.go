@@ -0,0 +1,142 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+)
+
+func validationRegistry(t *testing.T) *event.EventRegistry {
+	t.Helper()
+	registry := event.NewEventRegistry()
+	err := registry.Register(&event.EventSchema{
+		Type:    "order.created",
+		Source:  "orders",
+		Version: 1,
+		Validator: func(evt event.Event) error {
+			if evt.TenantID() == "" {
+				return errors.New("tenant required")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return registry
+}
+
+func validOrder() event.Event {
+	return event.New("order.created", "orders", "tenant-1", map[string]any{"id": "o-1"})
+}
+
+func invalidOrder() event.Event {
+	return event.New("order.created", "orders", "", map[string]any{"id": "o-2"})
+}
+
+// countingHandler returns a handler that counts its calls.
+func countingHandler(calls *int) event.Handler {
+	return event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		*calls++
+		return nil, nil
+	})
+}
+
+func TestSchemaValidationMiddleware_Reject(t *testing.T) {
+	calls := 0
+	handler := event.SchemaValidationMiddleware(validationRegistry(t), event.RejectInvalid())(countingHandler(&calls))
+	ctx := context.Background()
+
+	if _, err := handler.Handle(ctx, validOrder()); err != nil {
+		t.Fatalf("valid event rejected: %v", err)
+	}
+
+	_, err := handler.Handle(ctx, invalidOrder())
+	var evtErr *event.EventError
+	if !errors.As(err, &evtErr) {
+		t.Fatalf("expected EventError, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected handler to run only for the valid event, ran %d times", calls)
+	}
+
+	if _, err := handler.Handle(ctx, event.New("unknown.type", "x", "t", map[string]any{})); err == nil {
+		t.Error("expected event without a schema to be rejected")
+	}
+}
+
+func TestSchemaValidationMiddleware_DeadLetter(t *testing.T) {
+	dlq := event.NewInMemoryDLQ(event.DLQConfig{})
+	calls := 0
+	handler := event.SchemaValidationMiddleware(validationRegistry(t), event.DeadLetterInvalid(dlq))(countingHandler(&calls))
+	ctx := context.Background()
+
+	if _, err := handler.Handle(ctx, validOrder()); err != nil {
+		t.Fatalf("valid event failed: %v", err)
+	}
+	invalid := invalidOrder()
+	if _, err := handler.Handle(ctx, invalid); err != nil {
+		t.Fatalf("dead-lettered event should not fail the handler: %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("expected handler to run only for the valid event, ran %d times", calls)
+	}
+	failed, err := dlq.DrainAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 1 || failed[0].EventID != invalid.ID() {
+		t.Fatalf("expected the invalid event in the DLQ, got %+v", failed)
+	}
+	if failed[0].ErrorMessage != "validation failed: tenant required" {
+		t.Errorf("unexpected recorded error %q", failed[0].ErrorMessage)
+	}
+}
+
+func TestSchemaValidationMiddleware_LogAndPass(t *testing.T) {
+	var logged []string
+	calls := 0
+	handler := event.SchemaValidationMiddleware(validationRegistry(t), event.LogInvalid(func(evt event.Event, handler string, err error) {
+		logged = append(logged, evt.ID())
+	}))(countingHandler(&calls))
+
+	invalid := invalidOrder()
+	if _, err := handler.Handle(context.Background(), validOrder()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handler.Handle(context.Background(), invalid); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 2 {
+		t.Errorf("expected both events to reach the handler, got %d", calls)
+	}
+	if len(logged) != 1 || logged[0] != invalid.ID() {
+		t.Errorf("expected only the invalid event to be logged, got %v", logged)
+	}
+}
+
+func TestSchemaValidationMiddleware_WithRouter(t *testing.T) {
+	dlq := event.NewInMemoryDLQ(event.DLQConfig{})
+	router := event.NewRouter(event.RouterConfig{})
+	router.Use(event.SchemaValidationMiddleware(validationRegistry(t), event.DeadLetterInvalid(dlq)))
+	calls := 0
+	router.Register(countingHandler(&calls))
+
+	ctx := context.Background()
+	for _, evt := range []event.Event{validOrder(), invalidOrder()} {
+		if _, err := router.Route(ctx, evt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("expected handler to run once, ran %d times", calls)
+	}
+	if n, _ := dlq.Count(ctx); n != 1 {
+		t.Errorf("expected 1 dead-lettered event, got %d", n)
+	}
+}
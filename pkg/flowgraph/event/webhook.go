@@ -0,0 +1,218 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
+)
+
+// Webhook request headers set on every delivery.
+const (
+	// WebhookEventIDHeader carries the event ID for receiver-side deduplication.
+	WebhookEventIDHeader = "X-Flowgraph-Event-Id"
+
+	// WebhookEventTypeHeader carries the event type.
+	WebhookEventTypeHeader = "X-Flowgraph-Event-Type"
+
+	// WebhookSignatureHeader carries the HMAC signature when signing is enabled.
+	// The value has the form "sha256=<hex digest of the request body>".
+	WebhookSignatureHeader = "X-Flowgraph-Signature"
+)
+
+// WebhookHandler delivers events to an external HTTP endpoint.
+// Each event is POSTed as a JSON envelope (see Marshal), or in the format
+// of the codec set with WithWebhookCodec. Deliveries that
+// fail with a network error or a retryable status (5xx, 429) are retried;
+// any other non-2xx response fails immediately.
+//
+// Once the handler's own retries (WithWebhookRetry) are exhausted, the
+// error is categorized as permanent so the router does not retry the
+// delivery again with RouterConfig.RetryConfig.
+type WebhookHandler struct {
+	url     string
+	client  *http.Client
+	headers map[string]string
+	timeout time.Duration
+	retry   fgerrors.RetryConfig
+	secret  []byte
+	types   []string
+	codec   Codec
+}
+
+// WebhookOption configures a WebhookHandler.
+type WebhookOption func(*WebhookHandler)
+
+// WithWebhookHeader adds a header to every request.
+func WithWebhookHeader(key, value string) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.headers[key] = value
+	}
+}
+
+// WithWebhookTimeout bounds each delivery attempt (default: 10s).
+func WithWebhookTimeout(d time.Duration) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.timeout = d
+	}
+}
+
+// WithWebhookRetry sets the retry policy for failed deliveries
+// (default: fgerrors.DefaultRetry).
+func WithWebhookRetry(cfg fgerrors.RetryConfig) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.retry = cfg
+	}
+}
+
+// WithWebhookSigningSecret enables HMAC-SHA256 signing of request bodies.
+// The signature is sent in WebhookSignatureHeader.
+func WithWebhookSigningSecret(secret []byte) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.secret = secret
+	}
+}
+
+// WithWebhookCodec sets the codec used to encode request bodies
+// (default: DefaultCodec). The codec's ContentType is sent as the
+// Content-Type header.
+func WithWebhookCodec(codec Codec) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.codec = codec
+	}
+}
+
+// WithWebhookClient sets the HTTP client used for deliveries.
+func WithWebhookClient(client *http.Client) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.client = client
+	}
+}
+
+// WithWebhookEventTypes restricts the handler to specific event types.
+// By default the handler accepts all events.
+func WithWebhookEventTypes(types ...string) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.types = types
+	}
+}
+
+// NewWebhookHandler creates a handler that POSTs events to url.
+//
+// Example:
+//
+//	hook := event.NewWebhookHandler("https://example.com/hooks/flowgraph",
+//	    event.WithWebhookHeader("Authorization", "Bearer "+token),
+//	    event.WithWebhookSigningSecret(secret),
+//	    event.WithWebhookEventTypes("order.created"))
+//	router.Register(hook)
+func NewWebhookHandler(url string, opts ...WebhookOption) *WebhookHandler {
+	h := &WebhookHandler{
+		url:     url,
+		client:  http.DefaultClient,
+		headers: make(map[string]string),
+		timeout: 10 * time.Second,
+		retry:   fgerrors.DefaultRetry,
+		codec:   DefaultCodec,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Handles returns the configured event types (empty means all events).
+func (h *WebhookHandler) Handles() []string {
+	return h.types
+}
+
+// Handle delivers the event. It produces no derived events.
+func (h *WebhookHandler) Handle(ctx context.Context, evt Event) ([]Event, error) {
+	body, err := h.codec.Encode(evt)
+	if err != nil {
+		return nil, &EventError{
+			Event:   evt,
+			Handler: "webhook",
+			Message: "failed to encode event",
+			Err:     err,
+		}
+	}
+
+	result := fgerrors.WithRetryContext(ctx, h.retry, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, h.deliver(ctx, evt, body)
+	})
+	if result.Err != nil {
+		return nil, &EventError{
+			Event:     evt,
+			Handler:   "webhook",
+			Message:   fmt.Sprintf("webhook delivery to %s failed", h.url),
+			Err:       fgerrors.Permanent(result.Err, "webhook retries exhausted"),
+			Attempt:   result.Attempts,
+			Timestamp: time.Now(),
+		}
+	}
+
+	return nil, nil
+}
+
+// deliver performs a single POST attempt.
+func (h *WebhookHandler) deliver(ctx context.Context, evt Event, body []byte) error {
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return fgerrors.Permanent(err, "build webhook request")
+	}
+
+	req.Header.Set("Content-Type", h.codec.ContentType())
+	req.Header.Set(WebhookEventIDHeader, evt.ID())
+	req.Header.Set(WebhookEventTypeHeader, evt.Type())
+	for k, v := range h.headers {
+		req.Header.Set(k, v)
+	}
+	if len(h.secret) > 0 {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(h.secret, body))
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		// Network failures and per-attempt timeouts are worth retrying
+		return fgerrors.Transient(err, "webhook request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &fgerrors.HTTPError{
+			StatusCode: resp.StatusCode,
+			Message:    string(msg),
+			Endpoint:   h.url,
+		}
+	}
+
+	// Drain so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// SignWebhookPayload returns the signature header value for a webhook body:
+// "sha256=" followed by the hex HMAC-SHA256 of body using secret.
+// Receivers recompute it and compare with hmac.Equal.
+func SignWebhookPayload(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,187 @@
+package event_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+)
+
+var fastWebhookRetry = fgerrors.RetryConfig{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+	BackoffFactor:  2.0,
+}
+
+func TestWebhookHandler_DeliversEnvelope(t *testing.T) {
+	secret := []byte("s3cret")
+
+	var (
+		gotBody    []byte
+		gotHeaders http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotHeaders = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	hook := event.NewWebhookHandler(server.URL,
+		event.WithWebhookHeader("Authorization", "Bearer token"),
+		event.WithWebhookSigningSecret(secret),
+		event.WithWebhookRetry(fastWebhookRetry))
+
+	evt := event.New("order.created", "orders", "tenant-1", map[string]string{"id": "o-1"})
+	derived, err := hook.Handle(context.Background(), evt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(derived) != 0 {
+		t.Errorf("expected no derived events, got %d", len(derived))
+	}
+
+	var envelope struct {
+		Metadata event.Metadata    `json:"metadata"`
+		Payload  map[string]string `json:"payload"`
+	}
+	if err := json.Unmarshal(gotBody, &envelope); err != nil {
+		t.Fatalf("body is not a JSON envelope: %v", err)
+	}
+	if envelope.Metadata.EventID != evt.ID() {
+		t.Errorf("expected event ID %s, got %s", evt.ID(), envelope.Metadata.EventID)
+	}
+	if envelope.Metadata.EventType != "order.created" {
+		t.Errorf("expected type order.created, got %s", envelope.Metadata.EventType)
+	}
+	if envelope.Payload["id"] != "o-1" {
+		t.Errorf("expected payload id=o-1, got %v", envelope.Payload)
+	}
+
+	if got := gotHeaders.Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected JSON content type, got %s", got)
+	}
+	if got := gotHeaders.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("expected custom header, got %q", got)
+	}
+	if got := gotHeaders.Get(event.WebhookEventIDHeader); got != evt.ID() {
+		t.Errorf("expected event ID header %s, got %s", evt.ID(), got)
+	}
+	if got := gotHeaders.Get(event.WebhookSignatureHeader); got != event.SignWebhookPayload(secret, gotBody) {
+		t.Errorf("signature mismatch: %s", got)
+	}
+}
+
+func TestWebhookHandler_RetriesOn500(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	hook := event.NewWebhookHandler(server.URL, event.WithWebhookRetry(fastWebhookRetry))
+
+	evt := event.NewAny("test.event", "test", "t1", nil)
+	if _, err := hook.Handle(context.Background(), evt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestWebhookHandler_Non2xxFails(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "bad payload", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	hook := event.NewWebhookHandler(server.URL, event.WithWebhookRetry(fastWebhookRetry))
+
+	evt := event.NewAny("test.event", "test", "t1", nil)
+	_, err := hook.Handle(context.Background(), evt)
+	if err == nil {
+		t.Fatal("expected error for 400 response")
+	}
+
+	var httpErr *fgerrors.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected HTTPError 400, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected no retry for 400, got %d attempts", calls.Load())
+	}
+}
+
+func TestWebhookHandler_RetriesExhausted(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	hook := event.NewWebhookHandler(server.URL, event.WithWebhookRetry(fastWebhookRetry))
+
+	_, err := hook.Handle(context.Background(), event.NewAny("test.event", "test", "t1", nil))
+	if err == nil {
+		t.Fatal("expected error after retries exhausted")
+	}
+	if calls.Load() != int32(fastWebhookRetry.MaxAttempts) {
+		t.Errorf("expected %d attempts, got %d", fastWebhookRetry.MaxAttempts, calls.Load())
+	}
+}
+
+// TestWebhookHandler_RouterDoesNotRetry tests that a failing endpoint
+// receives only the webhook's own attempts when routed with default retries.
+func TestWebhookHandler_RouterDoesNotRetry(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var routeErr error
+	router := event.NewRouter(event.RouterConfig{
+		OnError: func(evt event.Event, handler string, err error) { routeErr = err },
+	})
+	router.Register(event.NewWebhookHandler(server.URL, event.WithWebhookRetry(fastWebhookRetry)))
+
+	if _, err := router.Route(context.Background(), event.NewAny("test.event", "test", "t1", nil)); err != nil {
+		t.Fatalf("unexpected route error: %v", err)
+	}
+	if calls.Load() != int32(fastWebhookRetry.MaxAttempts) {
+		t.Errorf("expected %d attempts, got %d", fastWebhookRetry.MaxAttempts, calls.Load())
+	}
+	if routeErr == nil || fgerrors.IsRetryable(routeErr) {
+		t.Errorf("expected a permanent handler error, got %v", routeErr)
+	}
+}
+
+func TestWebhookHandler_Handles(t *testing.T) {
+	hook := event.NewWebhookHandler("http://example.invalid",
+		event.WithWebhookEventTypes("a", "b"))
+
+	if got := hook.Handles(); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("expected [a b], got %v", got)
+	}
+	if got := event.NewWebhookHandler("http://example.invalid").Handles(); len(got) != 0 {
+		t.Errorf("expected all events by default, got %v", got)
+	}
+}
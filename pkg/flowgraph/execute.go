@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/randalmurphal/flowgraph/pkg/flowgraph/checkpoint"
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
 	"github.com/randalmurphal/flowgraph/pkg/flowgraph/observability"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -37,7 +38,7 @@ const MaxCheckpointSize = 100 * 1024 * 1024 // 100MB
 //	if err != nil {
 //	    // result contains state at point of failure
 //	}
-func (cg *CompiledGraph[S]) Run(ctx Context, state S, opts ...RunOption) (result S, runErr error) {
+func (cg *CompiledGraph[S]) Run(ctx Context, state S, opts ...RunOption) (S, error) {
 	if ctx == nil {
 		return state, ErrNilContext
 	}
@@ -51,12 +52,26 @@ func (cg *CompiledGraph[S]) Run(ctx Context, state S, opts ...RunOption) (result
 	if cfg.checkpointStore != nil && cfg.runID == "" {
 		return state, ErrRunIDRequired
 	}
+	if cfg.suspendFraction > 0 && cfg.checkpointStore == nil {
+		return state, ErrSuspendRequiresCheckpointing
+	}
 
+	if cfg.runRetry != nil {
+		return cg.runWithRetry(ctx, state, cfg)
+	}
+	return cg.run(ctx, state, cfg)
+}
+
+// run executes one attempt of Run with validated configuration.
+func (cg *CompiledGraph[S]) run(ctx Context, state S, cfg runConfig) (result S, runErr error) {
 	// Get run ID for observability (from config or context)
 	runID := cfg.runID
 	if runID == "" {
 		runID = ctx.RunID()
 	}
+	ctx = bindRun(ctx, runID, cfg.runBudgetUSD)
+	cfg.live = registerRun(runID)
+	defer cfg.live.unregister()
 
 	// Start timing
 	startTime := time.Now()
@@ -76,7 +91,15 @@ func (cg *CompiledGraph[S]) Run(ctx Context, state S, opts ...RunOption) (result
 
 	// Execute the graph
 	var nodeCount int
-	result, nodeCount, runErr = cg.runFromWithObservability(execCtx, ctx, state, cg.entryPoint, &cfg)
+	entry, runErr := cg.selectEntry(state)
+	if runErr == nil && cfg.initialCheckpoint && cfg.checkpointStore != nil {
+		_, runErr = cg.saveCheckpointWithObservability(ctx, &cfg, START, "", state, entry)
+	}
+	if runErr == nil {
+		result, nodeCount, runErr = cg.runFromWithObservability(execCtx, ctx, state, entry, &cfg)
+	} else {
+		result = state
+	}
 
 	// Calculate duration
 	duration := time.Since(startTime)
@@ -89,12 +112,22 @@ func (cg *CompiledGraph[S]) Run(ctx Context, state S, opts ...RunOption) (result
 	if runErr != nil {
 		// Get last node from error if available
 		lastNode := ""
-		if nodeErr, ok := runErr.(*NodeError); ok {
+		failure := runErr
+		if compErr, ok := runErr.(*CompensationError); ok {
+			failure = compErr.Err
+		}
+		if nodeErr, ok := failure.(*NodeError); ok {
 			lastNode = nodeErr.NodeID
-		} else if maxErr, ok := runErr.(*MaxIterationsError); ok {
+		} else if maxErr, ok := failure.(*MaxIterationsError); ok {
 			lastNode = maxErr.LastNodeID
-		} else if cancelErr, ok := runErr.(*CancellationError); ok {
+		} else if cancelErr, ok := failure.(*CancellationError); ok {
 			lastNode = cancelErr.NodeID
+		} else if suspendErr, ok := failure.(*SuspendedError); ok {
+			lastNode = suspendErr.NodeID
+		} else if interruptErr, ok := failure.(*InterruptError); ok {
+			lastNode = interruptErr.NodeID
+		} else if budgetErr, ok := failure.(*BudgetError); ok {
+			lastNode = budgetErr.NodeID
 		}
 		observability.LogRunError(cfg.logger, runID, runErr, durationMs, lastNode)
 	} else {
@@ -114,13 +147,29 @@ func (cg *CompiledGraph[S]) runFrom(ctx Context, state S, startNode string, cfg
 // runFromWithObservability executes the graph with full observability.
 // tracingCtx carries span context; fgCtx is the flowgraph Context.
 // Returns the final state, node count, and any error.
-func (cg *CompiledGraph[S]) runFromWithObservability(tracingCtx context.Context, fgCtx Context, state S, startNode string, cfg *runConfig) (S, int, error) {
+func (cg *CompiledGraph[S]) runFromWithObservability(tracingCtx context.Context, fgCtx Context, state S, startNode string, cfg *runConfig) (_ S, _ int, runErr error) {
+	if cfg.graphCompensation {
+		cfg.compensationLog = &compensationLog{}
+		defer func() {
+			if runErr != nil {
+				runErr = compensate(fgCtx, cfg.compensationLog, runErr)
+			}
+		}()
+	}
+
 	current := startNode
 	iterations := 0
 	prevNode := ""
 	nodeCount := 0
+	budget := newRunBudget(cfg)
+	checkpointed := false       // whether prevNode's result was just checkpointed
+	sinceCheckpoint := 0        // nodes completed since the last checkpoint
+	var joinScratch *Scratchpad // scratchpad of the fork/join whose join node is current
+	var lastErr error           // error that routed execution to current via an error edge
 
 	for current != END {
+		cfg.live.update(current, state)
+
 		iterations++
 		if iterations > cfg.maxIterations {
 			return state, nodeCount, &MaxIterationsError{
@@ -142,21 +191,49 @@ func (cg *CompiledGraph[S]) runFromWithObservability(tracingCtx context.Context,
 		default:
 		}
 
+		// Suspend or stop if a budget has been used up
+		if err := budget.check(current, state, nodeCount, checkpointed); err != nil {
+			return state, nodeCount, err
+		}
+		checkpointed = false
+
+		// A join node, and its router, see its fork/join's scratchpad
+		nodeCtx := fgCtx
+		if joinScratch != nil {
+			nodeCtx = withScratchpad(fgCtx, joinScratch)
+			joinScratch = nil
+		}
+		if lastErr != nil {
+			nodeCtx = withLastError(nodeCtx, lastErr)
+			lastErr = nil
+		}
+
 		// Check if this is a fork node - handle parallel execution
 		if fork := cg.GetForkNode(current); fork != nil {
 			// Execute the fork node itself first
+			input := state
 			var nodeErr error
-			state, nodeErr = cg.executeNode(fgCtx, current, state)
+			var nodeTrace *nodeTrace
+			state, nodeTrace, nodeErr = cg.executeTracedNode(nodeCtx, current, state, cfg)
 			if nodeErr != nil {
-				return state, nodeCount, nodeErr
+				next, final, err := cg.handleNodeFailure(fgCtx, cfg, nodeTrace, current, prevNode, input, state, nodeErr)
+				if err != nil {
+					return final, nodeCount, err
+				}
+				lastErr = nodeErr
+				prevNode = current
+				current = next
+				continue
 			}
+			nodeTrace.end(fork.JoinNodeID, false, nil)
+			cg.recordCompleted(cfg, current, state)
 			nodeCount++
 
 			// Now execute branches in parallel
 			var mergedState S
 			var joinNode string
 			var forkErr error
-			mergedState, joinNode, forkErr = cg.executeForkJoin(fgCtx, fork, state, cfg)
+			mergedState, joinNode, joinScratch, forkErr = cg.executeForkJoin(fgCtx, fork, state, cfg)
 			if forkErr != nil {
 				return state, nodeCount, forkErr
 			}
@@ -167,6 +244,45 @@ func (cg *CompiledGraph[S]) runFromWithObservability(tracingCtx context.Context,
 			continue
 		}
 
+		// Dynamic fan-out: execute the node, then its runtime-selected branches
+		if fanOut, ok := cg.fanOuts[current]; ok {
+			input := state
+			var nodeErr error
+			var nodeTrace *nodeTrace
+			state, nodeTrace, nodeErr = cg.executeTracedNode(nodeCtx, current, state, cfg)
+			if nodeErr != nil {
+				next, final, err := cg.handleNodeFailure(fgCtx, cfg, nodeTrace, current, prevNode, input, state, nodeErr)
+				if err != nil {
+					return final, nodeCount, err
+				}
+				lastErr = nodeErr
+				prevNode = current
+				current = next
+				continue
+			}
+			nodeTrace.end(cg.edges[current][0], false, nil)
+			cg.recordCompleted(cfg, current, state)
+			nodeCount++
+
+			fork, fanOutErr := cg.resolveFanOut(nodeCtx, current, fanOut, state)
+			if fanOutErr != nil {
+				return state, nodeCount, fanOutErr
+			}
+
+			if len(fork.Branches) > 0 {
+				mergedState, _, scratch, forkErr := cg.executeForkJoin(fgCtx, fork, state, cfg)
+				if forkErr != nil {
+					return state, nodeCount, forkErr
+				}
+				state = mergedState
+				joinScratch = scratch
+			}
+
+			prevNode = current
+			current = fork.JoinNodeID
+			continue
+		}
+
 		// Log node start
 		observability.LogNodeStart(cfg.logger, current)
 
@@ -181,8 +297,10 @@ func (cg *CompiledGraph[S]) runFromWithObservability(tracingCtx context.Context,
 		nodeStart := time.Now()
 
 		// Execute the node
+		input := state
 		var nodeErr error
-		state, nodeErr = cg.executeNode(fgCtx, current, state)
+		var nodeTrace *nodeTrace
+		state, nodeTrace, nodeErr = cg.executeTracedNode(nodeCtx, current, state, cfg)
 
 		// Calculate duration
 		nodeDuration := time.Since(nodeStart)
@@ -196,24 +314,38 @@ func (cg *CompiledGraph[S]) runFromWithObservability(tracingCtx context.Context,
 			cfg.spans.EndSpanWithError(nodeSpan, nodeErr)
 		}
 
-		// Log node completion or error
+		// Log node completion or handle its failure
 		if nodeErr != nil {
-			observability.LogNodeError(cfg.logger, current, nodeErr)
-			return state, nodeCount, nodeErr
+			next, final, err := cg.handleNodeFailure(fgCtx, cfg, nodeTrace, current, prevNode, input, state, nodeErr)
+			if err != nil {
+				return final, nodeCount, err
+			}
+			lastErr = nodeErr
+			prevNode = current
+			current = next
+			continue
 		}
 		observability.LogNodeComplete(cfg.logger, current, nodeDurationMs)
+		cg.recordCompleted(cfg, current, state)
 		nodeCount++
 
 		// Determine next node
-		next, err := cg.nextNode(fgCtx, state, current)
+		next, err := cg.nextNode(nodeCtx, state, current, cfg)
+		nodeTrace.end(next, cg.isConditional[current], err)
 		if err != nil {
 			return state, nodeCount, err
 		}
 
-		// Checkpoint after successful node execution
+		// Checkpoint after successful node execution, every interval nodes
 		if cfg.checkpointStore != nil {
-			if err := cg.saveCheckpointWithObservability(fgCtx, cfg, current, prevNode, state, next); err != nil {
-				return state, nodeCount, err
+			sinceCheckpoint++
+			if next == END || sinceCheckpoint >= cfg.checkpointInterval {
+				saved, err := cg.saveCheckpointWithObservability(fgCtx, cfg, current, prevNode, state, next)
+				if err != nil {
+					return state, nodeCount, err
+				}
+				checkpointed = saved
+				sinceCheckpoint = 0
 			}
 		}
 
@@ -224,40 +356,93 @@ func (cg *CompiledGraph[S]) runFromWithObservability(tracingCtx context.Context,
 	return state, nodeCount, nil
 }
 
+// executeTracedNode executes a node under a node trace and checks the size
+// of its result.
+func (cg *CompiledGraph[S]) executeTracedNode(ctx Context, nodeID string, state S, cfg *runConfig) (S, *nodeTrace, error) {
+	tracedCtx, nodeTrace := startNodeTrace(cfg, ctx, nodeID)
+	result, err := cg.executeNodeWithTimeout(tracedCtx, nodeID, state, cfg)
+	if err == nil {
+		err = checkStateSize(cfg, nodeID, result)
+	}
+	return result, nodeTrace, err
+}
+
+// handleNodeFailure decides what follows a failed node and ends its trace.
+// An interrupt pauses the run with the node's input state; otherwise the
+// error is logged and, if the node has an error edge that catches it,
+// execution continues at the returned node. Any other failure returns the
+// node's output state with the error that ends the run.
+func (cg *CompiledGraph[S]) handleNodeFailure(ctx Context, cfg *runConfig, nodeTrace *nodeTrace, nodeID, prevNodeID string, input, output S, nodeErr error) (next string, final S, err error) {
+	if reason, ok := interruptReason(nodeErr); ok {
+		nodeTrace.end("", false, nodeErr)
+		return "", input, cg.interrupt(ctx, cfg, nodeID, prevNodeID, input, reason)
+	}
+
+	observability.LogNodeError(cfg.logger, nodeID, nodeErr)
+	if to, ok := cg.errorEdgeTarget(nodeID, nodeErr, cfg); ok {
+		nodeTrace.end(to, false, nodeErr)
+		return to, output, nil
+	}
+	nodeTrace.end("", false, nodeErr)
+	return "", output, nodeErr
+}
+
 // saveCheckpointWithObservability persists the current state with observability.
-func (cg *CompiledGraph[S]) saveCheckpointWithObservability(ctx Context, cfg *runConfig, nodeID, prevNodeID string, state S, nextNode string) error {
+// Reports whether the checkpoint was saved; non-fatal failures are logged
+// and return false with a nil error.
+func (cg *CompiledGraph[S]) saveCheckpointWithObservability(ctx Context, cfg *runConfig, nodeID, prevNodeID string, state S, nextNode string) (bool, error) {
 	// Serialize state
-	stateBytes, err := json.Marshal(state)
+	codec := cfg.checkpointCodec
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+	stateBytes, err := codec.Marshal(state)
 	if err != nil {
 		if cfg.checkpointFailureFatal {
-			return &CheckpointError{
+			return false, &CheckpointError{
 				NodeID: nodeID,
 				Op:     "serialize",
 				Err:    err,
 			}
 		}
 		observability.LogCheckpointError(cfg.logger, nodeID, "serialize", err)
-		return nil
+		return false, nil
+	}
+
+	// Compress and encrypt if configured
+	stateBytes, op, err := encodeCheckpointState(cfg, codec, stateBytes)
+	if err != nil {
+		if cfg.checkpointFailureFatal {
+			return false, &CheckpointError{
+				NodeID: nodeID,
+				Op:     op,
+				Err:    err,
+			}
+		}
+		observability.LogCheckpointError(cfg.logger, nodeID, op, err)
+		return false, nil
 	}
 
 	// Check size limit to prevent memory exhaustion
 	if len(stateBytes) > MaxCheckpointSize {
 		err := fmt.Errorf("checkpoint size %d exceeds limit %d", len(stateBytes), MaxCheckpointSize)
 		if cfg.checkpointFailureFatal {
-			return &CheckpointError{
+			return false, &CheckpointError{
 				NodeID: nodeID,
 				Op:     "size_check",
 				Err:    err,
 			}
 		}
 		observability.LogCheckpointError(cfg.logger, nodeID, "size_check", err)
-		return nil
+		return false, nil
 	}
 
 	// Create checkpoint
 	cfg.sequence++
 	cp := checkpoint.New(cfg.runID, nodeID, cfg.sequence, stateBytes, nextNode).
-		WithPrevNode(prevNodeID)
+		WithPrevNode(prevNodeID).
+		WithMetadata(cfg.checkpointMetadata).
+		WithCodec(codec.Name())
 
 	if ec, ok := ctx.(*executionContext); ok {
 		cp = cp.WithAttempt(ec.attempt)
@@ -266,27 +451,27 @@ func (cg *CompiledGraph[S]) saveCheckpointWithObservability(ctx Context, cfg *ru
 	data, err := cp.Marshal()
 	if err != nil {
 		if cfg.checkpointFailureFatal {
-			return &CheckpointError{
+			return false, &CheckpointError{
 				NodeID: nodeID,
 				Op:     "marshal",
 				Err:    err,
 			}
 		}
 		observability.LogCheckpointError(cfg.logger, nodeID, "marshal", err)
-		return nil
+		return false, nil
 	}
 
 	// Save to store
 	if err := cfg.checkpointStore.Save(cfg.runID, nodeID, data); err != nil {
 		if cfg.checkpointFailureFatal {
-			return &CheckpointError{
+			return false, &CheckpointError{
 				NodeID: nodeID,
 				Op:     "save",
 				Err:    err,
 			}
 		}
 		observability.LogCheckpointError(cfg.logger, nodeID, "save", err)
-		return nil
+		return false, nil
 	}
 
 	// Log and record successful checkpoint
@@ -294,12 +479,97 @@ func (cg *CompiledGraph[S]) saveCheckpointWithObservability(ctx Context, cfg *ru
 	observability.LogCheckpoint(cfg.logger, nodeID, sizeBytes)
 	cfg.metrics.RecordCheckpoint(ctx, nodeID, int64(sizeBytes))
 
+	return true, nil
+}
+
+// checkStateSize enforces the WithMaxStateSize limit after a node completes.
+func checkStateSize[S any](cfg *runConfig, nodeID string, state S) error {
+	if cfg.maxStateSize <= 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return &NodeError{
+			NodeID: nodeID,
+			Op:     "size_check",
+			Err:    fmt.Errorf("%w: %w", ErrSerializeState, err),
+		}
+	}
+
+	if len(data) > cfg.maxStateSize {
+		return &StateSizeError{
+			NodeID: nodeID,
+			Size:   len(data),
+			Limit:  cfg.maxStateSize,
+		}
+	}
 	return nil
 }
 
-// executeNode executes a single node with panic recovery.
+// executeNodeWithTimeout executes a node, bounded by its configured timeout.
+// Without a timeout for the node, this is equivalent to executeNode.
+//
+// On timeout the node's goroutine is abandoned: its context is cancelled
+// and any result it later produces is discarded.
+func (cg *CompiledGraph[S]) executeNodeWithTimeout(ctx Context, nodeID string, state S, cfg *runConfig) (S, error) {
+	timeout, ok := cfg.nodeTimeouts[nodeID]
+	if !ok {
+		return cg.executeNode(ctx, nodeID, state, cfg)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	nodeCtx := ctx
+	if ec, ok := ctx.(*executionContext); ok {
+		nodeCtx = ec.withContext(timeoutCtx)
+	}
+
+	type outcome struct {
+		state S
+		err   error
+	}
+	done := make(chan outcome, 1) // Buffered so an abandoned node never blocks
+
+	go func() {
+		result, err := cg.executeNode(nodeCtx, nodeID, state, cfg)
+		done <- outcome{state: result, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		return out.state, out.err
+	case <-timeoutCtx.Done():
+		// Parent cancellation takes precedence over the node timeout
+		if ctx.Err() != nil {
+			return state, &CancellationError{
+				NodeID:       nodeID,
+				State:        state,
+				Cause:        ctx.Err(),
+				WasExecuting: true,
+			}
+		}
+		return state, &NodeError{
+			NodeID: nodeID,
+			Op:     "timeout",
+			Err:    fmt.Errorf("exceeded %s: %w", timeout, context.DeadlineExceeded),
+		}
+	}
+}
+
+// executeNode executes a single node, answering from its node cache if
+// one is configured with WithNodeCache.
+func (cg *CompiledGraph[S]) executeNode(ctx Context, nodeID string, state S, cfg *runConfig) (S, error) {
+	if cache, ok := cfg.nodeCaches[nodeID]; ok {
+		return cg.executeCachedNode(ctx, nodeID, state, cfg, cache)
+	}
+	return cg.invokeNode(ctx, nodeID, state, cfg)
+}
+
+// invokeNode executes a single node with panic recovery.
 // Returns the new state and any error (including wrapped panics).
-func (cg *CompiledGraph[S]) executeNode(ctx Context, nodeID string, state S) (result S, err error) {
+func (cg *CompiledGraph[S]) invokeNode(ctx Context, nodeID string, state S, cfg *runConfig) (result S, err error) {
 	fn, exists := cg.getNode(nodeID)
 	if !exists {
 		// This shouldn't happen if compilation was successful
@@ -310,6 +580,28 @@ func (cg *CompiledGraph[S]) executeNode(ctx Context, nodeID string, state S) (re
 		}
 	}
 
+	if factoryKey, dynamic := cg.dynamicNodes[nodeID]; dynamic {
+		fn, err = resolveDynamicNode[S](cfg.nodeRegistry, factoryKey)
+		if err != nil {
+			return state, &NodeError{
+				NodeID: nodeID,
+				Op:     "resolve",
+				Err:    err,
+			}
+		}
+	}
+
+	op := "execute"
+	if sub, ok := cg.subgraphs[nodeID]; ok {
+		fn = subgraphNode(sub, nodeID, cfg)
+		op = "subgraph"
+	}
+
+	if fault, ok := cfg.faults[nodeID]; ok {
+		fn = withFault(fn, fault)
+	}
+	fn = ChainNodeMiddleware(fn, cg.middleware...)
+
 	// Create node-specific context with enriched logger
 	nodeCtx := ctx
 	if ec, ok := ctx.(*executionContext); ok {
@@ -328,11 +620,15 @@ func (cg *CompiledGraph[S]) executeNode(ctx Context, nodeID string, state S) (re
 		}
 	}()
 
+	if retry := cg.getNodeConfig(nodeID).retry; retry != nil {
+		return cg.executeNodeWithRetry(ctx, nodeID, fn, state, *retry)
+	}
+
 	result, err = fn(nodeCtx, state)
 	if err != nil {
 		return result, &NodeError{
 			NodeID: nodeID,
-			Op:     "execute",
+			Op:     op,
 			Err:    err,
 		}
 	}
@@ -340,9 +636,75 @@ func (cg *CompiledGraph[S]) executeNode(ctx Context, nodeID string, state S) (re
 	return result, nil
 }
 
+// executeNodeWithRetry runs a node under its retry policy.
+// Each attempt receives a context whose Attempt() reflects the attempt number.
+// On failure, the state from the last attempt is returned.
+func (cg *CompiledGraph[S]) executeNodeWithRetry(ctx Context, nodeID string, fn NodeFunc[S], state S, retry fgerrors.RetryConfig) (S, error) {
+	attempt := 0
+	last := state
+	effects := sideEffectsFrom(ctx)
+
+	res := fgerrors.WithRetryContext(ctx, retry, func(context.Context) (S, error) {
+		attempt++
+		queued := effects.len()
+		recordAttempt(ctx, attempt)
+
+		attemptCtx := ctx
+		if ec, ok := ctx.(*executionContext); ok {
+			attemptCtx = ec.withAttempt(attempt).withNodeID(nodeID)
+		}
+
+		out, err := fn(attemptCtx, state)
+		if err != nil {
+			last = out
+			effects.truncate(queued) // Discard side effects of the failed attempt
+			attemptCtx.Logger().Warn("node attempt failed", "error", err)
+		}
+		return out, err
+	})
+
+	if res.Err != nil {
+		return last, &NodeError{
+			NodeID: nodeID,
+			Op:     "execute",
+			Err:    res.Err,
+		}
+	}
+
+	return res.Value, nil
+}
+
+// selectEntry returns the node to start execution from.
+// Evaluates the entry selector against the initial state if one is set.
+func (cg *CompiledGraph[S]) selectEntry(state S) (entry string, err error) {
+	if cg.entrySelector == nil {
+		return cg.entryPoint, nil
+	}
+
+	// Panic recovery for selector functions
+	defer func() {
+		if r := recover(); r != nil {
+			entry = ""
+			err = &PanicError{
+				NodeID: "",
+				Value:  r,
+				Stack:  string(debug.Stack()),
+			}
+		}
+	}()
+
+	entry = cg.entrySelector(state)
+	if entry == "" || entry == END || !cg.HasNode(entry) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidEntrySelection, entry)
+	}
+
+	return entry, nil
+}
+
 // nextNode determines the next node to execute.
 // Checks conditional edges first, then simple edges.
-func (cg *CompiledGraph[S]) nextNode(ctx Context, state S, current string) (next string, err error) {
+// Conditional routing decisions are recorded if a BranchRecorder is set.
+func (cg *CompiledGraph[S]) nextNode(ctx Context, state S, current string, cfg *runConfig) (next string, err error) {
 	// Check for conditional edge first
 	if router, exists := cg.getRouter(current); exists {
 		// Create node-specific context for the router
@@ -384,6 +746,9 @@ func (cg *CompiledGraph[S]) nextNode(ctx Context, state S, current string) (next
 			}
 		}
 
+		if cfg.branchRecorder != nil {
+			cfg.branchRecorder.record(current, next)
+		}
 		return next, nil
 	}
 
@@ -11,13 +11,14 @@ import (
 // It clones state for each branch, executes branches in goroutines,
 // waits for completion, and merges the results.
 //
-// Returns the merged state and the join node to continue from.
+// Returns the merged state, the join node to continue from, and the
+// branches' scratchpad for the join node to read.
 func (cg *CompiledGraph[S]) executeForkJoin(
 	ctx Context,
 	forkNode *ForkNode,
 	state S,
 	cfg *runConfig,
-) (mergedState S, joinNode string, err error) {
+) (mergedState S, joinNode string, scratch *Scratchpad, err error) {
 	startTime := time.Now()
 	hook := cg.getBranchHook()
 	fjConfig := cg.getForkJoinConfig()
@@ -37,12 +38,23 @@ func (cg *CompiledGraph[S]) executeForkJoin(
 		defer cancel()
 	}
 
+	// Branches share one scratchpad, which the join also sees
+	scratch = newScratchpad()
+	ctx = withScratchpad(ctx, scratch)
+
+	// A fork nested in a branch frees that branch's run-wide slot for its
+	// own branches while it waits for them
+	if slot := heldParallelSlot(ctx); slot != nil {
+		slot.release()
+		defer slot.reacquire()
+	}
+
 	// Clone state for each branch
 	branchStates := make(map[string]S)
 	for _, branchID := range forkNode.Branches {
 		cloned, cloneErr := cloneState(state, branchID)
 		if cloneErr != nil {
-			return state, "", fmt.Errorf("fork node %s: clone state for branch %s: %w",
+			return state, "", nil, fmt.Errorf("fork node %s: clone state for branch %s: %w",
 				forkNode.NodeID, branchID, cloneErr)
 		}
 
@@ -51,7 +63,7 @@ func (cg *CompiledGraph[S]) executeForkJoin(
 			var hookErr error
 			cloned, hookErr = hook.OnFork(ctx, branchID, cloned)
 			if hookErr != nil {
-				return state, "", fmt.Errorf("fork node %s: OnFork hook for branch %s: %w",
+				return state, "", nil, fmt.Errorf("fork node %s: OnFork hook for branch %s: %w",
 					forkNode.NodeID, branchID, hookErr)
 			}
 		}
@@ -59,6 +71,16 @@ func (cg *CompiledGraph[S]) executeForkJoin(
 		branchStates[branchID] = cloned
 	}
 
+	// With ordered side effects, each branch queues its side effects for
+	// the join instead of running them
+	var sideEffects map[string]*sideEffectQueue
+	if fjConfig.OrderedSideEffects {
+		sideEffects = make(map[string]*sideEffectQueue, len(forkNode.Branches))
+		for _, branchID := range forkNode.Branches {
+			sideEffects[branchID] = &sideEffectQueue{}
+		}
+	}
+
 	// Execute branches in parallel
 	results := make(chan BranchResult[S], len(forkNode.Branches))
 	var wg sync.WaitGroup
@@ -82,8 +104,27 @@ func (cg *CompiledGraph[S]) executeForkJoin(
 				}
 			}
 
-			// Execute this branch (pass timeoutCtx for tracing, ctx for flowgraph context)
-			result := cg.executeBranch(timeoutCtx, ctx, bID, bState, forkNode.JoinNodeID, cfg)
+			branchCtx := ctx
+			if q, ok := sideEffects[bID]; ok {
+				branchCtx = withSideEffectQueue(ctx, q)
+			}
+
+			// Acquire a run-wide slot if parallelism is limited
+			if cfg.parallelism != nil {
+				slot, slotErr := cfg.parallelism.acquire(timeoutCtx)
+				if slotErr != nil {
+					results <- BranchResult[S]{
+						BranchID: bID,
+						Error:    slotErr,
+					}
+					return
+				}
+				defer slot.release()
+				branchCtx = withParallelSlot(branchCtx, slot)
+			}
+
+			// Execute this branch (pass timeoutCtx for tracing, branchCtx for flowgraph context)
+			result := cg.executeBranch(timeoutCtx, branchCtx, bID, bState, forkNode.JoinNodeID, cfg)
 			results <- result
 
 			// Notify hook on error
@@ -100,16 +141,22 @@ func (cg *CompiledGraph[S]) executeForkJoin(
 	}()
 
 	// Collect results
-	branchResults := make([]BranchResult[S], 0, len(forkNode.Branches))
 	var firstError error
+	var firstFailed string
 	successfulStates := make(map[string]S)
 
 	for result := range results {
-		branchResults = append(branchResults, result)
-
 		if result.Error != nil {
 			if firstError == nil {
 				firstError = result.Error
+				firstFailed = result.BranchID
+			}
+			if fjConfig.ContinueOnError {
+				ctx.Logger().Warn("fork/join branch failed, continuing",
+					"fork_node", forkNode.NodeID,
+					"branch", result.BranchID,
+					"error", result.Error)
+				delete(sideEffects, result.BranchID)
 			}
 			// If fail-fast, we could cancel here, but we've already started all branches
 			// The context cancellation from timeout handles this
@@ -118,19 +165,26 @@ func (cg *CompiledGraph[S]) executeForkJoin(
 		}
 	}
 
-	// Check for errors
-	if firstError != nil {
-		return state, "", &ForkJoinError{
+	// Check for errors; with ContinueOnError, only if no branch succeeded
+	if firstError != nil && (!fjConfig.ContinueOnError || len(successfulStates) == 0) {
+		return state, "", nil, &ForkJoinError{
 			ForkNodeID: forkNode.NodeID,
-			BranchID:   branchResults[0].BranchID, // First failed branch
+			BranchID:   firstFailed,
 			Err:        firstError,
 		}
 	}
 
+	// Commit queued side effects in stable branch order
+	if sideEffects != nil {
+		if commitErr := commitSideEffects(ctx, forkNode.NodeID, sideEffects); commitErr != nil {
+			return state, "", nil, commitErr
+		}
+	}
+
 	// Call OnJoin hook if available
 	if hook != nil {
 		if joinErr := hook.OnJoin(ctx, successfulStates); joinErr != nil {
-			return state, "", fmt.Errorf("fork node %s: OnJoin hook: %w",
+			return state, "", nil, fmt.Errorf("fork node %s: OnJoin hook: %w",
 				forkNode.NodeID, joinErr)
 		}
 	}
@@ -146,7 +200,7 @@ func (cg *CompiledGraph[S]) executeForkJoin(
 		"branches", len(forkNode.Branches),
 		"duration_ms", duration.Milliseconds())
 
-	return mergedState, forkNode.JoinNodeID, nil
+	return mergedState, forkNode.JoinNodeID, scratch, nil
 }
 
 // executeBranch executes a single branch from its start node until it reaches the join node.
@@ -194,7 +248,10 @@ func (cg *CompiledGraph[S]) executeBranch(
 
 		// Execute the node
 		var nodeErr error
-		state, nodeErr = cg.executeNode(fgCtx, current, state)
+		state, nodeErr = cg.executeNodeWithTimeout(fgCtx, current, state, cfg)
+		if nodeErr == nil {
+			nodeErr = checkStateSize(cfg, current, state)
+		}
 		if nodeErr != nil {
 			return BranchResult[S]{
 				BranchID: branchID,
@@ -203,9 +260,10 @@ func (cg *CompiledGraph[S]) executeBranch(
 				Duration: time.Since(startTime),
 			}
 		}
+		cg.recordCompleted(cfg, current, state)
 
 		// Determine next node
-		next, routeErr := cg.nextNode(fgCtx, state, current)
+		next, routeErr := cg.nextNode(fgCtx, state, current, cfg)
 		if routeErr != nil {
 			return BranchResult[S]{
 				BranchID: branchID,
@@ -239,3 +297,65 @@ func (e *ForkJoinError) Error() string {
 func (e *ForkJoinError) Unwrap() error {
 	return e.Err
 }
+
+// parallelismLimit bounds the branches executing at once across a run.
+type parallelismLimit struct {
+	sem chan struct{}
+}
+
+func newParallelismLimit(n int) *parallelismLimit {
+	return &parallelismLimit{sem: make(chan struct{}, n)}
+}
+
+// acquire waits for a free slot until ctx is done.
+func (l *parallelismLimit) acquire(ctx context.Context) (*parallelSlot, error) {
+	select {
+	case l.sem <- struct{}{}:
+		return &parallelSlot{limit: l, held: true}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// parallelSlot is the run-wide slot of one branch. It is only used by the
+// branch's goroutine.
+type parallelSlot struct {
+	limit *parallelismLimit
+	held  bool
+}
+
+// release frees the slot if it is held.
+func (s *parallelSlot) release() {
+	if s.held {
+		s.held = false
+		<-s.limit.sem
+	}
+}
+
+// reacquire takes the slot back after a nested fork, waiting for room.
+func (s *parallelSlot) reacquire() {
+	if !s.held {
+		s.limit.sem <- struct{}{}
+		s.held = true
+	}
+}
+
+type parallelSlotKey struct{}
+
+// withParallelSlot records the branch's slot in ctx.
+func withParallelSlot(ctx Context, slot *parallelSlot) Context {
+	ec, ok := ctx.(*executionContext)
+	if !ok {
+		return ctx
+	}
+	return ec.withContext(context.WithValue(ec.Context, parallelSlotKey{}, slot))
+}
+
+// heldParallelSlot returns the slot of the branch ctx runs in, or nil.
+func heldParallelSlot(ctx Context) *parallelSlot {
+	slot, _ := ctx.Value(parallelSlotKey{}).(*parallelSlot)
+	if slot == nil || !slot.held {
+		return nil
+	}
+	return slot
+}
@@ -3,6 +3,8 @@ package flowgraph
 import (
 	"context"
 	"errors"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -165,6 +167,86 @@ func TestRun_ConditionalEdge_ToEND(t *testing.T) {
 	assert.Equal(t, []string{"check"}, executed) // Should stop at check
 }
 
+// TestRun_EntrySelector tests that different initial states start at different nodes.
+func TestRun_EntrySelector(t *testing.T) {
+	selector := func(s State) string {
+		if s.Initial != "" {
+			return s.Initial
+		}
+		return "draft"
+	}
+
+	tests := []struct {
+		name     string
+		initial  string
+		expected []string
+	}{
+		{name: "default entry", initial: "", expected: []string{"draft", "review", "publish"}},
+		{name: "resume at review", initial: "review", expected: []string{"review", "publish"}},
+		{name: "resume at publish", initial: "publish", expected: []string{"publish"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var executed []string
+
+			graph := NewGraph[State]().
+				AddNode("draft", makeTrackingNode("draft", &executed)).
+				AddNode("review", makeTrackingNode("review", &executed)).
+				AddNode("publish", makeTrackingNode("publish", &executed)).
+				AddEdge("draft", "review").
+				AddEdge("review", "publish").
+				AddEdge("publish", END).
+				SetEntrySelector(selector)
+
+			compiled, err := graph.Compile()
+			require.NoError(t, err)
+
+			result, err := compiled.Run(testCtx(), State{Initial: tt.initial})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, executed)
+			assert.Equal(t, tt.expected, result.Progress)
+		})
+	}
+}
+
+// TestRun_EntrySelector_InvalidResult_Error tests selector results that are not existing nodes.
+func TestRun_EntrySelector_InvalidResult_Error(t *testing.T) {
+	for _, returned := range []string{"", END, "unknown"} {
+		t.Run(returned, func(t *testing.T) {
+			graph := NewGraph[State]().
+				AddNode("a", passthrough[State]).
+				AddEdge("a", END).
+				SetEntrySelector(func(s State) string { return returned })
+
+			compiled, err := graph.Compile()
+			require.NoError(t, err)
+
+			_, err = compiled.Run(testCtx(), State{})
+
+			assert.ErrorIs(t, err, ErrInvalidEntrySelection)
+		})
+	}
+}
+
+// TestRun_EntrySelector_Panics_Recovered tests selector panic recovery.
+func TestRun_EntrySelector_Panics_Recovered(t *testing.T) {
+	graph := NewGraph[State]().
+		AddNode("a", passthrough[State]).
+		AddEdge("a", END).
+		SetEntrySelector(func(s State) string { panic("selector exploded") })
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{})
+
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "selector exploded", panicErr.Value)
+}
+
 // TestRun_Loop tests looping behavior with conditional exit.
 func TestRun_Loop(t *testing.T) {
 	var iterations int
@@ -361,6 +443,90 @@ func TestRun_Timeout(t *testing.T) {
 	assert.Equal(t, 1, nodeCount, "Only first node should have executed")
 }
 
+// TestRun_NodeTimeout tests that a slow node is interrupted by its own timeout.
+func TestRun_NodeTimeout(t *testing.T) {
+	var executed []string
+	release := make(chan struct{})
+	defer close(release)
+
+	// Ignores ctx.Done() so the executor must abandon it
+	stuckNode := func(ctx Context, s State) (State, error) {
+		<-release
+		return s, nil
+	}
+
+	graph := NewGraph[State]().
+		AddNode("first", makeTrackingNode("first", &executed)).
+		AddNode("stuck", stuckNode).
+		AddNode("after", makeTrackingNode("after", &executed)).
+		AddEdge("first", "stuck").
+		AddEdge("stuck", "after").
+		AddEdge("after", END).
+		SetEntry("first")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	start := time.Now()
+	result, err := compiled.Run(testCtx(), State{},
+		WithNodeTimeout("stuck", 20*time.Millisecond))
+
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+
+	var nodeErr *NodeError
+	require.ErrorAs(t, err, &nodeErr)
+	assert.Equal(t, "stuck", nodeErr.NodeID)
+	assert.Equal(t, "timeout", nodeErr.Op)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []string{"first"}, executed)
+	assert.Equal(t, []string{"first"}, result.Progress) // State before the timed-out node
+}
+
+// TestRun_NodeTimeout_NodeSeesDeadline tests that the node context carries the deadline.
+func TestRun_NodeTimeout_NodeSeesDeadline(t *testing.T) {
+	var sawCancel atomic.Bool
+
+	cooperative := func(ctx Context, s State) (State, error) {
+		_, hasDeadline := ctx.Deadline()
+		if !hasDeadline {
+			return s, errors.New("expected deadline on node context")
+		}
+		<-ctx.Done()
+		sawCancel.Store(true)
+		return s, ctx.Err()
+	}
+
+	graph := NewGraph[State]().
+		AddNode("slow", cooperative).
+		AddEdge("slow", END).
+		SetEntry("slow")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{}, WithNodeTimeout("slow", 10*time.Millisecond))
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Eventually(t, sawCancel.Load, time.Second, time.Millisecond)
+}
+
+// TestRun_NodeTimeout_FastNodeUnaffected tests that nodes finishing in time succeed.
+func TestRun_NodeTimeout_FastNodeUnaffected(t *testing.T) {
+	graph := NewGraph[Counter]().
+		AddNode("inc", increment).
+		AddEdge("inc", END).
+		SetEntry("inc")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), Counter{}, WithNodeTimeout("inc", time.Second))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Value)
+}
+
 // TestRun_MaxIterations_PreventsInfiniteLoop tests max iterations limit.
 func TestRun_MaxIterations_PreventsInfiniteLoop(t *testing.T) {
 	loopNode := func(ctx Context, s State) (State, error) {
@@ -808,6 +974,13 @@ func (f *failingCheckpointStore) DeleteRun(runID string) error {
 	return nil
 }
 
+func (f *failingCheckpointStore) ListRuns() ([]string, error) {
+	if f.failOn == "list_runs" {
+		return nil, errors.New("simulated list runs failure")
+	}
+	return nil, nil
+}
+
 func (f *failingCheckpointStore) Close() error {
 	if f.failOn == "close" {
 		return errors.New("simulated close failure")
@@ -861,3 +1034,54 @@ func TestMaxCheckpointSize_Constant(t *testing.T) {
 	// MaxCheckpointSize should be 100MB
 	assert.Equal(t, 100*1024*1024, MaxCheckpointSize)
 }
+
+// TestRun_MaxStateSize_Exceeded tests that a node growing state past the limit aborts the run.
+func TestRun_MaxStateSize_Exceeded(t *testing.T) {
+	grow := func(ctx Context, s State) (State, error) {
+		s.Progress = append(s.Progress, strings.Repeat("x", 64))
+		return s, nil
+	}
+
+	graph := NewGraph[State]().
+		AddNode("grow", grow).
+		AddConditionalEdge("grow", func(ctx Context, s State) string {
+			if len(s.Progress) >= 100 {
+				return END
+			}
+			return "grow"
+		}).
+		SetEntry("grow")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), State{}, WithMaxStateSize(512))
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrStateTooLarge)
+
+	var sizeErr *StateSizeError
+	require.ErrorAs(t, err, &sizeErr)
+	assert.Equal(t, "grow", sizeErr.NodeID)
+	assert.Equal(t, 512, sizeErr.Limit)
+	assert.Greater(t, sizeErr.Size, 512)
+	assert.Less(t, len(result.Progress), 100, "run should stop before the loop finishes")
+}
+
+// TestRun_MaxStateSize_WithinLimit tests that a well-behaved graph is unaffected.
+func TestRun_MaxStateSize_WithinLimit(t *testing.T) {
+	graph := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddNode("b", increment).
+		AddEdge("a", "b").
+		AddEdge("b", END).
+		SetEntry("a")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), Counter{}, WithMaxStateSize(1024))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Value)
+}
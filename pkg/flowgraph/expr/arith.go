@@ -0,0 +1,86 @@
+package expr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// toNumber converts v to int64 or float64 for arithmetic.
+// Numeric strings are parsed; other types are rejected with ErrNonNumeric.
+func toNumber(v any) (any, error) {
+	switch val := v.(type) {
+	case int:
+		return int64(val), nil
+	case int32:
+		return int64(val), nil
+	case int64:
+		return val, nil
+	case float32:
+		return float64(val), nil
+	case float64:
+		return val, nil
+	case string:
+		var num json.Number
+		if err := json.Unmarshal([]byte(strings.TrimSpace(val)), &num); err == nil {
+			return parseNumber(num.String()), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %v (%T)", ErrNonNumeric, v, v)
+}
+
+// arithmetic applies +, -, *, or / to two values.
+// Integer operands produce an int64 result except for division, which
+// always produces float64.
+func arithmetic(op string, left, right any) (any, error) {
+	l, err := toNumber(left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := toNumber(right)
+	if err != nil {
+		return nil, err
+	}
+
+	li, lInt := l.(int64)
+	ri, rInt := r.(int64)
+	if lInt && rInt && op != "/" {
+		switch op {
+		case "+":
+			return li + ri, nil
+		case "-":
+			return li - ri, nil
+		case "*":
+			return li * ri, nil
+		}
+	}
+
+	lf, rf := ToFloat64(l), ToFloat64(r)
+	switch op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, ErrDivisionByZero
+		}
+		return lf / rf, nil
+	default:
+		return nil, fmt.Errorf("unknown arithmetic operator: %s", op)
+	}
+}
+
+// negate returns -v for a numeric value.
+func negate(v any) (any, error) {
+	n, err := toNumber(v)
+	if err != nil {
+		return nil, err
+	}
+	if i, ok := n.(int64); ok {
+		return -i, nil
+	}
+	return -n.(float64), nil
+}
@@ -0,0 +1,338 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AST is the parsed structure of an expression, for tooling such as
+// editors and linters that need more than the evaluated result. Create one
+// with Parse or Evaluator.Parse.
+//
+// An AST is read-only: nodes expose their parts through accessor methods
+// and are independent of any Program compiled from the same source.
+//
+// Example:
+//
+//	ast, err := expr.Parse("status == 'ready' and retries < 3")
+//	if err != nil {
+//	    return err
+//	}
+//	root := ast.Root().(*expr.Binary) // root.Op() == "and"
+type AST struct {
+	root Node // nil for an empty expression
+}
+
+// Node is an element of an AST. The concrete types are *Literal, *Ident,
+// *Unary, *Binary, and *List.
+type Node interface {
+	// Pos returns the byte offset of the node in the source. For unary and
+	// binary nodes this is the operator; for lists, the opening parenthesis.
+	Pos() int
+
+	// String renders the node in normalized form.
+	String() string
+
+	astNode()
+}
+
+// OpClass classifies the operator of a Binary node.
+type OpClass int
+
+const (
+	// OpLogical is "and" or "or".
+	OpLogical OpClass = iota
+	// OpComparison is a built-in comparison: ==, !=, <, >, <=, >=,
+	// contains, or in.
+	OpComparison
+	// OpArithmetic is +, -, *, or /.
+	OpArithmetic
+	// OpCustom is an operator registered with WithCustomOperator.
+	OpCustom
+)
+
+// String returns the class name.
+func (c OpClass) String() string {
+	switch c {
+	case OpLogical:
+		return "logical"
+	case OpComparison:
+		return "comparison"
+	case OpArithmetic:
+		return "arithmetic"
+	case OpCustom:
+		return "custom"
+	default:
+		return fmt.Sprintf("OpClass(%d)", int(c))
+	}
+}
+
+// Literal is a constant: a string, int64, float64, bool, or nil.
+type Literal struct {
+	value any
+	pos   int
+}
+
+// Ident is a variable reference, possibly dotted for nested access.
+type Ident struct {
+	name string
+	pos  int
+}
+
+// Unary applies "not" or "-" to its operand. A "!" in the source is
+// reported as "not".
+type Unary struct {
+	op      string
+	operand Node
+	pos     int
+}
+
+// Binary applies a logical, comparison, arithmetic, or custom operator.
+type Binary struct {
+	op          string
+	class       OpClass
+	left, right Node
+	pos         int
+}
+
+// List is a parenthesized list literal on the right side of "in".
+type List struct {
+	elems []Node
+	pos   int
+}
+
+// Parse parses source with the evaluator's custom operators into an AST.
+// Returns an error wrapping ErrSyntax if source cannot be parsed. An empty
+// source yields an AST with a nil root.
+func (e *Evaluator) Parse(source string) (*AST, error) {
+	if strings.TrimSpace(source) == "" {
+		return &AST{}, nil
+	}
+	n, err := parse(source, e.customOps)
+	if err != nil {
+		return nil, err
+	}
+	return &AST{root: toAST(n)}, nil
+}
+
+// Parse parses source using the default evaluator (no custom operators).
+func Parse(source string) (*AST, error) {
+	return New().Parse(source)
+}
+
+// Root returns the top-level node, or nil for an empty expression.
+func (a *AST) Root() Node {
+	return a.root
+}
+
+// String renders the expression in normalized form: single spaces around
+// binary operators, "not" for "!", single-quoted strings, and only the
+// parentheses precedence requires. Parsing the result yields an equivalent
+// AST.
+func (a *AST) String() string {
+	if a.root == nil {
+		return ""
+	}
+	return a.root.String()
+}
+
+// Value returns the constant.
+func (n *Literal) Value() any { return n.value }
+
+// Pos returns the offset of the literal.
+func (n *Literal) Pos() int { return n.pos }
+
+// String renders the literal.
+func (n *Literal) String() string {
+	switch v := n.value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return quote(v)
+	case float64:
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".e") {
+			s += ".0" // Keep it a float when re-parsed
+		}
+		return s
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Name returns the variable name.
+func (n *Ident) Name() string { return n.name }
+
+// Pos returns the offset of the identifier.
+func (n *Ident) Pos() int { return n.pos }
+
+// String returns the variable name.
+func (n *Ident) String() string { return n.name }
+
+// Op returns "not" or "-".
+func (n *Unary) Op() string { return n.op }
+
+// Operand returns the node the operator applies to.
+func (n *Unary) Operand() Node { return n.operand }
+
+// Pos returns the offset of the operator.
+func (n *Unary) Pos() int { return n.pos }
+
+// String renders the operator and its operand.
+func (n *Unary) String() string {
+	if n.op == "not" {
+		return "not " + wrap(n.operand, precedence(n.operand) < precNot)
+	}
+	return "-" + wrap(n.operand, precedence(n.operand) < precUnary)
+}
+
+// Op returns the operator name, e.g. "and", "<=", or a custom name.
+func (n *Binary) Op() string { return n.op }
+
+// Class returns the kind of operator.
+func (n *Binary) Class() OpClass { return n.class }
+
+// Left returns the left operand.
+func (n *Binary) Left() Node { return n.left }
+
+// Right returns the right operand. For "in" this is a *List or a node
+// evaluating to a slice.
+func (n *Binary) Right() Node { return n.right }
+
+// Pos returns the offset of the operator.
+func (n *Binary) Pos() int { return n.pos }
+
+// String renders both operands around the operator.
+func (n *Binary) String() string {
+	prec := precedence(n)
+	// Comparisons do not chain, so both sides bind tighter
+	leftParens := precedence(n.left) < prec || (prec == precComparison && precedence(n.left) == prec)
+	rightParens := precedence(n.right) <= prec
+	return wrap(n.left, leftParens) + " " + n.op + " " + wrap(n.right, rightParens)
+}
+
+// Elems returns a copy of the list elements.
+func (n *List) Elems() []Node { return append([]Node(nil), n.elems...) }
+
+// Pos returns the offset of the opening parenthesis.
+func (n *List) Pos() int { return n.pos }
+
+// String renders the parenthesized elements.
+func (n *List) String() string {
+	elems := make([]string, len(n.elems))
+	for i, elem := range n.elems {
+		elems[i] = elem.String()
+	}
+	return "(" + strings.Join(elems, ", ") + ")"
+}
+
+func (*Literal) astNode() {}
+func (*Ident) astNode()   {}
+func (*Unary) astNode()   {}
+func (*Binary) astNode()  {}
+func (*List) astNode()    {}
+
+// Rendering precedence, lowest first, matching the parser.
+const (
+	precOr = iota + 1
+	precAnd
+	precNot
+	precComparison
+	precAdditive
+	precMultiplicative
+	precUnary
+	precPrimary
+)
+
+// precedence returns the binding strength of n when rendered.
+func precedence(n Node) int {
+	switch n := n.(type) {
+	case *Unary:
+		if n.op == "not" {
+			return precNot
+		}
+		return precUnary
+	case *Binary:
+		switch n.op {
+		case "or":
+			return precOr
+		case "and":
+			return precAnd
+		case "+", "-":
+			return precAdditive
+		case "*", "/":
+			return precMultiplicative
+		}
+		return precComparison
+	case *Literal:
+		if isNegative(n.value) {
+			return precUnary // Rendered with a leading "-"
+		}
+	}
+	return precPrimary
+}
+
+// toAST converts a parsed node tree to its exported form.
+func toAST(n node) Node {
+	switch n := n.(type) {
+	case *literalNode:
+		return &Literal{value: n.value, pos: n.pos}
+	case *identNode:
+		return &Ident{name: n.name, pos: n.pos}
+	case *unaryNode:
+		return &Unary{op: n.op, operand: toAST(n.operand), pos: n.pos}
+	case *listNode:
+		list := &List{elems: make([]Node, len(n.elems)), pos: n.pos}
+		for i, elem := range n.elems {
+			list.elems[i] = toAST(elem)
+		}
+		return list
+	case *binaryNode:
+		return &Binary{op: n.op, class: classify(n.op), left: toAST(n.left), right: toAST(n.right), pos: n.pos}
+	default:
+		panic(fmt.Sprintf("expr: unknown node type %T", n))
+	}
+}
+
+// classify returns the class of a binary operator.
+func classify(op string) OpClass {
+	switch {
+	case op == "and" || op == "or":
+		return OpLogical
+	case comparisonOps[op]:
+		return OpComparison
+	case op == "+" || op == "-" || op == "*" || op == "/":
+		return OpArithmetic
+	default:
+		return OpCustom
+	}
+}
+
+// wrap renders n, parenthesized if parens is set.
+func wrap(n Node, parens bool) string {
+	if parens {
+		return "(" + n.String() + ")"
+	}
+	return n.String()
+}
+
+// quote renders s as a string literal. The language has no escapes, so
+// double quotes are used only when s contains a single quote.
+func quote(s string) string {
+	if strings.ContainsRune(s, '\'') {
+		return `"` + s + `"`
+	}
+	return "'" + s + "'"
+}
+
+// isNegative reports whether a numeric literal is below zero.
+func isNegative(v any) bool {
+	switch v := v.(type) {
+	case int64:
+		return v < 0
+	case float64:
+		return v < 0
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package expr
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse_Shape(t *testing.T) {
+	src := "status in ('ready', 'queued') and not (retries * 2 >= limit or user.role == 'admin')"
+	ast, err := Parse(src)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	and, ok := ast.Root().(*Binary)
+	if !ok || and.Op() != "and" || and.Class() != OpLogical {
+		t.Fatalf("root = %#v, want logical 'and'", ast.Root())
+	}
+	if and.Pos() != strings.Index(src, "and") {
+		t.Errorf("and.Pos() = %d, want %d", and.Pos(), strings.Index(src, "and"))
+	}
+
+	in, ok := and.Left().(*Binary)
+	if !ok || in.Op() != "in" || in.Class() != OpComparison {
+		t.Fatalf("left = %#v, want comparison 'in'", and.Left())
+	}
+	if id, ok := in.Left().(*Ident); !ok || id.Name() != "status" || id.Pos() != 0 {
+		t.Errorf("in.Left() = %#v, want status at 0", in.Left())
+	}
+	list, ok := in.Right().(*List)
+	if !ok {
+		t.Fatalf("in.Right() = %#v, want *List", in.Right())
+	}
+	var values []any
+	for _, elem := range list.Elems() {
+		values = append(values, elem.(*Literal).Value())
+	}
+	if !reflect.DeepEqual(values, []any{"ready", "queued"}) {
+		t.Errorf("list values = %v", values)
+	}
+
+	not, ok := and.Right().(*Unary)
+	if !ok || not.Op() != "not" {
+		t.Fatalf("right = %#v, want unary 'not'", and.Right())
+	}
+	or, ok := not.Operand().(*Binary)
+	if !ok || or.Op() != "or" {
+		t.Fatalf("not operand = %#v, want 'or'", not.Operand())
+	}
+	ge, ok := or.Left().(*Binary)
+	if !ok || ge.Op() != ">=" {
+		t.Fatalf("or.Left() = %#v, want '>='", or.Left())
+	}
+	mul, ok := ge.Left().(*Binary)
+	if !ok || mul.Op() != "*" || mul.Class() != OpArithmetic {
+		t.Fatalf("ge.Left() = %#v, want arithmetic '*'", ge.Left())
+	}
+	if lit, ok := mul.Right().(*Literal); !ok || lit.Value() != int64(2) {
+		t.Errorf("mul.Right() = %#v, want literal 2", mul.Right())
+	}
+	eq := or.Right().(*Binary)
+	if id, ok := eq.Left().(*Ident); !ok || id.Name() != "user.role" {
+		t.Errorf("eq.Left() = %#v, want user.role", eq.Left())
+	}
+}
+
+func TestParse_CustomOperator(t *testing.T) {
+	e := New(WithCustomOperator("starts with", func(left, right any) bool {
+		return strings.HasPrefix(left.(string), right.(string))
+	}))
+	ast, err := e.Parse("name starts with 'test'")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	root, ok := ast.Root().(*Binary)
+	if !ok || root.Op() != "starts with" || root.Class() != OpCustom {
+		t.Fatalf("root = %#v, want custom 'starts with'", ast.Root())
+	}
+	if got := ast.String(); got != "name starts with 'test'" {
+		t.Errorf("String() = %q", got)
+	}
+
+	if _, err := Parse("name starts with 'test'"); !errors.Is(err, ErrSyntax) {
+		t.Errorf("Parse() without operator error = %v, want ErrSyntax", err)
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	ast, err := Parse("  ")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if ast.Root() != nil || ast.String() != "" {
+		t.Errorf("empty AST = %v, %q", ast.Root(), ast.String())
+	}
+}
+
+func TestParse_SyntaxError(t *testing.T) {
+	ast, err := Parse("a and (b or")
+	if !errors.Is(err, ErrSyntax) {
+		t.Fatalf("Parse() error = %v, want ErrSyntax", err)
+	}
+	if ast != nil {
+		t.Errorf("Parse() returned non-nil AST on error")
+	}
+}
+
+func TestAST_String(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"a==1", "a == 1"},
+		{"!done and x", "not done and x"},
+		{"((a or b)) and c", "(a or b) and c"},
+		{"a or (b and c)", "a or b and c"},
+		{"not (a == b)", "not a == b"},
+		{"(not a) == b", "(not a) == b"},
+		{"a - (b - c)", "a - (b - c)"},
+		{"(a - b) - c", "a - b - c"},
+		{"(a + b) * -c", "(a + b) * -c"},
+		{"-(a + b)", "-(a + b)"},
+		{"-5 * 2", "-5 * 2"},
+		{"x / 2.0", "x / 2.0"},
+		{"1.5e3 > x", "1500.0 > x"},
+		{`name == "it's"`, `name == "it's"`},
+		{"s in ('a',)", "s in ('a')"},
+		{"s in roles", "s in roles"},
+		{"v == null", "v == null"},
+	}
+	for _, tt := range tests {
+		ast, err := Parse(tt.src)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.src, err)
+			continue
+		}
+		if got := ast.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestAST_StringRoundTrip(t *testing.T) {
+	sources := []string{
+		"status in ('ready', 'queued') and not (retries * 2 >= limit or user.role == 'admin')",
+		"(count + 2) * 10 > threshold - -3",
+		"a - (b - c) / (d * e) == 0 or !flag",
+		"not not x and (y or z)",
+		"msg contains 'err' and level != 'debug'",
+	}
+	vars := []map[string]any{
+		{"status": "ready", "retries": 1, "limit": 5, "user": map[string]any{"role": "dev"},
+			"count": 3, "threshold": 40, "a": 10, "b": 4, "c": 2, "d": 1, "e": 2, "flag": false,
+			"x": true, "y": false, "z": true, "msg": "err: boom", "level": "info"},
+		{"status": "done", "retries": 3, "limit": 5, "user": map[string]any{"role": "admin"},
+			"count": 8, "threshold": 100, "a": 1, "b": 1, "c": 1, "d": 1, "e": 1, "flag": true,
+			"x": false, "y": true, "z": false, "msg": "ok", "level": "debug"},
+	}
+
+	for _, src := range sources {
+		ast, err := Parse(src)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", src, err)
+		}
+		rendered := ast.String()
+		again, err := Parse(rendered)
+		if err != nil {
+			t.Fatalf("Parse(%q) of rendered %q error: %v", src, rendered, err)
+		}
+		if again.String() != rendered {
+			t.Errorf("String() not stable: %q -> %q", rendered, again.String())
+		}
+
+		for _, v := range vars {
+			want, err1 := EvalValue(src, v)
+			got, err2 := EvalValue(rendered, v)
+			if !reflect.DeepEqual(got, want) || (err1 == nil) != (err2 == nil) {
+				t.Errorf("EvalValue(%q) = %v, %v; original %q = %v, %v", rendered, got, err2, src, want, err1)
+			}
+		}
+	}
+}
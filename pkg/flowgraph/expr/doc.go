@@ -4,22 +4,28 @@ Package expr provides expression evaluation for flowgraph conditions.
 # Overview
 
 expr implements a simple expression language for evaluating conditions in
-workflow graphs. It supports comparison operators, logical operators, and
-variable resolution from a context map.
+workflow graphs. It supports comparison, logical, and arithmetic operators,
+parentheses for grouping, and variable resolution from a context map.
 
 # Expression Syntax
 
-	<expr> := <comparison>
-	        | <expr> 'and' <expr>
-	        | <expr> 'or' <expr>
-	        | 'not' <expr>
-	        | '!' <expr>
-	        | <value>
-
-	<comparison> := <value> <op> <value>
-	<op> := '==' | '!=' | '<' | '>' | '<=' | '>=' | 'contains'
+	<expr>       := <or>
+	<or>         := <and> ('or' <and>)*
+	<and>        := <not> ('and' <not>)*
+	<not>        := ('not' | '!') <not> | <comparison>
+	<comparison> := <sum> [<op> <sum>] | <sum> 'in' <list>
+	<sum>        := <product> (('+' | '-') <product>)*
+	<product>    := <unary> (('*' | '/') <unary>)*
+	<unary>      := '-' <unary> | <primary>
+	<primary>    := <value> | '(' <expr> ')'
+	<list>       := '(' [<sum> (',' <sum>)* [',']] ')' | <sum>
+
+	<op> := '==' | '!=' | '<' | '>' | '<=' | '>=' | 'contains' | <custom>
 	<value> := 'string' | "string" | number | true | false | null | identifier
 
+Operators are listed from lowest to highest precedence: "a or b and c"
+means "a or (b and c)", and "not x == y" means "not (x == y)".
+
 # Operators
 
 Comparison operators:
@@ -31,6 +37,7 @@ Comparison operators:
 	<=         Less than or equal (numeric comparison)
 	>=         Greater than or equal (numeric comparison)
 	contains   String contains substring
+	in         Membership in a list (uses == for each element)
 
 Logical operators:
 
@@ -39,6 +46,23 @@ Logical operators:
 	not        Logical NOT (prefix)
 	!          Logical NOT (prefix)
 
+Arithmetic operators:
+
+	a + b      Addition
+	a - b      Subtraction
+	a * b      Multiplication
+	a / b      Division
+	-a         Negation (prefix)
+
+Arithmetic operands must be numbers or numeric strings.
+
+Integer arithmetic yields int64; division and any float operand yield
+float64. Division by zero returns ErrDivisionByZero, and non-numeric
+operands return ErrNonNumeric. Malformed expressions return ErrSyntax.
+
+The right side of "in" is either a parenthesized list literal or a
+variable holding a slice or array; anything else returns ErrNotList.
+
 # Value Types
 
 Values can be:
@@ -49,6 +73,22 @@ Values can be:
   - Null: null, nil
   - Variables: referenced by name from the vars map
 
+Dotted identifiers walk nested maps, and numeric segments index slices:
+
+	user.profile.age > 18
+	items.0.name == 'widget'
+
+A key containing dots (vars["user.age"]) is matched directly before the
+nested path is tried. A missing nested field resolves to nil, which is
+falsy. An identifier that resolves to nothing at all evaluates to its own
+name as a string, so "status == active" compares against "active".
+Evaluators created with WithStrictVariables return ErrUndefinedVariable
+for both instead.
+
+String comparisons are case-sensitive. Evaluators created with
+WithCaseInsensitive fold case for ==, !=, contains, and in when both
+operands are strings.
+
 # Examples
 
 Simple comparisons:
@@ -74,9 +114,51 @@ Contains operator:
 
 	message contains 'error'    // true if message contains "error"
 
+In operator:
+
+	status in ('active', 'pending')
+	role in allowedRoles        // allowedRoles is a []string in vars
+
+Arithmetic and grouping:
+
+	(count + 2) > threshold
+	retries * backoff <= 60 and (force or not cached)
+
+	v, _ := expr.EvalValue("(count + 2) * 10", vars)  // int64(50)
+
+# Precompiled Programs
+
+Eval and Evaluate parse the expression on every call. When the same
+expression is evaluated repeatedly, such as a routing condition checked on
+every iteration, compile it once and reuse the Program:
+
+	prog, err := expr.Compile("retries < maxRetries and status != 'done'")
+	if err != nil {
+	    return err
+	}
+	ok, err := prog.Eval(vars)
+
+Evaluator.Compile does the same with the evaluator's custom operators and
+options. A Program is immutable and safe for concurrent use.
+
+# Inspecting Expressions
+
+Parse returns the expression's syntax tree for tooling such as editors and
+config linters. Nodes are *Literal, *Ident, *Unary, *Binary, and *List,
+each with its byte offset in the source; Binary.Class distinguishes
+logical, comparison, arithmetic, and custom operators. AST.String renders
+a normalized form that parses back to an equivalent tree:
+
+	ast, _ := expr.Parse("!done and (count+1)*2>=limit")
+	ast.String() // "not done and (count + 1) * 2 >= limit"
+
+Evaluator.Parse does the same with the evaluator's custom operators.
+
 # Custom Operators
 
-Register custom binary operators:
+Register custom binary operators. Names may be words (including several
+words, e.g. "starts with") or punctuation (e.g. "=~"); custom operators
+have comparison precedence:
 
 	e := expr.New(
 	    expr.WithCustomOperator("matches", func(left, right any) bool {
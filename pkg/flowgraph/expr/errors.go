@@ -0,0 +1,30 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by evaluation.
+var (
+	// ErrSyntax indicates the expression could not be parsed.
+	ErrSyntax = errors.New("syntax error")
+
+	// ErrDivisionByZero indicates an arithmetic division by zero.
+	ErrDivisionByZero = errors.New("division by zero")
+
+	// ErrNonNumeric indicates an arithmetic operand is not a number.
+	ErrNonNumeric = errors.New("non-numeric operand")
+
+	// ErrUndefinedVariable indicates an identifier did not resolve to a
+	// variable. Only returned by evaluators created with WithStrictVariables.
+	ErrUndefinedVariable = errors.New("undefined variable")
+
+	// ErrNotList indicates the right side of "in" is not a list.
+	ErrNotList = errors.New("right side of 'in' is not a list")
+)
+
+// syntaxError returns an error wrapping ErrSyntax at a byte offset.
+func syntaxError(pos int, msg string) error {
+	return fmt.Errorf("%w at position %d: %s", ErrSyntax, pos, msg)
+}
@@ -1,9 +1,6 @@
 package expr
 
-import (
-	"fmt"
-	"strings"
-)
+import "fmt"
 
 // BinaryOp is a function that compares two values and returns a boolean result.
 type BinaryOp func(left, right any) bool
@@ -11,6 +8,8 @@ type BinaryOp func(left, right any) bool
 // Evaluator evaluates boolean expressions with optional custom operators.
 type Evaluator struct {
 	customOps map[string]BinaryOp
+	strict    bool
+	foldCase  bool
 }
 
 // Option configures an Evaluator.
@@ -27,6 +26,26 @@ func WithCustomOperator(name string, fn BinaryOp) Option {
 	}
 }
 
+// WithStrictVariables makes unresolved identifiers an error. By default an
+// unknown name evaluates to itself as a string and a missing nested field
+// (e.g. "user.profile.age" when user has no profile) evaluates to nil. In
+// strict mode both return an error wrapping ErrUndefinedVariable.
+func WithStrictVariables() Option {
+	return func(e *Evaluator) {
+		e.strict = true
+	}
+}
+
+// WithCaseInsensitive makes ==, !=, contains, and in ignore case when
+// both operands are strings, so "status == 'ACTIVE'" matches "active".
+// Numeric and boolean comparisons are unaffected. Comparisons are
+// case-sensitive by default.
+func WithCaseInsensitive() Option {
+	return func(e *Evaluator) {
+		e.foldCase = true
+	}
+}
+
 // New creates a new Evaluator with the given options.
 func New(opts ...Option) *Evaluator {
 	e := &Evaluator{}
@@ -37,8 +56,34 @@ func New(opts ...Option) *Evaluator {
 }
 
 // Evaluate evaluates a boolean expression against the provided variables.
+// Non-boolean results (e.g. a bare variable or arithmetic) are converted
+// using IsTruthy. An empty expression evaluates to false.
+//
+// Evaluate parses expr on every call; use Compile to parse once when the
+// same expression is evaluated repeatedly.
 func (e *Evaluator) Evaluate(expr string, vars map[string]any) (bool, error) {
-	return e.evaluateCondition(expr, vars)
+	prog, err := e.Compile(expr)
+	if err != nil {
+		return false, err
+	}
+	return prog.Eval(vars)
+}
+
+// EvaluateValue evaluates an expression and returns its value rather than
+// its truthiness. Arithmetic yields int64 or float64, comparisons and
+// logical operators yield bool, and literals and variables yield
+// themselves.
+//
+// Example:
+//
+//	v, _ := expr.New().EvaluateValue("(count + 2) * 10", map[string]any{"count": 3})
+//	// v == int64(50)
+func (e *Evaluator) EvaluateValue(expr string, vars map[string]any) (any, error) {
+	n, err := parse(expr, e.customOps)
+	if err != nil {
+		return nil, err
+	}
+	return n.eval(e, vars)
 }
 
 // Eval is a convenience function that evaluates an expression using
@@ -47,96 +92,94 @@ func Eval(expr string, vars map[string]any) (bool, error) {
 	return New().Evaluate(expr, vars)
 }
 
-// evaluateCondition evaluates a condition expression.
-// Supports: ==, !=, <, >, <=, >=, and, or, not, !, contains
-func (e *Evaluator) evaluateCondition(expr string, vars map[string]any) (bool, error) {
-	expr = strings.TrimSpace(expr)
-	if expr == "" {
-		return false, nil
+// EvalValue is a convenience function that evaluates an expression to a
+// value using the default evaluator (no custom operators).
+func EvalValue(expr string, vars map[string]any) (any, error) {
+	return New().EvaluateValue(expr, vars)
+}
+
+func (n *literalNode) eval(_ *Evaluator, _ map[string]any) (any, error) {
+	return n.value, nil
+}
+
+func (n *identNode) eval(e *Evaluator, vars map[string]any) (any, error) {
+	val, found, rooted := lookupVar(vars, n.name)
+	switch {
+	case found:
+		return val, nil
+	case e.strict:
+		return nil, fmt.Errorf("%w: %s", ErrUndefinedVariable, n.name)
+	case rooted:
+		return nil, nil
+	default:
+		return n.name, nil
 	}
+}
 
-	// Handle negation with "not " prefix
-	if strings.HasPrefix(expr, "not ") {
-		inner := strings.TrimPrefix(expr, "not ")
-		result, err := e.evaluateCondition(strings.TrimSpace(inner), vars)
+func (n *listNode) eval(e *Evaluator, vars map[string]any) (any, error) {
+	values := make([]any, len(n.elems))
+	for i, elem := range n.elems {
+		v, err := elem.eval(e, vars)
 		if err != nil {
-			return false, err
+			return nil, err
 		}
-		return !result, nil
+		values[i] = v
 	}
+	return values, nil
+}
 
-	// Handle negation with "!" prefix
-	if strings.HasPrefix(expr, "!") {
-		inner := strings.TrimPrefix(expr, "!")
-		result, err := e.evaluateCondition(strings.TrimSpace(inner), vars)
-		if err != nil {
-			return false, err
-		}
-		return !result, nil
+func (n *unaryNode) eval(e *Evaluator, vars map[string]any) (any, error) {
+	val, err := n.operand.eval(e, vars)
+	if err != nil {
+		return nil, err
 	}
+	if n.op == "not" {
+		return !IsTruthy(val), nil
+	}
+	return negate(val)
+}
 
-	// Handle AND (split on first " and ")
-	if parts := strings.SplitN(expr, " and ", 2); len(parts) == 2 {
-		left, errL := e.evaluateCondition(parts[0], vars)
-		if errL != nil {
-			return false, errL
-		}
-		right, errR := e.evaluateCondition(parts[1], vars)
-		if errR != nil {
-			return false, errR
-		}
-		return left && right, nil
+func (n *binaryNode) eval(e *Evaluator, vars map[string]any) (any, error) {
+	left, err := n.left.eval(e, vars)
+	if err != nil {
+		return nil, err
 	}
 
-	// Handle OR (split on first " or ")
-	if parts := strings.SplitN(expr, " or ", 2); len(parts) == 2 {
-		left, errL := e.evaluateCondition(parts[0], vars)
-		if errL != nil {
-			return false, errL
+	// Short-circuit logical operators
+	switch n.op {
+	case "and":
+		if !IsTruthy(left) {
+			return false, nil
 		}
-		right, errR := e.evaluateCondition(parts[1], vars)
-		if errR != nil {
-			return false, errR
+		right, err := n.right.eval(e, vars)
+		if err != nil {
+			return nil, err
+		}
+		return IsTruthy(right), nil
+	case "or":
+		if IsTruthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(e, vars)
+		if err != nil {
+			return nil, err
 		}
-		return left || right, nil
+		return IsTruthy(right), nil
 	}
 
-	// Define built-in operators in order (longer operators first to avoid partial matches)
-	builtinOps := []struct {
-		op      string
-		compare BinaryOp
-	}{
-		{"==", func(l, r any) bool { return fmt.Sprintf("%v", l) == fmt.Sprintf("%v", r) }},
-		{"!=", func(l, r any) bool { return fmt.Sprintf("%v", l) != fmt.Sprintf("%v", r) }},
-		{">=", func(l, r any) bool { return ToFloat64(l) >= ToFloat64(r) }},
-		{"<=", func(l, r any) bool { return ToFloat64(l) <= ToFloat64(r) }},
-		{">", func(l, r any) bool { return ToFloat64(l) > ToFloat64(r) }},
-		{"<", func(l, r any) bool { return ToFloat64(l) < ToFloat64(r) }},
-		{" contains ", func(l, r any) bool {
-			return strings.Contains(fmt.Sprintf("%v", l), fmt.Sprintf("%v", r))
-		}},
+	right, err := n.right.eval(e, vars)
+	if err != nil {
+		return nil, err
 	}
 
-	// Try built-in operators
-	for _, op := range builtinOps {
-		if parts := strings.SplitN(expr, op.op, 2); len(parts) == 2 {
-			left := Resolve(strings.TrimSpace(parts[0]), vars)
-			right := Resolve(strings.TrimSpace(parts[1]), vars)
-			return op.compare(left, right), nil
-		}
+	switch {
+	case comparisonOps[n.op]:
+		return compare(left, right, n.op, e.foldCase)
+	case n.op == "+" || n.op == "-" || n.op == "*" || n.op == "/":
+		return arithmetic(n.op, left, right)
 	}
-
-	// Try custom operators (wrap with spaces for word boundaries)
-	for name, fn := range e.customOps {
-		opPattern := " " + name + " "
-		if parts := strings.SplitN(expr, opPattern, 2); len(parts) == 2 {
-			left := Resolve(strings.TrimSpace(parts[0]), vars)
-			right := Resolve(strings.TrimSpace(parts[1]), vars)
-			return fn(left, right), nil
-		}
+	if fn, ok := e.customOps[n.op]; ok {
+		return fn(left, right), nil
 	}
-
-	// Single value - check if truthy
-	val := Resolve(expr, vars)
-	return IsTruthy(val), nil
+	return nil, fmt.Errorf("unknown operator: %s", n.op)
 }
@@ -1,7 +1,10 @@
 package expr
 
 import (
+	"errors"
+	"fmt"
 	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -838,6 +841,24 @@ func TestResolve(t *testing.T) {
 			vars: map[string]any{"hello": "world"},
 			want: "world",
 		},
+		{
+			name: "nested field",
+			s:    "user.name",
+			vars: map[string]any{"user": map[string]any{"name": "alice"}},
+			want: "alice",
+		},
+		{
+			name: "missing nested field",
+			s:    "user.email",
+			vars: map[string]any{"user": map[string]any{"name": "alice"}},
+			want: nil,
+		},
+		{
+			name: "unknown dotted identifier",
+			s:    "user.name",
+			vars: map[string]any{},
+			want: "user.name",
+		},
 	}
 
 	for _, tt := range tests {
@@ -939,6 +960,9 @@ func TestCompare(t *testing.T) {
 		{"greater or equal false", 5, 10, ">=", false, false},
 		{"contains true", "hello world", "world", "contains", true, false},
 		{"contains false", "hello world", "foo", "contains", false, false},
+		{"in true", "b", []string{"a", "b"}, "in", true, false},
+		{"in false", "c", []any{"a", "b"}, "in", false, false},
+		{"in not a list", "a", "abc", "in", false, true},
 		{"unknown operator", 1, 2, "??", false, true},
 	}
 
@@ -1056,3 +1080,392 @@ func TestEval_VariableTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestEval_ArithmeticAndParentheses(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		vars map[string]any
+		want bool
+	}{
+		{
+			name: "addition in comparison",
+			expr: "(count + 2) > threshold",
+			vars: map[string]any{"count": 9, "threshold": 10},
+			want: true,
+		},
+		{
+			name: "multiplication binds tighter than addition",
+			expr: "1 + 2 * 3 == 7",
+			want: true,
+		},
+		{
+			name: "parentheses override precedence",
+			expr: "(1 + 2) * 3 == 9",
+			want: true,
+		},
+		{
+			name: "left associative subtraction",
+			expr: "10 - 4 - 3 == 3",
+			want: true,
+		},
+		{
+			name: "division yields float",
+			expr: "total / count >= 2.5",
+			vars: map[string]any{"total": 5, "count": 2},
+			want: true,
+		},
+		{
+			name: "unary minus on variable",
+			expr: "-temp > 0",
+			vars: map[string]any{"temp": -3.5},
+			want: true,
+		},
+		{
+			name: "numeric string operand",
+			expr: "value * 2 == 20",
+			vars: map[string]any{"value": "10"},
+			want: true,
+		},
+		{
+			name: "grouped boolean logic",
+			expr: "a and (b or c)",
+			vars: map[string]any{"a": true, "b": false, "c": true},
+			want: true,
+		},
+		{
+			name: "grouping changes boolean result",
+			expr: "(a and b) or c",
+			vars: map[string]any{"a": false, "b": true, "c": false},
+			want: false,
+		},
+		{
+			name: "not with parenthesized group",
+			expr: "not (a or b)",
+			vars: map[string]any{"a": false, "b": false},
+			want: true,
+		},
+		{
+			name: "operators without spaces",
+			expr: "count+1>=limit",
+			vars: map[string]any{"count": 4, "limit": 5},
+			want: true,
+		},
+		{
+			name: "operators inside quoted strings are literal",
+			expr: "label == 'a + b'",
+			vars: map[string]any{"label": "a + b"},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Eval(tt.expr, tt.vars)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval(%q, %v) = %v, want %v", tt.expr, tt.vars, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalValue(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		vars map[string]any
+		want any
+	}{
+		{"integer arithmetic", "(count + 2) * 10", map[string]any{"count": 3}, int64(50)},
+		{"float arithmetic", "price * 2", map[string]any{"price": 1.25}, 2.5},
+		{"division", "7 / 2", nil, 3.5},
+		{"negative literal", "-5", nil, int64(-5)},
+		{"variable", "name", map[string]any{"name": "flow"}, "flow"},
+		{"comparison", "2 > 1", nil, true},
+		{"logical", "a or b", map[string]any{"a": false, "b": 0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvalValue(tt.expr, tt.vars)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("EvalValue(%q) = %v (%T), want %v (%T)", tt.expr, got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestEval_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		vars    map[string]any
+		wantErr error
+	}{
+		{"division by zero", "count / 0 > 1", map[string]any{"count": 5}, ErrDivisionByZero},
+		{"division by zero variable", "1 / n", map[string]any{"n": 0.0}, ErrDivisionByZero},
+		{"non-numeric operand", "name + 1 > 0", map[string]any{"name": "flow"}, ErrNonNumeric},
+		{"unclosed parenthesis", "(a and b", nil, ErrSyntax},
+		{"unexpected closing parenthesis", "a)", nil, ErrSyntax},
+		{"unterminated string", "status == 'open", nil, ErrSyntax},
+		{"dangling operator", "count +", nil, ErrSyntax},
+		{"unknown character", "count # 2", nil, ErrSyntax},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Eval(tt.expr, tt.vars)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Eval(%q) error = %v, want %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluator_CustomOperatorForms(t *testing.T) {
+	e := New(
+		WithCustomOperator("=~", func(l, r any) bool {
+			return strings.HasPrefix(fmt.Sprintf("%v", l), fmt.Sprintf("%v", r))
+		}),
+		WithCustomOperator("starts with", func(l, r any) bool {
+			return strings.HasPrefix(fmt.Sprintf("%v", l), fmt.Sprintf("%v", r))
+		}),
+	)
+
+	for _, expr := range []string{
+		"name =~ 'flow'",
+		"name starts with 'flow'",
+		"name starts with 'flow' and (count + 1) > 1",
+	} {
+		got, err := e.Evaluate(expr, map[string]any{"name": "flowgraph", "count": 1})
+		if err != nil {
+			t.Fatalf("Evaluate(%q): unexpected error: %v", expr, err)
+		}
+		if !got {
+			t.Errorf("Evaluate(%q) = false, want true", expr)
+		}
+	}
+}
+
+func TestEval_InOperator(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		vars map[string]any
+		want bool
+	}{
+		{
+			name: "literal list match",
+			expr: "status in ('active', 'pending')",
+			vars: map[string]any{"status": "pending"},
+			want: true,
+		},
+		{
+			name: "literal list no match",
+			expr: "status in ('active', 'pending')",
+			vars: map[string]any{"status": "closed"},
+			want: false,
+		},
+		{
+			name: "single element list",
+			expr: "status in ('active')",
+			vars: map[string]any{"status": "active"},
+			want: true,
+		},
+		{
+			name: "trailing comma",
+			expr: "status in ('active',)",
+			vars: map[string]any{"status": "active"},
+			want: true,
+		},
+		{
+			name: "empty list",
+			expr: "status in ()",
+			vars: map[string]any{"status": "active"},
+			want: false,
+		},
+		{
+			name: "numeric equality semantics match ==",
+			expr: "count in (1, 2, 3)",
+			vars: map[string]any{"count": 2},
+			want: true,
+		},
+		{
+			name: "list elements can be expressions",
+			expr: "count in (limit - 1, limit)",
+			vars: map[string]any{"count": 9, "limit": 10},
+			want: true,
+		},
+		{
+			name: "[]string variable",
+			expr: "role in allowedRoles",
+			vars: map[string]any{"role": "admin", "allowedRoles": []string{"admin", "owner"}},
+			want: true,
+		},
+		{
+			name: "[]any variable",
+			expr: "id in ids",
+			vars: map[string]any{"id": 7, "ids": []any{int64(3), "7"}},
+			want: true,
+		},
+		{
+			name: "combined with logic",
+			expr: "not (status in ('closed', 'archived')) and count > 0",
+			vars: map[string]any{"status": "open", "count": 1},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Eval(tt.expr, tt.vars)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval(%q, %v) = %v, want %v", tt.expr, tt.vars, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEval_InOperatorErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		vars    map[string]any
+		wantErr error
+	}{
+		{"scalar variable", "role in admin", map[string]any{"admin": "admin"}, ErrNotList},
+		{"string literal", "role in 'admin'", nil, ErrNotList},
+		{"unclosed list", "role in ('a', 'b'", nil, ErrSyntax},
+		{"missing comma", "role in ('a' 'b')", nil, ErrSyntax},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Eval(tt.expr, tt.vars)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Eval(%q) error = %v, want %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEval_NestedFields(t *testing.T) {
+	vars := map[string]any{
+		"user": map[string]any{
+			"profile": map[string]any{"age": 21, "tags": []string{"admin", "beta"}},
+		},
+		"items": []any{
+			map[string]any{"name": "first", "qty": 2},
+			map[string]any{"name": "second", "qty": 0},
+		},
+		"limits":   map[string]int{"max": 10},
+		"dot.key":  "flat",
+		"user.age": 99, // A flat key wins over the nested path
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		want bool
+	}{
+		{"nested map", "user.profile.age > 18", true},
+		{"flat dotted key", "dot.key == 'flat'", true},
+		{"flat key wins", "user.age == 99", true},
+		{"missing intermediate is nil", "user.settings.theme == null", true},
+		{"missing intermediate is falsy", "not user.settings.theme", true},
+		{"slice index", "items.0.name == 'first'", true},
+		{"slice index arithmetic", "items.0.qty + items.1.qty == 2", true},
+		{"index out of range is nil", "items.5.name == null", true},
+		{"non-numeric index is nil", "items.first == null", true},
+		{"typed map", "limits.max == 10", true},
+		{"nested list in", "'beta' in user.profile.tags", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Eval(tt.expr, vars)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluator_WithStrictVariables(t *testing.T) {
+	e := New(WithStrictVariables())
+	vars := map[string]any{"user": map[string]any{"age": 30}}
+
+	got, err := e.Evaluate("user.age >= 18", vars)
+	if err != nil || !got {
+		t.Fatalf("Evaluate(user.age >= 18) = %v, %v; want true, nil", got, err)
+	}
+
+	for _, expr := range []string{"user.profile.age > 18", "unknown == 'x'", "user.age > 18 and missing"} {
+		_, err := e.Evaluate(expr, vars)
+		if !errors.Is(err, ErrUndefinedVariable) {
+			t.Errorf("Evaluate(%q) error = %v, want ErrUndefinedVariable", expr, err)
+		}
+	}
+
+	// Short-circuiting skips unresolved names that are never evaluated
+	if _, err := e.Evaluate("user.age < 18 and missing", vars); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestEvaluator_WithCaseInsensitive(t *testing.T) {
+	e := New(WithCaseInsensitive())
+	vars := map[string]any{
+		"status":  "Active",
+		"message": "Disk ERROR on node",
+		"tags":    []any{"Beta", "GA"},
+		"count":   int64(10),
+		"enabled": true,
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"status == 'ACTIVE'", true},
+		{"'active' == status", true},
+		{"status != 'active'", false},
+		{"status == 'inactive'", false},
+		{"message contains 'error'", true},
+		{"message contains 'Warning'", false},
+		{"'beta' in tags", true},
+		{"count == 10", true},
+		{"enabled == true", true},
+		{"enabled == 'TRUE'", false},
+		{"count > 5", true},
+	}
+	for _, tt := range tests {
+		got, err := e.Evaluate(tt.expr, vars)
+		if err != nil {
+			t.Errorf("Evaluate(%q) error = %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Evaluate(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	// Default evaluator stays case-sensitive
+	for _, expr := range []string{"status == 'ACTIVE'", "message contains 'error'", "'beta' in tags"} {
+		if got, _ := Eval(expr, vars); got {
+			t.Errorf("Eval(%q) = true, want false without WithCaseInsensitive", expr)
+		}
+	}
+}
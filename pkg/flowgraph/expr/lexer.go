@@ -0,0 +1,153 @@
+package expr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// tokenKind classifies a lexical token.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokSymbol
+)
+
+// token is a lexical token with its byte offset in the source.
+type token struct {
+	kind tokenKind
+	text string // Identifier, symbol, or number text; unquoted string contents
+	pos  int
+}
+
+// builtinSymbols are the punctuation operators of the language.
+var builtinSymbols = []string{"==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "(", ")", ",", "!"}
+
+// lexer splits an expression into tokens.
+type lexer struct {
+	src     string
+	pos     int
+	symbols []string // Longest first, so "<=" wins over "<"
+}
+
+// newLexer creates a lexer for src. Symbolic custom operators (names
+// containing no identifier characters, e.g. "=~") are recognized alongside
+// the built-in symbols.
+func newLexer(src string, customOps map[string]BinaryOp) *lexer {
+	symbols := append([]string(nil), builtinSymbols...)
+	for name := range customOps {
+		if isSymbolic(name) {
+			symbols = append(symbols, name)
+		}
+	}
+	sort.SliceStable(symbols, func(i, j int) bool { return len(symbols[i]) > len(symbols[j]) })
+	return &lexer{src: src, symbols: symbols}
+}
+
+// tokenize returns all tokens in src, ending with tokEOF.
+func (l *lexer) tokenize() ([]token, error) {
+	var tokens []token
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.kind == tokEOF {
+			return tokens, nil
+		}
+	}
+}
+
+// next scans the next token.
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) && unicode.IsSpace(rune(l.src[l.pos])) {
+		l.pos++
+	}
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	c := l.src[l.pos]
+	switch {
+	case c == '\'' || c == '"':
+		end := strings.IndexByte(l.src[start+1:], c)
+		if end < 0 {
+			return token{}, syntaxError(start, "unterminated string")
+		}
+		l.pos = start + 1 + end + 1
+		return token{kind: tokString, text: l.src[start+1 : start+1+end], pos: start}, nil
+
+	case isDigit(c):
+		l.scanNumber()
+		return token{kind: tokNumber, text: l.src[start:l.pos], pos: start}, nil
+
+	case isIdentStart(c):
+		for l.pos < len(l.src) && isIdentChar(l.src[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokIdent, text: l.src[start:l.pos], pos: start}, nil
+	}
+
+	for _, sym := range l.symbols {
+		if strings.HasPrefix(l.src[start:], sym) {
+			l.pos += len(sym)
+			return token{kind: tokSymbol, text: sym, pos: start}, nil
+		}
+	}
+	return token{}, syntaxError(start, fmt.Sprintf("unexpected character %q", c))
+}
+
+// scanNumber advances over an integer, decimal, or exponent literal.
+func (l *lexer) scanNumber() {
+	digits := func() {
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+		}
+	}
+
+	digits()
+	if l.pos+1 < len(l.src) && l.src[l.pos] == '.' && isDigit(l.src[l.pos+1]) {
+		l.pos++
+		digits()
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		exp := l.pos + 1
+		if exp < len(l.src) && (l.src[exp] == '+' || l.src[exp] == '-') {
+			exp++
+		}
+		if exp < len(l.src) && isDigit(l.src[exp]) {
+			l.pos = exp
+			digits()
+		}
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '.'
+}
+
+// isSymbolic reports whether an operator name is punctuation rather than
+// a word, and so must be recognized by the lexer.
+func isSymbolic(name string) bool {
+	for i := 0; i < len(name); i++ {
+		if isIdentChar(name[i]) || name[i] == ' ' {
+			return false
+		}
+	}
+	return name != ""
+}
@@ -2,12 +2,23 @@ package expr
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 )
 
 // Compare compares two values using the specified operator.
 // Returns an error for unknown operators.
 func Compare(left, right any, op string) (bool, error) {
+	return compare(left, right, op, false)
+}
+
+// compare implements Compare. With foldCase, ==, !=, contains, and in
+// ignore case when both operands are strings.
+func compare(left, right any, op string, foldCase bool) (bool, error) {
+	if foldCase && op != "in" {
+		left, right = lowerStrings(left, right)
+	}
+
 	switch op {
 	case "==":
 		return compareEquals(left, right), nil
@@ -23,11 +34,24 @@ func Compare(left, right any, op string) (bool, error) {
 		return compareGTE(left, right), nil
 	case "contains":
 		return compareContains(left, right), nil
+	case "in":
+		return compareIn(left, right, foldCase)
 	default:
 		return false, fmt.Errorf("unknown operator: %s", op)
 	}
 }
 
+// lowerStrings lowercases left and right if both are strings, and returns
+// them unchanged otherwise, so numbers and booleans compare as before.
+func lowerStrings(left, right any) (any, any) {
+	l, lok := left.(string)
+	r, rok := right.(string)
+	if !lok || !rok {
+		return left, right
+	}
+	return strings.ToLower(l), strings.ToLower(r)
+}
+
 // compareEquals compares if left equals right using string comparison.
 func compareEquals(left, right any) bool {
 	return fmt.Sprintf("%v", left) == fmt.Sprintf("%v", right)
@@ -66,3 +90,22 @@ func compareGTE(left, right any) bool {
 func compareContains(left, right any) bool {
 	return strings.Contains(fmt.Sprintf("%v", left), fmt.Sprintf("%v", right))
 }
+
+// compareIn checks if left equals any element of the list right, using the
+// same equality as ==. Returns ErrNotList if right is not a slice or array.
+func compareIn(left, right any, foldCase bool) (bool, error) {
+	list := reflect.ValueOf(right)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return false, fmt.Errorf("%w: got %T", ErrNotList, right)
+	}
+	for i := 0; i < list.Len(); i++ {
+		l, r := left, list.Index(i).Interface()
+		if foldCase {
+			l, r = lowerStrings(l, r)
+		}
+		if compareEquals(l, r) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
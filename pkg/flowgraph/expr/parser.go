@@ -0,0 +1,353 @@
+package expr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// node is a parsed expression.
+type node interface {
+	eval(e *Evaluator, vars map[string]any) (any, error)
+}
+
+// literalNode is a constant value.
+type literalNode struct {
+	value any
+	pos   int
+}
+
+// identNode is a variable reference, possibly dotted for nested access.
+// Unknown names evaluate to themselves as strings, matching Resolve.
+type identNode struct {
+	name string
+	pos  int
+}
+
+// unaryNode applies "not" or "-" to its operand.
+type unaryNode struct {
+	op      string
+	operand node
+	pos     int // Operator offset
+}
+
+// listNode is a parenthesized list literal, e.g. ('a', 'b').
+type listNode struct {
+	elems []node
+	pos   int // Opening parenthesis offset
+}
+
+// binaryNode applies a logical, comparison, arithmetic, or custom operator.
+type binaryNode struct {
+	op          string
+	left, right node
+	pos         int // Operator offset
+}
+
+// comparisonOps are the built-in comparison operators, handled by Compare.
+var comparisonOps = map[string]bool{
+	"==": true, "!=": true, "<": true, ">": true, "<=": true, ">=": true, "contains": true, "in": true,
+}
+
+// parser builds a node tree from tokens by recursive descent.
+//
+// Precedence, lowest first:
+//
+//	or
+//	and
+//	not, !
+//	comparison (==, !=, <, >, <=, >=, contains, in, custom)
+//	+, -
+//	*, /
+//	unary -
+type parser struct {
+	tokens    []token
+	pos       int
+	customOps map[string]BinaryOp
+}
+
+// parse parses src into a node tree.
+func parse(src string, customOps map[string]BinaryOp) (node, error) {
+	tokens, err := newLexer(src, customOps).tokenize()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, customOps: customOps}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, syntaxError(tok.pos, fmt.Sprintf("unexpected %q", tok.text))
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// isKeyword reports whether the current token is the given word.
+func (p *parser) isKeyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == tokIdent && tok.text == word
+}
+
+// isSymbol reports whether the current token is the given symbol.
+func (p *parser) isSymbol(sym string) bool {
+	tok := p.peek()
+	return tok.kind == tokSymbol && tok.text == sym
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		pos := p.advance().pos
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "or", left: left, right: right, pos: pos}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		pos := p.advance().pos
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "and", left: left, right: right, pos: pos}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.isKeyword("not") || p.isSymbol("!") {
+		pos := p.advance().pos
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "not", operand: operand, pos: pos}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	pos := p.peek().pos
+	op, ok := p.comparisonOp()
+	if !ok {
+		return left, nil
+	}
+	if op == "in" && p.isSymbol("(") {
+		list, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: op, left: left, right: list, pos: pos}, nil
+	}
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{op: op, left: left, right: right, pos: pos}, nil
+}
+
+// comparisonOp consumes a built-in or custom comparison operator if one is
+// next. Custom operator names may span several words.
+func (p *parser) comparisonOp() (string, bool) {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokSymbol && comparisonOps[tok.text]:
+		p.advance()
+		return tok.text, true
+	case tok.kind == tokIdent && (tok.text == "contains" || tok.text == "in"):
+		p.advance()
+		return tok.text, true
+	}
+
+	for name := range p.customOps {
+		if n := p.matchWords(name); n > 0 {
+			p.pos += n
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// matchWords returns how many tokens spell the operator name at the
+// current position, or 0 if they do not.
+func (p *parser) matchWords(name string) int {
+	if isSymbolic(name) {
+		if p.isSymbol(name) {
+			return 1
+		}
+		return 0
+	}
+
+	words := strings.Fields(name)
+	for i, word := range words {
+		if p.pos+i >= len(p.tokens) {
+			return 0
+		}
+		tok := p.tokens[p.pos+i]
+		if tok.kind != tokIdent || tok.text != word {
+			return 0
+		}
+	}
+	return len(words)
+}
+
+// parseList parses a parenthesized, comma-separated list of values.
+// A single element without a comma is still a list, so "x in ('a')" works.
+func (p *parser) parseList() (node, error) {
+	list := &listNode{pos: p.advance().pos} // "("
+	if p.isSymbol(")") {
+		p.advance()
+		return list, nil
+	}
+	for {
+		elem, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		list.elems = append(list.elems, elem)
+
+		if p.isSymbol(",") {
+			p.advance()
+			if p.isSymbol(")") { // Trailing comma
+				p.advance()
+				return list, nil
+			}
+			continue
+		}
+		if !p.isSymbol(")") {
+			return nil, syntaxError(p.peek().pos, "expected ',' or ')' in list")
+		}
+		p.advance()
+		return list, nil
+	}
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isSymbol("+") || p.isSymbol("-") {
+		tok := p.advance()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tok.text, left: left, right: right, pos: tok.pos}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isSymbol("*") || p.isSymbol("/") {
+		tok := p.advance()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tok.text, left: left, right: right, pos: tok.pos}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.isSymbol("-") {
+		pos := p.advance().pos
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		// Fold negative literals so "-5" is the constant -5
+		if lit, ok := operand.(*literalNode); ok {
+			if v, err := negate(lit.value); err == nil {
+				return &literalNode{value: v, pos: pos}, nil
+			}
+		}
+		return &unaryNode{op: "-", operand: operand, pos: pos}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.advance()
+	switch tok.kind {
+	case tokNumber:
+		return &literalNode{value: parseNumber(tok.text), pos: tok.pos}, nil
+
+	case tokString:
+		return &literalNode{value: tok.text, pos: tok.pos}, nil
+
+	case tokIdent:
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return &literalNode{value: true, pos: tok.pos}, nil
+		case "false":
+			return &literalNode{value: false, pos: tok.pos}, nil
+		case "null", "nil":
+			return &literalNode{value: nil, pos: tok.pos}, nil
+		}
+		return &identNode{name: tok.text, pos: tok.pos}, nil
+
+	case tokSymbol:
+		if tok.text == "(" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if !p.isSymbol(")") {
+				return nil, syntaxError(p.peek().pos, "expected ')'")
+			}
+			p.advance()
+			return inner, nil
+		}
+		return nil, syntaxError(tok.pos, fmt.Sprintf("unexpected %q", tok.text))
+
+	default:
+		return nil, syntaxError(tok.pos, "unexpected end of expression")
+	}
+}
+
+// parseNumber converts a number token to int64 if integral, else float64.
+func parseNumber(text string) any {
+	num := json.Number(text)
+	if i, err := num.Int64(); err == nil {
+		return i
+	}
+	f, _ := num.Float64()
+	return f
+}
@@ -0,0 +1,72 @@
+package expr
+
+import "strings"
+
+// Program is a parsed expression that can be evaluated many times without
+// re-parsing. Create one with Compile or Evaluator.Compile.
+//
+// A Program is immutable and safe for concurrent use.
+//
+// Example:
+//
+//	prog, err := expr.Compile("retries < maxRetries and status != 'done'")
+//	if err != nil {
+//	    return err
+//	}
+//	for _, vars := range inputs {
+//	    ok, err := prog.Eval(vars)
+//	    ...
+//	}
+type Program struct {
+	source string
+	root   node // nil for an empty expression
+	eval   *Evaluator
+}
+
+// Compile parses source with the evaluator's custom operators and options.
+// The returned Program keeps a reference to the evaluator, which must not
+// be modified afterwards. Returns an error wrapping ErrSyntax if source
+// cannot be parsed. An empty source compiles to a Program that evaluates
+// to false.
+func (e *Evaluator) Compile(source string) (*Program, error) {
+	p := &Program{source: source, eval: e}
+	if strings.TrimSpace(source) == "" {
+		return p, nil
+	}
+
+	root, err := parse(source, e.customOps)
+	if err != nil {
+		return nil, err
+	}
+	p.root = root
+	return p, nil
+}
+
+// Compile parses source using the default evaluator (no custom operators).
+func Compile(source string) (*Program, error) {
+	return New().Compile(source)
+}
+
+// Eval evaluates the program against vars and converts the result using
+// IsTruthy.
+func (p *Program) Eval(vars map[string]any) (bool, error) {
+	val, err := p.EvalValue(vars)
+	if err != nil {
+		return false, err
+	}
+	return IsTruthy(val), nil
+}
+
+// EvalValue evaluates the program against vars and returns its value, as
+// Evaluator.EvaluateValue does. An empty program evaluates to nil.
+func (p *Program) EvalValue(vars map[string]any) (any, error) {
+	if p.root == nil {
+		return nil, nil
+	}
+	return p.root.eval(p.eval, vars)
+}
+
+// String returns the source the program was compiled from.
+func (p *Program) String() string {
+	return p.source
+}
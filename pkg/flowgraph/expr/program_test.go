@@ -0,0 +1,134 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	prog, err := Compile("count > limit and status == 'ready'")
+	if err != nil {
+		t.Fatalf("Compile() error: %v", err)
+	}
+
+	tests := []struct {
+		vars map[string]any
+		want bool
+	}{
+		{map[string]any{"count": 5, "limit": 3, "status": "ready"}, true},
+		{map[string]any{"count": 1, "limit": 3, "status": "ready"}, false},
+		{map[string]any{"count": 5, "limit": 3, "status": "waiting"}, false},
+	}
+	for _, tt := range tests {
+		got, err := prog.Eval(tt.vars)
+		if err != nil {
+			t.Fatalf("Eval(%v) error: %v", tt.vars, err)
+		}
+		if got != tt.want {
+			t.Errorf("Eval(%v) = %v, want %v", tt.vars, got, tt.want)
+		}
+	}
+
+	if got := prog.String(); got != "count > limit and status == 'ready'" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestCompile_SyntaxError(t *testing.T) {
+	prog, err := Compile("(a and b")
+	if !errors.Is(err, ErrSyntax) {
+		t.Fatalf("Compile() error = %v, want ErrSyntax", err)
+	}
+	if prog != nil {
+		t.Errorf("Compile() returned non-nil program on error")
+	}
+}
+
+func TestCompile_Empty(t *testing.T) {
+	prog, err := Compile("   ")
+	if err != nil {
+		t.Fatalf("Compile() error: %v", err)
+	}
+	got, err := prog.Eval(map[string]any{"x": true})
+	if err != nil || got {
+		t.Errorf("Eval() = %v, %v; want false, nil", got, err)
+	}
+	val, err := prog.EvalValue(nil)
+	if err != nil || val != nil {
+		t.Errorf("EvalValue() = %v, %v; want nil, nil", val, err)
+	}
+}
+
+func TestProgram_EvalValue(t *testing.T) {
+	prog, err := Compile("(count + 2) * 10")
+	if err != nil {
+		t.Fatalf("Compile() error: %v", err)
+	}
+	got, err := prog.EvalValue(map[string]any{"count": 3})
+	if err != nil {
+		t.Fatalf("EvalValue() error: %v", err)
+	}
+	if got != int64(50) {
+		t.Errorf("EvalValue() = %v (%T), want 50", got, got)
+	}
+
+	if _, err := prog.EvalValue(map[string]any{"count": "x"}); !errors.Is(err, ErrNonNumeric) {
+		t.Errorf("EvalValue() error = %v, want ErrNonNumeric", err)
+	}
+}
+
+func TestEvaluator_Compile(t *testing.T) {
+	e := New(
+		WithCustomOperator("starts with", func(left, right any) bool {
+			return strings.HasPrefix(fmt.Sprint(left), fmt.Sprint(right))
+		}),
+		WithStrictVariables(),
+	)
+
+	prog, err := e.Compile("name starts with 'flow'")
+	if err != nil {
+		t.Fatalf("Compile() error: %v", err)
+	}
+	got, err := prog.Eval(map[string]any{"name": "flowgraph"})
+	if err != nil || !got {
+		t.Errorf("Eval() = %v, %v; want true, nil", got, err)
+	}
+
+	// Strict mode carries over to the program
+	if _, err := prog.Eval(map[string]any{}); !errors.Is(err, ErrUndefinedVariable) {
+		t.Errorf("Eval() error = %v, want ErrUndefinedVariable", err)
+	}
+}
+
+func TestProgram_ConcurrentEval(t *testing.T) {
+	prog, err := Compile("n * 2 > 50 or n < 10")
+	if err != nil {
+		t.Fatalf("Compile() error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			got, err := prog.Eval(map[string]any{"n": n})
+			if err != nil {
+				errs <- err
+				return
+			}
+			if want := n*2 > 50 || n < 10; got != want {
+				errs <- fmt.Errorf("Eval(n=%d) = %v, want %v", n, got, want)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
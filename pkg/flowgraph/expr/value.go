@@ -3,11 +3,19 @@ package expr
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
 )
 
 // Resolve resolves a value from variables or returns a literal.
 // It handles quoted strings, booleans, null, numbers, and variable lookups.
+//
+// Dotted names such as "user.profile.age" walk nested maps, and numeric
+// segments index slices ("items.0.name"). A key containing dots is matched
+// directly first. If the first segment exists but the rest of the path does
+// not, the result is nil; if the first segment is missing too, the name is
+// returned as a string literal.
 func Resolve(s string, vars map[string]any) any {
 	s = strings.TrimSpace(s)
 	if s == "" {
@@ -47,16 +55,68 @@ func Resolve(s string, vars map[string]any) any {
 	}
 
 	// Check for variable in vars map
-	if vars != nil {
-		if val, ok := vars[s]; ok {
-			return val
-		}
+	if val, found, rooted := lookupVar(vars, s); found || rooted {
+		return val
 	}
 
 	// Return as string literal (unquoted identifier not in vars)
 	return s
 }
 
+// lookupVar looks up name in vars, walking nested values for dotted names.
+// found reports whether the full path exists. rooted reports whether the
+// first segment exists, so callers can tell a missing nested field (nil)
+// from an unknown name.
+func lookupVar(vars map[string]any, name string) (val any, found, rooted bool) {
+	if val, ok := vars[name]; ok {
+		return val, true, true
+	}
+
+	segments := strings.Split(name, ".")
+	if len(segments) < 2 {
+		return nil, false, false
+	}
+	current, ok := vars[segments[0]]
+	if !ok {
+		return nil, false, false
+	}
+	for _, seg := range segments[1:] {
+		if current, ok = field(current, seg); !ok {
+			return nil, false, true
+		}
+	}
+	return current, true, true
+}
+
+// field returns the named map entry or slice element of v.
+func field(v any, name string) (any, bool) {
+	if m, ok := v.(map[string]any); ok {
+		val, ok := m[name]
+		return val, ok
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		elem := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+		if !elem.IsValid() {
+			return nil, false
+		}
+		return elem.Interface(), true
+	case reflect.Slice, reflect.Array:
+		i, err := strconv.Atoi(name)
+		if err != nil || i < 0 || i >= rv.Len() {
+			return nil, false
+		}
+		return rv.Index(i).Interface(), true
+	default:
+		return nil, false
+	}
+}
+
 // IsTruthy returns whether a value is truthy.
 // nil is false, bools return their value, empty strings are false,
 // zero numbers are false, everything else is true.
@@ -0,0 +1,109 @@
+package flowgraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/expr"
+)
+
+// ExprRoute is one branch of a conditional edge added with
+// AddConditionalEdgeExpr: when the When expression holds, execution
+// moves to To.
+type ExprRoute struct {
+	// When is an expr condition evaluated against the state.
+	When string
+
+	// To is the target node ID or END.
+	To string
+}
+
+// AddConditionalEdgeExpr adds a conditional edge whose routes are expr
+// conditions rather than a RouterFunc. Routes are tried in order and the
+// first whose condition holds wins; if none holds, execution moves to
+// otherwise. An empty otherwise makes the run fail with a *RouterError
+// when no route matches.
+//
+// Conditions see the state as it encodes to JSON: the fields of an
+// object state are top-level variables, named by their json tags, and
+// any other state is the variable "state". A condition that fails to
+// evaluate is logged and treated as false.
+//
+// Example:
+//
+//	graph.AddConditionalEdgeExpr("review", []flowgraph.ExprRoute{
+//	    {When: "score >= 8", To: "publish"},
+//	    {When: "attempts < 3", To: "revise"},
+//	}, flowgraph.END)
+//
+// Panics if routes is empty, a route has an empty target, or a condition
+// does not compile.
+func (g *Graph[S]) AddConditionalEdgeExpr(from string, routes []ExprRoute, otherwise string) *Graph[S] {
+	router, err := exprRouter(routes, otherwise, stateVars[S])
+	if err != nil {
+		panic("flowgraph: " + err.Error())
+	}
+	return g.AddConditionalEdge(from, router)
+}
+
+// exprRouter compiles routes into a RouterFunc whose conditions are
+// evaluated against the variables vars returns for the state.
+func exprRouter[S any](routes []ExprRoute, otherwise string, vars func(S) (map[string]any, error)) (RouterFunc[S], error) {
+	if len(routes) == 0 {
+		return nil, errors.New("conditional edge needs at least one route")
+	}
+
+	programs := make([]*expr.Program, len(routes))
+	for i, route := range routes {
+		if route.To == "" {
+			return nil, fmt.Errorf("route %d: target is required", i)
+		}
+		program, err := expr.Compile(route.When)
+		if err != nil {
+			return nil, fmt.Errorf("route %d: compile condition %q: %w", i, route.When, err)
+		}
+		programs[i] = program
+	}
+
+	return func(ctx Context, state S) string {
+		env, err := vars(state)
+		if err != nil {
+			ctx.Logger().Error("encode state for route conditions", "error", err)
+			return otherwise
+		}
+
+		for i, program := range programs {
+			ok, err := program.Eval(env)
+			if err != nil {
+				ctx.Logger().Error("route condition failed",
+					"condition", program.String(),
+					"error", err,
+				)
+				continue
+			}
+			if ok {
+				return routes[i].To
+			}
+		}
+		return otherwise
+	}, nil
+}
+
+// stateVars converts state to expr variables via its JSON encoding.
+func stateVars[S any](state S) (map[string]any, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+
+	if vars, ok := decoded.(map[string]any); ok {
+		return vars, nil
+	}
+	return map[string]any{"state": decoded}, nil
+}
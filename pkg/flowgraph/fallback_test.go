@@ -0,0 +1,100 @@
+package flowgraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAddNodeWithFallback_FallbackSucceeds tests that a failing primary is
+// replaced by its fallback, which receives the original input state, and
+// the run continues.
+func TestAddNodeWithFallback_FallbackSucceeds(t *testing.T) {
+	var fallbackInput Counter
+	compiled, err := NewGraph[Counter]().
+		AddNodeWithFallback("work",
+			func(ctx Context, s Counter) (Counter, error) {
+				s.Value += 100 // Discarded on failure
+				return s, errors.New("primary down")
+			},
+			func(ctx Context, s Counter) (Counter, error) {
+				fallbackInput = s
+				s.Value += 10
+				return s, nil
+			}).
+		AddNode("after", increment).
+		AddEdge("work", "after").
+		AddEdge("after", END).
+		SetEntry("work").
+		Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), Counter{Value: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, Counter{Value: 1}, fallbackInput)
+	assert.Equal(t, 12, result.Value)
+}
+
+// TestAddNodeWithFallback_PrimarySucceeds tests that the fallback does not
+// run when the primary succeeds.
+func TestAddNodeWithFallback_PrimarySucceeds(t *testing.T) {
+	fallbackRan := false
+	compiled, err := NewGraph[Counter]().
+		AddNodeWithFallback("work", increment, func(ctx Context, s Counter) (Counter, error) {
+			fallbackRan = true
+			return s, nil
+		}).
+		AddEdge("work", END).
+		SetEntry("work").
+		Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), Counter{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Value)
+	assert.False(t, fallbackRan)
+}
+
+// TestAddNodeWithFallback_BothFail tests that the run fails with a NodeError
+// carrying both causes when primary and fallback fail.
+func TestAddNodeWithFallback_BothFail(t *testing.T) {
+	primaryErr := errors.New("primary down")
+	fallbackErr := errors.New("fallback down")
+	afterRan := false
+	compiled, err := NewGraph[State]().
+		AddNodeWithFallback("work", makeFailingNode(primaryErr), makeFailingNode(fallbackErr)).
+		AddNode("after", func(ctx Context, s State) (State, error) {
+			afterRan = true
+			return s, nil
+		}).
+		AddEdge("work", "after").
+		AddEdge("after", END).
+		SetEntry("work").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{})
+
+	var nodeErr *NodeError
+	require.ErrorAs(t, err, &nodeErr)
+	assert.Equal(t, "work", nodeErr.NodeID)
+	assert.ErrorIs(t, err, primaryErr)
+	assert.ErrorIs(t, err, fallbackErr)
+	assert.Contains(t, err.Error(), "primary: primary down")
+	assert.Contains(t, err.Error(), "fallback: fallback down")
+	assert.False(t, afterRan)
+}
+
+// TestAddNodeWithFallback_NilFunctions tests that nil functions panic.
+func TestAddNodeWithFallback_NilFunctions(t *testing.T) {
+	assert.Panics(t, func() {
+		NewGraph[Counter]().AddNodeWithFallback("work", nil, increment)
+	})
+	assert.Panics(t, func() {
+		NewGraph[Counter]().AddNodeWithFallback("work", increment, nil)
+	})
+}
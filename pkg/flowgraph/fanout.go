@@ -0,0 +1,148 @@
+package flowgraph
+
+import (
+	"fmt"
+	"runtime/debug"
+	"slices"
+)
+
+// AddDynamicFanOut adds a fan-out whose branches are chosen at runtime.
+// After node from executes, fn returns the IDs of the nodes to run as
+// parallel branches. Branches execute and merge exactly like a static
+// fork/join: state is cloned per branch, ForkJoinConfig (including
+// MaxConcurrency) and the BranchHook apply, and branch states are merged
+// with ParallelState.Merge.
+// Returns the graph for method chaining.
+//
+// AddDynamicFanOut does not name the join node: add exactly one static
+// edge, AddEdge(from, join), and its target is the join. Every branch must
+// be able to reach it; it is where execution continues once all branches
+// complete. If fn returns an empty slice, no branches run and execution
+// continues at the join directly.
+//
+// A fan-out node cannot also have a conditional edge. Compile checks both
+// conditions and fails with ErrInvalidFanOut if either is violated.
+//
+// At runtime, returning an unknown node, END, a duplicate, or a node that
+// cannot reach the join fails the run with a *RouterError wrapping
+// ErrInvalidFanOutTarget.
+//
+// Panics if fn is nil or from already has a fan-out.
+//
+// Example:
+//
+//	graph.AddNode("dispatch", dispatch).
+//	    AddNode("summarize", summarize).
+//	    AddNode("translate", translate).
+//	    AddNode("collect", collect).
+//	    AddDynamicFanOut("dispatch", func(ctx flowgraph.Context, s State) []string {
+//	        return s.RequestedSteps // e.g. ["summarize", "translate"]
+//	    }).
+//	    AddEdge("dispatch", "collect"). // join node
+//	    AddEdge("summarize", "collect").
+//	    AddEdge("translate", "collect")
+func (g *Graph[S]) AddDynamicFanOut(from string, fn FanOutFunc[S]) *Graph[S] {
+	if fn == nil {
+		panic("flowgraph: fan-out function cannot be nil")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, exists := g.fanOuts[from]; exists {
+		panic(fmt.Sprintf("flowgraph: duplicate fan-out from node: %s", from))
+	}
+
+	g.fanOuts[from] = fn
+	return g
+}
+
+// IsDynamicFanOut returns true if the node selects parallel branches at runtime.
+func (cg *CompiledGraph[S]) IsDynamicFanOut(id string) bool {
+	_, exists := cg.fanOuts[id]
+	return exists
+}
+
+// resolveFanOut evaluates a dynamic fan-out and returns the fork to execute.
+// The returned ForkNode has no branches if the fan-out selected none.
+func (cg *CompiledGraph[S]) resolveFanOut(ctx Context, from string, fn FanOutFunc[S], state S) (fork *ForkNode, err error) {
+	join := cg.edges[from][0]
+
+	fanOutCtx := ctx
+	if ec, ok := ctx.(*executionContext); ok {
+		fanOutCtx = ec.withNodeID(from)
+	}
+
+	// Panic recovery for fan-out functions
+	defer func() {
+		if r := recover(); r != nil {
+			fork = nil
+			err = &PanicError{
+				NodeID: from,
+				Value:  r,
+				Stack:  string(debug.Stack()),
+			}
+		}
+	}()
+
+	targets := fn(fanOutCtx, state)
+
+	seen := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		var reason string
+		switch {
+		case target == "" || target == END:
+			reason = "not a node"
+		case seen[target]:
+			reason = "duplicate branch"
+		case !cg.HasNode(target):
+			reason = "node not found"
+		case target == join:
+			reason = "branch is the join node"
+		case !cg.canReach(target, join):
+			reason = fmt.Sprintf("cannot reach join node %s", join)
+		}
+		if reason != "" {
+			return nil, &RouterError{
+				FromNode: from,
+				Returned: target,
+				Err:      fmt.Errorf("%w: %s", ErrInvalidFanOutTarget, reason),
+			}
+		}
+		seen[target] = true
+	}
+
+	return &ForkNode{
+		NodeID:     from,
+		Branches:   slices.Clone(targets),
+		JoinNodeID: join,
+	}, nil
+}
+
+// canReach reports whether target may be reached from start.
+// Nodes with conditional edges or fan-outs are assumed to reach any node.
+func (cg *CompiledGraph[S]) canReach(start, target string) bool {
+	visited := map[string]bool{start: true}
+	queue := []string{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == target {
+			return true
+		}
+		if cg.isConditional[current] || cg.IsDynamicFanOut(current) {
+			return true
+		}
+
+		for _, next := range cg.edges[current] {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,157 @@
+package flowgraph
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildFanOutGraph builds dispatch -> (dynamic workers) -> collect -> END.
+// The dispatcher selects the workers listed in targets. The "stray" node
+// bypasses collect and cannot be used as a branch.
+func buildFanOutGraph(targets []string, worker NodeFunc[TestState]) *Graph[TestState] {
+	graph := NewGraph[TestState]().
+		AddNode("dispatch", passthrough[TestState]).
+		AddNode("collect", func(ctx Context, s TestState) (TestState, error) {
+			s.Values["collected"] = 1
+			return s, nil
+		}).
+		AddDynamicFanOut("dispatch", func(ctx Context, s TestState) []string {
+			return targets
+		}).
+		AddEdge("dispatch", "collect").
+		AddEdge("collect", END).
+		AddNode("stray", passthrough[TestState]).
+		AddEdge("stray", END).
+		SetEntry("dispatch")
+
+	for _, id := range []string{"w1", "w2", "w3"} {
+		graph.AddNode(id, worker).AddEdge(id, "collect")
+	}
+	return graph
+}
+
+// recordWorker sets Values["done"] so the merge records which branches ran.
+func recordWorker(ctx Context, s TestState) (TestState, error) {
+	s.Values["done"] = 1
+	return s, nil
+}
+
+// TestAddDynamicFanOut_RunsSelectedBranches tests that only selected targets run and merge.
+func TestAddDynamicFanOut_RunsSelectedBranches(t *testing.T) {
+	compiled, err := buildFanOutGraph([]string{"w1", "w3"}, recordWorker).Compile()
+	require.NoError(t, err)
+	assert.True(t, compiled.IsDynamicFanOut("dispatch"))
+
+	result, err := compiled.Run(testCtx(), TestState{Values: map[string]int{}})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		"w1_done":   1,
+		"w3_done":   1,
+		"collected": 1,
+	}, result.Values)
+}
+
+// TestAddDynamicFanOut_EmptyRoutesToJoin tests that no targets skips straight to the join.
+func TestAddDynamicFanOut_EmptyRoutesToJoin(t *testing.T) {
+	var workerRuns atomic.Int32
+	worker := func(ctx Context, s TestState) (TestState, error) {
+		workerRuns.Add(1)
+		return s, nil
+	}
+
+	compiled, err := buildFanOutGraph(nil, worker).Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), TestState{Values: map[string]int{}})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), workerRuns.Load())
+	assert.Equal(t, 1, result.Values["collected"])
+}
+
+// TestAddDynamicFanOut_MaxConcurrency tests that ForkJoinConfig.MaxConcurrency applies.
+func TestAddDynamicFanOut_MaxConcurrency(t *testing.T) {
+	var executing, maxConcurrent atomic.Int32
+	worker := func(ctx Context, s TestState) (TestState, error) {
+		current := executing.Add(1)
+		for {
+			seen := maxConcurrent.Load()
+			if current <= seen || maxConcurrent.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+		executing.Add(-1)
+		return s, nil
+	}
+
+	graph := buildFanOutGraph([]string{"w1", "w2", "w3"}, worker).
+		SetForkJoinConfig(ForkJoinConfig{MaxConcurrency: 1})
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), TestState{Values: map[string]int{}})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), maxConcurrent.Load())
+}
+
+// TestAddDynamicFanOut_InvalidTarget tests runtime validation of fan-out results.
+func TestAddDynamicFanOut_InvalidTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []string
+	}{
+		{name: "unknown node", targets: []string{"w1", "missing"}},
+		{name: "duplicate", targets: []string{"w1", "w1"}},
+		{name: "END", targets: []string{END}},
+		{name: "join node", targets: []string{"collect"}},
+		{name: "cannot reach join", targets: []string{"w1", "stray"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiled, err := buildFanOutGraph(tt.targets, recordWorker).Compile()
+			require.NoError(t, err)
+
+			_, err = compiled.Run(testCtx(), TestState{Values: map[string]int{}})
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrInvalidFanOutTarget)
+
+			var routerErr *RouterError
+			require.ErrorAs(t, err, &routerErr)
+			assert.Equal(t, "dispatch", routerErr.FromNode)
+		})
+	}
+}
+
+// TestAddDynamicFanOut_CompileRequiresJoinEdge tests that a fan-out needs exactly one static edge.
+func TestAddDynamicFanOut_CompileRequiresJoinEdge(t *testing.T) {
+	graph := NewGraph[TestState]().
+		AddNode("dispatch", passthrough[TestState]).
+		AddNode("w1", passthrough[TestState]).
+		AddDynamicFanOut("dispatch", func(ctx Context, s TestState) []string {
+			return []string{"w1"}
+		}).
+		AddEdge("w1", END).
+		SetEntry("dispatch")
+
+	_, err := graph.Compile()
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidFanOut)
+	assert.Contains(t, err.Error(), `AddEdge("dispatch", join)`)
+}
+
+// TestAddDynamicFanOut_NilPanics tests that a nil fan-out function panics.
+func TestAddDynamicFanOut_NilPanics(t *testing.T) {
+	assert.PanicsWithValue(t, "flowgraph: fan-out function cannot be nil", func() {
+		NewGraph[TestState]().AddDynamicFanOut("dispatch", nil)
+	})
+}
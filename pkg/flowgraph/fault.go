@@ -0,0 +1,65 @@
+package flowgraph
+
+import (
+	"time"
+)
+
+// Fault describes a failure injected into a node by WithFaultInjection.
+type Fault struct {
+	// Probability is the chance, in [0, 1], that the fault fires on each
+	// attempt of the node. Drawn from Context.Rand, so a run repeated with
+	// the same run ID or seed injects the same faults.
+	Probability float64
+
+	// Effect runs before the node when the fault fires.
+	// Create one with InjectError, InjectDelay, or InjectPanic.
+	Effect FaultEffect
+}
+
+// FaultEffect is the action of a fault. Returning an error fails the node
+// attempt with that error without running the node; returning nil runs the
+// node as usual.
+type FaultEffect func(ctx Context) error
+
+// InjectError returns a fault effect that fails the node with err.
+func InjectError(err error) FaultEffect {
+	return func(Context) error {
+		return err
+	}
+}
+
+// InjectDelay returns a fault effect that waits d before the node runs.
+// If the context is cancelled or times out first, the node fails with the
+// context error.
+func InjectDelay(d time.Duration) FaultEffect {
+	return func(ctx Context) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// InjectPanic returns a fault effect that panics with v, as if the node
+// had panicked.
+func InjectPanic(v any) FaultEffect {
+	return func(Context) error {
+		panic(v)
+	}
+}
+
+// withFault wraps fn so that fault may fire before each call.
+func withFault[S any](fn NodeFunc[S], fault Fault) NodeFunc[S] {
+	return func(ctx Context, state S) (S, error) {
+		if fault.Probability >= 1 || ctx.Rand().Float64() < fault.Probability {
+			if err := fault.Effect(ctx); err != nil {
+				return state, err
+			}
+		}
+		return fn(ctx, state)
+	}
+}
@@ -0,0 +1,148 @@
+package flowgraph
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithFaultInjection_Error tests that an injected error fails the node without running it.
+func TestWithFaultInjection_Error(t *testing.T) {
+	errInjected := errors.New("injected")
+	ran := false
+	node := func(ctx Context, s Counter) (Counter, error) {
+		ran = true
+		return s, nil
+	}
+
+	compiled := linearCounterGraph(t, 1, node)
+	_, err := compiled.Run(testCtx(), Counter{},
+		WithFaultInjection(map[string]Fault{"a": {Probability: 1, Effect: InjectError(errInjected)}}))
+
+	var nodeErr *NodeError
+	require.ErrorAs(t, err, &nodeErr)
+	assert.Equal(t, "a", nodeErr.NodeID)
+	assert.ErrorIs(t, err, errInjected)
+	assert.False(t, ran)
+}
+
+// TestWithFaultInjection_TriggersRetry tests that injected transient errors go through node retry.
+func TestWithFaultInjection_TriggersRetry(t *testing.T) {
+	var attempts []int
+	node := func(ctx Context, s Counter) (Counter, error) {
+		attempts = append(attempts, ctx.Attempt())
+		s.Value++
+		return s, nil
+	}
+
+	graph := NewGraph[Counter]().
+		AddNode("fetch", node, WithNodeRetry(fastRetry)).
+		AddEdge("fetch", END).
+		SetEntry("fetch")
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	flaky := fgerrors.Transient(errors.New("upstream down"), "fetch")
+	_, err = compiled.Run(testCtx(), Counter{},
+		WithFaultInjection(map[string]Fault{"fetch": {Probability: 1, Effect: InjectError(flaky)}}))
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, flaky))
+	assert.Empty(t, attempts, "node must not run while the fault fires")
+}
+
+// TestWithFaultInjection_Panic tests that an injected panic is recovered like a node panic.
+func TestWithFaultInjection_Panic(t *testing.T) {
+	compiled := linearCounterGraph(t, 2, increment)
+
+	_, err := compiled.Run(testCtx(), Counter{},
+		WithFaultInjection(map[string]Fault{"b": {Probability: 1, Effect: InjectPanic("chaos")}}))
+
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "b", panicErr.NodeID)
+	assert.Equal(t, "chaos", panicErr.Value)
+}
+
+// TestWithFaultInjection_Delay tests that an injected delay is observed before the node runs.
+func TestWithFaultInjection_Delay(t *testing.T) {
+	compiled := linearCounterGraph(t, 1, increment)
+
+	start := time.Now()
+	result, err := compiled.Run(testCtx(), Counter{},
+		WithFaultInjection(map[string]Fault{"a": {Probability: 1, Effect: InjectDelay(30 * time.Millisecond)}}))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Value)
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+}
+
+// TestWithFaultInjection_DelayHitsNodeTimeout tests that injected delays count toward node timeouts.
+func TestWithFaultInjection_DelayHitsNodeTimeout(t *testing.T) {
+	compiled := linearCounterGraph(t, 1, increment)
+
+	_, err := compiled.Run(testCtx(), Counter{},
+		WithNodeTimeout("a", 10*time.Millisecond),
+		WithFaultInjection(map[string]Fault{"a": {Probability: 1, Effect: InjectDelay(time.Second)}}))
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// TestWithFaultInjection_Deterministic tests that the same seed injects the same faults.
+func TestWithFaultInjection_Deterministic(t *testing.T) {
+	compiled := linearCounterGraph(t, 6, increment)
+	faults := map[string]Fault{}
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		faults[id] = Fault{Probability: 0.5, Effect: InjectError(errors.New(id))}
+	}
+
+	outcome := func(seed uint64) string {
+		ctx := NewContext(context.Background(), WithRandSeed(seed))
+		_, err := compiled.Run(ctx, Counter{}, WithFaultInjection(faults))
+		if err == nil {
+			return "ok"
+		}
+		return err.Error()
+	}
+
+	for seed := uint64(0); seed < 5; seed++ {
+		assert.Equal(t, outcome(seed), outcome(seed), "seed %d", seed)
+	}
+}
+
+// TestWithFaultInjection_ZeroProbability tests that a fault with probability 0 never fires.
+func TestWithFaultInjection_ZeroProbability(t *testing.T) {
+	compiled := linearCounterGraph(t, 3, increment)
+
+	result, err := compiled.Run(testCtx(), Counter{},
+		WithFaultInjection(map[string]Fault{"b": {Probability: 0, Effect: InjectPanic("never")}}))
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Value)
+}
+
+// TestWithFaultInjection_Panics tests that invalid faults panic.
+func TestWithFaultInjection_Panics(t *testing.T) {
+	assert.Panics(t, func() {
+		WithFaultInjection(map[string]Fault{"a": {Probability: 1.5, Effect: InjectError(errors.New("x"))}})
+	})
+	assert.Panics(t, func() {
+		WithFaultInjection(map[string]Fault{"a": {Probability: 1}})
+	})
+}
+
+// TestContext_RandSeededFromRunID tests that Rand repeats for the same run ID.
+func TestContext_RandSeededFromRunID(t *testing.T) {
+	a := NewContext(context.Background(), WithContextRunID("run-1"))
+	b := NewContext(context.Background(), WithContextRunID("run-1"))
+	c := NewContext(context.Background(), WithContextRunID("run-2"))
+
+	first := a.Rand().Uint64()
+	assert.Equal(t, first, b.Rand().Uint64())
+	assert.NotEqual(t, first, c.Rand().Uint64())
+}
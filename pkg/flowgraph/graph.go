@@ -1,6 +1,7 @@
 package flowgraph
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -36,11 +37,19 @@ import (
 type Graph[S any] struct {
 	mu               sync.RWMutex
 	nodes            map[string]NodeFunc[S]
+	nodeConfigs      map[string]nodeConfig
+	dynamicNodes     map[string]string // nodeID -> registry factory key
+	subgraphs        map[string]*CompiledGraph[S]
 	edges            map[string][]string
 	conditionalEdges map[string]RouterFunc[S]
+	fanOuts          map[string]FanOutFunc[S]
+	compensations    map[string]CompensationFunc[S]
+	errorEdges       map[string]string
 	entryPoint       string
+	entrySelector    EntrySelectorFunc[S]
 	branchHook       BranchHook[S]
 	forkJoinConfig   ForkJoinConfig
+	middleware       []NodeMiddleware[S]
 }
 
 // NewGraph creates a new graph builder for state type S.
@@ -48,49 +57,165 @@ type Graph[S any] struct {
 func NewGraph[S any]() *Graph[S] {
 	return &Graph[S]{
 		nodes:            make(map[string]NodeFunc[S]),
+		nodeConfigs:      make(map[string]nodeConfig),
+		dynamicNodes:     make(map[string]string),
+		subgraphs:        make(map[string]*CompiledGraph[S]),
 		edges:            make(map[string][]string),
 		conditionalEdges: make(map[string]RouterFunc[S]),
+		fanOuts:          make(map[string]FanOutFunc[S]),
+		compensations:    make(map[string]CompensationFunc[S]),
+		errorEdges:       make(map[string]string),
 	}
 }
 
 // AddNode adds a named node to the graph.
+// Optional NodeOptions configure per-node behavior such as retries.
 // Returns the graph for method chaining.
 //
 // Panics if:
 //   - id is empty
 //   - id is the reserved word "END" or "__end__" (case-insensitive)
+//   - id is the reserved word "__start__" (case-insensitive)
 //   - id contains whitespace (space, tab, newline)
 //   - fn is nil
 //   - id already exists in the graph
-func (g *Graph[S]) AddNode(id string, fn NodeFunc[S]) *Graph[S] {
+func (g *Graph[S]) AddNode(id string, fn NodeFunc[S], opts ...NodeOption) *Graph[S] {
 	// Validation (panics per ADR-007)
-	if id == "" {
-		panic("flowgraph: node ID cannot be empty")
-	}
+	validateNodeID(id)
 
-	// Check reserved words (case-insensitive)
-	idLower := strings.ToLower(id)
-	if idLower == "end" || idLower == "__end__" {
-		panic("flowgraph: node ID cannot be reserved word 'END'")
+	if fn == nil {
+		panic("flowgraph: node function cannot be nil")
 	}
 
-	if strings.ContainsAny(id, " \t\n\r") {
-		panic("flowgraph: node ID cannot contain whitespace")
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.addNodeLocked(id, fn, opts)
+	return g
+}
+
+// AddDynamicNode adds a node whose function is resolved at execution time
+// from the node registry passed to Run via WithNodeRegistry.
+// The factoryKey is the registry key to look up.
+// Returns the graph for method chaining.
+//
+// Resolution happens each time the node executes, so registry updates take
+// effect on the next execution. If no registry is configured or the key is
+// absent, the node fails with a *NodeError wrapping ErrNodeRegistryMissing
+// or ErrNodeFactoryNotFound.
+//
+// Panics under the same conditions as AddNode, or if factoryKey is empty.
+//
+// Example:
+//
+//	nodes := registry.New[string, flowgraph.NodeFunc[MyState]]()
+//	nodes.Register("summarize-v2", summarizeV2)
+//
+//	graph.AddDynamicNode("summarize", "summarize-v2")
+//	result, err := compiled.Run(ctx, state, flowgraph.WithNodeRegistry(nodes))
+func (g *Graph[S]) AddDynamicNode(id, factoryKey string, opts ...NodeOption) *Graph[S] {
+	validateNodeID(id)
+
+	if factoryKey == "" {
+		panic("flowgraph: factory key cannot be empty")
 	}
 
-	if fn == nil {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.addNodeLocked(id, unresolvedDynamicNode[S], opts)
+	g.dynamicNodes[id] = factoryKey
+	return g
+}
+
+// AddNodeWithFallback adds a node that runs fallback when primary fails.
+// Returns the graph for method chaining.
+//
+// If primary returns an error, fallback runs with the state the node
+// received, not the state primary returned. The run fails only if fallback
+// also errors, with a *NodeError whose cause joins both errors so that
+// errors.Is and errors.As match either. Fallback is skipped if the context
+// was cancelled, and panics are not caught by the fallback.
+//
+// Node options apply to the node as a whole: under WithNodeRetry, each
+// attempt runs primary and then, if needed, fallback.
+//
+// Panics under the same conditions as AddNode, or if fallback is nil.
+//
+// Example:
+//
+//	graph.AddNodeWithFallback("summarize", summarizeWithLLM, summarizeHeuristic)
+func (g *Graph[S]) AddNodeWithFallback(id string, primary, fallback NodeFunc[S], opts ...NodeOption) *Graph[S] {
+	validateNodeID(id)
+
+	if primary == nil || fallback == nil {
 		panic("flowgraph: node function cannot be nil")
 	}
 
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	g.addNodeLocked(id, withFallback(primary, fallback), opts)
+	return g
+}
+
+// withFallback returns a node function that runs fallback on the original
+// input state if primary fails.
+func withFallback[S any](primary, fallback NodeFunc[S]) NodeFunc[S] {
+	return func(ctx Context, state S) (S, error) {
+		result, err := primary(ctx, state)
+		if err == nil {
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return result, err
+		}
+
+		ctx.Logger().Warn("node failed, running fallback", "error", err)
+		fallbackResult, fallbackErr := fallback(ctx, state)
+		if fallbackErr != nil {
+			return fallbackResult, errors.Join(
+				fmt.Errorf("primary: %w", err),
+				fmt.Errorf("fallback: %w", fallbackErr),
+			)
+		}
+		return fallbackResult, nil
+	}
+}
+
+// addNodeLocked registers a node and its options (must hold lock).
+func (g *Graph[S]) addNodeLocked(id string, fn NodeFunc[S], opts []NodeOption) {
 	if _, exists := g.nodes[id]; exists {
 		panic(fmt.Sprintf("flowgraph: duplicate node ID: %s", id))
 	}
 
+	var cfg nodeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	g.nodes[id] = fn
-	return g
+	g.nodeConfigs[id] = cfg
+}
+
+// validateNodeID panics if id is not a valid node identifier.
+func validateNodeID(id string) {
+	if id == "" {
+		panic("flowgraph: node ID cannot be empty")
+	}
+
+	// Check reserved words (case-insensitive)
+	idLower := strings.ToLower(id)
+	if idLower == "end" || idLower == "__end__" {
+		panic("flowgraph: node ID cannot be reserved word 'END'")
+	}
+	if idLower == START {
+		panic("flowgraph: node ID cannot be reserved word 'START'")
+	}
+
+	if strings.ContainsAny(id, " \t\n\r") {
+		panic("flowgraph: node ID cannot contain whitespace")
+	}
 }
 
 // AddEdge adds an unconditional edge from one node to another.
@@ -141,6 +266,39 @@ func (g *Graph[S]) SetEntry(id string) *Graph[S] {
 	return g
 }
 
+// SetEntrySelector designates a function that picks the entry node
+// from the initial state at Run time, instead of a fixed entry point.
+// Returns the graph for method chaining.
+//
+// Use this when the starting node depends on the input, e.g. resuming a
+// partially-completed workflow from different points without wrapping
+// the graph in an initial router node.
+//
+// SetEntry and SetEntrySelector are mutually exclusive; setting both
+// causes Compile() to fail with ErrEntryConflict.
+//
+// Panics if selector is nil.
+//
+// Example:
+//
+//	graph.SetEntrySelector(func(s MyState) string {
+//	    if s.Draft != "" {
+//	        return "review"
+//	    }
+//	    return "draft"
+//	})
+func (g *Graph[S]) SetEntrySelector(selector EntrySelectorFunc[S]) *Graph[S] {
+	if selector == nil {
+		panic("flowgraph: entry selector cannot be nil")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.entrySelector = selector
+	return g
+}
+
 // SetBranchHook sets the lifecycle hook for parallel branch execution.
 // The hook is called during fork/join operations to allow custom setup,
 // validation, and cleanup.
@@ -169,6 +327,7 @@ func (g *Graph[S]) SetBranchHook(hook BranchHook[S]) *Graph[S] {
 //   - MaxConcurrency: 0 (unlimited)
 //   - FailFast: false (wait for all branches)
 //   - MergeTimeout: 0 (no timeout)
+//   - ContinueOnError: false (any branch failure fails the fork)
 //
 // Example:
 //
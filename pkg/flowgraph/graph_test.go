@@ -198,6 +198,22 @@ func TestGraph_SetEntry_CanBeOverwritten(t *testing.T) {
 	assert.Equal(t, "second", graph.entryPoint)
 }
 
+// TestGraph_SetEntrySelector tests entry selector setting.
+func TestGraph_SetEntrySelector(t *testing.T) {
+	graph := NewGraph[Counter]()
+	result := graph.SetEntrySelector(func(s Counter) string { return "start" })
+
+	assert.Same(t, graph, result)
+	assert.NotNil(t, graph.entrySelector)
+}
+
+// TestGraph_SetEntrySelector_Nil_Panics tests that nil selector panics.
+func TestGraph_SetEntrySelector_Nil_Panics(t *testing.T) {
+	assert.PanicsWithValue(t, "flowgraph: entry selector cannot be nil", func() {
+		NewGraph[Counter]().SetEntrySelector(nil)
+	})
+}
+
 // TestGraph_FluentAPI tests full fluent API usage.
 func TestGraph_FluentAPI(t *testing.T) {
 	graph := NewGraph[Counter]().
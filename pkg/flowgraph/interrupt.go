@@ -0,0 +1,74 @@
+package flowgraph
+
+import "errors"
+
+// interruptSignal is the error returned by Interrupt.
+type interruptSignal struct {
+	reason string
+}
+
+func (i *interruptSignal) Error() string {
+	return "interrupt: " + i.reason
+}
+
+// Interrupt returns an error that pauses the run at the current node,
+// typically to wait for a human approval or other external input.
+//
+// Run does not treat an interrupt as a failure: error edges and
+// graph compensation are skipped. Instead the run checkpoints the
+// state the node received (if checkpointing is enabled) and returns an
+// *InterruptError. Resume executes the interrupted node again, so the node
+// should check whether its input has arrived before interrupting; supply
+// the input with WithStateOverride or WithBeforeResume.
+//
+// Interrupts are honored for nodes on the main execution path. An
+// interrupt from a fork branch fails the branch like any other error.
+//
+// Example:
+//
+//	func approve(ctx flowgraph.Context, s State) (State, error) {
+//	    if s.Approval == nil {
+//	        return s, flowgraph.Interrupt("awaiting approval")
+//	    }
+//	    return s, nil
+//	}
+//
+//	_, err := compiled.Run(ctx, state, flowgraph.WithCheckpointing(store), flowgraph.WithRunID("run-123"))
+//	var interrupted *flowgraph.InterruptError
+//	if errors.As(err, &interrupted) {
+//	    // Later, once the approval is in:
+//	    result, err = compiled.Resume(ctx, store, "run-123",
+//	        flowgraph.WithStateOverride(func(s any) any {
+//	            st := s.(State)
+//	            st.Approval = approval
+//	            return st
+//	        }))
+//	}
+func Interrupt(reason string) error {
+	return &interruptSignal{reason: reason}
+}
+
+// interruptReason reports whether err carries an Interrupt, and its reason.
+func interruptReason(err error) (string, bool) {
+	var sig *interruptSignal
+	if errors.As(err, &sig) {
+		return sig.reason, true
+	}
+	return "", false
+}
+
+// interrupt stops the run at nodeID, checkpointing the state the node
+// received so that Resume executes nodeID again.
+func (cg *CompiledGraph[S]) interrupt(ctx Context, cfg *runConfig, nodeID, prevNodeID string, state S, reason string) error {
+	if cfg.checkpointStore != nil {
+		if _, err := cg.saveCheckpointWithObservability(ctx, cfg, nodeID, prevNodeID, state, nodeID); err != nil {
+			return err
+		}
+	}
+	return &InterruptError{
+		RunID:  cfg.runID,
+		NodeID: nodeID,
+		Reason: reason,
+		State:  state,
+	}
+}
@@ -0,0 +1,108 @@
+package flowgraph
+
+import (
+	"testing"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/checkpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// approvalGraph compiles prepare -> approve -> finish, where approve
+// interrupts until State.Done is set.
+func approvalGraph(t *testing.T, tracker *[]string) *Graph[State] {
+	t.Helper()
+	approve := func(ctx Context, s State) (State, error) {
+		*tracker = append(*tracker, "approve")
+		if !s.Done {
+			s.Output = "partial"
+			return s, Interrupt("awaiting approval")
+		}
+		return s, nil
+	}
+	return NewGraph[State]().
+		AddNode("prepare", makeTrackingNode("prepare", tracker)).
+		AddNode("approve", approve).
+		AddNode("finish", makeTrackingNode("finish", tracker)).
+		AddEdge("prepare", "approve").
+		AddEdge("approve", "finish").
+		AddEdge("finish", END).
+		SetEntry("prepare")
+}
+
+// TestInterrupt_CheckpointsAndResumes tests that an interrupted run resumes at the interrupted node.
+func TestInterrupt_CheckpointsAndResumes(t *testing.T) {
+	var tracker []string
+	compiled, err := approvalGraph(t, &tracker).Compile()
+	require.NoError(t, err)
+	store := checkpoint.NewMemoryStore()
+
+	result, err := compiled.Run(testCtx(), State{}, WithCheckpointing(store), WithRunID("run-1"))
+
+	var interrupted *InterruptError
+	require.ErrorAs(t, err, &interrupted)
+	assert.ErrorIs(t, err, ErrInterrupted)
+	assert.Equal(t, "run-1", interrupted.RunID)
+	assert.Equal(t, "approve", interrupted.NodeID)
+	assert.Equal(t, "awaiting approval", interrupted.Reason)
+	assert.Equal(t, []string{"prepare"}, result.Progress)
+	assert.Empty(t, result.Output, "the state the node received is returned")
+	assert.Equal(t, result, interrupted.State)
+
+	// Resuming without input interrupts again
+	_, err = compiled.Resume(testCtx(), store, "run-1")
+	require.ErrorAs(t, err, &interrupted)
+
+	result, err = compiled.Resume(testCtx(), store, "run-1", WithStateOverride(func(s any) any {
+		st := s.(State)
+		st.Done = true
+		return st
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prepare", "approve", "approve", "approve", "finish"}, tracker)
+	assert.Equal(t, []string{"prepare", "finish"}, result.Progress)
+}
+
+// TestInterrupt_WithoutCheckpointing tests that an interrupt stops the run even without a store.
+func TestInterrupt_WithoutCheckpointing(t *testing.T) {
+	var tracker []string
+	compiled, err := approvalGraph(t, &tracker).Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{})
+
+	var interrupted *InterruptError
+	require.ErrorAs(t, err, &interrupted)
+	assert.Equal(t, "approve", interrupted.NodeID)
+	assert.Equal(t, []string{"prepare", "approve"}, tracker)
+}
+
+// TestInterrupt_SkipsErrorEdgesAndCompensation tests that an interrupt is not handled as a failure.
+func TestInterrupt_SkipsErrorEdgesAndCompensation(t *testing.T) {
+	var tracker []string
+	compensated := false
+	compiled, err := NewGraph[State]().
+		AddCompensatingNode("reserve", makeTrackingNode("reserve", &tracker),
+			func(ctx Context, s State) error {
+				compensated = true
+				return nil
+			}).
+		AddNode("approve", func(ctx Context, s State) (State, error) {
+			return s, Interrupt("awaiting approval")
+		}).
+		AddNode("recover", makeTrackingNode("recover", &tracker)).
+		AddEdge("reserve", "approve").
+		AddEdge("approve", END).
+		AddEdge("recover", END).
+		AddErrorEdge("approve", "recover").
+		SetEntry("reserve").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{}, WithGraphCompensation())
+
+	var interrupted *InterruptError
+	require.ErrorAs(t, err, &interrupted)
+	assert.Equal(t, []string{"reserve"}, tracker)
+	assert.False(t, compensated)
+}
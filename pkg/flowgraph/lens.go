@@ -0,0 +1,63 @@
+package flowgraph
+
+// LensNode adapts a node that works on a part of the state into a node
+// for the full state. get projects the sub-state from the state, fn runs on
+// it, and set writes fn's result back into the state. Fields outside the
+// projection are preserved, and if fn fails the state is returned
+// unchanged along with the error.
+//
+// Use LensNode to combine a sub-state node with other wrappers such as
+// AddNodeWithFallback; AddLensNode covers the common case.
+//
+// Panics if get, set, or fn is nil.
+//
+// Example:
+//
+//	summarize := func(ctx flowgraph.Context, doc Document) (Document, error) {
+//	    doc.Summary = summarizeText(doc.Body)
+//	    return doc, nil
+//	}
+//	node := flowgraph.LensNode(
+//	    func(s State) Document { return s.Doc },
+//	    func(s State, doc Document) State { s.Doc = doc; return s },
+//	    summarize)
+func LensNode[S, Sub any](get func(S) Sub, set func(S, Sub) S, fn NodeFunc[Sub]) NodeFunc[S] {
+	if get == nil || set == nil {
+		panic("flowgraph: lens get and set cannot be nil")
+	}
+	if fn == nil {
+		panic("flowgraph: node function cannot be nil")
+	}
+
+	return func(ctx Context, state S) (S, error) {
+		sub, err := fn(ctx, get(state))
+		if err != nil {
+			return state, err
+		}
+		return set(state, sub), nil
+	}
+}
+
+// AddLensNode adds a node that operates only on a projection of the state.
+// See LensNode for how get, set, and fn combine. Because Go methods cannot
+// have type parameters, this is a function taking the graph rather than a
+// method on it.
+//
+// Panics under the same conditions as AddNode and LensNode.
+//
+// Example:
+//
+//	flowgraph.AddLensNode(graph, "summarize",
+//	    func(s State) Document { return s.Doc },
+//	    func(s State, doc Document) State { s.Doc = doc; return s },
+//	    summarize)
+func AddLensNode[S, Sub any](
+	g *Graph[S],
+	id string,
+	get func(S) Sub,
+	set func(S, Sub) S,
+	fn NodeFunc[Sub],
+	opts ...NodeOption,
+) *Graph[S] {
+	return g.AddNode(id, LensNode(get, set, fn), opts...)
+}
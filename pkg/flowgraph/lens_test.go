@@ -0,0 +1,87 @@
+package flowgraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lensState is a state with a sub-state for lens tests.
+type lensState struct {
+	Name    string
+	Profile profile
+	Visits  int
+}
+
+type profile struct {
+	Email    string
+	Verified bool
+}
+
+func getProfile(s lensState) profile { return s.Profile }
+
+func setProfile(s lensState, p profile) lensState {
+	s.Profile = p
+	return s
+}
+
+// TestAddLensNode_MutatesOnlyProjection tests that a lens node sees and changes only its sub-state.
+func TestAddLensNode_MutatesOnlyProjection(t *testing.T) {
+	var seen profile
+	verify := func(ctx Context, p profile) (profile, error) {
+		seen = p
+		p.Verified = true
+		return p, nil
+	}
+	countVisit := func(ctx Context, s lensState) (lensState, error) {
+		s.Visits++
+		return s, nil
+	}
+
+	graph := NewGraph[lensState]()
+	AddLensNode(graph, "verify", getProfile, setProfile, verify)
+	graph.AddNode("visit", countVisit).
+		AddEdge("verify", "visit").
+		AddEdge("visit", END).
+		SetEntry("verify")
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), lensState{Name: "ada", Profile: profile{Email: "ada@example.com"}, Visits: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, profile{Email: "ada@example.com"}, seen)
+	assert.Equal(t, lensState{
+		Name:    "ada",
+		Profile: profile{Email: "ada@example.com", Verified: true},
+		Visits:  3,
+	}, result)
+}
+
+// TestAddLensNode_ErrorLeavesStateUnchanged tests that a failing lens node does not write back.
+func TestAddLensNode_ErrorLeavesStateUnchanged(t *testing.T) {
+	errInvalid := errors.New("invalid email")
+	fail := func(ctx Context, p profile) (profile, error) {
+		p.Email = "corrupted"
+		return p, errInvalid
+	}
+
+	node := LensNode(getProfile, setProfile, fail)
+	in := lensState{Name: "ada", Profile: profile{Email: "ada@example.com"}}
+
+	out, err := node(testCtx(), in)
+
+	assert.ErrorIs(t, err, errInvalid)
+	assert.Equal(t, in, out)
+}
+
+// TestLensNode_NilPanics tests that nil lens functions panic.
+func TestLensNode_NilPanics(t *testing.T) {
+	verify := func(ctx Context, p profile) (profile, error) { return p, nil }
+
+	assert.Panics(t, func() { LensNode[lensState, profile](nil, setProfile, verify) })
+	assert.Panics(t, func() { LensNode(getProfile, nil, verify) })
+	assert.Panics(t, func() { LensNode[lensState, profile](getProfile, setProfile, nil) })
+}
@@ -0,0 +1,458 @@
+package flowgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LintSeverity indicates how strongly a lint finding should be acted on.
+type LintSeverity int
+
+const (
+	// LintSeverityInfo marks a finding that is likely fine but worth a look.
+	LintSeverityInfo LintSeverity = iota
+
+	// LintSeverityWarning marks a finding that is likely a mistake.
+	LintSeverityWarning
+)
+
+// String returns the name of the severity.
+func (s LintSeverity) String() string {
+	switch s {
+	case LintSeverityInfo:
+		return "info"
+	case LintSeverityWarning:
+		return "warning"
+	default:
+		return fmt.Sprintf("LintSeverity(%d)", int(s))
+	}
+}
+
+// Lint rule identifiers reported in LintWarning.Rule.
+const (
+	// LintRuleAmbiguousEdges flags nodes with both simple and conditional
+	// edges. The conditional edge wins and the simple edges are ignored.
+	LintRuleAmbiguousEdges = "ambiguous-edges"
+
+	// LintRuleDeadEnd flags nodes with no edge, conditional edge, or
+	// fan-out. A run that reaches such a node fails after it succeeds.
+	LintRuleDeadEnd = "dead-end"
+
+	// LintRuleConstantRouter flags conditional edges that returned the
+	// same target for every sample state. A simple edge would do.
+	LintRuleConstantRouter = "constant-router"
+
+	// LintRuleInescapableLoop flags cycles of simple edges with no
+	// conditional edge and no edge leaving the cycle.
+	LintRuleInescapableLoop = "inescapable-loop"
+
+	// LintRuleUnbalancedFork flags forks whose branches differ greatly in
+	// length, so the short branches sit idle waiting at the join.
+	LintRuleUnbalancedFork = "unbalanced-fork"
+
+	// LintRuleUnreachableNode flags nodes that no path from the entry point
+	// reaches. Conditional edges and fan-outs may reach any node, so graphs
+	// that use them past the entry are checked only up to that point.
+	LintRuleUnreachableNode = "unreachable-node"
+)
+
+// Thresholds for LintRuleUnbalancedFork: the longest branch must be at
+// least forkImbalanceRatio times, and forkImbalanceMinDiff nodes, longer
+// than the shortest.
+const (
+	forkImbalanceRatio   = 3
+	forkImbalanceMinDiff = 3
+)
+
+// LintWarning is a non-fatal finding reported by Lint.
+type LintWarning struct {
+	Rule     string
+	Severity LintSeverity
+	NodeIDs  []string // Nodes involved, sorted
+	Message  string
+}
+
+// String formats the warning as "severity [rule] message".
+func (w LintWarning) String() string {
+	return fmt.Sprintf("%s [%s] %s", w.Severity, w.Rule, w.Message)
+}
+
+// Lint reports structural anti-patterns that Compile accepts but that
+// usually indicate a mistake or make a graph harder to maintain.
+//
+// Router targets are only known at runtime, so the constant-router check
+// calls each conditional edge's router with the given sample states and
+// flags routers that returned the same target for all of them. With fewer
+// than two samples that check is skipped. Routers that panic are ignored.
+//
+// Findings are ordered by rule, then by node ID.
+//
+// Example:
+//
+//	for _, w := range compiled.Lint(sampleStates...) {
+//	    log.Println(w)
+//	}
+func (cg *CompiledGraph[S]) Lint(samples ...S) []LintWarning {
+	var warnings []LintWarning
+	warnings = append(warnings, cg.lintAmbiguousEdges()...)
+	warnings = append(warnings, cg.lintConstantRouters(samples)...)
+	warnings = append(warnings, cg.lintDeadEnds()...)
+	warnings = append(warnings, cg.lintInescapableLoops()...)
+	warnings = append(warnings, cg.lintUnbalancedForks()...)
+	warnings = append(warnings, cg.lintUnreachableNodes()...)
+	return warnings
+}
+
+// CompileStrict compiles the graph like Compile and then lints it,
+// failing with ErrLintWarnings if Lint reports any finding of warning
+// severity. Info findings are allowed. Compile itself stays permissive.
+//
+// Example:
+//
+//	compiled, err := graph.CompileStrict()
+//	if errors.Is(err, flowgraph.ErrLintWarnings) {
+//	    log.Fatal(err) // lists each warning
+//	}
+func (g *Graph[S]) CompileStrict() (*CompiledGraph[S], error) {
+	compiled, err := g.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, w := range compiled.Lint() {
+		if w.Severity >= LintSeverityWarning {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrLintWarnings, w))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return compiled, nil
+}
+
+// lintAmbiguousEdges flags nodes with both simple and conditional edges.
+func (cg *CompiledGraph[S]) lintAmbiguousEdges() []LintWarning {
+	var warnings []LintWarning
+	for _, from := range sortedKeys(cg.conditionalEdges) {
+		targets := cg.edges[from]
+		if len(targets) == 0 {
+			continue
+		}
+		warnings = append(warnings, LintWarning{
+			Rule:     LintRuleAmbiguousEdges,
+			Severity: LintSeverityWarning,
+			NodeIDs:  []string{from},
+			Message: fmt.Sprintf("node %q has both a conditional edge and simple edges to %s; the simple edges are ignored",
+				from, strings.Join(targets, ", ")),
+		})
+	}
+	return warnings
+}
+
+// lintConstantRouters flags routers that return one target for all samples.
+func (cg *CompiledGraph[S]) lintConstantRouters(samples []S) []LintWarning {
+	if len(samples) < 2 {
+		return nil
+	}
+
+	ctx := NewContext(context.Background())
+	var warnings []LintWarning
+	for _, from := range sortedKeys(cg.conditionalEdges) {
+		router := cg.conditionalEdges[from]
+		target, constant := "", true
+		for i, s := range samples {
+			got, ok := probeRouter(ctx, router, s)
+			if !ok || (i > 0 && got != target) {
+				constant = false
+				break
+			}
+			target = got
+		}
+		if !constant {
+			continue
+		}
+		warnings = append(warnings, LintWarning{
+			Rule:     LintRuleConstantRouter,
+			Severity: LintSeverityInfo,
+			NodeIDs:  []string{from},
+			Message: fmt.Sprintf("router on %q returned %q for all %d sample states; consider AddEdge(%q, %q)",
+				from, target, len(samples), from, target),
+		})
+	}
+	return warnings
+}
+
+// probeRouter calls router, reporting false if it panics.
+func probeRouter[S any](ctx Context, router RouterFunc[S], state S) (target string, ok bool) {
+	defer func() {
+		if recover() != nil {
+			target, ok = "", false
+		}
+	}()
+	return router(ctx, state), true
+}
+
+// lintInescapableLoops flags cycles of simple edges that can never exit.
+// A cycle containing a conditional edge or dynamic fan-out is assumed to
+// have an exit, since its router may leave the cycle at runtime.
+func (cg *CompiledGraph[S]) lintInescapableLoops() []LintWarning {
+	var warnings []LintWarning
+	for _, scc := range stronglyConnected(cg.sortedNodeIDs(), cg.staticEdges()) {
+		if !cg.isCycle(scc) {
+			continue
+		}
+
+		members := make(map[string]bool, len(scc))
+		for _, id := range scc {
+			members[id] = true
+		}
+
+		escapes := false
+		for _, id := range scc {
+			_, hasConditional := cg.conditionalEdges[id]
+			_, hasFanOut := cg.fanOuts[id]
+			if hasConditional || hasFanOut {
+				escapes = true
+				break
+			}
+			for _, to := range cg.edges[id] {
+				if !members[to] {
+					escapes = true
+					break
+				}
+			}
+		}
+		if escapes {
+			continue
+		}
+
+		warnings = append(warnings, LintWarning{
+			Rule:     LintRuleInescapableLoop,
+			Severity: LintSeverityWarning,
+			NodeIDs:  scc,
+			Message: fmt.Sprintf("loop through %s has no conditional edge or exit; it runs until the iteration limit",
+				strings.Join(scc, ", ")),
+		})
+	}
+	return warnings
+}
+
+// staticEdges returns the simple edges that execution actually follows,
+// excluding those shadowed by a conditional edge.
+func (cg *CompiledGraph[S]) staticEdges() map[string][]string {
+	edges := make(map[string][]string, len(cg.edges))
+	for from, targets := range cg.edges {
+		if _, hasConditional := cg.conditionalEdges[from]; hasConditional {
+			continue
+		}
+		edges[from] = targets
+	}
+	return edges
+}
+
+// isCycle reports whether a strongly connected component contains a cycle:
+// either several nodes, or one node with an edge to itself.
+func (cg *CompiledGraph[S]) isCycle(scc []string) bool {
+	if len(scc) > 1 {
+		return true
+	}
+	for _, to := range cg.edges[scc[0]] {
+		if to == scc[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// stronglyConnected returns the strongly connected components of the graph
+// using Tarjan's algorithm. Each component is sorted; components are
+// ordered by their first node ID.
+func stronglyConnected(nodes []string, edges map[string][]string) [][]string {
+	index := make(map[string]int, len(nodes))
+	lowlink := make(map[string]int, len(nodes))
+	onStack := make(map[string]bool, len(nodes))
+	var stack []string
+	var sccs [][]string
+	next := 0
+
+	var visit func(id string)
+	visit = func(id string) {
+		index[id] = next
+		lowlink[id] = next
+		next++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, to := range edges[id] {
+			if to == END {
+				continue
+			}
+			if _, seen := index[to]; !seen {
+				visit(to)
+				lowlink[id] = min(lowlink[id], lowlink[to])
+			} else if onStack[to] {
+				lowlink[id] = min(lowlink[id], index[to])
+			}
+		}
+
+		if lowlink[id] != index[id] {
+			return
+		}
+		var scc []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			scc = append(scc, top)
+			if top == id {
+				break
+			}
+		}
+		sort.Strings(scc)
+		sccs = append(sccs, scc)
+	}
+
+	for _, id := range nodes {
+		if _, seen := index[id]; !seen {
+			visit(id)
+		}
+	}
+
+	sort.Slice(sccs, func(i, j int) bool { return sccs[i][0] < sccs[j][0] })
+	return sccs
+}
+
+// lintDeadEnds flags nodes with nowhere to go after they succeed. An error
+// edge alone does not count, since it is only taken on failure.
+func (cg *CompiledGraph[S]) lintDeadEnds() []LintWarning {
+	var warnings []LintWarning
+	for _, id := range cg.sortedNodeIDs() {
+		_, hasConditional := cg.conditionalEdges[id]
+		_, hasFanOut := cg.fanOuts[id]
+		if len(cg.successors[id]) > 0 || hasConditional || hasFanOut {
+			continue
+		}
+		warnings = append(warnings, LintWarning{
+			Rule:     LintRuleDeadEnd,
+			Severity: LintSeverityWarning,
+			NodeIDs:  []string{id},
+			Message:  fmt.Sprintf("node %q has no outgoing edge; add an edge to END if the run should stop there", id),
+		})
+	}
+	return warnings
+}
+
+// lintUnbalancedForks flags forks whose branch lengths differ greatly.
+// Branch length is the number of nodes on the shortest path from the
+// branch entry to the join node.
+func (cg *CompiledGraph[S]) lintUnbalancedForks() []LintWarning {
+	var warnings []LintWarning
+	for _, forkID := range sortedKeys(cg.forkNodes) {
+		fork := cg.forkNodes[forkID]
+		if fork.JoinNodeID == "" {
+			continue
+		}
+
+		shortest, longest := -1, -1
+		var shortBranch, longBranch string
+		for _, branch := range fork.Branches {
+			n := pathLength(branch, fork.JoinNodeID, cg.edges)
+			if n < 0 {
+				continue
+			}
+			if shortest < 0 || n < shortest {
+				shortest, shortBranch = n, branch
+			}
+			if n > longest {
+				longest, longBranch = n, branch
+			}
+		}
+		if shortest < 0 {
+			continue
+		}
+
+		if longest-shortest < forkImbalanceMinDiff || longest < forkImbalanceRatio*max(shortest, 1) {
+			continue
+		}
+
+		warnings = append(warnings, LintWarning{
+			Rule:     LintRuleUnbalancedFork,
+			Severity: LintSeverityInfo,
+			NodeIDs:  []string{forkID},
+			Message: fmt.Sprintf("fork %q has unbalanced branches: %q is %d nodes long, %q is %d",
+				forkID, longBranch, longest, shortBranch, shortest),
+		})
+	}
+	return warnings
+}
+
+// pathLength returns the number of nodes on the shortest path from start
+// to target, excluding target. Returns -1 if target is unreachable.
+func pathLength(start, target string, edges map[string][]string) int {
+	if start == target {
+		return 0
+	}
+
+	dist := map[string]int{start: 1}
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, next := range edges[current] {
+			if next == target {
+				return dist[current]
+			}
+			if _, seen := dist[next]; seen || next == END {
+				continue
+			}
+			dist[next] = dist[current] + 1
+			queue = append(queue, next)
+		}
+	}
+	return -1
+}
+
+// lintUnreachableNodes flags nodes not reachable from the entry point. It
+// follows the successors used for fork/join detection plus error edges.
+// Skipped with an entry selector, since any node may be the entry.
+func (cg *CompiledGraph[S]) lintUnreachableNodes() []LintWarning {
+	if cg.entryPoint == "" {
+		return nil
+	}
+
+	edges := make(map[string][]string, len(cg.successors)+len(cg.errorEdges))
+	for from, targets := range cg.successors {
+		edges[from] = targets
+	}
+	for from, to := range cg.errorEdges {
+		edges[from] = append(edges[from][:len(edges[from]):len(edges[from])], to)
+	}
+
+	reachable := computeReachable(cg.entryPoint, edges)
+	for id := range reachable {
+		_, hasConditional := cg.conditionalEdges[id]
+		_, hasFanOut := cg.fanOuts[id]
+		if hasConditional || hasFanOut {
+			return nil
+		}
+	}
+
+	var warnings []LintWarning
+	for _, id := range cg.sortedNodeIDs() {
+		if reachable[id] {
+			continue
+		}
+		warnings = append(warnings, LintWarning{
+			Rule:     LintRuleUnreachableNode,
+			Severity: LintSeverityWarning,
+			NodeIDs:  []string{id},
+			Message:  fmt.Sprintf("node %q is unreachable from entry %q", id, cg.entryPoint),
+		})
+	}
+	return warnings
+}
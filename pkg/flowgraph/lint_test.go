@@ -0,0 +1,242 @@
+package flowgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lintRules returns the rule of each warning, in order.
+func lintRules(warnings []LintWarning) []string {
+	rules := make([]string, len(warnings))
+	for i, w := range warnings {
+		rules[i] = w.Rule
+	}
+	return rules
+}
+
+// TestLint_CleanGraph tests that a well-formed graph produces no warnings.
+func TestLint_CleanGraph(t *testing.T) {
+	compiled, err := NewGraph[Counter]().
+		AddNode("fork", increment).
+		AddNode("left", increment).
+		AddNode("right", increment).
+		AddNode("join", increment).
+		AddNode("check", increment).
+		AddEdge("fork", "left").
+		AddEdge("fork", "right").
+		AddEdge("left", "join").
+		AddEdge("right", "join").
+		AddEdge("join", "check").
+		AddConditionalEdge("check", func(ctx Context, s Counter) string {
+			if s.Value < 10 {
+				return "fork"
+			}
+			return END
+		}).
+		SetEntry("fork").
+		Compile()
+	require.NoError(t, err)
+
+	assert.Empty(t, compiled.Lint(Counter{Value: 0}, Counter{Value: 20}))
+}
+
+// TestLint_AmbiguousEdges tests that a node with simple and conditional edges is flagged.
+func TestLint_AmbiguousEdges(t *testing.T) {
+	compiled, err := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddNode("b", increment).
+		AddEdge("a", "b").
+		AddConditionalEdge("a", func(ctx Context, s Counter) string { return END }).
+		AddEdge("b", END).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+
+	warnings := compiled.Lint()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, LintRuleAmbiguousEdges, warnings[0].Rule)
+	assert.Equal(t, LintSeverityWarning, warnings[0].Severity)
+	assert.Equal(t, []string{"a"}, warnings[0].NodeIDs)
+	assert.Contains(t, warnings[0].Message, "b")
+}
+
+// TestLint_ConstantRouter tests that a router returning one target for all samples is flagged.
+func TestLint_ConstantRouter(t *testing.T) {
+	compiled, err := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddNode("b", increment).
+		AddConditionalEdge("a", func(ctx Context, s Counter) string { return "b" }).
+		AddEdge("b", END).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+
+	warnings := compiled.Lint(Counter{Value: 1}, Counter{Value: 2})
+	require.Len(t, warnings, 1)
+	assert.Equal(t, LintRuleConstantRouter, warnings[0].Rule)
+	assert.Equal(t, LintSeverityInfo, warnings[0].Severity)
+	assert.Equal(t, []string{"a"}, warnings[0].NodeIDs)
+
+	// Without samples the check is skipped
+	assert.Empty(t, compiled.Lint())
+}
+
+// TestLint_ConstantRouter_Panics tests that panicking routers are not flagged.
+func TestLint_ConstantRouter_Panics(t *testing.T) {
+	compiled, err := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddConditionalEdge("a", func(ctx Context, s Counter) string { panic("boom") }).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+
+	assert.Empty(t, compiled.Lint(Counter{}, Counter{}))
+}
+
+// TestLint_InescapableLoop tests that a cycle with no exit is flagged.
+func TestLint_InescapableLoop(t *testing.T) {
+	compiled, err := NewGraph[Counter]().
+		AddNode("start", increment).
+		AddNode("spin1", increment).
+		AddNode("spin2", increment).
+		AddNode("self", increment).
+		AddConditionalEdge("start", func(ctx Context, s Counter) string { return "spin1" }).
+		AddEdge("spin1", "spin2").
+		AddEdge("spin2", "spin1").
+		AddEdge("self", "self").
+		SetEntry("start").
+		Compile()
+	require.NoError(t, err)
+
+	warnings := compiled.Lint()
+	assert.Equal(t, []string{LintRuleInescapableLoop, LintRuleInescapableLoop}, lintRules(warnings))
+	assert.Equal(t, []string{"self"}, warnings[0].NodeIDs)
+	assert.Equal(t, []string{"spin1", "spin2"}, warnings[1].NodeIDs)
+	assert.Equal(t, LintSeverityWarning, warnings[1].Severity)
+}
+
+// TestLint_UnbalancedFork tests that forks with very different branch lengths are flagged.
+func TestLint_UnbalancedFork(t *testing.T) {
+	compiled, err := NewGraph[Counter]().
+		AddNode("fork", increment).
+		AddNode("short", increment).
+		AddNode("long1", increment).
+		AddNode("long2", increment).
+		AddNode("long3", increment).
+		AddNode("long4", increment).
+		AddNode("join", increment).
+		AddEdge("fork", "short").
+		AddEdge("fork", "long1").
+		AddEdge("short", "join").
+		AddEdge("long1", "long2").
+		AddEdge("long2", "long3").
+		AddEdge("long3", "long4").
+		AddEdge("long4", "join").
+		AddEdge("join", END).
+		SetEntry("fork").
+		Compile()
+	require.NoError(t, err)
+
+	warnings := compiled.Lint()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, LintRuleUnbalancedFork, warnings[0].Rule)
+	assert.Equal(t, LintSeverityInfo, warnings[0].Severity)
+	assert.Equal(t, []string{"fork"}, warnings[0].NodeIDs)
+	assert.Contains(t, warnings[0].Message, `"long1" is 4 nodes long, "short" is 1`)
+}
+
+// TestLintWarning_String tests warning formatting.
+func TestLintWarning_String(t *testing.T) {
+	w := LintWarning{Rule: LintRuleAmbiguousEdges, Severity: LintSeverityWarning, Message: "msg"}
+	assert.Equal(t, "warning [ambiguous-edges] msg", w.String())
+	assert.Equal(t, "LintSeverity(7)", LintSeverity(7).String())
+}
+
+// TestLint_DeadEnd tests that a node with no outgoing edge is flagged, even with an error edge.
+func TestLint_DeadEnd(t *testing.T) {
+	compiled, err := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddNode("stuck", increment).
+		AddNode("recover", increment).
+		AddConditionalEdge("a", func(ctx Context, s Counter) string {
+			if s.Value > 5 {
+				return "stuck"
+			}
+			return END
+		}).
+		AddErrorEdge("stuck", "recover").
+		AddEdge("recover", END).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+
+	warnings := compiled.Lint()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, LintRuleDeadEnd, warnings[0].Rule)
+	assert.Equal(t, LintSeverityWarning, warnings[0].Severity)
+	assert.Equal(t, []string{"stuck"}, warnings[0].NodeIDs)
+}
+
+// TestLint_UnreachableNode tests that nodes off every path from entry are flagged.
+func TestLint_UnreachableNode(t *testing.T) {
+	compiled, err := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddNode("b", increment).
+		AddNode("recover", increment).
+		AddNode("orphan", increment).
+		AddEdge("a", "b").
+		AddEdge("b", END).
+		AddErrorEdge("b", "recover").
+		AddEdge("recover", END).
+		AddEdge("orphan", END).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+
+	warnings := compiled.Lint()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, LintRuleUnreachableNode, warnings[0].Rule)
+	assert.Equal(t, []string{"orphan"}, warnings[0].NodeIDs)
+}
+
+// TestLint_UnreachableNode_Conditional tests that a reachable conditional edge suppresses the check.
+func TestLint_UnreachableNode_Conditional(t *testing.T) {
+	compiled, err := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddNode("maybe", increment).
+		AddConditionalEdge("a", func(ctx Context, s Counter) string { return END }).
+		AddEdge("maybe", END).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+
+	assert.NotContains(t, lintRules(compiled.Lint()), LintRuleUnreachableNode)
+}
+
+// TestCompileStrict tests that lint warnings fail strict compilation but not Compile.
+func TestCompileStrict(t *testing.T) {
+	graph := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddNode("orphan", increment).
+		AddEdge("a", END).
+		SetEntry("a")
+
+	_, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = graph.CompileStrict()
+	require.ErrorIs(t, err, ErrLintWarnings)
+	assert.Contains(t, err.Error(), "orphan")
+	assert.Contains(t, err.Error(), LintRuleDeadEnd)
+	assert.Contains(t, err.Error(), LintRuleUnreachableNode)
+
+	compiled, err := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddEdge("a", END).
+		SetEntry("a").
+		CompileStrict()
+	require.NoError(t, err)
+	assert.NotNil(t, compiled)
+}
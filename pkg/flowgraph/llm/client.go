@@ -0,0 +1,22 @@
+package llm
+
+import "github.com/randalmurphal/llmkit/claude"
+
+// Client is the interface for LLM providers.
+// It is an alias for the llmkit client interface.
+type Client = claude.Client
+
+// CompletionRequest configures an LLM completion call.
+type CompletionRequest = claude.CompletionRequest
+
+// CompletionResponse is the output of a completion call.
+type CompletionResponse = claude.CompletionResponse
+
+// StreamChunk is a piece of a streaming response.
+type StreamChunk = claude.StreamChunk
+
+// Message is a conversation turn.
+type Message = claude.Message
+
+// TokenUsage tracks token consumption.
+type TokenUsage = claude.TokenUsage
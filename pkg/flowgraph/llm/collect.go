@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CollectStream drains a stream returned by Client.Stream and aggregates it
+// into a single response: chunk content is concatenated, tool calls are
+// appended in order, and token usage from every chunk that reports it is
+// summed with TokenUsage.Add.
+//
+// CollectStream returns when a chunk is marked Done or the channel is
+// closed. If a chunk reports an error, or ctx is cancelled first, the
+// response aggregated so far is returned along with the error.
+//
+// Example:
+//
+//	ch, err := client.Stream(ctx, req)
+//	if err != nil {
+//	    return err
+//	}
+//	resp, err := llm.CollectStream(ctx, ch)
+//
+// Panics if ch is nil.
+func CollectStream(ctx context.Context, ch <-chan StreamChunk) (*CompletionResponse, error) {
+	if ch == nil {
+		panic("llm: stream cannot be nil")
+	}
+
+	var content strings.Builder
+	resp := &CompletionResponse{}
+	done := func() *CompletionResponse {
+		resp.Content = content.String()
+		return resp
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return done(), fmt.Errorf("stream: %w", ctx.Err())
+		case chunk, ok := <-ch:
+			if !ok {
+				return done(), nil
+			}
+			content.WriteString(chunk.Content)
+			resp.ToolCalls = append(resp.ToolCalls, chunk.ToolCalls...)
+			if chunk.Usage != nil {
+				resp.Usage.Add(*chunk.Usage)
+			}
+			if chunk.Error != nil {
+				return done(), fmt.Errorf("stream: %w", chunk.Error)
+			}
+			if chunk.Done {
+				return done(), nil
+			}
+		}
+	}
+}
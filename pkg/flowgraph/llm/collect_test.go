@@ -0,0 +1,93 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/randalmurphal/llmkit/claude"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamOf returns a stream function that sends chunks and closes.
+func streamOf(chunks ...StreamChunk) func(context.Context, CompletionRequest) (<-chan StreamChunk, error) {
+	return func(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
+		ch := make(chan StreamChunk, len(chunks))
+		for _, c := range chunks {
+			ch <- c
+		}
+		close(ch)
+		return ch, nil
+	}
+}
+
+func TestCollectStream_Aggregates(t *testing.T) {
+	client := claude.NewMockClient("").WithStreamFunc(streamOf(
+		StreamChunk{Content: "Hello", Usage: &TokenUsage{InputTokens: 3}},
+		StreamChunk{Content: ", ", ToolCalls: []claude.ToolCall{{ID: "call_1", Name: "lookup"}}},
+		StreamChunk{Content: "world"},
+		StreamChunk{Done: true, Usage: &TokenUsage{OutputTokens: 5, TotalTokens: 8}},
+	))
+
+	ch, err := client.Stream(context.Background(), CompletionRequest{})
+	require.NoError(t, err)
+	resp, err := CollectStream(context.Background(), ch)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, world", resp.Content)
+	assert.Equal(t, TokenUsage{InputTokens: 3, OutputTokens: 5, TotalTokens: 8}, resp.Usage)
+	require.Len(t, resp.ToolCalls, 1)
+	assert.Equal(t, "lookup", resp.ToolCalls[0].Name)
+}
+
+func TestCollectStream_ClosedWithoutDone(t *testing.T) {
+	ch := make(chan StreamChunk, 1)
+	ch <- StreamChunk{Content: "partial"}
+	close(ch)
+
+	resp, err := CollectStream(context.Background(), ch)
+
+	require.NoError(t, err)
+	assert.Equal(t, "partial", resp.Content)
+}
+
+func TestCollectStream_ErrorMidStream(t *testing.T) {
+	boom := errors.New("connection reset")
+	client := claude.NewMockClient("").WithStreamFunc(streamOf(
+		StreamChunk{Content: "Hel"},
+		StreamChunk{Content: "lo"},
+		StreamChunk{Error: boom},
+	))
+
+	ch, err := client.Stream(context.Background(), CompletionRequest{})
+	require.NoError(t, err)
+	resp, err := CollectStream(context.Background(), ch)
+
+	assert.ErrorIs(t, err, boom)
+	require.NotNil(t, resp)
+	assert.Equal(t, "Hello", resp.Content)
+}
+
+func TestCollectStream_Cancelled(t *testing.T) {
+	ch := make(chan StreamChunk, 1)
+	ch <- StreamChunk{Content: "partial"}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	collected := make(chan error, 1)
+	var resp *CompletionResponse
+	go func() {
+		var err error
+		resp, err = CollectStream(ctx, ch)
+		collected <- err
+	}()
+	cancel()
+
+	err := <-collected
+	assert.ErrorIs(t, err, context.Canceled)
+	require.NotNil(t, resp)
+}
+
+func TestCollectStream_NilPanics(t *testing.T) {
+	assert.Panics(t, func() { _, _ = CollectStream(context.Background(), nil) })
+}
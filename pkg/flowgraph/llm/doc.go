@@ -0,0 +1,70 @@
+// Package llm provides client middleware for LLM calls made from flowgraph nodes.
+//
+// The client interface and implementations live in llmkit
+// (github.com/randalmurphal/llmkit/claude). This package re-exports the core
+// types and adds decorators that wrap any Client, so they compose with the
+// Claude CLI client, mocks, or custom providers alike.
+//
+// # OpenAI-Compatible Client
+//
+// OpenAIClient calls the OpenAI chat completions API, or any compatible
+// server, over HTTP, for deployments without the Claude CLI:
+//
+//	client := llm.NewOpenAIClient(
+//	    llm.WithOpenAIModel("gpt-4o-mini"),                 // Key from OPENAI_API_KEY
+//	    llm.WithOpenAIBaseURL("http://localhost:8000/v1"), // Optional
+//	)
+//
+// Rate limits (HTTP 429) and server errors wrap claude.ErrRateLimited and
+// claude.ErrUnavailable, and are categorized as transient by the errors
+// package, so retry helpers retry them.
+//
+// # Post-Processing
+//
+// Wrap a client to transform every completion before it reaches the node:
+//
+//	client := llm.NewPostProcessClient(claude.NewClaudeCLI(), llm.ExtractJSON)
+//
+//	resp, err := client.Complete(ctx, req)
+//	// resp.Content now holds only the first JSON object from the response
+//
+// Post-processors receive the response by pointer and may mutate it in place.
+// Returning an error fails the Complete call with that error.
+//
+// # Model Escalation
+//
+// EscalatingClient routes each call through an errors.Handler, starting on
+// the cheapest model in an escalation chain and moving to a stronger one
+// when the error is escalatable, such as an *errors.JSONParseError:
+//
+//	client := llm.NewEscalatingClient(
+//	    map[model.ModelName]llm.Client{
+//	        model.ModelSonnet: sonnetClient,
+//	        model.ModelOpus:   opusClient,
+//	    },
+//	    &model.DefaultEscalation,
+//	    errors.WithOnEscalate(onEscalate), // Optional
+//	)
+//
+// Transient errors are retried on the same model first; permanent errors
+// are returned without escalating.
+//
+// # Collecting Streams
+//
+// CollectStream drains a stream into a single response, concatenating
+// content and summing token usage:
+//
+//	ch, err := client.Stream(ctx, req)
+//	if err != nil {
+//	    return err
+//	}
+//	resp, err := llm.CollectStream(ctx, ch)
+//
+// If the stream fails or ctx is cancelled, the partial response is returned
+// with the error.
+//
+// # Streaming to an Event Bus
+//
+// The llmevent package publishes each chunk of a streaming completion to
+// an event bus; see llmevent.StreamToBus.
+package llm
@@ -0,0 +1,92 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
+	"github.com/randalmurphal/llmkit/model"
+)
+
+// EscalatingClient is a Client that retries failed calls and moves up a
+// model escalation chain when a weaker model cannot handle a request.
+//
+// Each call starts on the first model in the chain and runs through an
+// errors.Handler: transient errors are retried on the same model per the
+// handler's retry config, and escalatable errors (see errors.Categorize),
+// such as *errors.JSONParseError, move the call to the next model's
+// client. Permanent and human-required errors are returned immediately.
+//
+// Failed calls return the last error, wrapped in an
+// *errors.CategorizedError.
+//
+// EscalatingClient is safe for concurrent use if the wrapped clients are.
+type EscalatingClient struct {
+	clients map[model.ModelName]Client
+	start   model.ModelName
+	handler *fgerrors.Handler
+}
+
+// NewEscalatingClient creates a client that escalates through chain,
+// sending each model's calls to clients[model]. The request is passed to
+// each client unchanged, so every client should be configured for its
+// own model.
+//
+// Handler options configure retries, logging, and callbacks such as
+// errors.WithOnEscalate; chain always overrides errors.WithEscalation.
+//
+// Example:
+//
+//	client := llm.NewEscalatingClient(
+//	    map[model.ModelName]llm.Client{
+//	        model.ModelSonnet: sonnetClient,
+//	        model.ModelOpus:   opusClient,
+//	    },
+//	    &model.DefaultEscalation,
+//	    errors.WithOnEscalate(func(from, to model.ModelName, err error) {
+//	        logger.Warn("escalating", "from", from, "to", to, "error", err)
+//	    }),
+//	)
+//
+// Panics if chain is nil or empty, or if clients has no client for a
+// model in the chain.
+func NewEscalatingClient(
+	clients map[model.ModelName]Client,
+	chain *model.EscalationChain,
+	opts ...fgerrors.HandlerOption,
+) *EscalatingClient {
+	if chain == nil || len(chain.Models) == 0 {
+		panic("llm: escalation chain cannot be empty")
+	}
+	for _, m := range chain.Models {
+		if clients[m] == nil {
+			panic(fmt.Sprintf("llm: no client for model %q in escalation chain", m))
+		}
+	}
+
+	opts = append(opts, fgerrors.WithEscalation(chain))
+	return &EscalatingClient{
+		clients: clients,
+		start:   chain.Models[0],
+		handler: fgerrors.NewHandler(opts...),
+	}
+}
+
+// Complete implements Client.
+func (c *EscalatingClient) Complete(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
+	result := fgerrors.Execute(ctx, c.handler, c.start,
+		func(ctx context.Context, m model.ModelName) (*CompletionResponse, error) {
+			return c.clients[m].Complete(ctx, req)
+		})
+	return result.Value, result.Err
+}
+
+// Stream implements Client. Only errors returned when opening the stream
+// are retried or escalated; errors in chunks reach the caller as-is.
+func (c *EscalatingClient) Stream(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
+	result := fgerrors.Execute(ctx, c.handler, c.start,
+		func(ctx context.Context, m model.ModelName) (<-chan StreamChunk, error) {
+			return c.clients[m].Stream(ctx, req)
+		})
+	return result.Value, result.Err
+}
@@ -0,0 +1,143 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
+	"github.com/randalmurphal/llmkit/claude"
+	"github.com/randalmurphal/llmkit/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testChain = model.EscalationChain{
+	Models:      []model.ModelName{model.ModelHaiku, model.ModelSonnet},
+	MaxAttempts: 3,
+}
+
+func TestEscalatingClient_EscalatesOnJSONParseError(t *testing.T) {
+	weak := claude.NewMockClient("").WithError(&fgerrors.JSONParseError{Message: "unexpected end of input"})
+	strong := claude.NewMockClient(`{"ok": true}`)
+
+	type escalation struct {
+		from, to model.ModelName
+		err      error
+	}
+	var escalations []escalation
+	client := NewEscalatingClient(
+		map[model.ModelName]Client{model.ModelHaiku: weak, model.ModelSonnet: strong},
+		&testChain,
+		fgerrors.WithRetryConfig(fgerrors.NoRetry),
+		fgerrors.WithOnEscalate(func(from, to model.ModelName, err error) {
+			escalations = append(escalations, escalation{from, to, err})
+		}),
+	)
+
+	resp, err := client.Complete(context.Background(), CompletionRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, `{"ok": true}`, resp.Content)
+	assert.Equal(t, 1, weak.CallCount())
+	assert.Equal(t, 1, strong.CallCount())
+	require.Len(t, escalations, 1)
+	assert.Equal(t, model.ModelHaiku, escalations[0].from)
+	assert.Equal(t, model.ModelSonnet, escalations[0].to)
+	var jsonErr *fgerrors.JSONParseError
+	assert.ErrorAs(t, escalations[0].err, &jsonErr)
+}
+
+func TestEscalatingClient_NoEscalationOnSuccess(t *testing.T) {
+	weak := claude.NewMockClient("fine")
+	strong := claude.NewMockClient("unused")
+	escalated := false
+
+	client := NewEscalatingClient(
+		map[model.ModelName]Client{model.ModelHaiku: weak, model.ModelSonnet: strong},
+		&testChain,
+		fgerrors.WithOnEscalate(func(_, _ model.ModelName, _ error) { escalated = true }),
+	)
+
+	resp, err := client.Complete(context.Background(), CompletionRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "fine", resp.Content)
+	assert.Equal(t, 0, strong.CallCount())
+	assert.False(t, escalated)
+}
+
+func TestEscalatingClient_PermanentErrorNotEscalated(t *testing.T) {
+	permanent := errors.New("invalid api key")
+	weak := claude.NewMockClient("").WithError(permanent)
+	strong := claude.NewMockClient("unused")
+
+	client := NewEscalatingClient(
+		map[model.ModelName]Client{model.ModelHaiku: weak, model.ModelSonnet: strong},
+		&testChain,
+		fgerrors.WithRetryConfig(fgerrors.NoRetry),
+	)
+
+	_, err := client.Complete(context.Background(), CompletionRequest{})
+
+	require.ErrorIs(t, err, permanent)
+	assert.Equal(t, 0, strong.CallCount())
+}
+
+func TestEscalatingClient_ExhaustedChain(t *testing.T) {
+	parseErr := &fgerrors.JSONParseError{Message: "bad"}
+	weak := claude.NewMockClient("").WithError(parseErr)
+	strong := claude.NewMockClient("").WithError(parseErr)
+	var exhausted error
+
+	client := NewEscalatingClient(
+		map[model.ModelName]Client{model.ModelHaiku: weak, model.ModelSonnet: strong},
+		&testChain,
+		fgerrors.WithRetryConfig(fgerrors.NoRetry),
+		fgerrors.WithOnExhausted(func(err error) { exhausted = err }),
+	)
+
+	_, err := client.Complete(context.Background(), CompletionRequest{})
+
+	require.Error(t, err)
+	assert.True(t, fgerrors.IsEscalatable(err))
+	assert.Equal(t, 1, weak.CallCount())
+	assert.Equal(t, 1, strong.CallCount())
+	assert.Error(t, exhausted)
+}
+
+func TestEscalatingClient_StreamEscalates(t *testing.T) {
+	weak := claude.NewMockClient("").WithError(&fgerrors.JSONParseError{Message: "bad"})
+	strong := claude.NewMockClient("").WithStreamFunc(streamOf(
+		StreamChunk{Content: "streamed"},
+		StreamChunk{Done: true},
+	))
+
+	client := NewEscalatingClient(
+		map[model.ModelName]Client{model.ModelHaiku: weak, model.ModelSonnet: strong},
+		&testChain,
+		fgerrors.WithRetryConfig(fgerrors.NoRetry),
+	)
+
+	ch, err := client.Stream(context.Background(), CompletionRequest{})
+	require.NoError(t, err)
+	resp, err := CollectStream(context.Background(), ch)
+
+	require.NoError(t, err)
+	assert.Equal(t, "streamed", resp.Content)
+}
+
+func TestNewEscalatingClient_Panics(t *testing.T) {
+	assert.Panics(t, func() {
+		NewEscalatingClient(map[model.ModelName]Client{}, nil)
+	})
+	assert.Panics(t, func() {
+		NewEscalatingClient(map[model.ModelName]Client{}, &model.EscalationChain{})
+	})
+	assert.Panics(t, func() {
+		NewEscalatingClient(
+			map[model.ModelName]Client{model.ModelHaiku: claude.NewMockClient("")},
+			&testChain,
+		)
+	})
+}
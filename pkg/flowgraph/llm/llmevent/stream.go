@@ -0,0 +1,198 @@
+// Package llmevent publishes LLM output to an event bus.
+//
+// It is separate from package llm so that llm does not depend on the
+// event package.
+//
+// StreamToBus publishes each chunk of a streaming completion as an event,
+// so other components can follow the output live, and returns the
+// aggregated response:
+//
+//	resp, err := llmevent.StreamToBus(ctx, client, req, bus, "llm.token")
+//
+// Each event carries a StreamChunkEvent payload, and all events from one
+// call share a correlation ID. A bounded buffer keeps a slow bus from
+// stalling the LLM; see WithStreamBuffer.
+package llmevent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/llm"
+	"github.com/randalmurphal/llmkit/claude"
+)
+
+// StreamEventSource is the source of events published by StreamToBus.
+const StreamEventSource = "llm.stream"
+
+// defaultStreamBuffer is how many chunk events StreamToBus holds while the
+// bus is busy, unless overridden with WithStreamBuffer.
+const defaultStreamBuffer = 64
+
+// StreamChunkEvent is the payload of each event published by StreamToBus.
+type StreamChunkEvent struct {
+	// Index is the position of the chunk in the stream, starting at 0.
+	Index int `json:"index"`
+
+	// Content is the text in this chunk.
+	Content string `json:"content,omitempty"`
+
+	// ToolCalls are the tool calls in this chunk.
+	ToolCalls []claude.ToolCall `json:"tool_calls,omitempty"`
+
+	// Usage is the token usage, set only on the final chunk.
+	Usage *llm.TokenUsage `json:"usage,omitempty"`
+
+	// Done is true on the final chunk.
+	Done bool `json:"done"`
+
+	// Error is the stream error message, if the chunk reported one.
+	Error string `json:"error,omitempty"`
+}
+
+// StreamBusOption configures StreamToBus.
+type StreamBusOption func(*streamBusConfig)
+
+type streamBusConfig struct {
+	buffer int
+	onDrop func(StreamChunkEvent)
+}
+
+// WithStreamBuffer sets how many chunk events may wait for the bus before
+// further chunks are dropped. Default: 64.
+//
+// Panics if n is not positive.
+func WithStreamBuffer(n int) StreamBusOption {
+	if n <= 0 {
+		panic("llmevent: stream buffer must be positive")
+	}
+	return func(cfg *streamBusConfig) {
+		cfg.buffer = n
+	}
+}
+
+// WithStreamDropHandler sets a function called with each chunk event that
+// was not published because the buffer was full.
+func WithStreamDropHandler(fn func(StreamChunkEvent)) StreamBusOption {
+	return func(cfg *streamBusConfig) {
+		cfg.onDrop = fn
+	}
+}
+
+// StreamToBus streams a completion from client, publishing each chunk to bus
+// as an event of the given type, and returns the aggregated response.
+//
+// Every event carries a StreamChunkEvent payload and the same correlation
+// ID, so subscribers can reassemble the stream. Events are published from a
+// separate goroutine through a bounded buffer, so a slow bus does not hold
+// up the LLM. When the buffer is full, intermediate chunks are dropped from
+// the bus (but still included in the response); the final chunk is always
+// published. StreamToBus returns once every buffered event is published.
+//
+// If the stream reports an error, it is returned along with the response
+// aggregated so far. If publishing fails, the full response is returned
+// with the first publish error.
+//
+// Example:
+//
+//	resp, err := llmevent.StreamToBus(ctx, client, req, bus, "llm.token")
+//
+// Panics if client or bus is nil.
+func StreamToBus(
+	ctx context.Context,
+	client llm.Client,
+	req llm.CompletionRequest,
+	bus event.Bus,
+	eventType string,
+	opts ...StreamBusOption,
+) (*llm.CompletionResponse, error) {
+	if client == nil {
+		panic("llmevent: client cannot be nil")
+	}
+	if bus == nil {
+		panic("llmevent: bus cannot be nil")
+	}
+	cfg := &streamBusConfig{buffer: defaultStreamBuffer}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	start := time.Now()
+	chunks, err := client.Stream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make(chan event.Event, cfg.buffer)
+	published := make(chan error, 1)
+	go func() {
+		var firstErr error
+		for evt := range pending {
+			if err := bus.Publish(ctx, evt); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		published <- firstErr
+	}()
+
+	var (
+		correlationID = uuid.New().String()
+		content       strings.Builder
+		resp          = &llm.CompletionResponse{}
+		streamErr     error
+		index         int
+	)
+	for chunk := range chunks {
+		payload := StreamChunkEvent{
+			Index:     index,
+			Content:   chunk.Content,
+			ToolCalls: chunk.ToolCalls,
+			Usage:     chunk.Usage,
+			Done:      chunk.Done,
+		}
+		if chunk.Error != nil {
+			payload.Error = chunk.Error.Error()
+		}
+		evt := event.New(eventType, StreamEventSource, "", payload, event.WithCorrelationID(correlationID))
+		index++
+
+		if chunk.Done || chunk.Error != nil {
+			// Final chunk: wait for room rather than drop it
+			pending <- evt
+		} else {
+			select {
+			case pending <- evt:
+			default:
+				if cfg.onDrop != nil {
+					cfg.onDrop(payload)
+				}
+			}
+		}
+
+		content.WriteString(chunk.Content)
+		resp.ToolCalls = append(resp.ToolCalls, chunk.ToolCalls...)
+		if chunk.Usage != nil {
+			resp.Usage = *chunk.Usage
+		}
+		if chunk.Error != nil && streamErr == nil {
+			streamErr = chunk.Error
+		}
+	}
+	close(pending)
+	publishErr := <-published
+
+	resp.Content = content.String()
+	resp.Duration = time.Since(start)
+
+	if streamErr != nil {
+		return resp, fmt.Errorf("stream: %w", streamErr)
+	}
+	if publishErr != nil {
+		return resp, fmt.Errorf("publish stream chunk: %w", publishErr)
+	}
+	return resp, nil
+}
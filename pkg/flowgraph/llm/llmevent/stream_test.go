@@ -0,0 +1,193 @@
+package llmevent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/llm"
+	"github.com/randalmurphal/llmkit/claude"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamOf returns a stream function that sends chunks and closes.
+func streamOf(chunks ...llm.StreamChunk) func(context.Context, llm.CompletionRequest) (<-chan llm.StreamChunk, error) {
+	return func(ctx context.Context, req llm.CompletionRequest) (<-chan llm.StreamChunk, error) {
+		ch := make(chan llm.StreamChunk, len(chunks))
+		for _, c := range chunks {
+			ch <- c
+		}
+		close(ch)
+		return ch, nil
+	}
+}
+
+// collect subscribes to eventType and returns a function that waits for n events.
+func collect(t *testing.T, bus event.Bus, eventType string) func(n int) []event.Event {
+	t.Helper()
+	received := make(chan event.Event, 100)
+	bus.Subscribe([]string{eventType}, event.HandlerFunc(func(ctx context.Context, evt event.Event) ([]event.Event, error) {
+		received <- evt
+		return nil, nil
+	}))
+
+	return func(n int) []event.Event {
+		var events []event.Event
+		for len(events) < n {
+			select {
+			case evt := <-received:
+				events = append(events, evt)
+			case <-time.After(time.Second):
+				t.Fatalf("received %d of %d events", len(events), n)
+			}
+		}
+		return events
+	}
+}
+
+func TestStreamToBus_PublishesEachChunk(t *testing.T) {
+	bus := event.NewBus(event.DefaultBusConfig)
+	defer bus.Close()
+	wait := collect(t, bus, "llm.token")
+
+	client := claude.NewMockClient("").WithStreamFunc(streamOf(
+		llm.StreamChunk{Content: "Hello"},
+		llm.StreamChunk{Content: ", "},
+		llm.StreamChunk{Content: "world"},
+		llm.StreamChunk{Done: true, Usage: &llm.TokenUsage{InputTokens: 3, OutputTokens: 5, TotalTokens: 8}},
+	))
+
+	resp, err := StreamToBus(context.Background(), client, llm.CompletionRequest{}, bus, "llm.token")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, world", resp.Content)
+	assert.Equal(t, 8, resp.Usage.TotalTokens)
+
+	events := wait(4)
+	correlationID := events[0].CorrelationID()
+	assert.NotEmpty(t, correlationID)
+	for i, evt := range events {
+		assert.Equal(t, StreamEventSource, evt.Source())
+		assert.Equal(t, correlationID, evt.CorrelationID())
+		payload := evt.Data().(StreamChunkEvent)
+		assert.Equal(t, i, payload.Index)
+	}
+	assert.Equal(t, "Hello", events[0].Data().(StreamChunkEvent).Content)
+	assert.True(t, events[3].Data().(StreamChunkEvent).Done)
+}
+
+func TestStreamToBus_StreamError(t *testing.T) {
+	bus := event.NewBus(event.DefaultBusConfig)
+	defer bus.Close()
+	wait := collect(t, bus, "llm.token")
+
+	streamErr := errors.New("connection reset")
+	client := claude.NewMockClient("").WithStreamFunc(streamOf(
+		llm.StreamChunk{Content: "partial"},
+		llm.StreamChunk{Error: streamErr},
+	))
+
+	resp, err := StreamToBus(context.Background(), client, llm.CompletionRequest{}, bus, "llm.token")
+
+	assert.ErrorIs(t, err, streamErr)
+	require.NotNil(t, resp)
+	assert.Equal(t, "partial", resp.Content)
+
+	events := wait(2)
+	assert.Equal(t, "connection reset", events[1].Data().(StreamChunkEvent).Error)
+}
+
+func TestStreamToBus_StreamStartError(t *testing.T) {
+	startErr := errors.New("unavailable")
+	client := claude.NewMockClient("").WithStreamFunc(func(ctx context.Context, req llm.CompletionRequest) (<-chan llm.StreamChunk, error) {
+		return nil, startErr
+	})
+	bus := event.NewBus(event.DefaultBusConfig)
+	defer bus.Close()
+
+	resp, err := StreamToBus(context.Background(), client, llm.CompletionRequest{}, bus, "llm.token")
+
+	assert.ErrorIs(t, err, startErr)
+	assert.Nil(t, resp)
+}
+
+// blockingBus is a Bus whose Publish waits until release is closed.
+type blockingBus struct {
+	event.Bus
+	release chan struct{}
+
+	mu        sync.Mutex
+	published []StreamChunkEvent
+}
+
+func (b *blockingBus) Publish(ctx context.Context, evt event.Event) error {
+	<-b.release
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published = append(b.published, evt.Data().(StreamChunkEvent))
+	return nil
+}
+
+func TestStreamToBus_SlowBusDoesNotStallStream(t *testing.T) {
+	bus := &blockingBus{release: make(chan struct{})}
+
+	var chunks []llm.StreamChunk
+	for range 10 {
+		chunks = append(chunks, llm.StreamChunk{Content: "x"})
+	}
+	chunks = append(chunks, llm.StreamChunk{Done: true})
+
+	// The stream is unbuffered, so it only completes if every chunk is
+	// read while the bus is still blocked.
+	streamDone := make(chan struct{})
+	client := claude.NewMockClient("").WithStreamFunc(func(ctx context.Context, req llm.CompletionRequest) (<-chan llm.StreamChunk, error) {
+		ch := make(chan llm.StreamChunk)
+		go func() {
+			defer close(streamDone)
+			defer close(ch)
+			for _, c := range chunks {
+				ch <- c
+			}
+		}()
+		return ch, nil
+	})
+
+	var mu sync.Mutex
+	var dropped int
+	result := make(chan *llm.CompletionResponse, 1)
+	go func() {
+		resp, err := StreamToBus(context.Background(), client, llm.CompletionRequest{}, bus, "llm.token",
+			WithStreamBuffer(2),
+			WithStreamDropHandler(func(StreamChunkEvent) {
+				mu.Lock()
+				dropped++
+				mu.Unlock()
+			}))
+		assert.NoError(t, err)
+		result <- resp
+	}()
+
+	select {
+	case <-streamDone:
+	case <-time.After(time.Second):
+		t.Fatal("stream stalled behind the bus")
+	}
+	close(bus.release)
+
+	resp := <-result
+	assert.Equal(t, "xxxxxxxxxx", resp.Content)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Positive(t, dropped)
+	assert.Len(t, bus.published, len(chunks)-dropped)
+	assert.True(t, bus.published[len(bus.published)-1].Done, "final chunk must be published")
+}
+
+func TestWithStreamBuffer_PanicsOnNonPositive(t *testing.T) {
+	assert.Panics(t, func() { WithStreamBuffer(0) })
+}
@@ -0,0 +1,496 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
+	"github.com/randalmurphal/llmkit/claude"
+)
+
+// DefaultOpenAIBaseURL is the API root used by NewOpenAIClient unless
+// overridden with WithOpenAIBaseURL.
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// defaultOpenAITimeout matches the Claude CLI client's default.
+const defaultOpenAITimeout = 5 * time.Minute
+
+// OpenAIClient is a Client for the OpenAI chat completions API and the
+// many servers compatible with it.
+//
+// Errors wrap the llmkit sentinels so callers can branch on them:
+// HTTP 429 wraps ErrRateLimited, 5xx wraps ErrUnavailable, 400 wraps
+// ErrInvalidRequest (or ErrContextTooLong when the context window is
+// exceeded), and hitting the client timeout wraps ErrTimeout. HTTP
+// failures also wrap an *errors.HTTPError, so errors.Categorize treats
+// 429 and 5xx responses as transient and retry helpers retry them.
+//
+// OpenAIClient is safe for concurrent use.
+type OpenAIClient struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	timeout    time.Duration
+	httpClient *http.Client
+}
+
+// OpenAIOption configures an OpenAIClient.
+type OpenAIOption func(*OpenAIClient)
+
+// NewOpenAIClient creates a client for the OpenAI chat completions API.
+//
+// The API key defaults to the OPENAI_API_KEY environment variable; with
+// no key, requests are sent without an Authorization header, as local
+// compatible servers expect.
+//
+// Example:
+//
+//	client := llm.NewOpenAIClient(
+//	    llm.WithOpenAIModel("gpt-4o-mini"),
+//	    llm.WithOpenAITimeout(time.Minute),
+//	)
+//	resp, err := client.Complete(ctx, llm.CompletionRequest{
+//	    Messages: []llm.Message{{Role: claude.RoleUser, Content: "Hello"}},
+//	})
+func NewOpenAIClient(opts ...OpenAIOption) *OpenAIClient {
+	c := &OpenAIClient{
+		baseURL:    DefaultOpenAIBaseURL,
+		apiKey:     os.Getenv("OPENAI_API_KEY"),
+		timeout:    defaultOpenAITimeout,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithOpenAIBaseURL sets the API root, e.g. "http://localhost:8000/v1"
+// for a compatible server. Requests go to its /chat/completions endpoint.
+func WithOpenAIBaseURL(url string) OpenAIOption {
+	return func(c *OpenAIClient) { c.baseURL = strings.TrimSuffix(url, "/") }
+}
+
+// WithOpenAIAPIKey sets the API key sent as a bearer token.
+func WithOpenAIAPIKey(key string) OpenAIOption {
+	return func(c *OpenAIClient) { c.apiKey = key }
+}
+
+// WithOpenAIModel sets the default model, used when a request does not
+// name one.
+func WithOpenAIModel(model string) OpenAIOption {
+	return func(c *OpenAIClient) { c.model = model }
+}
+
+// WithOpenAITimeout sets the timeout for each call. For Stream it bounds
+// the whole stream. Default: 5 minutes. Zero disables the timeout.
+func WithOpenAITimeout(d time.Duration) OpenAIOption {
+	return func(c *OpenAIClient) { c.timeout = d }
+}
+
+// WithOpenAIHTTPClient sets the HTTP client used for requests.
+//
+// Panics if client is nil.
+func WithOpenAIHTTPClient(client *http.Client) OpenAIOption {
+	if client == nil {
+		panic("llm: http client cannot be nil")
+	}
+	return func(c *OpenAIClient) { c.httpClient = client }
+}
+
+// Complete sends a chat completion request and returns the response.
+func (c *OpenAIClient) Complete(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
+	start := time.Now()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	httpResp, err := c.post(ctx, "complete", req, false)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	var body openAIResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&body); err != nil {
+		return nil, c.transportError(ctx, "complete", fmt.Errorf("decode response: %w", err))
+	}
+	if len(body.Choices) == 0 {
+		return nil, openAIError("complete", errors.New("response has no choices"))
+	}
+
+	choice := body.Choices[0]
+	return &CompletionResponse{
+		Content:      choice.Message.Content,
+		ToolCalls:    toToolCalls(choice.Message.ToolCalls),
+		Usage:        body.Usage.toTokenUsage(),
+		Model:        body.Model,
+		FinishReason: choice.FinishReason,
+		Duration:     time.Since(start),
+	}, nil
+}
+
+// Stream sends a streaming chat completion request. Content arrives in
+// chunks as it is generated; tool calls are assembled from their deltas
+// and delivered with the usage on the final chunk, which has Done set.
+func (c *OpenAIClient) Stream(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
+	parent := ctx
+	ctx, cancel := c.withTimeout(ctx)
+
+	httpResp, err := c.post(ctx, "stream", req, true)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	ch := make(chan StreamChunk)
+	go func() {
+		defer close(ch)
+		defer cancel()
+		defer httpResp.Body.Close()
+
+		send := func(chunk StreamChunk) bool {
+			select {
+			case ch <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		final, err := readOpenAIStream(httpResp.Body, func(content string) bool {
+			return send(StreamChunk{Content: content})
+		})
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			// Deliver timeouts and read errors, unless the caller cancelled
+			// and may have stopped reading.
+			select {
+			case ch <- StreamChunk{Error: c.transportError(ctx, "stream", err)}:
+			case <-parent.Done():
+			}
+			return
+		}
+		send(final)
+	}()
+
+	return ch, nil
+}
+
+// withTimeout applies the client timeout to ctx, if one is set.
+func (c *OpenAIClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// post sends req to the chat completions endpoint and returns the
+// response if its status is 200.
+func (c *OpenAIClient) post(ctx context.Context, op string, req CompletionRequest, stream bool) (*http.Response, error) {
+	payload, err := c.buildRequest(req, stream)
+	if err != nil {
+		return nil, openAIError(op, fmt.Errorf("%w: %v", claude.ErrInvalidRequest, err))
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return nil, openAIError(op, fmt.Errorf("%w: %v", claude.ErrInvalidRequest, err))
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, c.transportError(ctx, op, err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		defer httpResp.Body.Close()
+		return nil, statusError(op, httpReq.URL.String(), httpResp)
+	}
+	return httpResp, nil
+}
+
+// buildRequest encodes req as a chat completions request body. Provider
+// options in req.Options are added as top-level fields.
+func (c *OpenAIClient) buildRequest(req CompletionRequest, stream bool) ([]byte, error) {
+	model := req.Model
+	if model == "" {
+		model = c.model
+	}
+	if model == "" {
+		return nil, errors.New("model is required (set it on the request or with WithOpenAIModel)")
+	}
+
+	body := make(map[string]any, len(req.Options)+7)
+	for k, v := range req.Options {
+		body[k] = v
+	}
+	body["model"] = model
+	body["messages"] = toOpenAIMessages(req)
+	if req.MaxTokens > 0 {
+		body["max_tokens"] = req.MaxTokens
+	}
+	if req.Temperature != 0 {
+		body["temperature"] = req.Temperature
+	}
+	if len(req.Tools) > 0 {
+		body["tools"] = toOpenAITools(req.Tools)
+	}
+	if stream {
+		body["stream"] = true
+		body["stream_options"] = map[string]any{"include_usage": true}
+	}
+	return json.Marshal(body)
+}
+
+// transportError wraps a failure to reach or read from the server,
+// reporting ErrTimeout if the client timeout expired.
+func (c *OpenAIClient) transportError(ctx context.Context, op string, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return openAIError(op, fmt.Errorf("%w: %v", claude.ErrTimeout, err))
+	}
+	if ctx.Err() != nil {
+		return openAIError(op, ctx.Err())
+	}
+	return openAIError(op, fmt.Errorf("%w: %v", claude.ErrUnavailable, err))
+}
+
+func openAIError(op string, err error) error {
+	return fmt.Errorf("openai %s: %w", op, err)
+}
+
+// statusError converts a non-200 response into an error wrapping the
+// matching llmkit sentinel and an *errors.HTTPError.
+func statusError(op, endpoint string, resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	message := strings.TrimSpace(string(data))
+	var code string
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+			Code    any    `json:"code"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(data, &body) == nil && body.Error.Message != "" {
+		message = body.Error.Message
+		code, _ = body.Error.Code.(string)
+	}
+
+	httpErr := &fgerrors.HTTPError{StatusCode: resp.StatusCode, Message: message, Endpoint: endpoint}
+	var sentinel error
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		sentinel = claude.ErrRateLimited
+	case resp.StatusCode >= 500:
+		sentinel = claude.ErrUnavailable
+	case code == "context_length_exceeded":
+		sentinel = claude.ErrContextTooLong
+	case resp.StatusCode == http.StatusBadRequest:
+		sentinel = claude.ErrInvalidRequest
+	default:
+		return openAIError(op, httpErr)
+	}
+	return openAIError(op, fmt.Errorf("%w: %w", sentinel, httpErr))
+}
+
+// Wire types for the chat completions API.
+
+type openAIMessage struct {
+	Role       string `json:"role"`
+	Content    string `json:"content"`
+	Name       string `json:"name,omitempty"`
+	ToolCallID string `json:"tool_call_id,omitempty"`
+}
+
+type openAITool struct {
+	Type     string         `json:"type"`
+	Function openAIFunction `json:"function"`
+}
+
+type openAIFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type openAIToolCall struct {
+	Index    int    `json:"index"`
+	ID       string `json:"id"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+func (u openAIUsage) toTokenUsage() TokenUsage {
+	return TokenUsage{
+		InputTokens:  u.PromptTokens,
+		OutputTokens: u.CompletionTokens,
+		TotalTokens:  u.TotalTokens,
+	}
+}
+
+type openAIResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message struct {
+			Content   string           `json:"content"`
+			ToolCalls []openAIToolCall `json:"tool_calls"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage openAIUsage `json:"usage"`
+}
+
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content   string           `json:"content"`
+			ToolCalls []openAIToolCall `json:"tool_calls"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+// toOpenAIMessages maps the system prompt and messages to chat messages.
+// For tool messages, Message.Name carries the ID of the tool call being
+// answered.
+func toOpenAIMessages(req CompletionRequest) []openAIMessage {
+	msgs := make([]openAIMessage, 0, len(req.Messages)+1)
+	if req.SystemPrompt != "" {
+		msgs = append(msgs, openAIMessage{Role: string(claude.RoleSystem), Content: req.SystemPrompt})
+	}
+	for _, m := range req.Messages {
+		msg := openAIMessage{Role: string(m.Role), Content: m.Content}
+		if m.Role == claude.RoleTool {
+			msg.ToolCallID = m.Name
+		} else {
+			msg.Name = m.Name
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+func toOpenAITools(tools []claude.Tool) []openAITool {
+	out := make([]openAITool, len(tools))
+	for i, t := range tools {
+		out[i] = openAITool{
+			Type: "function",
+			Function: openAIFunction{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.Parameters,
+			},
+		}
+	}
+	return out
+}
+
+func toToolCalls(calls []openAIToolCall) []claude.ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	out := make([]claude.ToolCall, len(calls))
+	for i, call := range calls {
+		out[i] = claude.ToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: toolArguments(call.Function.Arguments),
+		}
+	}
+	return out
+}
+
+// toolArguments returns the arguments as raw JSON, quoting them as a JSON
+// string if the model produced invalid JSON.
+func toolArguments(args string) json.RawMessage {
+	if json.Valid([]byte(args)) {
+		return json.RawMessage(args)
+	}
+	quoted, _ := json.Marshal(args)
+	return quoted
+}
+
+// readOpenAIStream reads server-sent events from r, calling onContent for
+// each content delta until it returns false. Returns the final chunk with
+// the assembled tool calls and usage.
+func readOpenAIStream(r io.Reader, onContent func(string) bool) (StreamChunk, error) {
+	var (
+		calls []openAIToolCall
+		usage *TokenUsage
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue // Blank separators, comments, and other fields
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return StreamChunk{}, fmt.Errorf("decode stream chunk: %w", err)
+		}
+		if chunk.Usage != nil {
+			u := chunk.Usage.toTokenUsage()
+			usage = &u
+		}
+		for _, choice := range chunk.Choices {
+			for _, delta := range choice.Delta.ToolCalls {
+				calls = mergeToolCallDelta(calls, delta)
+			}
+			if choice.Delta.Content != "" && !onContent(choice.Delta.Content) {
+				return StreamChunk{}, context.Canceled
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return StreamChunk{}, fmt.Errorf("read stream: %w", err)
+	}
+	return StreamChunk{ToolCalls: toToolCalls(calls), Usage: usage, Done: true}, nil
+}
+
+// mergeToolCallDelta adds a streamed tool call fragment to calls. The
+// first fragment of each call carries its ID and name; later ones append
+// to the arguments.
+func mergeToolCallDelta(calls []openAIToolCall, delta openAIToolCall) []openAIToolCall {
+	for delta.Index >= len(calls) {
+		calls = append(calls, openAIToolCall{Index: len(calls)})
+	}
+	call := &calls[delta.Index]
+	if delta.ID != "" {
+		call.ID = delta.ID
+	}
+	if delta.Function.Name != "" {
+		call.Function.Name = delta.Function.Name
+	}
+	call.Function.Arguments += delta.Function.Arguments
+	return calls
+}
@@ -0,0 +1,236 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
+	"github.com/randalmurphal/llmkit/claude"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openAIServer starts a server that records the decoded request body and
+// responds with handler.
+func openAIServer(t *testing.T, got *map[string]any, handler http.HandlerFunc) *OpenAIClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		if got != nil {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(got))
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return NewOpenAIClient(
+		WithOpenAIBaseURL(server.URL+"/v1/"),
+		WithOpenAIAPIKey("test-key"),
+		WithOpenAIModel("gpt-test"),
+	)
+}
+
+func TestOpenAIClient_Complete(t *testing.T) {
+	var got map[string]any
+	client := openAIServer(t, &got, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"model": "gpt-test-2024",
+			"choices": [{
+				"message": {
+					"content": "Hi there",
+					"tool_calls": [{"id": "call_1", "function": {"name": "lookup", "arguments": "{\"q\":\"go\"}"}}]
+				},
+				"finish_reason": "stop"
+			}],
+			"usage": {"prompt_tokens": 12, "completion_tokens": 3, "total_tokens": 15}
+		}`)
+	})
+
+	resp, err := client.Complete(context.Background(), CompletionRequest{
+		SystemPrompt: "Be brief",
+		Messages: []Message{
+			{Role: claude.RoleUser, Content: "Hello"},
+			{Role: claude.RoleTool, Content: "result", Name: "call_0"},
+		},
+		MaxTokens:   100,
+		Temperature: 0.5,
+		Tools:       []claude.Tool{{Name: "lookup", Parameters: json.RawMessage(`{"type":"object"}`)}},
+		Options:     map[string]any{"top_p": 0.9},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Hi there", resp.Content)
+	assert.Equal(t, "gpt-test-2024", resp.Model)
+	assert.Equal(t, "stop", resp.FinishReason)
+	assert.Equal(t, TokenUsage{InputTokens: 12, OutputTokens: 3, TotalTokens: 15}, resp.Usage)
+	require.Len(t, resp.ToolCalls, 1)
+	assert.Equal(t, "lookup", resp.ToolCalls[0].Name)
+	assert.JSONEq(t, `{"q":"go"}`, string(resp.ToolCalls[0].Arguments))
+
+	assert.Equal(t, "gpt-test", got["model"])
+	assert.Equal(t, []any{
+		map[string]any{"role": "system", "content": "Be brief"},
+		map[string]any{"role": "user", "content": "Hello"},
+		map[string]any{"role": "tool", "content": "result", "tool_call_id": "call_0"},
+	}, got["messages"])
+	assert.Equal(t, float64(100), got["max_tokens"])
+	assert.Equal(t, 0.5, got["temperature"])
+	assert.Equal(t, 0.9, got["top_p"])
+	assert.NotContains(t, got, "stream")
+	assert.Equal(t, []any{map[string]any{
+		"type":     "function",
+		"function": map[string]any{"name": "lookup", "parameters": map[string]any{"type": "object"}},
+	}}, got["tools"])
+}
+
+func TestOpenAIClient_RequestModelOverridesDefault(t *testing.T) {
+	var got map[string]any
+	client := openAIServer(t, &got, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"choices": [{"message": {"content": "ok"}}]}`)
+	})
+
+	_, err := client.Complete(context.Background(), CompletionRequest{Model: "gpt-other"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-other", got["model"])
+}
+
+func TestOpenAIClient_StatusErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		sentinel  error
+		retryable bool
+	}{
+		{"rate limited", http.StatusTooManyRequests, `{"error": {"message": "slow down"}}`, claude.ErrRateLimited, true},
+		{"server error", http.StatusInternalServerError, `oops`, claude.ErrUnavailable, true},
+		{"unavailable", http.StatusServiceUnavailable, ``, claude.ErrUnavailable, true},
+		{"bad request", http.StatusBadRequest, `{"error": {"message": "bad"}}`, claude.ErrInvalidRequest, false},
+		{"context too long", http.StatusBadRequest, `{"error": {"message": "too long", "code": "context_length_exceeded"}}`, claude.ErrContextTooLong, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := openAIServer(t, nil, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			})
+
+			_, err := client.Complete(context.Background(), CompletionRequest{})
+
+			require.ErrorIs(t, err, tt.sentinel)
+			var httpErr *fgerrors.HTTPError
+			require.ErrorAs(t, err, &httpErr)
+			assert.Equal(t, tt.status, httpErr.StatusCode)
+			assert.Equal(t, tt.retryable, fgerrors.IsRetryable(err))
+		})
+	}
+}
+
+func TestOpenAIClient_Unauthorized(t *testing.T) {
+	client := openAIServer(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error": {"message": "invalid key"}}`)
+	})
+
+	_, err := client.Complete(context.Background(), CompletionRequest{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid key")
+	assert.False(t, fgerrors.IsRetryable(err))
+}
+
+func TestOpenAIClient_Timeout(t *testing.T) {
+	client := openAIServer(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	})
+	WithOpenAITimeout(20 * time.Millisecond)(client)
+
+	_, err := client.Complete(context.Background(), CompletionRequest{})
+
+	assert.ErrorIs(t, err, claude.ErrTimeout)
+}
+
+func TestOpenAIClient_MissingModel(t *testing.T) {
+	client := NewOpenAIClient(WithOpenAIBaseURL("http://127.0.0.1:0"))
+
+	_, err := client.Complete(context.Background(), CompletionRequest{})
+
+	assert.ErrorIs(t, err, claude.ErrInvalidRequest)
+}
+
+func TestOpenAIClient_Stream(t *testing.T) {
+	var got map[string]any
+	client := openAIServer(t, &got, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, data := range []string{
+			`{"choices":[{"delta":{"role":"assistant","content":"Hel"}}]}`,
+			`{"choices":[{"delta":{"content":"lo"}}]}`,
+			`{"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_1","function":{"name":"lookup","arguments":"{\"q\":"}}]}}]}`,
+			`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"go\"}"}}]}}]}`,
+			`{"choices":[],"usage":{"prompt_tokens":5,"completion_tokens":2,"total_tokens":7}}`,
+			`[DONE]`,
+		} {
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+	})
+
+	ch, err := client.Stream(context.Background(), CompletionRequest{
+		Messages: []Message{{Role: claude.RoleUser, Content: "Hello"}},
+	})
+	require.NoError(t, err)
+
+	var chunks []StreamChunk
+	for chunk := range ch {
+		require.NoError(t, chunk.Error)
+		chunks = append(chunks, chunk)
+	}
+
+	require.Len(t, chunks, 3)
+	assert.Equal(t, "Hel", chunks[0].Content)
+	assert.Equal(t, "lo", chunks[1].Content)
+	final := chunks[2]
+	assert.True(t, final.Done)
+	assert.Equal(t, &TokenUsage{InputTokens: 5, OutputTokens: 2, TotalTokens: 7}, final.Usage)
+	require.Len(t, final.ToolCalls, 1)
+	assert.Equal(t, "call_1", final.ToolCalls[0].ID)
+	assert.JSONEq(t, `{"q":"go"}`, string(final.ToolCalls[0].Arguments))
+
+	assert.Equal(t, true, got["stream"])
+	assert.Equal(t, map[string]any{"include_usage": true}, got["stream_options"])
+}
+
+func TestOpenAIClient_StreamErrors(t *testing.T) {
+	t.Run("status", func(t *testing.T) {
+		client := openAIServer(t, nil, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+
+		_, err := client.Stream(context.Background(), CompletionRequest{})
+
+		assert.ErrorIs(t, err, claude.ErrRateLimited)
+	})
+
+	t.Run("malformed chunk", func(t *testing.T) {
+		client := openAIServer(t, nil, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"a\"}}]}\n\ndata: {oops\n\n")
+		})
+
+		ch, err := client.Stream(context.Background(), CompletionRequest{})
+		require.NoError(t, err)
+
+		var last StreamChunk
+		for chunk := range ch {
+			last = chunk
+		}
+		assert.ErrorIs(t, last.Error, claude.ErrUnavailable)
+		assert.False(t, last.Done)
+	})
+}
@@ -0,0 +1,102 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoJSON indicates no valid JSON object was found in a response.
+var ErrNoJSON = errors.New("no JSON object found in response")
+
+// PostProcessor transforms a completion response in place.
+// Returning an error fails the Complete call.
+type PostProcessor func(resp *CompletionResponse) error
+
+// PostProcessClient wraps a Client and runs a PostProcessor on every
+// completion response before returning it.
+type PostProcessClient struct {
+	inner Client
+	fn    PostProcessor
+}
+
+// NewPostProcessClient creates a client that applies fn to each response
+// returned by inner.Complete.
+//
+// Streaming responses are passed through unchanged, since chunks arrive
+// incrementally and cannot be post-processed as a whole.
+//
+// Panics if inner or fn is nil.
+func NewPostProcessClient(inner Client, fn PostProcessor) *PostProcessClient {
+	if inner == nil {
+		panic("llm: inner client cannot be nil")
+	}
+	if fn == nil {
+		panic("llm: post-processor cannot be nil")
+	}
+	return &PostProcessClient{inner: inner, fn: fn}
+}
+
+// Complete implements Client.
+func (c *PostProcessClient) Complete(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
+	resp, err := c.inner.Complete(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	if err := c.fn(resp); err != nil {
+		return resp, fmt.Errorf("post-process response: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Stream implements Client. Chunks are passed through unchanged.
+func (c *PostProcessClient) Stream(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
+	return c.inner.Stream(ctx, req)
+}
+
+// ChainPostProcessors combines processors to run in order.
+// Processing stops at the first error.
+func ChainPostProcessors(fns ...PostProcessor) PostProcessor {
+	return func(resp *CompletionResponse) error {
+		for _, fn := range fns {
+			if err := fn(resp); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ExtractJSON is a PostProcessor that replaces the response content with
+// the first valid JSON object it contains. Surrounding prose and markdown
+// code fences are discarded.
+//
+// Returns ErrNoJSON if the content contains no valid JSON object.
+func ExtractJSON(resp *CompletionResponse) error {
+	obj, ok := firstJSONObject(resp.Content)
+	if !ok {
+		return ErrNoJSON
+	}
+	resp.Content = obj
+	return nil
+}
+
+// firstJSONObject scans s for the first '{' that begins a valid JSON object.
+func firstJSONObject(s string) (string, bool) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '{' {
+			continue
+		}
+
+		dec := json.NewDecoder(strings.NewReader(s[i:]))
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == nil {
+			return string(raw), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/randalmurphal/llmkit/claude"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostProcessClient_ExtractJSON_FromFencedResponse(t *testing.T) {
+	inner := claude.NewMockClient("Here is the result:\n\n```json\n{\"name\": \"flowgraph\", \"tags\": [\"go\", \"llm\"]}\n```\n\nLet me know if you need more.")
+	client := NewPostProcessClient(inner, ExtractJSON)
+
+	resp, err := client.Complete(context.Background(), CompletionRequest{})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "flowgraph", "tags": ["go", "llm"]}`, resp.Content)
+}
+
+func TestPostProcessClient_ExtractJSON_SkipsInvalidBraces(t *testing.T) {
+	inner := claude.NewMockClient("Use {placeholders} like this: {\"ok\": true}")
+	client := NewPostProcessClient(inner, ExtractJSON)
+
+	resp, err := client.Complete(context.Background(), CompletionRequest{})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ok": true}`, resp.Content)
+}
+
+func TestPostProcessClient_ExtractJSON_NoJSON(t *testing.T) {
+	inner := claude.NewMockClient("I could not produce any JSON.")
+	client := NewPostProcessClient(inner, ExtractJSON)
+
+	_, err := client.Complete(context.Background(), CompletionRequest{})
+
+	assert.ErrorIs(t, err, ErrNoJSON)
+}
+
+func TestPostProcessClient_ProcessorErrorPropagates(t *testing.T) {
+	procErr := errors.New("response rejected")
+	inner := claude.NewMockClient("anything")
+	client := NewPostProcessClient(inner, func(resp *CompletionResponse) error {
+		return procErr
+	})
+
+	_, err := client.Complete(context.Background(), CompletionRequest{})
+
+	assert.ErrorIs(t, err, procErr)
+}
+
+func TestPostProcessClient_InnerErrorSkipsProcessor(t *testing.T) {
+	innerErr := errors.New("unavailable")
+	called := false
+	inner := claude.NewMockClient("").WithError(innerErr)
+	client := NewPostProcessClient(inner, func(resp *CompletionResponse) error {
+		called = true
+		return nil
+	})
+
+	_, err := client.Complete(context.Background(), CompletionRequest{})
+
+	assert.ErrorIs(t, err, innerErr)
+	assert.False(t, called)
+}
+
+func TestChainPostProcessors(t *testing.T) {
+	var order []string
+	extract := func(resp *CompletionResponse) error {
+		order = append(order, "extract")
+		return ExtractJSON(resp)
+	}
+	tag := func(resp *CompletionResponse) error {
+		order = append(order, "tag")
+		resp.FinishReason = "post-processed"
+		return nil
+	}
+
+	inner := claude.NewMockClient("```\n{\"a\": 1}\n```")
+	client := NewPostProcessClient(inner, ChainPostProcessors(extract, tag))
+
+	resp, err := client.Complete(context.Background(), CompletionRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"extract", "tag"}, order)
+	assert.JSONEq(t, `{"a": 1}`, resp.Content)
+	assert.Equal(t, "post-processed", resp.FinishReason)
+}
+
+func TestNewPostProcessClient_NilArgs_Panics(t *testing.T) {
+	assert.Panics(t, func() { NewPostProcessClient(nil, ExtractJSON) })
+	assert.Panics(t, func() { NewPostProcessClient(claude.NewMockClient(""), nil) })
+}
@@ -0,0 +1,70 @@
+package flowgraph
+
+import (
+	"context"
+	"sync"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/llm"
+)
+
+// llmSpend accumulates the LLM cost of one Run or Resume call.
+// It is shared by every node context of the run, including parallel
+// branches and subgraphs.
+type llmSpend struct {
+	mu    sync.Mutex
+	spent float64
+	limit float64 // 0 means unlimited
+}
+
+// total returns the spend so far.
+func (s *llmSpend) total() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.spent
+}
+
+// check returns a *BudgetExceededError if the limit has been reached.
+func (s *llmSpend) check(nodeID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.limit > 0 && s.spent >= s.limit {
+		return &BudgetExceededError{NodeID: nodeID, SpentUSD: s.spent, LimitUSD: s.limit}
+	}
+	return nil
+}
+
+// add records the cost of a completed call.
+func (s *llmSpend) add(cost float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.spent += cost
+}
+
+// meteredClient counts the cost of each call toward the run's spend and
+// refuses calls once the run budget is used up.
+type meteredClient struct {
+	client llm.Client
+	spend  *llmSpend
+	nodeID string
+}
+
+// Complete checks the budget, calls the client, and records the cost.
+func (m *meteredClient) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	if err := m.spend.check(m.nodeID); err != nil {
+		return nil, err
+	}
+	resp, err := m.client.Complete(ctx, req)
+	if resp != nil {
+		m.spend.add(resp.CostUSD)
+	}
+	return resp, err
+}
+
+// Stream checks the budget and calls the client. Streamed responses do
+// not report cost, so they are not counted.
+func (m *meteredClient) Stream(ctx context.Context, req llm.CompletionRequest) (<-chan llm.StreamChunk, error) {
+	if err := m.spend.check(m.nodeID); err != nil {
+		return nil, err
+	}
+	return m.client.Stream(ctx, req)
+}
@@ -0,0 +1,123 @@
+package flowgraph
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// costClient is an llm.Client whose completions each cost a fixed amount.
+type costClient struct {
+	cost  float64
+	calls atomic.Int32
+}
+
+func (c *costClient) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	c.calls.Add(1)
+	return &llm.CompletionResponse{Content: "ok", CostUSD: c.cost}, nil
+}
+
+func (c *costClient) Stream(ctx context.Context, req llm.CompletionRequest) (<-chan llm.StreamChunk, error) {
+	c.calls.Add(1)
+	ch := make(chan llm.StreamChunk, 1)
+	ch <- llm.StreamChunk{Done: true}
+	close(ch)
+	return ch, nil
+}
+
+// callLLM is a node that makes one completion call and records the run's
+// total spend in Counter.Value, in cents.
+func callLLM(ctx Context, s Counter) (Counter, error) {
+	if _, err := ctx.LLM().Complete(ctx, llm.CompletionRequest{}); err != nil {
+		return s, err
+	}
+	s.Value = int(ctx.TotalCostUSD()*100 + 0.5)
+	return s, nil
+}
+
+// TestTotalCostUSD_SumsCalls tests that spend accumulates across nodes.
+func TestTotalCostUSD_SumsCalls(t *testing.T) {
+	client := &costClient{cost: 0.25}
+	compiled := linearCounterGraph(t, 3, callLLM)
+	ctx := NewContext(context.Background(), WithLLM(client))
+
+	result, err := compiled.Run(ctx, Counter{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 75, result.Value)
+	assert.Equal(t, int32(3), client.calls.Load())
+}
+
+// TestTotalCostUSD_FreshPerRun tests that each Run starts from zero spend.
+func TestTotalCostUSD_FreshPerRun(t *testing.T) {
+	compiled := linearCounterGraph(t, 2, callLLM)
+	ctx := NewContext(context.Background(), WithLLM(&costClient{cost: 0.5}))
+
+	_, err := compiled.Run(ctx, Counter{})
+	require.NoError(t, err)
+	result, err := compiled.Run(ctx, Counter{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 100, result.Value)
+	assert.Zero(t, ctx.TotalCostUSD())
+}
+
+// TestWithRunBudgetUSD_FailsNextCall tests that the call after the budget is reached fails.
+func TestWithRunBudgetUSD_FailsNextCall(t *testing.T) {
+	client := &costClient{cost: 0.4}
+	compiled := linearCounterGraph(t, 4, callLLM)
+	ctx := NewContext(context.Background(), WithLLM(client))
+
+	_, err := compiled.Run(ctx, Counter{}, WithRunBudgetUSD(1.0))
+
+	var budgetErr *BudgetExceededError
+	require.ErrorAs(t, err, &budgetErr)
+	assert.True(t, errors.Is(err, ErrBudgetExceeded))
+	assert.Equal(t, "d", budgetErr.NodeID)
+	assert.InDelta(t, 1.2, budgetErr.SpentUSD, 1e-9)
+	assert.Equal(t, 1.0, budgetErr.LimitUSD)
+	assert.Equal(t, int32(3), client.calls.Load(), "refused call must not reach the client")
+}
+
+// TestWithRunBudgetUSD_ParallelBranches tests that fork branches share one budget.
+func TestWithRunBudgetUSD_ParallelBranches(t *testing.T) {
+	client := &costClient{cost: 1}
+	graph := NewGraph[Counter]().
+		AddNode("start", passthrough[Counter]).
+		AddNode("a", callLLM).
+		AddNode("b", callLLM).
+		AddNode("join", passthrough[Counter]).
+		AddEdge("start", "a").
+		AddEdge("start", "b").
+		AddEdge("a", "join").
+		AddEdge("b", "join").
+		AddEdge("join", END).
+		SetEntry("start")
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	ctx := NewContext(context.Background(), WithLLM(client))
+	_, err = compiled.Run(ctx, Counter{}, WithRunBudgetUSD(1))
+
+	assert.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.Equal(t, int32(1), client.calls.Load())
+}
+
+// TestContext_LLMUnconfigured tests that LLM is nil without WithLLM.
+func TestContext_LLMUnconfigured(t *testing.T) {
+	ctx := NewContext(context.Background())
+
+	assert.Nil(t, ctx.LLM())
+	assert.Zero(t, ctx.TotalCostUSD())
+}
+
+// TestWithRunBudgetUSD_Panics tests invalid budgets panic.
+func TestWithRunBudgetUSD_Panics(t *testing.T) {
+	assert.Panics(t, func() { WithRunBudgetUSD(0) })
+	assert.Panics(t, func() { WithRunBudgetUSD(-1) })
+}
@@ -0,0 +1,94 @@
+package flowgraph
+
+import (
+	"runtime/debug"
+	"time"
+)
+
+// NodeMiddleware wraps node functions to add cross-cutting concerns such
+// as authorization checks, state logging or mutation guards.
+//
+// Middleware runs inside the node's execution: ctx.NodeID() and
+// ctx.Attempt() identify the node and attempt, an error returned without
+// calling next short-circuits the node and fails it like a node error, and
+// panics are recovered by the executor.
+type NodeMiddleware[S any] func(next NodeFunc[S]) NodeFunc[S]
+
+// ChainNodeMiddleware applies middleware in order, with first middleware outermost.
+func ChainNodeMiddleware[S any](fn NodeFunc[S], middleware ...NodeMiddleware[S]) NodeFunc[S] {
+	// Apply in reverse order so first middleware is outermost
+	for i := len(middleware) - 1; i >= 0; i-- {
+		fn = middleware[i](fn)
+	}
+	return fn
+}
+
+// Use adds middleware around every node of the graph, applied in
+// registration order with the first outermost. Middleware wraps each
+// attempt of a node with WithNodeRetry, and the embedded graph of a
+// subgraph node as a whole; the subgraph's own nodes run under the
+// subgraph's middleware. Node cache hits (WithNodeCache) skip the node
+// and its middleware.
+//
+// Panics if mw is nil.
+//
+// Example:
+//
+//	graph.Use(flowgraph.RecoveryMiddleware[State]()).
+//	    Use(flowgraph.TimingMiddleware[State](func(nodeID string, d time.Duration, err error) {
+//	        log.Printf("node %s took %s", nodeID, d)
+//	    }))
+func (g *Graph[S]) Use(mw NodeMiddleware[S]) *Graph[S] {
+	if mw == nil {
+		panic("flowgraph: node middleware cannot be nil")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.middleware = append(g.middleware, mw)
+	return g
+}
+
+// RecoveryMiddleware recovers panics in a node, logs the stack to the
+// node's logger, and returns a *PanicError, as the executor's own recovery
+// does. Middleware registered after it sees the panic as the node's error.
+// As with any panic, error edges route it only under
+// WithErrorEdgeCatchesPanics.
+func RecoveryMiddleware[S any]() NodeMiddleware[S] {
+	return func(next NodeFunc[S]) NodeFunc[S] {
+		return func(ctx Context, state S) (result S, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					stack := string(debug.Stack())
+					ctx.Logger().Error("node panic recovered", "panic", r, "stack", stack)
+					result = state
+					err = &PanicError{
+						NodeID: ctx.NodeID(),
+						Value:  r,
+						Stack:  stack,
+					}
+				}
+			}()
+			return next(ctx, state)
+		}
+	}
+}
+
+// TimingMiddleware reports how long each node execution took, and its
+// error, to record.
+//
+// Panics if record is nil.
+func TimingMiddleware[S any](record func(nodeID string, duration time.Duration, err error)) NodeMiddleware[S] {
+	if record == nil {
+		panic("flowgraph: timing record function cannot be nil")
+	}
+	return func(next NodeFunc[S]) NodeFunc[S] {
+		return func(ctx Context, state S) (S, error) {
+			start := time.Now()
+			result, err := next(ctx, state)
+			record(ctx.NodeID(), time.Since(start), err)
+			return result, err
+		}
+	}
+}
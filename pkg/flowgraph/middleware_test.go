@@ -0,0 +1,151 @@
+package flowgraph
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tagMiddleware records name before and after each node it wraps.
+func tagMiddleware(name string, tracker *[]string) NodeMiddleware[State] {
+	return func(next NodeFunc[State]) NodeFunc[State] {
+		return func(ctx Context, s State) (State, error) {
+			*tracker = append(*tracker, name+">"+ctx.NodeID())
+			result, err := next(ctx, s)
+			*tracker = append(*tracker, name+"<"+ctx.NodeID())
+			return result, err
+		}
+	}
+}
+
+// TestUse_RegistrationOrder tests that the first middleware is outermost around every node.
+func TestUse_RegistrationOrder(t *testing.T) {
+	var tracker []string
+	compiled, err := NewGraph[State]().
+		AddNode("a", makeTrackingNode("a", &tracker)).
+		AddNode("b", makeTrackingNode("b", &tracker)).
+		AddEdge("a", "b").
+		AddEdge("b", END).
+		SetEntry("a").
+		Use(tagMiddleware("outer", &tracker)).
+		Use(tagMiddleware("inner", &tracker)).
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"outer>a", "inner>a", "a", "inner<a", "outer<a",
+		"outer>b", "inner>b", "b", "inner<b", "outer<b",
+	}, tracker)
+}
+
+// TestUse_ShortCircuit tests that middleware can fail a node without running it.
+func TestUse_ShortCircuit(t *testing.T) {
+	var tracker []string
+	errDenied := errors.New("denied")
+	guard := func(next NodeFunc[State]) NodeFunc[State] {
+		return func(ctx Context, s State) (State, error) {
+			if ctx.NodeID() == "admin" {
+				return s, errDenied
+			}
+			return next(ctx, s)
+		}
+	}
+	compiled, err := NewGraph[State]().
+		AddNode("a", makeTrackingNode("a", &tracker)).
+		AddNode("admin", makeTrackingNode("admin", &tracker)).
+		AddEdge("a", "admin").
+		AddEdge("admin", END).
+		SetEntry("a").
+		Use(guard).
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{})
+
+	var nodeErr *NodeError
+	require.ErrorAs(t, err, &nodeErr)
+	assert.Equal(t, "admin", nodeErr.NodeID)
+	assert.ErrorIs(t, err, errDenied)
+	assert.Equal(t, []string{"a"}, tracker)
+}
+
+// TestRecoveryMiddleware tests that recovered panics are reported as a
+// *PanicError and routed by error edges only when panics are caught.
+func TestRecoveryMiddleware(t *testing.T) {
+	var tracker []string
+	var seen error
+	compiled, err := NewGraph[State]().
+		AddNode("boom", makePanicNode("kaboom")).
+		AddNode("recover", makeTrackingNode("recover", &tracker)).
+		AddEdge("boom", END).
+		AddEdge("recover", END).
+		AddErrorEdge("boom", "recover").
+		SetEntry("boom").
+		Use(TimingMiddleware[State](func(_ string, _ time.Duration, err error) {
+			if err != nil {
+				seen = err
+			}
+		})).
+		Use(RecoveryMiddleware[State]()).
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{})
+
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.NodeID)
+	assert.Equal(t, "kaboom", panicErr.Value)
+	assert.NotEmpty(t, panicErr.Stack)
+	assert.ErrorAs(t, seen, &panicErr, "outer middleware sees the recovered panic")
+	assert.Empty(t, tracker)
+
+	_, err = compiled.Run(testCtx(), State{}, WithErrorEdgeCatchesPanics())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"recover"}, tracker)
+}
+
+// TestTimingMiddleware tests that node durations and errors are reported.
+func TestTimingMiddleware(t *testing.T) {
+	type timing struct {
+		nodeID string
+		err    error
+	}
+	var timings []timing
+	errFail := errors.New("fail")
+	compiled, err := NewGraph[State]().
+		AddNode("ok", passthrough[State]).
+		AddNode("fail", makeFailingNode(errFail)).
+		AddEdge("ok", "fail").
+		AddEdge("fail", END).
+		SetEntry("ok").
+		Use(TimingMiddleware[State](func(nodeID string, d time.Duration, err error) {
+			assert.GreaterOrEqual(t, d, time.Duration(0))
+			timings = append(timings, timing{nodeID, err})
+		})).
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{})
+	require.Error(t, err)
+	assert.Equal(t, []timing{{"ok", nil}, {"fail", errFail}}, timings)
+}
+
+// TestUse_NilPanics tests that nil middleware panics.
+func TestUse_NilPanics(t *testing.T) {
+	assert.PanicsWithValue(t, "flowgraph: node middleware cannot be nil", func() {
+		NewGraph[State]().Use(nil)
+	})
+}
+
+// TestTimingMiddleware_NilPanics tests that a nil record function panics.
+func TestTimingMiddleware_NilPanics(t *testing.T) {
+	assert.PanicsWithValue(t, "flowgraph: timing record function cannot be nil", func() {
+		TimingMiddleware[Counter](nil)
+	})
+}
@@ -1,9 +1,17 @@
 package flowgraph
 
+import (
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
+)
+
 // END is the terminal node identifier.
 // Use this as an edge target to indicate the graph should terminate.
 const END = "__end__"
 
+// START is the node ID of the checkpoint that records a run's initial
+// state when WithInitialCheckpoint is used. It is never executed.
+const START = "__start__"
+
 // NodeFunc is the signature for all node functions.
 // Nodes receive the execution context and current state,
 // and return the updated state (or the same state) and any error.
@@ -34,3 +42,56 @@ type NodeFunc[S any] func(ctx Context, state S) (S, error)
 //	    return "process"
 //	}
 type RouterFunc[S any] func(ctx Context, state S) string
+
+// FanOutFunc selects the branches to run in parallel after a node.
+// It is used with Graph.AddDynamicFanOut when the number of branches is only
+// known at runtime.
+//
+// Each returned ID must name an existing node that can reach the fan-out's
+// join node. Returning an empty slice skips straight to the join.
+//
+// Example:
+//
+//	func perItem(ctx flowgraph.Context, s State) []string {
+//	    targets := make([]string, 0, len(s.Items))
+//	    for _, item := range s.Items {
+//	        targets = append(targets, "process-"+item.Kind)
+//	    }
+//	    return targets
+//	}
+type FanOutFunc[S any] func(ctx Context, state S) []string
+
+// EntrySelectorFunc picks the entry node based on the initial state.
+// It is evaluated once at the start of Run.
+//
+// The selector must return the ID of an existing node.
+// Returning an empty string, END, or an unknown node ID causes Run to fail
+// with ErrInvalidEntrySelection.
+type EntrySelectorFunc[S any] func(state S) string
+
+// NodeOption configures per-node execution behavior.
+// Pass node options as trailing arguments to Graph.AddNode.
+type NodeOption func(*nodeConfig)
+
+// nodeConfig holds per-node execution settings.
+type nodeConfig struct {
+	retry *fgerrors.RetryConfig
+}
+
+// WithNodeRetry retries the node on transient errors using the backoff and
+// jitter settings of the given retry configuration.
+//
+// Errors are classified with errors.Categorize (or cfg.RetryableFunc if set);
+// non-retryable errors fail immediately. Context cancellation is respected
+// between attempts. Context.Attempt() reports the current attempt number,
+// starting at 1. Checkpoints are only written after the final successful attempt.
+//
+// Example:
+//
+//	graph.AddNode("fetch", fetch, flowgraph.WithNodeRetry(
+//	    errors.NewRetryConfig(errors.WithMaxAttempts(5))))
+func WithNodeRetry(cfg fgerrors.RetryConfig) NodeOption {
+	return func(c *nodeConfig) {
+		c.retry = &cfg
+	}
+}
@@ -0,0 +1,177 @@
+package flowgraph
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/checkpoint"
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastRetry is a retry policy with negligible backoff for tests.
+var fastRetry = fgerrors.NewRetryConfig(
+	fgerrors.WithMaxAttempts(3),
+	fgerrors.WithInitialBackoff(time.Millisecond),
+	fgerrors.WithMaxBackoff(time.Millisecond),
+	fgerrors.WithJitter(0),
+)
+
+// TestWithNodeRetry_TransientThenSuccess tests that transient errors are retried.
+func TestWithNodeRetry_TransientThenSuccess(t *testing.T) {
+	var attempts []int
+
+	flaky := func(ctx Context, s Counter) (Counter, error) {
+		attempts = append(attempts, ctx.Attempt())
+		if len(attempts) < 3 {
+			return s, fgerrors.Transient(errors.New("rate limited"), "fetch")
+		}
+		s.Value++
+		return s, nil
+	}
+
+	graph := NewGraph[Counter]().
+		AddNode("fetch", flaky, WithNodeRetry(fastRetry)).
+		AddEdge("fetch", END).
+		SetEntry("fetch")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), Counter{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Value)
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+}
+
+// TestWithNodeRetry_PermanentFailsImmediately tests that non-retryable errors are not retried.
+func TestWithNodeRetry_PermanentFailsImmediately(t *testing.T) {
+	calls := 0
+	permanentErr := errors.New("invalid config")
+
+	node := func(ctx Context, s Counter) (Counter, error) {
+		calls++
+		return s, permanentErr
+	}
+
+	graph := NewGraph[Counter]().
+		AddNode("fetch", node, WithNodeRetry(fastRetry)).
+		AddEdge("fetch", END).
+		SetEntry("fetch")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), Counter{})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+	assert.ErrorIs(t, err, permanentErr)
+
+	var nodeErr *NodeError
+	require.ErrorAs(t, err, &nodeErr)
+	assert.Equal(t, "fetch", nodeErr.NodeID)
+}
+
+// TestWithNodeRetry_Exhausted tests that the last error is returned after max attempts.
+func TestWithNodeRetry_Exhausted(t *testing.T) {
+	calls := 0
+	transientErr := fgerrors.Transient(errors.New("timeout"), "fetch")
+
+	node := func(ctx Context, s Counter) (Counter, error) {
+		calls++
+		return s, transientErr
+	}
+
+	graph := NewGraph[Counter]().
+		AddNode("fetch", node, WithNodeRetry(fastRetry)).
+		AddEdge("fetch", END).
+		SetEntry("fetch")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), Counter{})
+
+	require.Error(t, err)
+	assert.Equal(t, 3, calls)
+	assert.ErrorIs(t, err, transientErr)
+}
+
+// TestWithNodeRetry_RespectsCancellation tests that cancellation stops retrying during backoff.
+func TestWithNodeRetry_RespectsCancellation(t *testing.T) {
+	calls := 0
+	ctx, cancel := context.WithCancel(context.Background())
+
+	node := func(fctx Context, s Counter) (Counter, error) {
+		calls++
+		cancel()
+		return s, fgerrors.Transient(errors.New("unavailable"), "fetch")
+	}
+
+	slowRetry := fgerrors.NewRetryConfig(
+		fgerrors.WithMaxAttempts(5),
+		fgerrors.WithInitialBackoff(time.Minute),
+	)
+
+	graph := NewGraph[Counter]().
+		AddNode("fetch", node, WithNodeRetry(slowRetry)).
+		AddEdge("fetch", END).
+		SetEntry("fetch")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(NewContext(ctx), Counter{})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// TestWithNodeRetry_CheckpointOnlyAfterSuccess tests that retries don't write extra checkpoints.
+func TestWithNodeRetry_CheckpointOnlyAfterSuccess(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	calls := 0
+
+	flaky := func(ctx Context, s Counter) (Counter, error) {
+		calls++
+		s.Value += 10
+		if calls < 3 {
+			return s, fgerrors.Transient(errors.New("busy"), "fetch")
+		}
+		return s, nil
+	}
+
+	graph := NewGraph[Counter]().
+		AddNode("fetch", flaky, WithNodeRetry(fastRetry)).
+		AddNode("inc", increment).
+		AddEdge("fetch", "inc").
+		AddEdge("inc", END).
+		SetEntry("fetch")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), Counter{},
+		WithCheckpointing(store),
+		WithRunID("retry-run"))
+	require.NoError(t, err)
+	assert.Equal(t, 11, result.Value)
+
+	infos, err := store.List("retry-run")
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Equal(t, "fetch", infos[0].NodeID)
+	assert.Equal(t, 1, infos[0].Sequence)
+
+	data, err := store.Load("retry-run", "fetch")
+	require.NoError(t, err)
+	cp, err := checkpoint.Unmarshal(data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Value":10}`, string(cp.State))
+}
@@ -0,0 +1,136 @@
+package flowgraph
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Cache stores node results for WithNodeCache. Keys are hashes of a node's
+// ID and input state; values are the node's output state encoded as JSON.
+//
+// Implementations must be safe for concurrent use, since parallel branches
+// may run the same node at once.
+type Cache interface {
+	// Get returns the value stored under key, if any.
+	Get(key string) ([]byte, bool)
+
+	// Set stores value under key.
+	Set(key string, value []byte)
+}
+
+// LRUCache is an in-memory Cache that holds up to a fixed number of
+// entries, evicting the least recently used entry when full.
+// It is safe for concurrent use.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently used
+	entries  map[string]*list.Element
+}
+
+// lruEntry is an element of LRUCache.order.
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+// NewLRUCache creates an LRUCache holding up to capacity entries.
+//
+// Panics if capacity is not positive.
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity <= 0 {
+		panic("flowgraph: cache capacity must be > 0")
+	}
+	return &LRUCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value stored under key and marks it recently used.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).value, true
+}
+
+// Set stores value under key, evicting the least recently used entry if
+// the cache is full.
+func (c *LRUCache) Set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEntry).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// executeCachedNode answers nodeID from cache when its input state was
+// seen before, and otherwise executes it and caches a successful result.
+func (cg *CompiledGraph[S]) executeCachedNode(ctx Context, nodeID string, state S, cfg *runConfig, cache Cache) (S, error) {
+	key, err := nodeCacheKey(nodeID, state)
+	if err != nil {
+		return state, &NodeError{NodeID: nodeID, Op: "cache", Err: err}
+	}
+
+	if data, ok := cache.Get(key); ok {
+		var cached S
+		if err := json.Unmarshal(data, &cached); err != nil {
+			return state, &NodeError{NodeID: nodeID, Op: "cache", Err: fmt.Errorf("decode cached state: %w", err)}
+		}
+		return cached, nil
+	}
+
+	result, err := cg.invokeNode(ctx, nodeID, state, cfg)
+	if err != nil {
+		return result, err
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return result, &NodeError{NodeID: nodeID, Op: "cache", Err: fmt.Errorf("%w: %w", ErrSerializeState, err)}
+	}
+	cache.Set(key, data)
+	return result, nil
+}
+
+// nodeCacheKey hashes the node ID and the JSON encoding of its input state.
+func nodeCacheKey[S any](nodeID string, state S) (string, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrSerializeState, err)
+	}
+
+	h := sha256.New()
+	h.Write([]byte(nodeID))
+	h.Write([]byte{0})
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,95 @@
+package flowgraph
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingGraph builds a single "inc" node that counts its invocations.
+func countingGraph(t *testing.T, calls *atomic.Int32, err error) *CompiledGraph[Counter] {
+	t.Helper()
+	compiled, compileErr := NewGraph[Counter]().
+		AddNode("inc", func(ctx Context, s Counter) (Counter, error) {
+			calls.Add(1)
+			s.Value++
+			return s, err
+		}).
+		AddEdge("inc", END).
+		SetEntry("inc").
+		Compile()
+	require.NoError(t, compileErr)
+	return compiled
+}
+
+// TestWithNodeCache_Hit tests that a repeated input state is answered from the cache.
+func TestWithNodeCache_Hit(t *testing.T) {
+	var calls atomic.Int32
+	compiled := countingGraph(t, &calls, nil)
+	cache := NewLRUCache(10)
+
+	for range 3 {
+		result, err := compiled.Run(testCtx(), Counter{Value: 1}, WithNodeCache("inc", cache))
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Value)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+
+	_, err := compiled.Run(testCtx(), Counter{Value: 5}, WithNodeCache("inc", cache))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load(), "different input state should miss")
+	assert.Equal(t, 2, cache.Len())
+}
+
+// TestWithNodeCache_ErrorsNotCached tests that failed results are not stored.
+func TestWithNodeCache_ErrorsNotCached(t *testing.T) {
+	var calls atomic.Int32
+	compiled := countingGraph(t, &calls, errors.New("boom"))
+	cache := NewLRUCache(10)
+
+	for range 2 {
+		_, err := compiled.Run(testCtx(), Counter{}, WithNodeCache("inc", cache))
+		require.Error(t, err)
+	}
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, 0, cache.Len())
+}
+
+// TestWithNodeCache_UnserializableState tests that caching state that cannot be encoded fails the node.
+func TestWithNodeCache_UnserializableState(t *testing.T) {
+	compiled, err := NewGraph[chan int]().
+		AddNode("n", passthrough[chan int]).
+		AddEdge("n", END).
+		SetEntry("n").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), make(chan int), WithNodeCache("n", NewLRUCache(1)))
+
+	var nodeErr *NodeError
+	require.ErrorAs(t, err, &nodeErr)
+	assert.Equal(t, "cache", nodeErr.Op)
+	assert.ErrorIs(t, err, ErrSerializeState)
+}
+
+// TestLRUCache_Eviction tests that the least recently used entry is evicted.
+func TestLRUCache_Eviction(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", []byte("1"))
+	cache.Set("b", []byte("2"))
+	_, _ = cache.Get("a") // b is now least recently used
+	cache.Set("c", []byte("3"))
+
+	_, ok := cache.Get("b")
+	assert.False(t, ok)
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+	assert.Equal(t, 2, cache.Len())
+
+	assert.PanicsWithValue(t, "flowgraph: cache capacity must be > 0", func() { NewLRUCache(0) })
+	assert.PanicsWithValue(t, "flowgraph: node cache cannot be nil", func() { WithNodeCache("n", nil) })
+}
@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"errors"
 	"log/slog"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -193,6 +195,54 @@ func TestRun_WithMetrics_Enabled(t *testing.T) {
 	assert.Equal(t, 1, result.Value)
 }
 
+// spyMetrics records the metrics calls it receives.
+type spyMetrics struct {
+	mu         sync.Mutex
+	nodes      []string
+	nodeErrors int
+	runs       []bool
+}
+
+func (s *spyMetrics) RecordNodeExecution(_ context.Context, nodeID string, _ time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nodes = append(s.nodes, nodeID)
+	if err != nil {
+		s.nodeErrors++
+	}
+}
+
+func (s *spyMetrics) RecordGraphRun(_ context.Context, success bool, _ time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs = append(s.runs, success)
+}
+
+func (s *spyMetrics) RecordCheckpoint(_ context.Context, _ string, _ int64) {}
+
+func TestRun_WithMetricsCollector(t *testing.T) {
+	compiled, err := NewGraph[State]().
+		AddNode("ok", passthrough[State]).
+		AddNode("fail", makeFailingNode(errors.New("boom"))).
+		AddEdge("ok", "fail").
+		AddEdge("fail", END).
+		SetEntry("ok").
+		Compile()
+	require.NoError(t, err)
+
+	spy := &spyMetrics{}
+	_, err = compiled.Run(testCtx(), State{}, WithMetricsCollector(spy))
+
+	require.Error(t, err)
+	assert.Equal(t, []string{"ok", "fail"}, spy.nodes)
+	assert.Equal(t, 1, spy.nodeErrors)
+	assert.Equal(t, []bool{false}, spy.runs)
+
+	assert.PanicsWithValue(t, "flowgraph: metrics collector cannot be nil", func() {
+		WithMetricsCollector(nil)
+	})
+}
+
 func TestRun_WithTracing_Disabled(t *testing.T) {
 	// Tracing disabled by default - should not panic
 	graph := NewGraph[Counter]().
@@ -1,28 +1,67 @@
 package flowgraph
 
 import (
+	"crypto/cipher"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/randalmurphal/flowgraph/pkg/flowgraph/checkpoint"
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
 	"github.com/randalmurphal/flowgraph/pkg/flowgraph/observability"
 )
 
 // runConfig holds configuration for graph execution.
 type runConfig struct {
 	maxIterations int
+	maxStateSize  int // bytes; 0 disables the check
+	nodeTimeouts  map[string]time.Duration
+	nodeRegistry  any // *registry.Registry[string, NodeFunc[S]], typed at resolution
+	faults        map[string]Fault
+	nodeCaches    map[string]Cache
+	parallelism   *parallelismLimit // Shared by all fork/join branches
+
+	// Compensation
+	graphCompensation bool
+	compensationLog   *compensationLog // completed compensating nodes of the current call
+
+	// Error edges
+	errorEdgeCatchesPanics bool
+
+	// Whole-run retry
+	runRetry *fgerrors.RetryConfig
+
+	// Budgets
+	timeBudget      time.Duration
+	costBudget      float64
+	costFunc        func(any) float64
+	suspendFraction float64 // 0 disables suspension
+	runBudgetUSD    float64 // LLM spend limit; 0 disables the check
 
 	// Checkpointing
 	checkpointStore        checkpoint.Store
 	runID                  string
 	checkpointFailureFatal bool
+	checkpointCompression  Compression
+	checkpointCodec        Codec // nil means JSONCodec
+	checkpointCipher       cipher.AEAD
+	checkpointMetadata     map[string]string
+	checkpointInterval     int // save after every Nth node; 0 or 1 saves after each
+	initialCheckpoint      bool
 	sequence               int
 
+	// Query
+	live *liveRun // nil unless this is a top-level Run or Resume
+
 	// Resume
+	resuming      bool // set by Resume and ResumeFrom
 	stateOverride func(any) any
 	validateState func(any) error
 	replayNode    bool
 
 	// Observability
+	branchRecorder *BranchRecorder
+	trace          *Trace
 	logger         *slog.Logger
 	metricsEnabled bool
 	tracingEnabled bool
@@ -76,6 +115,327 @@ func WithMaxIterations(n int) RunOption {
 	}
 }
 
+// WithMaxStateSize limits the JSON-encoded size of state between nodes.
+// After each node completes, the state is serialized and measured; if it
+// exceeds n bytes, Run fails with a *StateSizeError naming the node.
+//
+// This guards against state that grows without bound in loops and applies
+// whether or not checkpointing is enabled. The check costs one JSON encode
+// per node and is skipped entirely unless this option is set.
+//
+// Panics if n <= 0.
+//
+// Example:
+//
+//	result, err := compiled.Run(ctx, state, flowgraph.WithMaxStateSize(10<<20))
+func WithMaxStateSize(n int) RunOption {
+	if n <= 0 {
+		panic("flowgraph: max state size must be > 0")
+	}
+	return func(c *runConfig) {
+		c.maxStateSize = n
+	}
+}
+
+// WithTimeBudget limits the wall-clock time of a single Run or Resume call.
+// The budget is checked at node boundaries: once it is used up, the run
+// fails with a *BudgetError before starting the next node. A node that is
+// already executing is not interrupted; use WithNodeTimeout for that.
+//
+// Each Run or Resume call gets a fresh budget, which suits pre-emptible
+// or serverless invocations with a fixed time limit.
+//
+// Panics if d <= 0.
+//
+// Example:
+//
+//	result, err := compiled.Run(ctx, state, flowgraph.WithTimeBudget(14*time.Minute))
+func WithTimeBudget(d time.Duration) RunOption {
+	if d <= 0 {
+		panic("flowgraph: time budget must be > 0")
+	}
+	return func(c *runConfig) {
+		c.timeBudget = d
+	}
+}
+
+// WithCostBudget limits the cost of a run as measured from its state.
+// cost receives the current state and returns the cost accumulated so far,
+// e.g. LLM spend tracked in a state field. Because cost is read from state,
+// it carries over across Resume calls.
+//
+// The budget is checked at node boundaries: once cost reaches limit, the
+// run fails with a *BudgetError before starting the next node.
+//
+// Panics if limit <= 0 or cost is nil.
+//
+// Example:
+//
+//	result, err := compiled.Run(ctx, state,
+//	    flowgraph.WithCostBudget(5.00, func(s any) float64 {
+//	        return s.(MyState).SpentUSD
+//	    }))
+func WithCostBudget(limit float64, cost func(state any) float64) RunOption {
+	if limit <= 0 {
+		panic("flowgraph: cost budget must be > 0")
+	}
+	if cost == nil {
+		panic("flowgraph: cost function cannot be nil")
+	}
+	return func(c *runConfig) {
+		c.costBudget = limit
+		c.costFunc = cost
+	}
+}
+
+// WithRunBudgetUSD caps the LLM spend of a run. Every Complete call made
+// through Context.LLM adds its CompletionResponse.CostUSD to the run total
+// (see Context.TotalCostUSD); once the total reaches limit, the next LLM
+// call fails with a *BudgetExceededError without reaching the provider.
+//
+// Spend is tracked in memory, so each Run or Resume call starts from zero.
+// Streaming calls are checked against the budget but not counted, since
+// stream chunks do not report cost.
+//
+// Panics if limit <= 0.
+//
+// Example:
+//
+//	ctx := flowgraph.NewContext(context.Background(), flowgraph.WithLLM(client))
+//	result, err := compiled.Run(ctx, state, flowgraph.WithRunBudgetUSD(2.50))
+//	if errors.Is(err, flowgraph.ErrBudgetExceeded) {
+//	    // Some node tried to call the LLM after the run spent $2.50
+//	}
+func WithRunBudgetUSD(limit float64) RunOption {
+	if limit <= 0 {
+		panic("flowgraph: run budget must be > 0")
+	}
+	return func(c *runConfig) {
+		c.runBudgetUSD = limit
+	}
+}
+
+// WithSuspendOnBudget makes a run stop voluntarily once it has used the
+// given fraction of its time or cost budget. At the next node boundary
+// after a checkpoint, the run returns a *SuspendedError instead of
+// continuing; a later Resume picks up where it stopped.
+//
+// Requires checkpointing and at least one of WithTimeBudget or
+// WithCostBudget. At least one node runs per invocation, so a resumed run
+// always makes progress. Fork/join and fan-out boundaries are not
+// checkpointed, so suspension waits for the next plain node boundary.
+//
+// Panics if fraction is not in (0, 1].
+//
+// Example:
+//
+//	result, err := compiled.Run(ctx, state,
+//	    flowgraph.WithCheckpointing(store),
+//	    flowgraph.WithRunID(runID),
+//	    flowgraph.WithTimeBudget(15*time.Minute),
+//	    flowgraph.WithSuspendOnBudget(0.8))
+//	if errors.Is(err, flowgraph.ErrSuspended) {
+//	    // Schedule another invocation that calls compiled.Resume
+//	}
+func WithSuspendOnBudget(fraction float64) RunOption {
+	if fraction <= 0 || fraction > 1 {
+		panic("flowgraph: suspend fraction must be in (0, 1]")
+	}
+	return func(c *runConfig) {
+		c.suspendFraction = fraction
+	}
+}
+
+// WithNodeTimeout bounds the execution time of a single node.
+// If the node does not return within d, Run fails with a *NodeError whose
+// Op is "timeout" and whose Err wraps context.DeadlineExceeded.
+//
+// The node's context is cancelled when the timeout fires. Nodes that ignore
+// ctx.Done() keep running in the background, but their result is discarded.
+//
+// Can be passed multiple times to bound different nodes.
+// Panics if d <= 0.
+//
+// Example:
+//
+//	result, err := compiled.Run(ctx, state,
+//	    flowgraph.WithNodeTimeout("fetch", 30*time.Second),
+//	    flowgraph.WithNodeTimeout("summarize", 2*time.Minute))
+func WithNodeTimeout(nodeID string, d time.Duration) RunOption {
+	if d <= 0 {
+		panic("flowgraph: node timeout must be > 0")
+	}
+	return func(c *runConfig) {
+		if c.nodeTimeouts == nil {
+			c.nodeTimeouts = make(map[string]time.Duration)
+		}
+		c.nodeTimeouts[nodeID] = d
+	}
+}
+
+// WithNodeCache memoizes the results of node nodeID in cache. Before the
+// node runs, its input state is encoded as JSON and hashed with the node
+// ID; if cache holds a result under that key, it is returned without
+// calling the node. Otherwise the node runs as usual, including retries,
+// and a successful result is stored. Errors are never cached.
+//
+// Only cache nodes that are pure: a hit skips the node's side effects,
+// including LLM calls and their cost. Like checkpointing, caching requires
+// state that round-trips through encoding/json; a node whose state cannot
+// be encoded fails with a *NodeError wrapping ErrSerializeState.
+//
+// The cache outlives the run, so sharing it across runs, or across the
+// branches of a fork/join, reuses results. Keys include the node ID, so
+// one Cache can serve several nodes.
+//
+// Panics if cache is nil.
+//
+// Example:
+//
+//	cache := flowgraph.NewLRUCache(1000)
+//	result, err := compiled.Run(ctx, state,
+//	    flowgraph.WithNodeCache("summarize", cache))
+func WithNodeCache(nodeID string, cache Cache) RunOption {
+	if cache == nil {
+		panic("flowgraph: node cache cannot be nil")
+	}
+	return func(c *runConfig) {
+		if c.nodeCaches == nil {
+			c.nodeCaches = make(map[string]Cache)
+		}
+		c.nodeCaches[nodeID] = cache
+	}
+}
+
+// WithMaxParallelism limits the fork/join branches executing at once
+// across the whole run to n, however many forks and dynamic fan-outs are
+// active and including those in subgraphs. ForkJoinConfig.MaxConcurrency
+// still caps each fork: a branch starts once both its fork and the run
+// have room. A branch that reaches a nested fork gives up its slot while
+// the nested branches run, so nesting cannot deadlock.
+//
+// Each Run or Resume call gets its own limit; concurrent runs of the same
+// compiled graph do not share it.
+//
+// Panics if n <= 0.
+//
+// Example:
+//
+//	result, err := compiled.Run(ctx, state, flowgraph.WithMaxParallelism(8))
+func WithMaxParallelism(n int) RunOption {
+	if n <= 0 {
+		panic("flowgraph: max parallelism must be > 0")
+	}
+	return func(c *runConfig) {
+		c.parallelism = newParallelismLimit(n)
+	}
+}
+
+// WithFaultInjection injects failures into nodes for chaos testing,
+// without modifying them. faults maps node IDs to the fault to inject.
+// Before each attempt of a listed node, including retries, the fault
+// fires with its probability, drawn from Context.Rand: use WithRandSeed or
+// a fixed WithRunID to make a run reproducible.
+//
+// Injected errors and panics take the same path as real ones, so retries,
+// error edges, and compensation can be exercised. Injected delays count
+// toward WithNodeTimeout.
+//
+// Panics if a probability is outside [0, 1] or an effect is nil.
+//
+// Example:
+//
+//	result, err := compiled.Run(ctx, state,
+//	    flowgraph.WithFaultInjection(map[string]flowgraph.Fault{
+//	        "fetch": {Probability: 0.3, Effect: flowgraph.InjectError(errUpstream)},
+//	        "store": {Probability: 1, Effect: flowgraph.InjectDelay(2 * time.Second)},
+//	    }))
+func WithFaultInjection(faults map[string]Fault) RunOption {
+	copied := make(map[string]Fault, len(faults))
+	for nodeID, fault := range faults {
+		if fault.Probability < 0 || fault.Probability > 1 {
+			panic(fmt.Sprintf("flowgraph: fault probability for node %s must be in [0, 1]", nodeID))
+		}
+		if fault.Effect == nil {
+			panic(fmt.Sprintf("flowgraph: fault effect for node %s cannot be nil", nodeID))
+		}
+		copied[nodeID] = fault
+	}
+	return func(c *runConfig) {
+		c.faults = copied
+	}
+}
+
+// WithGraphCompensation gives the run saga semantics: if it fails, the
+// compensations of nodes added with AddCompensatingNode that completed
+// during the call are run in reverse execution order, including nodes in
+// fork/join branches and nodes that ran more than once. Every compensation
+// runs even if an earlier one fails, and none is cancelled with the run.
+//
+// The run then returns a *CompensationError wrapping the original error
+// and reporting each compensation's outcome. If no compensating node
+// completed, or the run was suspended (see WithSuspendOnBudget), the
+// original error is returned and nothing is compensated. Nodes inside
+// subgraphs are not compensated.
+//
+// Example:
+//
+//	_, err := compiled.Run(ctx, order, flowgraph.WithGraphCompensation())
+//	var compErr *flowgraph.CompensationError
+//	if errors.As(err, &compErr) && compErr.Failed() {
+//	    // Some effects could not be undone
+//	}
+func WithGraphCompensation() RunOption {
+	return func(c *runConfig) {
+		c.graphCompensation = true
+	}
+}
+
+// WithErrorEdgeCatchesPanics makes error edges (see Graph.AddErrorEdge)
+// also route nodes that panic. The target's Context.LastError returns the
+// *PanicError. By default a panic fails the run even if the node has an
+// error edge.
+//
+// Example:
+//
+//	result, err := compiled.Run(ctx, state, flowgraph.WithErrorEdgeCatchesPanics())
+func WithErrorEdgeCatchesPanics() RunOption {
+	return func(c *runConfig) {
+		c.errorEdgeCatchesPanics = true
+	}
+}
+
+// WithRunRetry reruns the whole graph from its entry point, with the
+// original initial state, when a run fails with a retryable error.
+//
+// Errors are classified with errors.Categorize (or cfg.RetryableFunc if
+// set); non-retryable errors fail immediately, and suspended or interrupted
+// runs are never retried. Attempts are spaced by cfg's backoff and jitter,
+// and cancellation of the Context stops further attempts. Each retry runs
+// under a derived run ID, "<run ID>-retry-N", so its logs, traces and
+// checkpoints are kept apart from earlier attempts.
+//
+// Unlike WithNodeRetry, which repeats a single node and keeps the progress
+// of the nodes before it, a run retry starts over: the failed attempt's
+// progress is discarded, and its side effects are not undone unless the
+// run uses WithGraphCompensation. State is passed by value, so nodes must not
+// mutate maps or slices of the initial state in place.
+// Panics if cfg.MaxAttempts is less than 1.
+//
+// Example:
+//
+//	result, err := compiled.Run(ctx, state,
+//	    flowgraph.WithRunID("run-123"),
+//	    flowgraph.WithRunRetry(errors.NewRetryConfig(errors.WithMaxAttempts(3))))
+func WithRunRetry(cfg fgerrors.RetryConfig) RunOption {
+	if cfg.MaxAttempts < 1 {
+		panic("flowgraph: run retry needs at least one attempt")
+	}
+	return func(c *runConfig) {
+		c.runRetry = &cfg
+	}
+}
+
 // WithCheckpointing enables checkpoint saving during execution.
 // Checkpoints are saved after each node completes successfully.
 //
@@ -107,6 +467,143 @@ func WithRunID(id string) RunOption {
 	}
 }
 
+// WithInitialCheckpoint also checkpoints the initial state before the
+// entry node runs, under the node ID START. This records everything needed
+// to replay the run with CompareRun, and lets Resume restart a run that
+// failed in its first node.
+//
+// Has no effect without WithCheckpointing.
+//
+// Example:
+//
+//	result, err := compiled.Run(ctx, state,
+//	    flowgraph.WithCheckpointing(store),
+//	    flowgraph.WithRunID("golden-1"),
+//	    flowgraph.WithInitialCheckpoint())
+func WithInitialCheckpoint() RunOption {
+	return func(c *runConfig) {
+		c.initialCheckpoint = true
+	}
+}
+
+// WithCheckpointInterval saves a checkpoint after every nth successful
+// node instead of after each one, cutting checkpoint writes for graphs of
+// many small nodes. The checkpoint of the last node before END is always
+// saved.
+//
+// Resume continues from the last saved checkpoint, so the nodes completed
+// since then run again. Nodes must therefore be idempotent, as they
+// already must be for a crash between a node finishing and its checkpoint
+// being saved. WithSuspendOnBudget only suspends right after a saved
+// checkpoint, so it may suspend up to n-1 nodes later.
+//
+// Panics if n <= 0.
+//
+// Example:
+//
+//	result, err := compiled.Run(ctx, state,
+//	    flowgraph.WithCheckpointing(store),
+//	    flowgraph.WithRunID("run-123"),
+//	    flowgraph.WithCheckpointInterval(10))
+func WithCheckpointInterval(n int) RunOption {
+	if n <= 0 {
+		panic("flowgraph: checkpoint interval must be > 0")
+	}
+	return func(c *runConfig) {
+		c.checkpointInterval = n
+	}
+}
+
+// WithCheckpointCompression compresses checkpoint state before it is saved.
+// Default: CompressionNone.
+//
+// Compressed state is written with a header so Resume and ResumeFrom detect
+// and decompress it automatically; uncompressed checkpoints still load.
+// A resumed run keeps the compression of the checkpoint it resumed from.
+// The MaxCheckpointSize limit applies to the compressed size.
+//
+// Example:
+//
+//	result, err := compiled.Run(ctx, state,
+//	    flowgraph.WithCheckpointing(store),
+//	    flowgraph.WithRunID("run-123"),
+//	    flowgraph.WithCheckpointCompression(flowgraph.CompressionGzip))
+func WithCheckpointCompression(c Compression) RunOption {
+	return func(cfg *runConfig) {
+		cfg.checkpointCompression = c
+	}
+}
+
+// WithCheckpointCodec sets how checkpoint state is serialized.
+// Default: JSONCodec. Panics if codec is nil.
+//
+// The codec's name is recorded in each checkpoint, and Resume and
+// ResumeFrom decode with the recorded codec, so changing the codec does
+// not strand existing checkpoints. A resumed run keeps the codec of the
+// checkpoint it resumed from unless WithResumeRunOptions sets another.
+// To resume a checkpoint written with a custom codec, pass the codec
+// through WithResumeRunOptions.
+//
+// Example:
+//
+//	result, err := compiled.Run(ctx, state,
+//	    flowgraph.WithCheckpointing(store),
+//	    flowgraph.WithRunID("run-123"),
+//	    flowgraph.WithCheckpointCodec(flowgraph.GobCodec{}))
+func WithCheckpointCodec(codec Codec) RunOption {
+	if codec == nil {
+		panic("flowgraph: checkpoint codec cannot be nil")
+	}
+	return func(cfg *runConfig) {
+		cfg.checkpointCodec = codec
+	}
+}
+
+// WithCheckpointEncryption encrypts checkpoint state at rest with AES-GCM.
+// The key must be 16, 24, or 32 bytes (AES-128, -192, or -256); panics
+// otherwise.
+//
+// State is encrypted after serialization (and compression, if enabled)
+// just before it is saved, so this works with any checkpoint.Store.
+// Pass the same key to Resume or ResumeFrom via WithDecryptionKey.
+// Checkpoint metadata (run ID, node IDs, sequence) is not encrypted.
+//
+// Example:
+//
+//	result, err := compiled.Run(ctx, state,
+//	    flowgraph.WithCheckpointing(store),
+//	    flowgraph.WithRunID("run-123"),
+//	    flowgraph.WithCheckpointEncryption(key))
+func WithCheckpointEncryption(key []byte) RunOption {
+	aead := newCheckpointCipher(key)
+	return func(c *runConfig) {
+		c.checkpointCipher = aead
+	}
+}
+
+// WithCheckpointMetadata attaches labels to every checkpoint the run saves,
+// such as the git SHA of the deployed code or a user-facing stage name.
+// Stores report them in checkpoint.Info.Metadata, and resuming reports
+// those of the loaded checkpoint in ResumeInfo.Metadata, so a
+// WithBeforeResume hook can refuse checkpoints from incompatible code.
+// Metadata is not encrypted by WithCheckpointEncryption. The map is copied.
+//
+// Example:
+//
+//	result, err := compiled.Run(ctx, state,
+//	    flowgraph.WithCheckpointing(store),
+//	    flowgraph.WithRunID("run-123"),
+//	    flowgraph.WithCheckpointMetadata(map[string]string{"git_sha": buildSHA}))
+func WithCheckpointMetadata(metadata map[string]string) RunOption {
+	copied := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		copied[k] = v
+	}
+	return func(c *runConfig) {
+		c.checkpointMetadata = copied
+	}
+}
+
 // WithCheckpointFailureFatal controls whether checkpoint failures stop execution.
 //
 // Default: true (checkpoint failures stop execution with CheckpointError).
@@ -146,6 +643,55 @@ func WithObservabilityLogger(logger *slog.Logger) RunOption {
 	}
 }
 
+// WithExecutionTrace appends an entry to trace for every node the run
+// executes, recording when it started, how long it took, its attempts,
+// where execution went next (including the choices of conditional edges),
+// and its error. RunWithTrace is a shorthand for the common case.
+//
+// Fork and fan-out nodes are traced, with the join node as NextNode, but
+// the nodes of their branches and of subgraphs are not. Entries are
+// appended as the run progresses, so trace must not be read or shared with
+// another run until Run returns.
+//
+// Panics if trace is nil.
+//
+// Example:
+//
+//	var trace flowgraph.Trace
+//	_, err := compiled.Run(ctx, state, flowgraph.WithExecutionTrace(&trace))
+//	assert.Equal(t, []string{"fetch", "review", "publish"}, trace.NodeIDs())
+func WithExecutionTrace(trace *Trace) RunOption {
+	if trace == nil {
+		panic("flowgraph: execution trace cannot be nil")
+	}
+	return func(c *runConfig) {
+		c.trace = trace
+	}
+}
+
+// WithBranchRecorder records the target each conditional edge routes to
+// in rec. Pass the same recorder to many runs to measure branch coverage;
+// see BranchRecorder.BranchCoverage. Only valid routing decisions are
+// recorded; a router that panics or returns an unknown node is not.
+//
+// Panics if rec is nil.
+//
+// Example:
+//
+//	recorder := flowgraph.NewBranchRecorder()
+//	for _, state := range testStates {
+//	    compiled.Run(ctx, state, flowgraph.WithBranchRecorder(recorder))
+//	}
+//	coverage := recorder.BranchCoverage() // from -> target -> count
+func WithBranchRecorder(rec *BranchRecorder) RunOption {
+	if rec == nil {
+		panic("flowgraph: branch recorder cannot be nil")
+	}
+	return func(c *runConfig) {
+		c.branchRecorder = rec
+	}
+}
+
 // WithMetrics enables OpenTelemetry metrics collection.
 // When enabled, flowgraph records metrics for node executions, latency,
 // errors, and checkpoint sizes.
@@ -166,6 +712,8 @@ func WithObservabilityLogger(logger *slog.Logger) RunOption {
 //   - flowgraph.node.errors{node_id="..."}
 //   - flowgraph.graph.runs{success="true|false"}
 //   - flowgraph.checkpoint.size_bytes{node_id="..."}
+//
+// To collect metrics without OpenTelemetry, use WithMetricsCollector.
 func WithMetrics(enabled bool) RunOption {
 	return func(c *runConfig) {
 		c.metricsEnabled = enabled
@@ -177,6 +725,42 @@ func WithMetrics(enabled bool) RunOption {
 	}
 }
 
+// MetricsCollector receives the metrics of a run. WithMetrics(true) uses
+// the OpenTelemetry implementation; WithMetricsCollector plugs in any
+// other, such as a test spy or a Prometheus adapter.
+type MetricsCollector = observability.MetricsRecorder
+
+// WithMetricsCollector sends run metrics to c instead of OpenTelemetry.
+// Subgraphs report to the same collector. Calls may come from parallel
+// branches concurrently, so c must be safe for concurrent use.
+//
+// Panics if c is nil.
+//
+// Example:
+//
+//	type spy struct {
+//	    mu    sync.Mutex
+//	    nodes []string
+//	}
+//
+//	func (s *spy) RecordNodeExecution(_ context.Context, nodeID string, _ time.Duration, _ error) {
+//	    s.mu.Lock()
+//	    defer s.mu.Unlock()
+//	    s.nodes = append(s.nodes, nodeID)
+//	}
+//	// ... RecordGraphRun, RecordCheckpoint
+//
+//	result, err := compiled.Run(ctx, state, flowgraph.WithMetricsCollector(&spy{}))
+func WithMetricsCollector(c MetricsCollector) RunOption {
+	if c == nil {
+		panic("flowgraph: metrics collector cannot be nil")
+	}
+	return func(cfg *runConfig) {
+		cfg.metricsEnabled = true
+		cfg.metrics = c
+	}
+}
+
 // WithTracing enables OpenTelemetry distributed tracing.
 // When enabled, flowgraph creates spans for graph runs and node executions.
 //
@@ -211,7 +795,22 @@ func WithTracing(enabled bool) RunOption {
 type resumeConfig struct {
 	stateOverride func(any) any
 	validateState func(any) error
+	beforeResume  func(ResumeInfo, any) (any, error)
 	replayNode    bool
+	cipher        cipher.AEAD
+	runOptions    []RunOption
+}
+
+// ResumeInfo describes the checkpoint a resume is about to continue from.
+type ResumeInfo struct {
+	RunID    string
+	LastNode string // Node whose checkpoint was loaded
+	NextNode string // Node execution will start at (LastNode with WithReplayNode)
+	Sequence int    // Sequence number of the loaded checkpoint
+
+	// Metadata is the loaded checkpoint's labels from
+	// WithCheckpointMetadata, or nil.
+	Metadata map[string]string
 }
 
 // ResumeOption configures resume behavior.
@@ -253,6 +852,38 @@ func WithStateValidation(fn func(any) error) ResumeOption {
 	}
 }
 
+// WithBeforeResume registers a hook that runs after the checkpoint is
+// loaded and before execution continues. The hook sees where the run will
+// resume and may return a modified state. Returning an error aborts the
+// resume: Resume returns the restored state and an error wrapping both
+// ErrResumeAborted and the hook's error, without executing any nodes.
+//
+// The hook runs after WithStateOverride and WithStateValidation. S must be
+// the graph's state type; otherwise Resume fails with ErrResumeAborted.
+//
+// Example:
+//
+//	result, err := compiled.Resume(ctx, store, runID,
+//	    flowgraph.WithBeforeResume(func(info flowgraph.ResumeInfo, s MyState) (MyState, error) {
+//	        if s.Expired() {
+//	            return s, fmt.Errorf("run %s expired before %s", info.RunID, info.NextNode)
+//	        }
+//	        s.ResumedAt = time.Now()
+//	        return s, nil
+//	    }))
+func WithBeforeResume[S any](fn func(info ResumeInfo, state S) (S, error)) ResumeOption {
+	return func(c *resumeConfig) {
+		c.beforeResume = func(info ResumeInfo, state any) (any, error) {
+			typed, ok := state.(S)
+			if !ok {
+				var want S
+				return state, fmt.Errorf("hook expects state of type %T, got %T", want, state)
+			}
+			return fn(info, typed)
+		}
+	}
+}
+
 // WithReplayNode causes the resume to re-execute the checkpointed node.
 // By default, resume starts from the node AFTER the checkpoint.
 // Use this when the checkpointed node is idempotent and you want to retry it.
@@ -267,3 +898,37 @@ func WithReplayNode() ResumeOption {
 		c.replayNode = true
 	}
 }
+
+// WithDecryptionKey sets the key used to decrypt checkpoints written with
+// WithCheckpointEncryption. The resumed run encrypts its own checkpoints
+// with the same key. Panics if the key is not 16, 24, or 32 bytes.
+//
+// If a checkpoint is encrypted and the key is missing or wrong, Resume
+// returns an error wrapping ErrCheckpointDecryption.
+//
+// Example:
+//
+//	result, err := compiled.Resume(ctx, store, runID,
+//	    flowgraph.WithDecryptionKey(key))
+func WithDecryptionKey(key []byte) ResumeOption {
+	aead := newCheckpointCipher(key)
+	return func(c *resumeConfig) {
+		c.cipher = aead
+	}
+}
+
+// WithResumeRunOptions applies run options to the resumed execution, such
+// as WithMaxIterations or WithTimeBudget. Checkpointing and the run ID are
+// always taken from the Resume call and cannot be overridden.
+//
+// Example:
+//
+//	result, err := compiled.Resume(ctx, store, runID,
+//	    flowgraph.WithResumeRunOptions(
+//	        flowgraph.WithTimeBudget(15*time.Minute),
+//	        flowgraph.WithSuspendOnBudget(0.8)))
+func WithResumeRunOptions(opts ...RunOption) ResumeOption {
+	return func(c *resumeConfig) {
+		c.runOptions = append(c.runOptions, opts...)
+	}
+}
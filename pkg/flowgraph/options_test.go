@@ -2,6 +2,7 @@ package flowgraph
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -60,8 +61,82 @@ func TestWithMaxIterations_PanicsOnExceedingLimit(t *testing.T) {
 	})
 }
 
+// TestWithMaxParallelism_PanicsOnNonPositive tests panic for invalid limits.
+func TestWithMaxParallelism_PanicsOnNonPositive(t *testing.T) {
+	assert.PanicsWithValue(t, "flowgraph: max parallelism must be > 0", func() {
+		WithMaxParallelism(0)
+	})
+	assert.PanicsWithValue(t, "flowgraph: max parallelism must be > 0", func() {
+		WithMaxParallelism(-1)
+	})
+}
+
 // TestDefaultMaxIterations_Constant tests the default constant value.
 func TestDefaultMaxIterations_Constant(t *testing.T) {
 	assert.Equal(t, 1000, DefaultMaxIterations)
 	assert.Equal(t, 100000, MaxIterationsLimit)
 }
+
+// TestWithNodeTimeout tests per-node timeout configuration.
+func TestWithNodeTimeout(t *testing.T) {
+	cfg := defaultRunConfig()
+	WithNodeTimeout("a", time.Second)(&cfg)
+	WithNodeTimeout("b", 2*time.Second)(&cfg)
+
+	assert.Equal(t, time.Second, cfg.nodeTimeouts["a"])
+	assert.Equal(t, 2*time.Second, cfg.nodeTimeouts["b"])
+}
+
+// TestWithNodeTimeout_PanicsOnNonPositive tests panic for zero or negative durations.
+func TestWithNodeTimeout_PanicsOnNonPositive(t *testing.T) {
+	assert.PanicsWithValue(t, "flowgraph: node timeout must be > 0", func() {
+		WithNodeTimeout("a", 0)
+	})
+	assert.PanicsWithValue(t, "flowgraph: node timeout must be > 0", func() {
+		WithNodeTimeout("a", -time.Second)
+	})
+}
+
+// TestWithCheckpointInterval_PanicsOnNonPositive tests panic for invalid intervals.
+func TestWithCheckpointInterval_PanicsOnNonPositive(t *testing.T) {
+	assert.PanicsWithValue(t, "flowgraph: checkpoint interval must be > 0", func() {
+		WithCheckpointInterval(0)
+	})
+}
+
+// TestWithMaxStateSize tests state size limit configuration.
+func TestWithMaxStateSize(t *testing.T) {
+	cfg := defaultRunConfig()
+	assert.Equal(t, 0, cfg.maxStateSize, "disabled by default")
+
+	WithMaxStateSize(1024)(&cfg)
+	assert.Equal(t, 1024, cfg.maxStateSize)
+}
+
+// TestWithMaxStateSize_PanicsOnNonPositive tests panic for zero or negative limits.
+func TestWithMaxStateSize_PanicsOnNonPositive(t *testing.T) {
+	assert.PanicsWithValue(t, "flowgraph: max state size must be > 0", func() {
+		WithMaxStateSize(0)
+	})
+	assert.PanicsWithValue(t, "flowgraph: max state size must be > 0", func() {
+		WithMaxStateSize(-1)
+	})
+}
+
+// TestWithCheckpointEncryption_InvalidKey tests that bad key lengths panic.
+func TestWithCheckpointEncryption_InvalidKey(t *testing.T) {
+	assert.Panics(t, func() { WithCheckpointEncryption([]byte("short")) })
+	assert.Panics(t, func() { WithDecryptionKey(nil) })
+	assert.NotPanics(t, func() { WithCheckpointEncryption(make([]byte, 32)) })
+}
+
+// TestBudgetOptions_Invalid tests that invalid budget options panic.
+func TestBudgetOptions_Invalid(t *testing.T) {
+	cost := func(any) float64 { return 0 }
+	assert.Panics(t, func() { WithTimeBudget(0) })
+	assert.Panics(t, func() { WithCostBudget(0, cost) })
+	assert.Panics(t, func() { WithCostBudget(1, nil) })
+	assert.Panics(t, func() { WithSuspendOnBudget(0) })
+	assert.Panics(t, func() { WithSuspendOnBudget(1.5) })
+	assert.NotPanics(t, func() { WithSuspendOnBudget(1) })
+}
@@ -91,6 +91,8 @@ type ForkJoinConfig struct {
 	// MaxConcurrency limits the number of branches executing simultaneously.
 	// 0 = unlimited (all branches start immediately).
 	// Use this to prevent resource exhaustion with many branches.
+	// It applies to each fork separately; use WithMaxParallelism to limit
+	// branches across all forks of a run.
 	MaxConcurrency int
 
 	// FailFast stops all branches when any branch fails.
@@ -102,6 +104,21 @@ type ForkJoinConfig struct {
 	// 0 = no timeout (wait indefinitely).
 	// If timeout is reached, remaining branches are cancelled.
 	MergeTimeout time.Duration
+
+	// ContinueOnError lets the join proceed when some branches fail.
+	// Failed branches are logged and passed to BranchHook.OnBranchError,
+	// and only the successful branches' states are merged (and, with
+	// OrderedSideEffects, only their side effects committed). If every
+	// branch fails, the fork still fails with a *ForkJoinError.
+	// false = any branch failure fails the fork (default).
+	ContinueOnError bool
+
+	// OrderedSideEffects defers side effects registered with
+	// CommitSideEffect until the join. Branches still compute in parallel,
+	// but their side effects run serially in sorted branch-ID order after
+	// all branches succeed, and not at all if any branch fails.
+	// false = side effects run immediately when registered (default).
+	OrderedSideEffects bool
 }
 
 // DefaultForkJoinConfig returns the default configuration.
@@ -2,6 +2,7 @@ package flowgraph
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync/atomic"
 	"testing"
@@ -244,6 +245,99 @@ func TestForkJoin_BranchError(t *testing.T) {
 	}
 }
 
+func TestForkJoin_ContinueOnError(t *testing.T) {
+	var failedBranch atomic.Value
+	var merged TestState
+
+	graph := NewGraph[TestState]().
+		AddNode("start", func(ctx Context, s TestState) (TestState, error) {
+			return s, nil
+		}).
+		AddNode("workerA", func(ctx Context, s TestState) (TestState, error) {
+			s.Values["a"] = 1
+			return s, nil
+		}).
+		AddNode("workerB", func(ctx Context, s TestState) (TestState, error) {
+			s.Values["b"] = 1
+			return s, fmt.Errorf("workerB failed")
+		}).
+		AddNode("collect", func(ctx Context, s TestState) (TestState, error) {
+			merged = s
+			return s, nil
+		}).
+		AddEdge("start", "workerA").
+		AddEdge("start", "workerB").
+		AddEdge("workerA", "collect").
+		AddEdge("workerB", "collect").
+		AddEdge("collect", END).
+		SetEntry("start").
+		SetForkJoinConfig(ForkJoinConfig{ContinueOnError: true}).
+		SetBranchHook(&testBranchHook{
+			onBranchError: func(ctx Context, branchID string, s TestState, err error) {
+				failedBranch.Store(branchID)
+			},
+		})
+
+	compiled, err := graph.Compile()
+	if err != nil {
+		t.Fatalf("Compile() error: %v", err)
+	}
+
+	ctx := NewContext(context.Background())
+	if _, runErr := compiled.Run(ctx, TestState{Values: make(map[string]int)}); runErr != nil {
+		t.Fatalf("Run() error: %v", runErr)
+	}
+
+	if merged.Values["workerA_a"] != 1 {
+		t.Errorf("expected surviving branch in merge, got %v", merged.Values)
+	}
+	if _, ok := merged.Values["workerB_b"]; ok {
+		t.Errorf("expected failed branch excluded from merge, got %v", merged.Values)
+	}
+	if failedBranch.Load() != "workerB" {
+		t.Errorf("expected OnBranchError for workerB, got %v", failedBranch.Load())
+	}
+}
+
+func TestForkJoin_ContinueOnError_AllFail(t *testing.T) {
+	fail := func(ctx Context, s TestState) (TestState, error) {
+		return s, fmt.Errorf("worker failed")
+	}
+	graph := NewGraph[TestState]().
+		AddNode("start", func(ctx Context, s TestState) (TestState, error) {
+			return s, nil
+		}).
+		AddNode("workerA", fail).
+		AddNode("workerB", fail).
+		AddNode("collect", func(ctx Context, s TestState) (TestState, error) {
+			t.Error("expected join not to run when all branches fail")
+			return s, nil
+		}).
+		AddEdge("start", "workerA").
+		AddEdge("start", "workerB").
+		AddEdge("workerA", "collect").
+		AddEdge("workerB", "collect").
+		AddEdge("collect", END).
+		SetEntry("start").
+		SetForkJoinConfig(ForkJoinConfig{ContinueOnError: true})
+
+	compiled, err := graph.Compile()
+	if err != nil {
+		t.Fatalf("Compile() error: %v", err)
+	}
+
+	ctx := NewContext(context.Background())
+	_, runErr := compiled.Run(ctx, TestState{Values: make(map[string]int)})
+
+	var forkErr *ForkJoinError
+	if !errors.As(runErr, &forkErr) {
+		t.Fatalf("Expected ForkJoinError, got %T: %v", runErr, runErr)
+	}
+	if forkErr.BranchID != "workerA" && forkErr.BranchID != "workerB" {
+		t.Errorf("expected a failed branch ID, got %q", forkErr.BranchID)
+	}
+}
+
 func TestForkJoin_WithBranchHook(t *testing.T) {
 	var onForkCalls []string
 	var onJoinCalled bool
@@ -402,6 +496,127 @@ func TestForkJoin_MaxConcurrency(t *testing.T) {
 	}
 }
 
+// concurrencyTracker records the peak number of nodes running at once.
+type concurrencyTracker struct {
+	executing atomic.Int32
+	peak      atomic.Int32
+}
+
+func (c *concurrencyTracker) work(ctx Context, s TestState) (TestState, error) {
+	current := c.executing.Add(1)
+	for {
+		peak := c.peak.Load()
+		if current <= peak || c.peak.CompareAndSwap(peak, current) {
+			break
+		}
+	}
+	time.Sleep(30 * time.Millisecond)
+	c.executing.Add(-1)
+	return s, nil
+}
+
+// nestedForkGraph builds a graph whose two branches are subgraphs that each
+// fork into three workers.
+func nestedForkGraph(t *testing.T, tracker *concurrencyTracker, innerMax int) *CompiledGraph[TestState] {
+	t.Helper()
+	noop := func(ctx Context, s TestState) (TestState, error) { return s, nil }
+
+	inner, err := NewGraph[TestState]().
+		AddNode("split", noop).
+		AddNode("w1", tracker.work).
+		AddNode("w2", tracker.work).
+		AddNode("w3", tracker.work).
+		AddNode("join", noop).
+		AddEdge("split", "w1").
+		AddEdge("split", "w2").
+		AddEdge("split", "w3").
+		AddEdge("w1", "join").
+		AddEdge("w2", "join").
+		AddEdge("w3", "join").
+		AddEdge("join", END).
+		SetEntry("split").
+		SetForkJoinConfig(ForkJoinConfig{MaxConcurrency: innerMax}).
+		Compile()
+	if err != nil {
+		t.Fatalf("Compile() inner error: %v", err)
+	}
+
+	outer, err := NewGraph[TestState]().
+		AddNode("start", noop).
+		AddSubgraph("forkA", inner).
+		AddSubgraph("forkB", inner).
+		AddNode("collect", noop).
+		AddEdge("start", "forkA").
+		AddEdge("start", "forkB").
+		AddEdge("forkA", "collect").
+		AddEdge("forkB", "collect").
+		AddEdge("collect", END).
+		SetEntry("start").
+		Compile()
+	if err != nil {
+		t.Fatalf("Compile() outer error: %v", err)
+	}
+	return outer
+}
+
+func TestForkJoin_MaxParallelism(t *testing.T) {
+	ctx := NewContext(context.Background())
+	initial := TestState{Values: make(map[string]int)}
+
+	// Without a run-wide limit, both forks run all their workers
+	unlimited := &concurrencyTracker{}
+	if _, err := nestedForkGraph(t, unlimited, 3).Run(ctx, initial); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if unlimited.peak.Load() <= 2 {
+		t.Fatalf("expected more than 2 concurrent workers without a limit, got %d", unlimited.peak.Load())
+	}
+
+	limited := &concurrencyTracker{}
+	if _, err := nestedForkGraph(t, limited, 3).Run(ctx, initial, WithMaxParallelism(2)); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if limited.peak.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent workers across forks, got %d", limited.peak.Load())
+	}
+}
+
+func TestForkJoin_MaxParallelism_LocalCap(t *testing.T) {
+	// Per-fork MaxConcurrency still applies under a looser run-wide limit
+	tracker := &concurrencyTracker{}
+	ctx := NewContext(context.Background())
+	_, err := nestedForkGraph(t, tracker, 1).Run(ctx, TestState{Values: make(map[string]int)}, WithMaxParallelism(10))
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if tracker.peak.Load() > 2 {
+		t.Errorf("expected at most 1 worker per fork, got %d concurrent", tracker.peak.Load())
+	}
+}
+
+func TestForkJoin_MaxParallelism_NestedNoDeadlock(t *testing.T) {
+	tracker := &concurrencyTracker{}
+	ctx := NewContext(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := nestedForkGraph(t, tracker, 0).Run(ctx, TestState{Values: make(map[string]int)}, WithMaxParallelism(1))
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run() error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("nested forks deadlocked under WithMaxParallelism(1)")
+	}
+	if tracker.peak.Load() != 1 {
+		t.Errorf("expected 1 concurrent worker, got %d", tracker.peak.Load())
+	}
+}
+
 func TestNoForkJoin_SequentialExecution(t *testing.T) {
 	// Verify that graphs without fork/join still work
 	graph := NewGraph[TestState]().
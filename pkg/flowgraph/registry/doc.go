@@ -16,6 +16,9 @@
 //	    fmt.Println(value) // Output: 1
 //	}
 //
+// Keys returns a snapshot of the registered keys, Len the number of entries,
+// and Clear removes every entry.
+//
 // # Factory Pattern
 //
 // Registries work well for factory patterns where you register constructors:
@@ -49,6 +52,23 @@
 // GetOrCreate is atomic - the factory function is called at most once per key,
 // even under concurrent access.
 //
+// # Change Callbacks
+//
+// OnRegister and OnDelete add callbacks that run after entries change, for
+// example to invalidate a derived cache:
+//
+//	plugins.OnRegister(func(name string, p Plugin) {
+//	    cache.Delete(name)
+//	})
+//	plugins.OnDelete(func(name string) {
+//	    cache.Delete(name)
+//	})
+//
+// Callbacks run in the order they were added, after the lock is released,
+// so they may safely call back into the registry. GetOrCreate fires
+// OnRegister only when it creates a value, and Delete fires OnDelete only
+// when the key existed.
+//
 // # Thread Safety
 //
 // All Registry methods are safe for concurrent use. The Range method iterates
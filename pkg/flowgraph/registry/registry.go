@@ -5,8 +5,10 @@ import "sync"
 // Registry is a thread-safe registry for values indexed by key.
 // It uses sync.RWMutex for optimal read-heavy workloads.
 type Registry[K comparable, V any] struct {
-	mu      sync.RWMutex
-	entries map[K]V
+	mu         sync.RWMutex
+	entries    map[K]V
+	onRegister []func(K, V)
+	onDelete   []func(K)
 }
 
 // New creates a new empty registry.
@@ -16,20 +18,54 @@ func New[K comparable, V any]() *Registry[K, V] {
 	}
 }
 
+// OnRegister adds a callback invoked after a value is added or updated by
+// Register, RegisterMany, or GetOrCreate (only when it creates the value).
+//
+// Callbacks run in the order they were added, on the goroutine that made
+// the change, after the registry lock is released, so they may call back
+// into the registry. Callbacks added while a change is in progress may
+// not see it.
+func (r *Registry[K, V]) OnRegister(fn func(key K, value V)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onRegister = append(r.onRegister, fn)
+}
+
+// OnDelete adds a callback invoked after a key is removed by Delete or
+// Clear. It is not called for keys that were not present. Callbacks run
+// like those added with OnRegister.
+func (r *Registry[K, V]) OnDelete(fn func(key K)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onDelete = append(r.onDelete, fn)
+}
+
 // Register adds or updates a value in the registry.
 func (r *Registry[K, V]) Register(key K, value V) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 	r.entries[key] = value
+	hooks := r.onRegister
+	r.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn(key, value)
+	}
 }
 
 // RegisterMany adds multiple entries to the registry.
 func (r *Registry[K, V]) RegisterMany(entries map[K]V) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 	for k, v := range entries {
 		r.entries[k] = v
 	}
+	hooks := r.onRegister
+	r.mu.Unlock()
+
+	for k, v := range entries {
+		for _, fn := range hooks {
+			fn(k, v)
+		}
+	}
 }
 
 // Get returns the value for a key and whether it exists.
@@ -62,12 +98,21 @@ func (r *Registry[K, V]) Has(key K) bool {
 // Delete removes a key from the registry.
 func (r *Registry[K, V]) Delete(key K) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	_, existed := r.entries[key]
 	delete(r.entries, key)
+	hooks := r.onDelete
+	r.mu.Unlock()
+
+	if existed {
+		for _, fn := range hooks {
+			fn(key)
+		}
+	}
 }
 
 // Keys returns all keys in the registry.
-// The order is not guaranteed.
+// The order is not guaranteed. The slice is a snapshot, so callers can
+// iterate it without holding the lock.
 func (r *Registry[K, V]) Keys() []K {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -85,6 +130,27 @@ func (r *Registry[K, V]) Len() int {
 	return len(r.entries)
 }
 
+// Clear removes all entries from the registry.
+func (r *Registry[K, V]) Clear() {
+	r.mu.Lock()
+	hooks := r.onDelete
+	var removed []K
+	if len(hooks) > 0 {
+		removed = make([]K, 0, len(r.entries))
+		for k := range r.entries {
+			removed = append(removed, k)
+		}
+	}
+	clear(r.entries)
+	r.mu.Unlock()
+
+	for _, k := range removed {
+		for _, fn := range hooks {
+			fn(k)
+		}
+	}
+}
+
 // Range iterates over all entries in the registry.
 // The function fn is called for each entry. If fn returns false,
 // iteration stops.
@@ -122,16 +188,28 @@ func (r *Registry[K, V]) GetOrCreate(key K, factory func() V) V {
 	}
 
 	// Slow path: create with write lock
+	v, hooks, created := r.create(key, factory)
+	if created {
+		for _, fn := range hooks {
+			fn(key, v)
+		}
+	}
+	return v
+}
+
+// create stores factory() under key unless it already exists, returning
+// the value, the OnRegister callbacks to run, and whether it was created.
+func (r *Registry[K, V]) create(key K, factory func() V) (V, []func(K, V), bool) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	// Double-check after acquiring write lock
 	if v, ok := r.entries[key]; ok {
-		return v
+		return v, nil, false
 	}
 
 	// Create and store
-	v = factory()
+	v := factory()
 	r.entries[key] = v
-	return v
+	return v, r.onRegister, true
 }
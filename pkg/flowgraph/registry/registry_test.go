@@ -153,6 +153,99 @@ func TestLen(t *testing.T) {
 	assert.Equal(t, 1, r.Len())
 }
 
+func TestClear(t *testing.T) {
+	r := New[string, int]()
+	r.Register("one", 1)
+	r.Register("two", 2)
+	keys := r.Keys()
+
+	r.Clear()
+
+	assert.Equal(t, 0, r.Len())
+	assert.Empty(t, r.Keys())
+	assert.False(t, r.Has("one"))
+	assert.Len(t, keys, 2, "earlier Keys snapshot should be unaffected")
+
+	r.Register("three", 3)
+	assert.Equal(t, 1, r.Len())
+}
+
+func TestOnRegister(t *testing.T) {
+	r := New[string, int]()
+	var calls []string
+	r.OnRegister(func(key string, value int) {
+		calls = append(calls, "first:"+key)
+	})
+	r.OnRegister(func(key string, value int) {
+		calls = append(calls, "second:"+key)
+		assert.True(t, r.Has(key), "callback should see the mutation")
+	})
+
+	r.Register("one", 1)
+	r.RegisterMany(map[string]int{"two": 2})
+
+	assert.Equal(t, []string{"first:one", "second:one", "first:two", "second:two"}, calls)
+}
+
+func TestOnRegisterGetOrCreate(t *testing.T) {
+	r := New[string, int]()
+	var registered []int
+	r.OnRegister(func(key string, value int) {
+		registered = append(registered, value)
+	})
+
+	r.GetOrCreate("key", func() int { return 1 })
+	r.GetOrCreate("key", func() int { return 2 })
+
+	assert.Equal(t, []int{1}, registered, "should fire only when created")
+}
+
+func TestOnDelete(t *testing.T) {
+	r := New[string, int]()
+	r.RegisterMany(map[string]int{"one": 1, "two": 2, "three": 3})
+	var deleted []string
+	r.OnDelete(func(key string) {
+		deleted = append(deleted, key)
+	})
+
+	r.Delete("one")
+	r.Delete("missing")
+	assert.Equal(t, []string{"one"}, deleted)
+
+	r.Clear()
+	assert.ElementsMatch(t, []string{"one", "two", "three"}, deleted)
+}
+
+func TestCallbacksMayReenter(t *testing.T) {
+	r := New[string, int]()
+	derived := New[string, int]()
+	r.OnRegister(func(key string, value int) {
+		derived.Register(key, r.MustGet(key)*2)
+	})
+	r.OnDelete(func(key string) {
+		derived.Delete(key)
+		r.Delete("other") // Re-entrant mutation must not deadlock
+	})
+
+	r.Register("key", 21)
+	r.Register("other", 1)
+	r.Delete("key")
+
+	assert.False(t, derived.Has("key"))
+	assert.False(t, r.Has("other"))
+}
+
+func TestGetOrCreateFactoryPanicReleasesLock(t *testing.T) {
+	r := New[string, int]()
+
+	assert.Panics(t, func() {
+		r.GetOrCreate("key", func() int { panic("boom") })
+	})
+
+	r.Register("key", 1)
+	assert.Equal(t, 1, r.MustGet("key"))
+}
+
 func TestRange(t *testing.T) {
 	r := New[string, int]()
 	r.Register("one", 1)
@@ -439,6 +532,32 @@ func TestConcurrentDelete(t *testing.T) {
 	assert.Equal(t, 0, r.Len())
 }
 
+func TestConcurrentClear(t *testing.T) {
+	r := New[int, int]()
+
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Add(3)
+		go func(key int) {
+			defer wg.Done()
+			r.Register(key, key)
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = r.Len()
+			_ = r.Keys()
+		}()
+		go func() {
+			defer wg.Done()
+			r.Clear()
+		}()
+	}
+	wg.Wait()
+
+	r.Clear()
+	assert.Equal(t, 0, r.Len())
+}
+
 func TestConcurrentRangeWithMutations(t *testing.T) {
 	r := New[int, int]()
 	for i := range 100 {
@@ -1,7 +1,6 @@
 package flowgraph
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -29,38 +28,17 @@ func (cg *CompiledGraph[S]) Resume(ctx Context, store checkpoint.Store, runID st
 		opt(&cfg)
 	}
 
-	// Find latest checkpoint
-	infos, err := store.List(runID)
+	// Find and load the latest checkpoint
+	cp, err := loadLatestCheckpoint(store, runID)
 	if err != nil {
-		return zero, fmt.Errorf("list checkpoints: %w", err)
-	}
-	if len(infos) == 0 {
-		return zero, fmt.Errorf("%w: %s", ErrNoCheckpoints, runID)
-	}
-
-	// Load the latest checkpoint (last in sequence)
-	latest := infos[len(infos)-1]
-	data, err := store.Load(runID, latest.NodeID)
-	if err != nil {
-		return zero, fmt.Errorf("load checkpoint: %w", err)
-	}
-
-	// Unmarshal checkpoint
-	cp, err := checkpoint.Unmarshal(data)
-	if err != nil {
-		return zero, fmt.Errorf("%w: %w", ErrDeserializeState, err)
-	}
-
-	// Check version compatibility
-	if cp.Version != checkpoint.Version {
-		return zero, fmt.Errorf("%w: got %d, expected %d",
-			ErrCheckpointVersionMismatch, cp.Version, checkpoint.Version)
+		return zero, err
 	}
 
 	// Deserialize state
 	var state S
-	if err := json.Unmarshal(cp.State, &state); err != nil {
-		return zero, fmt.Errorf("%w: %w", ErrDeserializeState, err)
+	compression, codec, err := decodeCheckpointState(cp, codecOption(cfg.runOptions), cfg.cipher, &state)
+	if err != nil {
+		return zero, err
 	}
 
 	// Apply state override if configured
@@ -85,13 +63,33 @@ func (cg *CompiledGraph[S]) Resume(ctx Context, store checkpoint.Store, runID st
 		startNode = cp.NodeID
 	}
 
+	state, err = runBeforeResume(&cfg, ResumeInfo{
+		RunID:    runID,
+		LastNode: cp.NodeID,
+		NextNode: startNode,
+		Sequence: cp.Sequence,
+		Metadata: cp.Metadata,
+	}, state)
+	if err != nil {
+		return state, err
+	}
+
 	// Continue execution from determined node
 	runCfg := defaultRunConfig()
+	runCfg.checkpointCompression = compression
+	runCfg.checkpointCodec = codec
+	runCfg.checkpointCipher = cfg.cipher
+	for _, opt := range cfg.runOptions {
+		opt(&runCfg)
+	}
 	runCfg.checkpointStore = store
 	runCfg.runID = runID
 	runCfg.sequence = cp.Sequence
+	runCfg.resuming = true
+	runCfg.live = registerRun(runID)
+	defer runCfg.live.unregister()
 
-	return cg.runFrom(ctx, state, startNode, &runCfg)
+	return cg.runFrom(bindRun(ctx, runID, runCfg.runBudgetUSD), state, startNode, &runCfg)
 }
 
 // ResumeFrom continues execution from a specific checkpoint.
@@ -137,8 +135,9 @@ func (cg *CompiledGraph[S]) ResumeFrom(ctx Context, store checkpoint.Store, runI
 
 	// Deserialize state
 	var state S
-	if err := json.Unmarshal(cp.State, &state); err != nil {
-		return zero, fmt.Errorf("%w: %w", ErrDeserializeState, err)
+	compression, codec, err := decodeCheckpointState(cp, codecOption(cfg.runOptions), cfg.cipher, &state)
+	if err != nil {
+		return zero, err
 	}
 
 	// Apply state override if configured
@@ -168,11 +167,81 @@ func (cg *CompiledGraph[S]) ResumeFrom(ctx Context, store checkpoint.Store, runI
 		return zero, fmt.Errorf("%w: %s", ErrInvalidResumeNode, startNode)
 	}
 
+	state, err = runBeforeResume(&cfg, ResumeInfo{
+		RunID:    runID,
+		LastNode: nodeID,
+		NextNode: startNode,
+		Sequence: cp.Sequence,
+		Metadata: cp.Metadata,
+	}, state)
+	if err != nil {
+		return state, err
+	}
+
 	// Continue execution from determined node
 	runCfg := defaultRunConfig()
+	runCfg.checkpointCompression = compression
+	runCfg.checkpointCodec = codec
+	runCfg.checkpointCipher = cfg.cipher
+	for _, opt := range cfg.runOptions {
+		opt(&runCfg)
+	}
 	runCfg.checkpointStore = store
 	runCfg.runID = runID
 	runCfg.sequence = cp.Sequence
+	runCfg.resuming = true
+	runCfg.live = registerRun(runID)
+	defer runCfg.live.unregister()
+
+	return cg.runFrom(bindRun(ctx, runID, runCfg.runBudgetUSD), state, startNode, &runCfg)
+}
+
+// runBeforeResume applies the WithBeforeResume hook, if configured.
+func runBeforeResume[S any](cfg *resumeConfig, info ResumeInfo, state S) (S, error) {
+	if cfg.beforeResume == nil {
+		return state, nil
+	}
+
+	modified, err := cfg.beforeResume(info, state)
+	if err != nil {
+		return state, fmt.Errorf("%w: %w", ErrResumeAborted, err)
+	}
+	typed, ok := modified.(S)
+	if !ok {
+		return state, fmt.Errorf("%w: hook returned %T, expected %T", ErrResumeAborted, modified, state)
+	}
+	return typed, nil
+}
+
+// loadLatestCheckpoint loads the most recent checkpoint for a run.
+// Returns an error wrapping ErrNoCheckpoints if the run has none.
+func loadLatestCheckpoint(store checkpoint.Store, runID string) (*checkpoint.Checkpoint, error) {
+	infos, err := store.List(runID)
+	if err != nil {
+		return nil, fmt.Errorf("list checkpoints: %w", err)
+	}
+	if len(infos) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoCheckpoints, runID)
+	}
+
+	// Load the latest checkpoint (last in sequence)
+	latest := infos[len(infos)-1]
+	data, err := store.Load(runID, latest.NodeID)
+	if err != nil {
+		return nil, fmt.Errorf("load checkpoint: %w", err)
+	}
+
+	// Unmarshal checkpoint
+	cp, err := checkpoint.Unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDeserializeState, err)
+	}
+
+	// Check version compatibility
+	if cp.Version != checkpoint.Version {
+		return nil, fmt.Errorf("%w: got %d, expected %d",
+			ErrCheckpointVersionMismatch, cp.Version, checkpoint.Version)
+	}
 
-	return cg.runFrom(ctx, state, startNode, &runCfg)
+	return cp, nil
 }
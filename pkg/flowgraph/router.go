@@ -0,0 +1,59 @@
+package flowgraph
+
+import (
+	"fmt"
+	"sort"
+)
+
+// WeightedRouter returns a RouterFunc that picks a target at random, in
+// proportion to its weight, for A/B experiments and gradual rollouts.
+// Targets with zero weight are never picked. Targets may be node IDs or
+// END; unknown targets fail the run with a *RouterError when picked.
+//
+// The choice is drawn from Context.Rand, so it is reproducible: use
+// WithRandSeed, or a fixed WithRunID, to get the same choices in tests.
+//
+// Panics if weights is empty, a weight is negative, or all weights are
+// zero.
+//
+// Example:
+//
+//	graph.AddConditionalEdge("checkout", flowgraph.WeightedRouter[Order](map[string]int{
+//	    "checkoutV1": 90,
+//	    "checkoutV2": 10,
+//	}))
+//
+//	ctx := flowgraph.NewContext(context.Background(), flowgraph.WithRandSeed(42))
+func WeightedRouter[S any](weights map[string]int) RouterFunc[S] {
+	if len(weights) == 0 {
+		panic("flowgraph: weighted router needs at least one target")
+	}
+
+	// Sort targets so a seed always maps to the same choices
+	targets := make([]string, 0, len(weights))
+	for target, weight := range weights {
+		if weight < 0 {
+			panic(fmt.Sprintf("flowgraph: weighted router target %s has negative weight %d", target, weight))
+		}
+		if weight > 0 {
+			targets = append(targets, target)
+		}
+	}
+	if len(targets) == 0 {
+		panic("flowgraph: weighted router needs a target with positive weight")
+	}
+	sort.Strings(targets)
+
+	cumulative := make([]int, len(targets))
+	total := 0
+	for i, target := range targets {
+		total += weights[target]
+		cumulative[i] = total
+	}
+
+	return func(ctx Context, _ S) string {
+		n := ctx.Rand().IntN(total)
+		i := sort.SearchInts(cumulative, n+1)
+		return targets[i]
+	}
+}
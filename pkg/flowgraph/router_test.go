@@ -0,0 +1,72 @@
+package flowgraph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pickMany evaluates router n times with a context seeded with seed.
+func pickMany(router RouterFunc[Counter], seed uint64, n int) []string {
+	ctx := NewContext(context.Background(), WithRandSeed(seed))
+	picks := make([]string, n)
+	for i := range picks {
+		picks[i] = router(ctx, Counter{})
+	}
+	return picks
+}
+
+// TestWeightedRouter_Proportions tests that targets are picked in proportion to weight.
+func TestWeightedRouter_Proportions(t *testing.T) {
+	router := WeightedRouter[Counter](map[string]int{"a": 3, "b": 1, "never": 0})
+
+	counts := map[string]int{}
+	for _, target := range pickMany(router, 1, 4000) {
+		counts[target]++
+	}
+
+	assert.Zero(t, counts["never"])
+	assert.InDelta(t, 3000, counts["a"], 150)
+	assert.InDelta(t, 1000, counts["b"], 150)
+}
+
+// TestWeightedRouter_Deterministic tests that the same seed makes the same choices.
+func TestWeightedRouter_Deterministic(t *testing.T) {
+	weights := map[string]int{"a": 1, "b": 1, "c": 1}
+
+	first := pickMany(WeightedRouter[Counter](weights), 7, 50)
+	second := pickMany(WeightedRouter[Counter](weights), 7, 50)
+
+	assert.Equal(t, first, second)
+}
+
+// TestWeightedRouter_InGraph tests routing with AddConditionalEdge.
+func TestWeightedRouter_InGraph(t *testing.T) {
+	compiled, err := NewGraph[Counter]().
+		AddNode("start", passthrough[Counter]).
+		AddNode("inc", increment).
+		AddEdge("inc", END).
+		AddConditionalEdge("start", WeightedRouter[Counter](map[string]int{"inc": 1, END: 0})).
+		SetEntry("start").
+		Compile()
+	assert.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), Counter{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Value)
+}
+
+// TestWeightedRouter_Panics tests construction validation.
+func TestWeightedRouter_Panics(t *testing.T) {
+	assert.PanicsWithValue(t, "flowgraph: weighted router needs at least one target", func() {
+		WeightedRouter[Counter](nil)
+	})
+	assert.PanicsWithValue(t, "flowgraph: weighted router needs a target with positive weight", func() {
+		WeightedRouter[Counter](map[string]int{"a": 0})
+	})
+	assert.PanicsWithValue(t, "flowgraph: weighted router target a has negative weight -1", func() {
+		WeightedRouter[Counter](map[string]int{"a": -1})
+	})
+}
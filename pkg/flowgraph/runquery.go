@@ -0,0 +1,96 @@
+package flowgraph
+
+import "sync"
+
+// liveRuns holds the in-progress runs that Query can inspect, by run ID.
+var liveRuns = struct {
+	sync.RWMutex
+	runs map[string]*liveRun
+}{runs: make(map[string]*liveRun)}
+
+// liveRun is the latest committed state and current node of a run.
+type liveRun struct {
+	runID string
+
+	mu     sync.RWMutex
+	state  any
+	nodeID string
+}
+
+// registerRun makes runID queryable until unregister is called. It returns
+// nil (a no-op liveRun) for an empty run ID. A later run registered under
+// the same ID replaces the earlier one.
+func registerRun(runID string) *liveRun {
+	if runID == "" {
+		return nil
+	}
+	run := &liveRun{runID: runID}
+
+	liveRuns.Lock()
+	liveRuns.runs[runID] = run
+	liveRuns.Unlock()
+	return run
+}
+
+// unregister removes the run from the registry, unless it was replaced.
+func (r *liveRun) unregister() {
+	if r == nil {
+		return
+	}
+	liveRuns.Lock()
+	if liveRuns.runs[r.runID] == r {
+		delete(liveRuns.runs, r.runID)
+	}
+	liveRuns.Unlock()
+}
+
+// update records the state committed before nodeID executes.
+func (r *liveRun) update(nodeID string, state any) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.nodeID = nodeID
+	r.state = state
+	r.mu.Unlock()
+}
+
+// Query returns the latest committed state of an in-progress run in this
+// process, and the ID of the node it is executing. The state is the one
+// the current node received: the result of the last completed node, or of
+// the last fork/join. Query reports false if no run with that ID is in
+// progress, or if its state is not an S.
+//
+// Run, Resume and ResumeFrom make a run queryable while they execute,
+// under the run ID from WithRunID or the Context. Subgraph runs are not
+// registered separately; query the outer run.
+//
+// The returned state is a copy of the state value, so maps, slices and
+// pointers in it are shared with the run. Treat them as read-only, and
+// avoid nodes that mutate them in place if the run is queried.
+//
+// Example:
+//
+//	go compiled.Run(ctx, state, flowgraph.WithRunID("run-123"))
+//
+//	if st, node, ok := flowgraph.Query[State]("run-123"); ok {
+//	    fmt.Printf("at %s, %d items processed\n", node, st.Processed)
+//	}
+func Query[S any](runID string) (S, string, bool) {
+	var zero S
+
+	liveRuns.RLock()
+	run, ok := liveRuns.runs[runID]
+	liveRuns.RUnlock()
+	if !ok {
+		return zero, "", false
+	}
+
+	run.mu.RLock()
+	defer run.mu.RUnlock()
+	state, ok := run.state.(S)
+	if !ok {
+		return zero, "", false
+	}
+	return state, run.nodeID, true
+}
@@ -0,0 +1,86 @@
+package flowgraph
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/checkpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQuery_RunningGraph tests that Query sees a run's committed state while it executes.
+func TestQuery_RunningGraph(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	blocking := func(ctx Context, s Counter) (Counter, error) {
+		close(entered)
+		<-release
+		s.Value += 10
+		return s, nil
+	}
+	compiled, err := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddNode("b", increment).
+		AddNode("wait", blocking).
+		AddEdge("a", "b").
+		AddEdge("b", "wait").
+		AddEdge("wait", END).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+
+	_, _, ok := Query[Counter]("query-run")
+	assert.False(t, ok, "not started")
+
+	var wg sync.WaitGroup
+	var result Counter
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		result, _ = compiled.Run(testCtx(), Counter{}, WithRunID("query-run"))
+	}()
+
+	<-entered
+	state, node, ok := Query[Counter]("query-run")
+	require.True(t, ok)
+	assert.Equal(t, "wait", node)
+	assert.Equal(t, 2, state.Value)
+
+	_, _, ok = Query[State]("query-run")
+	assert.False(t, ok, "wrong state type")
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, 12, result.Value)
+
+	_, _, ok = Query[Counter]("query-run")
+	assert.False(t, ok, "finished runs are unregistered")
+}
+
+// TestQuery_Resume tests that a resumed run is queryable.
+func TestQuery_Resume(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	var seen []string
+	probe := func(ctx Context, s Counter) (Counter, error) {
+		if _, node, ok := Query[Counter]("resume-query"); ok {
+			seen = append(seen, node)
+		}
+		return s, nil
+	}
+	compiled, err := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddNode("probe", probe).
+		AddEdge("a", "probe").
+		AddEdge("probe", END).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), Counter{}, WithCheckpointing(store), WithRunID("resume-query"))
+	require.NoError(t, err)
+
+	_, err = compiled.ResumeFrom(testCtx(), store, "resume-query", "a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"probe", "probe"}, seen)
+}
@@ -0,0 +1,62 @@
+package flowgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
+)
+
+// runWithRetry runs the graph under cfg.runRetry, restarting from the entry
+// point with the initial state after each retryable failure. It returns the
+// result of the last attempt, and its error unwrapped.
+func (cg *CompiledGraph[S]) runWithRetry(ctx Context, state S, cfg runConfig) (S, error) {
+	baseRunID := cfg.runID
+	if baseRunID == "" {
+		baseRunID = ctx.RunID()
+	}
+
+	retry := *cfg.runRetry
+	retryable := retry.RetryableFunc
+	if retryable == nil {
+		retryable = fgerrors.IsRetryable
+	}
+	retry.RetryableFunc = func(err error) bool {
+		if errors.Is(err, ErrSuspended) || errors.Is(err, ErrInterrupted) {
+			return false
+		}
+		return retryable(err)
+	}
+
+	attempt := 0
+	last := state
+	var lastErr error
+	fgerrors.WithRetryContext(ctx, retry, func(context.Context) (S, error) {
+		attemptCfg := cfg
+		attemptCfg.runID = retryRunID(baseRunID, attempt)
+		attempt++
+
+		last, lastErr = cg.run(ctx, state, attemptCfg)
+		if lastErr != nil && attempt < retry.MaxAttempts && retry.RetryableFunc(lastErr) {
+			ctx.Logger().Warn("run attempt failed, retrying",
+				"run_id", attemptCfg.runID, "attempt", attempt, "error", lastErr)
+		}
+		return last, lastErr
+	})
+
+	if attempt == 0 {
+		// Cancelled before the first attempt; run reports the cancellation
+		return cg.run(ctx, state, cfg)
+	}
+	return last, lastErr
+}
+
+// retryRunID returns the run ID of a run retry attempt, counted from 0.
+// The first attempt keeps the run's own ID.
+func retryRunID(runID string, attempt int) string {
+	if attempt == 0 || runID == "" {
+		return runID
+	}
+	return fmt.Sprintf("%s-retry-%d", runID, attempt)
+}
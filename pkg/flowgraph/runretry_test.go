@@ -0,0 +1,120 @@
+package flowgraph
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/checkpoint"
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyCounterGraph compiles a -> flaky, where flaky fails with err on
+// its first failures runs. Run IDs seen by flaky are appended to runIDs.
+func flakyCounterGraph(t *testing.T, failures int, err error, runIDs *[]string) *CompiledGraph[Counter] {
+	t.Helper()
+	flaky := func(ctx Context, s Counter) (Counter, error) {
+		*runIDs = append(*runIDs, ctx.RunID())
+		if len(*runIDs) <= failures {
+			return s, err
+		}
+		return s, nil
+	}
+	compiled, compileErr := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddNode("flaky", flaky).
+		AddEdge("a", "flaky").
+		AddEdge("flaky", END).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, compileErr)
+	return compiled
+}
+
+// TestRunRetry_RestartsWithInitialState tests that retries start over from the entry point.
+func TestRunRetry_RestartsWithInitialState(t *testing.T) {
+	var runIDs []string
+	transient := fgerrors.Transient(errors.New("flaky"), "test")
+	compiled := flakyCounterGraph(t, 2, transient, &runIDs)
+	store := checkpoint.NewMemoryStore()
+
+	result, err := compiled.Run(testCtx(), Counter{Value: 10},
+		WithRunID("run-1"),
+		WithCheckpointing(store),
+		WithRunRetry(fastRetry))
+
+	require.NoError(t, err)
+	assert.Equal(t, 11, result.Value, "each attempt starts from the initial state")
+	assert.Equal(t, []string{"run-1", "run-1-retry-1", "run-1-retry-2"}, runIDs)
+
+	infos, err := store.List("run-1-retry-2")
+	require.NoError(t, err)
+	assert.NotEmpty(t, infos, "attempts checkpoint under their own run ID")
+}
+
+// TestRunRetry_Exhausted tests that the last attempt's error is returned unwrapped.
+func TestRunRetry_Exhausted(t *testing.T) {
+	var runIDs []string
+	transient := fgerrors.Transient(errors.New("flaky"), "test")
+	compiled := flakyCounterGraph(t, 5, transient, &runIDs)
+
+	retry := fastRetry
+	retry.MaxAttempts = 2
+
+	_, err := compiled.Run(testCtx(), Counter{}, WithRunID("run-1"), WithRunRetry(retry))
+
+	var nodeErr *NodeError
+	require.ErrorAs(t, err, &nodeErr)
+	assert.Equal(t, "flaky", nodeErr.NodeID)
+	assert.Len(t, runIDs, 2)
+}
+
+// TestRunRetry_NonRetryable tests that permanent errors fail immediately.
+func TestRunRetry_NonRetryable(t *testing.T) {
+	var runIDs []string
+	compiled := flakyCounterGraph(t, 5, errors.New("permanent"), &runIDs)
+
+	_, err := compiled.Run(testCtx(), Counter{}, WithRunID("run-1"), WithRunRetry(fastRetry))
+
+	assert.Error(t, err)
+	assert.Equal(t, []string{"run-1"}, runIDs)
+}
+
+// TestRunRetry_NeverRetriesInterrupt tests that interrupts are not retried, even by RetryableFunc.
+func TestRunRetry_NeverRetriesInterrupt(t *testing.T) {
+	var runIDs []string
+	compiled := flakyCounterGraph(t, 5, Interrupt("wait"), &runIDs)
+	retry := fastRetry
+	retry.RetryableFunc = func(error) bool { return true }
+
+	_, err := compiled.Run(testCtx(), Counter{}, WithRunID("run-1"), WithRunRetry(retry))
+
+	assert.ErrorIs(t, err, ErrInterrupted)
+	assert.Len(t, runIDs, 1)
+}
+
+// TestRunRetry_Cancelled tests that cancellation stops retrying during backoff.
+func TestRunRetry_Cancelled(t *testing.T) {
+	var runIDs []string
+	transient := fgerrors.Transient(errors.New("flaky"), "test")
+	compiled := flakyCounterGraph(t, 5, transient, &runIDs)
+
+	goCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	retry := fgerrors.RetryConfig{MaxAttempts: 5, InitialBackoff: time.Hour, MaxBackoff: time.Hour, BackoffFactor: 1}
+
+	_, err := compiled.Run(NewContext(goCtx), Counter{}, WithRunRetry(retry))
+
+	assert.Error(t, err)
+	assert.Len(t, runIDs, 1)
+}
+
+// TestWithRunRetry_Panics tests that a retry config without attempts panics.
+func TestWithRunRetry_Panics(t *testing.T) {
+	assert.PanicsWithValue(t, "flowgraph: run retry needs at least one attempt", func() {
+		WithRunRetry(fgerrors.RetryConfig{})
+	})
+}
@@ -0,0 +1,80 @@
+package saga
+
+import (
+	"context"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+)
+
+// EventSource is the source of events published with WithEventBus.
+const EventSource = "saga"
+
+// Lifecycle event types published with WithEventBus.
+const (
+	EventStarted       = "saga.started"
+	EventStepCompleted = "saga.step.completed"
+	EventStepFailed    = "saga.step.failed"
+	EventCompensated   = "saga.compensated"
+	EventCompleted     = "saga.completed"
+)
+
+// LifecycleEvent is the payload of events published with WithEventBus.
+type LifecycleEvent struct {
+	// ExecutionID is the saga execution, also the event's correlation ID.
+	ExecutionID string `json:"execution_id"`
+
+	// SagaName is the name of the saga definition.
+	SagaName string `json:"saga_name"`
+
+	// Step is the step name, set only on step events.
+	Step string `json:"step,omitempty"`
+
+	// Status is the step's status on step events and the execution's
+	// status otherwise. On EventCompensated it is StatusFailed if any
+	// compensation failed.
+	Status Status `json:"status"`
+
+	// Error is the step error on step events, or the compensation errors
+	// on EventCompensated.
+	Error string `json:"error,omitempty"`
+}
+
+// WithEventBus publishes saga lifecycle events to bus: EventStarted,
+// EventStepCompleted, EventStepFailed, EventCompensated and EventCompleted.
+// Each carries a LifecycleEvent payload, and its correlation ID is the
+// execution ID, so subscribers can follow one execution. A failed optional
+// step is published as EventStepCompleted with Error set, and skipped steps
+// are not published.
+//
+// Events are published synchronously as the saga progresses. Publish
+// errors are logged and do not affect the saga.
+//
+// Example:
+//
+//	bus := event.NewBus(event.DefaultBusConfig)
+//	orch := saga.NewOrchestrator(saga.WithEventBus(bus))
+//	bus.Subscribe([]string{"saga.*", "saga.step.*"}, auditHandler)
+func WithEventBus(bus event.Bus) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.bus = bus
+	}
+}
+
+// publish sends a lifecycle event for execution to the event bus, if one
+// is configured.
+func (o *Orchestrator) publish(ctx context.Context, eventType string, execution *Execution, payload LifecycleEvent) {
+	if o.bus == nil {
+		return
+	}
+
+	payload.ExecutionID = execution.ID
+	payload.SagaName = execution.SagaName
+	evt := event.New(eventType, EventSource, "", payload, event.WithCorrelationID(execution.ID))
+	if err := o.bus.Publish(ctx, evt); err != nil {
+		o.logger.Error("failed to publish saga event",
+			"saga_id", execution.ID,
+			"event_type", eventType,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,132 @@
+package saga_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/saga"
+)
+
+// eventRecorder collects events delivered by a bus subscription.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []event.Event
+}
+
+func (r *eventRecorder) Handle(_ context.Context, evt event.Event) ([]event.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, evt)
+	return nil, nil
+}
+
+func (r *eventRecorder) Handles() []string { return nil }
+
+func (r *eventRecorder) types() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	types := make([]string, len(r.events))
+	for i, evt := range r.events {
+		types[i] = evt.Type()
+	}
+	return types
+}
+
+func (r *eventRecorder) payloads() []saga.LifecycleEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	payloads := make([]saga.LifecycleEvent, len(r.events))
+	for i, evt := range r.events {
+		payloads[i] = evt.Data().(saga.LifecycleEvent)
+	}
+	return payloads
+}
+
+func newRecordingBus(t *testing.T) (*event.LocalBus, *eventRecorder) {
+	t.Helper()
+	bus := event.NewBus(event.DefaultBusConfig)
+	t.Cleanup(func() { bus.Close() })
+	rec := &eventRecorder{}
+	bus.SubscribeAll(rec)
+	return bus, rec
+}
+
+func TestWithEventBus_Completed(t *testing.T) {
+	bus, rec := newRecordingBus(t)
+	orch := saga.NewOrchestrator(saga.WithEventBus(bus))
+	orch.MustRegister(&saga.Definition{
+		Name: "order",
+		Steps: []saga.Step{
+			{Name: "reserve", Handler: func(_ context.Context, _ any) (any, error) { return "r", nil }},
+			{Name: "charge", Handler: func(_ context.Context, _ any) (any, error) { return "c", nil }},
+		},
+	})
+
+	execution, err := orch.Start(context.Background(), "order", nil)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(rec.types()) == 4 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{
+		saga.EventStarted,
+		saga.EventStepCompleted,
+		saga.EventStepCompleted,
+		saga.EventCompleted,
+	}, rec.types())
+
+	payloads := rec.payloads()
+	assert.Equal(t, "reserve", payloads[1].Step)
+	assert.Equal(t, "charge", payloads[2].Step)
+	assert.Equal(t, saga.StatusCompleted, payloads[2].Status)
+	assert.Equal(t, saga.StatusCompleted, payloads[3].Status)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	for _, evt := range rec.events {
+		assert.Equal(t, execution.ID, evt.CorrelationID())
+		assert.Equal(t, saga.EventSource, evt.Source())
+		assert.Equal(t, "order", evt.Data().(saga.LifecycleEvent).SagaName)
+	}
+}
+
+func TestWithEventBus_Compensated(t *testing.T) {
+	bus, rec := newRecordingBus(t)
+	orch := saga.NewOrchestrator(saga.WithEventBus(bus))
+	orch.MustRegister(&saga.Definition{
+		Name: "order",
+		Steps: []saga.Step{
+			{
+				Name:         "reserve",
+				Handler:      func(_ context.Context, _ any) (any, error) { return "r", nil },
+				Compensation: func(_ context.Context, _ any) (any, error) { return nil, nil },
+			},
+			{
+				Name:    "charge",
+				Handler: func(_ context.Context, _ any) (any, error) { return nil, errors.New("card declined") },
+			},
+		},
+	})
+
+	_, err := orch.Start(context.Background(), "order", nil)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(rec.types()) == 4 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{
+		saga.EventStarted,
+		saga.EventStepCompleted,
+		saga.EventStepFailed,
+		saga.EventCompensated,
+	}, rec.types())
+
+	payloads := rec.payloads()
+	assert.Equal(t, "charge", payloads[2].Step)
+	assert.Equal(t, saga.StatusFailed, payloads[2].Status)
+	assert.Equal(t, "card declined", payloads[2].Error)
+	assert.Equal(t, saga.StatusCompensated, payloads[3].Status)
+}
@@ -0,0 +1,40 @@
+package saga
+
+import "context"
+
+// idempotencyKeyCtx is the context key for the running step's idempotency key.
+type idempotencyKeyCtx struct{}
+
+// IdempotencyKeyFromContext returns the idempotency key of the saga step
+// whose Handler or Compensation received ctx, or "" outside a step.
+//
+// The key is stable across retries and across Recover, so a handler can
+// record it alongside its side effect and skip the effect when it sees the
+// key again.
+//
+// Example:
+//
+//	Handler: func(ctx context.Context, input any) (any, error) {
+//	    key := saga.IdempotencyKeyFromContext(ctx)
+//	    if charge, ok := payments.Lookup(key); ok {
+//	        return charge, nil // Already charged by an earlier attempt
+//	    }
+//	    return payments.Charge(ctx, key, input.(Order).Total)
+//	},
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyCtx{}).(string)
+	return key
+}
+
+// withIdempotencyKey returns ctx carrying key for the step's handlers.
+func withIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+// stepIdempotencyKey returns the idempotency key of step for input.
+func stepIdempotencyKey(executionID string, step *Step, input any) string {
+	if step.IdempotencyKey != nil {
+		return step.IdempotencyKey(input)
+	}
+	return executionID + "/" + step.Name
+}
@@ -5,7 +5,9 @@
 // completed steps are compensated in reverse order.
 //
 // This package supports both orchestration (centralized coordinator) and
-// choreography (event-driven) saga patterns.
+// choreography (event-driven) saga patterns. With WithEventBus, the
+// orchestrator publishes lifecycle events (see EventStarted) that
+// choreography consumers and audit logs can subscribe to.
 //
 // Design Influences:
 //   - Microservices.io Saga Pattern
@@ -22,8 +24,14 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/event"
 )
 
+// ErrTotalTimeout is the cause recorded in Execution.Error when a saga
+// exceeds its Definition.TotalTimeout.
+var ErrTotalTimeout = errors.New("saga total timeout exceeded")
+
 // Status represents the state of a saga execution.
 type Status string
 
@@ -35,6 +43,9 @@ const (
 	StatusCompensating Status = "compensating"
 	StatusCompensated  Status = "compensated"
 	StatusFailed       Status = "failed"
+
+	// StatusSkipped marks a step whose Condition returned false.
+	StatusSkipped Status = "skipped"
 )
 
 // StepHandler executes a saga step.
@@ -60,10 +71,40 @@ type Step struct {
 	Optional bool
 
 	// RetryPolicy configures retries for this step.
+	// Nil means use the orchestrator default (see WithRetryPolicy).
 	RetryPolicy *RetryPolicy
+
+	// Condition decides whether this step runs. It receives the same input
+	// the Handler would. If it returns false, the step is marked
+	// StatusSkipped, is never compensated, and its input passes through to
+	// the next step. Nil means always run.
+	Condition func(ctx context.Context, input any) bool
+
+	// ParallelGroup runs this step concurrently with the consecutive steps
+	// that share the same non-empty group. Every member receives the same
+	// input, and the step after the group receives a []any holding each
+	// member's output in step order (nil for skipped members and failed
+	// optional members). Members may finish, and publish their lifecycle
+	// events, in any order; the next step starts only once all have.
+	// If any non-optional member failed, the members that succeeded are
+	// compensated, in reverse step order, before the earlier steps.
+	ParallelGroup string
+
+	// IdempotencyKey derives the key that identifies this step's effect
+	// for the given input, so handlers can skip work that was already
+	// applied by an earlier attempt or before a resume. The key is stored
+	// in StepExecution.IdempotencyKey and passed to the Handler and the
+	// Compensation through the context (see IdempotencyKeyFromContext).
+	// Nil means "<execution ID>/<step name>".
+	IdempotencyKey func(input any) string
 }
 
 // RetryPolicy configures step retry behavior.
+//
+// A failed handler is retried until MaxAttempts attempts have been made,
+// waiting InitialWait before the first retry and multiplying the wait by
+// Multiplier after each one, up to MaxWait. A MaxWait of zero leaves the
+// wait unbounded, and a Multiplier below 1 keeps it constant.
 type RetryPolicy struct {
 	MaxAttempts int
 	InitialWait time.Duration
@@ -90,6 +131,13 @@ type Definition struct {
 	// Timeout is the default timeout per step.
 	Timeout time.Duration
 
+	// TotalTimeout bounds the whole saga, measured from its start. If it
+	// expires before the last step completes, the running step is
+	// cancelled and completed steps are compensated, with Execution.Error
+	// wrapping ErrTotalTimeout. Step timeouts nest inside it. Zero means
+	// no limit.
+	TotalTimeout time.Duration
+
 	// OnComplete is called when the saga completes successfully.
 	OnComplete func(ctx context.Context, execution *Execution)
 
@@ -105,6 +153,9 @@ func (d *Definition) Validate() error {
 	if len(d.Steps) == 0 {
 		return errors.New("saga must have at least one step")
 	}
+	if d.TotalTimeout < 0 {
+		return errors.New("saga total timeout cannot be negative")
+	}
 	for i, step := range d.Steps {
 		if step.Name == "" {
 			return fmt.Errorf("step %d: name is required", i)
@@ -112,10 +163,43 @@ func (d *Definition) Validate() error {
 		if step.Handler == nil {
 			return fmt.Errorf("step %d (%s): handler is required", i, step.Name)
 		}
+		if g := step.ParallelGroup; g != "" && i > 0 && d.Steps[i-1].ParallelGroup != g {
+			for _, earlier := range d.Steps[:i-1] {
+				if earlier.ParallelGroup == g {
+					return fmt.Errorf("step %d (%s): parallel group %q must be consecutive", i, step.Name, g)
+				}
+			}
+		}
 	}
 	return nil
 }
 
+// groupEnd returns the index of the last step in the parallel group that
+// starts at step i, or i if step i is not in a group.
+func (d *Definition) groupEnd(i int) int {
+	g := d.Steps[i].ParallelGroup
+	if g == "" {
+		return i
+	}
+	for i+1 < len(d.Steps) && d.Steps[i+1].ParallelGroup == g {
+		i++
+	}
+	return i
+}
+
+// groupStart returns the index of the first step in the parallel group
+// containing step i, or i if step i is not in a group.
+func (d *Definition) groupStart(i int) int {
+	g := d.Steps[i].ParallelGroup
+	if g == "" {
+		return i
+	}
+	for i > 0 && d.Steps[i-1].ParallelGroup == g {
+		i--
+	}
+	return i
+}
+
 // StepExecution tracks a single step's execution.
 type StepExecution struct {
 	StepName   string        `json:"step_name"`
@@ -127,6 +211,18 @@ type StepExecution struct {
 	FinishedAt time.Time     `json:"finished_at,omitempty"`
 	Duration   time.Duration `json:"duration,omitempty"`
 	Retries    int           `json:"retries"`
+
+	// IdempotencyKey is the key the step's handlers received; see
+	// Step.IdempotencyKey.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	// CompensationStatus is StatusCompensated or StatusFailed once the
+	// step's compensation has run, and empty before then or when the step
+	// has no compensation.
+	CompensationStatus Status `json:"compensation_status,omitempty"`
+
+	// CompensationError is the error of the last failed compensation.
+	CompensationError string `json:"compensation_error,omitempty"`
 }
 
 // Execution tracks the complete saga execution.
@@ -175,6 +271,8 @@ type Orchestrator struct {
 	sagas      map[string]*Definition
 	executions map[string]*Execution // Used when store is nil (in-memory mode)
 	store      Store                 // Optional persistent store
+	retry      *RetryPolicy          // Default for steps without a RetryPolicy
+	bus        event.Bus             // Optional lifecycle event bus
 	mu         sync.RWMutex
 	logger     *slog.Logger
 }
@@ -191,6 +289,18 @@ func WithStore(store Store) OrchestratorOption {
 	}
 }
 
+// WithRetryPolicy sets the retry policy for steps that do not set their
+// own. If not set, such steps run once.
+//
+// Example:
+//
+//	orch := saga.NewOrchestrator(saga.WithRetryPolicy(saga.DefaultRetryPolicy))
+func WithRetryPolicy(policy *RetryPolicy) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.retry = policy
+	}
+}
+
 // WithLogger configures the logger for the orchestrator.
 func WithLogger(logger *slog.Logger) OrchestratorOption {
 	return func(o *Orchestrator) {
@@ -279,86 +389,62 @@ func (o *Orchestrator) Start(ctx context.Context, sagaName string, input any) (*
 		o.mu.Unlock()
 	}
 
+	o.publish(ctx, EventStarted, execution, LifecycleEvent{Status: StatusRunning})
+
 	// Execute saga steps asynchronously
-	go o.execute(ctx, saga, execution)
+	go o.execute(ctx, saga, execution, 0, input)
 
 	return execution, nil
 }
 
-// execute runs the saga steps sequentially.
-func (o *Orchestrator) execute(ctx context.Context, saga *Definition, execution *Execution) {
-	currentOutput := execution.Input
+// execute runs the saga steps sequentially, starting at step start with
+// the given input.
+func (o *Orchestrator) execute(ctx context.Context, saga *Definition, execution *Execution, start int, input any) {
+	currentOutput := input
 	var stepErr error
 
-	for i := range saga.Steps {
-		step := &saga.Steps[i]
+	// Steps run under the saga deadline; persistence and compensation use
+	// ctx so they still run once the deadline has passed
+	sagaCtx := ctx
+	if saga.TotalTimeout > 0 {
+		var cancel context.CancelFunc
+		sagaCtx, cancel = context.WithDeadline(ctx, execution.StartedAt.Add(saga.TotalTimeout))
+		defer cancel()
+	}
 
+	for i := start; i < len(saga.Steps); i++ {
 		// Check for cancellation
 		select {
-		case <-ctx.Done():
-			o.compensateFrom(ctx, saga, execution, i-1, ctx.Err())
+		case <-sagaCtx.Done():
+			o.compensateFrom(ctx, saga, execution, i-1, sagaCause(ctx, sagaCtx, saga, sagaCtx.Err()))
 			return
 		default:
 		}
 
-		stepExec := &execution.Steps[i]
+		if end := saga.groupEnd(i); end > i {
+			currentOutput, stepErr = o.runGroup(ctx, sagaCtx, saga, execution, i, end, currentOutput)
+			if stepErr != nil {
+				o.compensateFrom(ctx, saga, execution, end, stepErr)
+				return
+			}
+			i = end
+			continue
+		}
+
 		execution.mu.Lock()
 		execution.CurrentStep = i
-		stepExec.Status = StatusRunning
-		stepExec.StartedAt = time.Now()
-		stepExec.Input = currentOutput
 		execution.mu.Unlock()
 
-		// Persist step start
-		o.persistExecution(ctx, execution)
-
-		// Execute step with timeout
 		var output any
-		output, stepErr = o.executeStep(ctx, saga, step, currentOutput)
-
-		execution.mu.Lock()
-		stepExec.FinishedAt = time.Now()
-		stepExec.Duration = stepExec.FinishedAt.Sub(stepExec.StartedAt)
-
+		var produced bool
+		output, produced, stepErr = o.runStep(ctx, sagaCtx, saga, execution, i, currentOutput)
 		if stepErr != nil {
-			stepExec.Status = StatusFailed
-			stepExec.Error = stepErr.Error()
-
-			// Handle optional steps
-			if step.Optional {
-				o.logger.Debug("optional saga step failed, continuing",
-					"saga_id", execution.ID,
-					"step", step.Name,
-					"error", stepErr,
-				)
-				stepExec.Status = StatusCompleted
-				stepErr = nil
-			}
-		} else {
-			stepExec.Status = StatusCompleted
-			stepExec.Output = output
-			currentOutput = output
-		}
-		execution.mu.Unlock()
-
-		// Persist step completion
-		o.persistExecution(ctx, execution)
-
-		if stepErr != nil {
-			o.logger.Error("saga step failed",
-				"saga_id", execution.ID,
-				"saga_name", saga.Name,
-				"step", step.Name,
-				"error", stepErr,
-			)
 			o.compensateFrom(ctx, saga, execution, i-1, stepErr)
 			return
 		}
-
-		o.logger.Debug("saga step completed",
-			"saga_id", execution.ID,
-			"step", step.Name,
-		)
+		if produced {
+			currentOutput = output
+		}
 	}
 
 	// All steps completed successfully
@@ -376,16 +462,165 @@ func (o *Orchestrator) execute(ctx context.Context, saga *Definition, execution
 		"saga_name", saga.Name,
 	)
 
+	o.publish(ctx, EventCompleted, execution, LifecycleEvent{Status: StatusCompleted})
+
 	if saga.OnComplete != nil {
 		saga.OnComplete(ctx, execution.Clone())
 	}
 }
 
-// executeStep runs a single step with timeout.
+// runGroup runs steps first through last of a parallel group concurrently
+// with the same input and returns their outputs in step order. Members
+// that already completed or were skipped before a resume are not run
+// again. Returns the first failure, in step order, of a non-optional
+// member once all members have finished.
+func (o *Orchestrator) runGroup(ctx, sagaCtx context.Context, saga *Definition, execution *Execution, first, last int, input any) (any, error) {
+	execution.mu.Lock()
+	execution.CurrentStep = first
+	execution.mu.Unlock()
+
+	outputs := make([]any, last-first+1)
+	errs := make([]error, len(outputs))
+	var wg sync.WaitGroup
+	for i := first; i <= last; i++ {
+		execution.mu.Lock()
+		stepExec := execution.Steps[i]
+		execution.mu.Unlock()
+		if stepExec.Status == StatusSkipped {
+			continue
+		}
+		if stepExec.Status == StatusCompleted {
+			if stepExec.Error == "" {
+				outputs[i-first] = stepExec.Output
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			output, produced, err := o.runStep(ctx, sagaCtx, saga, execution, i, input)
+			if produced {
+				outputs[i-first] = output
+			}
+			errs[i-first] = err
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return outputs, nil
+}
+
+// runStep runs step i with input: it evaluates the step's Condition,
+// executes the handler with retries, and records the outcome. produced
+// reports whether the handler ran and succeeded, so its output passes on;
+// it is false for a skipped step and for a failed optional step, which is
+// recorded as completed and returns no error.
+func (o *Orchestrator) runStep(ctx, sagaCtx context.Context, saga *Definition, execution *Execution, i int, input any) (output any, produced bool, err error) {
+	step := &saga.Steps[i]
+	stepExec := &execution.Steps[i]
+
+	if step.Condition != nil && !step.Condition(sagaCtx, input) {
+		execution.mu.Lock()
+		stepExec.Status = StatusSkipped
+		stepExec.Input = input
+		execution.mu.Unlock()
+
+		o.persistExecution(ctx, execution)
+
+		o.logger.Debug("saga step skipped",
+			"saga_id", execution.ID,
+			"step", step.Name,
+		)
+		return nil, false, nil
+	}
+
+	key := stepIdempotencyKey(execution.ID, step, input)
+
+	execution.mu.Lock()
+	stepExec.Status = StatusRunning
+	stepExec.StartedAt = time.Now()
+	stepExec.Input = input
+	stepExec.IdempotencyKey = key
+	execution.mu.Unlock()
+
+	// Persist step start
+	o.persistExecution(ctx, execution)
+
+	// Execute step with timeout
+	output, err = o.executeStep(withIdempotencyKey(sagaCtx, key), saga, execution, step, stepExec, input)
+	if err != nil {
+		err = sagaCause(ctx, sagaCtx, saga, err)
+	}
+
+	execution.mu.Lock()
+	stepExec.FinishedAt = time.Now()
+	stepExec.Duration = stepExec.FinishedAt.Sub(stepExec.StartedAt)
+
+	if err != nil {
+		stepExec.Status = StatusFailed
+		stepExec.Error = err.Error()
+
+		// Handle optional steps
+		if step.Optional {
+			o.logger.Debug("optional saga step failed, continuing",
+				"saga_id", execution.ID,
+				"step", step.Name,
+				"error", err,
+			)
+			stepExec.Status = StatusCompleted
+		}
+	} else {
+		stepExec.Status = StatusCompleted
+		stepExec.Output = output
+	}
+	stepEvent := LifecycleEvent{Step: step.Name, Status: stepExec.Status, Error: stepExec.Error}
+	execution.mu.Unlock()
+
+	// Persist step completion
+	o.persistExecution(ctx, execution)
+
+	if err != nil && !step.Optional {
+		o.publish(ctx, EventStepFailed, execution, stepEvent)
+		o.logger.Error("saga step failed",
+			"saga_id", execution.ID,
+			"saga_name", saga.Name,
+			"step", step.Name,
+			"error", err,
+		)
+		return nil, false, err
+	}
+	o.publish(ctx, EventStepCompleted, execution, stepEvent)
+
+	o.logger.Debug("saga step completed",
+		"saga_id", execution.ID,
+		"step", step.Name,
+	)
+	return output, err == nil, nil
+}
+
+// sagaCause returns err, wrapped with ErrTotalTimeout if the saga
+// deadline has passed while the caller's ctx is still live.
+func sagaCause(ctx, sagaCtx context.Context, saga *Definition, err error) error {
+	if ctx.Err() == nil && errors.Is(sagaCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s: %w", ErrTotalTimeout, saga.TotalTimeout, err)
+	}
+	return err
+}
+
+// executeStep runs a single step with timeout, retrying failures per the
+// step's retry policy. Each retry increments stepExec.Retries.
 func (o *Orchestrator) executeStep(
 	ctx context.Context,
 	saga *Definition,
+	execution *Execution,
 	step *Step,
+	stepExec *StepExecution,
 	input any,
 ) (any, error) {
 	timeout := step.Timeout
@@ -396,12 +631,67 @@ func (o *Orchestrator) executeStep(
 		timeout = 30 * time.Second
 	}
 
+	policy := step.RetryPolicy
+	if policy == nil {
+		policy = o.retry
+	}
+	maxAttempts := 1
+	var wait time.Duration
+	if policy != nil && policy.MaxAttempts > 1 {
+		maxAttempts = policy.MaxAttempts
+		wait = policy.InitialWait
+	}
+
+	for attempt := 1; ; attempt++ {
+		output, err := o.attemptStep(ctx, step, input, timeout)
+		if err == nil || attempt >= maxAttempts {
+			return output, err
+		}
+
+		o.logger.Debug("saga step failed, retrying",
+			"saga_id", execution.ID,
+			"step", step.Name,
+			"attempt", attempt,
+			"wait", wait,
+			"error", err,
+		)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		case <-timer.C:
+		}
+
+		execution.mu.Lock()
+		stepExec.Retries++
+		execution.mu.Unlock()
+		o.persistExecution(ctx, execution)
+
+		wait = nextWait(wait, policy)
+	}
+}
+
+// attemptStep runs the step handler once under timeout.
+func (o *Orchestrator) attemptStep(ctx context.Context, step *Step, input any, timeout time.Duration) (any, error) {
 	stepCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	return step.Handler(stepCtx, input)
 }
 
+// nextWait returns the backoff before the retry after one that waited wait.
+func nextWait(wait time.Duration, policy *RetryPolicy) time.Duration {
+	if policy.Multiplier > 1 {
+		wait = time.Duration(float64(wait) * policy.Multiplier)
+	}
+	if policy.MaxWait > 0 && wait > policy.MaxWait {
+		wait = policy.MaxWait
+	}
+	return wait
+}
+
 // persistExecution saves the execution to the store if configured.
 func (o *Orchestrator) persistExecution(ctx context.Context, execution *Execution) {
 	if o.store == nil {
@@ -446,26 +736,135 @@ func (o *Orchestrator) compensateFrom(
 		step := &saga.Steps[i]
 		stepExec := &execution.Steps[i]
 
-		// Skip if step wasn't completed or has no compensation
-		if stepExec.Status != StatusCompleted || step.Compensation == nil {
+		// Skip if step wasn't completed, has no compensation, or was
+		// already compensated before a restart
+		if stepExec.Status != StatusCompleted || step.Compensation == nil ||
+			stepExec.CompensationStatus == StatusCompensated {
 			continue
 		}
 
-		o.logger.Debug("compensating saga step",
+		if compErr := o.compensateStep(ctx, execution, step, stepExec); compErr != nil {
+			compensateErrors = append(compensateErrors,
+				fmt.Sprintf("%s: %s", step.Name, compErr.Error()))
+		}
+	}
+
+	now := time.Now()
+	execution.mu.Lock()
+	if len(compensateErrors) > 0 {
+		execution.Status = StatusFailed
+		execution.CompensateError = fmt.Sprintf("compensation errors: %v", compensateErrors)
+	} else {
+		execution.Status = StatusCompensated
+	}
+	execution.CompensatedAt = &now
+	execution.FinishedAt = now
+	status := execution.Status
+	compensated := LifecycleEvent{Status: status, Error: execution.CompensateError}
+	execution.mu.Unlock()
+
+	// Persist final compensation state
+	o.persistExecution(ctx, execution)
+
+	o.logger.Info("saga compensation completed",
+		"saga_id", execution.ID,
+		"saga_name", saga.Name,
+		"status", status,
+	)
+
+	o.publish(ctx, EventCompensated, execution, compensated)
+
+	if saga.OnCompensate != nil {
+		saga.OnCompensate(ctx, execution.Clone())
+	}
+}
+
+// compensateStep runs one step's compensation with the step's output and
+// records the outcome on stepExec.
+func (o *Orchestrator) compensateStep(
+	ctx context.Context,
+	execution *Execution,
+	step *Step,
+	stepExec *StepExecution,
+) error {
+	o.logger.Debug("compensating saga step",
+		"saga_id", execution.ID,
+		"step", step.Name,
+	)
+
+	_, compErr := step.Compensation(withIdempotencyKey(ctx, stepExec.IdempotencyKey), stepExec.Output)
+
+	execution.mu.Lock()
+	if compErr != nil {
+		stepExec.CompensationStatus = StatusFailed
+		stepExec.CompensationError = compErr.Error()
+	} else {
+		stepExec.CompensationStatus = StatusCompensated
+		stepExec.CompensationError = ""
+	}
+	execution.mu.Unlock()
+
+	if compErr != nil {
+		o.logger.Error("saga compensation failed",
 			"saga_id", execution.ID,
 			"step", step.Name,
+			"error", compErr,
 		)
+	}
+	return compErr
+}
+
+// RecompensateFailed re-runs the compensations that failed during an
+// earlier compensation of the execution, in reverse step order. Steps
+// whose compensation succeeded are not run again.
+//
+// It is intended for sagas left in StatusFailed with CompensateError set,
+// once the cause of the failures has been fixed. If every retried
+// compensation succeeds, the execution becomes StatusCompensated and
+// CompensateError is cleared; otherwise it stays StatusFailed and the
+// remaining failures are returned and recorded in CompensateError.
+// Unlike Compensate, RecompensateFailed runs synchronously.
+func (o *Orchestrator) RecompensateFailed(ctx context.Context, executionID string) error {
+	execution, err := o.getExecution(ctx, executionID)
+	if err != nil {
+		return err
+	}
+	if execution == nil {
+		return fmt.Errorf("execution %q not found", executionID)
+	}
 
-		// Run compensation with the step's output
-		_, compErr := step.Compensation(ctx, stepExec.Output)
-		if compErr != nil {
+	o.mu.RLock()
+	saga := o.sagas[execution.SagaName]
+	o.mu.RUnlock()
+	if saga == nil {
+		return fmt.Errorf("saga %q not found", execution.SagaName)
+	}
+
+	execution.mu.Lock()
+	if execution.Status != StatusFailed || execution.CompensateError == "" {
+		execution.mu.Unlock()
+		return errors.New("saga has no failed compensations")
+	}
+	execution.Status = StatusCompensating
+	execution.mu.Unlock()
+
+	o.persistExecution(ctx, execution)
+
+	o.logger.Info("retrying failed saga compensations",
+		"saga_id", execution.ID,
+		"saga_name", saga.Name,
+	)
+
+	var compensateErrors []string
+	for i := len(execution.Steps) - 1; i >= 0; i-- {
+		stepExec := &execution.Steps[i]
+		if stepExec.CompensationStatus != StatusFailed || i >= len(saga.Steps) {
+			continue
+		}
+		step := &saga.Steps[i]
+		if compErr := o.compensateStep(ctx, execution, step, stepExec); compErr != nil {
 			compensateErrors = append(compensateErrors,
 				fmt.Sprintf("%s: %s", step.Name, compErr.Error()))
-			o.logger.Error("saga compensation failed",
-				"saga_id", execution.ID,
-				"step", step.Name,
-				"error", compErr,
-			)
 		}
 	}
 
@@ -476,23 +875,134 @@ func (o *Orchestrator) compensateFrom(
 		execution.CompensateError = fmt.Sprintf("compensation errors: %v", compensateErrors)
 	} else {
 		execution.Status = StatusCompensated
+		execution.CompensateError = ""
 	}
 	execution.CompensatedAt = &now
 	execution.FinishedAt = now
+	status := execution.Status
+	compensated := LifecycleEvent{Status: status, Error: execution.CompensateError}
 	execution.mu.Unlock()
 
-	// Persist final compensation state
 	o.persistExecution(ctx, execution)
 
-	o.logger.Info("saga compensation completed",
+	o.logger.Info("saga recompensation completed",
 		"saga_id", execution.ID,
 		"saga_name", saga.Name,
-		"status", execution.Status,
+		"status", status,
 	)
 
+	o.publish(ctx, EventCompensated, execution, compensated)
+
 	if saga.OnCompensate != nil {
 		saga.OnCompensate(ctx, execution.Clone())
 	}
+	if len(compensateErrors) > 0 {
+		return fmt.Errorf("compensation errors: %v", compensateErrors)
+	}
+	return nil
+}
+
+// Recover resumes the executions that were in flight when the process
+// stopped, as recorded in the store. Call it once at startup, after
+// registering the saga definitions and before starting new sagas.
+//
+// A StatusRunning execution resumes at its first step that did not
+// complete; a step that was running is executed again, so step handlers
+// should be idempotent. If that step had already failed, compensation
+// starts instead. A StatusCompensating execution finishes its
+// compensation, skipping steps that were already compensated. Recovered
+// executions run asynchronously, as with Start.
+//
+// Step inputs and outputs are read back from the store, so with a
+// serializing store such as SQLiteStore, handlers of recovered executions
+// receive JSON-decoded values.
+//
+// Recover is a no-op without a store. Executions of sagas that are not
+// registered are skipped and reported in the returned error.
+func (o *Orchestrator) Recover(ctx context.Context) error {
+	if o.store == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, status := range []Status{StatusRunning, StatusCompensating} {
+		executions, err := o.store.List(ctx, &ListFilter{Status: status})
+		if err != nil {
+			return fmt.Errorf("list %s executions: %w", status, err)
+		}
+
+		for _, execution := range executions {
+			o.mu.RLock()
+			saga := o.sagas[execution.SagaName]
+			o.mu.RUnlock()
+			if saga == nil || len(saga.Steps) != len(execution.Steps) {
+				errs = append(errs, fmt.Errorf("execution %q: saga %q not registered or changed", execution.ID, execution.SagaName))
+				continue
+			}
+
+			o.logger.Info("recovering saga execution",
+				"saga_id", execution.ID,
+				"saga_name", saga.Name,
+				"status", status,
+			)
+			o.resume(ctx, saga, execution)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// resume continues a recovered execution from its persisted state.
+func (o *Orchestrator) resume(ctx context.Context, saga *Definition, execution *Execution) {
+	if execution.Status == StatusCompensating {
+		lastCompleted := -1
+		for i := range execution.Steps {
+			if execution.Steps[i].Status == StatusCompleted {
+				lastCompleted = i
+			}
+		}
+		go o.compensateFrom(ctx, saga, execution, lastCompleted, errors.New(execution.Error))
+		return
+	}
+
+	// Resume at the first step, or parallel group, that did not finish,
+	// with the output of the last one that produced one
+	input := execution.Input
+	start := 0
+	for start < len(execution.Steps) {
+		end := saga.groupEnd(start)
+		outputs := make([]any, end-start+1)
+		finished := true
+		for i := start; i <= end; i++ {
+			stepExec := &execution.Steps[i]
+			switch {
+			case stepExec.Status == StatusCompleted && stepExec.Error == "":
+				outputs[i-start] = stepExec.Output
+			case stepExec.Status != StatusCompleted && stepExec.Status != StatusSkipped:
+				finished = false
+			}
+		}
+		if !finished {
+			break
+		}
+
+		if end > start {
+			input = outputs
+		} else if execution.Steps[start].Status == StatusCompleted && execution.Steps[start].Error == "" {
+			input = outputs[0]
+		}
+		start = end + 1
+	}
+
+	if start < len(execution.Steps) {
+		end := saga.groupEnd(start)
+		for i := start; i <= end; i++ {
+			if execution.Steps[i].Status == StatusFailed {
+				go o.compensateFrom(ctx, saga, execution, end, errors.New(execution.Steps[i].Error))
+				return
+			}
+		}
+	}
+	go o.execute(ctx, saga, execution, start, input)
 }
 
 // Compensate triggers compensation for a running or completed saga.
@@ -64,6 +64,17 @@ func TestDefinition_Validate(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "handler is required")
 	})
+
+	t.Run("negative total timeout", func(t *testing.T) {
+		def := &saga.Definition{
+			Name:         "test",
+			TotalTimeout: -time.Second,
+			Steps:        []saga.Step{{Name: "step1", Handler: func(_ context.Context, _ any) (any, error) { return "ok", nil }}},
+		}
+		err := def.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "total timeout")
+	})
 }
 
 func TestOrchestrator_Register(t *testing.T) {
@@ -542,3 +553,664 @@ func TestOrchestrator_OnComplete_Callback(t *testing.T) {
 	assert.Equal(t, saga.StatusCompleted, callbackExec.Status)
 	mu.Unlock()
 }
+
+func TestOrchestrator_RecompensateFailed(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []saga.OrchestratorOption
+	}{
+		{"in-memory", nil},
+		{"store", []saga.OrchestratorOption{saga.WithStore(saga.NewMemoryStore())}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			orch := saga.NewOrchestrator(tc.opts...)
+
+			var compensated []string
+			var downstreamFixed bool
+			var mu sync.Mutex
+
+			def := &saga.Definition{
+				Name: "partial-compensation",
+				Steps: []saga.Step{
+					{
+						Name:    "reserve",
+						Handler: func(_ context.Context, _ any) (any, error) { return "r1", nil },
+						Compensation: func(_ context.Context, _ any) (any, error) {
+							mu.Lock()
+							defer mu.Unlock()
+							if !downstreamFixed {
+								return nil, errors.New("inventory service down")
+							}
+							compensated = append(compensated, "reserve")
+							return nil, nil
+						},
+					},
+					{
+						Name:    "charge",
+						Handler: func(_ context.Context, _ any) (any, error) { return "c1", nil },
+						Compensation: func(_ context.Context, _ any) (any, error) {
+							mu.Lock()
+							defer mu.Unlock()
+							compensated = append(compensated, "charge")
+							return nil, nil
+						},
+					},
+					{
+						Name:    "ship",
+						Handler: func(_ context.Context, _ any) (any, error) { return nil, errors.New("no carrier") },
+					},
+				},
+			}
+			require.NoError(t, orch.Register(def))
+
+			ctx := context.Background()
+			execution, err := orch.Start(ctx, "partial-compensation", nil)
+			require.NoError(t, err)
+			time.Sleep(200 * time.Millisecond)
+
+			exec := orch.Get(execution.ID)
+			require.NotNil(t, exec)
+			assert.Equal(t, saga.StatusFailed, exec.Status)
+			assert.Contains(t, exec.CompensateError, "inventory service down")
+			assert.Equal(t, saga.StatusFailed, exec.Steps[0].CompensationStatus)
+			assert.Equal(t, "inventory service down", exec.Steps[0].CompensationError)
+			assert.Equal(t, saga.StatusCompensated, exec.Steps[1].CompensationStatus)
+			assert.Empty(t, exec.Steps[2].CompensationStatus)
+
+			// Still failing: nothing changes except the error is refreshed
+			require.Error(t, orch.RecompensateFailed(ctx, execution.ID))
+			assert.Equal(t, saga.StatusFailed, orch.Get(execution.ID).Status)
+
+			mu.Lock()
+			downstreamFixed = true
+			mu.Unlock()
+
+			require.NoError(t, orch.RecompensateFailed(ctx, execution.ID))
+
+			exec = orch.Get(execution.ID)
+			assert.Equal(t, saga.StatusCompensated, exec.Status)
+			assert.Empty(t, exec.CompensateError)
+			assert.Equal(t, saga.StatusCompensated, exec.Steps[0].CompensationStatus)
+			assert.Empty(t, exec.Steps[0].CompensationError)
+
+			mu.Lock()
+			assert.Equal(t, []string{"charge", "reserve"}, compensated, "succeeded compensations must not re-run")
+			mu.Unlock()
+
+			assert.Error(t, orch.RecompensateFailed(ctx, execution.ID), "nothing left to recompensate")
+		})
+	}
+}
+
+func TestOrchestrator_RecompensateFailed_NotFound(t *testing.T) {
+	orch := saga.NewOrchestrator()
+
+	assert.Error(t, orch.RecompensateFailed(context.Background(), "missing"))
+}
+
+func TestOrchestrator_Start_RetrySucceeds(t *testing.T) {
+	orch := saga.NewOrchestrator()
+
+	var compensatedSteps []string
+	var attempts int
+	var mu sync.Mutex
+
+	def := &saga.Definition{
+		Name:    "retrying-saga",
+		Timeout: 5 * time.Second,
+		Steps: []saga.Step{
+			{
+				Name:    "step1",
+				Handler: func(_ context.Context, _ any) (any, error) { return "result1", nil },
+				Compensation: func(_ context.Context, _ any) (any, error) {
+					mu.Lock()
+					compensatedSteps = append(compensatedSteps, "step1")
+					mu.Unlock()
+					return nil, nil
+				},
+			},
+			{
+				Name: "step2-flaky",
+				Handler: func(_ context.Context, _ any) (any, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					attempts++
+					if attempts == 1 {
+						return nil, errors.New("temporary failure")
+					}
+					return "result2", nil
+				},
+				RetryPolicy: &saga.RetryPolicy{
+					MaxAttempts: 3,
+					InitialWait: 10 * time.Millisecond,
+					MaxWait:     50 * time.Millisecond,
+					Multiplier:  2.0,
+				},
+			},
+		},
+	}
+	require.NoError(t, orch.Register(def))
+
+	execution, err := orch.Start(context.Background(), "retrying-saga", nil)
+	require.NoError(t, err)
+
+	time.Sleep(200 * time.Millisecond)
+
+	exec := orch.Get(execution.ID)
+	require.NotNil(t, exec)
+	assert.Equal(t, saga.StatusCompleted, exec.Status)
+	assert.Equal(t, "result2", exec.Output)
+	assert.Equal(t, 1, exec.Steps[1].Retries)
+	assert.Equal(t, 0, exec.Steps[0].Retries)
+
+	mu.Lock()
+	assert.Equal(t, 2, attempts)
+	assert.Empty(t, compensatedSteps)
+	mu.Unlock()
+}
+
+func TestOrchestrator_Start_RetriesExhausted(t *testing.T) {
+	orch := saga.NewOrchestrator(saga.WithRetryPolicy(&saga.RetryPolicy{
+		MaxAttempts: 3,
+		InitialWait: time.Millisecond,
+		Multiplier:  2.0,
+	}))
+
+	var attempts int
+	var compensated bool
+	var mu sync.Mutex
+
+	def := &saga.Definition{
+		Name: "exhausted-saga",
+		Steps: []saga.Step{
+			{
+				Name:    "step1",
+				Handler: func(_ context.Context, _ any) (any, error) { return "result1", nil },
+				Compensation: func(_ context.Context, _ any) (any, error) {
+					mu.Lock()
+					compensated = true
+					mu.Unlock()
+					return nil, nil
+				},
+			},
+			{
+				Name: "step2-fails",
+				Handler: func(_ context.Context, _ any) (any, error) {
+					mu.Lock()
+					attempts++
+					mu.Unlock()
+					return nil, errors.New("always fails")
+				},
+			},
+		},
+	}
+	require.NoError(t, orch.Register(def))
+
+	execution, err := orch.Start(context.Background(), "exhausted-saga", nil)
+	require.NoError(t, err)
+
+	time.Sleep(200 * time.Millisecond)
+
+	exec := orch.Get(execution.ID)
+	require.NotNil(t, exec)
+	assert.Equal(t, saga.StatusCompensated, exec.Status)
+	assert.Equal(t, saga.StatusFailed, exec.Steps[1].Status)
+	assert.Equal(t, 2, exec.Steps[1].Retries)
+
+	mu.Lock()
+	assert.Equal(t, 3, attempts)
+	assert.True(t, compensated)
+	mu.Unlock()
+}
+
+func TestOrchestrator_Start_RetryStopsOnCancel(t *testing.T) {
+	orch := saga.NewOrchestrator()
+
+	var attempts int
+	var mu sync.Mutex
+
+	def := &saga.Definition{
+		Name: "cancelled-saga",
+		Steps: []saga.Step{
+			{
+				Name: "step1",
+				Handler: func(_ context.Context, _ any) (any, error) {
+					mu.Lock()
+					attempts++
+					mu.Unlock()
+					return nil, errors.New("fails")
+				},
+				RetryPolicy: &saga.RetryPolicy{MaxAttempts: 5, InitialWait: time.Hour},
+			},
+		},
+	}
+	require.NoError(t, orch.Register(def))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	execution, err := orch.Start(ctx, "cancelled-saga", nil)
+	require.NoError(t, err)
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+
+	exec := orch.Get(execution.ID)
+	require.NotNil(t, exec)
+	assert.Equal(t, saga.StatusCompensated, exec.Status)
+	assert.Contains(t, exec.Steps[0].Error, "context canceled")
+
+	mu.Lock()
+	assert.Equal(t, 1, attempts)
+	mu.Unlock()
+}
+
+func TestOrchestrator_Start_TotalTimeout(t *testing.T) {
+	orch := saga.NewOrchestrator()
+
+	var compensatedSteps, executedSteps []string
+	var mu sync.Mutex
+	record := func(list *[]string, name string) {
+		mu.Lock()
+		*list = append(*list, name)
+		mu.Unlock()
+	}
+
+	def := &saga.Definition{
+		Name:         "slow-saga",
+		Timeout:      5 * time.Second, // Step timeout nests inside the total timeout
+		TotalTimeout: 50 * time.Millisecond,
+		Steps: []saga.Step{
+			{
+				Name: "step1",
+				Handler: func(_ context.Context, _ any) (any, error) {
+					record(&executedSteps, "step1")
+					return "result1", nil
+				},
+				Compensation: func(ctx context.Context, _ any) (any, error) {
+					if ctx.Err() != nil {
+						return nil, ctx.Err()
+					}
+					record(&compensatedSteps, "step1")
+					return nil, nil
+				},
+			},
+			{
+				Name: "step2-slow",
+				Handler: func(ctx context.Context, _ any) (any, error) {
+					record(&executedSteps, "step2")
+					<-ctx.Done()
+					return nil, ctx.Err()
+				},
+			},
+			{
+				Name: "step3",
+				Handler: func(_ context.Context, _ any) (any, error) {
+					record(&executedSteps, "step3")
+					return nil, nil
+				},
+			},
+		},
+	}
+	require.NoError(t, orch.Register(def))
+
+	start := time.Now()
+	execution, err := orch.Start(context.Background(), "slow-saga", nil)
+	require.NoError(t, err)
+
+	time.Sleep(200 * time.Millisecond)
+
+	exec := orch.Get(execution.ID)
+	require.NotNil(t, exec)
+	assert.Equal(t, saga.StatusCompensated, exec.Status)
+	assert.Contains(t, exec.Error, saga.ErrTotalTimeout.Error())
+	assert.Contains(t, exec.Steps[1].Error, saga.ErrTotalTimeout.Error())
+	assert.Less(t, exec.Steps[1].FinishedAt.Sub(start), time.Second)
+
+	mu.Lock()
+	assert.Equal(t, []string{"step1", "step2"}, executedSteps)
+	assert.Equal(t, []string{"step1"}, compensatedSteps)
+	mu.Unlock()
+}
+
+func TestOrchestrator_Start_StepFailureIsNotTotalTimeout(t *testing.T) {
+	orch := saga.NewOrchestrator()
+
+	def := &saga.Definition{
+		Name:         "failing-within-deadline",
+		TotalTimeout: time.Second,
+		Steps: []saga.Step{
+			{
+				Name:    "step1",
+				Handler: func(_ context.Context, _ any) (any, error) { return nil, errors.New("boom") },
+			},
+		},
+	}
+	require.NoError(t, orch.Register(def))
+
+	execution, err := orch.Start(context.Background(), "failing-within-deadline", nil)
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	exec := orch.Get(execution.ID)
+	require.NotNil(t, exec)
+	assert.Equal(t, saga.StatusCompensated, exec.Status)
+	assert.Equal(t, "boom", exec.Error)
+}
+
+func TestOrchestrator_Start_SkippedStep(t *testing.T) {
+	orch := saga.NewOrchestrator()
+
+	var executedSteps []string
+	var mu sync.Mutex
+	record := func(name string) {
+		mu.Lock()
+		executedSteps = append(executedSteps, name)
+		mu.Unlock()
+	}
+
+	def := &saga.Definition{
+		Name: "gift-saga",
+		Steps: []saga.Step{
+			{
+				Name: "create-order",
+				Handler: func(_ context.Context, input any) (any, error) {
+					record("create-order")
+					return input, nil
+				},
+			},
+			{
+				Name: "gift-wrap",
+				Condition: func(_ context.Context, input any) bool {
+					return input == "gift"
+				},
+				Handler: func(_ context.Context, _ any) (any, error) {
+					record("gift-wrap")
+					return "wrapped", nil
+				},
+			},
+			{
+				Name: "ship",
+				Handler: func(_ context.Context, input any) (any, error) {
+					record("ship")
+					return input, nil
+				},
+			},
+		},
+	}
+	require.NoError(t, orch.Register(def))
+
+	execution, err := orch.Start(context.Background(), "gift-saga", "plain")
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	exec := orch.Get(execution.ID)
+	require.NotNil(t, exec)
+	assert.Equal(t, saga.StatusCompleted, exec.Status)
+	assert.Equal(t, saga.StatusSkipped, exec.Steps[1].Status)
+	assert.Equal(t, "plain", exec.Output)
+
+	mu.Lock()
+	assert.Equal(t, []string{"create-order", "ship"}, executedSteps)
+	mu.Unlock()
+}
+
+func TestOrchestrator_Start_SkippedStepNotCompensated(t *testing.T) {
+	orch := saga.NewOrchestrator()
+
+	var executedSteps, compensatedSteps []string
+	var mu sync.Mutex
+	record := func(list *[]string, name string) {
+		mu.Lock()
+		*list = append(*list, name)
+		mu.Unlock()
+	}
+
+	def := &saga.Definition{
+		Name: "skip-compensation-saga",
+		Steps: []saga.Step{
+			{
+				Name: "step1",
+				Handler: func(_ context.Context, _ any) (any, error) {
+					record(&executedSteps, "step1")
+					return "result1", nil
+				},
+				Compensation: func(_ context.Context, _ any) (any, error) {
+					record(&compensatedSteps, "step1")
+					return nil, nil
+				},
+			},
+			{
+				Name:      "step2-skipped",
+				Condition: func(_ context.Context, _ any) bool { return false },
+				Handler: func(_ context.Context, _ any) (any, error) {
+					record(&executedSteps, "step2")
+					return "result2", nil
+				},
+				Compensation: func(_ context.Context, _ any) (any, error) {
+					record(&compensatedSteps, "step2")
+					return nil, nil
+				},
+			},
+			{
+				Name: "step3-fails",
+				Handler: func(_ context.Context, input any) (any, error) {
+					record(&executedSteps, "step3")
+					assert.Equal(t, "result1", input)
+					return nil, errors.New("step3 failed")
+				},
+			},
+		},
+	}
+	require.NoError(t, orch.Register(def))
+
+	execution, err := orch.Start(context.Background(), "skip-compensation-saga", nil)
+	require.NoError(t, err)
+
+	time.Sleep(200 * time.Millisecond)
+
+	exec := orch.Get(execution.ID)
+	require.NotNil(t, exec)
+	assert.Equal(t, saga.StatusCompensated, exec.Status)
+	assert.Equal(t, saga.StatusSkipped, exec.Steps[1].Status)
+	assert.Empty(t, exec.Steps[1].CompensationStatus)
+
+	mu.Lock()
+	assert.Equal(t, []string{"step1", "step3"}, executedSteps)
+	assert.Equal(t, []string{"step1"}, compensatedSteps)
+	mu.Unlock()
+}
+
+func TestOrchestrator_Start_IdempotencyKey(t *testing.T) {
+	orch := saga.NewOrchestrator()
+
+	var handlerKeys, compensationKeys []string
+	var mu sync.Mutex
+	record := func(ctx context.Context, keys *[]string) {
+		mu.Lock()
+		*keys = append(*keys, saga.IdempotencyKeyFromContext(ctx))
+		mu.Unlock()
+	}
+
+	def := &saga.Definition{
+		Name:    "idempotent-saga",
+		Timeout: 5 * time.Second,
+		Steps: []saga.Step{
+			{
+				Name: "reserve",
+				Handler: func(ctx context.Context, input any) (any, error) {
+					record(ctx, &handlerKeys)
+					return input, nil
+				},
+				Compensation: func(ctx context.Context, _ any) (any, error) {
+					record(ctx, &compensationKeys)
+					return nil, nil
+				},
+				IdempotencyKey: func(input any) string { return "reserve:" + input.(string) },
+			},
+			{
+				Name: "charge",
+				Handler: func(ctx context.Context, _ any) (any, error) {
+					record(ctx, &handlerKeys)
+					return nil, errors.New("card declined")
+				},
+				RetryPolicy: &saga.RetryPolicy{MaxAttempts: 2, InitialWait: time.Millisecond},
+			},
+		},
+	}
+	require.NoError(t, orch.Register(def))
+
+	execution, err := orch.Start(context.Background(), "idempotent-saga", "order-1")
+	require.NoError(t, err)
+
+	time.Sleep(200 * time.Millisecond)
+
+	exec := orch.Get(execution.ID)
+	require.NotNil(t, exec)
+	assert.Equal(t, saga.StatusCompensated, exec.Status)
+	assert.Equal(t, "reserve:order-1", exec.Steps[0].IdempotencyKey)
+	assert.Equal(t, execution.ID+"/charge", exec.Steps[1].IdempotencyKey)
+
+	mu.Lock()
+	defer mu.Unlock()
+	// Retries of a step see the same key, and so does its compensation
+	assert.Equal(t, []string{"reserve:order-1", execution.ID + "/charge", execution.ID + "/charge"}, handlerKeys)
+	assert.Equal(t, []string{"reserve:order-1"}, compensationKeys)
+	assert.Empty(t, saga.IdempotencyKeyFromContext(context.Background()))
+}
+
+func TestOrchestrator_Start_ParallelGroup(t *testing.T) {
+	orch := saga.NewOrchestrator()
+
+	// Both reservations must be running at once to get past the barrier
+	var barrier sync.WaitGroup
+	barrier.Add(2)
+	reserve := func(name string) saga.StepHandler {
+		return func(_ context.Context, input any) (any, error) {
+			barrier.Done()
+			barrier.Wait()
+			return name + ":" + input.(string), nil
+		}
+	}
+
+	var finalInput any
+	def := &saga.Definition{
+		Name:    "parallel-saga",
+		Timeout: time.Second,
+		Steps: []saga.Step{
+			{Name: "inventory", Handler: reserve("inventory"), ParallelGroup: "reserve"},
+			{Name: "shipping", Handler: reserve("shipping"), ParallelGroup: "reserve"},
+			{
+				Name: "gift-wrap",
+				Handler: func(_ context.Context, _ any) (any, error) {
+					return "wrapped", nil
+				},
+				ParallelGroup: "reserve",
+				Condition:     func(_ context.Context, _ any) bool { return false },
+			},
+			{
+				Name: "confirm",
+				Handler: func(_ context.Context, input any) (any, error) {
+					finalInput = input
+					return "confirmed", nil
+				},
+			},
+		},
+	}
+	require.NoError(t, orch.Register(def))
+
+	execution, err := orch.Start(context.Background(), "parallel-saga", "order-1")
+	require.NoError(t, err)
+
+	time.Sleep(200 * time.Millisecond)
+
+	exec := orch.Get(execution.ID)
+	require.NotNil(t, exec)
+	assert.Equal(t, saga.StatusCompleted, exec.Status)
+	assert.Equal(t, []any{"inventory:order-1", "shipping:order-1", nil}, finalInput)
+	for _, i := range []int{0, 1} {
+		assert.Equal(t, saga.StatusCompleted, exec.Steps[i].Status)
+		assert.Equal(t, "order-1", exec.Steps[i].Input)
+		assert.False(t, exec.Steps[i].StartedAt.IsZero())
+	}
+	assert.Equal(t, saga.StatusSkipped, exec.Steps[2].Status)
+}
+
+func TestOrchestrator_Start_ParallelGroupFailureCompensatesGroup(t *testing.T) {
+	orch := saga.NewOrchestrator()
+
+	var compensated []string
+	var mu sync.Mutex
+	compensation := func(name string) saga.StepHandler {
+		return func(_ context.Context, _ any) (any, error) {
+			mu.Lock()
+			compensated = append(compensated, name)
+			mu.Unlock()
+			return nil, nil
+		}
+	}
+	succeed := func(_ context.Context, input any) (any, error) { return input, nil }
+
+	def := &saga.Definition{
+		Name:    "partial-group-saga",
+		Timeout: time.Second,
+		Steps: []saga.Step{
+			{Name: "create-order", Handler: succeed, Compensation: compensation("create-order")},
+			{Name: "inventory", Handler: succeed, Compensation: compensation("inventory"), ParallelGroup: "reserve"},
+			{
+				Name: "shipping",
+				Handler: func(_ context.Context, _ any) (any, error) {
+					return nil, errors.New("no carrier")
+				},
+				Compensation:  compensation("shipping"),
+				ParallelGroup: "reserve",
+			},
+			{
+				Name: "payment",
+				Handler: func(_ context.Context, input any) (any, error) {
+					time.Sleep(20 * time.Millisecond) // Finishes after shipping failed
+					return input, nil
+				},
+				Compensation:  compensation("payment"),
+				ParallelGroup: "reserve",
+			},
+			{Name: "confirm", Handler: succeed, Compensation: compensation("confirm")},
+		},
+	}
+	require.NoError(t, orch.Register(def))
+
+	execution, err := orch.Start(context.Background(), "partial-group-saga", "order-1")
+	require.NoError(t, err)
+
+	time.Sleep(200 * time.Millisecond)
+
+	exec := orch.Get(execution.ID)
+	require.NotNil(t, exec)
+	assert.Equal(t, saga.StatusCompensated, exec.Status)
+	assert.Contains(t, exec.Error, "no carrier")
+	assert.Equal(t, saga.StatusFailed, exec.Steps[2].Status)
+	assert.Equal(t, saga.StatusPending, exec.Steps[4].Status)
+
+	mu.Lock()
+	defer mu.Unlock()
+	// Every member that succeeded is compensated, in reverse step order,
+	// before the steps that ran before the group
+	assert.Equal(t, []string{"payment", "inventory", "create-order"}, compensated)
+}
+
+func TestDefinition_Validate_ParallelGroup(t *testing.T) {
+	handler := func(_ context.Context, _ any) (any, error) { return nil, nil }
+	def := &saga.Definition{
+		Name: "split-group",
+		Steps: []saga.Step{
+			{Name: "a", Handler: handler, ParallelGroup: "g"},
+			{Name: "b", Handler: handler},
+			{Name: "c", Handler: handler, ParallelGroup: "g"},
+		},
+	}
+	require.ErrorContains(t, def.Validate(), `parallel group "g" must be consecutive`)
+
+	def.Steps[1].ParallelGroup = "g"
+	require.NoError(t, def.Validate())
+}
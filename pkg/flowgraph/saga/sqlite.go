@@ -0,0 +1,287 @@
+package saga
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver
+)
+
+// ErrStoreClosed is returned when using a store after Close.
+var ErrStoreClosed = errors.New("saga store is closed")
+
+// SQLiteStore persists saga executions to SQLite, so in-flight sagas
+// survive a restart (see Orchestrator.Recover).
+// It is suitable for single-process production use.
+//
+// Executions are stored as JSON, so step inputs and outputs read back from
+// the store are JSON-decoded values (map[string]any, float64, and so on)
+// rather than the original Go types.
+type SQLiteStore struct {
+	db     *sql.DB
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewSQLiteStore creates a new SQLite saga store.
+// The path should be a file path (e.g., "./sagas.db") or ":memory:" for testing.
+//
+// The database file is created with restrictive permissions (0600) since
+// saga inputs and outputs may contain sensitive data.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	// Create file with restrictive permissions BEFORE sql.Open touches it.
+	if path != ":memory:" {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			f, createErr := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+			if createErr == nil {
+				if closeErr := f.Close(); closeErr != nil {
+					slog.Warn("failed to close saga store file after creation",
+						slog.String("path", path),
+						slog.String("error", closeErr.Error()))
+				}
+			}
+			// Ignore createErr - file might have been created between Stat and OpenFile (TOCTOU)
+		}
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	if path == ":memory:" {
+		// Each connection to :memory: is a separate database
+		db.SetMaxOpenConns(1)
+	}
+
+	// Enable WAL mode for better concurrent read performance
+	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("enable WAL mode: %w", err)
+	}
+
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS saga_executions (
+			id TEXT PRIMARY KEY,
+			saga_name TEXT NOT NULL,
+			status TEXT NOT NULL,
+			started_at TEXT NOT NULL,
+			data BLOB NOT NULL
+		)
+	`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create table: %w", err)
+	}
+
+	if _, err := db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_saga_executions_status
+		ON saga_executions(status)
+	`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create index: %w", err)
+	}
+
+	// Ensure permissions are correct for existing files
+	if path != ":memory:" {
+		if err := os.Chmod(path, 0600); err != nil {
+			slog.Warn("failed to set restrictive permissions on saga store file",
+				slog.String("path", path),
+				slog.String("error", err.Error()),
+				slog.String("security_note", "saga data may be readable by other users"))
+		}
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// Create persists a new execution.
+func (s *SQLiteStore) Create(ctx context.Context, execution *Execution) error {
+	if execution.ID == "" {
+		return fmt.Errorf("execution ID is required")
+	}
+	snapshot := execution.Clone()
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("marshal execution: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO saga_executions (id, saga_name, status, started_at, data)
+		VALUES (?, ?, ?, ?, ?)
+	`, snapshot.ID, snapshot.SagaName, string(snapshot.Status),
+		snapshot.StartedAt.UTC().Format(time.RFC3339Nano), data)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint") {
+			return fmt.Errorf("execution %q already exists", snapshot.ID)
+		}
+		return fmt.Errorf("create execution: %w", err)
+	}
+	return nil
+}
+
+// Update persists changes to an existing execution.
+func (s *SQLiteStore) Update(ctx context.Context, execution *Execution) error {
+	if execution.ID == "" {
+		return fmt.Errorf("execution ID is required")
+	}
+	snapshot := execution.Clone()
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("marshal execution: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE saga_executions SET status = ?, data = ? WHERE id = ?
+	`, string(snapshot.Status), data, snapshot.ID)
+	if err != nil {
+		return fmt.Errorf("update execution: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrExecutionNotFound
+	}
+	return nil
+}
+
+// Get retrieves an execution by ID.
+func (s *SQLiteStore) Get(ctx context.Context, executionID string) (*Execution, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil, ErrStoreClosed
+	}
+
+	var data []byte
+	err := s.db.QueryRowContext(ctx, `
+		SELECT data FROM saga_executions WHERE id = ?
+	`, executionID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrExecutionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get execution: %w", err)
+	}
+	return decodeExecution(data)
+}
+
+// List returns executions matching the filter, oldest first.
+func (s *SQLiteStore) List(ctx context.Context, filter *ListFilter) ([]*Execution, error) {
+	query := `SELECT data FROM saga_executions WHERE 1 = 1`
+	var args []any
+	if filter != nil {
+		if filter.SagaName != "" {
+			query += ` AND saga_name = ?`
+			args = append(args, filter.SagaName)
+		}
+		if filter.Status != "" {
+			query += ` AND status = ?`
+			args = append(args, string(filter.Status))
+		}
+	}
+	query += ` ORDER BY started_at, id`
+	if filter != nil && (filter.Limit > 0 || filter.Offset > 0) {
+		limit := filter.Limit
+		if limit <= 0 {
+			limit = -1 // No limit
+		}
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, filter.Offset)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil, ErrStoreClosed
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list executions: %w", err)
+	}
+	defer rows.Close()
+
+	result := []*Execution{}
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("scan execution: %w", err)
+		}
+		exec, err := decodeExecution(data)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, exec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate executions: %w", err)
+	}
+	return result, nil
+}
+
+// Delete removes an execution.
+func (s *SQLiteStore) Delete(ctx context.Context, executionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		DELETE FROM saga_executions WHERE id = ?
+	`, executionID)
+	if err != nil {
+		return fmt.Errorf("delete execution: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrExecutionNotFound
+	}
+	return nil
+}
+
+// Close closes the database. Closing twice is safe.
+func (s *SQLiteStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+	return s.db.Close()
+}
+
+// decodeExecution unmarshals a stored execution.
+func decodeExecution(data []byte) (*Execution, error) {
+	var exec Execution
+	if err := json.Unmarshal(data, &exec); err != nil {
+		return nil, fmt.Errorf("unmarshal execution: %w", err)
+	}
+	return &exec, nil
+}
+
+// Compile-time check that SQLiteStore implements Store.
+var _ Store = (*SQLiteStore)(nil)
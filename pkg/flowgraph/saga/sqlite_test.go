@@ -0,0 +1,164 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestSQLiteStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "sagas.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestSQLiteStore_CRUD(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	exec := &Execution{
+		ID:        "test-1",
+		SagaName:  "test-saga",
+		Status:    StatusRunning,
+		Input:     map[string]any{"order": "o-1"},
+		Steps:     []StepExecution{{StepName: "reserve", Status: StatusPending}},
+		StartedAt: time.Now(),
+	}
+
+	if err := store.Create(ctx, exec); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := store.Create(ctx, exec); err == nil {
+		t.Fatal("Expected error for duplicate create")
+	}
+	if err := store.Create(ctx, &Execution{}); err == nil {
+		t.Fatal("Expected error for missing ID")
+	}
+
+	exec.Status = StatusCompleted
+	exec.Steps[0].Status = StatusCompleted
+	exec.Steps[0].Output = "reserved"
+	if err := store.Update(ctx, exec); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := store.Update(ctx, &Execution{ID: "missing"}); !errors.Is(err, ErrExecutionNotFound) {
+		t.Errorf("Expected ErrExecutionNotFound, got %v", err)
+	}
+
+	got, err := store.Get(ctx, "test-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Status != StatusCompleted || got.Steps[0].Output != "reserved" {
+		t.Errorf("Unexpected execution: %+v", got)
+	}
+	if input, ok := got.Input.(map[string]any); !ok || input["order"] != "o-1" {
+		t.Errorf("Expected JSON-decoded input, got %#v", got.Input)
+	}
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, ErrExecutionNotFound) {
+		t.Errorf("Expected ErrExecutionNotFound, got %v", err)
+	}
+
+	if err := store.Delete(ctx, "test-1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := store.Delete(ctx, "test-1"); !errors.Is(err, ErrExecutionNotFound) {
+		t.Errorf("Expected ErrExecutionNotFound, got %v", err)
+	}
+}
+
+func TestSQLiteStore_List(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+	start := time.Now()
+
+	for i, e := range []struct {
+		id     string
+		name   string
+		status Status
+	}{
+		{"exec-1", "saga-a", StatusRunning},
+		{"exec-2", "saga-a", StatusCompleted},
+		{"exec-3", "saga-b", StatusRunning},
+	} {
+		exec := &Execution{ID: e.id, SagaName: e.name, Status: e.status, StartedAt: start.Add(time.Duration(i) * time.Second)}
+		if err := store.Create(ctx, exec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter *ListFilter
+		want   []string
+	}{
+		{"all", nil, []string{"exec-1", "exec-2", "exec-3"}},
+		{"by saga", &ListFilter{SagaName: "saga-a"}, []string{"exec-1", "exec-2"}},
+		{"by status", &ListFilter{Status: StatusRunning}, []string{"exec-1", "exec-3"}},
+		{"limit", &ListFilter{Limit: 2}, []string{"exec-1", "exec-2"}},
+		{"offset", &ListFilter{Offset: 2}, []string{"exec-3"}},
+		{"offset past end", &ListFilter{Offset: 5}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := store.List(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			ids := []string{}
+			for _, exec := range result {
+				ids = append(ids, exec.ID)
+			}
+			if len(ids) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Fatalf("Expected %v, got %v", tt.want, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestSQLiteStore_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sagas.db")
+	ctx := context.Background()
+
+	store1, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store1.Create(ctx, &Execution{ID: "exec-1", SagaName: "s", Status: StatusRunning, StartedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store1.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store1.Close(); err != nil {
+		t.Errorf("Second Close should be safe, got %v", err)
+	}
+	if _, err := store1.Get(ctx, "exec-1"); !errors.Is(err, ErrStoreClosed) {
+		t.Errorf("Expected ErrStoreClosed, got %v", err)
+	}
+
+	store2, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store2.Close()
+
+	exec, err := store2.Get(ctx, "exec-1")
+	if err != nil {
+		t.Fatalf("Expected execution to survive reopen: %v", err)
+	}
+	if exec.Status != StatusRunning {
+		t.Errorf("Expected running, got %s", exec.Status)
+	}
+}
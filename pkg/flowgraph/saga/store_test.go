@@ -2,6 +2,7 @@ package saga
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 )
@@ -287,3 +288,227 @@ func TestOrchestrator_WithStore_Compensation(t *testing.T) {
 		t.Errorf("Expected compensated status in store, got %v", persisted.Status)
 	}
 }
+
+// crashedExecution returns an execution as persisted by a process that
+// stopped while running step1 of a two-step saga.
+func crashedExecution(status Status, step1 Status) *Execution {
+	return &Execution{
+		ID:       "crashed-1",
+		SagaName: "recoverable",
+		Status:   status,
+		Input:    "input",
+		Error:    "process crashed",
+		Steps: []StepExecution{
+			{StepName: "step0", Status: StatusCompleted, Input: "input", Output: "out0"},
+			{StepName: "step1", Status: step1, Input: "out0"},
+		},
+		CurrentStep: 1,
+		StartedAt:   time.Now(),
+	}
+}
+
+type recoverCalls struct {
+	mu       sync.Mutex
+	executed []string
+	step1In  any
+	reverted []string
+}
+
+func (c *recoverCalls) record(list *[]string, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	*list = append(*list, name)
+}
+
+func recoverableSaga(calls *recoverCalls) *Definition {
+	return &Definition{
+		Name: "recoverable",
+		Steps: []Step{
+			{
+				Name: "step0",
+				Handler: func(_ context.Context, _ any) (any, error) {
+					calls.record(&calls.executed, "step0")
+					return "out0", nil
+				},
+				Compensation: func(_ context.Context, _ any) (any, error) {
+					calls.record(&calls.reverted, "step0")
+					return nil, nil
+				},
+			},
+			{
+				Name: "step1",
+				Handler: func(_ context.Context, input any) (any, error) {
+					calls.record(&calls.executed, "step1")
+					calls.mu.Lock()
+					calls.step1In = input
+					calls.mu.Unlock()
+					return "out1", nil
+				},
+			},
+		},
+	}
+}
+
+func waitForStatus(t *testing.T, orch *Orchestrator, id string, want Status) *Execution {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if exec := orch.Get(id); exec != nil && exec.Status == want {
+			return exec
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("execution %s did not reach %s", id, want)
+	return nil
+}
+
+func TestOrchestrator_Recover_ResumesRunning(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+	if err := store.Create(ctx, crashedExecution(StatusRunning, StatusRunning)); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := &recoverCalls{}
+	orch := NewOrchestrator(WithStore(store))
+	orch.MustRegister(recoverableSaga(calls))
+
+	if err := orch.Recover(ctx); err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+
+	exec := waitForStatus(t, orch, "crashed-1", StatusCompleted)
+	if exec.Output != "out1" {
+		t.Errorf("Expected output out1, got %v", exec.Output)
+	}
+
+	calls.mu.Lock()
+	defer calls.mu.Unlock()
+	if len(calls.executed) != 1 || calls.executed[0] != "step1" {
+		t.Errorf("Expected only step1 to run, got %v", calls.executed)
+	}
+	if calls.step1In != "out0" {
+		t.Errorf("Expected step1 to receive step0 output, got %v", calls.step1In)
+	}
+}
+
+func TestOrchestrator_Recover_CompensatesFailedStep(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+	if err := store.Create(ctx, crashedExecution(StatusRunning, StatusFailed)); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := &recoverCalls{}
+	orch := NewOrchestrator(WithStore(store))
+	orch.MustRegister(recoverableSaga(calls))
+
+	if err := orch.Recover(ctx); err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+
+	waitForStatus(t, orch, "crashed-1", StatusCompensated)
+
+	calls.mu.Lock()
+	defer calls.mu.Unlock()
+	if len(calls.executed) != 0 {
+		t.Errorf("Expected no steps to run, got %v", calls.executed)
+	}
+	if len(calls.reverted) != 1 {
+		t.Errorf("Expected step0 to be compensated, got %v", calls.reverted)
+	}
+}
+
+func TestOrchestrator_Recover_FinishesCompensation(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	// Crashed mid-compensation: step0 already compensated, so it must not run again
+	exec := crashedExecution(StatusCompensating, StatusCompleted)
+	exec.Steps[0].CompensationStatus = StatusCompensated
+	if err := store.Create(ctx, exec); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := &recoverCalls{}
+	orch := NewOrchestrator(WithStore(store))
+	orch.MustRegister(recoverableSaga(calls))
+
+	if err := orch.Recover(ctx); err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+
+	waitForStatus(t, orch, "crashed-1", StatusCompensated)
+
+	calls.mu.Lock()
+	defer calls.mu.Unlock()
+	if len(calls.reverted) != 0 {
+		t.Errorf("Expected no compensation to re-run, got %v", calls.reverted)
+	}
+}
+
+func TestOrchestrator_Recover_ResumesParallelGroup(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	// Crashed while step1 of the group {step0, step1} was running
+	exec := crashedExecution(StatusRunning, StatusRunning)
+	exec.Steps[1].Input = "input"
+	exec.Steps = append(exec.Steps, StepExecution{StepName: "step2", Status: StatusPending})
+	if err := store.Create(ctx, exec); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := &recoverCalls{}
+	def := recoverableSaga(calls)
+	def.Steps[0].ParallelGroup = "group"
+	def.Steps[1].ParallelGroup = "group"
+	var step2In any
+	def.Steps = append(def.Steps, Step{
+		Name: "step2",
+		Handler: func(_ context.Context, input any) (any, error) {
+			calls.record(&calls.executed, "step2")
+			calls.mu.Lock()
+			step2In = input
+			calls.mu.Unlock()
+			return "out2", nil
+		},
+	})
+
+	orch := NewOrchestrator(WithStore(store))
+	orch.MustRegister(def)
+
+	if err := orch.Recover(ctx); err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+
+	waitForStatus(t, orch, "crashed-1", StatusCompleted)
+
+	calls.mu.Lock()
+	defer calls.mu.Unlock()
+	if len(calls.executed) != 2 || calls.executed[0] != "step1" || calls.executed[1] != "step2" {
+		t.Errorf("Expected step1 then step2 to run, got %v", calls.executed)
+	}
+	if calls.step1In != "input" {
+		t.Errorf("Expected step1 to receive the group input, got %v", calls.step1In)
+	}
+	if got, ok := step2In.([]any); !ok || len(got) != 2 || got[0] != "out0" || got[1] != "out1" {
+		t.Errorf("Expected step2 to receive the group outputs, got %v", step2In)
+	}
+}
+
+func TestOrchestrator_Recover_UnregisteredSaga(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	if err := store.Create(ctx, crashedExecution(StatusRunning, StatusRunning)); err != nil {
+		t.Fatal(err)
+	}
+
+	orch := NewOrchestrator(WithStore(store))
+	if err := orch.Recover(ctx); err == nil {
+		t.Fatal("Expected error for unregistered saga")
+	}
+	if err := NewOrchestrator().Recover(ctx); err != nil {
+		t.Errorf("Expected no-op without a store, got %v", err)
+	}
+}
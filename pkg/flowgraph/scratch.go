@@ -0,0 +1,101 @@
+package flowgraph
+
+import (
+	"maps"
+	"sync"
+)
+
+// Scratchpad is a concurrency-safe key/value map shared by the branches
+// of one fork/join, returned by Context.BranchScratch.
+//
+// Branch state stays isolated; the scratchpad is the opt-in exception,
+// for branches that need to publish partial results for their siblings
+// or for the join. Each fork/join gets a fresh, empty scratchpad, shared
+// by all of its branches, the BranchHook's OnJoin, and the join node and
+// its router. Nodes after the join, and nodes outside any fork/join, see
+// nil.
+//
+// Concurrency contract:
+//   - Store, Load, Delete, Range, and Snapshot are safe to call from
+//     any branch at any time.
+//   - Branches run concurrently, so a read from a sibling's key sees
+//     whatever that sibling has stored so far, or nothing. Do not rely on
+//     ordering between branches; coordinate through values that tell the
+//     reader whether they are complete.
+//   - By the time OnJoin and the join node run, all branches have
+//     finished, so the scratchpad holds every value they stored.
+//   - Values are shared, not copied. Store values that are immutable or
+//     otherwise safe to read concurrently.
+//
+// A nil Scratchpad is empty and discards writes.
+type Scratchpad struct {
+	mu     sync.RWMutex
+	values map[string]any
+}
+
+// newScratchpad creates an empty scratchpad.
+func newScratchpad() *Scratchpad {
+	return &Scratchpad{values: make(map[string]any)}
+}
+
+// Store sets the value for key.
+func (s *Scratchpad) Store(key string, value any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+// Load returns the value stored for key, if any.
+func (s *Scratchpad) Load(key string) (any, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Delete removes key.
+func (s *Scratchpad) Delete(key string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+// Range calls fn for each key and value until fn returns false. It
+// iterates over a snapshot, so fn may call other Scratchpad methods.
+func (s *Scratchpad) Range(fn func(key string, value any) bool) {
+	for k, v := range s.Snapshot() {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+// Snapshot returns a copy of the current contents.
+func (s *Scratchpad) Snapshot() map[string]any {
+	if s == nil {
+		return map[string]any{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.values)
+}
+
+// withScratchpad returns ctx carrying s as its branch scratchpad.
+// Custom Context implementations cannot carry one and are returned
+// unchanged.
+func withScratchpad(ctx Context, s *Scratchpad) Context {
+	ec, ok := ctx.(*executionContext)
+	if !ok {
+		return ctx
+	}
+	return ec.derive(func(d *executionContext) { d.scratch = s })
+}
@@ -0,0 +1,177 @@
+package flowgraph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scratchGraph builds dispatch -> (workerA, workerB) -> collect -> after -> END
+// with the given worker and collect functions.
+func scratchGraph(workerA, workerB, collect NodeFunc[TestState], after NodeFunc[TestState]) *Graph[TestState] {
+	return NewGraph[TestState]().
+		AddNode("dispatch", passthrough[TestState]).
+		AddNode("workerA", workerA).
+		AddNode("workerB", workerB).
+		AddNode("collect", collect).
+		AddNode("after", after).
+		AddEdge("dispatch", "workerA").
+		AddEdge("dispatch", "workerB").
+		AddEdge("workerA", "collect").
+		AddEdge("workerB", "collect").
+		AddEdge("collect", "after").
+		AddEdge("after", END).
+		SetEntry("dispatch")
+}
+
+// TestBranchScratch_JoinReadsBranchValues tests that the join node sees values stored by both branches.
+func TestBranchScratch_JoinReadsBranchValues(t *testing.T) {
+	var atDispatch, atAfter *Scratchpad
+	var joined map[string]any
+
+	graph := scratchGraph(
+		func(ctx Context, s TestState) (TestState, error) {
+			ctx.BranchScratch().Store("a", 1)
+			return s, nil
+		},
+		func(ctx Context, s TestState) (TestState, error) {
+			ctx.BranchScratch().Store("b", 2)
+			return s, nil
+		},
+		func(ctx Context, s TestState) (TestState, error) {
+			joined = ctx.BranchScratch().Snapshot()
+			return s, nil
+		},
+		func(ctx Context, s TestState) (TestState, error) {
+			atAfter = ctx.BranchScratch()
+			return s, nil
+		},
+	)
+	graph.nodes["dispatch"] = func(ctx Context, s TestState) (TestState, error) {
+		atDispatch = ctx.BranchScratch()
+		return s, nil
+	}
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), TestState{Values: map[string]int{}})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": 1, "b": 2}, joined)
+	assert.Nil(t, atDispatch, "fork node runs before the fork/join")
+	assert.Nil(t, atAfter, "scratchpad is scoped to the join node")
+}
+
+// TestBranchScratch_SiblingRead tests that a branch can read a value published by its sibling.
+func TestBranchScratch_SiblingRead(t *testing.T) {
+	graph := scratchGraph(
+		func(ctx Context, s TestState) (TestState, error) {
+			ctx.BranchScratch().Store("plan", 42)
+			return s, nil
+		},
+		func(ctx Context, s TestState) (TestState, error) {
+			deadline := time.Now().Add(time.Second)
+			for time.Now().Before(deadline) {
+				if v, ok := ctx.BranchScratch().Load("plan"); ok {
+					s.Values["seen_plan"] = v.(int)
+					return s, nil
+				}
+				time.Sleep(time.Millisecond)
+			}
+			return s, nil
+		},
+		passthrough[TestState],
+		passthrough[TestState],
+	)
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), TestState{Values: map[string]int{}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 42, result.Values["workerB_seen_plan"])
+}
+
+// TestBranchScratch_FreshPerFork tests that each fork/join starts with an empty scratchpad.
+func TestBranchScratch_FreshPerFork(t *testing.T) {
+	var sizes []int
+	graph := scratchGraph(
+		func(ctx Context, s TestState) (TestState, error) {
+			ctx.BranchScratch().Store("a", true)
+			return s, nil
+		},
+		passthrough[TestState],
+		func(ctx Context, s TestState) (TestState, error) {
+			sizes = append(sizes, len(ctx.BranchScratch().Snapshot()))
+			return s, nil
+		},
+		passthrough[TestState],
+	)
+	graph.nodes["workerB"] = func(ctx Context, s TestState) (TestState, error) {
+		if _, ok := ctx.BranchScratch().Load("stale"); ok {
+			s.Values["stale"] = 1
+		}
+		ctx.BranchScratch().Store("stale", true)
+		return s, nil
+	}
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		result, err := compiled.Run(testCtx(), TestState{Values: map[string]int{}})
+		require.NoError(t, err)
+		assert.NotContains(t, result.Values, "workerB_stale")
+	}
+	assert.Equal(t, []int{2, 2}, sizes)
+}
+
+// TestBranchScratch_OutsideForkJoin tests that nodes outside a fork/join get a nil, empty scratchpad.
+func TestBranchScratch_OutsideForkJoin(t *testing.T) {
+	graph := NewGraph[Counter]().
+		AddNode("a", func(ctx Context, s Counter) (Counter, error) {
+			scratch := ctx.BranchScratch()
+			assert.Nil(t, scratch)
+			scratch.Store("ignored", 1)
+			_, ok := scratch.Load("ignored")
+			assert.False(t, ok)
+			assert.Empty(t, scratch.Snapshot())
+			return s, nil
+		}).
+		AddEdge("a", END).
+		SetEntry("a")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), Counter{})
+	require.NoError(t, err)
+}
+
+// TestScratchpad_Range tests iteration, early stop, and Delete.
+func TestScratchpad_Range(t *testing.T) {
+	s := newScratchpad()
+	s.Store("a", 1)
+	s.Store("b", 2)
+	s.Store("c", 3)
+	s.Delete("c")
+
+	sum := 0
+	s.Range(func(key string, value any) bool {
+		sum += value.(int)
+		s.Store(key+"-seen", true) // Safe: Range iterates a snapshot
+		return true
+	})
+	assert.Equal(t, 3, sum)
+
+	calls := 0
+	s.Range(func(string, any) bool {
+		calls++
+		return false
+	})
+	assert.Equal(t, 1, calls)
+}
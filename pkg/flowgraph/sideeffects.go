@@ -0,0 +1,124 @@
+package flowgraph
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// SideEffectFunc performs an external side effect, such as writing to a
+// shared resource. It receives the registering node's context when run
+// immediately, or the fork node's context when committed at a join.
+type SideEffectFunc func(ctx Context) error
+
+// CommitSideEffect registers a side effect from inside a node.
+//
+// In a fork branch with ForkJoinConfig.OrderedSideEffects enabled, fn is
+// queued rather than run: branches compute in parallel, and once all of
+// them succeed their queued side effects run one at a time at the join, in
+// sorted branch-ID order (and registration order within a branch). If any
+// branch fails, no queued side effects run. A failed node attempt that is
+// retried discards the side effects it registered.
+//
+// Everywhere else, fn runs immediately and its error is returned.
+//
+// Example:
+//
+//	func writeReport(ctx flowgraph.Context, s State) (State, error) {
+//	    report := render(s)
+//	    err := flowgraph.CommitSideEffect(ctx, func(ctx flowgraph.Context) error {
+//	        return sharedLog.Append(ctx, report)
+//	    })
+//	    return s, err
+//	}
+func CommitSideEffect(ctx Context, fn SideEffectFunc) error {
+	if fn == nil {
+		return nil
+	}
+	if q := sideEffectsFrom(ctx); q != nil {
+		q.add(fn)
+		return nil
+	}
+	return fn(ctx)
+}
+
+// sideEffectsKey is the context key for a branch's side effect queue.
+type sideEffectsKey struct{}
+
+// sideEffectQueue collects one branch's side effects. Methods are safe to
+// call on a nil queue.
+type sideEffectQueue struct {
+	mu      sync.Mutex
+	effects []SideEffectFunc
+}
+
+// add queues fn.
+func (q *sideEffectQueue) add(fn SideEffectFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.effects = append(q.effects, fn)
+}
+
+// len returns the number of queued side effects.
+func (q *sideEffectQueue) len() int {
+	if q == nil {
+		return 0
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.effects)
+}
+
+// truncate discards side effects queued after the first n.
+func (q *sideEffectQueue) truncate(n int) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if n < len(q.effects) {
+		q.effects = q.effects[:n]
+	}
+}
+
+// sideEffectsFrom returns the side effect queue carried by ctx, or nil.
+func sideEffectsFrom(ctx context.Context) *sideEffectQueue {
+	q, _ := ctx.Value(sideEffectsKey{}).(*sideEffectQueue)
+	return q
+}
+
+// withSideEffectQueue returns a branch context that queues side effects in q.
+// Custom Context implementations cannot carry a queue, so their side
+// effects run immediately.
+func withSideEffectQueue(ctx Context, q *sideEffectQueue) Context {
+	ec, ok := ctx.(*executionContext)
+	if !ok {
+		return ctx
+	}
+	return ec.withContext(context.WithValue(ec.Context, sideEffectsKey{}, q))
+}
+
+// commitSideEffects runs the queued side effects of each branch in sorted
+// branch-ID order, stopping at the first error.
+func commitSideEffects(ctx Context, forkNodeID string, queues map[string]*sideEffectQueue) error {
+	branchIDs := make([]string, 0, len(queues))
+	for id := range queues {
+		branchIDs = append(branchIDs, id)
+	}
+	slices.Sort(branchIDs)
+
+	for _, branchID := range branchIDs {
+		q := queues[branchID]
+		for i, fn := range q.effects {
+			if err := fn(ctx); err != nil {
+				return &ForkJoinError{
+					ForkNodeID: forkNodeID,
+					BranchID:   branchID,
+					Err:        fmt.Errorf("commit side effect %d: %w", i+1, err),
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package flowgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sideEffectLog records committed side effects in order.
+type sideEffectLog struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *sideEffectLog) append(entry string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+}
+
+func (l *sideEffectLog) all() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.entries...)
+}
+
+// orderedForkGraph builds start -> {c, a, b} -> collect, where each branch
+// sleeps for its delay and then registers side effects writing to log.
+// Branch "a" sleeps longest so it finishes last.
+func orderedForkGraph(t *testing.T, log *sideEffectLog, cfg ForkJoinConfig, running, maxRunning *int32) *CompiledGraph[TestState] {
+	t.Helper()
+
+	delays := map[string]time.Duration{"a": 60 * time.Millisecond, "b": 30 * time.Millisecond, "c": 0}
+	branch := func(id string) NodeFunc[TestState] {
+		return func(ctx Context, s TestState) (TestState, error) {
+			n := atomic.AddInt32(running, 1)
+			for {
+				peak := atomic.LoadInt32(maxRunning)
+				if n <= peak || atomic.CompareAndSwapInt32(maxRunning, peak, n) {
+					break
+				}
+			}
+			time.Sleep(delays[id])
+			atomic.AddInt32(running, -1)
+
+			for i := 1; i <= 2; i++ {
+				entry := fmt.Sprintf("%s%d", id, i)
+				if err := CommitSideEffect(ctx, func(Context) error {
+					log.append(entry)
+					return nil
+				}); err != nil {
+					return s, err
+				}
+			}
+			return s, nil
+		}
+	}
+
+	graph := NewGraph[TestState]().
+		AddNode("start", passthroughTestState).
+		AddNode("c", branch("c")).
+		AddNode("a", branch("a")).
+		AddNode("b", branch("b")).
+		AddNode("collect", passthroughTestState).
+		AddEdge("start", "c").
+		AddEdge("start", "a").
+		AddEdge("start", "b").
+		AddEdge("c", "collect").
+		AddEdge("a", "collect").
+		AddEdge("b", "collect").
+		AddEdge("collect", END).
+		SetEntry("start").
+		SetForkJoinConfig(cfg)
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+	return compiled
+}
+
+func passthroughTestState(_ Context, s TestState) (TestState, error) {
+	return s, nil
+}
+
+// TestCommitSideEffect_OrderedAtJoin tests that branches compute
+// concurrently but commit side effects in sorted branch-ID order.
+func TestCommitSideEffect_OrderedAtJoin(t *testing.T) {
+	var log sideEffectLog
+	var running, maxRunning int32
+	compiled := orderedForkGraph(t, &log, ForkJoinConfig{OrderedSideEffects: true}, &running, &maxRunning)
+
+	start := time.Now()
+	_, err := compiled.Run(testCtx(), TestState{Values: map[string]int{}})
+	elapsed := time.Since(start)
+	require.NoError(t, err)
+
+	// Branch delays sum to 90ms; parallel compute takes about 60ms
+	assert.Less(t, elapsed, 85*time.Millisecond, "branches should compute concurrently")
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&maxRunning), int32(2))
+
+	assert.Equal(t, []string{"a1", "a2", "b1", "b2", "c1", "c2"}, log.all())
+}
+
+// TestCommitSideEffect_UnorderedRunsImmediately tests that without
+// OrderedSideEffects, side effects run as branches register them.
+func TestCommitSideEffect_UnorderedRunsImmediately(t *testing.T) {
+	var log sideEffectLog
+	var running, maxRunning int32
+	compiled := orderedForkGraph(t, &log, ForkJoinConfig{}, &running, &maxRunning)
+
+	_, err := compiled.Run(testCtx(), TestState{Values: map[string]int{}})
+	require.NoError(t, err)
+
+	// "c" has no delay and "a" the longest, so completion order is c, b, a
+	assert.Equal(t, []string{"c1", "c2", "b1", "b2", "a1", "a2"}, log.all())
+}
+
+// TestCommitSideEffect_OutsideFork tests that side effects outside a fork
+// run immediately and return their error.
+func TestCommitSideEffect_OutsideFork(t *testing.T) {
+	ran := false
+	err := CommitSideEffect(testCtx(), func(Context) error {
+		ran = true
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, ran)
+
+	boom := errors.New("boom")
+	err = CommitSideEffect(NewContext(context.Background()), func(Context) error { return boom })
+	assert.ErrorIs(t, err, boom)
+}
+
+// TestCommitSideEffect_BranchFailureDiscards tests that no queued side
+// effects run if any branch fails.
+func TestCommitSideEffect_BranchFailureDiscards(t *testing.T) {
+	var log sideEffectLog
+	graph := NewGraph[TestState]().
+		AddNode("start", passthroughTestState).
+		AddNode("ok", func(ctx Context, s TestState) (TestState, error) {
+			return s, CommitSideEffect(ctx, func(Context) error {
+				log.append("ok")
+				return nil
+			})
+		}).
+		AddNode("fail", func(ctx Context, s TestState) (TestState, error) {
+			_ = CommitSideEffect(ctx, func(Context) error {
+				log.append("fail")
+				return nil
+			})
+			return s, errors.New("branch failed")
+		}).
+		AddNode("collect", passthroughTestState).
+		AddEdge("start", "ok").
+		AddEdge("start", "fail").
+		AddEdge("ok", "collect").
+		AddEdge("fail", "collect").
+		AddEdge("collect", END).
+		SetEntry("start").
+		SetForkJoinConfig(ForkJoinConfig{OrderedSideEffects: true})
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), TestState{Values: map[string]int{}})
+	require.Error(t, err)
+	assert.Empty(t, log.all())
+}
+
+// TestCommitSideEffect_CommitError tests that a failing side effect fails
+// the fork and stops later commits.
+func TestCommitSideEffect_CommitError(t *testing.T) {
+	var log sideEffectLog
+	boom := errors.New("write failed")
+	effect := func(id string, err error) NodeFunc[TestState] {
+		return func(ctx Context, s TestState) (TestState, error) {
+			return s, CommitSideEffect(ctx, func(Context) error {
+				log.append(id)
+				return err
+			})
+		}
+	}
+
+	graph := NewGraph[TestState]().
+		AddNode("start", passthroughTestState).
+		AddNode("a", effect("a", boom)).
+		AddNode("b", effect("b", nil)).
+		AddNode("collect", passthroughTestState).
+		AddEdge("start", "a").
+		AddEdge("start", "b").
+		AddEdge("a", "collect").
+		AddEdge("b", "collect").
+		AddEdge("collect", END).
+		SetEntry("start").
+		SetForkJoinConfig(ForkJoinConfig{OrderedSideEffects: true})
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), TestState{Values: map[string]int{}})
+	require.ErrorIs(t, err, boom)
+
+	var fjErr *ForkJoinError
+	require.ErrorAs(t, err, &fjErr)
+	assert.Equal(t, "a", fjErr.BranchID)
+	assert.Equal(t, []string{"a"}, log.all())
+}
+
+// TestCommitSideEffect_RetryDiscardsFailedAttempt tests that side effects
+// registered by a failed, retried attempt are not committed.
+func TestCommitSideEffect_RetryDiscardsFailedAttempt(t *testing.T) {
+	var log sideEffectLog
+	graph := NewGraph[TestState]().
+		AddNode("start", passthroughTestState).
+		AddNode("flaky", func(ctx Context, s TestState) (TestState, error) {
+			attempt := ctx.Attempt()
+			_ = CommitSideEffect(ctx, func(Context) error {
+				log.append(fmt.Sprintf("flaky-attempt-%d", attempt))
+				return nil
+			})
+			if attempt == 1 {
+				return s, fgerrors.Transient(errors.New("flaky"), "flaky")
+			}
+			return s, nil
+		}, WithNodeRetry(fastRetry)).
+		AddNode("steady", passthroughTestState).
+		AddNode("collect", passthroughTestState).
+		AddEdge("start", "flaky").
+		AddEdge("start", "steady").
+		AddEdge("flaky", "collect").
+		AddEdge("steady", "collect").
+		AddEdge("collect", END).
+		SetEntry("start").
+		SetForkJoinConfig(ForkJoinConfig{OrderedSideEffects: true})
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), TestState{Values: map[string]int{}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"flaky-attempt-2"}, log.all())
+}
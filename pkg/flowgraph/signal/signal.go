@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 	"sync"
 	"time"
 
@@ -62,6 +63,17 @@ type Signal struct {
 
 	// Error contains error details if processing failed.
 	Error string `json:"error,omitempty"`
+
+	// ExpiresAt is when the signal expires if it is still pending. Expired
+	// signals are never delivered: stores and the Dispatcher mark them
+	// StatusFailed with ErrSignalExpired. Zero means the signal never
+	// expires.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+
+	// Priority orders delivery of pending signals for the same target:
+	// higher priorities are dequeued first, and signals of equal priority
+	// are dequeued in send order. The default is 0.
+	Priority int `json:"priority,omitempty"`
 }
 
 // NewSignal creates a new signal with the given name and target.
@@ -82,6 +94,36 @@ func (s *Signal) WithSender(senderID string) *Signal {
 	return s
 }
 
+// WithPriority sets the delivery priority of the signal.
+//
+// Example:
+//
+//	sig := signal.NewSignal("cancel", runID, nil).WithPriority(10)
+func (s *Signal) WithPriority(priority int) *Signal {
+	s.Priority = priority
+	return s
+}
+
+// WithTTL makes the signal expire d after it was sent, if it has not been
+// delivered by then.
+//
+// Example:
+//
+//	sig := signal.NewSignal("nudge", runID, nil).WithTTL(10 * time.Minute)
+func (s *Signal) WithTTL(d time.Duration) *Signal {
+	sentAt := s.SentAt
+	if sentAt.IsZero() {
+		sentAt = time.Now()
+	}
+	s.ExpiresAt = sentAt.Add(d)
+	return s
+}
+
+// Expired reports whether the signal has an expiry that is not after now.
+func (s *Signal) Expired(now time.Time) bool {
+	return !s.ExpiresAt.IsZero() && !now.Before(s.ExpiresAt)
+}
+
 // Clone creates a deep copy of the signal.
 func (s *Signal) Clone() *Signal {
 	signalCopy := *s
@@ -174,12 +216,16 @@ var ErrSignalNotFound = errors.New("signal not found")
 // ErrNoHandler is returned when no handler exists for a signal.
 var ErrNoHandler = errors.New("no handler for signal")
 
+// ErrSignalExpired is recorded on signals that expired before delivery.
+var ErrSignalExpired = errors.New("signal expired")
+
 // Store persists and retrieves signals.
 type Store interface {
 	// Enqueue adds a signal for delivery.
 	Enqueue(ctx context.Context, signal *Signal) error
 
-	// Dequeue returns pending signals for a target.
+	// Dequeue returns pending signals for a target, highest Priority
+	// first and then in send order.
 	Dequeue(ctx context.Context, targetID string) ([]*Signal, error)
 
 	// Get retrieves a signal by ID.
@@ -234,21 +280,44 @@ func (s *MemoryStore) Enqueue(_ context.Context, signal *Signal) error {
 	return nil
 }
 
-// Dequeue returns pending signals for a target.
+// Dequeue returns pending signals for a target. Expired pending signals
+// are marked failed with ErrSignalExpired instead of being returned.
 func (s *MemoryStore) Dequeue(_ context.Context, targetID string) ([]*Signal, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
+	now := time.Now()
 	signalIDs := s.byTarget[targetID]
 	var pending []*Signal
 	for _, id := range signalIDs {
-		if sig := s.signals[id]; sig != nil && sig.Status == StatusPending {
-			pending = append(pending, sig.Clone())
+		sig := s.signals[id]
+		if sig == nil || sig.Status != StatusPending {
+			continue
+		}
+		if sig.Expired(now) {
+			sig.Status = StatusFailed
+			sig.ProcessedAt = &now
+			sig.Error = ErrSignalExpired.Error()
+			continue
 		}
+		pending = append(pending, sig.Clone())
 	}
+	sortByPriority(pending)
 	return pending, nil
 }
 
+// sortByPriority orders signals by descending Priority, then by SentAt.
+// The sort is stable, so signals sent at the same instant keep their
+// enqueue order.
+func sortByPriority(signals []*Signal) {
+	sort.SliceStable(signals, func(i, j int) bool {
+		if signals[i].Priority != signals[j].Priority {
+			return signals[i].Priority > signals[j].Priority
+		}
+		return signals[i].SentAt.Before(signals[j].SentAt)
+	})
+}
+
 // Get retrieves a signal by ID.
 func (s *MemoryStore) Get(_ context.Context, signalID string) (*Signal, error) {
 	s.mu.RLock()
@@ -320,18 +389,50 @@ func (s *MemoryStore) Delete(_ context.Context, signalID string) error {
 	if !exists {
 		return ErrSignalNotFound
 	}
+	s.deleteLocked(sig)
+	return nil
+}
+
+// PurgeExpired deletes every signal whose expiry is not after now,
+// whatever its status, and returns how many were deleted.
+func (s *MemoryStore) PurgeExpired(_ context.Context, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for _, sig := range s.signals {
+		if sig.Expired(now) {
+			s.deleteLocked(sig)
+			purged++
+		}
+	}
+	return purged, nil
+}
 
+// deleteLocked removes sig from the store. The caller must hold s.mu.
+func (s *MemoryStore) deleteLocked(sig *Signal) {
 	// Remove from byTarget index
 	targetSignals := s.byTarget[sig.TargetID]
 	for i, id := range targetSignals {
-		if id == signalID {
+		if id == sig.ID {
 			s.byTarget[sig.TargetID] = append(targetSignals[:i], targetSignals[i+1:]...)
 			break
 		}
 	}
+	if len(s.byTarget[sig.TargetID]) == 0 {
+		delete(s.byTarget, sig.TargetID)
+	}
 
-	delete(s.signals, signalID)
-	return nil
+	delete(s.signals, sig.ID)
+}
+
+// ExpiredPurger is implemented by stores that can delete expired signals
+// in bulk, as the Dispatcher's reaper requires. MemoryStore and
+// SQLiteStore implement it.
+type ExpiredPurger interface {
+	// PurgeExpired deletes every signal whose expiry is not after now and
+	// returns how many were deleted.
+	PurgeExpired(ctx context.Context, now time.Time) (int, error)
 }
 
 // Dispatcher sends and processes signals.
@@ -339,6 +440,13 @@ type Dispatcher struct {
 	registry *Registry
 	store    Store
 	logger   *slog.Logger
+
+	mu      sync.Mutex
+	notify  map[string]chan struct{} // targetID -> closed by the next Send
+	claimMu sync.Mutex               // serializes Wait claiming signals
+
+	stopReaper chan struct{} // nil unless WithReaper started a reaper
+	closeOnce  sync.Once
 }
 
 // NewDispatcher creates a new signal dispatcher.
@@ -347,6 +455,7 @@ func NewDispatcher(registry *Registry, store Store) *Dispatcher {
 		registry: registry,
 		store:    store,
 		logger:   slog.Default(),
+		notify:   make(map[string]chan struct{}),
 	}
 }
 
@@ -356,6 +465,84 @@ func (d *Dispatcher) WithLogger(logger *slog.Logger) *Dispatcher {
 	return d
 }
 
+// WithReaper starts a background goroutine that purges expired signals
+// from the store every interval, so signals that are never delivered do
+// not accumulate. Call Close to stop it.
+//
+// Panics if interval is not positive or the store does not implement
+// ExpiredPurger.
+//
+// Example:
+//
+//	dispatcher := signal.NewDispatcher(registry, store).WithReaper(time.Minute)
+//	defer dispatcher.Close()
+func (d *Dispatcher) WithReaper(interval time.Duration) *Dispatcher {
+	if interval <= 0 {
+		panic("signal: reaper interval must be > 0")
+	}
+	purger, ok := d.store.(ExpiredPurger)
+	if !ok {
+		panic(fmt.Sprintf("signal: store %T does not implement ExpiredPurger", d.store))
+	}
+	if d.stopReaper != nil {
+		panic("signal: reaper already started")
+	}
+
+	d.stopReaper = make(chan struct{})
+	go d.reapLoop(purger, interval, d.stopReaper)
+	return d
+}
+
+// Close stops the reaper started by WithReaper, if any. It does not close
+// the store. Closing twice is safe.
+func (d *Dispatcher) Close() {
+	d.closeOnce.Do(func() {
+		if d.stopReaper != nil {
+			close(d.stopReaper)
+		}
+	})
+}
+
+// reapLoop periodically purges expired signals until stop is closed.
+func (d *Dispatcher) reapLoop(purger ExpiredPurger, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			purged, err := purger.PurgeExpired(context.Background(), time.Now())
+			if err != nil {
+				d.logger.Error("failed to purge expired signals", "error", err)
+			} else if purged > 0 {
+				d.logger.Debug("purged expired signals", "count", purged)
+			}
+		}
+	}
+}
+
+// expire marks sig failed with ErrSignalExpired if it has expired, for
+// stores that return expired signals. Reports whether it had.
+func (d *Dispatcher) expire(ctx context.Context, sig *Signal) bool {
+	if !sig.Expired(time.Now()) {
+		return false
+	}
+	if markErr := d.store.MarkFailed(ctx, sig.ID, ErrSignalExpired); markErr != nil {
+		d.logger.Error("failed to mark signal as failed",
+			"signal_id", sig.ID,
+			"error", markErr,
+		)
+	}
+	d.logger.Debug("signal expired",
+		"signal_id", sig.ID,
+		"signal_name", sig.Name,
+		"target_id", sig.TargetID,
+	)
+	return true
+}
+
 // Send sends a signal to a target.
 func (d *Dispatcher) Send(ctx context.Context, signal *Signal) error {
 	if signal.TargetID == "" {
@@ -375,10 +562,107 @@ func (d *Dispatcher) Send(ctx context.Context, signal *Signal) error {
 		"target_id", signal.TargetID,
 	)
 
+	d.wake(signal.TargetID)
 	return nil
 }
 
-// Process processes all pending signals for a target.
+// Wait blocks until a pending signal named signalName is available for
+// targetID, marks it processed, and returns it. It returns ctx.Err() if
+// ctx is cancelled first.
+//
+// Wait lets a node pause for a human decision:
+//
+//	sig, err := dispatcher.Wait(ctx, runID, "approve")
+//	if err != nil {
+//	    return state, err // Cancelled or timed out
+//	}
+//	state.ApprovedBy = sig.SenderID
+//
+// Signals consumed by Wait need no registered handler; avoid calling
+// Process for the same target concurrently, which would handle or fail
+// them first. Each signal is returned to at most one waiter.
+//
+// Waiters are woken by Send on this Dispatcher. Signals enqueued directly
+// into the store, or by another process sharing it, are seen by the next
+// Wait call or the next wake-up.
+func (d *Dispatcher) Wait(ctx context.Context, targetID, signalName string) (*Signal, error) {
+	for {
+		// Watch before checking the store, so a Send in between still wakes us
+		woken := d.watch(targetID)
+
+		sig, err := d.claim(ctx, targetID, signalName)
+		if err != nil || sig != nil {
+			return sig, err
+		}
+
+		select {
+		case <-woken:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// claim marks the first pending signal named signalName for targetID as
+// processed and returns it, or returns nil if there is none.
+func (d *Dispatcher) claim(ctx context.Context, targetID, signalName string) (*Signal, error) {
+	d.claimMu.Lock()
+	defer d.claimMu.Unlock()
+
+	signals, err := d.store.Dequeue(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dequeue signals: %w", err)
+	}
+
+	for _, sig := range signals {
+		if sig.Name != signalName || d.expire(ctx, sig) {
+			continue
+		}
+		if err := d.store.MarkProcessed(ctx, sig.ID); err != nil {
+			return nil, fmt.Errorf("failed to mark signal as processed: %w", err)
+		}
+
+		d.logger.Debug("signal received by waiter",
+			"signal_id", sig.ID,
+			"signal_name", sig.Name,
+			"target_id", targetID,
+		)
+
+		now := time.Now()
+		sig.Status = StatusProcessed
+		sig.ProcessedAt = &now
+		return sig, nil
+	}
+	return nil, nil
+}
+
+// watch returns a channel that is closed by the next Send to targetID.
+func (d *Dispatcher) watch(targetID string) <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ch, ok := d.notify[targetID]
+	if !ok {
+		ch = make(chan struct{})
+		d.notify[targetID] = ch
+	}
+	return ch
+}
+
+// wake releases everyone watching targetID.
+func (d *Dispatcher) wake(targetID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if ch, ok := d.notify[targetID]; ok {
+		close(ch)
+		delete(d.notify, targetID)
+	}
+}
+
+// Process processes all pending signals for a target, in the order the
+// store dequeues them (highest Priority first for the built-in stores).
+// Expired signals are marked failed with ErrSignalExpired and not handled.
 func (d *Dispatcher) Process(ctx context.Context, targetID string) error {
 	signals, err := d.store.Dequeue(ctx, targetID)
 	if err != nil {
@@ -386,6 +670,9 @@ func (d *Dispatcher) Process(ctx context.Context, targetID string) error {
 	}
 
 	for _, sig := range signals {
+		if d.expire(ctx, sig) {
+			continue
+		}
 		if processErr := d.processOne(ctx, sig); processErr != nil {
 			d.logger.Error("signal processing failed",
 				"signal_id", sig.ID,
@@ -443,11 +730,15 @@ func (d *Dispatcher) processOne(ctx context.Context, sig *Signal) error {
 	return nil
 }
 
-// ProcessOne processes a specific signal by ID.
+// ProcessOne processes a specific signal by ID. Returns ErrSignalExpired,
+// after marking the signal failed, if a pending signal has expired.
 func (d *Dispatcher) ProcessOne(ctx context.Context, signalID string) error {
 	sig, err := d.store.Get(ctx, signalID)
 	if err != nil {
 		return err
 	}
+	if sig.Status == StatusPending && d.expire(ctx, sig) {
+		return ErrSignalExpired
+	}
 	return d.processOne(ctx, sig)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -376,3 +377,244 @@ func TestDispatcher_ProcessOne(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, processed)
 }
+
+func TestDispatcher_Wait_WokenBySend(t *testing.T) {
+	store := signal.NewMemoryStore()
+	dispatcher := signal.NewDispatcher(signal.NewRegistry(), store)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	result := make(chan *signal.Signal, 1)
+	go func() {
+		sig, err := dispatcher.Wait(ctx, "run-123", "approve")
+		assert.NoError(t, err)
+		result <- sig
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, dispatcher.Send(ctx, signal.NewSignal("other", "run-123", nil)))
+	require.NoError(t, dispatcher.Send(ctx, signal.NewSignal("approve", "run-456", nil)))
+	require.NoError(t, dispatcher.Send(ctx, signal.NewSignal("approve", "run-123", nil).WithSender("alice")))
+
+	select {
+	case sig := <-result:
+		require.NotNil(t, sig)
+		assert.Equal(t, "alice", sig.SenderID)
+		assert.Equal(t, signal.StatusProcessed, sig.Status)
+
+		got, err := store.Get(ctx, sig.ID)
+		require.NoError(t, err)
+		assert.Equal(t, signal.StatusProcessed, got.Status)
+	case <-ctx.Done():
+		t.Fatal("Wait did not return after Send")
+	}
+
+	pending, err := store.Dequeue(ctx, "run-123")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "other", pending[0].Name)
+}
+
+func TestDispatcher_Wait_AlreadyPending(t *testing.T) {
+	store := signal.NewMemoryStore()
+	dispatcher := signal.NewDispatcher(signal.NewRegistry(), store)
+	ctx := context.Background()
+
+	require.NoError(t, store.Enqueue(ctx, signal.NewSignal("approve", "run-123", nil)))
+
+	sig, err := dispatcher.Wait(ctx, "run-123", "approve")
+	require.NoError(t, err)
+	assert.Equal(t, "approve", sig.Name)
+}
+
+func TestDispatcher_Wait_ContextCancelled(t *testing.T) {
+	dispatcher := signal.NewDispatcher(signal.NewRegistry(), signal.NewMemoryStore())
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	sig, err := dispatcher.Wait(ctx, "run-123", "approve")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, sig)
+}
+
+func TestDispatcher_Wait_EachSignalToOneWaiter(t *testing.T) {
+	dispatcher := signal.NewDispatcher(signal.NewRegistry(), signal.NewMemoryStore())
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	const waiters = 3
+	results := make(chan string, waiters)
+	for i := 0; i < waiters; i++ {
+		go func() {
+			sig, err := dispatcher.Wait(ctx, "run-123", "approve")
+			if err == nil {
+				results <- sig.ID
+			}
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	for i := 0; i < waiters; i++ {
+		require.NoError(t, dispatcher.Send(ctx, signal.NewSignal("approve", "run-123", nil)))
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < waiters; i++ {
+		select {
+		case id := <-results:
+			assert.False(t, seen[id], "signal %s returned twice", id)
+			seen[id] = true
+		case <-ctx.Done():
+			t.Fatal("waiters did not all return")
+		}
+	}
+}
+
+func TestSignal_WithTTL(t *testing.T) {
+	sig := signal.NewSignal("test", "run-1", nil)
+	assert.True(t, sig.ExpiresAt.IsZero())
+	assert.False(t, sig.Expired(time.Now().Add(time.Hour)), "no TTL never expires")
+
+	sig.WithTTL(time.Minute)
+	assert.Equal(t, sig.SentAt.Add(time.Minute), sig.ExpiresAt)
+	assert.False(t, sig.Expired(sig.SentAt))
+	assert.True(t, sig.Expired(sig.SentAt.Add(time.Minute)))
+}
+
+func TestMemoryStore_Dequeue_Expired(t *testing.T) {
+	store := signal.NewMemoryStore()
+	ctx := context.Background()
+
+	expired := signal.NewSignal("stale", "run-1", nil).WithTTL(-time.Second)
+	live := signal.NewSignal("fresh", "run-1", nil).WithTTL(time.Hour)
+	require.NoError(t, store.Enqueue(ctx, expired))
+	require.NoError(t, store.Enqueue(ctx, live))
+
+	pending, err := store.Dequeue(ctx, "run-1")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, live.ID, pending[0].ID)
+
+	got, err := store.Get(ctx, expired.ID)
+	require.NoError(t, err)
+	assert.Equal(t, signal.StatusFailed, got.Status)
+	assert.Equal(t, signal.ErrSignalExpired.Error(), got.Error)
+}
+
+func TestDispatcher_Process_Expired(t *testing.T) {
+	registry := signal.NewRegistry()
+	var handled []string
+	registry.MustRegister("nudge", func(_ context.Context, _ string, sig *signal.Signal) error {
+		handled = append(handled, sig.ID)
+		return nil
+	})
+	store := &listStore{MemoryStore: signal.NewMemoryStore()}
+	dispatcher := signal.NewDispatcher(registry, store)
+	ctx := context.Background()
+
+	expired := signal.NewSignal("nudge", "run-1", nil).WithTTL(-time.Second)
+	require.NoError(t, dispatcher.Send(ctx, expired))
+	require.NoError(t, dispatcher.Process(ctx, "run-1"))
+	assert.Empty(t, handled)
+
+	got, err := store.Get(ctx, expired.ID)
+	require.NoError(t, err)
+	assert.Equal(t, signal.StatusFailed, got.Status)
+	assert.Equal(t, signal.ErrSignalExpired.Error(), got.Error)
+
+	// ProcessOne reports the expiry
+	another := signal.NewSignal("nudge", "run-1", nil).WithTTL(-time.Second)
+	require.NoError(t, dispatcher.Send(ctx, another))
+	assert.ErrorIs(t, dispatcher.ProcessOne(ctx, another.ID), signal.ErrSignalExpired)
+	assert.Empty(t, handled)
+}
+
+func TestDispatcher_WithReaper(t *testing.T) {
+	store := signal.NewMemoryStore()
+	dispatcher := signal.NewDispatcher(signal.NewRegistry(), store).WithReaper(5 * time.Millisecond)
+	defer dispatcher.Close()
+	ctx := context.Background()
+
+	expired := signal.NewSignal("stale", "run-1", nil).WithTTL(-time.Second)
+	kept := signal.NewSignal("fresh", "run-1", nil)
+	require.NoError(t, dispatcher.Send(ctx, expired))
+	require.NoError(t, dispatcher.Send(ctx, kept))
+
+	require.Eventually(t, func() bool {
+		_, err := store.Get(ctx, expired.ID)
+		return errors.Is(err, signal.ErrSignalNotFound)
+	}, time.Second, 5*time.Millisecond)
+
+	_, err := store.Get(ctx, kept.ID)
+	assert.NoError(t, err)
+
+	dispatcher.Close() // Closing twice is safe
+
+	assert.PanicsWithValue(t, "signal: reaper interval must be > 0", func() {
+		signal.NewDispatcher(signal.NewRegistry(), store).WithReaper(0)
+	})
+	assert.Panics(t, func() {
+		signal.NewDispatcher(signal.NewRegistry(), &listStore{MemoryStore: store}).WithReaper(time.Second)
+	}, "store without PurgeExpired")
+}
+
+// listStore is a Store that returns pending signals without checking
+// expiry, like a store written before signals could expire.
+type listStore struct {
+	*signal.MemoryStore
+}
+
+func (s *listStore) Dequeue(ctx context.Context, targetID string) ([]*signal.Signal, error) {
+	all, err := s.ListByTarget(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	var pending []*signal.Signal
+	for _, sig := range all {
+		if sig.Status == signal.StatusPending {
+			pending = append(pending, sig)
+		}
+	}
+	return pending, nil
+}
+
+// PurgeExpired hides the embedded MemoryStore's method, so listStore does
+// not implement ExpiredPurger.
+func (s *listStore) PurgeExpired() {}
+
+func TestDispatcher_Process_Priority(t *testing.T) {
+	registry := signal.NewRegistry()
+	var order []string
+	handler := func(_ context.Context, _ string, sig *signal.Signal) error {
+		order = append(order, sig.Payload["label"].(string))
+		return nil
+	}
+	for _, name := range []string{"config", "cancel", "nudge"} {
+		registry.MustRegister(name, handler)
+	}
+	dispatcher := signal.NewDispatcher(registry, signal.NewMemoryStore())
+	ctx := context.Background()
+
+	base := time.Now()
+	sends := []struct {
+		name     string
+		label    string
+		priority int
+	}{
+		{"config", "config-1", 0},
+		{"nudge", "nudge-1", 5},
+		{"cancel", "cancel", 10},
+		{"config", "config-2", 0},
+		{"nudge", "nudge-2", 5},
+		{"config", "config-low", -1},
+	}
+	for i, s := range sends {
+		sig := signal.NewSignal(s.name, "run-1", map[string]any{"label": s.label}).WithPriority(s.priority)
+		sig.SentAt = base.Add(time.Duration(i) * time.Millisecond)
+		require.NoError(t, dispatcher.Send(ctx, sig))
+	}
+
+	require.NoError(t, dispatcher.Process(ctx, "run-1"))
+	assert.Equal(t, []string{"cancel", "nudge-1", "nudge-2", "config-1", "config-2", "config-low"}, order)
+}
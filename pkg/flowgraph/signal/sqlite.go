@@ -0,0 +1,407 @@
+package signal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite" // Pure Go SQLite driver
+)
+
+// ErrStoreClosed is returned when using a store after Close.
+var ErrStoreClosed = errors.New("signal store is closed")
+
+// SQLiteStore persists signals to SQLite, so pending signals survive a
+// restart and can be shared between processes using the same file, such
+// as a web handler that sends signals and a worker that processes them.
+//
+// Signals are stored as JSON, so payload values read back from the store
+// are JSON-decoded (map[string]any, float64, and so on) rather than the
+// original Go types.
+type SQLiteStore struct {
+	db     *sql.DB
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewSQLiteStore creates a new SQLite signal store.
+// The path should be a file path (e.g., "./signals.db") or ":memory:" for testing.
+//
+// The database file is created with restrictive permissions (0600) since
+// signal payloads may contain sensitive data.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	// Create file with restrictive permissions BEFORE sql.Open touches it.
+	if path != ":memory:" {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			f, createErr := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+			if createErr == nil {
+				if closeErr := f.Close(); closeErr != nil {
+					slog.Warn("failed to close signal store file after creation",
+						slog.String("path", path),
+						slog.String("error", closeErr.Error()))
+				}
+			}
+			// Ignore createErr - file might have been created between Stat and OpenFile (TOCTOU)
+		}
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	if path == ":memory:" {
+		// Each connection to :memory: is a separate database
+		db.SetMaxOpenConns(1)
+	}
+
+	// Enable WAL mode for better concurrent read performance
+	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("enable WAL mode: %w", err)
+	}
+
+	// Wait for locks held by other processes sharing the file
+	if _, err := db.Exec("PRAGMA busy_timeout=5000"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("set busy timeout: %w", err)
+	}
+
+	// seq preserves enqueue order, as MemoryStore does. expires_at is
+	// ExpiresAt in Unix nanoseconds, or 0 for none.
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS signals (
+			seq INTEGER PRIMARY KEY AUTOINCREMENT,
+			id TEXT NOT NULL UNIQUE,
+			target_id TEXT NOT NULL,
+			status TEXT NOT NULL,
+			data BLOB NOT NULL,
+			expires_at INTEGER NOT NULL DEFAULT 0
+		)
+	`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create table: %w", err)
+	}
+	if err := addExpiresAtColumn(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	for _, stmt := range []string{
+		`CREATE INDEX IF NOT EXISTS idx_signals_target_id ON signals(target_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_signals_status ON signals(status)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("create index: %w", err)
+		}
+	}
+
+	// Ensure permissions are correct for existing files
+	if path != ":memory:" {
+		if err := os.Chmod(path, 0600); err != nil {
+			slog.Warn("failed to set restrictive permissions on signal store file",
+				slog.String("path", path),
+				slog.String("error", err.Error()),
+				slog.String("security_note", "signal payloads may be readable by other users"))
+		}
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// Enqueue adds a signal for delivery. Missing ID, SentAt, and Status
+// fields are filled in on signal, as with MemoryStore.
+func (s *SQLiteStore) Enqueue(ctx context.Context, signal *Signal) error {
+	if signal.ID == "" {
+		signal.ID = fmt.Sprintf("sig-%s", uuid.New().String()[:8])
+	}
+	if signal.SentAt.IsZero() {
+		signal.SentAt = time.Now()
+	}
+	if signal.Status == "" {
+		signal.Status = StatusPending
+	}
+
+	data, err := json.Marshal(signal)
+	if err != nil {
+		return fmt.Errorf("marshal signal: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO signals (id, target_id, status, data, expires_at) VALUES (?, ?, ?, ?, ?)
+	`, signal.ID, signal.TargetID, string(signal.Status), data, expiresAt(signal))
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint") {
+			return fmt.Errorf("signal %q already exists", signal.ID)
+		}
+		return fmt.Errorf("enqueue signal: %w", err)
+	}
+	return nil
+}
+
+// Dequeue returns pending signals for a target, highest Priority first and
+// then in send order. Expired pending signals are marked failed with
+// ErrSignalExpired instead of being returned.
+func (s *SQLiteStore) Dequeue(ctx context.Context, targetID string) ([]*Signal, error) {
+	signals, err := s.query(ctx, `
+		SELECT data FROM signals WHERE target_id = ? AND status = ? ORDER BY seq
+	`, targetID, string(StatusPending))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	pending := signals[:0]
+	for _, sig := range signals {
+		if !sig.Expired(now) {
+			pending = append(pending, sig)
+			continue
+		}
+		if err := s.MarkFailed(ctx, sig.ID, ErrSignalExpired); err != nil {
+			return nil, fmt.Errorf("expire signal: %w", err)
+		}
+	}
+	sortByPriority(pending)
+	return pending, nil
+}
+
+// Get retrieves a signal by ID.
+func (s *SQLiteStore) Get(ctx context.Context, signalID string) (*Signal, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil, ErrStoreClosed
+	}
+
+	var data []byte
+	err := s.db.QueryRowContext(ctx, `
+		SELECT data FROM signals WHERE id = ?
+	`, signalID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSignalNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get signal: %w", err)
+	}
+	return decodeSignal(data)
+}
+
+// MarkProcessed marks a signal as successfully processed.
+func (s *SQLiteStore) MarkProcessed(ctx context.Context, signalID string) error {
+	return s.update(ctx, signalID, func(sig *Signal) {
+		now := time.Now()
+		sig.Status = StatusProcessed
+		sig.ProcessedAt = &now
+	})
+}
+
+// MarkFailed marks a signal as failed.
+func (s *SQLiteStore) MarkFailed(ctx context.Context, signalID string, err error) error {
+	return s.update(ctx, signalID, func(sig *Signal) {
+		now := time.Now()
+		sig.Status = StatusFailed
+		sig.ProcessedAt = &now
+		if err != nil {
+			sig.Error = err.Error()
+		}
+	})
+}
+
+// ListByTarget returns all signals for a target, in enqueue order.
+func (s *SQLiteStore) ListByTarget(ctx context.Context, targetID string) ([]*Signal, error) {
+	return s.query(ctx, `
+		SELECT data FROM signals WHERE target_id = ? ORDER BY seq
+	`, targetID)
+}
+
+// Delete removes a signal.
+func (s *SQLiteStore) Delete(ctx context.Context, signalID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM signals WHERE id = ?`, signalID)
+	if err != nil {
+		return fmt.Errorf("delete signal: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrSignalNotFound
+	}
+	return nil
+}
+
+// PurgeExpired deletes every signal whose expiry is not after now,
+// whatever its status, and returns how many were deleted.
+func (s *SQLiteStore) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, ErrStoreClosed
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		DELETE FROM signals WHERE expires_at > 0 AND expires_at <= ?
+	`, now.UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("purge expired signals: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("purge expired signals: %w", err)
+	}
+	return int(n), nil
+}
+
+// Close closes the database. Closing twice is safe.
+func (s *SQLiteStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+	return s.db.Close()
+}
+
+// query returns the signals selected by a query on the data column.
+func (s *SQLiteStore) query(ctx context.Context, query string, args ...any) ([]*Signal, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil, ErrStoreClosed
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query signals: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*Signal
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("scan signal: %w", err)
+		}
+		sig, err := decodeSignal(data)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, sig)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate signals: %w", err)
+	}
+	return result, nil
+}
+
+// update applies fn to a stored signal in a transaction, so concurrent
+// updates from other processes are not lost.
+func (s *SQLiteStore) update(ctx context.Context, signalID string, fn func(*Signal)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op after Commit
+
+	var data []byte
+	err = tx.QueryRowContext(ctx, `SELECT data FROM signals WHERE id = ?`, signalID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrSignalNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get signal: %w", err)
+	}
+
+	sig, err := decodeSignal(data)
+	if err != nil {
+		return err
+	}
+	fn(sig)
+
+	data, err = json.Marshal(sig)
+	if err != nil {
+		return fmt.Errorf("marshal signal: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE signals SET status = ?, data = ? WHERE id = ?
+	`, string(sig.Status), data, signalID); err != nil {
+		return fmt.Errorf("update signal: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// addExpiresAtColumn adds the expires_at column to tables created before
+// signals could expire. Existing signals never expire.
+func addExpiresAtColumn(db *sql.DB) error {
+	var n int
+	if err := db.QueryRow(`
+		SELECT COUNT(*) FROM pragma_table_info('signals') WHERE name = 'expires_at'
+	`).Scan(&n); err != nil {
+		return fmt.Errorf("inspect table: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+	if _, err := db.Exec(`ALTER TABLE signals ADD COLUMN expires_at INTEGER NOT NULL DEFAULT 0`); err != nil {
+		return fmt.Errorf("add expires_at column: %w", err)
+	}
+	return nil
+}
+
+// expiresAt returns the value of the expires_at column for sig.
+func expiresAt(sig *Signal) int64 {
+	if sig.ExpiresAt.IsZero() {
+		return 0
+	}
+	return sig.ExpiresAt.UnixNano()
+}
+
+// decodeSignal unmarshals a stored signal.
+func decodeSignal(data []byte) (*Signal, error) {
+	var sig Signal
+	if err := json.Unmarshal(data, &sig); err != nil {
+		return nil, fmt.Errorf("unmarshal signal: %w", err)
+	}
+	return &sig, nil
+}
+
+// Compile-time checks that SQLiteStore implements Store and ExpiredPurger.
+var (
+	_ Store         = (*SQLiteStore)(nil)
+	_ ExpiredPurger = (*SQLiteStore)(nil)
+)
@@ -0,0 +1,273 @@
+package signal_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/signal"
+)
+
+func newTestSQLiteStore(t *testing.T, path string) *signal.SQLiteStore {
+	t.Helper()
+	store, err := signal.NewSQLiteStore(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestSQLiteStore_EnqueueGet(t *testing.T) {
+	store := newTestSQLiteStore(t, ":memory:")
+	ctx := context.Background()
+
+	sig := signal.NewSignal("approve", "run-123", map[string]any{"approver": "alice"}).WithSender("web")
+	require.NoError(t, store.Enqueue(ctx, sig))
+	assert.Error(t, store.Enqueue(ctx, sig), "duplicate ID")
+
+	got, err := store.Get(ctx, sig.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sig.Name, got.Name)
+	assert.Equal(t, "run-123", got.TargetID)
+	assert.Equal(t, "web", got.SenderID)
+	assert.Equal(t, "alice", got.Payload["approver"])
+	assert.Equal(t, signal.StatusPending, got.Status)
+
+	_, err = store.Get(ctx, "nonexistent")
+	assert.ErrorIs(t, err, signal.ErrSignalNotFound)
+}
+
+func TestSQLiteStore_EnqueueDefaults(t *testing.T) {
+	store := newTestSQLiteStore(t, ":memory:")
+	ctx := context.Background()
+
+	sig := &signal.Signal{Name: "cancel", TargetID: "run-1"}
+	require.NoError(t, store.Enqueue(ctx, sig))
+
+	assert.NotEmpty(t, sig.ID)
+	assert.False(t, sig.SentAt.IsZero())
+	assert.Equal(t, signal.StatusPending, sig.Status)
+}
+
+func TestSQLiteStore_DequeueOnlyPending(t *testing.T) {
+	store := newTestSQLiteStore(t, ":memory:")
+	ctx := context.Background()
+
+	var ids []string
+	for i := 0; i < 4; i++ {
+		sig := signal.NewSignal(fmt.Sprintf("s%d", i), "run-1", nil)
+		require.NoError(t, store.Enqueue(ctx, sig))
+		ids = append(ids, sig.ID)
+	}
+	require.NoError(t, store.Enqueue(ctx, signal.NewSignal("other", "run-2", nil)))
+
+	require.NoError(t, store.MarkProcessed(ctx, ids[0]))
+	require.NoError(t, store.MarkFailed(ctx, ids[2], errors.New("handler failed")))
+
+	pending, err := store.Dequeue(ctx, "run-1")
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, ids[1], pending[0].ID)
+	assert.Equal(t, ids[3], pending[1].ID)
+
+	pending, err = store.Dequeue(ctx, "nonexistent")
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	all, err := store.ListByTarget(ctx, "run-1")
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+}
+
+func TestSQLiteStore_MarkProcessedAndFailed(t *testing.T) {
+	store := newTestSQLiteStore(t, ":memory:")
+	ctx := context.Background()
+
+	processed := signal.NewSignal("a", "run-1", nil)
+	failed := signal.NewSignal("b", "run-1", nil)
+	require.NoError(t, store.Enqueue(ctx, processed))
+	require.NoError(t, store.Enqueue(ctx, failed))
+
+	require.NoError(t, store.MarkProcessed(ctx, processed.ID))
+	require.NoError(t, store.MarkFailed(ctx, failed.ID, errors.New("handler failed")))
+
+	got, err := store.Get(ctx, processed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, signal.StatusProcessed, got.Status)
+	assert.NotNil(t, got.ProcessedAt)
+
+	got, err = store.Get(ctx, failed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, signal.StatusFailed, got.Status)
+	assert.Equal(t, "handler failed", got.Error)
+
+	assert.ErrorIs(t, store.MarkProcessed(ctx, "nonexistent"), signal.ErrSignalNotFound)
+	assert.ErrorIs(t, store.MarkFailed(ctx, "nonexistent", nil), signal.ErrSignalNotFound)
+}
+
+func TestSQLiteStore_Delete(t *testing.T) {
+	store := newTestSQLiteStore(t, ":memory:")
+	ctx := context.Background()
+
+	sig := signal.NewSignal("test", "run-1", nil)
+	require.NoError(t, store.Enqueue(ctx, sig))
+
+	require.NoError(t, store.Delete(ctx, sig.ID))
+	_, err := store.Get(ctx, sig.ID)
+	assert.ErrorIs(t, err, signal.ErrSignalNotFound)
+	assert.ErrorIs(t, store.Delete(ctx, sig.ID), signal.ErrSignalNotFound)
+}
+
+func TestSQLiteStore_SharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signals.db")
+	sender := newTestSQLiteStore(t, path)
+	worker := newTestSQLiteStore(t, path)
+	ctx := context.Background()
+
+	sig := signal.NewSignal("approve", "run-1", nil)
+	require.NoError(t, sender.Enqueue(ctx, sig))
+
+	pending, err := worker.Dequeue(ctx, "run-1")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.NoError(t, worker.MarkProcessed(ctx, pending[0].ID))
+
+	got, err := sender.Get(ctx, sig.ID)
+	require.NoError(t, err)
+	assert.Equal(t, signal.StatusProcessed, got.Status)
+}
+
+func TestSQLiteStore_Concurrent(t *testing.T) {
+	store := newTestSQLiteStore(t, filepath.Join(t.TempDir(), "signals.db"))
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sig := signal.NewSignal("test", "run-1", nil)
+			assert.NoError(t, store.Enqueue(ctx, sig))
+			assert.NoError(t, store.MarkProcessed(ctx, sig.ID))
+		}()
+	}
+	wg.Wait()
+
+	all, err := store.ListByTarget(ctx, "run-1")
+	require.NoError(t, err)
+	assert.Len(t, all, 20)
+	pending, err := store.Dequeue(ctx, "run-1")
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestSQLiteStore_Closed(t *testing.T) {
+	store, err := signal.NewSQLiteStore(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+	require.NoError(t, store.Close())
+
+	ctx := context.Background()
+	assert.ErrorIs(t, store.Enqueue(ctx, signal.NewSignal("test", "run-1", nil)), signal.ErrStoreClosed)
+	_, err = store.Dequeue(ctx, "run-1")
+	assert.ErrorIs(t, err, signal.ErrStoreClosed)
+	assert.ErrorIs(t, store.MarkProcessed(ctx, "x"), signal.ErrStoreClosed)
+}
+
+func TestSQLiteStore_WithDispatcher(t *testing.T) {
+	store := newTestSQLiteStore(t, ":memory:")
+	registry := signal.NewRegistry()
+	var received []string
+	registry.MustRegister("approve", func(_ context.Context, _ string, sig *signal.Signal) error {
+		received = append(received, sig.Payload["by"].(string))
+		return nil
+	})
+	dispatcher := signal.NewDispatcher(registry, store)
+	ctx := context.Background()
+
+	require.NoError(t, dispatcher.Send(ctx, signal.NewSignal("approve", "run-1", map[string]any{"by": "alice"})))
+	require.NoError(t, dispatcher.Process(ctx, "run-1"))
+
+	assert.Equal(t, []string{"alice"}, received)
+	pending, err := store.Dequeue(ctx, "run-1")
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestSQLiteStore_Expiry(t *testing.T) {
+	store := newTestSQLiteStore(t, ":memory:")
+	ctx := context.Background()
+
+	expired := signal.NewSignal("stale", "run-1", nil).WithTTL(-time.Second)
+	live := signal.NewSignal("fresh", "run-1", nil).WithTTL(time.Hour)
+	forever := signal.NewSignal("plain", "run-1", nil)
+	for _, sig := range []*signal.Signal{expired, live, forever} {
+		require.NoError(t, store.Enqueue(ctx, sig))
+	}
+
+	pending, err := store.Dequeue(ctx, "run-1")
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, live.ID, pending[0].ID)
+	assert.Equal(t, forever.ID, pending[1].ID)
+
+	got, err := store.Get(ctx, expired.ID)
+	require.NoError(t, err)
+	assert.Equal(t, signal.StatusFailed, got.Status)
+	assert.Equal(t, signal.ErrSignalExpired.Error(), got.Error)
+
+	purged, err := store.PurgeExpired(ctx, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, purged)
+
+	_, err = store.Get(ctx, forever.ID)
+	assert.NoError(t, err, "signals without a TTL are never purged")
+}
+
+func TestSQLiteStore_MigratesExpiresAt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signals.db")
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE signals (
+		seq INTEGER PRIMARY KEY AUTOINCREMENT,
+		id TEXT NOT NULL UNIQUE,
+		target_id TEXT NOT NULL,
+		status TEXT NOT NULL,
+		data BLOB NOT NULL
+	)`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	store := newTestSQLiteStore(t, path)
+	ctx := context.Background()
+	sig := signal.NewSignal("stale", "run-1", nil).WithTTL(-time.Second)
+	require.NoError(t, store.Enqueue(ctx, sig))
+
+	purged, err := store.PurgeExpired(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+}
+
+func TestSQLiteStore_DequeuePriority(t *testing.T) {
+	store := newTestSQLiteStore(t, ":memory:")
+	ctx := context.Background()
+
+	low := signal.NewSignal("config", "run-1", nil)
+	high := signal.NewSignal("cancel", "run-1", nil).WithPriority(10)
+	mid := signal.NewSignal("nudge", "run-1", nil).WithPriority(5)
+	for _, sig := range []*signal.Signal{low, high, mid} {
+		require.NoError(t, store.Enqueue(ctx, sig))
+	}
+
+	pending, err := store.Dequeue(ctx, "run-1")
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	assert.Equal(t, []string{high.ID, mid.ID, low.ID}, []string{pending[0].ID, pending[1].ID, pending[2].ID})
+}
@@ -0,0 +1,163 @@
+package flowgraph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AddSubgraph adds a compiled graph as a single logical node.
+// When the node executes, the current state is passed to the subgraph's
+// entry node and the subgraph's final state becomes the node's result.
+// Returns the graph for method chaining.
+//
+// If the subgraph fails, the node fails with a *NodeError whose NodeID is
+// id and whose Op is "subgraph"; the error unwraps to the inner error
+// (typically the inner *NodeError).
+//
+// With checkpointing enabled, the subgraph checkpoints under the run ID
+// "<outer run ID>/<id>". If the outer run is resumed while the subgraph was
+// in progress, the subgraph resumes from its own latest checkpoint.
+// Otherwise, including when the node is retried, the subgraph starts at its
+// entry and discards checkpoints left by earlier attempts.
+// Inner checkpoints are deleted once the subgraph completes.
+//
+// The subgraph inherits the node registry, iteration and state size limits,
+// parallelism limit, logger, and metrics settings of the outer run. Per-node timeouts are not
+// inherited; node IDs are scoped to the graph that defines them.
+//
+// Panics under the same conditions as AddNode, or if sub is nil.
+//
+// Example:
+//
+//	review := reviewGraph.Compile() // *CompiledGraph[Doc]
+//	graph.AddNode("draft", draft).
+//	    AddSubgraph("review", review).
+//	    AddEdge("draft", "review")
+func (g *Graph[S]) AddSubgraph(id string, sub *CompiledGraph[S], opts ...NodeOption) *Graph[S] {
+	validateNodeID(id)
+
+	if sub == nil {
+		panic("flowgraph: subgraph cannot be nil")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.addNodeLocked(id, sub.runAsNode, opts)
+	g.subgraphs[id] = sub
+	return g
+}
+
+// IsSubgraph returns true if the node runs an embedded compiled graph.
+func (cg *CompiledGraph[S]) IsSubgraph(id string) bool {
+	_, ok := cg.subgraphs[id]
+	return ok
+}
+
+// runAsNode is the node function stored for subgraph nodes.
+// The executor runs subgraphs via runSubgraph so that run configuration is
+// inherited; this only runs if the node is invoked outside the executor.
+func (cg *CompiledGraph[S]) runAsNode(ctx Context, state S) (S, error) {
+	return cg.Run(ctx, state)
+}
+
+// subgraphRunID returns the namespaced run ID for a subgraph node.
+func subgraphRunID(outerRunID, nodeID string) string {
+	return outerRunID + "/" + nodeID
+}
+
+// subgraphNode returns a node function that runs sub under the outer run's
+// configuration.
+func subgraphNode[S any](sub *CompiledGraph[S], nodeID string, outer *runConfig) NodeFunc[S] {
+	return func(ctx Context, state S) (S, error) {
+		return sub.runSubgraph(ctx, nodeID, state, outer)
+	}
+}
+
+// runSubgraph executes cg as the body of the outer node nodeID.
+func (cg *CompiledGraph[S]) runSubgraph(ctx Context, nodeID string, state S, outer *runConfig) (S, error) {
+	cfg := defaultRunConfig()
+	cfg.maxIterations = outer.maxIterations
+	cfg.maxStateSize = outer.maxStateSize
+	cfg.parallelism = outer.parallelism
+	cfg.nodeRegistry = outer.nodeRegistry
+	cfg.logger = outer.logger
+	cfg.metricsEnabled = outer.metricsEnabled
+	cfg.tracingEnabled = outer.tracingEnabled
+	cfg.metrics = outer.metrics
+	cfg.spans = outer.spans
+
+	if outer.checkpointStore == nil {
+		entry, err := cg.selectEntry(state)
+		if err != nil {
+			return state, err
+		}
+		return cg.runFrom(ctx, state, entry, &cfg)
+	}
+
+	cfg.checkpointStore = outer.checkpointStore
+	cfg.checkpointFailureFatal = outer.checkpointFailureFatal
+	cfg.checkpointCompression = outer.checkpointCompression
+	cfg.checkpointCodec = outer.checkpointCodec
+	cfg.checkpointCipher = outer.checkpointCipher
+	cfg.checkpointMetadata = outer.checkpointMetadata
+	cfg.checkpointInterval = outer.checkpointInterval
+	cfg.runID = subgraphRunID(outer.runID, nodeID)
+	cfg.resuming = outer.resuming
+
+	if !outer.resuming {
+		// Checkpoints from a failed earlier attempt must not be resumed
+		if err := cfg.checkpointStore.DeleteRun(cfg.runID); err != nil {
+			return state, &CheckpointError{
+				NodeID: nodeID,
+				Op:     "delete",
+				Err:    err,
+			}
+		}
+	}
+
+	start, state, err := cg.subgraphStart(&cfg, state)
+	if err != nil {
+		return state, err
+	}
+
+	result := state
+	if start != END {
+		result, err = cg.runFrom(ctx, state, start, &cfg)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if err := cfg.checkpointStore.DeleteRun(cfg.runID); err != nil {
+		return result, &CheckpointError{
+			NodeID: nodeID,
+			Op:     "delete",
+			Err:    err,
+		}
+	}
+	return result, nil
+}
+
+// subgraphStart determines where a checkpointed subgraph run begins.
+// If the subgraph left checkpoints from an interrupted run, execution
+// continues from the latest one; otherwise it starts at the entry node.
+// Returns END if the subgraph had already completed.
+func (cg *CompiledGraph[S]) subgraphStart(cfg *runConfig, state S) (string, S, error) {
+	cp, err := loadLatestCheckpoint(cfg.checkpointStore, cfg.runID)
+	if errors.Is(err, ErrNoCheckpoints) {
+		entry, err := cg.selectEntry(state)
+		return entry, state, err
+	}
+	if err != nil {
+		return "", state, fmt.Errorf("resume subgraph: %w", err)
+	}
+
+	var resumed S
+	if _, _, err := decodeCheckpointState(cp, cfg.checkpointCodec, cfg.checkpointCipher, &resumed); err != nil {
+		return "", state, fmt.Errorf("resume subgraph: %w", err)
+	}
+
+	cfg.sequence = cp.Sequence
+	return cp.NextNode, resumed, nil
+}
@@ -0,0 +1,273 @@
+package flowgraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/checkpoint"
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compileCounterChain compiles a linear graph of increment nodes.
+func compileCounterChain(t *testing.T, ids ...string) *CompiledGraph[Counter] {
+	t.Helper()
+
+	graph := NewGraph[Counter]()
+	for i, id := range ids {
+		graph.AddNode(id, increment)
+		if i > 0 {
+			graph.AddEdge(ids[i-1], id)
+		}
+	}
+	graph.AddEdge(ids[len(ids)-1], END).SetEntry(ids[0])
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+	return compiled
+}
+
+// TestAddSubgraph_RunsAsSingleNode tests that state flows into and out of a subgraph.
+func TestAddSubgraph_RunsAsSingleNode(t *testing.T) {
+	inner := compileCounterChain(t, "x", "y", "z")
+
+	graph := NewGraph[Counter]().
+		AddNode("before", increment).
+		AddSubgraph("inner", inner).
+		AddNode("after", increment).
+		AddEdge("before", "inner").
+		AddEdge("inner", "after").
+		AddEdge("after", END).
+		SetEntry("before")
+
+	compiled, err := graph.Compile()
+	require.NoError(t, err)
+	assert.True(t, compiled.IsSubgraph("inner"))
+	assert.False(t, compiled.IsSubgraph("before"))
+
+	result, err := compiled.Run(testCtx(), Counter{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Value)
+}
+
+// TestAddSubgraph_ErrorWrapsInnerNodeError tests error attribution across the boundary.
+func TestAddSubgraph_ErrorWrapsInnerNodeError(t *testing.T) {
+	innerErr := errors.New("inner failure")
+
+	inner, err := NewGraph[State]().
+		AddNode("ok", passthrough[State]).
+		AddNode("fail", makeFailingNode(innerErr)).
+		AddEdge("ok", "fail").
+		AddEdge("fail", END).
+		SetEntry("ok").
+		Compile()
+	require.NoError(t, err)
+
+	compiled, err := NewGraph[State]().
+		AddSubgraph("sub", inner).
+		AddEdge("sub", END).
+		SetEntry("sub").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), State{})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, innerErr)
+
+	var outer *NodeError
+	require.ErrorAs(t, err, &outer)
+	assert.Equal(t, "sub", outer.NodeID)
+	assert.Equal(t, "subgraph", outer.Op)
+
+	var innerNodeErr *NodeError
+	require.ErrorAs(t, outer.Err, &innerNodeErr)
+	assert.Equal(t, "fail", innerNodeErr.NodeID)
+}
+
+// TestAddSubgraph_CheckpointsNamespaced tests that inner checkpoints use a namespaced run ID.
+func TestAddSubgraph_CheckpointsNamespaced(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+
+	inner, err := NewGraph[Counter]().
+		AddNode("x", increment).
+		AddNode("y", func(ctx Context, s Counter) (Counter, error) {
+			infos, err := store.List("run-1/sub")
+			require.NoError(t, err)
+			require.Len(t, infos, 1, "inner checkpoint for x should exist under namespaced run ID")
+			assert.Equal(t, "x", infos[0].NodeID)
+			return increment(ctx, s)
+		}).
+		AddEdge("x", "y").
+		AddEdge("y", END).
+		SetEntry("x").
+		Compile()
+	require.NoError(t, err)
+
+	compiled, err := NewGraph[Counter]().
+		AddSubgraph("sub", inner).
+		AddEdge("sub", END).
+		SetEntry("sub").
+		Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), Counter{},
+		WithCheckpointing(store), WithRunID("run-1"))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Value)
+
+	// Outer checkpoint recorded under the outer run ID
+	outerInfos, err := store.List("run-1")
+	require.NoError(t, err)
+	require.Len(t, outerInfos, 1)
+	assert.Equal(t, "sub", outerInfos[0].NodeID)
+
+	// Inner checkpoints cleaned up after the subgraph completes
+	innerInfos, err := store.List("run-1/sub")
+	require.NoError(t, err)
+	assert.Empty(t, innerInfos)
+}
+
+// TestAddSubgraph_ResumeAcrossBoundary tests resuming a run that crashed inside a subgraph.
+func TestAddSubgraph_ResumeAcrossBoundary(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	crash := true
+	var executed []string
+
+	track := func(name string) NodeFunc[Counter] {
+		return func(ctx Context, s Counter) (Counter, error) {
+			executed = append(executed, name)
+			return increment(ctx, s)
+		}
+	}
+
+	inner, err := NewGraph[Counter]().
+		AddNode("x", track("x")).
+		AddNode("y", func(ctx Context, s Counter) (Counter, error) {
+			if crash {
+				return s, errors.New("simulated crash")
+			}
+			return track("y")(ctx, s)
+		}).
+		AddEdge("x", "y").
+		AddEdge("y", END).
+		SetEntry("x").
+		Compile()
+	require.NoError(t, err)
+
+	compiled, err := NewGraph[Counter]().
+		AddNode("a", track("a")).
+		AddSubgraph("sub", inner).
+		AddNode("c", track("c")).
+		AddEdge("a", "sub").
+		AddEdge("sub", "c").
+		AddEdge("c", END).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), Counter{},
+		WithCheckpointing(store), WithRunID("run-1"))
+	require.Error(t, err)
+	assert.Equal(t, []string{"a", "x"}, executed)
+
+	crash = false
+	result, err := compiled.Resume(testCtx(), store, "run-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Value)
+	// Neither "a" nor the inner "x" re-execute
+	assert.Equal(t, []string{"a", "x", "y", "c"}, executed)
+}
+
+// TestAddSubgraph_RetryRestartsFromEntry tests that a subgraph that fails
+// once and is retried starts again at its entry instead of resuming the
+// checkpoints of the failed attempt.
+func TestAddSubgraph_RetryRestartsFromEntry(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	failures := 1
+	var executed []string
+
+	inner, err := NewGraph[Counter]().
+		AddNode("x", func(ctx Context, s Counter) (Counter, error) {
+			executed = append(executed, "x")
+			return increment(ctx, s)
+		}).
+		AddNode("y", func(ctx Context, s Counter) (Counter, error) {
+			executed = append(executed, "y")
+			if failures > 0 {
+				failures--
+				return s, fgerrors.Transient(errors.New("unavailable"), "y")
+			}
+			return increment(ctx, s)
+		}).
+		AddEdge("x", "y").
+		AddEdge("y", END).
+		SetEntry("x").
+		Compile()
+	require.NoError(t, err)
+
+	compiled, err := NewGraph[Counter]().
+		AddSubgraph("sub", inner, WithNodeRetry(fastRetry)).
+		AddEdge("sub", END).
+		SetEntry("sub").
+		Compile()
+	require.NoError(t, err)
+
+	result, err := compiled.Run(testCtx(), Counter{},
+		WithCheckpointing(store), WithRunID("run-1"))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Value)
+	assert.Equal(t, []string{"x", "y", "x", "y"}, executed)
+}
+
+// TestAddSubgraph_RerunIgnoresStaleCheckpoints tests that a new run with
+// the same run ID does not resume a subgraph that failed in an earlier run.
+func TestAddSubgraph_RerunIgnoresStaleCheckpoints(t *testing.T) {
+	store := checkpoint.NewMemoryStore()
+	crash := true
+
+	inner, err := NewGraph[Counter]().
+		AddNode("x", increment).
+		AddNode("y", func(ctx Context, s Counter) (Counter, error) {
+			if crash {
+				return s, errors.New("simulated crash")
+			}
+			return increment(ctx, s)
+		}).
+		AddEdge("x", "y").
+		AddEdge("y", END).
+		SetEntry("x").
+		Compile()
+	require.NoError(t, err)
+
+	compiled, err := NewGraph[Counter]().
+		AddSubgraph("sub", inner).
+		AddEdge("sub", END).
+		SetEntry("sub").
+		Compile()
+	require.NoError(t, err)
+
+	_, err = compiled.Run(testCtx(), Counter{},
+		WithCheckpointing(store), WithRunID("run-1"))
+	require.Error(t, err)
+
+	crash = false
+	result, err := compiled.Run(testCtx(), Counter{Value: 10},
+		WithCheckpointing(store), WithRunID("run-1"))
+
+	require.NoError(t, err)
+	// Started from the new input at "x", not from the stale checkpoint at "x"
+	assert.Equal(t, 12, result.Value)
+}
+
+// TestAddSubgraph_NilPanics tests that a nil subgraph panics.
+func TestAddSubgraph_NilPanics(t *testing.T) {
+	assert.PanicsWithValue(t, "flowgraph: subgraph cannot be nil", func() {
+		NewGraph[Counter]().AddSubgraph("sub", nil)
+	})
+}
@@ -0,0 +1,170 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Default operators for ${name:-default} and ${name:=default}.
+const (
+	defaultIfEmpty   = ":-" // Use the default if the variable is missing or empty
+	defaultIfMissing = ":=" // Use the default only if the variable is missing
+)
+
+// braceRef is a parsed ${...} reference.
+type braceRef struct {
+	name       string
+	op         string // "", defaultIfEmpty, or defaultIfMissing
+	defaultVal string // Unescaped default text, still to be expanded
+	call       bool   // Whether this is a ${name(...)} function call
+	args       []callArg
+}
+
+// parseBraceRef parses the reference starting with "${" at s[start:].
+// Returns the reference and the index just past its closing brace, or
+// ok=false if s[start:] does not begin a well-formed reference.
+//
+// A name followed by "(" is a function call; see parseCallArgs.
+//
+// Within a default, `\}` is a literal "}" and `\\` a literal backslash;
+// nested ${...} references are kept verbatim for later expansion. Only the
+// first ":-" or ":=" after the name is an operator, so later occurrences in
+// the default are literal text.
+func parseBraceRef(s string, start int) (ref braceRef, end int, ok bool) {
+	i := start + 2 // Skip "${"
+	nameStart := i
+	if i >= len(s) || !isNameStart(s[i]) {
+		return braceRef{}, 0, false
+	}
+	for i < len(s) && isNameChar(s[i]) {
+		i++
+	}
+	ref.name = s[nameStart:i]
+
+	if i < len(s) && s[i] == '}' {
+		return ref, i + 1, true
+	}
+
+	if i < len(s) && s[i] == '(' {
+		args, argsEnd, argsOK := parseCallArgs(s, i)
+		if !argsOK || argsEnd >= len(s) || s[argsEnd] != '}' {
+			return braceRef{}, 0, false
+		}
+		ref.call = true
+		ref.args = args
+		return ref, argsEnd + 1, true
+	}
+
+	switch {
+	case strings.HasPrefix(s[i:], defaultIfEmpty):
+		ref.op = defaultIfEmpty
+	case strings.HasPrefix(s[i:], defaultIfMissing):
+		ref.op = defaultIfMissing
+	default:
+		return braceRef{}, 0, false
+	}
+	i += len(ref.op)
+
+	var def strings.Builder
+	for i < len(s) {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && (s[i+1] == '}' || s[i+1] == '\\'):
+			def.WriteByte(s[i+1])
+			i += 2
+		case strings.HasPrefix(s[i:], "${"):
+			if _, nestedEnd, nestedOK := parseBraceRef(s, i); nestedOK {
+				def.WriteString(s[i:nestedEnd])
+				i = nestedEnd
+			} else {
+				def.WriteByte(s[i])
+				i++
+			}
+		case s[i] == '}':
+			ref.defaultVal = def.String()
+			return ref, i + 1, true
+		default:
+			def.WriteByte(s[i])
+			i++
+		}
+	}
+	return braceRef{}, 0, false // Unterminated
+}
+
+// expandBraces replaces each ${...} reference in s. Names of variables that
+// are missing with no default are appended to missing when MissingAction
+// is MissingError. chain holds the variables whose values are being
+// expanded under WithRecursiveExpansion. Returns an error only if a
+// function call fails or a variable references itself.
+func (e *Expander) expandBraces(s string, vars map[string]any, missing *[]string, chain []string) (string, error) {
+	var b strings.Builder
+	i := 0
+	for {
+		j := strings.Index(s[i:], "${")
+		if j < 0 {
+			b.WriteString(s[i:])
+			return b.String(), nil
+		}
+		start := i + j
+		b.WriteString(s[i:start])
+
+		ref, end, ok := parseBraceRef(s, start)
+		if !ok {
+			b.WriteByte('$')
+			i = start + 1
+			continue
+		}
+		val, err := e.resolveBraceRef(ref, s[start:end], vars, missing, chain)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(val)
+		i = end
+	}
+}
+
+// resolveBraceRef returns the replacement text for a single reference.
+// An explicit default takes precedence over the MissingAction.
+func (e *Expander) resolveBraceRef(ref braceRef, match string, vars map[string]any, missing *[]string, chain []string) (string, error) {
+	if ref.call {
+		return e.callFunc(ref, vars)
+	}
+
+	val, found := lookupNested(vars, ref.name)
+	switch ref.op {
+	case defaultIfEmpty:
+		if !found || isEmpty(val) {
+			return e.expandBraces(ref.defaultVal, vars, missing, chain)
+		}
+	case defaultIfMissing:
+		if !found {
+			return e.expandBraces(ref.defaultVal, vars, missing, chain)
+		}
+	}
+	if found {
+		return e.expandVariable(ref.name, fmt.Sprintf("%v", val), vars, missing, chain)
+	}
+
+	// Variable not found.
+	switch e.missingAction {
+	case MissingEmpty:
+		return "", nil
+	case MissingError:
+		*missing = append(*missing, ref.name)
+		return match, nil // Keep for now, will return error.
+	default: // MissingKeep
+		return match, nil
+	}
+}
+
+// isEmpty reports whether a value counts as empty for ${name:-default}.
+func isEmpty(val any) bool {
+	return val == nil || val == ""
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9') || c == '.'
+}
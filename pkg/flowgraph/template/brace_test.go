@@ -0,0 +1,194 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExpand_Defaults tests ${name:-default} and ${name:=default}.
+func TestExpand_Defaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		vars     map[string]any
+		expected string
+	}{
+		{
+			name:     "missing uses default",
+			input:    "${region:-us-east-1}",
+			vars:     nil,
+			expected: "us-east-1",
+		},
+		{
+			name:     "set ignores default",
+			input:    "${region:-us-east-1}",
+			vars:     map[string]any{"region": "eu-west-1"},
+			expected: "eu-west-1",
+		},
+		{
+			name:     "empty uses :- default",
+			input:    "${region:-us-east-1}",
+			vars:     map[string]any{"region": ""},
+			expected: "us-east-1",
+		},
+		{
+			name:     "nil uses :- default",
+			input:    "${region:-us-east-1}",
+			vars:     map[string]any{"region": nil},
+			expected: "us-east-1",
+		},
+		{
+			name:     "empty keeps value with :=",
+			input:    "[${region:=us-east-1}]",
+			vars:     map[string]any{"region": ""},
+			expected: "[]",
+		},
+		{
+			name:     "missing uses := default",
+			input:    "${region:=us-east-1}",
+			vars:     nil,
+			expected: "us-east-1",
+		},
+		{
+			name:     "zero is not empty",
+			input:    "${retries:-3}",
+			vars:     map[string]any{"retries": 0},
+			expected: "0",
+		},
+		{
+			name:     "empty default",
+			input:    "a${suffix:-}b",
+			vars:     nil,
+			expected: "ab",
+		},
+		{
+			name:     "default references another variable",
+			input:    "${host:-${fallback}}:8080",
+			vars:     map[string]any{"fallback": "localhost"},
+			expected: "localhost:8080",
+		},
+		{
+			name:     "nested defaults",
+			input:    "${a:-${b:-${c:-none}}}",
+			vars:     nil,
+			expected: "none",
+		},
+		{
+			name:     "default with text and reference",
+			input:    "${url:-https://${host}/api}",
+			vars:     map[string]any{"host": "example.com"},
+			expected: "https://example.com/api",
+		},
+		{
+			name:     "dot notation with default",
+			input:    "${user.name:-anonymous}",
+			vars:     map[string]any{"user": map[string]any{}},
+			expected: "anonymous",
+		},
+		{
+			name:     "literal :- in default",
+			input:    "${range:-1:-5}",
+			vars:     nil,
+			expected: "1:-5",
+		},
+		{
+			name:     "escaped closing brace in default",
+			input:    `${json:-{"a":1\}}`,
+			vars:     nil,
+			expected: `{"a":1}`,
+		},
+		{
+			name:     "escaped backslash in default",
+			input:    `${path:-C:\\temp}`,
+			vars:     nil,
+			expected: `C:\temp`,
+		},
+		{
+			name:     "other backslashes kept",
+			input:    `${pattern:-\d+}`,
+			vars:     nil,
+			expected: `\d+`,
+		},
+		{
+			name:     "dollar variable in default",
+			input:    "${port:-$fallback}",
+			vars:     map[string]any{"fallback": 80},
+			expected: "80",
+		},
+		{
+			name:     "unterminated default left as-is",
+			input:    "${name:-oops",
+			vars:     nil,
+			expected: "${name:-oops",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewExpander(WithMissingAction(MissingError)).Expand(tt.input, tt.vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+// TestExpand_DefaultsWithMissingAction tests that defaults take precedence
+// over the missing action, while missing variables inside a default still
+// follow it.
+func TestExpand_DefaultsWithMissingAction(t *testing.T) {
+	t.Run("MissingError", func(t *testing.T) {
+		exp := NewExpander(WithMissingAction(MissingError))
+
+		result, err := exp.Expand("${a:-x}", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "x", result)
+
+		_, err = exp.Expand("${a:-${b}}", nil)
+		var undefinedErr *UndefinedVariableError
+		require.ErrorAs(t, err, &undefinedErr)
+		assert.Equal(t, []string{"b"}, undefinedErr.Names)
+	})
+
+	t.Run("MissingKeep", func(t *testing.T) {
+		assert.Equal(t, "x-${b}", Expand("${a:-x-${b}}", nil))
+	})
+
+	t.Run("MissingEmpty", func(t *testing.T) {
+		exp := NewExpander(WithMissingAction(MissingEmpty))
+		result, err := exp.Expand("${a:-x-${b}}", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "x-", result)
+	})
+}
+
+// TestParseBraceRef tests parsing of individual ${...} references.
+func TestParseBraceRef(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantOK  bool
+		wantRef braceRef
+		wantEnd int
+	}{
+		{"${a}", true, braceRef{name: "a"}, 4},
+		{"${a.b}rest", true, braceRef{name: "a.b"}, 6},
+		{"${a:-x}", true, braceRef{name: "a", op: defaultIfEmpty, defaultVal: "x"}, 7},
+		{"${a:=x}", true, braceRef{name: "a", op: defaultIfMissing, defaultVal: "x"}, 7},
+		{"${a:-${b}}", true, braceRef{name: "a", op: defaultIfEmpty, defaultVal: "${b}"}, 10},
+		{`${a:-\}}`, true, braceRef{name: "a", op: defaultIfEmpty, defaultVal: "}"}, 8},
+		{"${}", false, braceRef{}, 0},
+		{"${1a}", false, braceRef{}, 0},
+		{"${a:x}", false, braceRef{}, 0},
+		{"${a", false, braceRef{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			ref, end, ok := parseBraceRef(tt.input, 0)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantRef, ref)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
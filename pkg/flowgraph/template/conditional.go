@@ -0,0 +1,82 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph/expr"
+)
+
+// ErrMalformedBlock is returned when ${if:...}, ${else}, and ${end} tags
+// are not properly balanced.
+var ErrMalformedBlock = errors.New("malformed conditional block")
+
+// blockPattern matches the conditional block tags ${if:name}, ${else}, and ${end}.
+var blockPattern = regexp.MustCompile(`\$\{(?:if:([a-zA-Z_][a-zA-Z0-9_.]*)|(else)|(end))\}`)
+
+// blockFrame tracks one open ${if:...} block while rendering.
+type blockFrame struct {
+	parentActive bool // Whether the enclosing text is being included
+	cond         bool // Whether the flag resolved truthy
+	inElse       bool // Whether ${else} has been seen
+}
+
+// expandConditionals resolves ${if:flag}...${else}...${end} blocks in s,
+// keeping only the text of the taken branches. Flags are looked up like
+// ${var} (including dot notation) and tested with expr.IsTruthy; a missing
+// flag is false.
+//
+// Strings without an ${if:...} tag are returned unchanged, so ${else} and
+// ${end} are only reserved in templates that use conditionals.
+func expandConditionals(s string, vars map[string]any) (string, error) {
+	if !strings.Contains(s, "${if:") {
+		return s, nil
+	}
+
+	var (
+		b      strings.Builder
+		stack  []blockFrame
+		active = true
+		last   = 0
+	)
+	for _, m := range blockPattern.FindAllStringSubmatchIndex(s, -1) {
+		if active {
+			b.WriteString(s[last:m[0]])
+		}
+		last = m[1]
+
+		switch {
+		case m[2] >= 0: // ${if:flag}
+			val, _ := lookupNested(vars, s[m[2]:m[3]])
+			frame := blockFrame{parentActive: active, cond: expr.IsTruthy(val)}
+			stack = append(stack, frame)
+			active = frame.parentActive && frame.cond
+
+		case m[4] >= 0: // ${else}
+			if len(stack) == 0 {
+				return s, fmt.Errorf("%w: ${else} without ${if:...} at offset %d", ErrMalformedBlock, m[0])
+			}
+			top := &stack[len(stack)-1]
+			if top.inElse {
+				return s, fmt.Errorf("%w: duplicate ${else} at offset %d", ErrMalformedBlock, m[0])
+			}
+			top.inElse = true
+			active = top.parentActive && !top.cond
+
+		default: // ${end}
+			if len(stack) == 0 {
+				return s, fmt.Errorf("%w: ${end} without ${if:...} at offset %d", ErrMalformedBlock, m[0])
+			}
+			active = stack[len(stack)-1].parentActive
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if len(stack) > 0 {
+		return s, fmt.Errorf("%w: %d unclosed ${if:...} block(s)", ErrMalformedBlock, len(stack))
+	}
+
+	b.WriteString(s[last:])
+	return b.String(), nil
+}
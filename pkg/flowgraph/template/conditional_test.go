@@ -0,0 +1,134 @@
+package template
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExpand_Conditionals tests ${if:flag}...${else}...${end} blocks.
+func TestExpand_Conditionals(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		vars     map[string]any
+		expected string
+	}{
+		{
+			name:     "truthy flag includes block",
+			input:    "/items${if:limit}?limit=${limit}${end}",
+			vars:     map[string]any{"limit": 10},
+			expected: "/items?limit=10",
+		},
+		{
+			name:     "missing flag excludes block",
+			input:    "/items${if:limit}?limit=${limit}${end}",
+			vars:     map[string]any{},
+			expected: "/items",
+		},
+		{
+			name:     "falsy values exclude block",
+			input:    "${if:a}a${end}${if:b}b${end}${if:c}c${end}${if:d}d${end}",
+			vars:     map[string]any{"a": false, "b": "", "c": 0, "d": nil},
+			expected: "",
+		},
+		{
+			name:     "else branch taken",
+			input:    "${if:admin}admin${else}guest${end}",
+			vars:     map[string]any{"admin": false},
+			expected: "guest",
+		},
+		{
+			name:     "else branch skipped",
+			input:    "${if:admin}admin${else}guest${end}",
+			vars:     map[string]any{"admin": true},
+			expected: "admin",
+		},
+		{
+			name:     "nested blocks both true",
+			input:    "a${if:x}b${if:y}c${end}d${end}e",
+			vars:     map[string]any{"x": true, "y": true},
+			expected: "abcde",
+		},
+		{
+			name:     "nested inner false",
+			input:    "a${if:x}b${if:y}c${else}C${end}d${end}e",
+			vars:     map[string]any{"x": true, "y": false},
+			expected: "abCde",
+		},
+		{
+			name:     "nested outer false hides inner else",
+			input:    "a${if:x}b${if:y}c${else}C${end}d${else}X${end}e",
+			vars:     map[string]any{"x": false, "y": false},
+			expected: "aXe",
+		},
+		{
+			name:     "dot notation flag",
+			input:    "${if:user.admin}root${else}user${end}",
+			vars:     map[string]any{"user": map[string]any{"admin": true}},
+			expected: "root",
+		},
+		{
+			name:     "end without if is a variable",
+			input:    "${start}-${end}",
+			vars:     map[string]any{"start": 1, "end": 5},
+			expected: "1-5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewExpander().Expand(tt.input, tt.vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+// TestExpand_ConditionalSkipsMissingVariables tests that variables in an
+// excluded branch are not reported missing.
+func TestExpand_ConditionalSkipsMissingVariables(t *testing.T) {
+	exp := NewExpander(WithMissingAction(MissingError))
+
+	result, err := exp.Expand("q=${q}${if:page}&page=${page}${end}", map[string]any{"q": "go"})
+	require.NoError(t, err)
+	assert.Equal(t, "q=go", result)
+
+	_, err = exp.Expand("${if:on}${missing}${end}", map[string]any{"on": true})
+	var undefinedErr *UndefinedVariableError
+	require.ErrorAs(t, err, &undefinedErr)
+	assert.Equal(t, []string{"missing"}, undefinedErr.Names)
+}
+
+// TestExpand_MalformedBlocks tests that unbalanced block tags are rejected.
+func TestExpand_MalformedBlocks(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"unclosed if", "${if:a}text"},
+		{"stray end", "${if:a}x${end}${end}"},
+		{"stray else", "${if:a}x${end}${else}"},
+		{"duplicate else", "${if:a}x${else}y${else}z${end}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewExpander().Expand(tt.input, map[string]any{"a": true})
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrMalformedBlock))
+			assert.Equal(t, tt.input, result)
+		})
+	}
+}
+
+// TestExpand_ConditionalsDisabledWithBraceStyle tests that block tags are
+// left alone when brace style is disabled.
+func TestExpand_ConditionalsDisabledWithBraceStyle(t *testing.T) {
+	exp := NewExpander(WithBraceStyle(false))
+	result, err := exp.Expand("${if:a}x${end}", map[string]any{"a": false})
+	require.NoError(t, err)
+	assert.Equal(t, "${if:a}x${end}", result)
+}
@@ -30,6 +30,75 @@ Two patterns are supported:
 The dollar style uses word boundary detection to avoid partial matches.
 For example, $port won't match inside $portNumber.
 
+# Default Values
+
+Shell-style defaults avoid pre-populating optional keys:
+
+  - ${name:-default} - use default if name is missing or empty ("" or nil)
+  - ${name:=default} - use default only if name is missing
+
+For example:
+
+	template.Expand("https://${host:-localhost}:${port:=8080}", nil)
+	// result: "https://localhost:8080"
+
+Defaults are expanded too, so they may reference other variables or nest
+further defaults:
+
+	template.Expand("${endpoint:-https://${host:-localhost}/api}", nil)
+	// result: "https://localhost/api"
+
+An explicit default takes precedence over the MissingAction, including
+MissingError. Variables referenced inside a default that is used are
+subject to the MissingAction as usual.
+
+Escaping inside a default:
+
+  - Only the first ":-" or ":=" after the name is an operator, so a literal
+    ":-" in the default needs no escape: ${range:-1:-5} yields "1:-5"
+  - \} is a literal "}" and \\ a literal backslash: ${json:-{"a":1\}}
+  - Other backslashes are kept as-is
+  - A default with no closing brace leaves the whole reference unexpanded
+
+# Functions
+
+${name(arg, ...)} calls a function and substitutes its result. Arguments
+are variables, resolved like ${var} (nil if missing), or single- or
+double-quoted literals in which \ escapes the next character:
+
+	template.Expand(`https://${lower(host)}/${default(region, "us-east")}`, vars)
+
+The built-ins are:
+
+  - upper(s), lower(s), trim(s) - change case or trim surrounding whitespace
+  - default(value, fallback) - fallback if value is missing or empty
+
+Register more with WithTemplateFunc. A function that fails, or is not
+registered, makes Expand return a *FuncError (wrapping ErrUnknownFunction
+for the latter):
+
+	exp := template.NewExpander(template.WithTemplateFunc("quote",
+	    func(args ...any) (any, error) {
+	        return strconv.Quote(fmt.Sprint(args[0])), nil
+	    }))
+
+# Conditional Sections
+
+With brace style enabled, ${if:flag}...${end} includes the enclosed text
+only when flag resolves truthy, using the same rules as expr.IsTruthy
+(nil, false, "", and zero are false). A missing flag is false, even with
+MissingError. Blocks may be nested and may have an ${else} branch:
+
+	tmpl := "https://${host}/search?q=${q}${if:page}&page=${page}${end}"
+	template.Expand(tmpl, map[string]any{"host": "api.example.com", "q": "go"})
+	// result: "https://api.example.com/search?q=go"
+
+	template.Expand("${if:user.admin}admin${else}guest${end}", vars)
+
+Block tags are resolved before variables, so variables inside an excluded
+branch are never reported missing. In a template that uses ${if:...},
+${else} and ${end} are reserved; unbalanced tags return ErrMalformedBlock.
+
 # Missing Variables
 
 By default, missing variables are kept as-is:
@@ -47,6 +116,36 @@ Configure behavior with options:
 	_, err := exp.Expand("Hello ${missing}", nil)
 	// err: "undefined variable: missing"
 
+MissingError scans the whole string rather than stopping at the first
+missing variable. The error lists every missing name once, in order of
+appearance, and the partially expanded result is returned alongside it:
+
+	preview, err := exp.Expand("${scheme}://${host}/${path}", map[string]any{"host": "api"})
+	// preview: "${scheme}://api/${path}"
+	// err: "undefined variables: scheme, path"
+
+# Escaping
+
+By default there is no way to emit a literal ${name}, and "$$var" expands
+to "$" followed by the value of var. Enable escapes with WithEscaping:
+
+	exp := template.NewExpander(template.WithEscaping(true))
+	result, _ := exp.Expand(`\${a}${b} costs $$5`, map[string]any{"b": "x"})
+	// result: "${a}x costs $5"
+
+With escaping enabled, \$ and $$ produce a literal "$" that never starts a
+variable, and \\ produces a literal backslash. Other backslashes are kept.
+
+# Recursive Expansion
+
+Variable values are substituted as-is by default. WithRecursiveExpansion
+also expands ${...} references inside values, up to a depth limit, and
+returns a *CycleError if a variable references itself:
+
+	exp := template.NewExpander(template.WithRecursiveExpansion(5))
+	result, _ := exp.Expand("${a}", map[string]any{"a": "${b}", "b": "done"})
+	// result: "done"
+
 # Batch Expansion
 
 Expand multiple strings or maps efficiently:
@@ -67,6 +166,14 @@ Expand multiple strings or maps efficiently:
 	    },
 	}, vars)
 
+# Listing References
+
+References lists the variables a template uses, which helps to resolve
+only those variables or to order templates that depend on each other:
+
+	template.References("${scheme:-https}://${host}")
+	// ["scheme", "host"]
+
 # Custom Expander
 
 Create a custom expander for advanced scenarios:
@@ -0,0 +1,40 @@
+package template
+
+import "strings"
+
+// Placeholders for escaped characters while a template is expanded. They
+// are Unicode private-use characters, so they cannot form part of a
+// variable reference and are not expected in real templates.
+const (
+	escapedDollar    = "\uE000"
+	escapedBackslash = "\uE001"
+)
+
+// restoreEscapes turns escape placeholders back into literal characters.
+var restoreEscapes = strings.NewReplacer(escapedDollar, "$", escapedBackslash, `\`)
+
+// protectEscapes replaces the escape sequences \$, $$, and \\ with
+// placeholders so that no expansion pass treats them as variable syntax.
+func protectEscapes(s string) string {
+	if !strings.ContainsAny(s, `\$`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if i+1 < len(s) {
+			switch s[i : i+2] {
+			case `\$`, "$$":
+				b.WriteString(escapedDollar)
+				i++
+				continue
+			case `\\`:
+				b.WriteString(escapedBackslash)
+				i++
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
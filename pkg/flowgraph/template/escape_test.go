@@ -0,0 +1,61 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExpand_Escaping tests escape sequences with WithEscaping enabled.
+func TestExpand_Escaping(t *testing.T) {
+	vars := map[string]any{"a": "A", "b": "B", "var": "value"}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"escaped brace", `\${foo}`, "${foo}"},
+		{"escaped dollar", `\$foo`, "$foo"},
+		{"escape adjacent to variable", `\${a}${b}`, "${a}B"},
+		{"variable adjacent to escape", `${a}\${b}`, "A${b}"},
+		{"escaped dollar style adjacent", `\$a$b`, "$aB"},
+		{"double dollar", "$$var", "$var"},
+		{"double dollar before brace", "$${a}", "${a}"},
+		{"price", "costs $$5", "costs $5"},
+		{"triple dollar", "$$$var", "$value"},
+		{"escaped backslash before variable", `\\${a}`, `\A`},
+		{"other backslashes kept", `C:\temp\${a}`, `C:\temp${a}`},
+		{"escaped conditional", `\${if:a}x\${end}`, "${if:a}x${end}"},
+		{"escape inside default", `${missing:-\${a}}`, "${a}"},
+		{"trailing backslash", `${a}\`, `A\`},
+	}
+
+	exp := NewExpander(WithEscaping(true), WithMissingAction(MissingError))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := exp.Expand(tt.input, vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+// TestExpand_EscapingDisabledByDefault tests that escapes are not
+// interpreted unless enabled.
+func TestExpand_EscapingDisabledByDefault(t *testing.T) {
+	vars := map[string]any{"var": "value", "foo": "bar"}
+
+	assert.Equal(t, "$value", Expand("$$var", vars))
+	assert.Equal(t, `\bar`, Expand(`\${foo}`, vars))
+}
+
+// TestExpand_EscapingWithMissingKeep tests that kept placeholders and
+// escapes are both restored in the output.
+func TestExpand_EscapingWithMissingKeep(t *testing.T) {
+	exp := NewExpander(WithEscaping(true))
+	result, err := exp.Expand(`${missing} \${literal} $$`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "${missing} ${literal} $", result)
+}
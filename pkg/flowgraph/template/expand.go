@@ -3,20 +3,17 @@ package template
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 )
 
-// Regular expressions for variable patterns.
-var (
-	// bracePattern matches ${varname} or ${object.property} - supports dot notation for nested access.
-	// Variable names can contain alphanumeric, underscore, and dots for hierarchical access.
-	bracePattern = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_.]*)\}`)
-
-	// dollarPattern matches $varname where varname is followed by a non-word character
-	// or end of string. This prevents $port from matching inside $portNumber.
-	// Does not support dot notation to avoid ambiguity with sentence endings.
-	dollarPattern = regexp.MustCompile(`\$([a-zA-Z_][a-zA-Z0-9_]*)(?:\b|$)`)
-)
+// dollarPattern matches $varname where varname is followed by a non-word character
+// or end of string. This prevents $port from matching inside $portNumber.
+// Does not support dot notation to avoid ambiguity with sentence endings.
+//
+// ${...} references are parsed by parseBraceRef rather than a regular
+// expression, since defaults may contain nested references.
+var dollarPattern = regexp.MustCompile(`\$([a-zA-Z_][a-zA-Z0-9_]*)(?:\b|$)`)
 
 // Expander expands variable patterns in strings.
 //
@@ -26,6 +23,9 @@ type Expander struct {
 	missingAction MissingAction
 	braceStyle    bool
 	dollarStyle   bool
+	escaping      bool
+	maxDepth      int
+	funcs         map[string]Func
 }
 
 // NewExpander creates a new Expander with the given options.
@@ -34,6 +34,8 @@ type Expander struct {
 //   - MissingAction: MissingKeep (keep placeholders as-is)
 //   - BraceStyle: enabled (${var})
 //   - DollarStyle: enabled ($var)
+//   - Escaping: disabled
+//   - Functions: the built-ins upper, lower, trim, and default
 //
 // Example:
 //
@@ -47,6 +49,10 @@ func NewExpander(opts ...Option) *Expander {
 		missingAction: MissingKeep,
 		braceStyle:    true,
 		dollarStyle:   true,
+		funcs:         make(map[string]Func, len(builtinFuncs)),
+	}
+	for name, fn := range builtinFuncs {
+		e.funcs[name] = fn
 	}
 	for _, opt := range opts {
 		opt(e)
@@ -57,8 +63,36 @@ func NewExpander(opts ...Option) *Expander {
 // Expand expands variable patterns in s using the provided vars.
 //
 // Returns the expanded string and any error encountered.
-// Errors are only returned when MissingAction is MissingError and
-// a variable is not found.
+// Errors are returned when MissingAction is MissingError and a variable
+// is not found, when conditional blocks are unbalanced (ErrMalformedBlock),
+// when a function call fails (*FuncError), or when a variable references
+// itself under WithRecursiveExpansion (*CycleError). In the last three
+// cases s is returned unchanged.
+//
+// With MissingError the whole string is still expanded: the result has
+// every variable that could be resolved substituted and the missing ones
+// kept as placeholders, and the *UndefinedVariableError lists each missing
+// name once, in order of first appearance.
+//
+// With brace style enabled, ${if:flag}...${else}...${end} blocks include
+// their text only when flag resolves truthy (see expr.IsTruthy). A missing
+// flag is false. Blocks may be nested and ${else} is optional:
+//
+//	exp.Expand("/items${if:limit}?limit=${limit}${end}", vars)
+//
+// ${name:-default} uses default when name is missing or empty, and
+// ${name:=default} only when it is missing. Defaults may contain other
+// references and take precedence over MissingError:
+//
+//	exp.Expand("${region:-us-east-1}", nil)
+//	// result: "us-east-1"
+//
+// ${name(arg, ...)} calls a function registered with WithTemplateFunc or
+// one of the built-ins upper, lower, trim, and default. Arguments are
+// variables (nil if missing) or quoted literals:
+//
+//	exp.Expand(`${default(region, "us-east")}`, nil)
+//	// result: "us-east"
 //
 // Supports dot notation for nested object access:
 //
@@ -78,25 +112,26 @@ func (e *Expander) Expand(s string, vars map[string]any) (string, error) {
 	result := s
 	var missingVars []string
 
+	if e.escaping {
+		result = protectEscapes(result)
+	}
+	source := result
+
+	// Resolve ${if:flag}...${end} blocks before substituting variables, so
+	// variables in excluded text are never reported missing.
+	if e.braceStyle {
+		var err error
+		if result, err = expandConditionals(result, vars); err != nil {
+			return s, err
+		}
+	}
+
 	// Expand ${var} patterns first (more specific).
 	if e.braceStyle {
-		result = bracePattern.ReplaceAllStringFunc(result, func(match string) string {
-			// Extract variable name from ${name}.
-			varName := match[2 : len(match)-1]
-			if val, ok := lookupNested(vars, varName); ok {
-				return fmt.Sprintf("%v", val)
-			}
-			// Variable not found.
-			switch e.missingAction {
-			case MissingEmpty:
-				return ""
-			case MissingError:
-				missingVars = append(missingVars, varName)
-				return match // Keep for now, will return error.
-			default: // MissingKeep
-				return match
-			}
-		})
+		var err error
+		if result, err = e.expandBraces(result, vars, &missingVars, nil); err != nil {
+			return s, err
+		}
 	}
 
 	// Expand $var patterns (less specific, after braces).
@@ -120,8 +155,12 @@ func (e *Expander) Expand(s string, vars map[string]any) (string, error) {
 		})
 	}
 
+	if e.escaping {
+		result = restoreEscapes.Replace(result)
+	}
+
 	if len(missingVars) > 0 {
-		return result, &UndefinedVariableError{Names: missingVars}
+		return result, &UndefinedVariableError{Names: appearanceOrder(source, missingVars)}
 	}
 
 	return result, nil
@@ -210,10 +249,54 @@ func (e *Expander) expandValue(v any, vars map[string]any) (any, error) {
 	}
 }
 
+// appearanceOrder returns names without duplicates, ordered by where each
+// is first referenced in s. Names not referenced in s itself (for example,
+// reached through a substituted value) keep their relative order at the end.
+func appearanceOrder(s string, names []string) []string {
+	pos := make(map[string]int, len(names))
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		if _, seen := pos[name]; !seen {
+			pos[name] = firstReference(s, name)
+			unique = append(unique, name)
+		}
+	}
+	sort.SliceStable(unique, func(i, j int) bool { return pos[unique[i]] < pos[unique[j]] })
+	return unique
+}
+
+// firstReference returns the index of the first ${name or $name reference
+// in s, or len(s) if there is none.
+func firstReference(s, name string) int {
+	first := len(s)
+	refs := []struct {
+		prefix string
+		inName func(byte) bool
+	}{
+		{"${" + name, isNameChar},
+		{"$" + name, func(c byte) bool { return isNameStart(c) || (c >= '0' && c <= '9') }},
+	}
+	for _, ref := range refs {
+		for i := 0; ; {
+			j := strings.Index(s[i:], ref.prefix)
+			if j < 0 {
+				break
+			}
+			end := i + j + len(ref.prefix)
+			if end == len(s) || !ref.inName(s[end]) {
+				first = min(first, i+j)
+				break
+			}
+			i = end
+		}
+	}
+	return first
+}
+
 // UndefinedVariableError is returned when MissingError is set and
 // one or more variables are not found.
 type UndefinedVariableError struct {
-	// Names is the list of undefined variable names.
+	// Names lists the undefined variable names in order of first appearance.
 	Names []string
 }
 
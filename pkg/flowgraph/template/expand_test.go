@@ -206,6 +206,17 @@ func TestExpand_MissingVariables(t *testing.T) {
 		assert.Contains(t, err.Error(), "undefined variables:")
 	})
 
+	t.Run("MissingError reports every name once in order", func(t *testing.T) {
+		exp := NewExpander(WithMissingAction(MissingError))
+		result, err := exp.Expand("$c ${a} ${host} ${b:-${d}} ${a} $c", map[string]any{"host": "example.com"})
+		require.Error(t, err)
+
+		var undefinedErr *UndefinedVariableError
+		require.ErrorAs(t, err, &undefinedErr)
+		assert.Equal(t, []string{"c", "a", "d"}, undefinedErr.Names)
+		assert.Equal(t, "$c ${a} example.com ${d} ${a} $c", result, "partial result is still returned")
+	})
+
 	t.Run("partial variables found", func(t *testing.T) {
 		exp := NewExpander(WithMissingAction(MissingError))
 		_, err := exp.Expand("${found} ${missing}", map[string]any{"found": "yes"})
@@ -0,0 +1,180 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownFunction is returned when a template calls a function that
+// is not registered with the Expander.
+var ErrUnknownFunction = errors.New("unknown function")
+
+// Func is a function callable from a template as ${name(arg, ...)}.
+// Arguments are variable values (nil if missing) or string literals.
+// The result is formatted with %v; nil becomes an empty string.
+type Func func(args ...any) (any, error)
+
+// FuncError is returned when a template function fails or is unknown.
+type FuncError struct {
+	// Name is the function name as written in the template.
+	Name string
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *FuncError) Error() string {
+	return fmt.Sprintf("template function %s: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FuncError) Unwrap() error {
+	return e.Err
+}
+
+// builtinFuncs are registered on every Expander.
+var builtinFuncs = map[string]Func{
+	"upper":   stringFunc(strings.ToUpper),
+	"lower":   stringFunc(strings.ToLower),
+	"trim":    stringFunc(strings.TrimSpace),
+	"default": defaultFunc,
+}
+
+// stringFunc adapts a string transform to a one-argument Func.
+// A nil argument is treated as "".
+func stringFunc(fn func(string) string) Func {
+	return func(args ...any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+		}
+		return fn(formatValue(args[0])), nil
+	}
+}
+
+// defaultFunc returns its first argument, or the second if the first is
+// missing or empty, like ${name:-default}.
+func defaultFunc(args ...any) (any, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("expected 2 arguments, got %d", len(args))
+	}
+	if isEmpty(args[0]) {
+		return args[1], nil
+	}
+	return args[0], nil
+}
+
+// formatValue formats a value for substitution, with nil as "".
+func formatValue(val any) string {
+	if val == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", val)
+}
+
+// callArg is one argument of a ${name(...)} call.
+type callArg struct {
+	name    string // Variable name, if not a literal
+	literal string
+	isLit   bool
+}
+
+// parseCallArgs parses a parenthesized argument list starting with "(" at
+// s[start:]. Arguments are variable names or single- or double-quoted
+// literals, in which \ escapes the next character. Returns the arguments
+// and the index just past ")", or ok=false if the list is malformed.
+func parseCallArgs(s string, start int) (args []callArg, end int, ok bool) {
+	i := skipSpaces(s, start+1) // Skip "("
+	if i < len(s) && s[i] == ')' {
+		return nil, i + 1, true
+	}
+
+	for i < len(s) {
+		var arg callArg
+		switch c := s[i]; {
+		case c == '"' || c == '\'':
+			var lit strings.Builder
+			i++
+			for i < len(s) && s[i] != c {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				lit.WriteByte(s[i])
+				i++
+			}
+			if i >= len(s) {
+				return nil, 0, false // Unterminated literal
+			}
+			i++ // Closing quote
+			arg = callArg{literal: lit.String(), isLit: true}
+		case isNameStart(c):
+			nameStart := i
+			for i < len(s) && isNameChar(s[i]) {
+				i++
+			}
+			arg = callArg{name: s[nameStart:i]}
+		default:
+			return nil, 0, false
+		}
+		args = append(args, arg)
+
+		i = skipSpaces(s, i)
+		if i >= len(s) {
+			return nil, 0, false
+		}
+		switch s[i] {
+		case ',':
+			i = skipSpaces(s, i+1)
+		case ')':
+			return args, i + 1, true
+		default:
+			return nil, 0, false
+		}
+	}
+	return nil, 0, false
+}
+
+// callFunc evaluates a ${name(...)} reference.
+func (e *Expander) callFunc(ref braceRef, vars map[string]any) (string, error) {
+	fn, ok := e.funcs[ref.name]
+	if !ok {
+		return "", &FuncError{Name: ref.name, Err: ErrUnknownFunction}
+	}
+
+	args := make([]any, len(ref.args))
+	for i, arg := range ref.args {
+		if arg.isLit {
+			args[i] = arg.literal
+		} else {
+			args[i], _ = lookupNested(vars, arg.name)
+		}
+	}
+
+	result, err := fn(args...)
+	if err != nil {
+		return "", &FuncError{Name: ref.name, Err: err}
+	}
+	return formatValue(result), nil
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+// isIdentifier reports whether name is usable as a function name.
+// Dots are not allowed, since they denote nested variable access.
+func isIdentifier(name string) bool {
+	if name == "" || !isNameStart(name[0]) {
+		return false
+	}
+	for i := 1; i < len(name); i++ {
+		if name[i] == '.' || !isNameChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,228 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExpand_BuiltinFuncs tests the built-in template functions.
+func TestExpand_BuiltinFuncs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		vars     map[string]any
+		expected string
+	}{
+		{
+			name:     "upper",
+			input:    "${upper(env)}",
+			vars:     map[string]any{"env": "prod"},
+			expected: "PROD",
+		},
+		{
+			name:     "lower",
+			input:    "${lower(env)}",
+			vars:     map[string]any{"env": "PROD"},
+			expected: "prod",
+		},
+		{
+			name:     "trim",
+			input:    "[${trim(name)}]",
+			vars:     map[string]any{"name": "  api \t"},
+			expected: "[api]",
+		},
+		{
+			name:     "default uses fallback when missing",
+			input:    `${default(region, "us-east")}`,
+			vars:     nil,
+			expected: "us-east",
+		},
+		{
+			name:     "default uses fallback when empty",
+			input:    `${default(region, "us-east")}`,
+			vars:     map[string]any{"region": ""},
+			expected: "us-east",
+		},
+		{
+			name:     "default keeps set value",
+			input:    `${default(region, "us-east")}`,
+			vars:     map[string]any{"region": "eu-west"},
+			expected: "eu-west",
+		},
+		{
+			name:     "default with variable fallback",
+			input:    "${default(region, fallback)}",
+			vars:     map[string]any{"fallback": "ap-south"},
+			expected: "ap-south",
+		},
+		{
+			name:     "single-quoted literal",
+			input:    "${upper('abc')}",
+			vars:     nil,
+			expected: "ABC",
+		},
+		{
+			name:     "escaped quote in literal",
+			input:    `${upper("say \"hi\"")}`,
+			vars:     nil,
+			expected: `SAY "HI"`,
+		},
+		{
+			name:     "literal with comma and paren",
+			input:    `${default(x, "a, (b)")}`,
+			vars:     nil,
+			expected: "a, (b)",
+		},
+		{
+			name:     "nested variable argument",
+			input:    "${upper(user.name)}",
+			vars:     map[string]any{"user": map[string]any{"name": "alice"}},
+			expected: "ALICE",
+		},
+		{
+			name:     "missing argument is empty",
+			input:    "[${upper(missing)}]",
+			vars:     nil,
+			expected: "[]",
+		},
+		{
+			name:     "non-string argument",
+			input:    "${lower(port)}",
+			vars:     map[string]any{"port": 8080},
+			expected: "8080",
+		},
+		{
+			name:     "surrounding text and spaces",
+			input:    `https://${lower( host )}/${default( path , "v1" )}`,
+			vars:     map[string]any{"host": "API.example.com"},
+			expected: "https://api.example.com/v1",
+		},
+		{
+			name:     "call inside a default",
+			input:    "${url:-https://${lower(host)}}",
+			vars:     map[string]any{"host": "EXAMPLE.com"},
+			expected: "https://example.com",
+		},
+		{
+			name:     "malformed call is kept",
+			input:    `${upper(env}`,
+			vars:     map[string]any{"env": "prod"},
+			expected: `${upper(env}`,
+		},
+		{
+			name:     "unterminated literal is kept",
+			input:    `${upper("env)}`,
+			vars:     nil,
+			expected: `${upper("env)}`,
+		},
+	}
+
+	exp := NewExpander()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := exp.Expand(tt.input, tt.vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+// TestWithTemplateFunc tests registering custom functions.
+func TestWithTemplateFunc(t *testing.T) {
+	join := func(args ...any) (any, error) {
+		parts := make([]string, len(args))
+		for i, a := range args {
+			parts[i] = fmt.Sprint(a)
+		}
+		return strings.Join(parts, "/"), nil
+	}
+	exp := NewExpander(WithTemplateFunc("join", join))
+
+	result, err := exp.Expand(`${join(host, "api", version)}`, map[string]any{"host": "example.com", "version": 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/api/2", result)
+}
+
+// TestWithTemplateFunc_OverridesBuiltin tests that a custom function
+// replaces a built-in of the same name.
+func TestWithTemplateFunc_OverridesBuiltin(t *testing.T) {
+	exp := NewExpander(WithTemplateFunc("upper", func(args ...any) (any, error) {
+		return "custom", nil
+	}))
+
+	result, err := exp.Expand("${upper(x)}", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "custom", result)
+
+	// Other expanders keep the built-in
+	result, err = NewExpander().Expand("${upper(x)}", map[string]any{"x": "a"})
+	require.NoError(t, err)
+	assert.Equal(t, "A", result)
+}
+
+// TestWithTemplateFunc_ZeroArgsAndNilResult tests calls without arguments
+// and functions that return nil.
+func TestWithTemplateFunc_ZeroArgsAndNilResult(t *testing.T) {
+	exp := NewExpander(WithTemplateFunc("nothing", func(args ...any) (any, error) {
+		assert.Empty(t, args)
+		return nil, nil
+	}))
+
+	result, err := exp.Expand("[${nothing()}]", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "[]", result)
+}
+
+// TestExpand_FuncErrors tests that function failures surface from Expand.
+func TestExpand_FuncErrors(t *testing.T) {
+	failErr := errors.New("lookup failed")
+	exp := NewExpander(WithTemplateFunc("fail", func(args ...any) (any, error) {
+		return nil, failErr
+	}))
+
+	t.Run("function error", func(t *testing.T) {
+		input := "host=${fail(x)}"
+		result, err := exp.Expand(input, nil)
+
+		require.ErrorIs(t, err, failErr)
+		var funcErr *FuncError
+		require.ErrorAs(t, err, &funcErr)
+		assert.Equal(t, "fail", funcErr.Name)
+		assert.Equal(t, input, result)
+	})
+
+	t.Run("unknown function", func(t *testing.T) {
+		_, err := exp.Expand("${nope(x)}", nil)
+		assert.ErrorIs(t, err, ErrUnknownFunction)
+	})
+
+	t.Run("wrong argument count", func(t *testing.T) {
+		_, err := exp.Expand(`${default("a")}`, nil)
+		var funcErr *FuncError
+		require.ErrorAs(t, err, &funcErr)
+		assert.Equal(t, "default", funcErr.Name)
+	})
+
+	t.Run("error inside taken default", func(t *testing.T) {
+		_, err := exp.Expand("${x:-${fail(y)}}", nil)
+		assert.ErrorIs(t, err, failErr)
+	})
+}
+
+// TestWithTemplateFunc_Panics tests validation of function registration.
+func TestWithTemplateFunc_Panics(t *testing.T) {
+	fn := func(args ...any) (any, error) { return nil, nil }
+
+	assert.Panics(t, func() { WithTemplateFunc("", fn) })
+	assert.Panics(t, func() { WithTemplateFunc("a.b", fn) })
+	assert.Panics(t, func() { WithTemplateFunc("1x", fn) })
+	assert.Panics(t, func() { WithTemplateFunc("ok", nil) })
+}
@@ -1,5 +1,7 @@
 package template
 
+import "fmt"
+
 // MissingAction specifies how to handle missing variables.
 type MissingAction int
 
@@ -63,3 +65,81 @@ func WithDollarStyle(enabled bool) Option {
 		e.dollarStyle = enabled
 	}
 }
+
+// WithEscaping enables escape sequences for literal characters:
+//
+//   - \${name} and \$name produce ${name} and $name unexpanded
+//   - $$ produces a single literal $
+//   - \\ produces a single literal backslash
+//
+// Other backslashes are kept as-is. Escapes apply everywhere in the
+// template, including inside ${name:-default} defaults.
+//
+// Default: false (disabled), so "$$var" expands to "$" followed by the
+// value of var.
+//
+// Example:
+//
+//	exp := NewExpander(WithEscaping(true))
+//	result, _ := exp.Expand(`\${name} is ${name}, costs $$5`, map[string]any{"name": "World"})
+//	// result: "${name} is World, costs $5"
+func WithEscaping(enabled bool) Option {
+	return func(e *Expander) {
+		e.escaping = enabled
+	}
+}
+
+// WithRecursiveExpansion expands ${...} references found in variable
+// values, so a value may be built from other variables. Values are
+// expanded up to maxDepth levels deep; references left beyond that are
+// kept as-is. A variable that references itself, directly or through
+// others, makes Expand return a *CycleError.
+//
+// Default: disabled (values are substituted as-is, in a single pass)
+//
+// Panics if maxDepth is not positive.
+//
+// Example:
+//
+//	exp := NewExpander(WithRecursiveExpansion(5))
+//	result, _ := exp.Expand("${url}", map[string]any{
+//	    "url":  "https://${host}/api",
+//	    "host": "example.com",
+//	})
+//	// result: "https://example.com/api"
+func WithRecursiveExpansion(maxDepth int) Option {
+	if maxDepth <= 0 {
+		panic("template: recursive expansion depth must be > 0")
+	}
+	return func(e *Expander) {
+		e.maxDepth = maxDepth
+	}
+}
+
+// WithTemplateFunc registers fn as a function callable from templates as
+// ${name(arg, ...)}, replacing any built-in or earlier function with the
+// same name. Errors from fn are returned by Expand as a *FuncError.
+//
+// Panics if name is not a valid identifier or fn is nil.
+//
+// Example:
+//
+//	exp := NewExpander(WithTemplateFunc("join", func(args ...any) (any, error) {
+//	    parts := make([]string, len(args))
+//	    for i, a := range args {
+//	        parts[i] = fmt.Sprint(a)
+//	    }
+//	    return strings.Join(parts, "/"), nil
+//	}))
+//	result, _ := exp.Expand(`${join(host, "api", version)}`, vars)
+func WithTemplateFunc(name string, fn Func) Option {
+	if !isIdentifier(name) {
+		panic(fmt.Sprintf("template: invalid function name %q", name))
+	}
+	if fn == nil {
+		panic("template: function cannot be nil")
+	}
+	return func(e *Expander) {
+		e.funcs[name] = fn
+	}
+}
@@ -0,0 +1,40 @@
+package template
+
+import (
+	"slices"
+	"strings"
+)
+
+// CycleError is returned when WithRecursiveExpansion is set and a variable
+// references itself, directly or through other variables.
+type CycleError struct {
+	// Names is the reference chain, starting and ending with the same name.
+	Names []string
+}
+
+// Error implements the error interface.
+func (e *CycleError) Error() string {
+	return "variable cycle: " + strings.Join(e.Names, " -> ")
+}
+
+// expandVariable expands the references in val, the value of variable
+// name, when recursive expansion is enabled. chain holds the variables
+// already being expanded, outermost first.
+func (e *Expander) expandVariable(name, val string, vars map[string]any, missing *[]string, chain []string) (string, error) {
+	if e.maxDepth == 0 || !strings.Contains(val, "${") {
+		return val, nil
+	}
+	if i := slices.Index(chain, name); i >= 0 {
+		return "", &CycleError{Names: append(slices.Clone(chain[i:]), name)}
+	}
+	if len(chain) >= e.maxDepth {
+		return val, nil
+	}
+
+	chain = append(chain[:len(chain):len(chain)], name)
+	val, err := expandConditionals(val, vars)
+	if err != nil {
+		return "", err
+	}
+	return e.expandBraces(val, vars, missing, chain)
+}
@@ -0,0 +1,81 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExpand_RecursiveExpansion tests that references in variable values are expanded.
+func TestExpand_RecursiveExpansion(t *testing.T) {
+	vars := map[string]any{"a": "${b}", "b": "done", "c": "${a}!"}
+
+	t.Run("single pass by default", func(t *testing.T) {
+		result, err := NewExpander().Expand("${a}", vars)
+		require.NoError(t, err)
+		assert.Equal(t, "${b}", result)
+	})
+
+	t.Run("value references another variable", func(t *testing.T) {
+		result, err := NewExpander(WithRecursiveExpansion(5)).Expand("${a}", vars)
+		require.NoError(t, err)
+		assert.Equal(t, "done", result)
+	})
+
+	t.Run("transitive references and conditionals", func(t *testing.T) {
+		vars := map[string]any{"c": "${a}!", "a": "${b}", "b": "done", "d": "${if:flag}on${else}off${end}", "flag": true}
+		result, err := NewExpander(WithRecursiveExpansion(5)).Expand("${c} ${d}", vars)
+		require.NoError(t, err)
+		assert.Equal(t, "done! on", result)
+	})
+
+	t.Run("depth limit keeps deeper references", func(t *testing.T) {
+		result, err := NewExpander(WithRecursiveExpansion(1)).Expand("${c}", vars)
+		require.NoError(t, err)
+		assert.Equal(t, "${b}!", result)
+	})
+
+	t.Run("missing names in values are reported", func(t *testing.T) {
+		exp := NewExpander(WithRecursiveExpansion(5), WithMissingAction(MissingError))
+		_, err := exp.Expand("${x}", map[string]any{"x": "${y}"})
+
+		var undefinedErr *UndefinedVariableError
+		require.ErrorAs(t, err, &undefinedErr)
+		assert.Equal(t, []string{"y"}, undefinedErr.Names)
+	})
+}
+
+// TestExpand_RecursiveExpansion_Cycle tests that self-referencing variables are detected.
+func TestExpand_RecursiveExpansion_Cycle(t *testing.T) {
+	exp := NewExpander(WithRecursiveExpansion(10))
+
+	t.Run("direct", func(t *testing.T) {
+		input := "x${a}"
+		result, err := exp.Expand(input, map[string]any{"a": "${a}"})
+
+		var cycleErr *CycleError
+		require.ErrorAs(t, err, &cycleErr)
+		assert.Equal(t, []string{"a", "a"}, cycleErr.Names)
+		assert.Equal(t, input, result)
+	})
+
+	t.Run("transitive", func(t *testing.T) {
+		_, err := exp.Expand("${start}", map[string]any{"start": "${a}", "a": "${b}", "b": "${a}"})
+
+		var cycleErr *CycleError
+		require.ErrorAs(t, err, &cycleErr)
+		assert.Equal(t, []string{"a", "b", "a"}, cycleErr.Names)
+		assert.Equal(t, "variable cycle: a -> b -> a", err.Error())
+	})
+
+	t.Run("repeated use is not a cycle", func(t *testing.T) {
+		result, err := exp.Expand("${a}", map[string]any{"a": "${b}-${b}", "b": "x"})
+		require.NoError(t, err)
+		assert.Equal(t, "x-x", result)
+	})
+
+	assert.PanicsWithValue(t, "template: recursive expansion depth must be > 0", func() {
+		WithRecursiveExpansion(0)
+	})
+}
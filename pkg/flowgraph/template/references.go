@@ -0,0 +1,70 @@
+package template
+
+import "strings"
+
+// References returns the names of the variables referenced by ${...}
+// patterns in s, without duplicates, in order of first appearance.
+//
+// Names inside defaults, function call arguments, and ${if:flag}
+// conditions are included; function names are not. Dollar-style $name
+// references are not reported. This lets callers resolve only the
+// variables a template needs, or detect dependencies between templates.
+//
+// Example:
+//
+//	References("${scheme:-https}://${host}${if:port}:${port}${end}")
+//	// result: ["scheme", "host", "port"]
+func References(s string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	collectReferences(s, strings.Contains(s, "${if:"), func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	})
+	return names
+}
+
+// collectReferences calls add for each variable referenced in s. When
+// blocks is set, ${if:...}, ${else}, and ${end} are treated as block tags,
+// as in expandConditionals.
+func collectReferences(s string, blocks bool, add func(string)) {
+	i := 0
+	for {
+		j := strings.Index(s[i:], "${")
+		if j < 0 {
+			return
+		}
+		start := i + j
+
+		if blocks {
+			if m := blockPattern.FindStringSubmatchIndex(s[start:]); m != nil && m[0] == 0 {
+				if m[2] >= 0 {
+					add(s[start+m[2] : start+m[3]])
+				}
+				i = start + m[1]
+				continue
+			}
+		}
+
+		ref, end, ok := parseBraceRef(s, start)
+		if !ok {
+			i = start + 1
+			continue
+		}
+		if ref.call {
+			for _, arg := range ref.args {
+				if !arg.isLit {
+					add(arg.name)
+				}
+			}
+		} else {
+			add(ref.name)
+			if ref.op != "" {
+				collectReferences(ref.defaultVal, blocks, add)
+			}
+		}
+		i = end
+	}
+}
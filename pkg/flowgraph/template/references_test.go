@@ -0,0 +1,63 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReferences tests listing the variables a template references.
+func TestReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "no references",
+			input:    "plain text with $dollar",
+			expected: nil,
+		},
+		{
+			name:     "simple and nested",
+			input:    "${user.name} at ${host}",
+			expected: []string{"user.name", "host"},
+		},
+		{
+			name:     "duplicates removed",
+			input:    "${a}${b}${a}",
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "defaults",
+			input:    "${region:-${fallback:=us-east}}",
+			expected: []string{"region", "fallback"},
+		},
+		{
+			name:     "function arguments",
+			input:    `${default(region, "us-east")}`,
+			expected: []string{"region"},
+		},
+		{
+			name:     "conditionals",
+			input:    "${if:port}:${port}${else}${end}",
+			expected: []string{"port"},
+		},
+		{
+			name:     "else and end without conditionals",
+			input:    "${else}${end}",
+			expected: []string{"else", "end"},
+		},
+		{
+			name:     "malformed",
+			input:    "${ unclosed ${ok",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, References(tt.input))
+		})
+	}
+}
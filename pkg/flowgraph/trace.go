@@ -0,0 +1,127 @@
+package flowgraph
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Trace is the record of a run collected with WithExecutionTrace or
+// RunWithTrace: one entry per node execution, in execution order.
+type Trace []TraceEntry
+
+// TraceEntry records one node execution.
+type TraceEntry struct {
+	// NodeID is the node that executed.
+	NodeID string
+
+	// StartedAt is when the node started.
+	StartedAt time.Time
+
+	// Duration is how long the node took, including retries.
+	Duration time.Duration
+
+	// Attempt is the attempt that succeeded or failed last (1 = first).
+	Attempt int
+
+	// NextNode is where execution continued: the successor, the target a
+	// conditional edge chose, the join node of a fork or fan-out, the
+	// target of an error edge, or END. Empty if the run stopped here.
+	NextNode string
+
+	// Conditional is true if NextNode was chosen by a conditional edge.
+	Conditional bool
+
+	// Err is the node's error, or the routing error if its conditional
+	// edge failed. A non-nil Err with NextNode set was routed by an error
+	// edge.
+	Err error
+}
+
+// NodeIDs returns the IDs of the traced nodes in execution order.
+func (t Trace) NodeIDs() []string {
+	ids := make([]string, len(t))
+	for i, entry := range t {
+		ids[i] = entry.NodeID
+	}
+	return ids
+}
+
+// RunWithTrace runs the graph like Run and also returns the trace of the
+// nodes it executed. The trace is returned even if the run fails, ending
+// with the node that failed.
+//
+// Example:
+//
+//	result, trace, err := compiled.RunWithTrace(ctx, state)
+//	for _, entry := range trace {
+//	    fmt.Printf("%s %s -> %s\n", entry.NodeID, entry.Duration, entry.NextNode)
+//	}
+func (cg *CompiledGraph[S]) RunWithTrace(ctx Context, state S, opts ...RunOption) (S, Trace, error) {
+	var trace Trace
+	opts = append(opts[:len(opts):len(opts)], WithExecutionTrace(&trace))
+	result, err := cg.Run(ctx, state, opts...)
+	return result, trace, err
+}
+
+// attemptCounter receives the attempt number of the node being traced.
+type attemptCounter struct {
+	n atomic.Int32
+}
+
+// withAttemptCounter returns ctx with counter attached, so the executor
+// can report the node's attempts. Contexts not created by NewContext are
+// returned unchanged.
+func withAttemptCounter(ctx Context, counter *attemptCounter) Context {
+	ec, ok := ctx.(*executionContext)
+	if !ok {
+		return ctx
+	}
+	return ec.derive(func(d *executionContext) { d.attempts = counter })
+}
+
+// recordAttempt reports attempt to the context's attempt counter, if any.
+func recordAttempt(ctx Context, attempt int) {
+	if ec, ok := ctx.(*executionContext); ok && ec.attempts != nil {
+		ec.attempts.n.Store(int32(attempt))
+	}
+}
+
+// nodeTrace times one node execution for the run's trace.
+type nodeTrace struct {
+	trace     *Trace
+	nodeID    string
+	startedAt time.Time
+	attempts  *attemptCounter
+}
+
+// startNodeTrace starts tracing nodeID and returns the context to execute
+// it with. Returns ctx and a nil nodeTrace if tracing is disabled.
+func startNodeTrace(cfg *runConfig, ctx Context, nodeID string) (Context, *nodeTrace) {
+	if cfg.trace == nil {
+		return ctx, nil
+	}
+	t := &nodeTrace{
+		trace:     cfg.trace,
+		nodeID:    nodeID,
+		startedAt: time.Now(),
+		attempts:  &attemptCounter{},
+	}
+	t.attempts.n.Store(1)
+	return withAttemptCounter(ctx, t.attempts), t
+}
+
+// end appends the node's entry to the trace. Safe to call on nil.
+func (t *nodeTrace) end(next string, conditional bool, err error) {
+	if t == nil {
+		return
+	}
+	*t.trace = append(*t.trace, TraceEntry{
+		NodeID:      t.nodeID,
+		StartedAt:   t.startedAt,
+		Duration:    time.Since(t.startedAt),
+		Attempt:     int(t.attempts.n.Load()),
+		NextNode:    next,
+		Conditional: conditional,
+		Err:         err,
+	})
+}
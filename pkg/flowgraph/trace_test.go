@@ -0,0 +1,106 @@
+package flowgraph
+
+import (
+	"errors"
+	"testing"
+
+	fgerrors "github.com/randalmurphal/flowgraph/pkg/flowgraph/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunWithTrace tests that nodes, routing decisions, and attempts are traced in order.
+func TestRunWithTrace(t *testing.T) {
+	failures := 1
+	compiled, err := NewGraph[Counter]().
+		AddNode("flaky", func(ctx Context, s Counter) (Counter, error) {
+			if failures > 0 {
+				failures--
+				return s, fgerrors.Transient(errors.New("blip"), "test")
+			}
+			return s, nil
+		}, WithNodeRetry(fgerrors.RetryConfig{MaxAttempts: 3, BackoffFactor: 1})).
+		AddNode("inc", increment).
+		AddEdge("flaky", "inc").
+		AddConditionalEdge("inc", func(ctx Context, s Counter) string {
+			if s.Value < 2 {
+				return "inc"
+			}
+			return END
+		}).
+		SetEntry("flaky").
+		Compile()
+	require.NoError(t, err)
+
+	result, trace, err := compiled.RunWithTrace(testCtx(), Counter{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Value)
+	assert.Equal(t, []string{"flaky", "inc", "inc"}, trace.NodeIDs())
+
+	assert.Equal(t, 2, trace[0].Attempt)
+	assert.Equal(t, "inc", trace[0].NextNode)
+	assert.False(t, trace[0].Conditional)
+
+	assert.Equal(t, 1, trace[1].Attempt)
+	assert.Equal(t, "inc", trace[1].NextNode)
+	assert.True(t, trace[1].Conditional)
+	assert.Equal(t, END, trace[2].NextNode)
+
+	for _, entry := range trace {
+		assert.NoError(t, entry.Err)
+		assert.False(t, entry.StartedAt.IsZero())
+	}
+	assert.False(t, trace[1].StartedAt.Before(trace[0].StartedAt))
+}
+
+// TestRunWithTrace_Failure tests that the failing node ends the trace with its error.
+func TestRunWithTrace_Failure(t *testing.T) {
+	boom := errors.New("boom")
+	compiled, err := NewGraph[State]().
+		AddNode("a", passthrough[State]).
+		AddNode("b", makeFailingNode(boom)).
+		AddEdge("a", "b").
+		AddEdge("b", END).
+		SetEntry("a").
+		Compile()
+	require.NoError(t, err)
+
+	_, trace, err := compiled.RunWithTrace(testCtx(), State{})
+
+	require.ErrorIs(t, err, boom)
+	require.Len(t, trace, 2)
+	assert.ErrorIs(t, trace[1].Err, boom)
+	assert.Empty(t, trace[1].NextNode)
+}
+
+// TestWithExecutionTrace_ForkAndErrorEdge tests tracing of fork nodes and error edges.
+func TestWithExecutionTrace_ForkAndErrorEdge(t *testing.T) {
+	compiled, err := NewGraph[TestState]().
+		AddNode("fork", passthrough[TestState]).
+		AddNode("a", passthrough[TestState]).
+		AddNode("b", passthrough[TestState]).
+		AddNode("join", func(ctx Context, s TestState) (TestState, error) {
+			return s, errors.New("join failed")
+		}).
+		AddNode("recover", passthrough[TestState]).
+		AddEdge("fork", "a").
+		AddEdge("fork", "b").
+		AddEdge("a", "join").
+		AddEdge("b", "join").
+		AddEdge("join", END).
+		AddErrorEdge("join", "recover").
+		AddEdge("recover", END).
+		SetEntry("fork").
+		Compile()
+	require.NoError(t, err)
+
+	var trace Trace
+	_, err = compiled.Run(testCtx(), TestState{Values: map[string]int{}}, WithExecutionTrace(&trace))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fork", "join", "recover"}, trace.NodeIDs())
+	assert.Equal(t, "join", trace[0].NextNode)
+	assert.Error(t, trace[1].Err)
+	assert.Equal(t, "recover", trace[1].NextNode)
+}
@@ -0,0 +1,252 @@
+package flowgraph
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Synthetic node identifiers used when rendering diagrams.
+const (
+	diagramStart    = "__start__"
+	diagramSelector = "__entry_selector__"
+	diagramRoute    = "__route"
+)
+
+// ToDOT renders the graph structure in Graphviz DOT format.
+//
+// Simple edges are drawn as solid arrows. Conditional edges are drawn as
+// dashed arrows, labeled with the router function name, into a diamond
+// decision point (their targets are only known at runtime). Detected
+// fork nodes, their branch entry nodes, and their join node are grouped
+// into a dashed cluster.
+//
+// Output is deterministic: nodes and edges are emitted in sorted order.
+//
+// Example:
+//
+//	os.WriteFile("graph.dot", []byte(compiled.ToDOT()), 0o644)
+//	// dot -Tsvg graph.dot -o graph.svg
+func (cg *CompiledGraph[S]) ToDOT() string {
+	var b strings.Builder
+
+	b.WriteString("digraph flowgraph {\n")
+	b.WriteString("\trankdir=TB;\n")
+	b.WriteString("\tnode [shape=box];\n")
+	fmt.Fprintf(&b, "\t%q [shape=circle, label=\"start\"];\n", diagramStart)
+	fmt.Fprintf(&b, "\t%q [shape=doublecircle, label=\"end\"];\n", END)
+
+	// Fork/join clusters
+	placed := make(map[string]bool)
+	for _, group := range cg.forkGroups() {
+		fmt.Fprintf(&b, "\tsubgraph %q {\n", "cluster_fork_"+group.fork.NodeID)
+		fmt.Fprintf(&b, "\t\tlabel=%q;\n", group.label())
+		b.WriteString("\t\tstyle=dashed;\n")
+		for _, id := range group.members {
+			fmt.Fprintf(&b, "\t\t%q;\n", id)
+			placed[id] = true
+		}
+		b.WriteString("\t}\n")
+	}
+
+	// Remaining nodes
+	for _, id := range cg.sortedNodeIDs() {
+		if !placed[id] {
+			fmt.Fprintf(&b, "\t%q;\n", id)
+		}
+	}
+
+	// Entry
+	if cg.entrySelector != nil {
+		fmt.Fprintf(&b, "\t%q [shape=diamond, label=\"entry selector\"];\n", diagramSelector)
+		fmt.Fprintf(&b, "\t%q -> %q [style=dashed];\n", diagramStart, diagramSelector)
+	} else {
+		fmt.Fprintf(&b, "\t%q -> %q;\n", diagramStart, cg.entryPoint)
+	}
+
+	// Edges
+	for _, from := range cg.sortedEdgeSources() {
+		if cg.isConditional[from] {
+			route := from + diagramRoute
+			fmt.Fprintf(&b, "\t%q [shape=diamond, label=\"route\"];\n", route)
+			fmt.Fprintf(&b, "\t%q -> %q [style=dashed, label=%q];\n", from, route, cg.routerName(from))
+			continue
+		}
+		for _, to := range cg.edges[from] {
+			fmt.Fprintf(&b, "\t%q -> %q;\n", from, to)
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// ToMermaid renders the graph structure as a Mermaid flowchart.
+//
+// Rendering follows the same conventions as ToDOT: conditional edges are
+// dashed and labeled with the router function name, and fork/join groups
+// are wrapped in a subgraph. Node IDs are mapped to generated identifiers
+// so that any node name renders safely; the original names appear as labels.
+//
+// Output is deterministic: nodes and edges are emitted in sorted order.
+func (cg *CompiledGraph[S]) ToMermaid() string {
+	var b strings.Builder
+
+	ids := cg.sortedNodeIDs()
+	mermaidID := make(map[string]string, len(ids)+1)
+	for i, id := range ids {
+		mermaidID[id] = fmt.Sprintf("n%d", i)
+	}
+	mermaidID[END] = "__end"
+
+	b.WriteString("flowchart TD\n")
+	b.WriteString("    __start([\"start\"])\n")
+	b.WriteString("    __end((\"end\"))\n")
+
+	// Fork/join subgraphs
+	placed := make(map[string]bool)
+	for i, group := range cg.forkGroups() {
+		fmt.Fprintf(&b, "    subgraph fork%d [\"%s\"]\n", i, mermaidEscape(group.label()))
+		for _, id := range group.members {
+			fmt.Fprintf(&b, "        %s[\"%s\"]\n", mermaidID[id], mermaidEscape(id))
+			placed[id] = true
+		}
+		b.WriteString("    end\n")
+	}
+
+	// Remaining nodes
+	for _, id := range ids {
+		if !placed[id] {
+			fmt.Fprintf(&b, "    %s[\"%s\"]\n", mermaidID[id], mermaidEscape(id))
+		}
+	}
+
+	// Entry
+	if cg.entrySelector != nil {
+		b.WriteString("    __entry_selector{\"entry selector\"}\n")
+		b.WriteString("    __start -.-> __entry_selector\n")
+	} else if entry, ok := mermaidID[cg.entryPoint]; ok {
+		fmt.Fprintf(&b, "    __start --> %s\n", entry)
+	}
+
+	// Edges
+	for _, from := range cg.sortedEdgeSources() {
+		if cg.isConditional[from] {
+			route := mermaidID[from] + diagramRoute
+			fmt.Fprintf(&b, "    %s{\"route\"}\n", route)
+			fmt.Fprintf(&b, "    %s -.->|\"%s\"| %s\n", mermaidID[from], mermaidEscape(cg.routerName(from)), route)
+			continue
+		}
+		for _, to := range cg.edges[from] {
+			fmt.Fprintf(&b, "    %s --> %s\n", mermaidID[from], mermaidID[to])
+		}
+	}
+
+	return b.String()
+}
+
+// forkGroup is a fork node with the nodes drawn alongside it.
+type forkGroup struct {
+	fork    *ForkNode
+	members []string
+}
+
+// label returns the display label for the group.
+func (g forkGroup) label() string {
+	if g.fork.JoinNodeID == "" || g.fork.JoinNodeID == END {
+		return "fork: " + g.fork.NodeID
+	}
+	return "fork/join: " + g.fork.NodeID + " → " + g.fork.JoinNodeID
+}
+
+// forkGroups returns fork nodes in sorted order with their branch entry and
+// join nodes. Each node appears in at most one group, since diagram
+// clusters cannot overlap.
+func (cg *CompiledGraph[S]) forkGroups() []forkGroup {
+	forkIDs := make([]string, 0, len(cg.forkNodes))
+	for id := range cg.forkNodes {
+		forkIDs = append(forkIDs, id)
+	}
+	sort.Strings(forkIDs)
+
+	claimed := make(map[string]bool)
+	groups := make([]forkGroup, 0, len(forkIDs))
+	for _, id := range forkIDs {
+		fork := cg.forkNodes[id]
+
+		candidates := append([]string{fork.NodeID}, fork.Branches...)
+		if fork.JoinNodeID != "" {
+			candidates = append(candidates, fork.JoinNodeID)
+		}
+
+		var members []string
+		for _, m := range candidates {
+			if m == END || claimed[m] {
+				continue
+			}
+			claimed[m] = true
+			members = append(members, m)
+		}
+		groups = append(groups, forkGroup{fork: fork, members: members})
+	}
+	return groups
+}
+
+// sortedNodeIDs returns all node IDs in sorted order.
+func (cg *CompiledGraph[S]) sortedNodeIDs() []string {
+	ids := cg.NodeIDs()
+	sort.Strings(ids)
+	return ids
+}
+
+// sortedEdgeSources returns all nodes with outgoing edges in sorted order.
+func (cg *CompiledGraph[S]) sortedEdgeSources() []string {
+	seen := make(map[string]bool)
+	var sources []string
+	for from := range cg.edges {
+		seen[from] = true
+		sources = append(sources, from)
+	}
+	for from := range cg.conditionalEdges {
+		if !seen[from] {
+			sources = append(sources, from)
+		}
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// routerName returns a display name for the router on the given node.
+func (cg *CompiledGraph[S]) routerName(id string) string {
+	router, ok := cg.conditionalEdges[id]
+	if !ok {
+		return ""
+	}
+	return funcName(router)
+}
+
+// funcName returns the short name of a function for display.
+func funcName(fn any) string {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return "router"
+	}
+
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return "router"
+	}
+
+	name := f.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// mermaidEscape escapes text for use inside a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
@@ -0,0 +1,93 @@
+package flowgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// routeByDone is a named router so diagram labels are predictable.
+func routeByDone(ctx Context, s State) string {
+	if s.Done {
+		return END
+	}
+	return "work"
+}
+
+// buildDiagramGraph compiles a graph with a conditional edge and a fork/join.
+func buildDiagramGraph(t *testing.T) *CompiledGraph[State] {
+	t.Helper()
+
+	compiled, err := NewGraph[State]().
+		AddNode("check", passthrough[State]).
+		AddNode("work", passthrough[State]).
+		AddNode("dispatch", passthrough[State]).
+		AddNode("workerA", passthrough[State]).
+		AddNode("workerB", passthrough[State]).
+		AddNode("collect", passthrough[State]).
+		AddConditionalEdge("check", routeByDone).
+		AddEdge("work", "dispatch").
+		AddEdge("dispatch", "workerA").
+		AddEdge("dispatch", "workerB").
+		AddEdge("workerA", "collect").
+		AddEdge("workerB", "collect").
+		AddEdge("collect", END).
+		SetEntry("check").
+		Compile()
+	require.NoError(t, err)
+	return compiled
+}
+
+// TestToDOT tests Graphviz rendering of nodes, edges, conditionals, and fork groups.
+func TestToDOT(t *testing.T) {
+	dot := buildDiagramGraph(t).ToDOT()
+
+	assert.Contains(t, dot, "digraph flowgraph {")
+	assert.Contains(t, dot, `"__start__" -> "check";`)
+	assert.Contains(t, dot, `"work" -> "dispatch";`)
+	assert.Contains(t, dot, `"collect" -> "__end__";`)
+
+	// Conditional edge is dashed and labeled with the router
+	assert.Contains(t, dot, `"check" -> "check__route" [style=dashed, label="flowgraph.routeByDone"];`)
+
+	// Fork/join grouped in a cluster
+	assert.Contains(t, dot, `subgraph "cluster_fork_dispatch" {`)
+	assert.Contains(t, dot, `label="fork/join: dispatch → collect";`)
+	assert.Contains(t, dot, "\t\t\"workerA\";\n")
+	assert.Contains(t, dot, "\t\t\"collect\";\n")
+}
+
+// TestToDOT_Deterministic tests that repeated rendering produces identical output.
+func TestToDOT_Deterministic(t *testing.T) {
+	compiled := buildDiagramGraph(t)
+	assert.Equal(t, compiled.ToDOT(), compiled.ToDOT())
+	assert.Equal(t, compiled.ToMermaid(), compiled.ToMermaid())
+}
+
+// TestToMermaid tests Mermaid rendering of nodes, edges, conditionals, and fork groups.
+func TestToMermaid(t *testing.T) {
+	mermaid := buildDiagramGraph(t).ToMermaid()
+
+	// Sorted IDs: check=n0, collect=n1, dispatch=n2, work=n3, workerA=n4, workerB=n5
+	assert.Contains(t, mermaid, "flowchart TD\n")
+	assert.Contains(t, mermaid, "__start --> n0\n")
+	assert.Contains(t, mermaid, "n3 --> n2\n")
+	assert.Contains(t, mermaid, "n1 --> __end\n")
+	assert.Contains(t, mermaid, "n0 -.->|\"flowgraph.routeByDone\"| n0__route\n")
+	assert.Contains(t, mermaid, "subgraph fork0 [\"fork/join: dispatch → collect\"]\n")
+	assert.Contains(t, mermaid, "        n4[\"workerA\"]\n")
+}
+
+// TestToMermaid_EntrySelector tests rendering a graph that selects its entry at runtime.
+func TestToMermaid_EntrySelector(t *testing.T) {
+	compiled, err := NewGraph[Counter]().
+		AddNode("a", increment).
+		AddEdge("a", END).
+		SetEntrySelector(func(Counter) string { return "a" }).
+		Compile()
+	require.NoError(t, err)
+
+	assert.Contains(t, compiled.ToMermaid(), "__start -.-> __entry_selector\n")
+	assert.Contains(t, compiled.ToDOT(), `"__start__" -> "__entry_selector__" [style=dashed];`)
+}